@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,15 +13,42 @@ import (
 	"go.uber.org/zap"
 
 	"pr-service/internal/app"
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/cache"
 	"pr-service/internal/config"
+	"pr-service/internal/cron"
 	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/eventbus"
 	"pr-service/internal/handler"
+	"pr-service/internal/kafka"
+	"pr-service/internal/lifecycle"
 	"pr-service/internal/logger"
-	"pr-service/internal/repository"
+	"pr-service/internal/maintenance"
+	"pr-service/internal/migrate"
+	"pr-service/internal/nats"
+	"pr-service/internal/notify"
 	"pr-service/internal/service/assignment"
+	"pr-service/internal/service/audit"
+	"pr-service/internal/service/directory"
+	"pr-service/internal/service/export"
+	"pr-service/internal/service/githubsync"
+	"pr-service/internal/service/jira"
+	"pr-service/internal/service/outbox"
 	"pr-service/internal/service/pullrequest"
+	"pr-service/internal/service/report"
+	"pr-service/internal/service/retention"
+	"pr-service/internal/service/rollup"
+	"pr-service/internal/service/schedule"
+	"pr-service/internal/service/slack"
 	"pr-service/internal/service/team"
+	"pr-service/internal/service/teamchannel"
+	"pr-service/internal/service/teamtoken"
 	"pr-service/internal/service/user"
+	"pr-service/internal/service/webhook"
+	"pr-service/internal/tracing"
+	"pr-service/internal/worker"
+	"pr-service/migrations"
 )
 
 func main() {
@@ -37,6 +65,9 @@ func main() {
 	}
 
 	// Override config from environment variables for Docker
+	if storage := os.Getenv("STORAGE"); storage != "" {
+		cfg.Storage = storage
+	}
 	if dbHost := os.Getenv("DB_HOST"); dbHost != "" {
 		cfg.Database.Host = dbHost
 	}
@@ -55,59 +86,343 @@ func main() {
 	if dbSSL := os.Getenv("DB_SSLMODE"); dbSSL != "" {
 		cfg.Database.SSLMode = dbSSL
 	}
+	if replicaDSN := os.Getenv("DB_REPLICA_DSN"); replicaDSN != "" {
+		cfg.Database.ReplicaDSN = replicaDSN
+	}
 
-	// Connect to database
 	ctx := context.Background()
-	dbURL := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.DBName,
-		cfg.Database.SSLMode,
-	)
 
-	dbPool, err := pgxpool.New(ctx, dbURL)
+	// Requests, service operations and queries are traced when a collector is configured
+	tracer, err := app.NewTracer(cfg.Tracing)
 	if err != nil {
-		log.Fatal("Failed to connect to database", zap.Error(err))
+		log.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+	traced := tracer != nil
+	if traced {
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := tracer.Shutdown(shutdownCtx); err != nil {
+				log.Error("Failed to export remaining spans", zap.Error(err))
+			}
+		}()
+		tracing.SetDefault(tracer)
 	}
-	defer dbPool.Close()
 
-	// Test connection
-	if err := dbPool.Ping(ctx); err != nil {
-		log.Fatal("Failed to ping database", zap.Error(err))
+	usesDatabase, err := app.UsesDatabase(cfg.Storage)
+	if err != nil {
+		log.Fatal("Invalid storage config", zap.Error(err))
+	}
+	// "pr-service migrate [up|down|status]" manages the schema and exits
+	migrateCommand := len(os.Args) > 1 && os.Args[1] == "migrate"
+	if migrateCommand && !usesDatabase {
+		log.Fatal("Migrations need the postgres storage", zap.String("storage", cfg.Storage))
 	}
-	log.Info("Successfully connected to database")
 
-	// Initialize context manager for transactions
-	contextManager := db.NewContextManager(dbPool, log)
+	var (
+		dbPool         *pgxpool.Pool
+		replicaPool    *pgxpool.Pool
+		contextManager *db.ContextManager
+		store          *app.Storage
+	)
+	if usesDatabase {
+		dbCfg := cfg.Database
+		if migrateCommand {
+			// the subcommand alone decides which migrations run
+			dbCfg.AutoMigrate = false
+		}
+		dbPool, replicaPool, contextManager, err = app.OpenDatabase(dbCfg, traced, log)
+		if err != nil {
+			log.Fatal("Failed to open database", zap.Error(err))
+		}
+		defer dbPool.Close()
+		if replicaPool != nil {
+			defer replicaPool.Close()
+		}
+
+		if migrateCommand {
+			if err := runMigrate(ctx, dbPool, os.Args[2:], log); err != nil {
+				log.Fatal("Migration failed", zap.Error(err))
+			}
+			return
+		}
+		store = app.NewPostgresStorage(contextManager)
+	} else {
+		log.Warn("Using in-memory storage, data is lost when the service stops")
+		store = app.NewMemoryStorage()
+	}
+	if err := app.CheckOrgClaim(ctx, cfg.Auth.OIDC, dbPool); err != nil {
+		log.Fatal("Invalid organization config", zap.Error(err))
+	}
 
 	// Initialize repositories
-	teamRepo := repository.NewTeamRepository(contextManager)
-	userRepo := repository.NewUserRepository(contextManager)
-	prRepo := repository.NewPRRepository(contextManager)
+	teamRepo := store.Teams
+	userRepo := store.Users
+	prRepo := store.PRs
+	scheduledChangeRepo := store.ScheduledChanges
+	auditRepo := store.Audit
+	rollupRepo := store.Rollups
+	webhookRepo := store.Webhooks
+	outboxRepo := store.Outbox
+	exportRepo := store.Export
+	teamTokenRepo := store.TeamTokens
+	auditLogRepo := store.AuditLog
+	transactor := store.Transactor
 
 	// Initialize services
-	assignmentStrategy := assignment.NewStrategy()
-	teamService := team.NewService(teamRepo, userRepo, contextManager)
-	userService := user.NewService(userRepo, prRepo, contextManager, assignmentStrategy)
-	prService := pullrequest.NewService(prRepo, userRepo, contextManager, assignmentStrategy)
+	assignmentStrategy := assignment.NewStrategy(assignment.WithDormantAfter(cfg.Assignment.DormantAfter))
+	statsCache := cache.New("stats", cfg.Stats.CacheTTL)
+	userCache := cache.New("users", cfg.Assignment.UserCacheTTL)
+	webhookService := webhook.NewService(webhookRepo, notify.NewSignedSender(cfg.Webhooks.Timeout),
+		webhook.WithRetryPolicy(cfg.Webhooks.MaxAttempts, cfg.Webhooks.RetryBase, cfg.Webhooks.RetryMax))
+	teamOpts := []team.Option{team.WithStatsCache(statsCache), team.WithUserCache(userCache), team.WithEventPublisher(webhookService)}
+	userOpts := []user.Option{user.WithStatsCache(statsCache), user.WithUserCache(userCache), user.WithEventPublisher(webhookService), user.WithReviewSLA(cfg.Report.ReviewSLA)}
+	prOpts := []pullrequest.Option{
+		pullrequest.WithSubTeamReviewers(cfg.Assignment.IncludeSubTeams),
+		pullrequest.WithReviewCapacity(cfg.Assignment.ReviewCapacity),
+		pullrequest.WithStatsCache(statsCache),
+		pullrequest.WithUserCache(userCache),
+		pullrequest.WithEventPublisher(webhookService),
+	}
+	// Slack direct messages are enabled by configuring a bot token; a digest
+	// schedule replaces the per-event messages with a periodic summary
+	var slackService *slack.Service
+	if cfg.Slack.BotToken != "" {
+		slackService = slack.NewService(notify.NewSlack(cfg.Slack.BotToken, cfg.Slack.APIURL, cfg.Slack.Timeout),
+			prRepo, cfg.Slack.Users, cfg.Slack.StaleAfter, cfg.Report.ReviewSLA)
+		if cfg.Slack.DigestSchedule == "" {
+			teamOpts = append(teamOpts, team.WithEventListener(slackService))
+			userOpts = append(userOpts, user.WithEventListener(slackService))
+			prOpts = append(prOpts, pullrequest.WithEventListener(slackService))
+		}
+	}
+	// Jira ticket keys are validated and commented on when a Jira instance is configured
+	var jiraService *jira.Service
+	if jc := cfg.Integrations.Jira; jc.URL != "" {
+		jiraClient := notify.NewJira(jc.URL, jc.Email, jc.APIToken, jc.Timeout)
+		jiraService = jira.NewService(jiraClient, prRepo)
+		prOpts = append(prOpts, pullrequest.WithTicketValidator(jiraClient), pullrequest.WithEventListener(jiraService))
+		teamOpts = append(teamOpts, team.WithEventListener(jiraService))
+		userOpts = append(userOpts, user.WithEventListener(jiraService))
+	}
+	// PR activity is posted to the chat channel each team configures in its settings
+	channelService := teamchannel.NewService(map[domain.NotificationChannelType]teamchannel.Channel{
+		domain.NotificationChannelSlack:      notify.NewSlackChannel(cfg.Webhooks.Timeout),
+		domain.NotificationChannelMSTeams:    notify.NewMSTeamsChannel(cfg.Webhooks.Timeout),
+		domain.NotificationChannelMattermost: notify.NewMattermostChannel(cfg.Webhooks.Timeout),
+	}, teamRepo, prRepo)
+	prOpts = append(prOpts, pullrequest.WithEventListener(channelService))
+	teamOpts = append(teamOpts, team.WithEventListener(channelService))
+	userOpts = append(userOpts, user.WithEventListener(channelService))
+	// Committed events are broadcast in-process to the event stream
+	eventBus := eventbus.New()
+	prOpts = append(prOpts, pullrequest.WithEventListener(eventBus))
+	teamOpts = append(teamOpts, team.WithEventListener(eventBus))
+	userOpts = append(userOpts, user.WithEventListener(eventBus))
+	// Reviewer assignments are written back to GitHub when an API token is configured
+	var githubSync *githubsync.Service
+	if gh := cfg.Integrations.GitHub; gh.APIToken != "" {
+		githubSync = githubsync.NewService(notify.NewGitHub(gh.APIToken, gh.APIURL, gh.Timeout), gh.Users, gh.WriteBackOwners)
+		prOpts = append(prOpts, pullrequest.WithEventListener(githubSync))
+		teamOpts = append(teamOpts, team.WithEventListener(githubSync))
+		userOpts = append(userOpts, user.WithEventListener(githubSync))
+	}
+	// Domain events are recorded in the outbox, which serves the event log for
+	// replay, and relayed to the configured transport
+	var outboxService *outbox.Service
+	switch cfg.Events.Transport {
+	case "":
+		outboxService = outbox.NewService(outboxRepo, transactor, nil)
+	case "kafka":
+		producer, err := kafka.NewProducer(cfg.Events.Kafka.Brokers, cfg.Events.Kafka.Topic,
+			cfg.Events.Kafka.ClientID, cfg.Events.Kafka.Timeout)
+		if err != nil {
+			log.Fatal("Failed to create Kafka producer", zap.Error(err))
+		}
+		outboxService = outbox.NewService(outboxRepo, transactor, producer)
+	case "nats":
+		outboxService = outbox.NewService(outboxRepo, transactor, nats.NewPublisher(cfg.Events.NATS.URL,
+			cfg.Events.NATS.Subject, cfg.Events.NATS.Name, cfg.Events.NATS.JetStream, cfg.Events.NATS.Timeout))
+	default:
+		log.Fatal("Unknown event transport", zap.String("transport", cfg.Events.Transport))
+	}
+	teamOpts = append(teamOpts, team.WithEventPublisher(outboxService))
+	userOpts = append(userOpts, user.WithEventPublisher(outboxService))
+	prOpts = append(prOpts, pullrequest.WithEventPublisher(outboxService))
+	teamService := team.NewService(teamRepo, userRepo, prRepo, auditRepo, transactor, assignmentStrategy, teamOpts...)
+	userService := user.NewService(userRepo, prRepo, auditRepo, transactor, assignmentStrategy, userOpts...)
+	prService := pullrequest.NewService(prRepo, userRepo, transactor, assignmentStrategy, prOpts...)
+	scheduleService := schedule.NewService(scheduledChangeRepo, userService)
+	rollupService := rollup.NewService(rollupRepo, transactor, cfg.Stats.BackfillDays)
+	exportService := export.NewService(exportRepo, transactor, export.WithStatsCache(statsCache), export.WithUserCache(userCache))
+	teamTokenService := teamtoken.NewService(teamTokenRepo, teamRepo, userRepo)
+	auditService := audit.NewService(auditLogRepo)
 
 	// Initialize handlers
 	teamHandler := handler.NewTeamHandler(teamService, log)
-	userHandler := handler.NewUserHandler(userService, log)
+	userHandler := handler.NewUserHandler(userService, scheduleService, log)
 	prHandler := handler.NewPRHandler(prService, log)
-	healthHandler := handler.NewHealthHandler()
+	var healthOpts []handler.HealthOption
+	var dbPoolHandler *handler.DBPoolHandler
+	if contextManager != nil {
+		healthOpts = append(healthOpts, handler.WithDatabase(contextManager, log))
+		dbPoolHandler = handler.NewDBPoolHandler(contextManager)
+	}
+	healthHandler := handler.NewHealthHandler(healthOpts...)
 	docsHandler := handler.NewDocsHandler("openapi.yml")
-	statsHandler := handler.NewStatsHandler(prService, log)
+	statsHandler := handler.NewStatsHandler(prService, rollupService, log)
+	webhookHandler := handler.NewOutboundWebhookHandler(webhookService, log)
+	eventsHandler := handler.NewEventsHandler(outboxService, eventBus, log)
+	reviewQueueHandler := handler.NewReviewQueueHandler(userService, eventBus, log)
+	graphqlHandler := handler.NewGraphQLHandler(teamService, userService, prService, log)
+	exportHandler := handler.NewExportHandler(exportService, log)
+	teamTokenHandler := handler.NewTeamTokenHandler(teamTokenService, log)
+	auditHandler := handler.NewAuditHandler(auditService, log)
+	mc := cfg.Server.Maintenance
+	maintenanceSwitch, err := maintenance.NewSwitch(maintenance.Mode(mc.Mode), mc.Message, mc.RetryAfter)
+	if err != nil {
+		log.Fatal("Invalid maintenance config", zap.Error(err))
+	}
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceSwitch, log)
+	requestLog := middleware.NewRequestLogPolicy(cfg.Logger.Requests.SlowThreshold, cfg.Logger.Requests.SampleRatio)
+	requestLog.SetDebugRoutes(cfg.Logger.Requests.DebugRoutes)
+	requestLogHandler := handler.NewRequestLogHandler(requestLog, log)
+	var githubHandler *handler.GitHubHandler
+	if cfg.Integrations.GitHub.WebhookSecret != "" {
+		githubHandler = handler.NewGitHubHandler(prService,
+			cfg.Integrations.GitHub.WebhookSecret, cfg.Integrations.GitHub.Users, log)
+	}
+	var gitlabHandler *handler.GitLabHandler
+	if cfg.Integrations.GitLab.WebhookToken != "" {
+		gitlabHandler = handler.NewGitLabHandler(prService,
+			cfg.Integrations.GitLab.WebhookToken, cfg.Integrations.GitLab.Users, log)
+	}
+	var bitbucketHandler *handler.BitbucketHandler
+	if len(cfg.Integrations.Bitbucket.Workspaces) > 0 {
+		workspaces := make(map[string]handler.BitbucketWorkspace, len(cfg.Integrations.Bitbucket.Workspaces))
+		for slug, ws := range cfg.Integrations.Bitbucket.Workspaces {
+			workspaces[slug] = handler.BitbucketWorkspace{Secret: ws.WebhookSecret, Users: ws.Users}
+		}
+		bitbucketHandler = handler.NewBitbucketHandler(prService, workspaces, log)
+	}
+	genericHandler, err := app.NewGenericWebhookHandler(cfg.Integrations.Generic, prService, log)
+	if err != nil {
+		log.Fatal("Invalid generic webhook config", zap.Error(err))
+	}
 
 	// Initialize and start HTTP server
-	server := app.NewServer(cfg, log, teamHandler, userHandler, prHandler, healthHandler, docsHandler, statsHandler)
+	server, err := app.NewServer(cfg, log, app.ServerDeps{
+		Handlers: app.Handlers{
+			Team:        teamHandler,
+			User:        userHandler,
+			PR:          prHandler,
+			Health:      healthHandler,
+			Docs:        docsHandler,
+			Stats:       statsHandler,
+			GitHub:      githubHandler,
+			GitLab:      gitlabHandler,
+			Bitbucket:   bitbucketHandler,
+			Generic:     genericHandler,
+			Webhooks:    webhookHandler,
+			Events:      eventsHandler,
+			GraphQL:     graphqlHandler,
+			Export:      exportHandler,
+			ReviewQueue: reviewQueueHandler,
+			TeamTokens:  teamTokenHandler,
+			Audit:       auditHandler,
+			Maintenance: maintenanceHandler,
+			RequestLog:  requestLogHandler,
+			DBPool:      dbPoolHandler,
+		},
+		TeamTokens:  teamTokenService,
+		Auditor:     auditService,
+		Maintenance: maintenanceSwitch,
+		RequestLog:  requestLog,
+	})
+	if err != nil {
+		log.Fatal("Invalid server configuration", zap.Error(err))
+	}
+
+	// Start scheduled changes, rollup, retention, partition, delivery, relay, report, notification, digest, team channel, escalation and directory sync workers
+	workerCtx, stopWorker := context.WithCancel(ctx)
+	defer stopWorker()
+	var jobs lifecycle.Tracker
+	scheduledWorker := worker.NewScheduledChangesWorker(scheduleService, store.Orgs, cfg.Scheduler.PollInterval, cfg.Scheduler.BatchSize, log)
+	jobs.Go(func() { scheduledWorker.Run(workerCtx) })
+	rollupWorker := worker.NewDailyRollupWorker(rollupService, store.Orgs, cfg.Stats.RollupInterval, log)
+	jobs.Go(func() { rollupWorker.Run(workerCtx) })
+	if rc := cfg.Retention; rc.MergedPRAge > 0 {
+		retentionOpts := []retention.Option{retention.WithStatsCache(statsCache)}
+		if rc.Delete {
+			retentionOpts = append(retentionOpts, retention.WithDelete())
+		}
+		retentionService := retention.NewService(prRepo, transactor, rc.MergedPRAge, rc.BatchSize, retentionOpts...)
+		retentionWorker := worker.NewRetentionWorker(retentionService, store.Orgs, rc.Interval, log)
+		jobs.Go(func() { retentionWorker.Run(workerCtx) })
+	}
+	if store.Partitions != nil {
+		partitionWorker := worker.NewPartitionWorker(store.Partitions, log)
+		jobs.Go(func() { partitionWorker.Run(workerCtx) })
+	}
+	webhookWorker := worker.NewWebhookDeliveriesWorker(webhookService, cfg.Webhooks.PollInterval, cfg.Webhooks.BatchSize, log)
+	jobs.Go(func() { webhookWorker.Run(workerCtx) })
+	if slackService != nil {
+		slackWorker := worker.NewSlackNotificationsWorker(slackService, store.Orgs, cfg.Slack.StaleCheckInterval, log)
+		jobs.Go(func() { slackWorker.Run(workerCtx) })
+	}
+	if slackService != nil && cfg.Slack.DigestSchedule != "" {
+		digestSchedule, err := cron.Parse(cfg.Slack.DigestSchedule)
+		if err != nil {
+			log.Fatal("Invalid Slack digest schedule", zap.Error(err))
+		}
+		digestWorker := worker.NewReviewDigestWorker(slackService, store.Orgs, digestSchedule, log)
+		jobs.Go(func() { digestWorker.Run(workerCtx) })
+	}
+	if githubSync != nil {
+		githubWorker := worker.NewGitHubWriteBackWorker(githubSync, log)
+		jobs.Go(func() { githubWorker.Run(workerCtx) })
+	}
+	if jiraService != nil {
+		jiraWorker := worker.NewJiraCommentsWorker(jiraService, log)
+		jobs.Go(func() { jiraWorker.Run(workerCtx) })
+	}
+	channelWorker := worker.NewTeamChannelNotificationsWorker(channelService, log)
+	jobs.Go(func() { channelWorker.Run(workerCtx) })
+	if cfg.Events.Transport != "" {
+		relayWorker := worker.NewOutboxRelayWorker(outboxService, cfg.Events.PollInterval, cfg.Events.BatchSize, log)
+		jobs.Go(func() { relayWorker.Run(workerCtx) })
+	}
+	if lc := cfg.Directory.LDAP; lc.URL != "" {
+		directoryService := directory.NewService(app.NewLDAPDirectory(lc), teamService, userService)
+		directoryWorker := worker.NewDirectorySyncWorker(directoryService, cfg.Directory.SyncInterval, log)
+		jobs.Go(func() { directoryWorker.Run(workerCtx) })
+	}
+	escalationService, err := app.NewEscalationService(cfg, prRepo)
+	if err != nil {
+		log.Fatal("Invalid escalation config", zap.Error(err))
+	}
+	if escalationService != nil {
+		escalationWorker := worker.NewReviewEscalationsWorker(escalationService, store.Orgs, cfg.Escalation.CheckInterval, log)
+		jobs.Go(func() { escalationWorker.Run(workerCtx) })
+	}
+	if cfg.Report.WebhookURL != "" {
+		spec := cfg.Report.Schedule
+		if spec == "" {
+			spec = report.DefaultSchedule
+		}
+		reportSchedule, err := cron.Parse(spec)
+		if err != nil {
+			log.Fatal("Invalid report schedule", zap.Error(err))
+		}
+		reportService := report.NewService(prService, notify.NewWebhook(cfg.Report.WebhookURL, cfg.Report.Timeout), cfg.Report.ReviewSLA)
+		reportWorker := worker.NewWeeklyReportWorker(reportService, reportSchedule, log)
+		jobs.Go(func() { reportWorker.Run(workerCtx) })
+	}
 
 	// Start server in goroutine
 	go func() {
 		log.Info("Starting HTTP server", zap.Int("port", cfg.Server.Port))
-		if err := server.Start(); err != nil {
+		if err := server.Start(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
@@ -118,14 +433,79 @@ func main() {
 	<-quit
 
 	log.Info("Shutting down server...")
+	server.Drain()
+	stopWorker()
+	// Shutdown waits for open connections, so end the event streams first
+	eventBus.Close()
 
 	// Graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownTimeout := cfg.Server.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 10 * time.Second
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Error("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Handlers and jobs may still hold transactions, so the pool is closed last
+	server.AwaitIdle(shutdownCtx, &jobs, contextManager)
+	if dbPool != nil {
+		dbPool.Close()
+	}
+	if replicaPool != nil {
+		replicaPool.Close()
+	}
+
 	log.Info("Server stopped")
 }
+
+// runMigrate runs the migrate subcommand: up applies pending migrations (the
+// default), down rolls back the latest one and status lists them all
+func runMigrate(ctx context.Context, pool *pgxpool.Pool, args []string, log *zap.Logger) error {
+	migrator, err := migrate.New(pool, migrations.FS, log)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info("Database schema is up to date", zap.Int("applied", applied))
+	case "down":
+		migration, ok, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			log.Info("No migration to roll back")
+		} else {
+			log.Info("Rolled back migration", zap.String("migration", migration))
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if !status.AppliedAt.IsZero() {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-25s %s\n", applied, status.Name)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, want up, down or status", command)
+	}
+	return nil
+}
@@ -5,7 +5,7 @@ echo "Running database migrations..."
 MAX_RETRIES=30
 RETRY_COUNT=0
 
-until goose -dir ./migrations postgres "postgresql://${DB_USER}:${DB_PASSWORD}@${DB_HOST}:${DB_PORT}/${DB_NAME}?sslmode=${DB_SSLMODE}" up; do
+until /app/pr-service migrate up; do
   RETRY_COUNT=$((RETRY_COUNT + 1))
   if [ $RETRY_COUNT -ge $MAX_RETRIES ]; then
     echo "Failed to run migrations after $MAX_RETRIES attempts"
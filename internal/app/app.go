@@ -4,38 +4,104 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"pr-service/internal/app/middleware"
+	"pr-service/internal/auth"
+	"pr-service/internal/breaker"
+	"pr-service/internal/cache"
 	"pr-service/internal/config"
+	"pr-service/internal/cron"
 	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/eventbus"
 	"pr-service/internal/handler"
+	"pr-service/internal/kafka"
+	"pr-service/internal/ldap"
+	"pr-service/internal/lifecycle"
 	"pr-service/internal/logger"
+	"pr-service/internal/maintenance"
+	"pr-service/internal/metrics"
+	"pr-service/internal/migrate"
+	"pr-service/internal/nats"
+	"pr-service/internal/notify"
+	"pr-service/internal/ratelimit"
 	"pr-service/internal/repository"
 	"pr-service/internal/service/assignment"
+	"pr-service/internal/service/audit"
+	"pr-service/internal/service/directory"
+	"pr-service/internal/service/escalation"
+	"pr-service/internal/service/export"
+	"pr-service/internal/service/githubsync"
+	"pr-service/internal/service/jira"
+	"pr-service/internal/service/outbox"
 	"pr-service/internal/service/pullrequest"
+	"pr-service/internal/service/report"
+	"pr-service/internal/service/retention"
+	"pr-service/internal/service/rollup"
+	"pr-service/internal/service/schedule"
+	"pr-service/internal/service/slack"
 	"pr-service/internal/service/team"
+	"pr-service/internal/service/teamchannel"
+	"pr-service/internal/service/teamtoken"
 	"pr-service/internal/service/user"
+	"pr-service/internal/service/webhook"
+	"pr-service/internal/tracing"
+	"pr-service/internal/worker"
+	"pr-service/migrations"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/zap"
 )
 
+// defaultShutdownTimeout bounds shutdown when server.shutdown_timeout is unset
+const defaultShutdownTimeout = 10 * time.Second
+
 // App is the main application structure
 type App struct {
 	cfg    *config.Config
 	logger *zap.Logger
 	pool   *pgxpool.Pool
+	rpool  *pgxpool.Pool
 	server *http.Server
+	admin  *http.Server
+	acme   *http.Server
+	worker *worker.ScheduledChangesWorker
+	rollup *worker.DailyRollupWorker
+	retain *worker.RetentionWorker
+	parts  *worker.PartitionWorker
+	report *worker.WeeklyReportWorker
+	hooks  *worker.WebhookDeliveriesWorker
+	slack  *worker.SlackNotificationsWorker
+	digest *worker.ReviewDigestWorker
+	github *worker.GitHubWriteBackWorker
+	jira   *worker.JiraCommentsWorker
+	chans  *worker.TeamChannelNotificationsWorker
+	dsync  *worker.DirectorySyncWorker
+	escal  *worker.ReviewEscalationsWorker
+	relay  *worker.OutboxRelayWorker
+	tracer *sdktrace.TracerProvider
+	health *handler.HealthHandler
+	// requests, jobs and txs are waited for on shutdown before the pool closes
+	requests *lifecycle.Tracker
+	jobs     lifecycle.Tracker
+	txs      *db.ContextManager
 }
 
 // Server wraps http.Server for the application
 type Server struct {
-	httpServer *http.Server
-	logger     *zap.Logger
+	httpServer  *http.Server
+	adminServer *http.Server
+	acmeServer  *http.Server
+	requests    *lifecycle.Tracker
+	health      *handler.HealthHandler
+	drainDelay  time.Duration
+	logger      *zap.Logger
 }
 
 // NewApp creates and configures the application
@@ -43,123 +109,529 @@ func NewApp(cfg *config.Config) (*App, error) {
 	// Initialize logger
 	log := logger.NewLogger("pr-service", cfg.Logger.Level, cfg.Logger.Encoding, cfg.Logger.Development)
 
-	// Build database DSN
-	dbURL := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.DBName,
-		cfg.Database.SSLMode,
-	)
-
-	// Create DB connection pool
-	poolCfg, err := pgxpool.ParseConfig(dbURL)
+	// Requests, service operations and queries are traced when a collector is configured
+	tracer, err := NewTracer(cfg.Tracing)
 	if err != nil {
-		log.Error("Failed to parse DB config", zap.Error(err))
+		log.Error("Failed to set up tracing", zap.Error(err))
 		return nil, err
 	}
+	if tracer != nil {
+		tracing.SetDefault(tracer)
+	}
 
-	poolCfg.MaxConns = int32(cfg.Database.MaxOpenConns)
-	poolCfg.MinConns = int32(cfg.Database.MaxIdleConns)
-	poolCfg.MaxConnLifetime = cfg.Database.ConnMaxLifetime
-
-	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
+	usesDatabase, err := UsesDatabase(cfg.Storage)
 	if err != nil {
-		log.Error("Failed to connect to database", zap.Error(err))
+		log.Error("Invalid storage config", zap.Error(err))
 		return nil, err
 	}
-
-	if err := pool.Ping(context.Background()); err != nil {
-		log.Error("Failed to ping database", zap.Error(err))
+	var (
+		pool       *pgxpool.Pool
+		replica    *pgxpool.Pool
+		ctxManager *db.ContextManager
+		store      *Storage
+	)
+	if usesDatabase {
+		pool, replica, ctxManager, err = OpenDatabase(cfg.Database, tracer != nil, log)
+		if err != nil {
+			return nil, err
+		}
+		store = NewPostgresStorage(ctxManager)
+	} else {
+		log.Warn("Using in-memory storage, data is lost when the service stops")
+		store = NewMemoryStorage()
+	}
+	// A failure past this point closes the pools opened above
+	ok := false
+	defer func() {
+		if !ok {
+			closePool(pool, replica)
+		}
+	}()
+	if err := CheckOrgClaim(context.Background(), cfg.Auth.OIDC, pool); err != nil {
+		log.Error("Invalid organization config", zap.Error(err))
 		return nil, err
 	}
 
-	log.Info("Successfully connected to database")
-
-	// Initialize context manager (transactor)
-	ctxManager := db.NewContextManager(pool, log)
-
 	// Initialize repositories
-	teamRepo := repository.NewTeamRepository(ctxManager)
-	userRepo := repository.NewUserRepository(ctxManager)
-	prRepo := repository.NewPRRepository(ctxManager)
+	teamRepo := store.Teams
+	userRepo := store.Users
+	prRepo := store.PRs
+	scheduledChangeRepo := store.ScheduledChanges
+	auditRepo := store.Audit
+	rollupRepo := store.Rollups
+	webhookRepo := store.Webhooks
+	outboxRepo := store.Outbox
+	exportRepo := store.Export
+	teamTokenRepo := store.TeamTokens
+	auditLogRepo := store.AuditLog
+	transactor := store.Transactor
 
 	// Initialize assignment strategy
-	assignStrategy := assignment.NewStrategy()
-
-	// Initialize services
-	teamService := team.NewService(teamRepo, userRepo, ctxManager)
-	userService := user.NewService(userRepo, prRepo, ctxManager, assignStrategy)
-	prService := pullrequest.NewService(prRepo, userRepo, ctxManager, assignStrategy)
+	assignStrategy := assignment.NewStrategy(assignment.WithDormantAfter(cfg.Assignment.DormantAfter))
+
+	// Initialize services; writes invalidate the shared stats cache and publish
+	// events to outbound webhooks
+	statsCache := cache.New("stats", cfg.Stats.CacheTTL)
+	userCache := cache.New("users", cfg.Assignment.UserCacheTTL)
+	webhookService := webhook.NewService(webhookRepo, notify.NewSignedSender(cfg.Webhooks.Timeout),
+		webhook.WithRetryPolicy(cfg.Webhooks.MaxAttempts, cfg.Webhooks.RetryBase, cfg.Webhooks.RetryMax))
+	teamOpts := []team.Option{team.WithStatsCache(statsCache), team.WithUserCache(userCache), team.WithEventPublisher(webhookService)}
+	userOpts := []user.Option{user.WithStatsCache(statsCache), user.WithUserCache(userCache), user.WithEventPublisher(webhookService), user.WithReviewSLA(cfg.Report.ReviewSLA)}
+	prOpts := []pullrequest.Option{
+		pullrequest.WithSubTeamReviewers(cfg.Assignment.IncludeSubTeams),
+		pullrequest.WithReviewCapacity(cfg.Assignment.ReviewCapacity),
+		pullrequest.WithStatsCache(statsCache),
+		pullrequest.WithUserCache(userCache),
+		pullrequest.WithEventPublisher(webhookService),
+	}
+	// Slack direct messages are enabled by configuring a bot token; a digest
+	// schedule replaces the per-event messages with a periodic summary
+	var slackService *slack.Service
+	if cfg.Slack.BotToken != "" {
+		slackService = slack.NewService(notify.NewSlack(cfg.Slack.BotToken, cfg.Slack.APIURL, cfg.Slack.Timeout),
+			prRepo, cfg.Slack.Users, cfg.Slack.StaleAfter, cfg.Report.ReviewSLA)
+		if cfg.Slack.DigestSchedule == "" {
+			teamOpts = append(teamOpts, team.WithEventListener(slackService))
+			userOpts = append(userOpts, user.WithEventListener(slackService))
+			prOpts = append(prOpts, pullrequest.WithEventListener(slackService))
+		}
+	}
+	// Jira ticket keys are validated and commented on when a Jira instance is configured
+	var jiraService *jira.Service
+	if jc := cfg.Integrations.Jira; jc.URL != "" {
+		jiraClient := notify.NewJira(jc.URL, jc.Email, jc.APIToken, jc.Timeout)
+		jiraService = jira.NewService(jiraClient, prRepo)
+		prOpts = append(prOpts, pullrequest.WithTicketValidator(jiraClient), pullrequest.WithEventListener(jiraService))
+		teamOpts = append(teamOpts, team.WithEventListener(jiraService))
+		userOpts = append(userOpts, user.WithEventListener(jiraService))
+	}
+	// PR activity is posted to the chat channel each team configures in its settings
+	channelService := teamchannel.NewService(map[domain.NotificationChannelType]teamchannel.Channel{
+		domain.NotificationChannelSlack:      notify.NewSlackChannel(cfg.Webhooks.Timeout),
+		domain.NotificationChannelMSTeams:    notify.NewMSTeamsChannel(cfg.Webhooks.Timeout),
+		domain.NotificationChannelMattermost: notify.NewMattermostChannel(cfg.Webhooks.Timeout),
+	}, teamRepo, prRepo)
+	prOpts = append(prOpts, pullrequest.WithEventListener(channelService))
+	teamOpts = append(teamOpts, team.WithEventListener(channelService))
+	userOpts = append(userOpts, user.WithEventListener(channelService))
+	// Committed events are broadcast in-process to the event stream
+	eventBus := eventbus.New()
+	prOpts = append(prOpts, pullrequest.WithEventListener(eventBus))
+	teamOpts = append(teamOpts, team.WithEventListener(eventBus))
+	userOpts = append(userOpts, user.WithEventListener(eventBus))
+	// Reviewer assignments are written back to GitHub when an API token is configured
+	var githubSync *githubsync.Service
+	if gh := cfg.Integrations.GitHub; gh.APIToken != "" {
+		githubSync = githubsync.NewService(notify.NewGitHub(gh.APIToken, gh.APIURL, gh.Timeout), gh.Users, gh.WriteBackOwners)
+		prOpts = append(prOpts, pullrequest.WithEventListener(githubSync))
+		teamOpts = append(teamOpts, team.WithEventListener(githubSync))
+		userOpts = append(userOpts, user.WithEventListener(githubSync))
+	}
+	// Domain events are recorded in the outbox, which serves the event log for
+	// replay, and relayed to the configured transport
+	var outboxService *outbox.Service
+	switch cfg.Events.Transport {
+	case "":
+		outboxService = outbox.NewService(outboxRepo, transactor, nil)
+	case "kafka":
+		producer, err := kafka.NewProducer(cfg.Events.Kafka.Brokers, cfg.Events.Kafka.Topic,
+			cfg.Events.Kafka.ClientID, cfg.Events.Kafka.Timeout)
+		if err != nil {
+			log.Error("Invalid Kafka config", zap.Error(err))
+			return nil, err
+		}
+		outboxService = outbox.NewService(outboxRepo, transactor, producer)
+	case "nats":
+		outboxService = outbox.NewService(outboxRepo, transactor, nats.NewPublisher(cfg.Events.NATS.URL,
+			cfg.Events.NATS.Subject, cfg.Events.NATS.Name, cfg.Events.NATS.JetStream, cfg.Events.NATS.Timeout))
+	default:
+		err := fmt.Errorf("unknown event transport %q", cfg.Events.Transport)
+		log.Error("Invalid events config", zap.Error(err))
+		return nil, err
+	}
+	teamOpts = append(teamOpts, team.WithEventPublisher(outboxService))
+	userOpts = append(userOpts, user.WithEventPublisher(outboxService))
+	prOpts = append(prOpts, pullrequest.WithEventPublisher(outboxService))
+	teamService := team.NewService(teamRepo, userRepo, prRepo, auditRepo, transactor, assignStrategy, teamOpts...)
+	userService := user.NewService(userRepo, prRepo, auditRepo, transactor, assignStrategy, userOpts...)
+	prService := pullrequest.NewService(prRepo, userRepo, transactor, assignStrategy, prOpts...)
+	scheduleService := schedule.NewService(scheduledChangeRepo, userService)
+	rollupService := rollup.NewService(rollupRepo, transactor, cfg.Stats.BackfillDays)
+	exportService := export.NewService(exportRepo, transactor, export.WithStatsCache(statsCache), export.WithUserCache(userCache))
+	teamTokenService := teamtoken.NewService(teamTokenRepo, teamRepo, userRepo)
+	auditService := audit.NewService(auditLogRepo)
 
 	// Initialize handlers
 	teamHandler := handler.NewTeamHandler(teamService, log)
-	userHandler := handler.NewUserHandler(userService, log)
+	userHandler := handler.NewUserHandler(userService, scheduleService, log)
 	prHandler := handler.NewPRHandler(prService, log)
-	healthHandler := handler.NewHealthHandler()
+	var healthOpts []handler.HealthOption
+	var dbPoolHandler *handler.DBPoolHandler
+	if ctxManager != nil {
+		healthOpts = append(healthOpts, handler.WithDatabase(ctxManager, log))
+		dbPoolHandler = handler.NewDBPoolHandler(ctxManager)
+	}
+	healthHandler := handler.NewHealthHandler(healthOpts...)
 	docsHandler := handler.NewDocsHandler("openapi.yml")
-	statsHandler := handler.NewStatsHandler(prService, log)
+	statsHandler := handler.NewStatsHandler(prService, rollupService, log)
+	webhookHandler := handler.NewOutboundWebhookHandler(webhookService, log)
+	eventsHandler := handler.NewEventsHandler(outboxService, eventBus, log)
+	reviewQueueHandler := handler.NewReviewQueueHandler(userService, eventBus, log)
+	graphqlHandler := handler.NewGraphQLHandler(teamService, userService, prService, log)
+	exportHandler := handler.NewExportHandler(exportService, log)
+	teamTokenHandler := handler.NewTeamTokenHandler(teamTokenService, log)
+	auditHandler := handler.NewAuditHandler(auditService, log)
+	mc := cfg.Server.Maintenance
+	maintenanceSwitch, err := maintenance.NewSwitch(maintenance.Mode(mc.Mode), mc.Message, mc.RetryAfter)
+	if err != nil {
+		log.Error("Invalid maintenance config", zap.Error(err))
+		return nil, err
+	}
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceSwitch, log)
+	requestLog := middleware.NewRequestLogPolicy(cfg.Logger.Requests.SlowThreshold, cfg.Logger.Requests.SampleRatio)
+	requestLog.SetDebugRoutes(cfg.Logger.Requests.DebugRoutes)
+	requestLogHandler := handler.NewRequestLogHandler(requestLog, log)
+
+	handlers := Handlers{
+		Team:        teamHandler,
+		User:        userHandler,
+		PR:          prHandler,
+		Health:      healthHandler,
+		Docs:        docsHandler,
+		Stats:       statsHandler,
+		Webhooks:    webhookHandler,
+		Events:      eventsHandler,
+		GraphQL:     graphqlHandler,
+		Export:      exportHandler,
+		ReviewQueue: reviewQueueHandler,
+		TeamTokens:  teamTokenHandler,
+		Audit:       auditHandler,
+		Maintenance: maintenanceHandler,
+		RequestLog:  requestLogHandler,
+		DBPool:      dbPoolHandler,
+	}
+	// Integration routes are enabled by configuring a webhook secret
+	if cfg.Integrations.GitHub.WebhookSecret != "" {
+		handlers.GitHub = handler.NewGitHubHandler(prService,
+			cfg.Integrations.GitHub.WebhookSecret, cfg.Integrations.GitHub.Users, log)
+	}
+	if cfg.Integrations.GitLab.WebhookToken != "" {
+		handlers.GitLab = handler.NewGitLabHandler(prService,
+			cfg.Integrations.GitLab.WebhookToken, cfg.Integrations.GitLab.Users, log)
+	}
+	if len(cfg.Integrations.Bitbucket.Workspaces) > 0 {
+		workspaces := make(map[string]handler.BitbucketWorkspace, len(cfg.Integrations.Bitbucket.Workspaces))
+		for slug, ws := range cfg.Integrations.Bitbucket.Workspaces {
+			workspaces[slug] = handler.BitbucketWorkspace{Secret: ws.WebhookSecret, Users: ws.Users}
+		}
+		handlers.Bitbucket = handler.NewBitbucketHandler(prService, workspaces, log)
+	}
+	handlers.Generic, err = NewGenericWebhookHandler(cfg.Integrations.Generic, prService, log)
+	if err != nil {
+		log.Error("Invalid generic webhook config", zap.Error(err))
+		return nil, err
+	}
 
-	// Setup HTTP router
-	mux := http.NewServeMux()
+	server, err := NewServer(cfg, log, ServerDeps{
+		Handlers:    handlers,
+		TeamTokens:  teamTokenService,
+		Auditor:     auditService,
+		Maintenance: maintenanceSwitch,
+		RequestLog:  requestLog,
+	})
+	if err != nil {
+		log.Error("Invalid server configuration", zap.Error(err))
+		return nil, err
+	}
+	// Shutdown waits for open connections, so end the event streams first
+	server.httpServer.RegisterOnShutdown(eventBus.Close)
+
+	scheduledWorker := worker.NewScheduledChangesWorker(scheduleService, store.Orgs, cfg.Scheduler.PollInterval, cfg.Scheduler.BatchSize, log)
+	rollupWorker := worker.NewDailyRollupWorker(rollupService, store.Orgs, cfg.Stats.RollupInterval, log)
+	var retentionWorker *worker.RetentionWorker
+	if rc := cfg.Retention; rc.MergedPRAge > 0 {
+		retentionOpts := []retention.Option{retention.WithStatsCache(statsCache)}
+		if rc.Delete {
+			retentionOpts = append(retentionOpts, retention.WithDelete())
+		}
+		retentionService := retention.NewService(prRepo, transactor, rc.MergedPRAge, rc.BatchSize, retentionOpts...)
+		retentionWorker = worker.NewRetentionWorker(retentionService, store.Orgs, rc.Interval, log)
+	}
+	var partitionWorker *worker.PartitionWorker
+	if store.Partitions != nil {
+		partitionWorker = worker.NewPartitionWorker(store.Partitions, log)
+	}
+	webhookWorker := worker.NewWebhookDeliveriesWorker(webhookService, cfg.Webhooks.PollInterval, cfg.Webhooks.BatchSize, log)
+	var slackWorker *worker.SlackNotificationsWorker
+	if slackService != nil {
+		slackWorker = worker.NewSlackNotificationsWorker(slackService, store.Orgs, cfg.Slack.StaleCheckInterval, log)
+	}
+	var digestWorker *worker.ReviewDigestWorker
+	if slackService != nil && cfg.Slack.DigestSchedule != "" {
+		digestSchedule, err := cron.Parse(cfg.Slack.DigestSchedule)
+		if err != nil {
+			log.Error("Invalid Slack digest schedule", zap.Error(err))
+			return nil, err
+		}
+		digestWorker = worker.NewReviewDigestWorker(slackService, store.Orgs, digestSchedule, log)
+	}
+	var githubWorker *worker.GitHubWriteBackWorker
+	if githubSync != nil {
+		githubWorker = worker.NewGitHubWriteBackWorker(githubSync, log)
+	}
+	var jiraWorker *worker.JiraCommentsWorker
+	if jiraService != nil {
+		jiraWorker = worker.NewJiraCommentsWorker(jiraService, log)
+	}
+	channelWorker := worker.NewTeamChannelNotificationsWorker(channelService, log)
+	var relayWorker *worker.OutboxRelayWorker
+	if cfg.Events.Transport != "" {
+		relayWorker = worker.NewOutboxRelayWorker(outboxService, cfg.Events.PollInterval, cfg.Events.BatchSize, log)
+	}
+	// Teams are synced from LDAP when a directory server is configured
+	var directoryWorker *worker.DirectorySyncWorker
+	if lc := cfg.Directory.LDAP; lc.URL != "" {
+		directoryService := directory.NewService(NewLDAPDirectory(lc), teamService, userService)
+		directoryWorker = worker.NewDirectorySyncWorker(directoryService, cfg.Directory.SyncInterval, log)
+	}
+
+	// Overdue reviews are escalated when an on-call tool is configured
+	escalationService, err := NewEscalationService(cfg, prRepo)
+	if err != nil {
+		log.Error("Invalid escalation config", zap.Error(err))
+		return nil, err
+	}
+	var escalationWorker *worker.ReviewEscalationsWorker
+	if escalationService != nil {
+		escalationWorker = worker.NewReviewEscalationsWorker(escalationService, store.Orgs, cfg.Escalation.CheckInterval, log)
+	}
 
-	// Team routes
-	mux.HandleFunc("POST /team/add", teamHandler.AddTeam)
-	mux.HandleFunc("GET /team/get", teamHandler.GetTeam)
+	// Weekly report delivery is enabled by configuring a webhook
+	var reportWorker *worker.WeeklyReportWorker
+	if cfg.Report.WebhookURL != "" {
+		spec := cfg.Report.Schedule
+		if spec == "" {
+			spec = report.DefaultSchedule
+		}
+		reportSchedule, err := cron.Parse(spec)
+		if err != nil {
+			log.Error("Invalid report schedule", zap.Error(err))
+			return nil, err
+		}
+		reportService := report.NewService(prService, notify.NewWebhook(cfg.Report.WebhookURL, cfg.Report.Timeout), cfg.Report.ReviewSLA)
+		reportWorker = worker.NewWeeklyReportWorker(reportService, reportSchedule, log)
+	}
 
-	// User routes
-	mux.HandleFunc("POST /users/setIsActive", userHandler.SetIsActive)
-	mux.HandleFunc("GET /users/getReview", userHandler.GetReview)
-	mux.HandleFunc("POST /users/deactivateTeamMembers", userHandler.BulkDeactivateTeamMembers)
+	ok = true
+	return &App{
+		cfg:    cfg,
+		logger: log,
+		pool:   pool,
+		rpool:  replica,
+		server: server.httpServer,
+		admin:  server.adminServer,
+		acme:   server.acmeServer,
+		worker: scheduledWorker,
+		rollup: rollupWorker,
+		retain: retentionWorker,
+		parts:  partitionWorker,
+		report: reportWorker,
+		hooks:  webhookWorker,
+		slack:  slackWorker,
+		digest: digestWorker,
+		relay:  relayWorker,
+		github: githubWorker,
+		jira:   jiraWorker,
+		chans:  channelWorker,
+		dsync:  directoryWorker,
+		escal:  escalationWorker,
+		tracer: tracer,
+		health: healthHandler,
+
+		requests: server.requests,
+		txs:      ctxManager,
+	}, nil
+}
 
-	// PR routes
-	mux.HandleFunc("POST /pullRequest/create", prHandler.CreatePR)
-	mux.HandleFunc("POST /pullRequest/merge", prHandler.MergePR)
-	mux.HandleFunc("POST /pullRequest/reassign", prHandler.ReassignReviewer)
+// OpenDatabase connects to the database and its read replica when one is
+// configured, applies the migrations when configured and creates the context
+// manager transactions run in
+func OpenDatabase(cfg config.DatabaseConfig, traced bool, log *zap.Logger) (*pgxpool.Pool, *pgxpool.Pool, *db.ContextManager, error) {
+	// Build database DSN
+	dbURL := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=%s",
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.DBName,
+		cfg.SSLMode,
+	)
 
-	// Stats routes
-	mux.HandleFunc("GET /stats/assignments", statsHandler.GetAssignmentStats)
+	// Create DB connection pool
+	poolCfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		log.Error("Failed to parse DB config", zap.Error(err))
+		return nil, nil, nil, err
+	}
 
-	// Health route
-	mux.HandleFunc("GET /health", healthHandler.Check)
+	poolCfg.MaxConns = int32(cfg.MaxOpenConns)
+	poolCfg.MinConns = int32(cfg.MaxIdleConns)
+	poolCfg.MaxConnLifetime = cfg.ConnMaxLifetime
+	if traced {
+		poolCfg.ConnConfig.Tracer = tracing.QueryTracer{}
+	}
 
-	// Documentation routes
-	mux.HandleFunc("GET /docs", docsHandler.ServeSwaggerUI)
-	mux.HandleFunc("GET /openapi.yml", docsHandler.ServeOpenAPI)
+	// Wait for a database that is still starting instead of failing at once
+	pool, err := db.Connect(context.Background(), poolCfg, db.RetryPolicy{
+		MaxAttempts: cfg.ConnectRetry.MaxAttempts,
+		BaseDelay:   cfg.ConnectRetry.BaseDelay,
+		MaxDelay:    cfg.ConnectRetry.MaxDelay,
+	}, log)
+	if err != nil {
+		log.Error("Failed to connect to database", zap.Error(err))
+		return nil, nil, nil, err
+	}
 
-	// Apply middleware chain: Recovery → Logging
-	// Note: Error handling is done within handlers via middleware.WriteErrorResponse
-	var handler http.Handler = mux
-	handler = middleware.Logging(log)(handler)
-	handler = middleware.Recovery(log)(handler)
+	log.Info("Successfully connected to database")
 
-	// Create HTTP server
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      handler,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
-		IdleTimeout:  cfg.Server.IdleTimeout,
+	if cfg.AutoMigrate {
+		migrator, err := migrate.New(pool, migrations.FS, log)
+		if err != nil {
+			log.Error("Failed to load migrations", zap.Error(err))
+			pool.Close()
+			return nil, nil, nil, err
+		}
+		_, err = migrator.Up(context.Background())
+		migrator.Close()
+		if err != nil {
+			log.Error("Failed to apply migrations", zap.Error(err))
+			pool.Close()
+			return nil, nil, nil, err
+		}
+	}
+	metrics.RegisterPoolStats("primary", pool)
+
+	// Reads of routes that don't write go to the replica
+	var replica *pgxpool.Pool
+	if cfg.ReplicaDSN != "" {
+		replicaCfg, err := pgxpool.ParseConfig(cfg.ReplicaDSN)
+		if err != nil {
+			log.Error("Failed to parse read replica DSN", zap.Error(err))
+			pool.Close()
+			return nil, nil, nil, err
+		}
+		replicaCfg.MaxConns = poolCfg.MaxConns
+		replicaCfg.MinConns = poolCfg.MinConns
+		replicaCfg.MaxConnLifetime = poolCfg.MaxConnLifetime
+		replicaCfg.ConnConfig.Tracer = poolCfg.ConnConfig.Tracer
+		replica, err = db.Connect(context.Background(), replicaCfg, db.RetryPolicy{
+			MaxAttempts: cfg.ConnectRetry.MaxAttempts,
+			BaseDelay:   cfg.ConnectRetry.BaseDelay,
+			MaxDelay:    cfg.ConnectRetry.MaxDelay,
+		}, log)
+		if err != nil {
+			log.Error("Failed to connect to read replica", zap.Error(err))
+			pool.Close()
+			return nil, nil, nil, err
+		}
+		log.Info("Successfully connected to read replica")
+		metrics.RegisterPoolStats("replica", replica)
 	}
 
-	return &App{
-		cfg:    cfg,
-		logger: log,
-		pool:   pool,
-		server: server,
-	}, nil
+	// Initialize context manager (transactor)
+	dbBreaker := breaker.New(cfg.CircuitBreaker.FailureThreshold, cfg.CircuitBreaker.Cooldown)
+	dbBreaker.OnStateChange(func(state breaker.State) {
+		log.Warn("Database circuit breaker changed state", zap.Stringer("state", state))
+	})
+	if dbBreaker != nil {
+		metrics.RegisterCircuitBreaker(dbBreaker)
+	}
+	txProfiles, err := TxProfiles(cfg.Transactions)
+	if err != nil {
+		log.Error("Invalid transaction options", zap.Error(err))
+		closePool(pool, replica)
+		return nil, nil, nil, err
+	}
+	ctxManager := db.NewContextManager(pool, log, db.WithCircuitBreaker(dbBreaker), db.WithRetry(db.RetryPolicy{
+		MaxAttempts: cfg.Retry.MaxAttempts,
+		BaseDelay:   cfg.Retry.BaseDelay,
+		MaxDelay:    cfg.Retry.MaxDelay,
+	}), db.WithReplica(replica), db.WithTxProfiles(txProfiles))
+	return pool, replica, ctxManager, nil
+}
+
+// closePool closes the pools that are open; in-memory storage has none
+func closePool(pools ...*pgxpool.Pool) {
+	for _, pool := range pools {
+		if pool != nil {
+			pool.Close()
+		}
+	}
 }
 
 // Run starts the application
 func (a *App) Run() error {
-	// Start HTTP server in goroutine
+	// Start scheduled changes, rollup, retention, partition, delivery, relay, report, notification, digest, team channel, escalation and directory sync workers
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	a.jobs.Go(func() { a.worker.Run(workerCtx) })
+	a.jobs.Go(func() { a.rollup.Run(workerCtx) })
+	a.jobs.Go(func() { a.hooks.Run(workerCtx) })
+	a.jobs.Go(func() { a.chans.Run(workerCtx) })
+	if a.retain != nil {
+		a.jobs.Go(func() { a.retain.Run(workerCtx) })
+	}
+	if a.parts != nil {
+		a.jobs.Go(func() { a.parts.Run(workerCtx) })
+	}
+	if a.report != nil {
+		a.jobs.Go(func() { a.report.Run(workerCtx) })
+	}
+	if a.slack != nil {
+		a.jobs.Go(func() { a.slack.Run(workerCtx) })
+	}
+	if a.digest != nil {
+		a.jobs.Go(func() { a.digest.Run(workerCtx) })
+	}
+	if a.relay != nil {
+		a.jobs.Go(func() { a.relay.Run(workerCtx) })
+	}
+	if a.github != nil {
+		a.jobs.Go(func() { a.github.Run(workerCtx) })
+	}
+	if a.jira != nil {
+		a.jobs.Go(func() { a.jira.Run(workerCtx) })
+	}
+	if a.dsync != nil {
+		a.jobs.Go(func() { a.dsync.Run(workerCtx) })
+	}
+	if a.escal != nil {
+		a.jobs.Go(func() { a.escal.Run(workerCtx) })
+	}
+
+	// Start HTTP servers in goroutines
 	go func() {
 		a.logger.Info("Starting HTTP server", zap.String("address", a.server.Addr))
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := listenAndServe(a.server); err != nil && err != http.ErrServerClosed {
 			a.logger.Fatal("HTTP server error", zap.Error(err))
 		}
 	}()
+	if a.admin != nil {
+		go func() {
+			a.logger.Info("Starting admin HTTP server", zap.String("address", a.admin.Addr))
+			if err := listenAndServe(a.admin); err != nil && err != http.ErrServerClosed {
+				a.logger.Fatal("Admin HTTP server error", zap.Error(err))
+			}
+		}()
+	}
+	if a.acme != nil {
+		go func() {
+			a.logger.Info("Starting ACME challenge server", zap.String("address", a.acme.Addr))
+			if err := a.acme.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				a.logger.Fatal("ACME challenge server error", zap.Error(err))
+			}
+		}()
+	}
 
 	// Wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -167,89 +639,333 @@ func (a *App) Run() error {
 	<-quit
 
 	a.logger.Info("Shutting down server...")
+	drain(a.health, a.cfg.Server.ShutdownDelay, a.logger)
+	stopWorker()
 
 	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout(a.cfg.Server))
 	defer cancel()
 
+	if a.acme != nil {
+		if err := a.acme.Shutdown(ctx); err != nil {
+			a.logger.Error("ACME challenge server forced to shutdown", zap.Error(err))
+		}
+	}
+	var shutdownErr error
+	if a.admin != nil {
+		if err := a.admin.Shutdown(ctx); err != nil {
+			a.logger.Error("Admin server forced to shutdown", zap.Error(err))
+			shutdownErr = err
+		}
+	}
 	if err := a.server.Shutdown(ctx); err != nil {
 		a.logger.Error("Server forced to shutdown", zap.Error(err))
-		return err
+		shutdownErr = err
+	}
+
+	// Handlers and jobs may still hold transactions, so the pool is closed last
+	awaitIdle(ctx, a.requests, &a.jobs, a.txs, a.logger)
+	if a.pool != nil {
+		closePool(a.pool, a.rpool)
+		a.logger.Info("Database connection pool closed")
 	}
 
-	// Close database pool
-	a.pool.Close()
-	a.logger.Info("Database connection pool closed")
+	if a.tracer != nil {
+		if err := a.tracer.Shutdown(ctx); err != nil {
+			a.logger.Error("Failed to export remaining spans", zap.Error(err))
+		}
+	}
 
+	if shutdownErr != nil {
+		return shutdownErr
+	}
 	a.logger.Info("Server exited gracefully")
 	return nil
 }
 
+// ServerDeps are what NewServer serves requests with
+type ServerDeps struct {
+	Handlers Handlers
+	// TeamTokens authenticates team tokens, which are accepted next to OIDC ones
+	TeamTokens auth.Authenticator
+	// Auditor records requests to state-changing routes in the audit log
+	Auditor     middleware.Auditor
+	Maintenance *maintenance.Switch
+	RequestLog  *middleware.RequestLogPolicy
+}
+
 // NewServer creates a new HTTP server with configured routes and middleware
-func NewServer(
-	cfg *config.Config,
-	log *zap.Logger,
-	teamHandler *handler.TeamHandler,
-	userHandler *handler.UserHandler,
-	prHandler *handler.PRHandler,
-	healthHandler *handler.HealthHandler,
-	docsHandler *handler.DocsHandler,
-	statsHandler *handler.StatsHandler,
-) *Server {
-	// Setup HTTP router
+func NewServer(cfg *config.Config, log *zap.Logger, deps ServerDeps) (*Server, error) {
+	// API routes are served under /v1 and, for existing clients, at their unversioned paths
+	limits := newLimitsResolver(cfg.Server.Limits)
+	newRouter := func(mux *http.ServeMux) apiRouter {
+		return newAPIRouter(mux, apiV1, true, deps.Auditor, deps.Maintenance, deps.RequestLog, limits, log)
+	}
 	mux := http.NewServeMux()
+	registerAPIRoutes(newRouter(mux), deps.Handlers)
+	registerOperationalRoutes(mux, deps.Handlers)
 
-	// Team routes
-	mux.HandleFunc("POST /team/add", teamHandler.AddTeam)
-	mux.HandleFunc("GET /team/get", teamHandler.GetTeam)
+	// Admin routes move to their own listener when one is configured
+	adminMux := mux
+	if cfg.Server.AdminPort != 0 {
+		adminMux = http.NewServeMux()
+	}
+	admin, err := newAdminAccess(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	registerAdminRoutes(newRouter(adminMux).recording(admin.patterns), deps.Handlers)
 
-	// User routes
-	mux.HandleFunc("POST /users/setIsActive", userHandler.SetIsActive)
-	mux.HandleFunc("GET /users/getReview", userHandler.GetReview)
-	mux.HandleFunc("POST /users/deactivateTeamMembers", userHandler.BulkDeactivateTeamMembers)
+	listenerTLS, err := newListenerTLS(cfg.Server.TLS, cfg.Server.AdminPort)
+	if err != nil {
+		return nil, err
+	}
+	requests := &lifecycle.Tracker{}
+	server := &Server{
+		httpServer: newHTTPServer(cfg.Server.Port, withMiddleware(mux, cfg, deps.TeamTokens, admin, deps.RequestLog, requests, log), cfg.Server),
+		acmeServer: listenerTLS.challenge,
+		requests:   requests,
+		health:     deps.Handlers.Health,
+		drainDelay: cfg.Server.ShutdownDelay,
+		logger:     log,
+	}
+	server.httpServer.TLSConfig = listenerTLS.public
+	if cfg.Server.AdminPort != 0 {
+		server.adminServer = newHTTPServer(cfg.Server.AdminPort, withMiddleware(adminMux, cfg, deps.TeamTokens, admin, deps.RequestLog, requests, log), cfg.Server)
+		server.adminServer.TLSConfig = listenerTLS.admin
+	}
+	return server, nil
+}
 
-	// PR routes
-	mux.HandleFunc("POST /pullRequest/create", prHandler.CreatePR)
-	mux.HandleFunc("POST /pullRequest/merge", prHandler.MergePR)
-	mux.HandleFunc("POST /pullRequest/reassign", prHandler.ReassignReviewer)
+// Start starts the HTTP server, the admin listener and the ACME challenge
+// server, if any, and returns when either stops
+func (s *Server) Start() error {
+	errs := make(chan error, 3)
+	if s.adminServer != nil {
+		go func() {
+			s.logger.Info("Starting admin HTTP server", zap.String("address", s.adminServer.Addr))
+			errs <- listenAndServe(s.adminServer)
+		}()
+	}
+	if s.acmeServer != nil {
+		go func() {
+			s.logger.Info("Starting ACME challenge server", zap.String("address", s.acmeServer.Addr))
+			errs <- s.acmeServer.ListenAndServe()
+		}()
+	}
+	go func() {
+		s.logger.Info("Starting HTTP server", zap.String("address", s.httpServer.Addr))
+		errs <- listenAndServe(s.httpServer)
+	}()
+	return <-errs
+}
+
+// Drain fails readiness and waits out the configured shutdown delay so load
+// balancers stop routing to the server before Shutdown closes its listeners
+func (s *Server) Drain() {
+	drain(s.health, s.drainDelay, s.logger)
+}
+
+// drain marks health as not ready and waits for delay
+func drain(health *handler.HealthHandler, delay time.Duration, logger *zap.Logger) {
+	health.SetDraining()
+	if delay <= 0 {
+		return
+	}
+	logger.Info("Draining before shutdown", zap.Duration("delay", delay))
+	time.Sleep(delay)
+}
 
-	// Stats routes
-	mux.HandleFunc("GET /stats/assignments", statsHandler.GetAssignmentStats)
+// awaitIdle waits for in-flight requests, background jobs and open
+// transactions to finish, in that order, until ctx is done. Whatever is still
+// running then is logged and abandoned. txs is nil with in-memory storage.
+func awaitIdle(ctx context.Context, requests, jobs *lifecycle.Tracker, txs *db.ContextManager, logger *zap.Logger) {
+	if err := requests.Wait(ctx); err != nil {
+		logger.Warn("Requests still in flight at shutdown", zap.Int("count", requests.Active()))
+	}
+	if err := jobs.Wait(ctx); err != nil {
+		logger.Warn("Background jobs still running at shutdown", zap.Int("count", jobs.Active()))
+	}
+	if txs == nil {
+		return
+	}
+	if err := txs.WaitForTransactions(ctx); err != nil {
+		logger.Warn("Transactions still open at shutdown", zap.Int("count", txs.ActiveTransactions()))
+	}
+}
 
-	// Health route
-	mux.HandleFunc("GET /health", healthHandler.Check)
+// AwaitIdle waits, bounded by ctx, for the server's in-flight requests, for
+// jobs and for the open transactions of txs to finish. Call it after Shutdown
+// and before closing the database pool.
+func (s *Server) AwaitIdle(ctx context.Context, jobs *lifecycle.Tracker, txs *db.ContextManager) {
+	awaitIdle(ctx, s.requests, jobs, txs, s.logger)
+}
 
-	// Documentation routes
-	mux.HandleFunc("GET /docs", docsHandler.ServeSwaggerUI)
-	mux.HandleFunc("GET /openapi.yml", docsHandler.ServeOpenAPI)
+// Shutdown gracefully shuts down the server, the admin listener and the ACME
+// challenge server
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.acmeServer != nil {
+		if err := s.acmeServer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	if s.adminServer != nil {
+		if err := s.adminServer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	return s.httpServer.Shutdown(ctx)
+}
 
-	// Apply middleware chain: Recovery → Logging
+// withMiddleware applies the middleware chain: Track → RequestID → SecurityHeaders → Tracing → Compress → Recovery → Logging → Metrics → CORS → AdminAllowlist → Authenticate → RateLimit
+// Team tokens are accepted next to OIDC tokens.
+func withMiddleware(mux *http.ServeMux, cfg *config.Config, teamTokens auth.Authenticator, admin adminAccess, requestLog *middleware.RequestLogPolicy, requests *lifecycle.Tracker, log *zap.Logger) http.Handler {
+	docsCSP := cfg.Server.SecurityHeaders.DocsContentSecurityPolicy
+	if docsCSP == "" {
+		docsCSP = handler.SwaggerUIContentSecurityPolicy()
+	}
 	var handler http.Handler = mux
-	handler = middleware.Logging(log)(handler)
+	rl := cfg.Server.RateLimit
+	handler = middleware.RateLimit(ratelimit.New(rl.Requests, rl.Window), log)(handler)
+	if oc := cfg.Auth.OIDC; oc.Issuer != "" {
+		var oidcOpts []auth.OIDCOption
+		if oc.OrgClaim != "" {
+			oidcOpts = append(oidcOpts, auth.WithOrgClaim(oc.OrgClaim))
+		}
+		oidc := auth.NewOIDC(oc.Issuer, oc.Audience, oc.UserClaim, oc.RolesClaim, oc.Users, oc.Timeout, oidcOpts...)
+		handler = middleware.Authenticate(auth.NewPrefixed(domain.TeamTokenPrefix, teamTokens, oidc), log)(handler)
+	}
+	handler = middleware.AdminAllowlist(admin.allowed, mux, admin.patterns, log)(handler)
+	// Preflights carry no token, so they are answered before authentication
+	cc := cfg.Server.CORS
+	handler = middleware.CORS(middleware.CORSPolicy{
+		AllowedOrigins:   cc.AllowedOrigins,
+		AllowedMethods:   cc.AllowedMethods,
+		AllowedHeaders:   cc.AllowedHeaders,
+		ExposedHeaders:   cc.ExposedHeaders,
+		AllowCredentials: cc.AllowCredentials,
+		MaxAge:           cc.MaxAge,
+	})(handler)
+	handler = middleware.Metrics(mux)(handler)
+	handler = middleware.Logging(log, requestLog)(handler)
 	handler = middleware.Recovery(log)(handler)
+	if cc := cfg.Server.Compression; cc.Enabled {
+		handler = middleware.Compress(middleware.CompressionPolicy{Level: cc.Level, MinSize: cc.MinSize})(handler)
+	}
+	handler = middleware.Tracing(mux)(handler)
+	if sc := cfg.Server.SecurityHeaders; sc.Enabled {
+		handler = middleware.SecurityHeaders(middleware.SecurityHeadersPolicy{
+			HSTSMaxAge:                sc.HSTSMaxAge,
+			HSTSIncludeSubdomains:     sc.HSTSIncludeSubdomains,
+			FrameOptions:              sc.FrameOptions,
+			ReferrerPolicy:            sc.ReferrerPolicy,
+			ContentSecurityPolicy:     sc.ContentSecurityPolicy,
+			DocsContentSecurityPolicy: docsCSP,
+			DocsPath:                  "/docs",
+		})(handler)
+	}
+	handler = middleware.RequestID(log)(handler)
+	return middleware.Track(requests)(handler)
+}
+
+// adminAccess restricts admin routes, known by their mux patterns, to clients
+// in the allowed networks
+type adminAccess struct {
+	allowed  []netip.Prefix
+	patterns map[string]bool
+}
+
+func newAdminAccess(cfg config.ServerConfig) (adminAccess, error) {
+	allowed, err := middleware.ParseAllowlist(cfg.AdminAllowedCIDRs)
+	if err != nil {
+		return adminAccess{}, err
+	}
+	return adminAccess{allowed: allowed, patterns: map[string]bool{}}, nil
+}
+
+// shutdownTimeout returns how long shutdown may wait for running work
+func shutdownTimeout(cfg config.ServerConfig) time.Duration {
+	if cfg.ShutdownTimeout <= 0 {
+		return defaultShutdownTimeout
+	}
+	return cfg.ShutdownTimeout
+}
 
-	// Create HTTP server
-	httpServer := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
+// newHTTPServer creates a server listening on port with the configured timeouts
+func newHTTPServer(port int, handler http.Handler, cfg config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
 		Handler:      handler,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
-		IdleTimeout:  cfg.Server.IdleTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
 	}
+}
 
-	return &Server{
-		httpServer: httpServer,
-		logger:     log,
+// NewTracer creates the tracer provider exporting to the configured
+// collector, or returns nil when tracing is disabled
+func NewTracer(cfg config.TracingConfig) (*sdktrace.TracerProvider, error) {
+	if cfg.Endpoint == "" {
+		return nil, nil
+	}
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "pr-service"
 	}
+	return tracing.NewProvider(context.Background(), cfg.Endpoint, serviceName, cfg.Headers, cfg.SampleRatio, cfg.Timeout)
 }
 
-// Start starts the HTTP server
-func (s *Server) Start() error {
-	s.logger.Info("Starting HTTP server", zap.String("address", s.httpServer.Addr))
-	return s.httpServer.ListenAndServe()
+// NewLDAPDirectory creates the LDAP directory teams are synced from
+func NewLDAPDirectory(cfg config.LDAPConfig) *ldap.Directory {
+	return ldap.NewDirectory(ldap.NewClient(cfg.URL, cfg.BindDN, cfg.BindPassword, cfg.Timeout), ldap.DirectoryConfig{
+		UserBaseDN:         cfg.UserBaseDN,
+		UserFilter:         cfg.UserFilter,
+		UserIDAttribute:    cfg.UserIDAttribute,
+		UsernameAttribute:  cfg.UsernameAttribute,
+		GroupBaseDN:        cfg.GroupBaseDN,
+		GroupFilter:        cfg.GroupFilter,
+		GroupNameAttribute: cfg.GroupNameAttribute,
+		MemberAttribute:    cfg.MemberAttribute,
+	})
 }
 
-// Shutdown gracefully shuts down the server
-func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+// NewEscalationService creates the service escalating overdue reviews to the
+// configured on-call tool, or returns nil when escalation is disabled
+func NewEscalationService(cfg *config.Config, prRepo repository.PRRepository) (*escalation.Service, error) {
+	ec := cfg.Escalation
+	switch ec.Provider {
+	case "":
+		return nil, nil
+	case "pagerduty":
+		pagerDuty := notify.NewPagerDuty(ec.PagerDuty.RoutingKey, ec.PagerDuty.URL, ec.PagerDuty.Severity, ec.Timeout)
+		return escalation.NewService(prRepo, pagerDuty, cfg.Report.ReviewSLA, ec.Margin), nil
+	case "opsgenie":
+		opsgenie := notify.NewOpsgenie(ec.Opsgenie.APIKey, ec.Opsgenie.APIURL, ec.Opsgenie.Priority, ec.Timeout)
+		return escalation.NewService(prRepo, opsgenie, cfg.Report.ReviewSLA, ec.Margin), nil
+	default:
+		return nil, fmt.Errorf("unknown escalation provider %q", ec.Provider)
+	}
+}
+
+// NewGenericWebhookHandler creates the handler of the configured generic webhook
+// adapters, or returns nil when none is configured
+func NewGenericWebhookHandler(cfg config.GenericWebhookConfig, prService *pullrequest.Service, log *zap.Logger) (*handler.GenericWebhookHandler, error) {
+	if len(cfg.Adapters) == 0 {
+		return nil, nil
+	}
+	adapters := make(map[string]handler.GenericAdapter, len(cfg.Adapters))
+	for name, ac := range cfg.Adapters {
+		adapters[name] = handler.GenericAdapter{
+			Token:           ac.Token,
+			TokenHeader:     ac.TokenHeader,
+			Secret:          ac.Secret,
+			SignatureHeader: ac.SignatureHeader,
+			Action:          ac.Action,
+			CreateOn:        ac.CreateOn,
+			MergeOn:         ac.MergeOn,
+			Fields:          ac.Fields,
+			Users:           ac.Users,
+		}
+	}
+	return handler.NewGenericWebhookHandler(prService, adapters, log)
 }
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// AdminAllowlist is a middleware that refuses requests to admin routes from
+// clients outside allowed with 403 before they are authenticated. Routes are
+// looked up in routes and are admin routes when their pattern is in admin.
+// The client is the peer address of the connection, so behind a proxy the
+// proxy's address has to be allowed. An empty allowed list allows everyone.
+func AdminAllowlist(allowed []netip.Prefix, routes *http.ServeMux, admin map[string]bool, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, pattern := routes.Handler(r); !admin[pattern] || clientAllowed(r.RemoteAddr, allowed) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			logger.Warn("Admin request from a disallowed address",
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("path", r.URL.Path),
+				requestIDField(w),
+			)
+			WriteErrorResponse(w, fmt.Errorf("client address is not allowed to call admin routes: %w", domain.ErrForbidden), logger)
+		})
+	}
+}
+
+// clientAllowed reports whether the host of remoteAddr is in one of allowed
+func clientAllowed(remoteAddr string, allowed []netip.Prefix) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAllowlist parses CIDR ranges such as 10.0.0.0/8; single addresses are
+// taken as ranges of one address
+func ParseAllowlist(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q in admin allowlist", entry)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
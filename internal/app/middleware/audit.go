@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Auditor records state-changing requests in the audit log
+type Auditor interface {
+	Record(ctx context.Context, entry domain.AuditEntry) error
+}
+
+// Audit is a middleware that records each request to the route of a
+// "METHOD /path" pattern in the audit log once it was served: the caller, the
+// SHA-256 of the body, the status and the error code. Run it outside
+// RequireRole so rejected calls are recorded too. Failing to record is logged
+// and does not change the response, which has already been sent.
+func Audit(auditor Auditor, pattern string, logger *zap.Logger) func(http.Handler) http.Handler {
+	_, route, _ := strings.Cut(pattern, " ")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &hashingBody{ReadCloser: r.Body, hash: sha256.New()}
+			r.Body = body
+			wrapped := &auditResponseWriter{ResponseWriter: w}
+
+			next.ServeHTTP(wrapped, r)
+
+			// Hash the whole payload even when the handler stopped reading early
+			_, _ = io.Copy(io.Discard, body)
+
+			entry := domain.AuditEntry{
+				Method:      r.Method,
+				Route:       route,
+				Path:        r.URL.Path,
+				PayloadHash: hex.EncodeToString(body.hash.Sum(nil)),
+				Status:      wrapped.statusCode,
+				ErrorCode:   wrapped.errorCode,
+				RequestID:   w.Header().Get(RequestIDHeader),
+			}
+			if entry.Status == 0 {
+				entry.Status = http.StatusOK
+			}
+			if principal, ok := domain.PrincipalFromContext(r.Context()); ok {
+				entry.Actor = principal.UserID
+			}
+
+			if err := auditor.Record(context.WithoutCancel(r.Context()), entry); err != nil {
+				logger.Error("Failed to record audit entry",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Error(err),
+					requestIDField(w),
+				)
+			}
+		})
+	}
+}
+
+// hashingBody feeds everything read from a request body into hash
+type hashingBody struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	return n, err
+}
+
+// auditResponseWriter captures the status of an audited response and the
+// error code written by WriteErrorResponse
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	errorCode  string
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// setAuditErrorCode hands the code of an error response to the audit
+// middleware wrapping w, if any
+func setAuditErrorCode(w http.ResponseWriter, code string) {
+	for w != nil {
+		if aw, ok := w.(*auditResponseWriter); ok {
+			aw.errorCode = code
+			return
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = u.Unwrap()
+	}
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Authenticator resolves a bearer token to the caller and their roles
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (domain.Principal, error)
+}
+
+// publicPaths are served without a token: probes, metrics and docs, plus
+// integration webhooks, which are authenticated by their own signatures
+var publicPaths = []string{"/health", "/livez", "/readyz", "/metrics", "/docs", "/openapi.yml", "/errors"}
+
+const integrationsPrefix = "/integrations/"
+
+// Authenticate is a middleware that requires a valid bearer token on every
+// non-public route and stores the caller in the request context
+func Authenticate(auth Authenticator, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			token = strings.TrimSpace(token)
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				WriteErrorResponse(w, domain.ErrUnauthorized, logger)
+				return
+			}
+
+			principal, err := auth.Authenticate(r.Context(), token)
+			if err != nil {
+				logger.Info("Bearer token rejected",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Error(err),
+				)
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				WriteErrorResponse(w, domain.ErrUnauthorized, logger)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(domain.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireDefaultOrg is a middleware that rejects callers of organizations
+// other than the default one with 403 FORBIDDEN, for routes acting on the
+// whole deployment rather than on data of the caller's organization
+func RequireDefaultOrg(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if org := domain.OrgFromContext(r.Context()); org != domain.DefaultOrg {
+				logger.Info("Caller of another organization on a deployment route",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("org_id", org),
+				)
+				WriteErrorResponse(w, domain.ErrForbidden, logger)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole is a middleware that rejects callers without role, or a role
+// that includes it, with 403 FORBIDDEN. Requests are let through when
+// authentication is off, so it must run inside Authenticate.
+func RequireRole(role domain.Role, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := domain.PrincipalFromContext(r.Context())
+			if ok && !principal.HasRole(role) {
+				logger.Info("Caller lacks the role of the route",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("user_id", principal.UserID),
+					zap.String("role", string(role)),
+				)
+				WriteErrorResponse(w, domain.ErrForbidden, logger)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithCaller returns a context carrying an authenticated caller without roles
+func WithCaller(ctx context.Context, userID string) context.Context {
+	return domain.WithPrincipal(ctx, domain.Principal{UserID: userID})
+}
+
+// CallerFromContext returns the authenticated caller's user ID, if any
+func CallerFromContext(ctx context.Context) (string, bool) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	return principal.UserID, ok
+}
+
+func isPublicPath(path string) bool {
+	return strings.HasPrefix(trimAPIVersion(path), integrationsPrefix) || isProbePath(path)
+}
+
+// isProbePath reports whether path is a probe, metrics or docs route
+func isProbePath(path string) bool {
+	for _, public := range publicPaths {
+		if path == public {
+			return true
+		}
+	}
+	return false
+}
+
+// trimAPIVersion strips a leading API version segment such as "/v1" from path
+func trimAPIVersion(path string) string {
+	rest, ok := strings.CutPrefix(path, "/v")
+	if !ok {
+		return path
+	}
+	digits := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+	if digits <= 0 || rest[digits] != '/' {
+		return path
+	}
+	return rest[digits:]
+}
@@ -7,6 +7,9 @@ package middleware
 // 200 - OK
 // 201 - Created
 // 400 - Bad Request (TEAM_EXISTS, invalid arguments)
+// 401 - Unauthorized (UNAUTHORIZED)
+// 403 - Forbidden (FORBIDDEN)
 // 404 - Not Found (NOT_FOUND)
 // 409 - Conflict (PR_EXISTS, PR_MERGED, NOT_ASSIGNED, NO_CANDIDATE)
+// 429 - Too Many Requests (RATE_LIMITED)
 // 500 - Internal Server Error
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressionMinSize is the smallest response body compressed when
+// no minimum is configured; smaller bodies gain less than the gzip overhead
+const DefaultCompressionMinSize = 1024
+
+// CompressionPolicy configures response compression. A zero or invalid Level
+// uses the gzip default and a zero MinSize DefaultCompressionMinSize.
+type CompressionPolicy struct {
+	Level   int
+	MinSize int
+}
+
+// uncompressedTypes are streamed or already compressed and are sent as is
+var uncompressedTypes = []string{"text/event-stream", "application/gzip", "application/zip", "image/"}
+
+// Compress is a middleware that gzips response bodies of at least MinSize
+// bytes for clients accepting gzip in Accept-Encoding. Bodies are buffered
+// up to MinSize to decide, so small responses and those flushed before
+// reaching it are sent uncompressed. Responses that set their own
+// Content-Encoding and event streams are left alone.
+func Compress(policy CompressionPolicy) func(http.Handler) http.Handler {
+	if policy.Level == 0 || policy.Level < gzip.HuffmanOnly || policy.Level > gzip.BestCompression {
+		policy.Level = gzip.DefaultCompression
+	}
+	if policy.MinSize <= 0 {
+		policy.MinSize = DefaultCompressionMinSize
+	}
+	pool := &sync.Pool{New: func() any {
+		zw, _ := gzip.NewWriterLevel(io.Discard, policy.Level)
+		return zw
+	}}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !acceptsEncoding(r.Header.Get("Accept-Encoding"), "gzip") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, pool: pool, minSize: policy.MinSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptsEncoding checks if an Accept-Encoding header allows encoding, named
+// or through "*", with a non-zero quality
+func acceptsEncoding(header, encoding string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encoding && name != "*" {
+			continue
+		}
+		q := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		// The named encoding overrides the wildcard
+		if name == encoding {
+			return q > 0
+		}
+		accepted = q > 0
+	}
+	return accepted
+}
+
+// compressWriter holds the status and the start of the body back until it
+// knows whether the response is worth compressing
+type compressWriter struct {
+	http.ResponseWriter
+	pool    *sync.Pool
+	minSize int
+
+	status  int
+	buf     []byte
+	decided bool
+	zw      *gzip.Writer
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if cw.status != 0 {
+		return
+	}
+	cw.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.zw != nil {
+			return cw.zw.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide sends the headers, compressed when the body is large enough and of
+// a compressible type, followed by the buffered start of the body
+func (cw *compressWriter) decide(large bool) error {
+	cw.decided = true
+	h := cw.Header()
+	// Sniffing after compression would see gzip bytes, so it is done on the plain body
+	if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	if large && h.Get("Content-Encoding") == "" && compressibleType(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		cw.zw = cw.pool.Get().(*gzip.Writer)
+		cw.zw.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.zw != nil {
+		_, err = cw.zw.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// Flush sends what was written so far; a response flushed before reaching
+// the minimum size is not compressed
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		cw.decide(false)
+	}
+	if cw.zw != nil {
+		cw.zw.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// close sends a response that stayed below the minimum size, or ends the
+// gzip stream
+func (cw *compressWriter) close() {
+	if !cw.decided && cw.status != 0 {
+		cw.decide(false)
+	}
+	if cw.zw != nil {
+		cw.zw.Close()
+		cw.zw.Reset(io.Discard)
+		cw.pool.Put(cw.zw)
+		cw.zw = nil
+	}
+}
+
+func compressibleType(contentType string) bool {
+	for _, prefix := range uncompressedTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy lists what browser pages of other origins may do with the API.
+// Origins are full origins such as https://dash.example.com, "*" for any
+// origin, or a "*." wildcard such as https://*.example.com for every
+// subdomain. Empty methods and headers use the defaults below.
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "Accept", RequestIDHeader}
+	// defaultCORSExposed are the response headers of the API that scripts may read
+	defaultCORSExposed = []string{
+		RequestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After",
+		"Deprecation", "Sunset", "Link", DeprecatedFieldsHeader,
+	}
+)
+
+// CORS is a middleware that lets browser pages of the allowed origins call the
+// API: it answers preflight requests itself, before authentication, and adds
+// the Access-Control headers to responses to allowed origins. Requests from
+// other origins are served without them, so browsers withhold the response.
+// It does nothing when no origin is allowed.
+func CORS(policy CORSPolicy) func(http.Handler) http.Handler {
+	methods := orDefault(policy.AllowedMethods, defaultCORSMethods)
+	headers := orDefault(policy.AllowedHeaders, defaultCORSHeaders)
+	exposed := strings.Join(orDefault(policy.ExposedHeaders, defaultCORSExposed), ", ")
+	anyOrigin := slices.Contains(policy.AllowedOrigins, "*")
+
+	return func(next http.Handler) http.Handler {
+		if len(policy.AllowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			allowed := anyOrigin || slices.ContainsFunc(policy.AllowedOrigins, func(pattern string) bool {
+				return matchOrigin(pattern, origin)
+			})
+			if allowed {
+				// A literal "*" cannot be combined with credentials, so the origin is echoed instead
+				if anyOrigin && !policy.AllowCredentials {
+					h.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					h.Set("Access-Control-Allow-Origin", origin)
+				}
+				if policy.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			if !preflight {
+				if allowed {
+					h.Set("Access-Control-Expose-Headers", exposed)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Preflights are answered here; leaving out the Allow headers
+			// refuses a method or header the policy does not permit
+			method := r.Header.Get("Access-Control-Request-Method")
+			if allowed && containsFold(methods, method) && allHeadersAllowed(headers, r.Header.Get("Access-Control-Request-Headers")) {
+				h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+				h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+				if policy.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// matchOrigin checks origin against an allowed origin or a "*." subdomain wildcard
+func matchOrigin(pattern, origin string) bool {
+	if strings.EqualFold(pattern, origin) {
+		return true
+	}
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	prefix := scheme + "://"
+	suffix := "." + host
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.EqualFold(origin[:len(prefix)], prefix) &&
+		strings.EqualFold(origin[len(origin)-len(suffix):], suffix)
+}
+
+// allHeadersAllowed checks the comma-separated headers a preflight asks for
+func allHeadersAllowed(allowed []string, requested string) bool {
+	for _, name := range strings.Split(requested, ",") {
+		if name = strings.TrimSpace(name); name != "" && !containsFold(allowed, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, s string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+func orDefault(values, defaults []string) []string {
+	if len(values) == 0 {
+		return defaults
+	}
+	return values
+}
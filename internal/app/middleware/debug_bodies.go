@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// DebugBodiesHeader asks for the bodies of one request to be logged; it is
+// honoured for admin callers only
+const DebugBodiesHeader = "X-Debug-Bodies"
+
+// debugBodyLimit is how much of each body is kept for the log
+const debugBodyLimit = 64 << 10
+
+// redacted replaces the values of sensitive fields in logged bodies
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are substrings of field names whose values are never logged
+var sensitiveKeys = []string{"token", "secret", "password", "passwd", "authorization", "api_key", "apikey",
+	"signature", "credential", "private_key", "cookie", "session"}
+
+// DebugBodies is a middleware that logs the sanitized request and response
+// bodies of requests to the route of a "METHOD /path" pattern, when policy
+// has debugging on for the route or an admin caller sent DebugBodiesHeader.
+// Values of fields that look like credentials are redacted, and bodies are
+// cut at 64 KiB; JSON that had to be cut is not logged at all.
+func DebugBodies(policy *RequestLogPolicy, pattern string, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !policy.DebugRoute(pattern) && !debugRequested(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body := &capturingBody{ReadCloser: r.Body}
+			r.Body = body
+			wrapped := &capturingResponseWriter{ResponseWriter: w}
+
+			next.ServeHTTP(wrapped, r)
+
+			status := wrapped.statusCode
+			if status == 0 {
+				status = http.StatusOK
+			}
+			logger.Info("HTTP request bodies",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("route", pattern),
+				zap.Int("status", status),
+				zap.String("request_content_type", r.Header.Get("Content-Type")),
+				zap.String("request_body", sanitizeBody(r.Header.Get("Content-Type"), body.captured.Bytes(), body.size)),
+				zap.String("response_content_type", w.Header().Get("Content-Type")),
+				zap.String("response_body", sanitizeBody(w.Header().Get("Content-Type"), wrapped.captured.Bytes(), wrapped.size)),
+				requestIDField(w),
+			)
+		})
+	}
+}
+
+// debugRequested reports whether an admin caller asked for body logging
+func debugRequested(r *http.Request) bool {
+	if r.Header.Get(DebugBodiesHeader) == "" {
+		return false
+	}
+	principal, ok := domain.PrincipalFromContext(r.Context())
+	return ok && principal.HasRole(domain.RoleAdmin)
+}
+
+// capturingBody keeps the first debugBodyLimit bytes read from a request body
+type capturingBody struct {
+	io.ReadCloser
+	captured bytes.Buffer
+	size     int
+}
+
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += n
+	if room := debugBodyLimit - b.captured.Len(); room > 0 {
+		b.captured.Write(p[:min(n, room)])
+	}
+	return n, err
+}
+
+// capturingResponseWriter keeps the status and the first debugBodyLimit
+// bytes of a response
+type capturingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	captured   bytes.Buffer
+	size       int
+}
+
+func (w *capturingResponseWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *capturingResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	if room := debugBodyLimit - w.captured.Len(); room > 0 {
+		w.captured.Write(b[:min(n, room)])
+	}
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *capturingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// sanitizeBody renders a captured body of size bytes for the log with
+// sensitive values redacted. Only JSON, NDJSON, form and plain text bodies are
+// shown; others are described by their size.
+func sanitizeBody(contentType string, captured []byte, size int) string {
+	if size == 0 {
+		return ""
+	}
+	truncated := size > len(captured)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if truncated {
+			return fmt.Sprintf("<%d bytes of JSON, too large to sanitize>", size)
+		}
+		return sanitizeJSON(captured)
+	case mediaType == "application/x-ndjson":
+		lines := strings.Split(strings.TrimRight(string(captured), "\n"), "\n")
+		if truncated {
+			// The last line may be cut in the middle
+			lines = lines[:len(lines)-1]
+		}
+		for i, line := range lines {
+			lines[i] = sanitizeJSON([]byte(line))
+		}
+		return strings.Join(lines, "\n") + truncatedNote(truncated, size)
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(captured))
+		if err != nil {
+			return fmt.Sprintf("<%d bytes of malformed form data>", size)
+		}
+		for key := range values {
+			if isSensitiveKey(key) {
+				values[key] = []string{redacted}
+			}
+		}
+		return values.Encode() + truncatedNote(truncated, size)
+	case mediaType == "text/plain" || mediaType == "text/csv":
+		return string(captured) + truncatedNote(truncated, size)
+	default:
+		return fmt.Sprintf("<%d bytes of %s>", size, contentType)
+	}
+}
+
+func truncatedNote(truncated bool, size int) string {
+	if !truncated {
+		return ""
+	}
+	return fmt.Sprintf("... <truncated, %d bytes in total>", size)
+}
+
+// sanitizeJSON redacts sensitive fields of a JSON document, at any depth
+func sanitizeJSON(data []byte) string {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Sprintf("<%d bytes of malformed JSON>", len(data))
+	}
+	out, err := json.Marshal(redact(doc))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes of JSON>", len(data))
+	}
+	return string(out)
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isSensitiveKey(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redact(value)
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = redact(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-service/internal/metrics"
+)
+
+// Deprecation describes the retirement of a route, or of some fields of its
+// request and response DTOs when Fields is set
+type Deprecation struct {
+	// Since is when the route or fields were deprecated
+	Since time.Time
+	// Sunset is when they stop being served; zero while no date is set
+	Sunset time.Time
+	// Fields names the deprecated DTO fields; empty deprecates the whole route
+	Fields []string
+	// Link points to the migration notes or the successor route
+	Link string
+}
+
+// DeprecatedFieldsHeader lists the deprecated DTO fields of a route that is
+// itself still supported
+const DeprecatedFieldsHeader = "X-Deprecated-Fields"
+
+// Deprecated is a middleware that announces d on every response of the route
+// it wraps: Deprecation (RFC 9745) carries the date it was deprecated, Sunset
+// (RFC 8594) the date it goes away, and Link the documentation.
+func Deprecated(d Deprecation) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+			if !d.Sunset.IsZero() {
+				h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if len(d.Fields) > 0 {
+				h.Set(DeprecatedFieldsHeader, strings.Join(d.Fields, ", "))
+			}
+			if d.Link != "" {
+				h.Add("Link", "<"+d.Link+`>; rel="deprecation"`)
+			}
+
+			next.ServeHTTP(w, r)
+
+			metrics.DeprecatedRequests.WithLabelValues(r.Method, routeLabel(r)).Inc()
+		})
+	}
+}
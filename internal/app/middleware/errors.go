@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -16,7 +17,15 @@ type ErrorResponse struct {
 
 // ErrorDetail represents the error details
 type ErrorDetail struct {
-	Code    string `json:"code"`
+	Code      string             `json:"code"`
+	Message   string             `json:"message"`
+	Details   []FieldErrorDetail `json:"details,omitempty"`
+	RequestID string             `json:"request_id,omitempty"`
+}
+
+// FieldErrorDetail names an invalid request field and the constraint it breaks
+type FieldErrorDetail struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
@@ -35,35 +44,43 @@ func ErrorHandler(logger *zap.Logger) func(http.Handler) http.Handler {
 // WriteErrorResponse writes an error response in OpenAPI format
 func WriteErrorResponse(w http.ResponseWriter, err error, logger *zap.Logger) {
 	statusCode := domain.GetHTTPStatus(err)
-	errorCode := domain.GetErrorCode(err)
 
 	// Log internal errors
 	if statusCode == http.StatusInternalServerError {
 		logger.Error("Internal server error",
 			zap.Error(err),
 			zap.Int("status", statusCode),
+			requestIDField(w),
 		)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
-	response := ErrorResponse{
-		Error: ErrorDetail{
-			Code:    string(errorCode),
-			Message: err.Error(),
-		},
+	response := ErrorResponse{Error: NewErrorDetail(err)}
+	response.Error.RequestID = w.Header().Get(RequestIDHeader)
+	setAuditErrorCode(w, response.Error.Code)
+	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+		logger.Error("failed to encode error response", zap.Error(encodeErr))
 	}
+}
 
+// NewErrorDetail describes err for an error response, naming the offending
+// fields of validation errors and hiding unknown errors
+func NewErrorDetail(err error) ErrorDetail {
+	errorCode := domain.GetErrorCode(err)
 	if errorCode == "" {
-		// For unknown errors, use generic message
-		response.Error.Code = "INTERNAL_ERROR"
-		response.Error.Message = "internal server error"
+		return ErrorDetail{Code: string(domain.ErrorCodeInternal), Message: "internal server error"}
 	}
 
-	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
-		logger.Error("failed to encode error response", zap.Error(encodeErr))
+	detail := ErrorDetail{Code: string(errorCode), Message: err.Error()}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, v := range validationErr.Violations {
+			detail.Details = append(detail.Details, FieldErrorDetail{Field: v.Field, Message: v.Constraint})
+		}
 	}
+	return detail
 }
 
 // MapDomainError maps domain errors to HTTP status codes and error codes
@@ -83,6 +100,24 @@ func MapDomainError(err error) (int, domain.ErrorCode) {
 		return http.StatusConflict, domain.ErrorCodeNoCandidate
 	case errors.Is(err, domain.ErrInvalidArgument):
 		return http.StatusBadRequest, ""
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized, domain.ErrorCodeUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, domain.ErrorCodeForbidden
+	case errors.Is(err, domain.ErrTicketNotFound):
+		return http.StatusBadRequest, domain.ErrorCodeTicketNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict, domain.ErrorCodeConflict
+	case errors.Is(err, domain.ErrRateLimited):
+		return http.StatusTooManyRequests, domain.ErrorCodeRateLimited
+	case errors.Is(err, domain.ErrPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge, domain.ErrorCodePayloadTooLarge
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable, domain.ErrorCodeTimeout
+	case errors.Is(err, domain.ErrUnavailable):
+		return http.StatusServiceUnavailable, domain.ErrorCodeUnavailable
+	case errors.Is(err, domain.ErrMaintenance):
+		return http.StatusServiceUnavailable, domain.ErrorCodeMaintenance
 	default:
 		return http.StatusInternalServerError, ""
 	}
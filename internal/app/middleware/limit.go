@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// RouteLimits bounds what one request to a route may take. A non-positive
+// MaxBodySize or Timeout leaves that resource unbounded.
+type RouteLimits struct {
+	// MaxBodySize is the largest request body accepted, in bytes
+	MaxBodySize int64
+	// Timeout is the deadline of the request context
+	Timeout time.Duration
+}
+
+// Limit is a middleware enforcing limits on a route. Bodies declaring a
+// larger Content-Length are rejected with 413 PAYLOAD_TOO_LARGE before the
+// handler runs; streamed bodies are cut off at the limit, which handlers
+// report as an invalid body. Handlers still working at the deadline see their
+// context cancelled, and queries fail with 503 TIMEOUT.
+func Limit(limits RouteLimits, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limits.MaxBodySize > 0 {
+				if r.ContentLength > limits.MaxBodySize {
+					logger.Info("Request body over the route limit",
+						zap.String("method", r.Method),
+						zap.String("path", r.URL.Path),
+						zap.Int64("content_length", r.ContentLength),
+						zap.Int64("max_body_size", limits.MaxBodySize),
+					)
+					WriteErrorResponse(w, domain.ErrPayloadTooLarge, logger)
+					return
+				}
+				r.Body = http.MaxBytesReader(w, r.Body, limits.MaxBodySize)
+			}
+			if limits.Timeout > 0 {
+				ctx, cancel := context.WithTimeout(r.Context(), limits.Timeout)
+				defer cancel()
+				r = r.WithContext(ctx)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
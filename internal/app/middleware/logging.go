@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"fmt"
+	"math/rand"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -29,8 +32,108 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
-// Logging is a middleware that logs HTTP requests and responses
-func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
+// Unwrap exposes the underlying writer to http.ResponseController, so streaming
+// handlers can flush and extend deadlines through the middleware
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// RequestLogPolicy decides which requests Logging writes: requests slower than
+// the slow threshold and server errors always, other requests with the sample
+// ratio. Its settings can be changed while requests are served.
+type RequestLogPolicy struct {
+	random func() float64
+
+	mu    sync.RWMutex
+	slow  time.Duration
+	ratio float64
+	debug map[string]bool
+}
+
+// NewRequestLogPolicy creates a policy; see SetThresholds for the arguments
+func NewRequestLogPolicy(slow time.Duration, ratio float64) *RequestLogPolicy {
+	p := &RequestLogPolicy{random: rand.Float64}
+	p.SetThresholds(slow, ratio)
+	return p
+}
+
+// SetThresholds sets the slow threshold and the share of other requests that
+// are logged. Zero slow disables the slow threshold; a ratio outside (0, 1]
+// logs every request.
+func (p *RequestLogPolicy) SetThresholds(slow time.Duration, ratio float64) {
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.slow, p.ratio = slow, ratio
+}
+
+// Thresholds returns the slow threshold and the sample ratio
+func (p *RequestLogPolicy) Thresholds() (time.Duration, float64) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.slow, p.ratio
+}
+
+// SetDebugRoutes replaces the "METHOD /path" patterns of the routes whose
+// request and response bodies are logged by DebugBodies
+func (p *RequestLogPolicy) SetDebugRoutes(patterns []string) {
+	debug := make(map[string]bool, len(patterns))
+	for _, pattern := range patterns {
+		debug[pattern] = true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.debug = debug
+}
+
+// DebugRoutes returns the patterns of the routes whose bodies are logged
+func (p *RequestLogPolicy) DebugRoutes() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	patterns := make([]string, 0, len(p.debug))
+	for pattern := range p.debug {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// DebugRoute reports whether the bodies of a route are logged
+func (p *RequestLogPolicy) DebugRoute(pattern string) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.debug[pattern]
+}
+
+// reason returns why a request is logged, or "" if it is not
+func (p *RequestLogPolicy) reason(duration time.Duration, status int) string {
+	if p == nil {
+		return "all"
+	}
+	slow, ratio := p.Thresholds()
+	switch {
+	case slow > 0 && duration >= slow:
+		return "slow"
+	case status >= http.StatusInternalServerError:
+		return "error"
+	case ratio >= 1:
+		return "all"
+	case p.random() < ratio:
+		return "sampled"
+	default:
+		return ""
+	}
+}
+
+// Logging is a middleware that logs HTTP requests and responses chosen by
+// policy; a nil policy logs every request. Slow requests are logged as
+// warnings.
+func Logging(logger *zap.Logger, policy *RequestLogPolicy) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -43,8 +146,12 @@ func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 			next.ServeHTTP(wrapped, r)
 
 			duration := time.Since(start)
+			reason := policy.reason(duration, wrapped.statusCode)
+			if reason == "" {
+				return
+			}
 
-			logger.Info("HTTP request",
+			fields := []zap.Field{
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
 				zap.String("query", r.URL.RawQuery),
@@ -54,9 +161,15 @@ func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.Int("response_size", wrapped.written),
 				zap.Duration("duration", duration),
 				zap.String("duration_ms", fmt.Sprintf("%.2f", duration.Seconds()*1000)),
-			)
+				zap.String("log_reason", reason),
+				requestIDField(w),
+				traceIDField(r.Context()),
+			}
+			if reason == "slow" {
+				logger.Warn("Slow HTTP request", fields...)
+				return
+			}
+			logger.Info("HTTP request", fields...)
 		})
 	}
 }
-
-// must import fmt
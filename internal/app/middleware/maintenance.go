@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/maintenance"
+
+	"go.uber.org/zap"
+)
+
+// Maintenance is a middleware that rejects requests to a route with 503 and
+// Retry-After while the maintenance mode of sw refuses them; write tells
+// whether the route changes state
+func Maintenance(sw *maintenance.Switch, write bool, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := sw.State()
+			if !state.Rejects(write) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err := domain.ErrMaintenance
+			if state.Message != "" {
+				err = fmt.Errorf("%s: %w", state.Message, domain.ErrMaintenance)
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(state.RetryAfter.Seconds()))))
+			WriteErrorResponse(w, err, logger)
+		})
+	}
+}
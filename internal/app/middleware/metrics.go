@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-service/internal/metrics"
+)
+
+// Metrics is a middleware that records request counts, latencies and requests
+// in flight per route of routes. The route is looked up before serving, so it
+// is known even when inner middleware passes a copy of the request on.
+func Metrics(routes *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			_, pattern := routes.Handler(r)
+			route := patternRoute(pattern)
+			metrics.HTTPRequestsInFlight.WithLabelValues(r.Method, route).Inc()
+			defer metrics.HTTPRequestsInFlight.WithLabelValues(r.Method, route).Dec()
+
+			wrapped := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     0,
+			}
+
+			next.ServeHTTP(wrapped, r)
+
+			status := wrapped.statusCode
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			code := strconv.Itoa(status)
+			metrics.HTTPRequests.WithLabelValues(r.Method, route, code).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(r.Method, route, code).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// routeLabel labels r by its matched pattern, not its path, to keep the number
+// of series bounded
+func routeLabel(r *http.Request) string {
+	return patternRoute(r.Pattern)
+}
+
+// patternRoute returns the path of a ServeMux pattern, or "unmatched" when no
+// route matched
+func patternRoute(pattern string) string {
+	if pattern == "" {
+		return "unmatched"
+	}
+	_, path, found := strings.Cut(pattern, " ")
+	if !found {
+		return pattern
+	}
+	return path
+}
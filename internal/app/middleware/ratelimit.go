@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/ratelimit"
+
+	"go.uber.org/zap"
+)
+
+// RateLimit is a middleware that limits requests per client and reports the
+// quota on every response in RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers (seconds until the quota refills), so clients can
+// throttle themselves. Clients are told apart by the authenticated caller, or
+// by IP address when authentication is off, so it must run inside
+// Authenticate. Probes, metrics and docs are not limited.
+func RateLimit(limiter *ratelimit.Limiter, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isProbePath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			status := limiter.Allow(rateLimitKey(r))
+			reset := strconv.Itoa(int(math.Ceil(status.Reset.Seconds())))
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(status.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+			h.Set("RateLimit-Reset", reset)
+			if !status.Allowed {
+				h.Set("Retry-After", reset)
+				WriteErrorResponse(w, domain.ErrRateLimited, logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifies the client a request counts against. User IDs are
+// only unique within an organization.
+func rateLimitKey(r *http.Request) string {
+	if userID, ok := CallerFromContext(r.Context()); ok {
+		return "user:" + domain.OrgFromContext(r.Context()) + "/" + userID
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
 	"runtime/debug"
 
+	"pr-service/internal/domain"
+
 	"go.uber.org/zap"
 )
 
@@ -18,12 +21,18 @@ func Recovery(logger *zap.Logger) func(http.Handler) http.Handler {
 						zap.String("path", r.URL.Path),
 						zap.Any("panic", err),
 						zap.String("stack", string(debug.Stack())),
+						requestIDField(w),
 					)
 
 					// Return 500 Internal Server Error
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusInternalServerError)
-					if _, writeErr := w.Write([]byte(`{"error":{"code":"INTERNAL_ERROR","message":"internal server error"}}`)); writeErr != nil {
+					response := ErrorResponse{Error: ErrorDetail{
+						Code:      string(domain.ErrorCodeInternal),
+						Message:   "internal server error",
+						RequestID: w.Header().Get(RequestIDHeader),
+					}}
+					if writeErr := json.NewEncoder(w).Encode(response); writeErr != nil {
 						logger.Error("failed to write recovery response", zap.Error(writeErr))
 					}
 				}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pr-service/internal/db"
+)
+
+// ReplicaReads is a middleware that lets the repositories serve the reads of a
+// route that doesn't write from the read replica, if one is configured
+func ReplicaReads(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(db.AllowReplica(r.Context())))
+	})
+}
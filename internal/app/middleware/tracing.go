@@ -1,21 +1,125 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
+	"strconv"
 
+	"pr-service/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
-// Tracing middleware for distributed tracing (optional for this project)
-// Can be implemented later if needed with OpenTelemetry or similar
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds request IDs accepted from clients and proxies
+const maxRequestIDLength = 128
 
-// RequestID adds a unique request ID to each request
+type requestIDKey struct{}
+
+// RequestID adds a unique request ID to each request. An ID set by a proxy
+// in X-Request-Id is kept; otherwise one is generated. The ID is echoed in the
+// X-Request-Id response header, logged and included in error responses.
 func RequestID(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// For now, just pass through
-			// Can add request ID generation here if needed
-			next.ServeHTTP(w, r)
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+		})
+	}
+}
+
+// RequestIDFromContext returns the ID of the request being served
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// requestIDField returns the log field of the request ID set on w, if any
+func requestIDField(w http.ResponseWriter) zap.Field {
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		return zap.String("request_id", id)
+	}
+	return zap.Skip()
+}
+
+// Tracing is a middleware that records a server span for each request, joining
+// the caller's trace when the request carries a W3C traceparent header. Spans
+// are named by the route of routes the request matches.
+func Tracing(routes *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pattern := routes.Handler(r)
+			route := patternRoute(pattern)
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("http.route", route),
+					attribute.String("url.path", r.URL.Path),
+				))
+			defer span.End()
+
+			wrapped := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     0,
+			}
+
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			status := wrapped.statusCode
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if id := w.Header().Get(RequestIDHeader); id != "" {
+				span.SetAttributes(attribute.String("http.request.id", id))
+			}
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, strconv.Itoa(status))
+			}
 		})
 	}
 }
+
+// traceIDField returns the log field of the trace ID of a recorded span in ctx, if any
+func traceIDField(ctx context.Context) zap.Field {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return zap.Skip()
+	}
+	return zap.String("trace_id", span.SpanContext().TraceID().String())
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID accepts short IDs of printable ASCII, so client-supplied IDs
+// cannot forge log lines or headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
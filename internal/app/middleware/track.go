@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pr-service/internal/lifecycle"
+)
+
+// Track is a middleware that counts requests being served in requests, so
+// shutdown can wait for them before releasing what handlers depend on
+func Track(requests *lifecycle.Tracker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer requests.Start()()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
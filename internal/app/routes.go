@@ -0,0 +1,382 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/config"
+	"pr-service/internal/domain"
+	"pr-service/internal/handler"
+	"pr-service/internal/maintenance"
+	"pr-service/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// apiV1 is the path prefix of the first API version
+const apiV1 = "/v1"
+
+// deprecatedRoutes is the route metadata table of the API lifecycle, keyed by
+// "METHOD /path" patterns as they are registered. Responses of a listed route
+// carry Deprecation and Sunset headers, under every version prefix it is
+// served at; list Fields to deprecate only parts of its DTOs. Document the
+// entry in openapi.yml as well, and drop the route once the sunset has passed.
+var deprecatedRoutes = map[string]middleware.Deprecation{}
+
+// routeRoles is the minimum token role of each API route, keyed like
+// deprecatedRoutes; other routes are open to every authenticated caller.
+// Routes acting on one team are lead routes, and their services further
+// limit leads to the teams they belong to. Roles are only checked when
+// authentication is on.
+var routeRoles = map[string]domain.Role{
+	"POST /team/add":           domain.RoleAdmin,
+	"POST /team/rename":        domain.RoleAdmin,
+	"POST /team/setParent":     domain.RoleAdmin,
+	"POST /team/import":        domain.RoleAdmin,
+	"POST /team/merge":         domain.RoleAdmin,
+	"POST /team/delete":        domain.RoleAdmin,
+	"GET /team/deleted":        domain.RoleAdmin,
+	"POST /team/restore":       domain.RoleAdmin,
+	"POST /users/add":          domain.RoleAdmin,
+	"POST /users/setRole":      domain.RoleAdmin,
+	"POST /users/delete":       domain.RoleAdmin,
+	"GET /users/deleted":       domain.RoleAdmin,
+	"POST /users/restore":      domain.RoleAdmin,
+	"POST /pullRequest/delete": domain.RoleAdmin,
+	"POST /webhooks/subscribe": domain.RoleAdmin,
+	"POST /webhooks/delete":    domain.RoleAdmin,
+	"GET /admin/export":        domain.RoleAdmin,
+	"GET /admin/audit":         domain.RoleAdmin,
+	"GET /admin/maintenance":   domain.RoleAdmin,
+	"POST /admin/maintenance":  domain.RoleAdmin,
+	"GET /admin/logging":       domain.RoleAdmin,
+	"POST /admin/logging":      domain.RoleAdmin,
+	"GET /admin/db/pool":       domain.RoleAdmin,
+	"POST /admin/import":       domain.RoleAdmin,
+
+	"POST /team/setSettings":            domain.RoleLead,
+	"POST /team/tokens/issue":           domain.RoleLead,
+	"GET /team/tokens/list":             domain.RoleLead,
+	"POST /team/tokens/revoke":          domain.RoleLead,
+	"POST /users/setIsActive":           domain.RoleLead,
+	"POST /users/deactivateTeamMembers": domain.RoleLead,
+	"POST /users/activateTeamMembers":   domain.RoleLead,
+}
+
+// unauditedRoutes are the non-GET routes left out of the audit log: GraphQL
+// only runs queries, and heartbeats are presence pings sent every few minutes
+var unauditedRoutes = map[string]bool{
+	"POST /graphql":         true,
+	"POST /users/heartbeat": true,
+}
+
+// queryRoutes are the non-GET routes that only read, so they stay available
+// in read-only maintenance mode
+var queryRoutes = map[string]bool{
+	"POST /graphql": true,
+}
+
+// primaryReadRoutes are the read routes that react to writes as they happen,
+// so they read from the primary rather than a lagging replica
+var primaryReadRoutes = map[string]bool{
+	"GET /users/reviewQueue/wait": true,
+}
+
+// maintenanceRoutes stay available in every maintenance mode, so it can be
+// inspected and turned off
+var maintenanceRoutes = map[string]bool{
+	"GET /admin/maintenance":  true,
+	"POST /admin/maintenance": true,
+}
+
+// deploymentRoutes act on the whole deployment rather than on the data of the
+// caller's organization, so only callers of the default organization may use
+// them
+var deploymentRoutes = map[string]bool{
+	"GET /admin/maintenance":  true,
+	"POST /admin/maintenance": true,
+	"GET /admin/logging":      true,
+	"POST /admin/logging":     true,
+	"GET /admin/db/pool":      true,
+}
+
+const (
+	// defaultMaxBodySize bounds request bodies of routes without their own limit
+	defaultMaxBodySize = 1 << 20
+	// defaultRequestTimeout bounds requests to routes without their own limit
+	defaultRequestTimeout = 30 * time.Second
+)
+
+// routeLimits are the built-in limits of routes that carry large payloads or
+// run long, keyed like deprecatedRoutes. Zero fields use the defaults and
+// negative ones remove the limit; config overrides both.
+var routeLimits = map[string]middleware.RouteLimits{
+	"POST /admin/import":          {MaxBodySize: 256 << 20, Timeout: 10 * time.Minute},
+	"GET /admin/export":           {Timeout: 10 * time.Minute},
+	"POST /team/import":           {MaxBodySize: 16 << 20, Timeout: 2 * time.Minute},
+	"POST /batch":                 {MaxBodySize: 8 << 20, Timeout: 2 * time.Minute},
+	"GET /users/reviewQueue/wait": {Timeout: 3 * time.Minute},
+	"GET /events/stream":          {Timeout: -1},
+
+	// Code hosts send payloads of up to 25 MB
+	"POST /integrations/github/webhook":         {MaxBodySize: 25 << 20},
+	"POST /integrations/gitlab/webhook":         {MaxBodySize: 25 << 20},
+	"POST /integrations/bitbucket/webhook":      {MaxBodySize: 25 << 20},
+	"POST /integrations/generic/{name}/webhook": {MaxBodySize: 25 << 20},
+}
+
+// limitsResolver merges the default, built-in and configured limits of routes
+type limitsResolver struct {
+	defaults  middleware.RouteLimits
+	overrides map[string]config.RouteLimitsConfig
+}
+
+func newLimitsResolver(cfg config.LimitsConfig) limitsResolver {
+	defaults := middleware.RouteLimits{MaxBodySize: cfg.MaxBodySize, Timeout: cfg.Timeout}
+	if defaults.MaxBodySize == 0 {
+		defaults.MaxBodySize = defaultMaxBodySize
+	}
+	if defaults.Timeout == 0 {
+		defaults.Timeout = defaultRequestTimeout
+	}
+	return limitsResolver{defaults: defaults, overrides: cfg.Routes}
+}
+
+// forRoute returns the limits of a "METHOD /path" pattern
+func (l limitsResolver) forRoute(pattern string) middleware.RouteLimits {
+	limits := routeLimits[pattern]
+	if o, ok := l.overrides[pattern]; ok {
+		if o.MaxBodySize != 0 {
+			limits.MaxBodySize = o.MaxBodySize
+		}
+		if o.Timeout != 0 {
+			limits.Timeout = o.Timeout
+		}
+	}
+	if limits.MaxBodySize == 0 {
+		limits.MaxBodySize = l.defaults.MaxBodySize
+	}
+	if limits.Timeout == 0 {
+		limits.Timeout = l.defaults.Timeout
+	}
+	return limits
+}
+
+// apiRouter registers API routes under a version prefix. With legacy set it
+// also serves them at the unversioned paths clients used before versioning,
+// so breaking DTO changes can ship under a new prefix without breaking them.
+// With an auditor set, requests to state-changing routes are recorded in the
+// audit log. Every route gets its body size and time limits, and with a
+// maintenance switch set routes are refused while maintenance mode is on.
+// With a request log policy set, bodies of routes being debugged are logged.
+// Routes that don't write may read from the read replica.
+type apiRouter struct {
+	// registered, when set, collects the mux patterns of the routes
+	registered  map[string]bool
+	mux         *http.ServeMux
+	prefix      string
+	legacy      bool
+	auditor     middleware.Auditor
+	maintenance *maintenance.Switch
+	requestLog  *middleware.RequestLogPolicy
+	limits      limitsResolver
+	logger      *zap.Logger
+}
+
+func newAPIRouter(mux *http.ServeMux, prefix string, legacy bool, auditor middleware.Auditor, sw *maintenance.Switch,
+	requestLog *middleware.RequestLogPolicy, limits limitsResolver, logger *zap.Logger) apiRouter {
+	return apiRouter{mux: mux, prefix: prefix, legacy: legacy, auditor: auditor, maintenance: sw, requestLog: requestLog, limits: limits, logger: logger}
+}
+
+// HandleFunc registers handler for a "METHOD /path" pattern under the version prefix
+func (a apiRouter) HandleFunc(pattern string, handler http.HandlerFunc) {
+	if role, ok := routeRoles[pattern]; ok {
+		handler = middleware.RequireRole(role, a.logger)(handler).ServeHTTP
+	}
+	if deploymentRoutes[pattern] {
+		handler = middleware.RequireDefaultOrg(a.logger)(handler).ServeHTTP
+	}
+	if d, ok := deprecatedRoutes[pattern]; ok {
+		handler = middleware.Deprecated(d)(handler).ServeHTTP
+	}
+	method, path, _ := strings.Cut(pattern, " ")
+	write := method != http.MethodGet && !queryRoutes[pattern]
+	if !write && !primaryReadRoutes[pattern] {
+		handler = middleware.ReplicaReads(handler).ServeHTTP
+	}
+	if a.auditor != nil && method != http.MethodGet && !unauditedRoutes[pattern] {
+		handler = middleware.Audit(a.auditor, pattern, a.logger)(handler).ServeHTTP
+	}
+	if a.requestLog != nil {
+		handler = middleware.DebugBodies(a.requestLog, pattern, a.logger)(handler).ServeHTTP
+	}
+	// Outside the audit, since refused requests change nothing
+	if a.maintenance != nil && !maintenanceRoutes[pattern] {
+		handler = middleware.Maintenance(a.maintenance, write, a.logger)(handler).ServeHTTP
+	}
+	// Outside the audit, which reads the rest of the body to hash it
+	handler = middleware.Limit(a.limits.forRoute(pattern), a.logger)(handler).ServeHTTP
+	a.mux.HandleFunc(method+" "+a.prefix+path, handler)
+	if a.registered != nil {
+		a.registered[method+" "+a.prefix+path] = true
+	}
+	if a.legacy {
+		a.mux.HandleFunc(pattern, handler)
+		if a.registered != nil {
+			a.registered[pattern] = true
+		}
+	}
+}
+
+// recording returns a router that adds the mux patterns of the routes it
+// registers to patterns
+func (a apiRouter) recording(patterns map[string]bool) apiRouter {
+	a.registered = patterns
+	return a
+}
+
+// Handlers are the HTTP handlers the server routes requests to. An
+// integration handler is nil when its integration is disabled, and DBPool is
+// nil without a database.
+type Handlers struct {
+	Team        *handler.TeamHandler
+	User        *handler.UserHandler
+	PR          *handler.PRHandler
+	Health      *handler.HealthHandler
+	Docs        *handler.DocsHandler
+	Stats       *handler.StatsHandler
+	GitHub      *handler.GitHubHandler
+	GitLab      *handler.GitLabHandler
+	Bitbucket   *handler.BitbucketHandler
+	Generic     *handler.GenericWebhookHandler
+	Webhooks    *handler.OutboundWebhookHandler
+	Events      *handler.EventsHandler
+	GraphQL     *handler.GraphQLHandler
+	Export      *handler.ExportHandler
+	ReviewQueue *handler.ReviewQueueHandler
+	TeamTokens  *handler.TeamTokenHandler
+	Audit       *handler.AuditHandler
+	Maintenance *handler.MaintenanceHandler
+	RequestLog  *handler.RequestLogHandler
+	DBPool      *handler.DBPoolHandler
+}
+
+// registerAPIRoutes registers the API routes served on the public listener
+func registerAPIRoutes(api apiRouter, h Handlers) {
+	// Team routes
+	api.HandleFunc("POST /team/add", h.Team.AddTeam)
+	api.HandleFunc("GET /team/get", h.Team.GetTeam)
+	api.HandleFunc("GET /team/list", h.Team.ListTeams)
+	api.HandleFunc("POST /team/rename", h.Team.RenameTeam)
+	api.HandleFunc("POST /team/setParent", h.Team.SetParentTeam)
+	api.HandleFunc("POST /team/import", h.Team.ImportTeam)
+	api.HandleFunc("POST /team/merge", h.Team.MergeTeams)
+	api.HandleFunc("GET /team/auditLog", h.Team.GetAuditLog)
+	api.HandleFunc("GET /team/settings", h.Team.GetSettings)
+	api.HandleFunc("POST /team/setSettings", h.Team.SetSettings)
+	api.HandleFunc("POST /team/tokens/issue", h.TeamTokens.Issue)
+	api.HandleFunc("GET /team/tokens/list", h.TeamTokens.List)
+	api.HandleFunc("POST /team/tokens/revoke", h.TeamTokens.Revoke)
+
+	// User routes
+	api.HandleFunc("POST /users/add", h.Team.AddMember)
+	api.HandleFunc("POST /users/setIsActive", h.User.SetIsActive)
+	api.HandleFunc("POST /users/setRole", h.User.SetRole)
+	api.HandleFunc("POST /users/heartbeat", h.User.Heartbeat)
+	api.HandleFunc("GET /users/dormant", h.User.ListDormantUsers)
+	api.HandleFunc("GET /users/getReview", h.User.GetReview)
+	api.HandleFunc("GET /users/reviewQueue/wait", h.ReviewQueue.Wait)
+	api.HandleFunc("GET /users/{id}/reviews.ics", h.User.GetReviewCalendar)
+	api.HandleFunc("POST /users/deactivateTeamMembers", h.User.BulkDeactivateTeamMembers)
+	api.HandleFunc("POST /users/activateTeamMembers", h.User.BulkActivateTeamMembers)
+
+	// PR routes
+	api.HandleFunc("POST /pullRequest/create", h.PR.CreatePR)
+	api.HandleFunc("POST /pullRequest/merge", h.PR.MergePR)
+	api.HandleFunc("POST /pullRequest/reassign", h.PR.ReassignReviewer)
+	api.HandleFunc("POST /pullRequest/review", h.PR.RecordReview)
+	api.HandleFunc("GET /pullRequest/list", h.PR.ListPRs)
+	api.HandleFunc("POST /batch", h.PR.Batch)
+
+	// Stats routes
+	api.HandleFunc("GET /stats/assignments", h.Stats.GetAssignmentStats)
+	api.HandleFunc("GET /stats/aging", h.Stats.GetAging)
+	api.HandleFunc("GET /stats/authors", h.Stats.GetAuthorStats)
+	api.HandleFunc("GET /stats/daily", h.Stats.GetDailyStats)
+	api.HandleFunc("GET /stats/fairness", h.Stats.GetFairness)
+	api.HandleFunc("GET /stats/pairs", h.Stats.GetReviewPairs)
+	api.HandleFunc("GET /stats/reassignments", h.Stats.GetReassignmentStats)
+	api.HandleFunc("GET /stats/timeToReview", h.Stats.GetTimeToReview)
+	api.HandleFunc("GET /stats/timeToMerge", h.Stats.GetTimeToMerge)
+	api.HandleFunc("GET /stats/workload", h.Stats.GetWorkload)
+
+	// Outbound webhook routes
+	api.HandleFunc("POST /webhooks/subscribe", h.Webhooks.Subscribe)
+	api.HandleFunc("GET /webhooks/list", h.Webhooks.ListSubscriptions)
+	api.HandleFunc("POST /webhooks/delete", h.Webhooks.Delete)
+	api.HandleFunc("GET /webhooks/deliveries", h.Webhooks.ListDeliveries)
+
+	// Event log routes
+	api.HandleFunc("GET /events", h.Events.ListEvents)
+	api.HandleFunc("GET /events/stream", h.Events.Stream)
+
+	// GraphQL routes
+	api.HandleFunc("POST /graphql", h.GraphQL.Query)
+	api.HandleFunc("GET /graphql/schema", h.GraphQL.Schema)
+
+	// Integration routes; a nil handler leaves the integration disabled
+	if h.GitHub != nil {
+		api.HandleFunc("POST /integrations/github/webhook", h.GitHub.Webhook)
+	}
+	if h.GitLab != nil {
+		api.HandleFunc("POST /integrations/gitlab/webhook", h.GitLab.Webhook)
+	}
+	if h.Bitbucket != nil {
+		api.HandleFunc("POST /integrations/bitbucket/webhook", h.Bitbucket.Webhook)
+	}
+	if h.Generic != nil {
+		api.HandleFunc("POST /integrations/generic/{name}/webhook", h.Generic.Webhook)
+	}
+}
+
+// registerOperationalRoutes registers the unversioned probe, metrics and
+// documentation routes, which need no token
+func registerOperationalRoutes(mux *http.ServeMux, h Handlers) {
+	// Health routes; /health predates the liveness/readiness split
+	mux.HandleFunc("GET /health", h.Health.Check)
+	mux.HandleFunc("GET /livez", h.Health.Check)
+	mux.HandleFunc("GET /readyz", h.Health.Ready)
+
+	// Metrics route
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	// Documentation routes
+	mux.HandleFunc("GET /docs", h.Docs.ServeSwaggerUI)
+	mux.HandleFunc("GET /openapi.yml", h.Docs.ServeOpenAPI)
+	mux.HandleFunc("GET /errors", handler.NewErrorCatalogHandler().List)
+}
+
+// registerAdminRoutes registers operations that destroy or bulk-copy data.
+// With server.admin_port set they are served only on the admin listener, so
+// the public port can be exposed without them.
+func registerAdminRoutes(api apiRouter, h Handlers) {
+	api.HandleFunc("POST /team/delete", h.Team.DeleteTeam)
+	api.HandleFunc("GET /team/deleted", h.Team.ListDeletedTeams)
+	api.HandleFunc("POST /team/restore", h.Team.RestoreTeam)
+	api.HandleFunc("POST /users/delete", h.User.DeleteUser)
+	api.HandleFunc("GET /users/deleted", h.User.ListDeletedUsers)
+	api.HandleFunc("POST /users/restore", h.User.RestoreUser)
+	api.HandleFunc("POST /pullRequest/delete", h.PR.DeletePR)
+	api.HandleFunc("GET /admin/export", h.Export.Export)
+	api.HandleFunc("POST /admin/import", h.Export.Import)
+	api.HandleFunc("GET /admin/audit", h.Audit.List)
+	api.HandleFunc("GET /admin/maintenance", h.Maintenance.Get)
+	api.HandleFunc("POST /admin/maintenance", h.Maintenance.Set)
+	api.HandleFunc("GET /admin/logging", h.RequestLog.Get)
+	api.HandleFunc("POST /admin/logging", h.RequestLog.Set)
+	if h.DBPool != nil {
+		api.HandleFunc("GET /admin/db/pool", h.DBPool.Get)
+	}
+}
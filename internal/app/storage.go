@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pr-service/internal/config"
+	"pr-service/internal/db"
+	"pr-service/internal/repository"
+	"pr-service/internal/repository/memory"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Storage backends selected by the storage config key
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// Storage holds the repositories of a storage backend and the transactor the
+// services run their transactions in. Orgs is nil for backends that only
+// hold the default organization.
+type Storage struct {
+	Teams            repository.TeamRepository
+	Users            repository.UserRepository
+	PRs              repository.PRRepository
+	ScheduledChanges repository.ScheduledChangeRepository
+	Audit            repository.AuditRepository
+	Rollups          repository.RollupRepository
+	Webhooks         repository.WebhookRepository
+	Outbox           repository.OutboxRepository
+	Export           repository.ExportRepository
+	Partitions       repository.PartitionRepository
+	Orgs             repository.OrgRepository
+	TeamTokens       repository.TeamTokenRepository
+	AuditLog         repository.AuditLogRepository
+	Transactor       db.Transactioner
+}
+
+// NewPostgresStorage creates the storage backed by the database behind cm
+func NewPostgresStorage(cm *db.ContextManager) *Storage {
+	return &Storage{
+		Teams:            repository.NewTeamRepository(cm),
+		Users:            repository.NewUserRepository(cm),
+		PRs:              repository.NewPRRepository(cm),
+		ScheduledChanges: repository.NewScheduledChangeRepository(cm),
+		Audit:            repository.NewAuditRepository(cm),
+		Rollups:          repository.NewRollupRepository(cm),
+		Webhooks:         repository.NewWebhookRepository(cm),
+		Outbox:           repository.NewOutboxRepository(cm),
+		Export:           repository.NewExportRepository(cm),
+		Partitions:       repository.NewPartitionRepository(cm),
+		Orgs:             repository.NewOrgRepository(cm),
+		TeamTokens:       repository.NewTeamTokenRepository(cm),
+		AuditLog:         repository.NewAuditLogRepository(cm),
+		Transactor:       cm,
+	}
+}
+
+// NewMemoryStorage creates an empty storage kept in process memory, for demos
+// and local development without Postgres
+func NewMemoryStorage() *Storage {
+	users := memory.NewUserRepository()
+	teams := memory.NewTeamRepository(users)
+	prs := memory.NewPRRepository(users)
+	return &Storage{
+		Teams:            teams,
+		Users:            users,
+		PRs:              prs,
+		ScheduledChanges: memory.NewScheduledChangeRepository(),
+		Audit:            memory.NewAuditRepository(),
+		Rollups:          memory.NewRollupRepository(prs),
+		Webhooks:         memory.NewWebhookRepository(),
+		Outbox:           memory.NewOutboxRepository(),
+		Export:           memory.NewExportRepository(teams, users, prs),
+		TeamTokens:       memory.NewTeamTokenRepository(),
+		AuditLog:         memory.NewAuditLogRepository(),
+		Transactor:       memory.NewTransactor(),
+	}
+}
+
+// UsesDatabase reports whether storage names the Postgres backend, the
+// default when it is empty, and fails for unknown backends
+func UsesDatabase(storage string) (bool, error) {
+	switch storage {
+	case "", StoragePostgres:
+		return true, nil
+	case StorageMemory:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown storage %q, expected %q or %q", storage, StoragePostgres, StorageMemory)
+	}
+}
+
+// CheckOrgClaim fails when tokens name organizations, through
+// auth.oidc.org_claim, that the storage can't keep apart: the in-memory
+// storage, used when pool is nil, only holds the default organization, and
+// database roles bypassing row level security see the rows of every one
+func CheckOrgClaim(ctx context.Context, oidc config.OIDCConfig, pool *pgxpool.Pool) error {
+	if oidc.OrgClaim == "" {
+		return nil
+	}
+	if pool == nil {
+		return fmt.Errorf("auth.oidc.org_claim needs the %q storage", StoragePostgres)
+	}
+	bypassed, err := db.RowSecurityBypassed(ctx, pool)
+	if err != nil {
+		return err
+	}
+	if bypassed {
+		return errors.New("auth.oidc.org_claim needs a database role subject to row level security, not a superuser or a role with BYPASSRLS")
+	}
+	return nil
+}
+
+// TxProfiles returns the transaction options configured in cfg by profile,
+// and fails for unknown isolation levels
+func TxProfiles(cfg config.TransactionsConfig) (map[db.TxProfile]db.TxOptions, error) {
+	profiles := make(map[db.TxProfile]db.TxOptions, 3)
+	for profile, tx := range map[db.TxProfile]config.TxConfig{
+		db.ProfileDefault: cfg.Default,
+		db.ProfileBulk:    cfg.Bulk,
+		db.ProfileStats:   cfg.Stats,
+	} {
+		isolation, err := db.ParseIsolation(tx.Isolation)
+		if err != nil {
+			return nil, fmt.Errorf("transactions %q: %w", profile, err)
+		}
+		profiles[profile] = db.TxOptions{Isolation: isolation, StatementTimeout: tx.StatementTimeout}
+	}
+	return profiles, nil
+}
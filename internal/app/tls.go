@@ -0,0 +1,97 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"pr-service/internal/config"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// listenerTLS is the TLS setup of the HTTP listeners
+type listenerTLS struct {
+	public *tls.Config
+	admin  *tls.Config
+	// challenge answers ACME HTTP-01 challenges and redirects other plain
+	// HTTP requests to HTTPS; nil unless autocert has an HTTP port
+	challenge *http.Server
+}
+
+// newListenerTLS loads the certificate of the listeners from files, or sets
+// up autocert to obtain it from Let's Encrypt. A zero listenerTLS serves plain
+// HTTP. With a client CA the admin listener only accepts clients presenting
+// a certificate it signed, which requires a separate admin port.
+func newListenerTLS(cfg config.TLSConfig, adminPort int) (listenerTLS, error) {
+	fromFiles := cfg.CertFile != "" || cfg.KeyFile != ""
+	fromACME := len(cfg.Autocert.Domains) > 0
+	switch {
+	case fromFiles && fromACME:
+		return listenerTLS{}, errors.New("tls.cert_file and tls.autocert cannot be combined")
+	case !fromFiles && !fromACME:
+		if cfg.AdminClientCAFile != "" {
+			return listenerTLS{}, errors.New("tls.admin_client_ca_file requires TLS to be enabled")
+		}
+		return listenerTLS{}, nil
+	case cfg.AdminClientCAFile != "" && adminPort == 0:
+		return listenerTLS{}, errors.New("tls.admin_client_ca_file requires server.admin_port")
+	}
+
+	var lt listenerTLS
+	if fromFiles {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return listenerTLS{}, errors.New("tls.cert_file and tls.key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return listenerTLS{}, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		lt.public = &tls.Config{Certificates: []tls.Certificate{cert}}
+	} else {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.Autocert.Domains...),
+			Email:      cfg.Autocert.Email,
+		}
+		if cfg.Autocert.CacheDir != "" {
+			manager.Cache = autocert.DirCache(cfg.Autocert.CacheDir)
+		}
+		lt.public = manager.TLSConfig()
+		if cfg.Autocert.HTTPPort != 0 {
+			lt.challenge = &http.Server{
+				Addr:              fmt.Sprintf(":%d", cfg.Autocert.HTTPPort),
+				Handler:           manager.HTTPHandler(nil),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+		}
+	}
+	lt.public.MinVersion = tls.VersionTLS12
+
+	lt.admin = lt.public.Clone()
+	if cfg.AdminClientCAFile != "" {
+		data, err := os.ReadFile(cfg.AdminClientCAFile)
+		if err != nil {
+			return listenerTLS{}, fmt.Errorf("failed to read admin client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return listenerTLS{}, errors.New("admin client CA file has no PEM certificates")
+		}
+		lt.admin.ClientCAs = pool
+		lt.admin.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return lt, nil
+}
+
+// listenAndServe serves HTTPS when srv has a TLS config and plain HTTP otherwise
+func listenAndServe(srv *http.Server) error {
+	if srv.TLSConfig != nil {
+		return srv.ListenAndServeTLS("", "")
+	}
+	return srv.ListenAndServe()
+}
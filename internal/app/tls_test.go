@@ -0,0 +1,151 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pr-service/internal/config"
+)
+
+// testCert is a certificate signed by parent, or self-signed when parent is nil
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert, template x509.Certificate) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.Subject = pkix.Name{CommonName: name}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := &template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// writePEM writes the certificate and its key to dir and returns their paths
+func (c *testCert) writePEM(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o600); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestListenerTLSRequiresAdminClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "test CA", nil, x509.Certificate{IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign})
+	server := newTestCert(t, "localhost", ca, x509.Certificate{
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	client := newTestCert(t, "ops", ca, x509.Certificate{ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	stranger := newTestCert(t, "stranger", nil, x509.Certificate{ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+
+	certFile, keyFile := server.writePEM(t, dir, "server")
+	caFile, _ := ca.writePEM(t, dir, "ca")
+	lt, err := newListenerTLS(config.TLSConfig{CertFile: certFile, KeyFile: keyFile, AdminClientCAFile: caFile}, 8081)
+	if err != nil {
+		t.Fatalf("newListenerTLS: %v", err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	public := httptest.NewUnstartedServer(ok)
+	public.TLS = lt.public
+	public.StartTLS()
+	defer public.Close()
+	admin := httptest.NewUnstartedServer(ok)
+	admin.TLS = lt.admin
+	admin.StartTLS()
+	defer admin.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(url string, cert *testCert) error {
+		t.Helper()
+		cfg := &tls.Config{RootCAs: roots}
+		if cert != nil {
+			cfg.Certificates = []tls.Certificate{{Certificate: [][]byte{cert.der}, PrivateKey: cert.key}}
+		}
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+		resp, err := c.Get(url)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	if err := get(public.URL, nil); err != nil {
+		t.Fatalf("expected the public listener to serve clients without certificates: %v", err)
+	}
+	if err := get(admin.URL, client); err != nil {
+		t.Fatalf("expected the admin listener to accept a client certificate of the CA: %v", err)
+	}
+	for name, cert := range map[string]*testCert{"no certificate": nil, "foreign certificate": stranger} {
+		if err := get(admin.URL, cert); err == nil {
+			t.Fatalf("%s: expected the admin listener to refuse the client", name)
+		}
+	}
+}
+
+func TestListenerTLSConfigErrors(t *testing.T) {
+	for name, c := range map[string]struct {
+		cfg       config.TLSConfig
+		adminPort int
+	}{
+		"files and autocert":      {config.TLSConfig{CertFile: "a", KeyFile: "b", Autocert: config.AutocertConfig{Domains: []string{"x.example.com"}}}, 0},
+		"certificate without key": {config.TLSConfig{CertFile: "a"}, 0},
+		"client CA without TLS":   {config.TLSConfig{AdminClientCAFile: "ca.pem"}, 8081},
+		"client CA without admin port": {config.TLSConfig{
+			Autocert: config.AutocertConfig{Domains: []string{"x.example.com"}}, AdminClientCAFile: "ca.pem",
+		}, 0},
+	} {
+		if _, err := newListenerTLS(c.cfg, c.adminPort); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+
+	lt, err := newListenerTLS(config.TLSConfig{}, 0)
+	if err != nil || lt.public != nil || lt.admin != nil {
+		t.Fatalf("expected plain HTTP without TLS settings, got %+v, %v", lt, err)
+	}
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+const (
+	// DefaultUserClaim is the token claim holding the caller when none is configured
+	DefaultUserClaim = "sub"
+	// DefaultRolesClaim is the token claim holding the caller's roles when none is configured
+	DefaultRolesClaim = "roles"
+	// defaultTimeout bounds discovery and JWKS requests when no timeout is configured
+	defaultTimeout = 10 * time.Second
+)
+
+// signingAlgorithms are the JWS algorithms tokens may be signed with
+var signingAlgorithms = []string{
+	oidc.RS256, oidc.RS384, oidc.RS512,
+	oidc.ES256, oidc.ES384, oidc.ES512,
+}
+
+// OIDC authenticates bearer tokens issued by an OpenID Connect provider with
+// go-oidc. Tokens are JWTs signed with RS256/384/512 or ES256/384/512 by a key
+// published in the provider's JWKS, which is located through the discovery
+// document on first use and refetched when a token names an unknown key.
+type OIDC struct {
+	issuer     string
+	audience   string
+	claim      string
+	rolesClaim string
+	orgClaim   string
+	users      map[string]string
+	client     *http.Client
+	now        func() time.Time
+
+	mu       sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+// OIDCOption configures optional OIDC behaviour
+type OIDCOption func(*OIDC)
+
+// WithOrgClaim reads the caller's organization from claim. Tokens without a
+// valid organization in it are rejected; without the option every caller
+// belongs to the default organization.
+func WithOrgClaim(claim string) OIDCOption {
+	return func(o *OIDC) {
+		o.orgClaim = claim
+	}
+}
+
+// NewOIDC creates an authenticator for tokens issued by issuer. A non-empty
+// audience must be listed in the token's aud claim. The caller is read from
+// claim (DefaultUserClaim when empty) and mapped to a user ID through users;
+// unmapped values are used as user IDs. Roles are read from rolesClaim
+// (DefaultRolesClaim when empty), a role name or a list of them; unknown names
+// are ignored and tokens without a known role grant member.
+func NewOIDC(issuer, audience, claim, rolesClaim string, users map[string]string, timeout time.Duration, opts ...OIDCOption) *OIDC {
+	if claim == "" {
+		claim = DefaultUserClaim
+	}
+	if rolesClaim == "" {
+		rolesClaim = DefaultRolesClaim
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	o := &OIDC{
+		issuer:     issuer,
+		audience:   audience,
+		claim:      claim,
+		rolesClaim: rolesClaim,
+		users:      users,
+		client:     &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Authenticate verifies token and returns the caller with their roles
+func (o *OIDC) Authenticate(ctx context.Context, token string) (domain.Principal, error) {
+	verifier, err := o.idTokenVerifier(ctx)
+	if err != nil {
+		return domain.Principal{}, err
+	}
+	idToken, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	var raw map[string]any
+	if err := idToken.Claims(&raw); err != nil {
+		return domain.Principal{}, fmt.Errorf("malformed token claims: %w", err)
+	}
+	subject, _ := raw[o.claim].(string)
+	if subject == "" {
+		return domain.Principal{}, fmt.Errorf("token has no %q claim", o.claim)
+	}
+	principal := domain.Principal{UserID: subject, Roles: tokenRoles(raw[o.rolesClaim])}
+	if userID, ok := o.users[subject]; ok {
+		principal.UserID = userID
+	}
+	if o.orgClaim != "" {
+		org, _ := raw[o.orgClaim].(string)
+		if !domain.ValidOrgID(org) {
+			return domain.Principal{}, fmt.Errorf("token has no valid %q claim", o.orgClaim)
+		}
+		principal.Org = org
+	}
+	return principal, nil
+}
+
+// tokenRoles reads the known roles of a roles claim holding a role name or a
+// list of them; without any the caller is a member
+func tokenRoles(claim any) []domain.Role {
+	var names []any
+	switch v := claim.(type) {
+	case string:
+		names = []any{v}
+	case []any:
+		names = v
+	}
+
+	var roles []domain.Role
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			if role := domain.Role(strings.ToLower(s)); role.IsValid() && !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		return []domain.Role{domain.RoleMember}
+	}
+	return roles
+}
+
+// idTokenVerifier returns the verifier of the provider's tokens, reading the
+// discovery document on first use. A failed discovery is retried by the next
+// call. The lock is not held while the document is fetched, so concurrent
+// first calls may each fetch it.
+func (o *OIDC) idTokenVerifier(ctx context.Context) (*oidc.IDTokenVerifier, error) {
+	o.mu.Lock()
+	verifier := o.verifier
+	o.mu.Unlock()
+	if verifier != nil {
+		return verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, o.client), o.issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+	}
+	var jwks struct {
+		URI string `json:"jwks_uri"`
+	}
+	if err := provider.Claims(&jwks); err != nil || jwks.URI == "" {
+		return nil, errors.New("OIDC discovery document has no jwks_uri")
+	}
+	// The key set outlives ctx; it fetches keys with the client alone
+	keys := oidc.NewRemoteKeySet(oidc.ClientContext(context.Background(), o.client), jwks.URI)
+	verifier = oidc.NewVerifier(o.issuer, keys, &oidc.Config{
+		ClientID:             o.audience,
+		SkipClientIDCheck:    o.audience == "",
+		SupportedSigningAlgs: signingAlgorithms,
+		Now:                  o.now,
+	})
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.verifier == nil {
+		o.verifier = verifier
+	}
+	return o.verifier, nil
+}
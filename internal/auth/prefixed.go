@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"pr-service/internal/domain"
+)
+
+// Authenticator resolves a bearer token to its caller
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (domain.Principal, error)
+}
+
+// Prefixed authenticates tokens starting with a prefix with one authenticator
+// and all other tokens with another, so that tokens issued by the service
+// itself can be accepted next to those of an identity provider.
+type Prefixed struct {
+	prefix   string
+	prefixed Authenticator
+	fallback Authenticator
+}
+
+// NewPrefixed creates an authenticator routing tokens by prefix
+func NewPrefixed(prefix string, prefixed, fallback Authenticator) *Prefixed {
+	return &Prefixed{prefix: prefix, prefixed: prefixed, fallback: fallback}
+}
+
+// Authenticate verifies token with the authenticator its prefix selects
+func (p *Prefixed) Authenticate(ctx context.Context, token string) (domain.Principal, error) {
+	if strings.HasPrefix(token, p.prefix) {
+		return p.prefixed.Authenticate(ctx, token)
+	}
+	return p.fallback.Authenticate(ctx, token)
+}
@@ -0,0 +1,165 @@
+// Package breaker provides a circuit breaker that fails calls fast while a
+// dependency is down.
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by Allow while the circuit is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit
+type State int
+
+const (
+	// Closed lets every call through and counts consecutive failures
+	Closed State = iota
+	// Open rejects every call until the cooldown has passed
+	Open
+	// HalfOpen lets a single trial call through; its outcome closes or
+	// reopens the circuit
+	HalfOpen
+)
+
+// String returns the state name used in logs and metrics
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker opens after threshold consecutive failures and rejects calls for
+// cooldown, then lets one trial call decide whether to close again. Every call
+// let through by Allow must report its outcome with Record.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+	onChange func(State)
+}
+
+// New creates a breaker opening after threshold consecutive failures for
+// cooldown; threshold <= 0 or cooldown <= 0 returns nil, which allows
+// everything
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return NewWithClock(threshold, cooldown, time.Now)
+}
+
+// NewWithClock is New with a custom clock, for tests
+func NewWithClock(threshold int, cooldown time.Duration, now func() time.Time) *Breaker {
+	if threshold <= 0 || cooldown <= 0 {
+		return nil
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown, now: now}
+}
+
+// OnStateChange registers f to be called with the new state on every
+// transition. f runs under the breaker's lock and must not call back into it.
+func (b *Breaker) OnStateChange(f func(State)) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = f
+}
+
+// Allow reports whether a call may proceed, returning ErrOpen if not
+func (b *Breaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrOpen
+		}
+		b.setState(HalfOpen)
+		b.trial = true
+		return nil
+	case HalfOpen:
+		if b.trial {
+			return ErrOpen
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record reports the outcome of a call let through by Allow
+func (b *Breaker) Record(failed bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == HalfOpen {
+		b.trial = false
+		if failed {
+			b.open()
+		} else {
+			b.failures = 0
+			b.setState(Closed)
+		}
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == Closed && b.failures >= b.threshold {
+		b.open()
+	}
+}
+
+// State returns the current state of the circuit
+func (b *Breaker) State() State {
+	if b == nil {
+		return Closed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+func (b *Breaker) open() {
+	b.openedAt = b.now()
+	b.failures = 0
+	b.setState(Open)
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(state)
+	}
+}
@@ -0,0 +1,87 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewWithClock(3, time.Minute, func() time.Time { return now })
+
+	// A success in between resets the count
+	for _, failed := range []bool{true, true, false, true, true} {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("expected a closed circuit, got %v", err)
+		}
+		b.Record(failed)
+	}
+	if b.State() != Closed {
+		t.Fatalf("expected closed, got %s", b.State())
+	}
+
+	b.Record(true)
+	if b.State() != Open {
+		t.Fatalf("expected open after 3 consecutive failures, got %s", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected ErrOpen, got %v", err)
+	}
+}
+
+func TestBreakerTrialCallAfterCooldown(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewWithClock(1, time.Minute, func() time.Time { return now })
+	var transitions []State
+	b.OnStateChange(func(s State) { transitions = append(transitions, s) })
+
+	b.Record(true)
+	now = now.Add(time.Minute)
+	if b.State() != HalfOpen {
+		t.Fatalf("expected half-open after the cooldown, got %s", b.State())
+	}
+
+	// Only one trial call goes through; its failure reopens the circuit
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected the trial call to pass, got %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected calls during the trial to be rejected, got %v", err)
+	}
+	b.Record(true)
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected a failed trial to reopen the circuit, got %v", err)
+	}
+
+	// A successful trial closes it
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected the trial call to pass, got %v", err)
+	}
+	b.Record(false)
+	if b.State() != Closed {
+		t.Fatalf("expected closed after a successful trial, got %s", b.State())
+	}
+
+	want := []State{Open, HalfOpen, Open, HalfOpen, Closed}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("expected transitions %v, got %v", want, transitions)
+		}
+	}
+}
+
+func TestNilBreakerAllowsEverything(t *testing.T) {
+	b := New(0, time.Minute)
+	if b != nil {
+		t.Fatal("expected a zero threshold to disable the breaker")
+	}
+	b.Record(true)
+	if err := b.Allow(); err != nil || b.State() != Closed {
+		t.Fatalf("expected a nil breaker to stay closed, got %v %s", err, b.State())
+	}
+}
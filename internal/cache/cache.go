@@ -0,0 +1,128 @@
+// Package cache provides a small in-process TTL cache for expensive read queries.
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"pr-service/internal/metrics"
+)
+
+// maxEntries bounds memory use; expired entries are swept once it is reached
+const maxEntries = 1024
+
+type entry struct {
+	value   any
+	expires time.Time
+}
+
+// Cache stores values for a fixed TTL. Invalidate drops everything, including
+// values being loaded concurrently. A nil *Cache is valid and caches nothing.
+type Cache struct {
+	name       string
+	ttl        time.Duration
+	mu         sync.Mutex
+	entries    map[string]entry
+	generation uint64
+}
+
+// New creates a cache named name (used as a metrics label); ttl <= 0 returns nil, disabling caching
+func New(name string, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &Cache{
+		name:    name,
+		ttl:     ttl,
+		entries: make(map[string]entry),
+	}
+}
+
+// Key builds a cache key from parts. Times are truncated to the TTL so that
+// windows ending "now" share an entry between requests made within one TTL.
+func (c *Cache) Key(parts ...any) string {
+	if c == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteByte('|')
+		}
+		if t, ok := part.(time.Time); ok {
+			part = t.UTC().Truncate(c.ttl).Format(time.RFC3339Nano)
+		}
+		fmt.Fprint(&b, part)
+	}
+	return b.String()
+}
+
+// Invalidate drops every cached value
+func (c *Cache) Invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	clear(c.entries)
+}
+
+func (c *Cache) get(key string) (any, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return nil, c.generation, false
+	}
+	return e.value, c.generation, true
+}
+
+func (c *Cache) set(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// An invalidation while loading means value may already be stale
+	if generation != c.generation {
+		return
+	}
+
+	now := time.Now()
+	if len(c.entries) >= maxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxEntries {
+			clear(c.entries)
+		}
+	}
+	c.entries[key] = entry{value: value, expires: now.Add(c.ttl)}
+}
+
+// Load returns the value cached under key, calling load and caching its result on a miss.
+// Errors are not cached.
+func Load[T any](c *Cache, key string, load func() (T, error)) (T, error) {
+	if c == nil {
+		return load()
+	}
+
+	cached, generation, ok := c.get(key)
+	if ok {
+		metrics.CacheRequests.WithLabelValues(c.name, "hit").Inc()
+		return cached.(T), nil
+	}
+	metrics.CacheRequests.WithLabelValues(c.name, "miss").Inc()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.set(key, value, generation)
+	return value, nil
+}
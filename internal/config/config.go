@@ -10,36 +10,491 @@ import (
 
 // Config represents application configuration
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Logger   LoggerConfig   `yaml:"logger"`
+	// Storage selects where data is kept: "postgres", the default, or
+	// "memory" for demos and local development without a database
+	Storage      string             `yaml:"storage"`
+	Server       ServerConfig       `yaml:"server"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Logger       LoggerConfig       `yaml:"logger"`
+	Assignment   AssignmentConfig   `yaml:"assignment"`
+	Scheduler    SchedulerConfig    `yaml:"scheduler"`
+	Stats        StatsConfig        `yaml:"stats"`
+	Retention    RetentionConfig    `yaml:"retention"`
+	Report       ReportConfig       `yaml:"report"`
+	Integrations IntegrationsConfig `yaml:"integrations"`
+	Webhooks     WebhooksConfig     `yaml:"webhooks"`
+	Slack        SlackConfig        `yaml:"slack"`
+	Events       EventsConfig       `yaml:"events"`
+	Directory    DirectoryConfig    `yaml:"directory"`
+	Auth         AuthConfig         `yaml:"auth"`
+	Escalation   EscalationConfig   `yaml:"escalation"`
+	Tracing      TracingConfig      `yaml:"tracing"`
 }
 
-// ServerConfig represents HTTP server configuration
+// ServerConfig represents HTTP server configuration. A non-zero AdminPort
+// serves admin operations on a separate listener instead of Port.
 type ServerConfig struct {
 	Port         int           `yaml:"port"`
+	AdminPort    int           `yaml:"admin_port"`
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownDelay is how long /readyz reports not ready before the server
+	// stops accepting connections, giving load balancers time to notice
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	// ShutdownTimeout bounds the wait for requests, background jobs and
+	// database transactions to finish; zero uses 10 seconds
+	ShutdownTimeout time.Duration         `yaml:"shutdown_timeout"`
+	RateLimit       RateLimitConfig       `yaml:"rate_limit"`
+	CORS            CORSConfig            `yaml:"cors"`
+	Compression     CompressionConfig     `yaml:"compression"`
+	TLS             TLSConfig             `yaml:"tls"`
+	Limits          LimitsConfig          `yaml:"limits"`
+	Maintenance     MaintenanceConfig     `yaml:"maintenance"`
+	SecurityHeaders SecurityHeadersConfig `yaml:"security_headers"`
+	// AdminAllowedCIDRs restricts admin routes to clients in these networks,
+	// checked before authentication; empty allows every client
+	AdminAllowedCIDRs []string `yaml:"admin_allowed_cidrs"`
 }
 
+// SecurityHeadersConfig represents the security headers set on every
+// response. HSTS is sent when HSTSMaxAge is set; empty values use the
+// middleware defaults, and the docs page gets a policy allowing Swagger UI.
+// The headers are omitted when Enabled is false.
+type SecurityHeadersConfig struct {
+	Enabled                   bool          `yaml:"enabled"`
+	HSTSMaxAge                time.Duration `yaml:"hsts_max_age"`
+	HSTSIncludeSubdomains     bool          `yaml:"hsts_include_subdomains"`
+	FrameOptions              string        `yaml:"frame_options"`
+	ReferrerPolicy            string        `yaml:"referrer_policy"`
+	ContentSecurityPolicy     string        `yaml:"content_security_policy"`
+	DocsContentSecurityPolicy string        `yaml:"docs_content_security_policy"`
+}
+
+// MaintenanceConfig represents the maintenance mode the service starts in:
+// "off", "read_only" or "full". Admins change it at runtime through
+// POST /admin/maintenance; zero RetryAfter uses the default.
+type MaintenanceConfig struct {
+	Mode       string        `yaml:"mode"`
+	Message    string        `yaml:"message"`
+	RetryAfter time.Duration `yaml:"retry_after"`
+}
+
+// RateLimitConfig represents the per-client request quota: Requests per Window
+// for each authenticated caller, or each IP address without authentication.
+// Rate limiting is disabled when Requests is zero.
+type RateLimitConfig struct {
+	Requests int           `yaml:"requests"`
+	Window   time.Duration `yaml:"window"`
+}
+
+// CORSConfig represents the origins whose browser pages may call the API
+// directly. Origins are full origins, "*" for any origin, or wildcards such as
+// https://*.example.com; empty methods and headers use the defaults. CORS is
+// disabled when AllowedOrigins is empty.
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// CompressionConfig represents gzip compression of response bodies of at
+// least MinSize bytes. Zero Level and MinSize use the middleware defaults.
+type CompressionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Level   int  `yaml:"level"`
+	MinSize int  `yaml:"min_size"`
+}
+
+// TLSConfig represents TLS termination by the server itself, with a
+// certificate from CertFile and KeyFile or obtained by autocert. TLS is
+// disabled when neither is set. AdminClientCAFile makes the admin listener
+// require client certificates signed by one of its CAs.
+type TLSConfig struct {
+	CertFile          string         `yaml:"cert_file"`
+	KeyFile           string         `yaml:"key_file"`
+	Autocert          AutocertConfig `yaml:"autocert"`
+	AdminClientCAFile string         `yaml:"admin_client_ca_file"`
+}
+
+// AutocertConfig represents certificates obtained from Let's Encrypt for
+// Domains. Certificates are kept in CacheDir across restarts; a non-zero
+// HTTPPort answers HTTP-01 challenges there and redirects to HTTPS, otherwise
+// only TLS-ALPN-01 challenges on the server port are used.
+type AutocertConfig struct {
+	Domains  []string `yaml:"domains"`
+	CacheDir string   `yaml:"cache_dir"`
+	Email    string   `yaml:"email"`
+	HTTPPort int      `yaml:"http_port"`
+}
+
+// LimitsConfig represents the largest request body, in bytes, and the
+// longest time to serve a request. Routes overrides them for "METHOD /path"
+// patterns as the routes are registered, without the version prefix. Zero
+// values use the defaults; negative ones remove the limit.
+type LimitsConfig struct {
+	MaxBodySize int64                        `yaml:"max_body_size"`
+	Timeout     time.Duration                `yaml:"timeout"`
+	Routes      map[string]RouteLimitsConfig `yaml:"routes"`
+}
+
+// RouteLimitsConfig represents the limits of one route; zero values keep the
+// route's built-in limits
+type RouteLimitsConfig struct {
+	MaxBodySize int64         `yaml:"max_body_size"`
+	Timeout     time.Duration `yaml:"timeout"`
+}
+
+// DatabaseConfig represents database configuration. With AutoMigrate the
+// embedded migrations are applied on startup. ReplicaDSN, when set, is the
+// connection string of a read replica serving the reads of routes that don't
+// write. Transactions holds the options transactions run with.
 type DatabaseConfig struct {
-	Host            string        `yaml:"host"`
-	Port            string        `yaml:"port"`
-	User            string        `yaml:"user"`
-	Password        string        `yaml:"password"`
-	DBName          string        `yaml:"dbname"`
-	SSLMode         string        `yaml:"sslmode"`
-	MaxOpenConns    int           `yaml:"max_open_conns"`
-	MaxIdleConns    int           `yaml:"max_idle_conns"`
-	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	Host            string               `yaml:"host"`
+	Port            string               `yaml:"port"`
+	User            string               `yaml:"user"`
+	Password        string               `yaml:"password"`
+	DBName          string               `yaml:"dbname"`
+	SSLMode         string               `yaml:"sslmode"`
+	MaxOpenConns    int                  `yaml:"max_open_conns"`
+	MaxIdleConns    int                  `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration        `yaml:"conn_max_lifetime"`
+	AutoMigrate     bool                 `yaml:"auto_migrate"`
+	CircuitBreaker  CircuitBreakerConfig `yaml:"circuit_breaker"`
+	Retry           RetryConfig          `yaml:"retry"`
+	ConnectRetry    RetryConfig          `yaml:"connect_retry"`
+	ReplicaDSN      string               `yaml:"replica_dsn"`
+	Transactions    TransactionsConfig   `yaml:"transactions"`
+}
+
+// TransactionsConfig represents the options of transactions by the kind of
+// work they do: Default for requests, Bulk for imports and retention batches,
+// Stats for rollups and exports. Fields left empty in Bulk and Stats fall back
+// to Default.
+type TransactionsConfig struct {
+	Default TxConfig `yaml:"default"`
+	Bulk    TxConfig `yaml:"bulk"`
+	Stats   TxConfig `yaml:"stats"`
+}
+
+// TxConfig represents the options of a transaction: its Isolation level, like
+// "read committed", "repeatable read" or "serializable", and the
+// StatementTimeout its statements are canceled after. Empty fields leave the
+// database defaults.
+type TxConfig struct {
+	Isolation        string        `yaml:"isolation"`
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+}
+
+// CircuitBreakerConfig represents the database circuit breaker: after
+// FailureThreshold consecutive calls fail because the database is unreachable
+// or saturated, calls fail fast for Cooldown before one trial call is let
+// through. The breaker is disabled when FailureThreshold is zero.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"`
+	Cooldown         time.Duration `yaml:"cooldown"`
+}
+
+// RetryConfig represents how a failed database call is retried: up to
+// MaxAttempts attempts in total with a jittered backoff from BaseDelay up to
+// MaxDelay. Retry covers transactions failing with a serialization failure, a
+// deadlock or a dropped connection, ConnectRetry the first connection on
+// startup. Retries are disabled when MaxAttempts is at most one.
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseDelay   time.Duration `yaml:"base_delay"`
+	MaxDelay    time.Duration `yaml:"max_delay"`
 }
 
 // LoggerConfig represents logger configuration
 type LoggerConfig struct {
-	Level       string `yaml:"level"`
-	Encoding    string `yaml:"encoding"`
-	Development bool   `yaml:"development"`
+	Level       string           `yaml:"level"`
+	Encoding    string           `yaml:"encoding"`
+	Development bool             `yaml:"development"`
+	Requests    RequestLogConfig `yaml:"requests"`
+}
+
+// RequestLogConfig represents which requests are logged: those slower than
+// SlowThreshold and server errors always, others with SampleRatio (all when
+// zero). DebugRoutes are "METHOD /path" patterns of routes whose sanitized
+// bodies are logged too. Admins change all three at runtime through
+// POST /admin/logging.
+type RequestLogConfig struct {
+	SlowThreshold time.Duration `yaml:"slow_threshold"`
+	SampleRatio   float64       `yaml:"sample_ratio"`
+	DebugRoutes   []string      `yaml:"debug_routes"`
+}
+
+// AssignmentConfig represents reviewer assignment configuration.
+// A zero UserCacheTTL disables caching of the user and team member lookups.
+type AssignmentConfig struct {
+	IncludeSubTeams bool          `yaml:"include_sub_teams"`
+	DormantAfter    time.Duration `yaml:"dormant_after"`
+	ReviewCapacity  int           `yaml:"review_capacity"`
+	UserCacheTTL    time.Duration `yaml:"user_cache_ttl"`
+}
+
+// SchedulerConfig represents scheduled changes worker configuration
+type SchedulerConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size"`
+}
+
+// StatsConfig represents daily stats rollup and stats caching configuration.
+// A zero CacheTTL disables caching.
+type StatsConfig struct {
+	RollupInterval time.Duration `yaml:"rollup_interval"`
+	BackfillDays   int           `yaml:"backfill_days"`
+	CacheTTL       time.Duration `yaml:"cache_ttl"`
+}
+
+// RetentionConfig represents the retention of merged PRs: every Interval, PRs
+// merged more than MergedPRAge ago are moved to the archive tables in batches
+// of BatchSize, or deleted with Delete. Retention is disabled when MergedPRAge
+// is zero.
+type RetentionConfig struct {
+	MergedPRAge time.Duration `yaml:"merged_pr_age"`
+	Delete      bool          `yaml:"delete"`
+	Interval    time.Duration `yaml:"interval"`
+	BatchSize   int           `yaml:"batch_size"`
+}
+
+// ReportConfig represents scheduled report delivery configuration.
+// Delivery is disabled when WebhookURL is empty. ReviewSLA also sets the due
+// dates of review calendars and the escalation deadline.
+type ReportConfig struct {
+	Schedule   string        `yaml:"schedule"`
+	WebhookURL string        `yaml:"webhook_url"`
+	Timeout    time.Duration `yaml:"timeout"`
+	ReviewSLA  time.Duration `yaml:"review_sla"`
+}
+
+// IntegrationsConfig represents inbound integrations with code hosting services
+type IntegrationsConfig struct {
+	GitHub    GitHubConfig         `yaml:"github"`
+	GitLab    GitLabConfig         `yaml:"gitlab"`
+	Bitbucket BitbucketConfig      `yaml:"bitbucket"`
+	Generic   GenericWebhookConfig `yaml:"generic"`
+	Jira      JiraConfig           `yaml:"jira"`
+}
+
+// GitHubConfig represents the GitHub webhook receiver and review request write-back
+// configuration. The receiver is disabled when WebhookSecret is empty and the
+// write-back when APIToken is empty. Users maps GitHub logins to user IDs;
+// unmapped logins are used as user IDs. A non-empty WriteBackOwners limits the
+// write-back to pull requests of those owners.
+type GitHubConfig struct {
+	WebhookSecret   string            `yaml:"webhook_secret"`
+	Users           map[string]string `yaml:"users"`
+	APIToken        string            `yaml:"api_token"`
+	APIURL          string            `yaml:"api_url"`
+	WriteBackOwners []string          `yaml:"write_back_owners"`
+	Timeout         time.Duration     `yaml:"timeout"`
+}
+
+// GitLabConfig represents the GitLab webhook receiver configuration.
+// The receiver is disabled when WebhookToken is empty. Users maps GitLab
+// usernames to user IDs; unmapped usernames are used as user IDs.
+type GitLabConfig struct {
+	WebhookToken string            `yaml:"webhook_token"`
+	Users        map[string]string `yaml:"users"`
+}
+
+// BitbucketConfig represents the Bitbucket Cloud webhook receiver configuration,
+// keyed by workspace slug. The receiver is disabled when no workspace is configured.
+type BitbucketConfig struct {
+	Workspaces map[string]BitbucketWorkspaceConfig `yaml:"workspaces"`
+}
+
+// BitbucketWorkspaceConfig represents webhook settings of one Bitbucket workspace.
+// Users maps Bitbucket nicknames to user IDs; unmapped nicknames are used as user IDs.
+type BitbucketWorkspaceConfig struct {
+	WebhookSecret string            `yaml:"webhook_secret"`
+	Users         map[string]string `yaml:"users"`
+}
+
+// GenericWebhookConfig represents adapters mapping webhooks of in-house systems
+// onto the PR lifecycle, keyed by the adapter name in the route. The receiver is
+// disabled when no adapter is configured.
+type GenericWebhookConfig struct {
+	Adapters map[string]GenericAdapterConfig `yaml:"adapters"`
+}
+
+// GenericAdapterConfig represents one generic webhook adapter. Requests are
+// authenticated with Token, Secret (HMAC-SHA256 signature) or both. Action is
+// a JSONPath to the value matched against CreateOn and MergeOn; Fields maps PR
+// fields to JSONPaths or "{$path}" templates. Users maps author values to user
+// IDs; unmapped values are used as user IDs. Empty headers use the defaults.
+type GenericAdapterConfig struct {
+	Token           string            `yaml:"token"`
+	TokenHeader     string            `yaml:"token_header"`
+	Secret          string            `yaml:"secret"`
+	SignatureHeader string            `yaml:"signature_header"`
+	Action          string            `yaml:"action"`
+	CreateOn        []string          `yaml:"create_on"`
+	MergeOn         []string          `yaml:"merge_on"`
+	Fields          map[string]string `yaml:"fields"`
+	Users           map[string]string `yaml:"users"`
+}
+
+// JiraConfig represents the Jira instance PR ticket keys are validated against and
+// commented on. The integration is disabled when URL is empty. Email and APIToken
+// authenticate to Jira Cloud; with an empty Email, APIToken is a personal access token.
+type JiraConfig struct {
+	URL      string        `yaml:"url"`
+	Email    string        `yaml:"email"`
+	APIToken string        `yaml:"api_token"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+// WebhooksConfig represents outbound webhook delivery configuration.
+// Zero values fall back to the service and worker defaults. Timeout also bounds
+// posts to team chat channels.
+type WebhooksConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size"`
+	Timeout      time.Duration `yaml:"timeout"`
+	MaxAttempts  int           `yaml:"max_attempts"`
+	RetryBase    time.Duration `yaml:"retry_base"`
+	RetryMax     time.Duration `yaml:"retry_max"`
+}
+
+// SlackConfig represents Slack direct message configuration. Notifications are
+// disabled when BotToken is empty. Users maps user IDs to Slack member IDs;
+// unmapped users are not notified. A non-empty DigestSchedule (cron, UTC)
+// replaces the per-event messages with a periodic digest of pending reviews.
+type SlackConfig struct {
+	BotToken           string            `yaml:"bot_token"`
+	APIURL             string            `yaml:"api_url"`
+	Users              map[string]string `yaml:"users"`
+	StaleAfter         time.Duration     `yaml:"stale_after"`
+	StaleCheckInterval time.Duration     `yaml:"stale_check_interval"`
+	DigestSchedule     string            `yaml:"digest_schedule"`
+	Timeout            time.Duration     `yaml:"timeout"`
+}
+
+// EventsConfig represents relaying domain events to a message broker through
+// the outbox. Transport selects the broker ("kafka" or "nats"); when it is empty
+// events are only kept in the outbox for replay. Zero values fall back to the worker defaults.
+type EventsConfig struct {
+	Transport    string        `yaml:"transport"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size"`
+	Kafka        KafkaConfig   `yaml:"kafka"`
+	NATS         NATSConfig    `yaml:"nats"`
+}
+
+// KafkaConfig represents the Kafka event transport. An empty Topic uses the producer default.
+type KafkaConfig struct {
+	Brokers  []string      `yaml:"brokers"`
+	Topic    string        `yaml:"topic"`
+	ClientID string        `yaml:"client_id"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+// NATSConfig represents the NATS event transport. Events are published to
+// "<Subject>.<event type>"; with JetStream each one waits for the stream's ack.
+// Empty values use the publisher defaults.
+type NATSConfig struct {
+	URL       string        `yaml:"url"`
+	Subject   string        `yaml:"subject"`
+	Name      string        `yaml:"name"`
+	JetStream bool          `yaml:"jetstream"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// DirectoryConfig represents syncing teams and users from a directory service
+// every SyncInterval. Syncing is disabled when LDAP.URL is empty.
+type DirectoryConfig struct {
+	SyncInterval time.Duration `yaml:"sync_interval"`
+	LDAP         LDAPConfig    `yaml:"ldap"`
+}
+
+// LDAPConfig represents the LDAP directory teams are read from. Each group matching
+// GroupFilter under GroupBaseDN is synced as a team; its MemberAttribute values
+// reference users matching UserFilter under UserBaseDN by DN or user ID.
+// Empty filters and attributes use an OpenLDAP-style schema.
+type LDAPConfig struct {
+	URL                string        `yaml:"url"`
+	BindDN             string        `yaml:"bind_dn"`
+	BindPassword       string        `yaml:"bind_password"`
+	UserBaseDN         string        `yaml:"user_base_dn"`
+	UserFilter         string        `yaml:"user_filter"`
+	UserIDAttribute    string        `yaml:"user_id_attribute"`
+	UsernameAttribute  string        `yaml:"username_attribute"`
+	GroupBaseDN        string        `yaml:"group_base_dn"`
+	GroupFilter        string        `yaml:"group_filter"`
+	GroupNameAttribute string        `yaml:"group_name_attribute"`
+	MemberAttribute    string        `yaml:"member_attribute"`
+	Timeout            time.Duration `yaml:"timeout"`
+}
+
+// AuthConfig represents authentication of API requests. Requests are not
+// authenticated when OIDC.Issuer is empty.
+type AuthConfig struct {
+	OIDC OIDCConfig `yaml:"oidc"`
+}
+
+// OIDCConfig represents the OpenID Connect provider bearer tokens are issued by.
+// A non-empty Audience must be listed in the token's aud claim. UserClaim names
+// the claim identifying the caller ("sub" when empty); Users maps its values to
+// user IDs, unmapped values are used as user IDs. RolesClaim names the claim
+// listing the caller's roles (admin, lead, member; "roles" when empty).
+// OrgClaim names the claim holding the caller's organization; when empty every
+// caller belongs to the default organization. It needs the postgres storage.
+type OIDCConfig struct {
+	Issuer     string            `yaml:"issuer"`
+	Audience   string            `yaml:"audience"`
+	UserClaim  string            `yaml:"user_claim"`
+	RolesClaim string            `yaml:"roles_claim"`
+	OrgClaim   string            `yaml:"org_claim"`
+	Users      map[string]string `yaml:"users"`
+	Timeout    time.Duration     `yaml:"timeout"`
+}
+
+// EscalationConfig represents escalating reviews that exceeded the first-review SLA
+// (Report.ReviewSLA) by more than Margin to an on-call tool. Provider selects the
+// tool ("pagerduty" or "opsgenie"); escalation is disabled when it is empty.
+// Zero values fall back to the service and worker defaults.
+type EscalationConfig struct {
+	Provider      string          `yaml:"provider"`
+	Margin        time.Duration   `yaml:"margin"`
+	CheckInterval time.Duration   `yaml:"check_interval"`
+	Timeout       time.Duration   `yaml:"timeout"`
+	PagerDuty     PagerDutyConfig `yaml:"pagerduty"`
+	Opsgenie      OpsgenieConfig  `yaml:"opsgenie"`
+}
+
+// PagerDutyConfig represents the PagerDuty service escalations trigger alerts in.
+// RoutingKey is the Events API v2 integration key; empty URL and Severity use the client defaults.
+type PagerDutyConfig struct {
+	RoutingKey string `yaml:"routing_key"`
+	URL        string `yaml:"url"`
+	Severity   string `yaml:"severity"`
+}
+
+// OpsgenieConfig represents the Opsgenie API integration escalations create alerts with.
+// Empty APIURL and Priority use the client defaults.
+type OpsgenieConfig struct {
+	APIKey   string `yaml:"api_key"`
+	APIURL   string `yaml:"api_url"`
+	Priority string `yaml:"priority"`
+}
+
+// TracingConfig represents exporting OpenTelemetry traces of requests, service
+// operations and database queries to an OTLP/HTTP collector. Tracing is disabled
+// when Endpoint is empty. Headers are added to export requests; SampleRatio is
+// the share of new traces recorded (all when zero), while requests with a W3C
+// traceparent follow the caller's decision. An empty ServiceName uses "pr-service".
+type TracingConfig struct {
+	Endpoint    string            `yaml:"endpoint"`
+	Headers     map[string]string `yaml:"headers"`
+	ServiceName string            `yaml:"service_name"`
+	SampleRatio float64           `yaml:"sample_ratio"`
+	Timeout     time.Duration     `yaml:"timeout"`
 }
 
 // LoadConfig loads configuration from file
@@ -0,0 +1,42 @@
+// Package cron parses standard five-field cron expressions
+// (minute hour day-of-month month day-of-week) and computes their next run.
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule is a parsed cron expression evaluated in UTC
+type Schedule struct {
+	spec cron.Schedule
+}
+
+// Parse parses a five-field cron expression with cron.ParseStandard. Fields
+// accept "*", numbers, names (MON, JAN), ranges (1-5), lists (1,3,5) and steps
+// (*/15, 0-30/5); descriptors such as @weekly are accepted too. Day of week 0
+// is Sunday. Expressions are evaluated in UTC unless they start with CRON_TZ=.
+func Parse(spec string) (*Schedule, error) {
+	zoned := spec
+	if !strings.HasPrefix(spec, "CRON_TZ=") && !strings.HasPrefix(spec, "TZ=") {
+		zoned = "CRON_TZ=UTC " + spec
+	}
+	schedule, err := cron.ParseStandard(zoned)
+	if err != nil {
+		return nil, fmt.Errorf("cron expression %q: %w", spec, err)
+	}
+	return &Schedule{spec: schedule}, nil
+}
+
+// Next returns the first matching minute strictly after t, in UTC.
+// It returns the zero time if nothing matches within five years (e.g. "0 0 30 2 *").
+func (s *Schedule) Next(t time.Time) time.Time {
+	next := s.spec.Next(t)
+	if next.IsZero() {
+		return next
+	}
+	return next.UTC()
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Connect creates a pool and waits until the database answers a ping,
+// retrying failed pings according to policy. Startup survives a database that
+// is still starting, as it often is when both are started together. Every
+// connection the pool hands out runs as the organization of the context it is
+// acquired with.
+func Connect(ctx context.Context, cfg *pgxpool.Config, policy RetryPolicy, logger *zap.Logger) (*pgxpool.Pool, error) {
+	cfg.PrepareConn = prepareOrg
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pool: %w", err)
+	}
+	if err := waitForDatabase(ctx, pool.Ping, policy, logger); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// waitForDatabase calls ping until it succeeds, policy runs out of attempts or
+// ctx is done
+func waitForDatabase(ctx context.Context, ping func(ctx context.Context) error, policy RetryPolicy, logger *zap.Logger) error {
+	attempts := max(policy.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx)
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("Database is available", zap.Int("attempt", attempt))
+			}
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("database unavailable after %d attempts: %w", attempt, err)
+		}
+
+		delay := policy.backoff(attempt)
+		logger.Warn("Database is not available yet, retrying",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("stopped waiting for the database: %w", err)
+		case <-timer.C:
+		}
+	}
+}
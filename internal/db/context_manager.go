@@ -2,11 +2,27 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"time"
+
+	"pr-service/internal/breaker"
+	"pr-service/internal/domain"
+	"pr-service/internal/lifecycle"
+	"pr-service/internal/metrics"
+	"pr-service/internal/tracing"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -17,15 +33,76 @@ const (
 )
 
 type ContextManager struct {
-	pool   *pgxpool.Pool
-	logger *zap.Logger
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool
+	logger  *zap.Logger
+	breaker *breaker.Breaker
+	retry   RetryPolicy
+	// profiles holds the options of transactions by profile
+	profiles map[TxProfile]TxOptions
+	// transactions tracks open transactions so shutdown can wait for them
+	// before closing the pool
+	transactions lifecycle.Tracker
+}
+
+// Option configures optional ContextManager behaviour
+type Option func(*ContextManager)
+
+// WithCircuitBreaker guards every statement and transaction with b, so calls
+// fail fast with domain.ErrUnavailable while the database is down or
+// saturated instead of waiting for the pool to time out
+func WithCircuitBreaker(b *breaker.Breaker) Option {
+	return func(cm *ContextManager) {
+		cm.breaker = b
+	}
 }
 
-func NewContextManager(pool *pgxpool.Pool, logger *zap.Logger) *ContextManager {
-	return &ContextManager{
+// RetryPolicy decides how transactions failing with a transient error are
+// retried: up to MaxAttempts attempts in total, waiting a random delay of up
+// to BaseDelay doubled after each attempt and capped at MaxDelay. Transactions
+// are not retried when MaxAttempts is at most one.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// backoff returns the delay before the attempt after the given number of
+// failed attempts, with full jitter so retries of transactions that
+// conflicted with each other don't collide again
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 {
+		delay = min(delay, p.MaxDelay)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay + 1)
+}
+
+// WithRetry reruns transactions started by Do that fail with a serialization
+// failure, a deadlock or a dropped connection, according to policy. f must
+// then be safe to run again: its database work is rolled back between
+// attempts, but anything else it does is not.
+func WithRetry(policy RetryPolicy) Option {
+	return func(cm *ContextManager) {
+		cm.retry = policy
+	}
+}
+
+func NewContextManager(pool *pgxpool.Pool, logger *zap.Logger, opts ...Option) *ContextManager {
+	cm := &ContextManager{
 		pool:   pool,
 		logger: logger,
 	}
+	for _, opt := range opts {
+		opt(cm)
+	}
+	return cm
 }
 
 type Engine interface {
@@ -39,24 +116,42 @@ type Transactioner interface {
 
 type EngineFactory interface {
 	Get(ctx context.Context) Engine
+	GetReadOnly(ctx context.Context) Engine
 }
 
 func (cm *ContextManager) putEngineInContext(ctx context.Context, engine Engine) context.Context {
 	return context.WithValue(ctx, EngineKey, engine)
 }
 
-func (cm *ContextManager) begin(ctx context.Context) (context.Context, error) {
+// begin starts a transaction with the options of ctx unless ctx already
+// carries one, and reports whether it did
+func (cm *ContextManager) begin(ctx context.Context) (context.Context, bool, error) {
 	_, ok := ctx.Value(EngineKey).(pgx.Tx)
 	if ok {
-		return ctx, nil
+		return ctx, false, nil
 	}
 
-	tx, err := cm.pool.Begin(ctx)
+	if err := cm.allow(); err != nil {
+		return ctx, false, err
+	}
+	opts := cm.txOptions(ctx)
+	tx, err := cm.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: opts.Isolation})
+	cm.breaker.Record(isUnavailable(err))
 	if err != nil {
-		return ctx, err
+		return ctx, false, err
+	}
+	if opts.StatementTimeout > 0 {
+		// SET LOCAL takes no parameters; set_config with is_local does the same
+		_, err = tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", fmt.Sprintf("%dms", opts.StatementTimeout.Milliseconds()))
+		if err != nil {
+			if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil {
+				cm.logger.Error("failed to rollback transaction", zap.Error(rbErr))
+			}
+			return ctx, false, fmt.Errorf("failed to set statement timeout: %w", err)
+		}
 	}
 
-	return cm.putEngineInContext(ctx, tx), nil
+	return cm.putEngineInContext(ctx, tx), true, nil
 }
 
 func (cm *ContextManager) commit(ctx context.Context) error {
@@ -77,19 +172,91 @@ func (cm *ContextManager) rollback(ctx context.Context) error {
 	return tx.Rollback(ctx)
 }
 
-func (cm *ContextManager) Do(ctx context.Context, f func(ctx context.Context) error) (err error) {
-	txCtx, err := cm.begin(ctx)
+// Do runs f in a transaction, committing when f succeeds. Calls nested in the
+// transaction of an outer Do run f in a savepoint of it, see nested; the
+// outer call alone commits or rolls back and, with WithRetry, retries the
+// whole transaction on transient errors.
+func (cm *ContextManager) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(EngineKey).(pgx.Tx); ok {
+		return cm.nested(ctx, tx, f)
+	}
+	return cm.withRetry(ctx, func() (bool, error) {
+		return cm.attempt(ctx, f)
+	})
+}
+
+// nested runs f in a savepoint of tx, the transaction of an outer Do. When f
+// fails only its own work is rolled back and its error returned, so the outer
+// call may handle it and go on with the transaction instead of finding it
+// aborted. The savepoint is released when f succeeds; its work is committed
+// with the outer transaction.
+func (cm *ContextManager) nested(ctx context.Context, tx pgx.Tx, f func(ctx context.Context) error) error {
+	savepoint, err := tx.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	if err := f(cm.putEngineInContext(ctx, savepoint)); err != nil {
+		if rbErr := savepoint.Rollback(context.WithoutCancel(ctx)); rbErr != nil {
+			cm.logger.Error("failed to roll back to savepoint", zap.Error(rbErr))
+		}
 		return err
 	}
+	if err := savepoint.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// withRetry runs attempt until it succeeds, fails with an error that is not
+// transient or runs out of attempts. attempt reports whether it failed in
+// the commit.
+func (cm *ContextManager) withRetry(ctx context.Context, attempt func() (bool, error)) error {
+	for attempts := 1; ; attempts++ {
+		committing, err := attempt()
+		if err == nil || attempts >= cm.retry.MaxAttempts || !isRetryable(err, committing) {
+			return err
+		}
+
+		delay := cm.retry.backoff(attempts)
+		cm.logger.Warn("Retrying transaction after transient error",
+			zap.Int("attempt", attempts),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		metrics.DBTransactionRetries.Inc()
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt runs f in a new transaction once and reports whether its error
+// came from the commit
+func (cm *ContextManager) attempt(ctx context.Context, f func(ctx context.Context) error) (committing bool, err error) {
+	txCtx, _, err := cm.begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer cm.transactions.Start()()
 
+	txCtx, span := tracing.Start(txCtx, "db.transaction", trace.WithSpanKind(trace.SpanKindClient))
+	start := time.Now()
 	detCtx := context.WithoutCancel(txCtx)
 	defer func() {
+		outcome := "commit"
 		if p := recover(); p != nil {
+			metrics.TransactionDuration.WithLabelValues("rollback").Observe(time.Since(start).Seconds())
 			cm.logger.Error("panic occurred in transaction", zap.Any("panic", p))
 			if rbErr := cm.rollback(detCtx); rbErr != nil {
 				cm.logger.Error("failed to rollback transaction after panic", zap.Error(rbErr))
 			}
+			span.SetStatus(codes.Error, fmt.Sprint("panic: ", p))
+			span.End()
 			panic(p)
 		}
 		if err != nil {
@@ -98,22 +265,191 @@ func (cm *ContextManager) Do(ctx context.Context, f func(ctx context.Context) er
 			if innerErr != nil {
 				cm.logger.Error("failed to rollback transaction", zap.Error(innerErr))
 			}
+			outcome = "rollback"
 		} else {
+			committing = true
 			err = cm.commit(txCtx)
 			if err != nil {
 				cm.logger.Error("failed to commit transaction", zap.Error(err))
+				outcome = "rollback"
 			}
 		}
+		metrics.TransactionDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+		span.SetAttributes(attribute.String("db.transaction.outcome", outcome))
+		tracing.RecordError(span, err)
+		span.End()
 	}()
 
 	err = f(txCtx)
 
-	return err
+	return false, err
 }
 
 func (cm *ContextManager) Get(ctx context.Context) Engine {
-	if engine, ok := ctx.Value(EngineKey).(Engine); ok {
+	engine, ok := ctx.Value(EngineKey).(Engine)
+	if !ok {
+		engine = cm.pool
+	}
+	if cm.breaker == nil {
 		return engine
 	}
-	return cm.pool
+	return guardedEngine{Engine: engine, cm: cm}
+}
+
+// allow asks the circuit breaker whether a call may reach the database
+func (cm *ContextManager) allow() error {
+	if err := cm.breaker.Allow(); err != nil {
+		metrics.DBCircuitRejections.Inc()
+		return fmt.Errorf("database circuit breaker is open: %w", domain.ErrUnavailable)
+	}
+	return nil
+}
+
+// guardedEngine runs statements through the circuit breaker and reports
+// whether the database answered them
+type guardedEngine struct {
+	Engine
+	cm *ContextManager
+}
+
+func (e guardedEngine) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if err := e.cm.allow(); err != nil {
+		return nil, err
+	}
+	rows, err := e.Engine.Query(ctx, sql, args...)
+	e.cm.breaker.Record(isUnavailable(err))
+	return rows, err
+}
+
+func (e guardedEngine) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if err := e.cm.allow(); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	tag, err := e.Engine.Exec(ctx, sql, args...)
+	e.cm.breaker.Record(isUnavailable(err))
+	return tag, err
+}
+
+// isUnavailable reports whether err means the database could not serve the
+// call: it is unreachable, refuses connections, ran out of resources or did
+// not answer in time. Errors of the statement itself, such as constraint
+// violations, and calls canceled by the client do not count.
+func isUnavailable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Classes 08 (connection exception), 53 (insufficient resources) and
+		// 57P (operator intervention: shutdown, cannot connect now)
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") || strings.HasPrefix(pgErr.Code, "57P")
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) || errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isRetryable reports whether a transaction that failed with err may be run
+// again: it lost a serialization conflict or a deadlock, or the connection
+// dropped before the commit. A connection lost while committing leaves it
+// unknown whether the transaction was applied, so it is not retried, and
+// neither is a call rejected by the open circuit breaker.
+func isRetryable(err error, committing bool) bool {
+	if err == nil || errors.Is(err, domain.ErrUnavailable) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// 40001 serialization_failure and 40P01 deadlock_detected are safe
+		// to retry even from the commit, which the database rejected
+		if pgErr.Code == "40001" || pgErr.Code == "40P01" {
+			return true
+		}
+	}
+	if committing || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return isUnavailable(err)
+}
+
+// PoolStats is a snapshot of a connection pool. The counters and wait times
+// accumulate since the pool was opened. EmptyAcquires counts the acquires
+// that found no idle connection and waited for one, EmptyAcquireWait the time
+// they waited.
+type PoolStats struct {
+	AcquiredConns     int32
+	IdleConns         int32
+	ConstructingConns int32
+	TotalConns        int32
+	MaxConns          int32
+	Acquires          int64
+	EmptyAcquires     int64
+	CanceledAcquires  int64
+	AcquireWait       time.Duration
+	EmptyAcquireWait  time.Duration
+	NewConns          int64
+	LifetimeDestroys  int64
+	IdleDestroys      int64
+}
+
+// Saturation is the share of the pool's capacity in use, from 0 to 1
+func (s PoolStats) Saturation() float64 {
+	if s.MaxConns <= 0 {
+		return 0
+	}
+	return float64(s.AcquiredConns) / float64(s.MaxConns)
+}
+
+// Ping checks that a connection to the database can be acquired and used
+func (cm *ContextManager) Ping(ctx context.Context) error {
+	return cm.pool.Ping(ctx)
+}
+
+// PoolStats returns the current state of the connection pool
+func (cm *ContextManager) PoolStats() PoolStats {
+	return poolStats(cm.pool)
+}
+
+// ReplicaPoolStats returns the current state of the read replica pool, and
+// false when no replica is configured
+func (cm *ContextManager) ReplicaPoolStats() (PoolStats, bool) {
+	if cm.replica == nil {
+		return PoolStats{}, false
+	}
+	return poolStats(cm.replica), true
+}
+
+func poolStats(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		AcquiredConns:     stat.AcquiredConns(),
+		IdleConns:         stat.IdleConns(),
+		ConstructingConns: stat.ConstructingConns(),
+		TotalConns:        stat.TotalConns(),
+		MaxConns:          stat.MaxConns(),
+		Acquires:          stat.AcquireCount(),
+		EmptyAcquires:     stat.EmptyAcquireCount(),
+		CanceledAcquires:  stat.CanceledAcquireCount(),
+		AcquireWait:       stat.AcquireDuration(),
+		EmptyAcquireWait:  stat.EmptyAcquireWaitTime(),
+		NewConns:          stat.NewConnsCount(),
+		LifetimeDestroys:  stat.MaxLifetimeDestroyCount(),
+		IdleDestroys:      stat.MaxIdleDestroyCount(),
+	}
+}
+
+// ActiveTransactions returns the number of transactions started by Do that
+// have not finished yet
+func (cm *ContextManager) ActiveTransactions() int {
+	return cm.transactions.Active()
+}
+
+// WaitForTransactions blocks until no transaction started by Do is open or
+// ctx is done. Shutdown calls it before closing the pool.
+func (cm *ContextManager) WaitForTransactions(ctx context.Context) error {
+	return cm.transactions.Wait(ctx)
 }
@@ -0,0 +1,343 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"pr-service/internal/breaker"
+	"pr-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// fakeEngine answers every statement with err
+type fakeEngine struct {
+	err   error
+	calls int
+}
+
+func (e *fakeEngine) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	e.calls++
+	return nil, e.err
+}
+
+func (e *fakeEngine) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	e.calls++
+	return pgconn.CommandTag{}, e.err
+}
+
+func TestIsUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"success", nil, false},
+		{"no rows", pgx.ErrNoRows, false},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"canceled by client", fmt.Errorf("failed to get team: %w", context.Canceled), false},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, true},
+		{"shutting down", &pgconn.PgError{Code: "57P01"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"pool acquire timed out", fmt.Errorf("failed to get team: %w", context.DeadlineExceeded), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnavailable(tt.err); got != tt.want {
+				t.Fatalf("isUnavailable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardedEngineFailsFastWhenOpen(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := breaker.NewWithClock(2, time.Minute, func() time.Time { return now })
+	cm := NewContextManager(nil, zap.NewNop(), WithCircuitBreaker(b))
+	engine := &fakeEngine{err: &pgconn.PgError{Code: "23505"}}
+	ctx := cm.putEngineInContext(context.Background(), engine)
+
+	// Statement errors leave the circuit closed
+	for i := 0; i < 3; i++ {
+		if _, err := cm.Get(ctx).Exec(ctx, "INSERT"); errors.Is(err, domain.ErrUnavailable) {
+			t.Fatalf("expected the statement error, got %v", err)
+		}
+	}
+
+	engine.err = context.DeadlineExceeded
+	for i := 0; i < 2; i++ {
+		_, _ = cm.Get(ctx).Query(ctx, "SELECT")
+	}
+	calls := engine.calls
+	if _, err := cm.Get(ctx).Query(ctx, "SELECT"); !errors.Is(err, domain.ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable once the circuit opened, got %v", err)
+	}
+	if engine.calls != calls {
+		t.Fatal("expected an open circuit not to reach the database")
+	}
+
+	// After the cooldown a successful trial closes the circuit
+	now = now.Add(time.Minute)
+	engine.err = nil
+	if _, err := cm.Get(ctx).Exec(ctx, "UPDATE"); err != nil {
+		t.Fatalf("expected the trial call to succeed, got %v", err)
+	}
+	if b.State() != breaker.Closed {
+		t.Fatalf("expected a closed circuit, got %s", b.State())
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		committing bool
+		want       bool
+	}{
+		{"success", nil, false, false},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false, false},
+		{"serialization failure", fmt.Errorf("failed to update PR: %w", &pgconn.PgError{Code: "40001"}), false, true},
+		{"serialization failure on commit", &pgconn.PgError{Code: "40001"}, true, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, false, true},
+		{"connection dropped", &pgconn.PgError{Code: "08006"}, false, true},
+		{"connection dropped on commit", &pgconn.PgError{Code: "08006"}, true, false},
+		{"timed out", context.DeadlineExceeded, false, false},
+		{"circuit open", fmt.Errorf("database circuit breaker is open: %w", domain.ErrUnavailable), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err, tt.committing); got != tt.want {
+				t.Fatalf("isRetryable(%v, %v) = %v, want %v", tt.err, tt.committing, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Millisecond, MaxDelay: 30 * time.Millisecond}
+	for attempts, limit := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 4: 30 * time.Millisecond} {
+		for i := 0; i < 100; i++ {
+			if delay := policy.backoff(attempts); delay < 0 || delay > limit {
+				t.Fatalf("backoff(%d) = %s, want at most %s", attempts, delay, limit)
+			}
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	serialization := &pgconn.PgError{Code: "40001"}
+	cm := NewContextManager(nil, zap.NewNop(), WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	// A transient error is retried until the transaction succeeds
+	calls := 0
+	err := cm.withRetry(context.Background(), func() (bool, error) {
+		calls++
+		if calls < 3 {
+			return false, serialization
+		}
+		return false, nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on the third attempt, got %v after %d", err, calls)
+	}
+
+	// Attempts are capped
+	calls = 0
+	err = cm.withRetry(context.Background(), func() (bool, error) {
+		calls++
+		return false, serialization
+	})
+	if !errors.Is(err, serialization) || calls != 3 {
+		t.Fatalf("expected the error after 3 attempts, got %v after %d", err, calls)
+	}
+
+	// Other errors are returned at once
+	calls = 0
+	conflict := &pgconn.PgError{Code: "23505"}
+	err = cm.withRetry(context.Background(), func() (bool, error) {
+		calls++
+		return false, conflict
+	})
+	if !errors.Is(err, conflict) || calls != 1 {
+		t.Fatalf("expected one attempt for a statement error, got %d", calls)
+	}
+
+	// Without a policy nothing is retried
+	calls = 0
+	_ = NewContextManager(nil, zap.NewNop()).withRetry(context.Background(), func() (bool, error) {
+		calls++
+		return false, serialization
+	})
+	if calls != 1 {
+		t.Fatalf("expected one attempt without a retry policy, got %d", calls)
+	}
+}
+
+func TestWaitForDatabase(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond}
+	refused := errors.New("connection refused")
+
+	calls := 0
+	err := waitForDatabase(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return refused
+		}
+		return nil
+	}, policy, zap.NewNop())
+	if err != nil || calls != 3 {
+		t.Fatalf("expected the database on the third ping, got %v after %d", err, calls)
+	}
+
+	calls = 0
+	err = waitForDatabase(context.Background(), func(context.Context) error {
+		calls++
+		return refused
+	}, policy, zap.NewNop())
+	if !errors.Is(err, refused) || calls != 4 {
+		t.Fatalf("expected to give up after 4 pings, got %v after %d", err, calls)
+	}
+
+	// A canceled startup stops waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = waitForDatabase(ctx, func(context.Context) error {
+		calls++
+		return refused
+	}, RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour}, zap.NewNop())
+	if err == nil || calls != 1 {
+		t.Fatalf("expected to stop after the first ping, got %v after %d", err, calls)
+	}
+}
+
+// fakeTx stands in for an open transaction
+type fakeTx struct {
+	pgx.Tx
+}
+
+func TestGetReadOnly(t *testing.T) {
+	ctx := context.Background()
+	primary, err := pgxpool.New(ctx, "postgres://primary/pr_service")
+	if err != nil {
+		t.Fatalf("primary pool: %v", err)
+	}
+	defer primary.Close()
+	replica, err := pgxpool.New(ctx, "postgres://replica/pr_service")
+	if err != nil {
+		t.Fatalf("replica pool: %v", err)
+	}
+	defer replica.Close()
+
+	cm := NewContextManager(primary, zap.NewNop(), WithReplica(replica))
+	if got := cm.GetReadOnly(ctx); got != primary {
+		t.Error("expected reads without AllowReplica to use the primary")
+	}
+	if got := cm.GetReadOnly(AllowReplica(ctx)); got != replica {
+		t.Error("expected reads with AllowReplica to use the replica")
+	}
+	tx := fakeTx{}
+	if got := cm.GetReadOnly(cm.putEngineInContext(AllowReplica(ctx), tx)); got != tx {
+		t.Error("expected reads in a transaction to use it")
+	}
+	if got := NewContextManager(primary, zap.NewNop()).GetReadOnly(AllowReplica(ctx)); got != primary {
+		t.Error("expected reads to use the primary without a replica")
+	}
+}
+
+func TestTxOptions(t *testing.T) {
+	cm := NewContextManager(nil, zap.NewNop(), WithTxProfiles(map[TxProfile]TxOptions{
+		ProfileDefault: {Isolation: pgx.ReadCommitted, StatementTimeout: 5 * time.Second},
+		ProfileStats:   {Isolation: pgx.RepeatableRead},
+	}))
+	ctx := context.Background()
+
+	if got := cm.txOptions(ctx); got != (TxOptions{Isolation: pgx.ReadCommitted, StatementTimeout: 5 * time.Second}) {
+		t.Errorf("expected the default options, got %+v", got)
+	}
+	// Fields a profile leaves zero fall back to the default
+	stats := WithTxProfile(ctx, ProfileStats)
+	if got := cm.txOptions(stats); got != (TxOptions{Isolation: pgx.RepeatableRead, StatementTimeout: 5 * time.Second}) {
+		t.Errorf("expected the stats options, got %+v", got)
+	}
+	if got := cm.txOptions(WithTxProfile(ctx, ProfileBulk)); got != cm.txOptions(ctx) {
+		t.Errorf("expected an unconfigured profile to use the default options, got %+v", got)
+	}
+	// Per-call options override the profile
+	got := cm.txOptions(WithTxOptions(stats, TxOptions{StatementTimeout: time.Minute}))
+	if got != (TxOptions{Isolation: pgx.RepeatableRead, StatementTimeout: time.Minute}) {
+		t.Errorf("expected the per-call timeout, got %+v", got)
+	}
+}
+
+func TestParseIsolation(t *testing.T) {
+	for level, want := range map[string]pgx.TxIsoLevel{
+		"":                "",
+		"read committed":  pgx.ReadCommitted,
+		"repeatable_read": pgx.RepeatableRead,
+		"SERIALIZABLE":    pgx.Serializable,
+	} {
+		if got, err := ParseIsolation(level); err != nil || got != want {
+			t.Errorf("ParseIsolation(%q) = %q, %v, want %q", level, got, err, want)
+		}
+	}
+	if _, err := ParseIsolation("snapshot"); err == nil {
+		t.Error("expected an unknown isolation level to fail")
+	}
+}
+
+// savepointTx records the savepoints created, released and rolled back in it
+type savepointTx struct {
+	pgx.Tx
+	name string
+	log  *[]string
+}
+
+func (tx savepointTx) Begin(context.Context) (pgx.Tx, error) {
+	name := fmt.Sprintf("%s/sp%d", tx.name, len(*tx.log))
+	*tx.log = append(*tx.log, "begin "+name)
+	return savepointTx{name: name, log: tx.log}, nil
+}
+
+func (tx savepointTx) Commit(context.Context) error {
+	*tx.log = append(*tx.log, "release "+tx.name)
+	return nil
+}
+
+func (tx savepointTx) Rollback(context.Context) error {
+	*tx.log = append(*tx.log, "rollback "+tx.name)
+	return nil
+}
+
+func TestNestedDoUsesSavepoints(t *testing.T) {
+	cm := NewContextManager(nil, zap.NewNop())
+	var log []string
+	outer := savepointTx{name: "tx", log: &log}
+	ctx := cm.putEngineInContext(context.Background(), outer)
+	failure := errors.New("inner failure")
+
+	err := cm.Do(ctx, func(ctx context.Context) error {
+		if got := cm.Get(ctx); got == Engine(outer) {
+			t.Error("expected the nested call to run in the savepoint")
+		}
+		// A failing call nested further rolls back only its savepoint
+		if err := cm.Do(ctx, func(context.Context) error { return failure }); !errors.Is(err, failure) {
+			t.Errorf("expected the inner error, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the nested call to succeed, got %v", err)
+	}
+
+	want := []string{"begin tx/sp0", "begin tx/sp0/sp1", "rollback tx/sp0/sp1", "release tx/sp0"}
+	if fmt.Sprint(log) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, log)
+	}
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"pr-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// orgSetting is the session setting the row level security policies of the
+// tenant tables read the organization of a session from
+const orgSetting = "pr_service.org_id"
+
+// allOrgsSetting is the org setting under which the policies show the rows of
+// every organization
+const allOrgsSetting = "*"
+
+type allOrgsKey struct{}
+
+// AllOrgs marks ctx so statements run with it see and change the rows of
+// every organization. It is for work done on behalf of no organization, like
+// migrations, partition maintenance and looking a token up before its
+// organization is known.
+func AllOrgs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allOrgsKey{}, true)
+}
+
+// sessionOrg returns the org setting of a session running statements with ctx
+func sessionOrg(ctx context.Context) string {
+	if all, _ := ctx.Value(allOrgsKey{}).(bool); all {
+		return allOrgsSetting
+	}
+	return domain.OrgFromContext(ctx)
+}
+
+// prepareOrg sets the organization of conn to the one of ctx before the pool
+// hands conn out. The setting is kept for the session, so it is only changed
+// when conn was last used for another organization.
+func prepareOrg(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	org := sessionOrg(ctx)
+	data := conn.PgConn().CustomData()
+	if data[orgSetting] == org {
+		return true, nil
+	}
+	if _, err := conn.Exec(ctx, "SELECT set_config($1, $2, false)", orgSetting, org); err != nil {
+		// The setting of the session is unknown now, so it is not reused
+		return false, fmt.Errorf("failed to set organization: %w", err)
+	}
+	data[orgSetting] = org
+	return true, nil
+}
+
+// RowSecurityBypassed reports whether the sessions of pool bypass row level
+// security, as those of superusers and roles with BYPASSRLS do. Such sessions
+// see the rows of every organization whatever they set.
+func RowSecurityBypassed(ctx context.Context, pool *pgxpool.Pool) (bool, error) {
+	var bypassed bool
+	err := pool.QueryRow(ctx, "SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypassed)
+	if err != nil {
+		return false, fmt.Errorf("failed to check row level security: %w", err)
+	}
+	return bypassed, nil
+}
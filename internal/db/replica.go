@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type replicaKey struct{}
+
+// AllowReplica marks ctx as tolerating replication lag, so reads made with it
+// outside a transaction may be served by the read replica. Callers that go on
+// to write based on what they read must not use it.
+func AllowReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaKey{}, true)
+}
+
+// replicaAllowed reports whether ctx was marked by AllowReplica
+func replicaAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(replicaKey{}).(bool)
+	return allowed
+}
+
+// WithReplica serves read-only queries from pool, a read replica of the
+// primary. Writes and transactions stay on the primary. The circuit breaker
+// only tracks the primary, so replica reads bypass it.
+func WithReplica(pool *pgxpool.Pool) Option {
+	return func(cm *ContextManager) {
+		cm.replica = pool
+	}
+}
+
+// GetReadOnly returns the engine for a query that doesn't write: the read
+// replica when one is configured and ctx allows it, otherwise the same engine
+// as Get. Queries in a transaction always run in it.
+func (cm *ContextManager) GetReadOnly(ctx context.Context) Engine {
+	if _, ok := ctx.Value(EngineKey).(pgx.Tx); ok || cm.replica == nil || !replicaAllowed(ctx) {
+		return cm.Get(ctx)
+	}
+	return cm.replica
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TxOptions are the options a transaction started by Do runs with. Zero
+// fields leave the database default: its isolation level, and no statement
+// timeout.
+type TxOptions struct {
+	Isolation        pgx.TxIsoLevel
+	StatementTimeout time.Duration
+}
+
+// Isolation levels of TxOptions, so callers need not import pgx
+const (
+	ReadCommitted  = pgx.ReadCommitted
+	RepeatableRead = pgx.RepeatableRead
+	Serializable   = pgx.Serializable
+)
+
+// merge returns o with the fields set in override replaced
+func (o TxOptions) merge(override TxOptions) TxOptions {
+	if override.Isolation != "" {
+		o.Isolation = override.Isolation
+	}
+	if override.StatementTimeout > 0 {
+		o.StatementTimeout = override.StatementTimeout
+	}
+	return o
+}
+
+// TxProfile names the kind of work a transaction does, so the options of
+// each kind can be configured apart
+type TxProfile string
+
+const (
+	// ProfileDefault covers the short transactions of requests
+	ProfileDefault TxProfile = ""
+	// ProfileBulk covers transactions writing many rows, like imports and
+	// retention batches
+	ProfileBulk TxProfile = "bulk"
+	// ProfileStats covers transactions reading many rows, like rollups and
+	// exports
+	ProfileStats TxProfile = "stats"
+)
+
+type txProfileKey struct{}
+
+type txOptionsKey struct{}
+
+// WithTxProfile marks ctx so transactions Do starts with it use the options
+// configured for profile
+func WithTxProfile(ctx context.Context, profile TxProfile) context.Context {
+	return context.WithValue(ctx, txProfileKey{}, profile)
+}
+
+// WithTxOptions sets the options of the transactions Do starts with ctx,
+// overriding the fields they set in the options of its profile
+func WithTxOptions(ctx context.Context, opts TxOptions) context.Context {
+	return context.WithValue(ctx, txOptionsKey{}, opts)
+}
+
+// WithTxProfiles sets the options transactions of each profile run with.
+// Fields left zero in a profile other than ProfileDefault fall back to
+// ProfileDefault.
+func WithTxProfiles(profiles map[TxProfile]TxOptions) Option {
+	return func(cm *ContextManager) {
+		cm.profiles = profiles
+	}
+}
+
+// txOptions returns the options of a transaction started with ctx
+func (cm *ContextManager) txOptions(ctx context.Context) TxOptions {
+	opts := cm.profiles[ProfileDefault]
+	if profile, ok := ctx.Value(txProfileKey{}).(TxProfile); ok && profile != ProfileDefault {
+		opts = opts.merge(cm.profiles[profile])
+	}
+	if override, ok := ctx.Value(txOptionsKey{}).(TxOptions); ok {
+		opts = opts.merge(override)
+	}
+	return opts
+}
+
+// ParseIsolation parses an isolation level as written in SQL, like "repeatable
+// read", with underscores allowed for spaces. An empty level leaves the
+// database default.
+func ParseIsolation(level string) (pgx.TxIsoLevel, error) {
+	normalized := strings.ToLower(strings.TrimSpace(strings.ReplaceAll(level, "_", " ")))
+	switch pgx.TxIsoLevel(normalized) {
+	case "":
+		return "", nil
+	case pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable, pgx.ReadUncommitted:
+		return pgx.TxIsoLevel(normalized), nil
+	default:
+		return "", fmt.Errorf("unknown isolation level %q", level)
+	}
+}
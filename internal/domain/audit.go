@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// AuditEntry is one state-changing API request recorded in the audit log.
+// Route is the registered pattern path, such as /team/add, and PayloadHash the
+// hex SHA-256 of the request body, so entries can be matched to payloads
+// without storing them. Actor is empty when authentication is off.
+type AuditEntry struct {
+	ID          int64
+	Actor       string
+	Method      string
+	Route       string
+	Path        string
+	PayloadHash string
+	Status      int
+	ErrorCode   string
+	RequestID   string
+	CreatedAt   time.Time
+}
+
+// Succeeded checks if the request was applied
+func (e AuditEntry) Succeeded() bool {
+	return e.Status < 400
+}
+
+// AuditFilter selects audit log entries; zero fields match every entry
+type AuditFilter struct {
+	Actor string
+	Route string
+	// Failed selects rejected requests only when true, applied ones only when false
+	Failed *bool
+	From   time.Time
+	To     time.Time
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "fmt"
+
+// BatchOpType describes which PR operation a batch step performs
+type BatchOpType string
+
+const (
+	BatchOpCreate   BatchOpType = "create"
+	BatchOpMerge    BatchOpType = "merge"
+	BatchOpReassign BatchOpType = "reassign"
+)
+
+// BatchOperation is one step of a batch; only the fields of its type are used
+type BatchOperation struct {
+	Type            BatchOpType
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	TeamName        string
+	Repository      string
+	TicketKey       string
+	OldUserID       string
+}
+
+// BatchResult is the outcome of a batch step. ReplacedBy is set for reassignments.
+type BatchResult struct {
+	PR         PullRequest
+	ReplacedBy string
+}
+
+// BatchError reports the step that failed a batch; the whole batch is rolled back
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
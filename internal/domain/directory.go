@@ -0,0 +1,18 @@
+package domain
+
+// DirectoryGroup is a group read from a directory service, synced as a team
+// of the same name. Members carry the directory's user ID and display name.
+type DirectoryGroup struct {
+	TeamName string
+	Members  []User
+}
+
+// DirectorySync reports the outcome of syncing teams from a directory.
+// Deactivated lists users that disappeared from the directory; their open
+// reviews were handed over as Reassignments.
+type DirectorySync struct {
+	TeamsSynced   int
+	TeamsCreated  []string
+	Deactivated   []string
+	Reassignments []Reassignment
+}
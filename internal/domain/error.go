@@ -1,6 +1,10 @@
 package domain
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
 // Domain errors - переносим из BusinessThing и адаптируем под наши нужды
 var (
@@ -24,6 +28,33 @@ var (
 
 	// ErrInvalidArgument - невалидный аргумент (400)
 	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrUnauthorized - запрос не прошёл проверку подписи или токена (401)
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden - у вызывающего нет нужной роли или он действует от имени другого пользователя (403)
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrTicketNotFound - тикет не найден в Jira (400)
+	ErrTicketNotFound = errors.New("ticket not found")
+
+	// ErrConflict - текущее состояние не позволяет выполнить операцию (409)
+	ErrConflict = errors.New("conflict with current state")
+
+	// ErrVersionConflict - PR изменён параллельно с момента чтения (409, CONFLICT)
+	ErrVersionConflict = fmt.Errorf("%w: pull request was changed concurrently, retry", ErrConflict)
+
+	// ErrRateLimited - вызывающий исчерпал квоту запросов (429)
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrPayloadTooLarge - тело запроса больше допустимого для маршрута (413)
+	ErrPayloadTooLarge = errors.New("request body too large")
+
+	// ErrUnavailable - зависимость недоступна, запрос отклонён без ожидания (503)
+	ErrUnavailable = errors.New("service temporarily unavailable")
+
+	// ErrMaintenance - сервис в режиме обслуживания и не выполняет запрос (503)
+	ErrMaintenance = errors.New("service is under maintenance")
 )
 
 type ErrorCode string
@@ -36,41 +67,71 @@ const (
 	ErrorCodeNoCandidate     ErrorCode = "NO_CANDIDATE"
 	ErrorCodeNotFound        ErrorCode = "NOT_FOUND"
 	ErrorCodeInvalidArgument ErrorCode = "INVALID_ARGUMENT"
+	ErrorCodeUnauthorized    ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden       ErrorCode = "FORBIDDEN"
+	ErrorCodeTicketNotFound  ErrorCode = "TICKET_NOT_FOUND"
+	ErrorCodeConflict        ErrorCode = "CONFLICT"
+	ErrorCodeRateLimited     ErrorCode = "RATE_LIMITED"
+	ErrorCodePayloadTooLarge ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrorCodeTimeout         ErrorCode = "TIMEOUT"
+	ErrorCodeUnavailable     ErrorCode = "UNAVAILABLE"
+	ErrorCodeMaintenance     ErrorCode = "MAINTENANCE"
+	ErrorCodeInternal        ErrorCode = "INTERNAL_ERROR"
 )
 
+// ErrorInfo describes an error code of the API
+type ErrorInfo struct {
+	// Err is the domain error reported under the code; nil for INTERNAL_ERROR,
+	// which covers every error not in the registry
+	Err         error
+	Code        ErrorCode
+	Status      int
+	Description string
+}
+
+// ErrorRegistry lists every error code the API returns. GetErrorCode and
+// GetHTTPStatus match errors against it in order, and the error catalog
+// endpoint serves it, so a new domain error needs only an entry here.
+var ErrorRegistry = []ErrorInfo{
+	{ErrTeamExists, ErrorCodeTeamExists, 400, "Команда с таким именем уже существует"},
+	{ErrPRExists, ErrorCodePRExists, 409, "PR с таким ID уже существует"},
+	{ErrPRMerged, ErrorCodePRMerged, 409, "PR уже смержен и не может быть изменён"},
+	{ErrNotAssigned, ErrorCodeNotAssigned, 409, "Пользователь не назначен ревьювером этого PR"},
+	{ErrNoCandidate, ErrorCodeNoCandidate, 409, "Нет активного кандидата для назначения ревьювером"},
+	{ErrNotFound, ErrorCodeNotFound, 404, "Ресурс не найден"},
+	{ErrInvalidArgument, ErrorCodeInvalidArgument, 400, "Невалидный запрос; поля, не прошедшие проверку, перечислены в details"},
+	{ErrUnauthorized, ErrorCodeUnauthorized, 401, "Нет токена, токен невалиден или не прошла проверка подписи вебхука"},
+	{ErrForbidden, ErrorCodeForbidden, 403, "Роль токена не допускает операцию, лид действует вне своей команды или вызывающий действует от имени другого пользователя"},
+	{ErrTicketNotFound, ErrorCodeTicketNotFound, 400, "Тикет не найден в Jira"},
+	{ErrConflict, ErrorCodeConflict, 409, "Текущее состояние не позволяет выполнить операцию или ресурс изменён параллельным запросом; во втором случае запрос можно повторить"},
+	{ErrRateLimited, ErrorCodeRateLimited, 429, "Квота запросов исчерпана; повторить после Retry-After"},
+	{ErrPayloadTooLarge, ErrorCodePayloadTooLarge, 413, "Тело запроса больше допустимого для маршрута"},
+	{context.DeadlineExceeded, ErrorCodeTimeout, 503, "Запрос не уложился в отведённое маршруту время"},
+	{ErrUnavailable, ErrorCodeUnavailable, 503, "База данных недоступна или перегружена; запрос отклонён сразу, повторить позже"},
+	{ErrMaintenance, ErrorCodeMaintenance, 503, "Сервис в режиме обслуживания: только чтение или полностью закрыт; повторить после Retry-After"},
+	{nil, ErrorCodeInternal, 500, "Внутренняя ошибка сервера; подробности только в логах"},
+}
+
+// LookupError returns the registry entry err is reported under, or INTERNAL_ERROR
+func LookupError(err error) ErrorInfo {
+	for _, info := range ErrorRegistry {
+		if info.Err != nil && errors.Is(err, info.Err) {
+			return info
+		}
+	}
+	return ErrorRegistry[len(ErrorRegistry)-1]
+}
+
+// GetErrorCode returns the code of err, or "" for errors not in the registry
 func GetErrorCode(err error) ErrorCode {
-	switch {
-	case errors.Is(err, ErrTeamExists):
-		return ErrorCodeTeamExists
-	case errors.Is(err, ErrPRExists):
-		return ErrorCodePRExists
-	case errors.Is(err, ErrPRMerged):
-		return ErrorCodePRMerged
-	case errors.Is(err, ErrNotAssigned):
-		return ErrorCodeNotAssigned
-	case errors.Is(err, ErrNoCandidate):
-		return ErrorCodeNoCandidate
-	case errors.Is(err, ErrNotFound):
-		return ErrorCodeNotFound
-	case errors.Is(err, ErrInvalidArgument):
-		return ErrorCodeInvalidArgument
-	default:
+	info := LookupError(err)
+	if info.Err == nil {
 		return ""
 	}
+	return info.Code
 }
 
+// GetHTTPStatus returns the HTTP status err is reported with
 func GetHTTPStatus(err error) int {
-	switch {
-	case errors.Is(err, ErrNotFound):
-		return 404
-	case errors.Is(err, ErrTeamExists):
-		return 400
-	case errors.Is(err, ErrPRExists), errors.Is(err, ErrPRMerged),
-		errors.Is(err, ErrNotAssigned), errors.Is(err, ErrNoCandidate):
-		return 409
-	case errors.Is(err, ErrInvalidArgument):
-		return 400
-	default:
-		return 500
-	}
+	return LookupError(err).Status
 }
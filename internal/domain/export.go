@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// ExportFormatVersion is written into every export; imports of other versions are rejected
+const ExportFormatVersion = 1
+
+// DataExport is a full dump of teams, users, memberships, PRs and reviewer
+// assignments, used to clone an environment or move data between instances.
+// Soft-deleted users are included so PRs they authored or reviewed restore.
+type DataExport struct {
+	Version      int
+	ExportedAt   time.Time
+	Teams        []ExportedTeam
+	Users        []ExportedUser
+	Memberships  []ExportedMembership
+	PullRequests []ExportedPullRequest
+	Reviewers    []ExportedReviewer
+}
+
+// ExportedTeam is a team row; empty channel fields mean no notification channel
+type ExportedTeam struct {
+	TeamName                string
+	ParentTeamName          string
+	NotificationChannelType NotificationChannelType
+	NotificationChannelURL  string
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+	DeletedAt               *time.Time
+}
+
+// ExportedUser is a user row without memberships
+type ExportedUser struct {
+	UserID     string
+	Username   string
+	IsActive   bool
+	Role       UserRole
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  *time.Time
+	LastSeenAt *time.Time
+}
+
+// ExportedMembership places a user in a team
+type ExportedMembership struct {
+	TeamName string
+	UserID   string
+	JoinedAt time.Time
+}
+
+// ExportedPullRequest is a PR row without its reviewers
+type ExportedPullRequest struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	TeamName        string
+	Repository      string
+	TicketKey       string
+	Status          PRStatus
+	CreatedAt       time.Time
+	MergedAt        *time.Time
+}
+
+// ExportedReviewer is a reviewer assignment with its review timeline
+type ExportedReviewer struct {
+	PullRequestID   string
+	UserID          string
+	AssignedAt      time.Time
+	FirstActionAt   *time.Time
+	StaleNotifiedAt *time.Time
+	EscalatedAt     *time.Time
+}
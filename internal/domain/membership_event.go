@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// MembershipAction describes a change recorded in the membership audit log
+type MembershipAction string
+
+const (
+	MembershipAdded       MembershipAction = "ADDED"
+	MembershipRemoved     MembershipAction = "REMOVED"
+	MembershipActivated   MembershipAction = "ACTIVATED"
+	MembershipDeactivated MembershipAction = "DEACTIVATED"
+	MembershipMoved       MembershipAction = "MOVED"
+)
+
+// MembershipEvent is one entry of the membership audit log.
+// For MOVED events TeamName is the destination and FromTeamName the origin.
+type MembershipEvent struct {
+	ID           int64
+	TeamName     string
+	UserID       string
+	Action       MembershipAction
+	FromTeamName string
+	CreatedAt    time.Time
+}
+
+// NewMembershipEvent creates an audit entry for a change within one team
+func NewMembershipEvent(teamName, userID string, action MembershipAction) MembershipEvent {
+	return MembershipEvent{
+		TeamName:  teamName,
+		UserID:    userID,
+		Action:    action,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewMembershipMove creates an audit entry for a user moved between teams
+func NewMembershipMove(fromTeam, toTeam, userID string) MembershipEvent {
+	event := NewMembershipEvent(toTeam, userID, MembershipMoved)
+	event.FromTeamName = fromTeam
+	return event
+}
+
+// ActivityEvents records an activity change of user in every team they belong to
+func ActivityEvents(user User, isActive bool) []MembershipEvent {
+	action := MembershipDeactivated
+	if isActive {
+		action = MembershipActivated
+	}
+
+	events := make([]MembershipEvent, 0, len(user.Teams))
+	for _, teamName := range user.Teams {
+		events = append(events, NewMembershipEvent(teamName, user.UserID, action))
+	}
+	return events
+}
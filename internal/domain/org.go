@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultOrg is the organization of callers whose credentials name none, and
+// of all data stored before organizations were introduced
+const DefaultOrg = "default"
+
+// maxOrgIDLength matches the org_id columns
+const maxOrgIDLength = 100
+
+// ValidOrgID checks if id can name an organization. "*" is reserved for
+// statements spanning every organization.
+func ValidOrgID(id string) bool {
+	return id != "" && len(id) <= maxOrgIDLength && !strings.ContainsAny(id, "* \t\r\n")
+}
+
+type orgKey struct{}
+
+// WithOrg returns a context whose work is done within org, overriding the
+// organization of its caller. Background work uses it to act for one
+// organization at a time.
+func WithOrg(ctx context.Context, org string) context.Context {
+	return context.WithValue(ctx, orgKey{}, org)
+}
+
+// OrgFromContext returns the organization work done with ctx belongs to: the
+// one set by WithOrg, else the caller's, else DefaultOrg
+func OrgFromContext(ctx context.Context) string {
+	if org, ok := ctx.Value(orgKey{}).(string); ok && org != "" {
+		return org
+	}
+	if p, ok := ctx.Value(principalKey{}).(Principal); ok && p.Org != "" {
+		return p.Org
+	}
+	return DefaultOrg
+}
+
+// SetEventOrg sets the OrgID of events that have none to the organization of
+// ctx
+func SetEventOrg(ctx context.Context, events []Event) {
+	for i := range events {
+		if events[i].OrgID == "" {
+			events[i].OrgID = OrgFromContext(ctx)
+		}
+	}
+}
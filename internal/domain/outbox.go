@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OutboxMessage is an event recorded in the transaction of the change that
+// caused it and waiting to be relayed to the message broker. Key is the
+// partitioning key; messages with the same key are relayed in ID order.
+// TxID is the ID of the recording transaction, which orders the event log.
+type OutboxMessage struct {
+	ID          int64
+	TxID        uint64
+	EventType   EventType
+	Key         string
+	Payload     []byte
+	CreatedAt   time.Time
+	PublishedAt *time.Time
+}
+
+// Cursor returns the position of the message in the event log
+func (m OutboxMessage) Cursor() EventCursor {
+	return EventCursor{TxID: m.TxID, ID: m.ID}
+}
+
+// EventCursor is a position in the event log, which is ordered by recording
+// transaction and then by ID. Transactions commit out of ID order, but a
+// transaction's events are only replayed once every older transaction has
+// finished, so events never appear behind a cursor already handed out.
+// The zero cursor is the start of the log.
+type EventCursor struct {
+	TxID uint64
+	ID   int64
+}
+
+// String encodes the cursor for clients
+func (c EventCursor) String() string {
+	return fmt.Sprintf("%d-%d", c.TxID, c.ID)
+}
+
+// ParseEventCursor decodes a cursor produced by EventCursor.String
+func ParseEventCursor(s string) (EventCursor, error) {
+	rawTx, rawID, ok := strings.Cut(s, "-")
+	if !ok {
+		return EventCursor{}, ErrInvalidArgument
+	}
+	txID, err := strconv.ParseUint(rawTx, 10, 64)
+	if err != nil {
+		return EventCursor{}, ErrInvalidArgument
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id < 0 {
+		return EventCursor{}, ErrInvalidArgument
+	}
+	return EventCursor{TxID: txID, ID: id}, nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Role is an access role granted to a caller by their bearer token. Roles are
+// ordered: admin includes lead, and lead includes member.
+type Role string
+
+const (
+	RoleMember Role = "member"
+	RoleLead   Role = "lead"
+	RoleAdmin  Role = "admin"
+)
+
+// roleRanks orders the known roles; unknown roles grant nothing
+var roleRanks = map[Role]int{RoleMember: 1, RoleLead: 2, RoleAdmin: 3}
+
+// IsValid checks if r is a known role
+func (r Role) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Principal is the authenticated caller of a request. Team is set for callers
+// restricted to one team, which may only change that team's PRs. Org is the
+// organization the caller belongs to; empty means DefaultOrg.
+type Principal struct {
+	UserID string
+	Roles  []Role
+	Team   string
+	Org    string
+}
+
+// HasRole checks if the principal holds role or a role that includes it
+func (p Principal) HasRole(role Role) bool {
+	return slices.ContainsFunc(p.Roles, func(r Role) bool {
+		return roleRanks[r] >= roleRanks[role] && roleRanks[r] > 0
+	})
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated caller, if the request was
+// authenticated. Without authentication no access checks apply.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok && p.UserID != ""
+}
+
+// AuthorizeLead checks that the caller in ctx may run lead operations on one
+// of teams: admins may on any team and leads on the teams they belong to.
+// getUser loads the caller's user; unauthenticated calls are not checked.
+func AuthorizeLead(ctx context.Context, getUser func(context.Context, string) (User, error), teams ...string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.HasRole(RoleAdmin) {
+		return nil
+	}
+	if !principal.HasRole(RoleLead) {
+		return ErrForbidden
+	}
+
+	caller, err := getUser(ctx, principal.UserID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(teams, caller.IsMemberOf) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeTeam checks that a caller restricted to a team in ctx acts within
+// teamName; other callers are not limited
+func AuthorizeTeam(ctx context.Context, teamName string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if ok && principal.Team != "" && principal.Team != teamName {
+		return ErrForbidden
+	}
+	return nil
+}
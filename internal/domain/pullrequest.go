@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"regexp"
+	"strings"
+	"time"
+)
 
 type PRStatus string
 
@@ -13,21 +17,54 @@ type PullRequest struct {
 	PullRequestID     string
 	PullRequestName   string
 	AuthorID          string
+	TeamName          string
+	Repository        string
+	TicketKey         string
 	Status            PRStatus
 	AssignedReviewers []string
 	CreatedAt         time.Time
 	MergedAt          *time.Time
+	// Version counts changes to the PR; an update made from an older version
+	// fails with ErrVersionConflict
+	Version int64
+}
+
+// PRFilter narrows a PR listing. Fields combine with AND; a list matches any
+// of its values and an empty one matches every PR. Time bounds are half-open,
+// [From, To), and nil bounds are unset; merged bounds never match open PRs.
+type PRFilter struct {
+	TicketKeys   []string
+	Statuses     []PRStatus
+	AuthorIDs    []string
+	TeamNames    []string
+	Repositories []string
+	ReviewerIDs  []string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	MergedFrom   *time.Time
+	MergedTo     *time.Time
+}
+
+// ticketKeyPattern matches Jira issue keys such as "PAY-123"
+var ticketKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[1-9][0-9]*$`)
+
+// NormalizeTicketKey upper-cases a Jira issue key and reports whether it is well-formed
+func NormalizeTicketKey(key string) (string, bool) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	return key, ticketKeyPattern.MatchString(key)
 }
 
-func NewPullRequest(prID, prName, authorID string) PullRequest {
+func NewPullRequest(prID, prName, authorID, teamName string) PullRequest {
 	return PullRequest{
 		PullRequestID:     prID,
 		PullRequestName:   prName,
 		AuthorID:          authorID,
+		TeamName:          teamName,
 		Status:            PRStatusOpen,
 		AssignedReviewers: make([]string, 0),
 		CreatedAt:         time.Now(),
 		MergedAt:          nil,
+		Version:           1,
 	}
 }
 
@@ -83,3 +120,32 @@ func (pr *PullRequest) AddReviewer(userID string) {
 func (pr *PullRequest) SetReviewers(reviewers []string) {
 	pr.AssignedReviewers = reviewers
 }
+
+// StaleReview is an open review the reviewer has not acted on since AssignedAt
+type StaleReview struct {
+	PullRequestID   string
+	PullRequestName string
+	TeamName        string
+	UserID          string
+	AssignedAt      time.Time
+}
+
+// Escalation is a review raised with the on-call rotation because it is still
+// waiting for the reviewer's first action well past DueAt
+type Escalation struct {
+	StaleReview
+	DueAt time.Time
+}
+
+// ReviewAssignment is an open review waiting for the reviewer's first action,
+// due DueAt under the first-review SLA
+type ReviewAssignment struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	TeamName        string
+	Repository      string
+	UserID          string
+	AssignedAt      time.Time
+	DueAt           time.Time
+}
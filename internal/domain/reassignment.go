@@ -1,8 +1,14 @@
 package domain
 
 // Reassignment describes reviewer replacement details.
+// An empty NewUserID means the review was closed without a replacement.
 type Reassignment struct {
 	PullRequestID string
 	OldUserID     string
 	NewUserID     string
 }
+
+// IsClosed reports whether the reviewer was removed without a replacement.
+func (r Reassignment) IsClosed() bool {
+	return r.NewUserID == ""
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// WeeklyReport summarizes review activity over [From, To)
+type WeeklyReport struct {
+	From        time.Time
+	To          time.Time
+	ReviewSLA   time.Duration
+	Assignments map[string]int
+	SLA         []ReviewSLA
+	Fairness    []TeamFairness
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "fmt"
+
+// RosterFormat is the encoding of an imported team roster
+type RosterFormat string
+
+const (
+	RosterFormatCSV    RosterFormat = "csv"
+	RosterFormatNDJSON RosterFormat = "ndjson"
+)
+
+// IsValid checks if format is one of the supported roster encodings
+func (f RosterFormat) IsValid() bool {
+	return f == RosterFormatCSV || f == RosterFormatNDJSON
+}
+
+// RosterRow is one member entry of an imported roster.
+// A nil IsActive and an empty Role keep the current values of existing users.
+type RosterRow struct {
+	Line     int
+	UserID   string
+	Username string
+	IsActive *bool
+	Role     UserRole
+}
+
+// RosterRowError explains why a roster row was rejected
+type RosterRowError struct {
+	Line    int
+	UserID  string
+	Message string
+}
+
+// RosterError reports every rejected row of a roster import
+type RosterError struct {
+	Rows []RosterRowError
+}
+
+func (e *RosterError) Error() string {
+	return fmt.Sprintf("roster has %d invalid rows", len(e.Rows))
+}
+
+// Unwrap lets a roster error be handled as an invalid argument
+func (e *RosterError) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// RosterImport summarizes an applied roster import
+type RosterImport struct {
+	Team           Team
+	TeamCreated    bool
+	CreatedUserIDs []string
+	UpdatedUserIDs []string
+}
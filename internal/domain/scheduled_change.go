@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ScheduledChangeKind describes which operation a scheduled change performs
+type ScheduledChangeKind string
+
+const (
+	ScheduledChangeSetIsActive    ScheduledChangeKind = "SET_IS_ACTIVE"
+	ScheduledChangeBulkDeactivate ScheduledChangeKind = "BULK_DEACTIVATE"
+)
+
+// ScheduledChangeStatus is the lifecycle state of a scheduled change
+type ScheduledChangeStatus string
+
+const (
+	ScheduledChangePending    ScheduledChangeStatus = "PENDING"
+	ScheduledChangeProcessing ScheduledChangeStatus = "PROCESSING"
+	ScheduledChangeApplied    ScheduledChangeStatus = "APPLIED"
+	ScheduledChangeFailed     ScheduledChangeStatus = "FAILED"
+)
+
+// ScheduledChange is an activity change that is applied at EffectiveAt
+type ScheduledChange struct {
+	ID          int64
+	Kind        ScheduledChangeKind
+	TeamName    string
+	UserIDs     []string
+	IsActive    bool
+	EffectiveAt time.Time
+	Status      ScheduledChangeStatus
+	Error       string
+	CreatedAt   time.Time
+	AppliedAt   *time.Time
+}
+
+// NewScheduledChange creates a pending scheduled change
+func NewScheduledChange(kind ScheduledChangeKind, teamName string, userIDs []string, isActive bool, effectiveAt time.Time) ScheduledChange {
+	return ScheduledChange{
+		Kind:        kind,
+		TeamName:    teamName,
+		UserIDs:     userIDs,
+		IsActive:    isActive,
+		EffectiveAt: effectiveAt,
+		Status:      ScheduledChangePending,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// LatencyStats summarizes durations of one group (a team, a user, ...) in seconds
+type LatencyStats struct {
+	Key   string
+	Count int
+	P50   float64
+	P90   float64
+	P99   float64
+}
+
+// ReviewerLoad is the number of assignments of an active team member
+type ReviewerLoad struct {
+	TeamName string
+	UserID   string
+	Count    int
+}
+
+// TeamFairness describes how evenly reviews are spread across active members of a team
+type TeamFairness struct {
+	TeamName      string
+	ActiveMembers int
+	Assignments   int
+	// Gini is 0 when every member got the same number of reviews and approaches 1
+	// when a single member got all of them
+	Gini float64
+	// MaxMinRatio is nil when the least loaded member got no reviews
+	MaxMinRatio *float64
+}
+
+// NewTeamFairness computes fairness of a team from assignment counts of its active members
+func NewTeamFairness(teamName string, counts []int) TeamFairness {
+	fairness := TeamFairness{
+		TeamName:      teamName,
+		ActiveMembers: len(counts),
+	}
+	if len(counts) == 0 {
+		return fairness
+	}
+
+	sorted := append([]int(nil), counts...)
+	sort.Ints(sorted)
+
+	weighted := 0
+	for i, count := range sorted {
+		fairness.Assignments += count
+		weighted += (i + 1) * count
+	}
+	if fairness.Assignments == 0 {
+		return fairness
+	}
+
+	n := float64(len(sorted))
+	fairness.Gini = 2*float64(weighted)/(n*float64(fairness.Assignments)) - (n+1)/n
+
+	if minCount := sorted[0]; minCount > 0 {
+		ratio := float64(sorted[len(sorted)-1]) / float64(minCount)
+		fairness.MaxMinRatio = &ratio
+	}
+
+	return fairness
+}
+
+// PRAging counts open PRs of a team by how long they have been open
+type PRAging struct {
+	TeamName         string
+	UnderOneDay      int
+	OneToThreeDays   int
+	ThreeToSevenDays int
+	OverSevenDays    int
+}
+
+// Total returns the number of open PRs of the team
+func (a PRAging) Total() int {
+	return a.UnderOneDay + a.OneToThreeDays + a.ThreeToSevenDays + a.OverSevenDays
+}
+
+// DailyStats is a per-day rollup of review activity of a team
+type DailyStats struct {
+	Day           time.Time
+	TeamName      string
+	Assignments   int
+	Merges        int
+	Reassignments int
+}
+
+// StatsSort orders keyed counts in stats listings
+type StatsSort string
+
+const (
+	StatsSortCountDesc StatsSort = "count_desc"
+	StatsSortCountAsc  StatsSort = "count_asc"
+	StatsSortKey       StatsSort = "key"
+)
+
+// IsValid checks if the sort order is supported
+func (s StatsSort) IsValid() bool {
+	switch s {
+	case StatsSortCountDesc, StatsSortCountAsc, StatsSortKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// KeyCount is a count of one group (a user, a PR, ...)
+type KeyCount struct {
+	Key   string
+	Count int
+}
+
+// AssignmentStats is a page of per-reviewer and per-PR assignment counts
+type AssignmentStats struct {
+	ByUser     []KeyCount
+	TotalUsers int
+	ByPR       []KeyCount
+	TotalPRs   int
+}
+
+// ReviewerWorkload is an active user's current review load against their capacity.
+// Utilization is OpenReviews as a percentage of Capacity and may exceed 100.
+type ReviewerWorkload struct {
+	UserID      string
+	Username    string
+	OpenReviews int
+	Capacity    int
+	Utilization float64
+}
+
+// NewReviewerWorkload computes utilization of openReviews against a positive capacity
+func NewReviewerWorkload(userID, username string, openReviews, capacity int) ReviewerWorkload {
+	return ReviewerWorkload{
+		UserID:      userID,
+		Username:    username,
+		OpenReviews: openReviews,
+		Capacity:    capacity,
+		Utilization: float64(openReviews) * 100 / float64(capacity),
+	}
+}
+
+// AuthorStats summarizes PRs created within a window by one group (an author or a team).
+// AvgReviewWaitSeconds is the mean time from creation to the first reviewer action
+// over PRs that got one; nil if none did.
+type AuthorStats struct {
+	Key                  string
+	PRsCreated           int
+	PRsMerged            int
+	AvgReviewers         float64
+	AvgReviewWaitSeconds *float64
+}
+
+// MergeRate is the share of created PRs that are merged, between 0 and 1
+func (s AuthorStats) MergeRate() float64 {
+	if s.PRsCreated == 0 {
+		return 0
+	}
+	return float64(s.PRsMerged) / float64(s.PRsCreated)
+}
+
+// ReviewPair is the number of times ReviewerID was assigned to PRs by AuthorID
+type ReviewPair struct {
+	AuthorID   string
+	ReviewerID string
+	Count      int
+}
+
+// ReviewMatrix arranges review pairs as a dense matrix: Counts[i][j] is the number
+// of assignments of Reviewers[j] to PRs by Authors[i]. Both axes are sorted.
+type ReviewMatrix struct {
+	Authors   []string
+	Reviewers []string
+	Counts    [][]int
+}
+
+// NewReviewMatrix builds a matrix out of pairs; missing pairs count as zero
+func NewReviewMatrix(pairs []ReviewPair) ReviewMatrix {
+	authorIdx := make(map[string]int)
+	reviewerIdx := make(map[string]int)
+	for _, p := range pairs {
+		authorIdx[p.AuthorID] = 0
+		reviewerIdx[p.ReviewerID] = 0
+	}
+
+	m := ReviewMatrix{
+		Authors:   sortedKeys(authorIdx),
+		Reviewers: sortedKeys(reviewerIdx),
+	}
+	for i, id := range m.Authors {
+		authorIdx[id] = i
+	}
+	for j, id := range m.Reviewers {
+		reviewerIdx[id] = j
+	}
+
+	m.Counts = make([][]int, len(m.Authors))
+	for i := range m.Counts {
+		m.Counts[i] = make([]int, len(m.Reviewers))
+	}
+	for _, p := range pairs {
+		m.Counts[authorIdx[p.AuthorID]][reviewerIdx[p.ReviewerID]] += p.Count
+	}
+	return m
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReassignmentStats counts reviewers taken off PRs for one group (a user or a team).
+// Closed is the part of Total where no replacement was found.
+type ReassignmentStats struct {
+	Key    string
+	Total  int
+	Closed int
+}
+
+// Replaced is the number of reassignments that found a replacement reviewer
+func (s ReassignmentStats) Replaced() int {
+	return s.Total - s.Closed
+}
+
+// ReviewSLA is one team's compliance with the first-review SLA. Due counts
+// assignments that got a first action or have been waiting longer than the SLA;
+// Met counts those acted on within it.
+type ReviewSLA struct {
+	TeamName string
+	Due      int
+	Met      int
+}
+
+// Compliance is the share of due assignments that met the SLA, or 1 if none are due
+func (s ReviewSLA) Compliance() float64 {
+	if s.Due == 0 {
+		return 1
+	}
+	return float64(s.Met) / float64(s.Due)
+}
@@ -2,12 +2,15 @@ package domain
 
 import "time"
 
-// Team represents a team of users
+// Team represents a team of users. DeletedAt is set on teams that were
+// deleted and can still be restored.
 type Team struct {
-	TeamName  string
-	Members   []User
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	TeamName       string
+	ParentTeamName string
+	Members        []User
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      *time.Time
 }
 
 // NewTeam creates a new team
@@ -21,6 +24,11 @@ func NewTeam(teamName string, members []User) Team {
 	}
 }
 
+// IsDeleted checks if team was removed
+func (t *Team) IsDeleted() bool {
+	return t.DeletedAt != nil
+}
+
 // GetActiveMembers returns only active members
 func (t *Team) GetActiveMembers() []User {
 	active := make([]User, 0, len(t.Members))
@@ -52,3 +60,55 @@ func (t *Team) HasMember(userID string) bool {
 	}
 	return false
 }
+
+// TeamSummary is a lightweight view of a team used in listings
+type TeamSummary struct {
+	TeamName          string
+	MemberCount       int
+	ActiveMemberCount int
+	CreatedAt         time.Time
+}
+
+// TeamMerge describes folding a source team into a target team
+type TeamMerge struct {
+	SourceTeam      string
+	TargetTeam      string
+	DryRun          bool
+	MovedUserIDs    []string
+	SharedUserIDs   []string
+	ReparentedTeams []string
+	MovedPRIDs      []string
+	Team            Team
+}
+
+// NotificationChannelType is the chat tool a team notification channel lives in
+type NotificationChannelType string
+
+const (
+	NotificationChannelSlack      NotificationChannelType = "slack"
+	NotificationChannelMSTeams    NotificationChannelType = "msteams"
+	NotificationChannelMattermost NotificationChannelType = "mattermost"
+)
+
+// IsValid reports whether t is a known channel type
+func (t NotificationChannelType) IsValid() bool {
+	switch t {
+	case NotificationChannelSlack, NotificationChannelMSTeams, NotificationChannelMattermost:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationChannel is a team chat channel posted to through an incoming webhook
+type NotificationChannel struct {
+	Type       NotificationChannelType
+	WebhookURL string
+}
+
+// TeamSettings holds per-team configuration. A nil NotificationChannel leaves
+// the team without channel notifications.
+type TeamSettings struct {
+	TeamName            string
+	NotificationChannel *NotificationChannel
+}
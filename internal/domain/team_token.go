@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// TeamTokenPrefix starts every team token, telling them apart from OIDC tokens
+const TeamTokenPrefix = "prt_"
+
+// TeamToken is a bearer token restricted to one team, issued for the team's
+// bots. Its callers hold the member role and may only change PRs of the team.
+type TeamToken struct {
+	ID        int64
+	OrgID     string
+	TeamName  string
+	Name      string
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+// IsExpired checks if the token is no longer valid at now
+func (t TeamToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// Principal returns the caller authenticated by the token
+func (t TeamToken) Principal() Principal {
+	return Principal{
+		UserID: "team-token:" + strconv.FormatInt(t.ID, 10),
+		Roles:  []Role{RoleMember},
+		Team:   t.TeamName,
+		Org:    t.OrgID,
+	}
+}
@@ -1,25 +1,54 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
-// User represents a team member
+// UserRole is a member's role within their team
+type UserRole string
+
+const (
+	UserRoleMember UserRole = "member"
+	UserRoleLead   UserRole = "lead"
+)
+
+// IsValid checks if role is one of the known roles
+func (r UserRole) IsValid() bool {
+	return r == UserRoleMember || r == UserRoleLead
+}
+
+// User represents a team member.
+// TeamName is the team the user is viewed in: the requested team for roster
+// reads and the earliest joined team otherwise. Teams lists every membership.
+// LastSeenAt is the latest heartbeat; nil if the user has never been seen.
 type User struct {
-	UserID    string
-	Username  string
-	TeamName  string
-	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	UserID     string
+	Username   string
+	TeamName   string
+	Teams      []string
+	IsActive   bool
+	Role       UserRole
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  *time.Time
+	LastSeenAt *time.Time
 }
 
-// NewUser creates a new user
+// NewUser creates a new user with the member role
 func NewUser(userID, username, teamName string, isActive bool) User {
 	now := time.Now()
+	var teams []string
+	if teamName != "" {
+		teams = []string{teamName}
+	}
 	return User{
 		UserID:    userID,
 		Username:  username,
 		TeamName:  teamName,
+		Teams:     teams,
 		IsActive:  isActive,
+		Role:      UserRoleMember,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -50,3 +79,66 @@ func (u *User) SetIsActive(isActive bool) {
 		u.Deactivate()
 	}
 }
+
+// MarkDeleted deactivates the user and records the deletion time
+func (u *User) MarkDeleted() {
+	now := time.Now()
+	u.IsActive = false
+	u.UpdatedAt = now
+	u.DeletedAt = &now
+}
+
+// MarkSeen records a heartbeat at the given time
+func (u *User) MarkSeen(at time.Time) {
+	u.LastSeenAt = &at
+}
+
+// IsDormant reports whether the user has not been seen within dormantAfter of now.
+// Users that were never seen are dormant.
+func (u *User) IsDormant(now time.Time, dormantAfter time.Duration) bool {
+	return u.LastSeenAt == nil || now.Sub(*u.LastSeenAt) > dormantAfter
+}
+
+// IsDeleted checks if user was removed
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// SetRole changes the user's team role
+func (u *User) SetRole(role UserRole) {
+	u.Role = role
+	u.UpdatedAt = time.Now()
+}
+
+// IsLead checks if user leads their team
+func (u *User) IsLead() bool {
+	return u.Role == UserRoleLead
+}
+
+// IsMemberOf checks if user belongs to teamName
+func (u *User) IsMemberOf(teamName string) bool {
+	return u.TeamName == teamName || slices.Contains(u.Teams, teamName)
+}
+
+// MemberTeams returns every team the user belongs to
+func (u *User) MemberTeams() []string {
+	if u.TeamName == "" || slices.Contains(u.Teams, u.TeamName) {
+		return u.Teams
+	}
+	return append([]string{u.TeamName}, u.Teams...)
+}
+
+// JoinTeam records membership in teamName, keeping existing memberships
+func (u *User) JoinTeam(teamName string) {
+	if !slices.Contains(u.Teams, teamName) {
+		u.Teams = append(u.Teams, teamName)
+	}
+	if u.TeamName == "" {
+		u.TeamName = teamName
+	}
+}
+
+// CanManageTeam checks if user may perform team-scoped admin actions on teamName
+func (u *User) CanManageTeam(teamName string) bool {
+	return u.IsLead() && u.IsMemberOf(teamName)
+}
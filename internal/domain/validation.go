@@ -0,0 +1,62 @@
+package domain
+
+import "strings"
+
+// FieldViolation names a request field and the constraint its value breaks.
+// Field is a path into the request, such as members[2].user_id.
+type FieldViolation struct {
+	Field      string
+	Constraint string
+}
+
+func (v FieldViolation) String() string {
+	return v.Field + ": " + v.Constraint
+}
+
+// ValidationError is an ErrInvalidArgument that names the offending fields
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+// NewValidationError reports a single invalid field
+func NewValidationError(field, constraint string) *ValidationError {
+	return &ValidationError{Violations: []FieldViolation{{Field: field, Constraint: constraint}}}
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return ErrInvalidArgument.Error() + ": " + strings.Join(parts, "; ")
+}
+
+// Is makes errors.Is(err, ErrInvalidArgument) hold for validation errors
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidArgument
+}
+
+// Validator collects field violations of a request
+type Validator struct {
+	violations []FieldViolation
+}
+
+// Check records a violation of constraint by field unless ok
+func (v *Validator) Check(ok bool, field, constraint string) {
+	if !ok {
+		v.violations = append(v.violations, FieldViolation{Field: field, Constraint: constraint})
+	}
+}
+
+// Required records a violation when value is blank
+func (v *Validator) Required(value, field string) {
+	v.Check(strings.TrimSpace(value) != "", field, "must not be empty")
+}
+
+// Err returns a *ValidationError with the recorded violations, or nil when there are none
+func (v *Validator) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// EventType names a domain event published to webhooks and the message broker
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pr.created"
+	EventReviewerAssigned   EventType = "reviewer.assigned"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventPRMerged           EventType = "pr.merged"
+	EventUserDeactivated    EventType = "user.deactivated"
+)
+
+// IsValid reports whether t is a known event type
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventPRCreated, EventReviewerAssigned, EventReviewerReassigned, EventPRMerged, EventUserDeactivated:
+		return true
+	default:
+		return false
+	}
+}
+
+// Event is something that happened to a pull request or a user. PR is set for
+// PR-level events; ReviewerID is the assigned or replacement reviewer (empty when
+// a review was closed without a replacement) and OldReviewerID the replaced one.
+// User events carry UserID and the teams the user belonged to. OrgID is the
+// organization the event happened in.
+type Event struct {
+	Type          EventType
+	OrgID         string
+	OccurredAt    time.Time
+	PullRequestID string
+	PR            *PullRequest
+	ReviewerID    string
+	OldReviewerID string
+	UserID        string
+	TeamNames     []string
+}
+
+// Key identifies the entity the event is about; events with the same key
+// must be consumed in order
+func (e Event) Key() string {
+	if e.PullRequestID != "" {
+		return e.PullRequestID
+	}
+	return e.UserID
+}
+
+// NewPREvent creates a PR-level event
+func NewPREvent(t EventType, pr PullRequest) Event {
+	return Event{
+		Type:          t,
+		OccurredAt:    time.Now(),
+		PullRequestID: pr.PullRequestID,
+		PR:            &pr,
+	}
+}
+
+// NewAssignmentEvents creates a reviewer.assigned event per reviewer
+func NewAssignmentEvents(prID string, reviewerIDs []string) []Event {
+	events := make([]Event, 0, len(reviewerIDs))
+	for _, reviewerID := range reviewerIDs {
+		events = append(events, Event{
+			Type:          EventReviewerAssigned,
+			OccurredAt:    time.Now(),
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+		})
+	}
+	return events
+}
+
+// NewReassignmentEvents creates a reviewer.reassigned event per reassignment
+func NewReassignmentEvents(reassignments []Reassignment) []Event {
+	events := make([]Event, 0, len(reassignments))
+	for _, r := range reassignments {
+		events = append(events, Event{
+			Type:          EventReviewerReassigned,
+			OccurredAt:    time.Now(),
+			PullRequestID: r.PullRequestID,
+			ReviewerID:    r.NewUserID,
+			OldReviewerID: r.OldUserID,
+		})
+	}
+	return events
+}
+
+// NewDeactivationEvents creates a user.deactivated event per user deactivated
+// in the given membership audit entries, listing every team of the user
+func NewDeactivationEvents(membership []MembershipEvent) []Event {
+	var events []Event
+	index := make(map[string]int)
+	for _, m := range membership {
+		if m.Action != MembershipDeactivated {
+			continue
+		}
+		if i, ok := index[m.UserID]; ok {
+			events[i].TeamNames = append(events[i].TeamNames, m.TeamName)
+			continue
+		}
+		index[m.UserID] = len(events)
+		events = append(events, Event{
+			Type:       EventUserDeactivated,
+			OccurredAt: m.CreatedAt,
+			UserID:     m.UserID,
+			TeamNames:  []string{m.TeamName},
+		})
+	}
+	return events
+}
+
+// WebhookSubscription is an outbound webhook registered for a set of event types
+type WebhookSubscription struct {
+	ID         int64
+	URL        string
+	Secret     string
+	EventTypes []EventType
+	CreatedAt  time.Time
+}
+
+// Wants reports whether the subscription receives events of type t
+func (s WebhookSubscription) Wants(t EventType) bool {
+	return slices.Contains(s.EventTypes, t)
+}
+
+// WebhookDeliveryStatus is the lifecycle state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// IsValid reports whether s is a known delivery status
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookDelivery is one event queued for one subscription. A pending delivery
+// is attempted at NextAttemptAt; ResponseStatus is the last HTTP status received.
+// URL and Secret are copied from the subscription when the delivery is claimed.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	URL            string
+	Secret         string
+	EventType      EventType
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	ResponseStatus *int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
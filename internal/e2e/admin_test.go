@@ -0,0 +1,234 @@
+package e2e
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/auth"
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/handler"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service/audit"
+)
+
+func TestHTTPE2EAdminAllowlist(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	s := newTestServer(t)
+	defer s.Close()
+
+	// The allowlist runs before authentication, as the app chains it
+	log := zap.NewNop()
+	serve := func(cidrs ...string) {
+		allowed, err := middleware.ParseAllowlist(cidrs)
+		if err != nil {
+			t.Fatalf("failed to parse allowlist: %v", err)
+		}
+		mux := http.NewServeMux()
+		admin := map[string]bool{"POST /team/delete": true, "POST /v1/team/delete": true}
+		handleAPI(mux, "POST /team/delete", s.server.Config.Handler.ServeHTTP)
+		mux.Handle("/", s.server.Config.Handler)
+		oidc := auth.NewOIDC(provider.URL, "pr-service", "", "", nil, 0)
+		srv := httptest.NewServer(middleware.AdminAllowlist(allowed, mux, admin, log)(middleware.Authenticate(oidc, log)(mux)))
+		t.Cleanup(srv.Close)
+		s.base, s.client = srv.URL, srv.Client()
+	}
+	deleteTeam := func(header http.Header, expectedStatus int) middleware.ErrorResponse {
+		t.Helper()
+		var resp middleware.ErrorResponse
+		header.Set("Content-Type", "application/json")
+		s.postWithHeaders("/v1/team/delete", header, strings.NewReader(`{"team_name":"backend"}`), expectedStatus, &resp)
+		return resp
+	}
+
+	// Clients outside the allowlist are refused before their token is checked
+	serve("10.0.0.0/8", "192.168.1.10")
+	if resp := deleteTeam(http.Header{}, http.StatusForbidden); resp.Error.Code != "FORBIDDEN" {
+		t.Fatalf("expected FORBIDDEN, got %+v", resp)
+	}
+	s.getJSON("/health", http.StatusOK, nil)
+	s.getJSON("/team/get?team_name=backend", http.StatusUnauthorized, nil)
+
+	// Allowed clients go on to authentication
+	serve("127.0.0.0/8", "::1")
+	if resp := deleteTeam(http.Header{}, http.StatusUnauthorized); resp.Error.Code != "UNAUTHORIZED" {
+		t.Fatalf("expected UNAUTHORIZED, got %+v", resp)
+	}
+
+	if _, err := middleware.ParseAllowlist([]string{"10.0.0.0/33"}); err == nil {
+		t.Fatal("expected an invalid CIDR to be rejected")
+	}
+}
+
+func TestHTTPE2EAuditLog(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	s := newTestServer(t)
+	defer s.Close()
+
+	// Mutating routes are audited outside their role checks, as the app registers them
+	log := zap.NewNop()
+	auditService := audit.NewService(memory.NewAuditLogRepository())
+	auditHandler := handler.NewAuditHandler(auditService, log)
+	mux := http.NewServeMux()
+	handleAPI(mux, "POST /team/add", middleware.Audit(auditService, "POST /team/add", log)(
+		middleware.RequireRole(domain.RoleAdmin, log)(s.server.Config.Handler)).ServeHTTP)
+	handleAPI(mux, "POST /pullRequest/create", middleware.Audit(auditService, "POST /pullRequest/create", log)(s.server.Config.Handler).ServeHTTP)
+	mux.Handle("GET /admin/audit", middleware.RequireRole(domain.RoleAdmin, log)(http.HandlerFunc(auditHandler.List)))
+	mux.Handle("/", s.server.Config.Handler)
+	oidc := auth.NewOIDC(provider.URL, "pr-service", "", "", nil, 0)
+	authed := httptest.NewServer(middleware.RequestID(log)(middleware.Authenticate(oidc, log)(mux)))
+	defer authed.Close()
+	s.base, s.client = authed.URL, authed.Client()
+
+	token := func(sub string, roles any) string {
+		claims := map[string]any{"iss": provider.URL, "aud": "pr-service", "sub": sub, "exp": time.Now().Add(time.Hour).Unix()}
+		if roles != nil {
+			claims["roles"] = roles
+		}
+		return provider.sign(t, "RS256", "rsa-1", claims)
+	}
+	admin := token("root", []string{"admin"})
+	member := token("u2", nil)
+	postAs := func(token, path string, body []byte, expectedStatus int) {
+		t.Helper()
+		s.postWithHeaders(path, http.Header{"Content-Type": {"application/json"}, "Authorization": {"Bearer " + token}},
+			bytes.NewReader(body), expectedStatus, nil)
+	}
+	listAs := func(token, query string, expectedStatus int) auditLogPage {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, s.base+"/admin/audit"+query, nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := s.client.Do(req)
+		if err != nil {
+			t.Fatalf("list audit log: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != expectedStatus {
+			t.Fatalf("expected status %d from audit log, got %d", expectedStatus, resp.StatusCode)
+		}
+		var out auditLogPage
+		if expectedStatus == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode audit log: %v", err)
+			}
+		}
+		return out
+	}
+
+	teamBody := []byte(`{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true},` +
+		`{"user_id":"u2","username":"Bob","is_active":true},{"user_id":"u3","username":"Carol","is_active":true}]}`)
+	postAs(member, "/team/add", teamBody, http.StatusForbidden)
+	postAs(admin, "/v1/team/add", teamBody, http.StatusCreated)
+	postAs(member, "/pullRequest/create", []byte(`{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u2"}`), http.StatusCreated)
+	postAs(member, "/pullRequest/create", []byte(`{"pull_request_id":"pr-1","pull_request_name":"Again","author_id":"u2"}`), http.StatusConflict)
+	// Reads are not audited
+	s.getJSON("/team/get?team_name=backend", http.StatusUnauthorized, nil)
+
+	// Only admins read the log, newest entries first
+	listAs(member, "", http.StatusForbidden)
+	all := listAs(admin, "", http.StatusOK)
+	if all.Total != 4 || len(all.Entries) != 4 {
+		t.Fatalf("expected 4 audit entries, got %+v", all)
+	}
+	sum := sha256.Sum256(teamBody)
+	created, rejected := all.Entries[2], all.Entries[3]
+	if created.Actor != "root" || created.Route != "/team/add" || created.Path != "/v1/team/add" || created.Status != http.StatusCreated ||
+		created.PayloadHash != hex.EncodeToString(sum[:]) || created.ErrorCode != "" || created.RequestID == "" {
+		t.Fatalf("unexpected entry of the created team: %+v", created)
+	}
+	if rejected.Actor != "u2" || rejected.Status != http.StatusForbidden || rejected.ErrorCode != "FORBIDDEN" || rejected.PayloadHash != created.PayloadHash {
+		t.Fatalf("unexpected entry of the rejected call: %+v", rejected)
+	}
+	if conflict := all.Entries[0]; conflict.Route != "/pullRequest/create" || conflict.ErrorCode != "PR_EXISTS" {
+		t.Fatalf("unexpected entry of the duplicate PR: %+v", conflict)
+	}
+
+	// Entries are filtered by caller and outcome and paged with cursors
+	failed := listAs(admin, "?actor=u2&failed=true", http.StatusOK)
+	if failed.Total != 2 || failed.Entries[0].ErrorCode != "PR_EXISTS" || failed.Entries[1].ErrorCode != "FORBIDDEN" {
+		t.Fatalf("unexpected failed calls of u2: %+v", failed)
+	}
+	first := listAs(admin, "?route=/pullRequest/create&limit=1&order=asc", http.StatusOK)
+	if first.Total != 2 || len(first.Entries) != 1 || first.Entries[0].Status != http.StatusCreated || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	second := listAs(admin, "?route=/pullRequest/create&limit=1&order=asc&cursor="+first.NextCursor, http.StatusOK)
+	if len(second.Entries) != 1 || second.Entries[0].Status != http.StatusConflict || second.NextCursor != "" {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+	listAs(admin, "?from=yesterday", http.StatusBadRequest)
+}
+
+type auditLogPage struct {
+	Entries    []handler.AuditEntryDTO `json:"entries"`
+	Total      int                     `json:"total"`
+	NextCursor string                  `json:"next_cursor"`
+}
+
+// stubPools reports fixed pool stats
+type stubPools struct {
+	primary db.PoolStats
+	replica *db.PoolStats
+}
+
+func (p stubPools) PoolStats() db.PoolStats { return p.primary }
+
+func (p stubPools) ReplicaPoolStats() (db.PoolStats, bool) {
+	if p.replica == nil {
+		return db.PoolStats{}, false
+	}
+	return *p.replica, true
+}
+
+func TestHTTPE2EDBPool(t *testing.T) {
+	pools := stubPools{primary: db.PoolStats{
+		AcquiredConns:    3,
+		IdleConns:        1,
+		TotalConns:       4,
+		MaxConns:         4,
+		Acquires:         100,
+		EmptyAcquires:    4,
+		EmptyAcquireWait: 20 * time.Millisecond,
+	}}
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /admin/db/pool", handler.NewDBPoolHandler(&pools).Get)
+	srv := httptest.NewServer(admin)
+	defer srv.Close()
+	s := &testServer{t: t, base: srv.URL, client: srv.Client()}
+
+	type poolsResponse struct {
+		Pools []handler.DBPoolDTO `json:"pools"`
+	}
+	var got poolsResponse
+	s.getJSON("/admin/db/pool", http.StatusOK, &got)
+	if len(got.Pools) != 1 {
+		t.Fatalf("expected only the primary pool, got %+v", got.Pools)
+	}
+	primary := got.Pools[0]
+	if primary.Name != "primary" || primary.Saturation != 0.75 || primary.EmptyAcquires != 4 || primary.AvgEmptyAcquireWaitMs != 5 {
+		t.Fatalf("unexpected primary pool: %+v", primary)
+	}
+
+	pools.replica = &db.PoolStats{MaxConns: 8}
+	s.getJSON("/admin/db/pool", http.StatusOK, &got)
+	if len(got.Pools) != 2 || got.Pools[1].Name != "replica" || got.Pools[1].MaxConns != 8 {
+		t.Fatalf("expected the replica pool after the primary, got %+v", got.Pools)
+	}
+}
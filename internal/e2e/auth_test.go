@@ -0,0 +1,498 @@
+package e2e
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/auth"
+	"pr-service/internal/domain"
+	"pr-service/internal/handler"
+	"pr-service/internal/maintenance"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service/teamtoken"
+)
+
+func TestHTTPE2ERoleBasedAccess(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	s := newTestServer(t)
+	defer s.Close()
+	for team, members := range map[string][]string{"backend": {"u1", "u2", "u3"}, "platform": {"p1", "p2"}} {
+		var body []testMember
+		for _, id := range members {
+			body = append(body, activeMember(id, id))
+		}
+		s.addTeam(team, body...)
+	}
+
+	// Routes get their minimum role as the app assigns them
+	log := zap.NewNop()
+	mux := http.NewServeMux()
+	mux.Handle("POST /team/delete", middleware.RequireRole(domain.RoleAdmin, log)(s.server.Config.Handler))
+	mux.Handle("POST /users/deactivateTeamMembers", middleware.RequireRole(domain.RoleLead, log)(s.server.Config.Handler))
+	mux.Handle("/", s.server.Config.Handler)
+	oidc := auth.NewOIDC(provider.URL, "pr-service", "", "", nil, 0)
+	authed := httptest.NewServer(middleware.Authenticate(oidc, log)(mux))
+	defer authed.Close()
+	s.base, s.client = authed.URL, authed.Client()
+
+	token := func(sub string, roles any) string {
+		claims := map[string]any{"iss": provider.URL, "aud": "pr-service", "sub": sub, "exp": time.Now().Add(time.Hour).Unix()}
+		if roles != nil {
+			claims["roles"] = roles
+		}
+		return provider.sign(t, "RS256", "rsa-1", claims)
+	}
+	admin := token("root", []string{"admin"})
+	lead := token("u1", "lead")
+	member := token("u2", nil)
+	postAs := func(token, path string, body any, expectedStatus int) {
+		t.Helper()
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		var errResp middleware.ErrorResponse
+		var out any
+		if expectedStatus == http.StatusForbidden {
+			out = &errResp
+		}
+		s.postWithHeaders(path, http.Header{"Content-Type": {"application/json"}, "Authorization": {"Bearer " + token}},
+			bytes.NewReader(data), expectedStatus, out)
+		if expectedStatus == http.StatusForbidden && errResp.Error.Code != "FORBIDDEN" {
+			t.Fatalf("expected FORBIDDEN from %s, got %+v", path, errResp)
+		}
+	}
+
+	// Members cannot run lead routes, and leads only act on their own team
+	postAs(member, "/users/deactivateTeamMembers", map[string]any{"team_name": "backend", "user_ids": []string{"u3"}}, http.StatusForbidden)
+	postAs(lead, "/v1/users/deactivateTeamMembers", map[string]any{"team_name": "platform", "user_ids": []string{"p2"}}, http.StatusForbidden)
+	postAs(lead, "/users/deactivateTeamMembers", map[string]any{"team_name": "backend", "user_ids": []string{"u3"}}, http.StatusOK)
+	postAs(lead, "/users/setIsActive", map[string]any{"user_id": "p2", "is_active": false}, http.StatusForbidden)
+	postAs(lead, "/users/setIsActive", map[string]any{"user_id": "u3", "is_active": true}, http.StatusOK)
+	postAs(lead, "/team/setSettings", map[string]any{"team_name": "platform"}, http.StatusForbidden)
+
+	// Only admins delete teams, and they act on any team
+	postAs(lead, "/team/delete", map[string]string{"team_name": "platform"}, http.StatusForbidden)
+	postAs(admin, "/users/deactivateTeamMembers", map[string]any{"team_name": "platform", "user_ids": []string{"p2"}}, http.StatusOK)
+	postAs(admin, "/team/delete", map[string]string{"team_name": "platform", "target_team_name": "backend"}, http.StatusOK)
+
+	// Routes without a role are open to every caller
+	postAs(member, "/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u2",
+	}, http.StatusCreated)
+}
+
+func TestHTTPE2ETeamTokens(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	s := newTestServer(t)
+	defer s.Close()
+	for team, members := range map[string][]string{"backend": {"u1", "u2", "u3"}, "platform": {"p1", "p2", "p3"}} {
+		var body []testMember
+		for _, id := range members {
+			body = append(body, activeMember(id, id))
+		}
+		s.addTeam(team, body...)
+	}
+	s.createPR("pr-p1", "Platform change", "p1")
+
+	log := zap.NewNop()
+	tokens := teamtoken.NewService(memory.NewTeamTokenRepository(), s.teamRepo, s.userRepo)
+	tokenHandler := handler.NewTeamTokenHandler(tokens, log)
+	mux := http.NewServeMux()
+	mux.Handle("POST /team/tokens/issue", middleware.RequireRole(domain.RoleLead, log)(http.HandlerFunc(tokenHandler.Issue)))
+	mux.Handle("GET /team/tokens/list", middleware.RequireRole(domain.RoleLead, log)(http.HandlerFunc(tokenHandler.List)))
+	mux.Handle("POST /team/tokens/revoke", middleware.RequireRole(domain.RoleLead, log)(http.HandlerFunc(tokenHandler.Revoke)))
+	mux.Handle("/", s.server.Config.Handler)
+	oidc := auth.NewOIDC(provider.URL, "pr-service", "", "", nil, 0)
+	authed := httptest.NewServer(middleware.Authenticate(auth.NewPrefixed(domain.TeamTokenPrefix, tokens, oidc), log)(mux))
+	defer authed.Close()
+	s.base, s.client = authed.URL, authed.Client()
+
+	lead := provider.sign(t, "RS256", "rsa-1", map[string]any{
+		"iss": provider.URL, "aud": "pr-service", "sub": "u1", "roles": "lead", "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	postAs := func(token, path string, body any, expectedStatus int, out any) {
+		t.Helper()
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		s.postWithHeaders(path, http.Header{"Content-Type": {"application/json"}, "Authorization": {"Bearer " + token}},
+			bytes.NewReader(data), expectedStatus, out)
+	}
+
+	// Leads issue tokens for their own team only
+	postAs(lead, "/team/tokens/issue", map[string]string{"team_name": "platform", "name": "ci"}, http.StatusForbidden, nil)
+	var issued struct {
+		Token handler.TeamTokenDTO `json:"token"`
+	}
+	postAs(lead, "/team/tokens/issue", map[string]string{"team_name": "backend", "name": "ci", "expires_in": "24h"}, http.StatusCreated, &issued)
+	if !strings.HasPrefix(issued.Token.Token, domain.TeamTokenPrefix) || issued.Token.TeamName != "backend" || issued.Token.ExpiresAt == "" {
+		t.Fatalf("unexpected issued token: %+v", issued.Token)
+	}
+	bot := issued.Token.Token
+
+	// The token acts within its team and is refused on other teams' PRs
+	postAs(bot, "/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-b1", "pull_request_name": "Bot change", "author_id": "u2",
+	}, http.StatusCreated, nil)
+	postAs(bot, "/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-p2", "pull_request_name": "Foreign change", "author_id": "p2",
+	}, http.StatusForbidden, nil)
+	postAs(bot, "/pullRequest/reassign", map[string]string{"pull_request_id": "pr-p1", "old_user_id": "p2"}, http.StatusForbidden, nil)
+	postAs(bot, "/pullRequest/merge", map[string]string{"pull_request_id": "pr-p1"}, http.StatusForbidden, nil)
+	postAs(bot, "/pullRequest/merge", map[string]string{"pull_request_id": "pr-b1"}, http.StatusOK, nil)
+
+	// Team tokens hold the member role and cannot manage tokens
+	postAs(bot, "/team/tokens/issue", map[string]string{"team_name": "backend", "name": "other"}, http.StatusForbidden, nil)
+
+	// The secret is never listed, tokens are paged oldest first, and revoked
+	// tokens stop authenticating
+	postAs(lead, "/team/tokens/issue", map[string]string{"team_name": "backend", "name": "deploy"}, http.StatusCreated, nil)
+	type tokenPage struct {
+		Tokens     []handler.TeamTokenDTO `json:"tokens"`
+		Total      int                    `json:"total"`
+		NextCursor string                 `json:"next_cursor"`
+	}
+	listAs := func(token, query string) tokenPage {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, s.base+"/team/tokens/list?team_name=backend"+query, nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := s.client.Do(req)
+		if err != nil {
+			t.Fatalf("list tokens: %v", err)
+		}
+		var page tokenPage
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected token list: status %d, %+v, %v", resp.StatusCode, page, err)
+		}
+		return page
+	}
+	first := listAs(lead, "&limit=1")
+	if first.Total != 2 || len(first.Tokens) != 1 || first.Tokens[0].Name != "ci" || first.Tokens[0].Token != "" || first.NextCursor == "" {
+		t.Fatalf("unexpected first token page: %+v", first)
+	}
+	second := listAs(lead, "&limit=1&cursor="+first.NextCursor)
+	if len(second.Tokens) != 1 || second.Tokens[0].Name != "deploy" || second.Tokens[0].Token != "" || second.NextCursor != "" {
+		t.Fatalf("unexpected second token page: %+v", second)
+	}
+	if newest := listAs(lead, "&order=desc"); len(newest.Tokens) != 2 || newest.Tokens[0].Name != "deploy" {
+		t.Fatalf("expected the newest token first, got %+v", newest)
+	}
+	postAs(lead, "/team/tokens/revoke", map[string]int64{"id": issued.Token.ID}, http.StatusOK, nil)
+	postAs(bot, "/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-b2", "pull_request_name": "After revoke", "author_id": "u2",
+	}, http.StatusUnauthorized, nil)
+	postAs(domain.TeamTokenPrefix+"unknown", "/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-b2", "pull_request_name": "Unknown", "author_id": "u2",
+	}, http.StatusUnauthorized, nil)
+}
+
+func TestHTTPE2EOIDCAuth(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	s := newTestServer(t)
+	defer s.Close()
+	oidc := auth.NewOIDC(provider.URL, "pr-service", "", "", map[string]string{"alice-sub": "u1"}, 0)
+	authed := httptest.NewServer(middleware.Authenticate(oidc, zap.NewNop())(s.server.Config.Handler))
+	defer authed.Close()
+	s.base, s.client = authed.URL, authed.Client()
+
+	now := time.Now()
+	alice := provider.sign(t, "RS256", "rsa-1", map[string]any{
+		"iss": provider.URL, "aud": "pr-service", "sub": "alice-sub", "exp": now.Add(time.Hour).Unix(),
+	})
+	bob := provider.sign(t, "ES256", "ec-1", map[string]any{
+		"iss": provider.URL, "aud": []string{"other", "pr-service"}, "sub": "u2", "exp": now.Add(time.Hour).Unix(),
+	})
+	bearer := func(token string) http.Header {
+		return http.Header{"Content-Type": {"application/json"}, "Authorization": {"Bearer " + token}}
+	}
+	postAs := func(token, path string, body any, expectedStatus int, out any) {
+		t.Helper()
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		s.postWithHeaders(path, bearer(token), bytes.NewReader(data), expectedStatus, out)
+	}
+
+	postAs(alice, "/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Carol", "is_active": true},
+		},
+	}, http.StatusCreated, nil)
+	postAs(alice, "/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1",
+	}, http.StatusCreated, nil)
+
+	// API routes need a token; probes, metrics and integration webhooks do not
+	resp, err := s.client.Get(s.base + "/team/get?team_name=backend")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != "Bearer" {
+		t.Fatalf("expected 401 with a Bearer challenge, got %d %q", resp.StatusCode, resp.Header.Get("WWW-Authenticate"))
+	}
+	s.getJSON("/health", http.StatusOK, nil)
+	s.postGitHubEvent("ping", testGitHubSecret, map[string]any{"zen": "hi"}, http.StatusOK, nil)
+	s.postWithHeaders("/v1/integrations/generic/unknown/webhook", http.Header{"Content-Type": {"application/json"}},
+		strings.NewReader(`{}`), http.StatusNotFound, nil)
+	resp, err = s.client.Get(s.base + "/v1/team/get?team_name=backend")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected versioned API routes to need a token, got %d", resp.StatusCode)
+	}
+
+	// Self-service endpoints act as the caller and refuse to act for anyone else
+	var review struct {
+		UserID string `json:"user_id"`
+	}
+	postAs(bob, "/pullRequest/review", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, &review)
+	if review.UserID != "u2" {
+		t.Fatalf("expected the review to be recorded for u2, got %q", review.UserID)
+	}
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	postAs(bob, "/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u3"}, http.StatusForbidden, &errResp)
+	if errResp.Error.Code != "FORBIDDEN" {
+		t.Fatalf("expected FORBIDDEN, got %+v", errResp)
+	}
+	postAs(bob, "/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u2"}, http.StatusOK, nil)
+
+	var heartbeat struct {
+		User struct {
+			UserID     string     `json:"user_id"`
+			LastSeenAt *time.Time `json:"last_seen_at"`
+		} `json:"user"`
+	}
+	postAs(alice, "/users/heartbeat", map[string]string{}, http.StatusOK, &heartbeat)
+	if heartbeat.User.UserID != "u1" || heartbeat.User.LastSeenAt == nil {
+		t.Fatalf("expected the mapped subject u1 to be marked as seen, got %+v", heartbeat.User)
+	}
+	postAs(alice, "/users/heartbeat", map[string]string{"user_id": "u2"}, http.StatusForbidden, nil)
+
+	// NumericDate claims may be fractional
+	fractional := provider.sign(t, "RS256", "rsa-1", map[string]any{
+		"iss": provider.URL, "aud": "pr-service", "sub": "alice-sub",
+		"exp": float64(now.Add(time.Hour).UnixMilli()) / 1000, "nbf": float64(now.Add(-time.Minute).UnixMilli()) / 1000,
+	})
+	postAs(fractional, "/users/heartbeat", map[string]string{}, http.StatusOK, nil)
+
+	// Keys are cached across tokens signed with known keys
+	if fetches := provider.jwksFetches(); fetches != 1 {
+		t.Fatalf("expected the JWKS to be fetched once, got %d", fetches)
+	}
+
+	// Tokens that are expired, for another audience or issuer, unsigned or tampered with are rejected
+	rejected := map[string]string{
+		"expired": provider.sign(t, "RS256", "rsa-1", map[string]any{
+			"iss": provider.URL, "aud": "pr-service", "sub": "u2", "exp": now.Add(-time.Hour).Unix(),
+		}),
+		"audience": provider.sign(t, "RS256", "rsa-1", map[string]any{
+			"iss": provider.URL, "aud": "other", "sub": "u2", "exp": now.Add(time.Hour).Unix(),
+		}),
+		"issuer": provider.sign(t, "RS256", "rsa-1", map[string]any{
+			"iss": "https://evil.example.com", "aud": "pr-service", "sub": "u2", "exp": now.Add(time.Hour).Unix(),
+		}),
+		"no expiry": provider.sign(t, "RS256", "rsa-1", map[string]any{
+			"iss": provider.URL, "aud": "pr-service", "sub": "u2",
+		}),
+		"unknown key": provider.sign(t, "RS256", "rsa-2", map[string]any{
+			"iss": provider.URL, "aud": "pr-service", "sub": "u2", "exp": now.Add(time.Hour).Unix(),
+		}),
+		"alg none":    unsignedToken(t, map[string]any{"iss": provider.URL, "aud": "pr-service", "sub": "u2", "exp": now.Add(time.Hour).Unix()}),
+		"alg mixup":   provider.sign(t, "ES256", "rsa-1", map[string]any{"iss": provider.URL, "aud": "pr-service", "sub": "u2", "exp": now.Add(time.Hour).Unix()}),
+		"tampered":    alice[:strings.LastIndex(alice, ".")] + "." + bob[strings.LastIndex(bob, ".")+1:],
+		"not a token": "opaque-token",
+	}
+	for name, token := range rejected {
+		req, err := http.NewRequest(http.MethodPost, s.base+"/users/heartbeat", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header = bearer(token)
+		resp, err := s.client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected the %s token to be rejected, got %d", name, resp.StatusCode)
+		}
+	}
+
+}
+
+func TestHTTPE2EOrganizations(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	s := newTestServer(t)
+	defer s.Close()
+
+	// Deployment routes are limited to the default organization, as the app registers them
+	log := zap.NewNop()
+	sw, err := maintenance.NewSwitch("", "", 0)
+	if err != nil {
+		t.Fatalf("failed to create maintenance switch: %v", err)
+	}
+	maintenanceHandler := handler.NewMaintenanceHandler(sw, log)
+	mux := http.NewServeMux()
+	handleAPI(mux, "POST /admin/maintenance", middleware.RequireDefaultOrg(log)(http.HandlerFunc(maintenanceHandler.Set)).ServeHTTP)
+	mux.Handle("/", s.server.Config.Handler)
+	oidc := auth.NewOIDC(provider.URL, "pr-service", "", "", nil, 0, auth.WithOrgClaim("org"))
+	authed := httptest.NewServer(middleware.Authenticate(oidc, log)(mux))
+	defer authed.Close()
+	s.base, s.client = authed.URL, authed.Client()
+
+	token := func(org any) string {
+		claims := map[string]any{"iss": provider.URL, "aud": "pr-service", "sub": "root", "roles": "admin", "exp": time.Now().Add(time.Hour).Unix()}
+		if org != nil {
+			claims["org"] = org
+		}
+		return provider.sign(t, "RS256", "rsa-1", claims)
+	}
+	postAs := func(token, path string, body any, expectedStatus int) {
+		t.Helper()
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		s.postWithHeaders(path, http.Header{"Content-Type": {"application/json"}, "Authorization": {"Bearer " + token}},
+			bytes.NewReader(data), expectedStatus, nil)
+	}
+
+	// Tokens must name a valid organization once the claim is configured
+	for _, org := range []any{nil, "", 42, "*"} {
+		postAs(token(org), "/users/heartbeat", map[string]string{}, http.StatusUnauthorized)
+	}
+
+	postAs(token("acme"), "/admin/maintenance", map[string]any{"mode": "off"}, http.StatusForbidden)
+	postAs(token("acme"), "/v1/admin/maintenance", map[string]any{"mode": "off"}, http.StatusForbidden)
+	postAs(token(domain.DefaultOrg), "/admin/maintenance", map[string]any{"mode": "off"}, http.StatusOK)
+}
+
+// fakeOIDCProvider serves a discovery document and a JWKS with one RSA and one EC signing key
+type fakeOIDCProvider struct {
+	*httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+
+	mu      sync.Mutex
+	fetches int
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(crand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatalf("generate EC key: %v", err)
+	}
+	p := &fakeOIDCProvider{rsaKey: rsaKey, ecKey: ecKey}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": p.URL, "jwks_uri": p.URL + "/keys"})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, _ *http.Request) {
+		p.mu.Lock()
+		p.fetches++
+		p.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+				"n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec-1", "use": "sig", "crv": "P-256",
+				"x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+			{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": b64(rsaKey.N.Bytes()), "e": "AQAB"},
+		}})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *fakeOIDCProvider) jwksFetches() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fetches
+}
+
+// sign issues a JWT; RS algorithms use the RSA key and ES ones the EC key
+func (p *fakeOIDCProvider) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	signed := jwtSegment(t, map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + jwtSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	var err error
+	if strings.HasPrefix(alg, "RS") {
+		signature, err = rsa.SignPKCS1v15(crand.Reader, p.rsaKey, crypto.SHA256, digest[:])
+	} else {
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(crand.Reader, p.ecKey, digest[:])
+		if err == nil {
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	}
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func unsignedToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	return jwtSegment(t, map[string]string{"alg": "none"}) + "." + jwtSegment(t, claims) + "."
+}
+
+func jwtSegment(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal token segment: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,204 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-service/internal/notify"
+)
+
+type channelPost struct {
+	webhookURL string
+	text       string
+}
+
+type recordingChannel struct {
+	mu    sync.Mutex
+	posts []channelPost
+}
+
+func (c *recordingChannel) Post(_ context.Context, webhookURL, text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.posts = append(c.posts, channelPost{webhookURL: webhookURL, text: text})
+	return nil
+}
+
+// take returns and forgets the recorded posts
+func (c *recordingChannel) take() []channelPost {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	posts := c.posts
+	c.posts = nil
+	return posts
+}
+
+// flushChannels posts every queued team channel message, as the notifications worker would
+func (s *testServer) flushChannels() {
+	s.t.Helper()
+	for {
+		select {
+		case event := <-s.channels.Pending():
+			if err := s.channels.Send(context.Background(), event); err != nil {
+				s.t.Fatalf("post to team channel: %v", err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func TestHTTPE2ETeamNotificationChannels(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	for _, team := range []string{"backend", "frontend"} {
+		prefix := team[:1]
+		s.addTeam(team, activeMember(prefix+"1", "Alice"), activeMember(prefix+"2", "Bob"), activeMember(prefix+"3", "Carol"), activeMember(prefix+"4", "Dave"))
+	}
+
+	type settingsResponse struct {
+		Settings struct {
+			TeamName            string `json:"team_name"`
+			NotificationChannel *struct {
+				Type       string `json:"type"`
+				WebhookURL string `json:"webhook_url"`
+			} `json:"notification_channel"`
+		} `json:"settings"`
+	}
+	var settings settingsResponse
+	s.getJSON("/team/settings?team_name=backend", http.StatusOK, &settings)
+	if settings.Settings.TeamName != "backend" || settings.Settings.NotificationChannel != nil {
+		t.Fatalf("expected no channel by default, got %+v", settings)
+	}
+	s.getJSON("/team/settings?team_name=unknown", http.StatusNotFound, nil)
+	s.getJSON("/team/settings", http.StatusBadRequest, nil)
+
+	// unknown type, relative URL, non-HTTP URL, missing URL, missing type
+	for _, channel := range []map[string]string{
+		{"type": "irc", "webhook_url": "https://chat.example.com/hook"},
+		{"type": "slack", "webhook_url": "/hook"},
+		{"type": "slack", "webhook_url": "ftp://chat.example.com/hook"},
+		{"type": "msteams"},
+		{"webhook_url": "https://chat.example.com/hook"},
+	} {
+		s.postJSON("/team/setSettings", map[string]any{"team_name": "backend", "notification_channel": channel}, http.StatusBadRequest, nil)
+	}
+	s.postJSON("/team/setSettings", map[string]any{
+		"team_name":            "unknown",
+		"notification_channel": map[string]string{"type": "slack", "webhook_url": "https://hooks.slack.com/services/T/B/x"},
+	}, http.StatusNotFound, nil)
+
+	s.postJSON("/team/setSettings", map[string]any{
+		"team_name":            "backend",
+		"notification_channel": map[string]string{"type": "MSTeams", "webhook_url": " https://acme.webhook.office.com/hook "},
+	}, http.StatusOK, &settings)
+	if ch := settings.Settings.NotificationChannel; ch == nil || ch.Type != "msteams" || ch.WebhookURL != "https://acme.webhook.office.com/hook" {
+		t.Fatalf("expected the normalized channel to be echoed, got %+v", settings)
+	}
+	settings = settingsResponse{}
+	s.getJSON("/team/settings?team_name=backend", http.StatusOK, &settings)
+	if ch := settings.Settings.NotificationChannel; ch == nil || ch.Type != "msteams" || ch.WebhookURL != "" {
+		t.Fatalf("expected the channel without its webhook URL, got %+v", settings)
+	}
+
+	// Only the PRs of the team with a channel are posted
+	created := s.createPR("pr-1", "Add refunds", "b1")
+	s.createPR("pr-2", "New header", "f1")
+	var reassigned reassignResponse
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     created.PR.AssignedReviewers[0],
+	}, http.StatusOK, &reassigned)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+	s.flushChannels()
+
+	posts := s.posts.take()
+	if len(posts) != 3 {
+		t.Fatalf("expected three posts for the backend PR, got %+v", posts)
+	}
+	for _, post := range posts {
+		if post.webhookURL != "https://acme.webhook.office.com/hook" || !strings.Contains(post.text, `"Add refunds" (pr-1)`) {
+			t.Fatalf("unexpected post %+v", post)
+		}
+	}
+	wantReassign := fmt.Sprintf("reviewer %s was replaced by %s", created.PR.AssignedReviewers[0], reassigned.ReplacedBy)
+	if !strings.Contains(posts[0].text, "is waiting for review") ||
+		!strings.Contains(posts[1].text, wantReassign) || !strings.Contains(posts[2].text, "was merged") {
+		t.Fatalf("expected creation, reassignment and merge posts, got %+v", posts)
+	}
+
+	// The channel follows a renamed team and is removed with null
+	s.postJSON("/team/rename", map[string]string{"team_name": "backend", "new_team_name": "platform"}, http.StatusOK, nil)
+	s.getJSON("/team/settings?team_name=platform", http.StatusOK, &settings)
+	if settings.Settings.NotificationChannel == nil {
+		t.Fatalf("expected the channel to follow the rename, got %+v", settings)
+	}
+	s.postJSON("/team/setSettings", map[string]any{"team_name": "platform", "notification_channel": nil}, http.StatusOK, &settings)
+	if settings.Settings.NotificationChannel != nil {
+		t.Fatalf("expected the channel to be removed, got %+v", settings)
+	}
+	s.createPR("pr-3", "Refund emails", "b2")
+	s.flushChannels()
+	if posts := s.posts.take(); len(posts) != 0 {
+		t.Fatalf("expected no posts after removing the channel, got %+v", posts)
+	}
+}
+
+func TestHTTPE2ETeamChannelClients(t *testing.T) {
+	payloads := make(map[string]map[string]any)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&payload) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/revoked" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("no_service"))
+			return
+		}
+		payloads[r.URL.Path] = payload
+	}))
+	defer api.Close()
+
+	ctx := context.Background()
+	if err := notify.NewSlackChannel(0).Post(ctx, api.URL+"/slack", "PR merged"); err != nil {
+		t.Fatalf("post to slack: %v", err)
+	}
+	if err := notify.NewMSTeamsChannel(0).Post(ctx, api.URL+"/msteams", "PR merged"); err != nil {
+		t.Fatalf("post to msteams: %v", err)
+	}
+	if err := notify.NewMattermostChannel(0).Post(ctx, api.URL+"/mattermost", "PR merged"); err != nil {
+		t.Fatalf("post to mattermost: %v", err)
+	}
+
+	if payloads["/slack"]["text"] != "PR merged" {
+		t.Fatalf("unexpected slack payload %+v", payloads["/slack"])
+	}
+	if payloads["/mattermost"]["text"] != "PR merged" || payloads["/mattermost"]["username"] != "pr-service" {
+		t.Fatalf("unexpected mattermost payload %+v", payloads["/mattermost"])
+	}
+	card, _ := json.Marshal(payloads["/msteams"])
+	if payloads["/msteams"]["type"] != "message" ||
+		!strings.Contains(string(card), `"contentType":"application/vnd.microsoft.card.adaptive"`) ||
+		!strings.Contains(string(card), `"text":"PR merged"`) {
+		t.Fatalf("unexpected msteams payload %s", card)
+	}
+
+	err := notify.NewSlackChannel(0).Post(ctx, api.URL+"/revoked", "PR merged")
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "no_service") {
+		t.Fatalf("expected the webhook error to be returned, got %v", err)
+	}
+	err = notify.NewSlackChannel(time.Second).Post(ctx, "http://127.0.0.1:1/services/secret-token", "PR merged")
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("expected a connection error without the webhook URL, got %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-service/internal/ldap"
+	"pr-service/internal/service/directory"
+)
+
+func TestHTTPE2EDirectorySync(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+	s.postJSON("/users/setRole", map[string]string{"team_name": "backend", "user_id": "u1", "role": "lead"}, http.StatusOK, nil)
+	created := s.createPR("pr-1", "Add refunds", "u1")
+	gone := created.PR.AssignedReviewers[0]
+
+	// the reviewer has left the company: they are missing from the directory,
+	// while Eve joined backend and the new platform group
+	dir := newFakeLDAPServer(t, "cn=sync,dc=example,dc=com", "secret")
+	defer dir.Close()
+	var backend []string
+	for _, id := range []string{"u1", "u2", "u3", "u4"} {
+		if id != gone {
+			dir.addUser(id, "User "+id)
+			backend = append(backend, "uid="+id+",ou=people,dc=example,dc=com")
+		}
+	}
+	dir.addUser("u5", "Eve")
+	dir.addGroup("backend", append(backend, "UID=u5, OU=People, DC=example, DC=com", "cn=nested,ou=teams,dc=example,dc=com")...)
+	dir.addGroup("platform", "uid=u5,ou=people,dc=example,dc=com")
+
+	client := ldap.NewClient("ldap://"+dir.addr(), "cn=sync,dc=example,dc=com", "secret", time.Second)
+	sync := directory.NewService(ldap.NewDirectory(client, ldap.DirectoryConfig{
+		UserBaseDN:  "ou=people,dc=example,dc=com",
+		GroupBaseDN: "ou=teams,dc=example,dc=com",
+	}), s.teams, s.users)
+
+	ctx := context.Background()
+	result, err := sync.Sync(ctx)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if result.TeamsSynced != 2 || !slices.Equal(result.TeamsCreated, []string{"platform"}) ||
+		!slices.Equal(result.Deactivated, []string{gone}) || len(result.Reassignments) != 1 ||
+		result.Reassignments[0].PullRequestID != "pr-1" || result.Reassignments[0].OldUserID != gone {
+		t.Fatalf("unexpected sync result %+v", result)
+	}
+	// users come in two pages
+	want := []string{"(objectClass=inetOrgPerson)", "(objectClass=inetOrgPerson)", "(objectClass=groupOfNames)"}
+	if filters := dir.searchFilters(); !slices.Equal(filters, want) {
+		t.Fatalf("expected searches %v, got %v", want, filters)
+	}
+
+	type teamMember struct {
+		UserID   string `json:"user_id"`
+		Username string `json:"username"`
+		IsActive bool   `json:"is_active"`
+		Role     string `json:"role"`
+	}
+	var team struct {
+		Members []teamMember `json:"members"`
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusOK, &team)
+	members := make(map[string]teamMember)
+	for _, m := range team.Members {
+		members[m.UserID] = m
+	}
+	if _, ok := members[gone]; ok || len(members) != 4 || members["u5"].Username != "Eve" || !members["u5"].IsActive {
+		t.Fatalf("expected backend to match the directory, got %+v", team.Members)
+	}
+	if members["u1"].Role != "lead" || members["u1"].Username != "User u1" {
+		t.Fatalf("expected u1 to keep the lead role and take the directory name, got %+v", members["u1"])
+	}
+	s.getJSON("/team/get?team_name=platform", http.StatusOK, &team)
+	if len(team.Members) != 1 || team.Members[0].UserID != "u5" {
+		t.Fatalf("expected platform to be created with Eve, got %+v", team.Members)
+	}
+
+	// A local deactivation survives later syncs, which change nothing else
+	s.postJSON("/users/setIsActive", map[string]any{"user_id": "u5", "is_active": false}, http.StatusOK, nil)
+	result, err = sync.Sync(ctx)
+	if err != nil || result.TeamsSynced != 2 || len(result.TeamsCreated) != 0 || len(result.Deactivated) != 0 {
+		t.Fatalf("expected a no-op sync, got %+v (%v)", result, err)
+	}
+	s.getJSON("/team/get?team_name=platform", http.StatusOK, &team)
+	if team.Members[0].IsActive {
+		t.Fatalf("expected Eve to stay deactivated, got %+v", team.Members[0])
+	}
+
+	// An empty directory is refused instead of deactivating everybody
+	empty := newFakeLDAPServer(t, "", "")
+	defer empty.Close()
+	emptySync := directory.NewService(ldap.NewDirectory(ldap.NewClient("ldap://"+empty.addr(), "", "", time.Second), ldap.DirectoryConfig{}), s.teams, s.users)
+	if _, err := emptySync.Sync(ctx); err == nil || !strings.Contains(err.Error(), "no groups") {
+		t.Fatalf("expected an empty directory to be refused, got %v", err)
+	}
+
+	denied := directory.NewService(ldap.NewDirectory(ldap.NewClient("ldap://"+dir.addr(), "cn=sync,dc=example,dc=com", "wrong", time.Second), ldap.DirectoryConfig{}), s.teams, s.users)
+	if _, err := denied.Sync(ctx); err == nil || !strings.Contains(err.Error(), "LDAP Result Code 49") {
+		t.Fatalf("expected invalid credentials to be reported, got %v", err)
+	}
+}
+
+// fakeLDAPServer answers simple binds and subtree searches from an in-memory
+// directory, returning user entries in two pages to exercise paging
+type fakeLDAPServer struct {
+	t        *testing.T
+	listener net.Listener
+	bindDN   string
+	password string
+
+	mu      sync.Mutex
+	users   [][2]string
+	groups  map[string][]string
+	filters []string
+}
+
+func newFakeLDAPServer(t *testing.T, bindDN, password string) *fakeLDAPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeLDAPServer{t: t, listener: listener, bindDN: bindDN, password: password, groups: make(map[string][]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeLDAPServer) addr() string { return s.listener.Addr().String() }
+
+func (s *fakeLDAPServer) Close() { s.listener.Close() }
+
+func (s *fakeLDAPServer) addUser(uid, cn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = append(s.users, [2]string{uid, cn})
+}
+
+func (s *fakeLDAPServer) addGroup(cn string, members ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups[cn] = members
+}
+
+// searchFilters returns the equality filters of the searches seen so far
+func (s *fakeLDAPServer) searchFilters() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.filters)
+}
+
+func (s *fakeLDAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		tag, content, err := readBER(r)
+		if err != nil || tag != 0x30 {
+			return
+		}
+		parts := splitBER(content)
+		if len(parts) < 2 {
+			return
+		}
+		id := parts[0].content
+		op := parts[1]
+
+		switch op.tag {
+		case 0x42: // unbind
+			return
+		case 0x60: // bind
+			fields := splitBER(op.content)
+			code := byte(0)
+			if string(fields[1].content) != s.bindDN || string(fields[2].content) != s.password {
+				code = 49 // invalidCredentials
+			}
+			s.reply(conn, id, berTLV(0x61, ldapResult(code)), nil)
+		case 0x63: // search
+			fields := splitBER(op.content)
+			base := string(fields[0].content)
+			s.mu.Lock()
+			if f := fields[6]; f.tag == 0xa3 {
+				ava := splitBER(f.content)
+				s.filters = append(s.filters, fmt.Sprintf("(%s=%s)", ava[0].content, ava[1].content))
+			}
+			s.mu.Unlock()
+
+			var cookie []byte
+			if len(parts) > 2 {
+				control := splitBER(splitBER(parts[2].content)[0].content)
+				value := splitBER(splitBER(control[len(control)-1].content)[0].content)
+				cookie = value[1].content
+			}
+			s.search(conn, id, base, string(cookie))
+		}
+	}
+}
+
+func (s *fakeLDAPServer) search(conn net.Conn, id []byte, base, cookie string) {
+	s.mu.Lock()
+	users := slices.Clone(s.users)
+	groups := make(map[string][]string, len(s.groups))
+	for cn, members := range s.groups {
+		groups[cn] = members
+	}
+	s.mu.Unlock()
+
+	var entries [][]byte
+	next := ""
+	switch {
+	case strings.HasPrefix(base, "ou=people"):
+		half := len(users) / 2
+		if cookie == "" {
+			users, next = users[:half], "page-2"
+		} else {
+			users = users[half:]
+		}
+		for _, u := range users {
+			entries = append(entries, ldapEntry("uid="+u[0]+",ou=people,dc=example,dc=com",
+				map[string][]string{"uid": {u[0]}, "cn": {u[1]}}))
+		}
+	case strings.HasPrefix(base, "ou=teams"):
+		for cn, members := range groups {
+			entries = append(entries, ldapEntry("cn="+cn+",ou=teams,dc=example,dc=com",
+				map[string][]string{"cn": {cn}, "member": members}))
+		}
+	}
+
+	for _, entry := range entries {
+		s.reply(conn, id, entry, nil)
+	}
+	paging := berTLV(0x30, berTLV(0x02, []byte{100}), berTLV(0x04, []byte(next)))
+	controls := berTLV(0xa0, berTLV(0x30, berTLV(0x04, []byte("1.2.840.113556.1.4.319")), berTLV(0x04, paging)))
+	s.reply(conn, id, berTLV(0x65, ldapResult(0)), controls)
+}
+
+func (s *fakeLDAPServer) reply(conn net.Conn, id, op, controls []byte) {
+	msg := [][]byte{berTLV(0x02, id), op}
+	if controls != nil {
+		msg = append(msg, controls)
+	}
+	_, _ = conn.Write(berTLV(0x30, msg...))
+}
+
+func ldapResult(code byte) []byte {
+	return slices.Concat(berTLV(0x0a, []byte{code}), berTLV(0x04, nil), berTLV(0x04, nil))
+}
+
+func ldapEntry(dn string, attrs map[string][]string) []byte {
+	var encoded [][]byte
+	for name, values := range attrs {
+		var vals [][]byte
+		for _, v := range values {
+			vals = append(vals, berTLV(0x04, []byte(v)))
+		}
+		encoded = append(encoded, berTLV(0x30, berTLV(0x04, []byte(name)), berTLV(0x31, vals...)))
+	}
+	return berTLV(0x64, berTLV(0x04, []byte(dn)), berTLV(0x30, encoded...))
+}
+
+type berElement struct {
+	tag     byte
+	content []byte
+}
+
+func berTLV(tag byte, content ...[]byte) []byte {
+	body := slices.Concat(content...)
+	out := []byte{tag}
+	switch n := len(body); {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n <= 0xff:
+		out = append(out, 0x81, byte(n))
+	default:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	}
+	return append(out, body...)
+}
+
+func readBER(r *bufio.Reader) (byte, []byte, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	size := int(first)
+	if first >= 0x80 {
+		size = 0
+		for i := 0; i < int(first&0x7f); i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return 0, nil, err
+			}
+			size = size<<8 | int(b)
+		}
+	}
+	content := make([]byte, size)
+	_, err = io.ReadFull(r, content)
+	return tag, content, err
+}
+
+func splitBER(data []byte) []berElement {
+	var elems []berElement
+	for len(data) >= 2 {
+		tag, size, head := data[0], int(data[1]), 2
+		if data[1] >= 0x80 {
+			n := int(data[1] & 0x7f)
+			size = 0
+			for _, b := range data[2 : 2+n] {
+				size = size<<8 | int(b)
+			}
+			head = 2 + n
+		}
+		elems = append(elems, berElement{tag: tag, content: data[head : head+size]})
+		data = data[head+size:]
+	}
+	return elems
+}
@@ -0,0 +1,175 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/notify"
+	"pr-service/internal/service/escalation"
+)
+
+func TestHTTPE2EReviewEscalation(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"))
+	for _, id := range []string{"pr-1", "pr-2"} {
+		s.createPR(id, "Refunds "+id, "u1")
+	}
+
+	escalator := &recordingEscalator{}
+	service := escalation.NewService(s.prRepo, escalator, 24*time.Hour, 4*time.Hour)
+	ctx := context.Background()
+
+	// Past the SLA but within the margin nothing is escalated
+	s.prRepo.BackdateAssignment("pr-1", "u2", 26*time.Hour)
+	if found, err := service.EscalateOverdueReviews(ctx, time.Now(), 10); err != nil || found != 0 {
+		t.Fatalf("expected no escalation within the margin, got %d (%v)", found, err)
+	}
+
+	// Reviews acted on or merged are not escalated; the rest are, once
+	s.prRepo.BackdateAssignment("pr-1", "u2", 4*time.Hour)
+	s.prRepo.BackdateAssignment("pr-1", "u3", 40*time.Hour)
+	s.prRepo.BackdateAssignment("pr-2", "u2", 40*time.Hour)
+	s.prRepo.BackdateAssignment("pr-2", "u3", 40*time.Hour)
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u3"}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-2"}, http.StatusOK, nil)
+
+	found, err := service.EscalateOverdueReviews(ctx, time.Now(), 10)
+	if err != nil || found != 1 {
+		t.Fatalf("expected one overdue review, got %d (%v)", found, err)
+	}
+	escalations := escalator.take()
+	if len(escalations) != 1 {
+		t.Fatalf("expected one escalation, got %+v", escalations)
+	}
+	got := escalations[0]
+	if got.PullRequestID != "pr-1" || got.UserID != "u2" || got.TeamName != "backend" || got.PullRequestName != "Refunds pr-1" {
+		t.Fatalf("unexpected escalation %+v", got)
+	}
+	if due := got.AssignedAt.Add(24 * time.Hour); !got.DueAt.Equal(due) {
+		t.Fatalf("expected the review to be due at %v, got %v", due, got.DueAt)
+	}
+	if found, err := service.EscalateOverdueReviews(ctx, time.Now(), 10); err != nil || found != 0 {
+		t.Fatalf("expected overdue reviews to be escalated once, got %d (%v)", found, err)
+	}
+
+	// Failures are reported for each review and not retried
+	s.createPR("pr-3", "Payouts", "u1")
+	s.prRepo.BackdateAssignment("pr-3", "u2", 30*time.Hour)
+	s.prRepo.BackdateAssignment("pr-3", "u3", 30*time.Hour)
+	escalator.fail = errors.New("on-call tool unavailable")
+	found, err = service.EscalateOverdueReviews(ctx, time.Now(), 10)
+	if found != 2 || err == nil || !strings.Contains(err.Error(), "review of pr-3 by u2") || !strings.Contains(err.Error(), "review of pr-3 by u3") {
+		t.Fatalf("expected both failures to be reported, got %d (%v)", found, err)
+	}
+	if found, err := service.EscalateOverdueReviews(ctx, time.Now(), 10); err != nil || found != 0 {
+		t.Fatalf("expected failed escalations not to be retried, got %d (%v)", found, err)
+	}
+}
+
+func TestHTTPE2EEscalationClients(t *testing.T) {
+	type request struct {
+		path string
+		auth string
+		body map[string]any
+	}
+	var received []request
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, request{path: r.URL.Path, auth: r.Header.Get("Authorization"), body: body})
+		switch {
+		case r.URL.Path == "/v2/enqueue" && body["routing_key"] == "pd-key":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"status":"success","dedup_key":"x"}`))
+		case r.URL.Path == "/v2/enqueue":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"invalid event","message":"Event object is invalid","errors":["Invalid routing key"]}`))
+		case r.URL.Path == "/v2/alerts" && r.Header.Get("Authorization") == "GenieKey og-key":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"result":"Request will be processed","requestId":"r1"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Key format is not valid!"}`))
+		}
+	}))
+	defer api.Close()
+
+	assignedAt := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	review := domain.Escalation{
+		StaleReview: domain.StaleReview{
+			PullRequestID: "acme/api#7", PullRequestName: strings.Repeat("Refactor the refund ledger ", 6),
+			TeamName: "payments", UserID: "u2", AssignedAt: assignedAt,
+		},
+		DueAt: assignedAt.Add(24 * time.Hour),
+	}
+	ctx := context.Background()
+
+	if err := notify.NewPagerDuty("pd-key", api.URL+"/v2/enqueue", "", 0).Escalate(ctx, review); err != nil {
+		t.Fatalf("pagerduty escalate: %v", err)
+	}
+	pd := received[0].body
+	payload, _ := pd["payload"].(map[string]any)
+	if pd["event_action"] != "trigger" || pd["dedup_key"] != "pr-service/review/acme/api#7/u2" ||
+		payload["severity"] != "warning" || payload["group"] != "payments" || payload["timestamp"] != "2024-03-05T09:00:00Z" ||
+		!strings.Contains(payload["summary"].(string), "by u2 is overdue since 2024-03-05 09:00 UTC") {
+		t.Fatalf("unexpected pagerduty event %+v", pd)
+	}
+	err := notify.NewPagerDuty("wrong", api.URL+"/v2/enqueue", "", 0).Escalate(ctx, review)
+	if err == nil || !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "Invalid routing key") {
+		t.Fatalf("expected the pagerduty error to be returned, got %v", err)
+	}
+
+	received = nil
+	if err := notify.NewOpsgenie("og-key", api.URL+"/", "P2", 0).Escalate(ctx, review); err != nil {
+		t.Fatalf("opsgenie escalate: %v", err)
+	}
+	og := received[0].body
+	message, _ := og["message"].(string)
+	details, _ := og["details"].(map[string]any)
+	if received[0].path != "/v2/alerts" || og["alias"] != "pr-service/review/acme/api#7/u2" || og["priority"] != "P2" ||
+		len(message) != 130 || !strings.HasSuffix(message, "...") || details["reviewer_id"] != "u2" ||
+		fmt.Sprint(og["tags"]) != "[pr-service review-sla payments]" {
+		t.Fatalf("unexpected opsgenie alert %+v", og)
+	}
+	err = notify.NewOpsgenie("wrong", api.URL, "", 0).Escalate(ctx, review)
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "Key format is not valid") {
+		t.Fatalf("expected the opsgenie error to be returned, got %v", err)
+	}
+}
+
+// recordingEscalator records escalations, failing them with fail when set
+type recordingEscalator struct {
+	mu          sync.Mutex
+	escalations []domain.Escalation
+	fail        error
+}
+
+func (e *recordingEscalator) Escalate(_ context.Context, escalation domain.Escalation) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.fail != nil {
+		return e.fail
+	}
+	e.escalations = append(e.escalations, escalation)
+	return nil
+}
+
+// take returns and forgets the recorded escalations
+func (e *recordingEscalator) take() []domain.Escalation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	escalations := e.escalations
+	e.escalations = nil
+	return escalations
+}
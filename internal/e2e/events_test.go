@@ -0,0 +1,341 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/repository/memory"
+	"pr-service/internal/service/outbox"
+)
+
+type relayedEvent struct {
+	key           string
+	header        string
+	Event         string `json:"event"`
+	PullRequestID string `json:"pull_request_id"`
+	PullRequest   *struct {
+		Status string `json:"status"`
+	} `json:"pull_request"`
+	ReviewerID    string   `json:"reviewer_id"`
+	OldReviewerID string   `json:"old_reviewer_id"`
+	UserID        string   `json:"user_id"`
+	TeamNames     []string `json:"team_names"`
+}
+
+// recordingTransport stands in for the message broker; it rejects messages while failing is set
+type recordingTransport struct {
+	mu       sync.Mutex
+	failing  bool
+	messages []domain.OutboxMessage
+}
+
+func (b *recordingTransport) Send(_ context.Context, messages []domain.OutboxMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failing {
+		return errors.New("broker unavailable")
+	}
+	b.messages = append(b.messages, messages...)
+	return nil
+}
+
+func (b *recordingTransport) setFailing(failing bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failing = failing
+}
+
+func (b *recordingTransport) take() []domain.OutboxMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	messages := b.messages
+	b.messages = nil
+	return messages
+}
+
+// relayEvents drains the outbox in small batches and returns the relayed events in order
+func (s *testServer) relayEvents() []relayedEvent {
+	s.t.Helper()
+	for {
+		sent, err := s.outbox.Relay(context.Background(), 2)
+		if err != nil {
+			s.t.Fatalf("relay events: %v", err)
+		}
+		if sent == 0 {
+			break
+		}
+	}
+
+	var events []relayedEvent
+	for _, m := range s.broker.take() {
+		var e relayedEvent
+		if err := json.Unmarshal(m.Payload, &e); err != nil {
+			s.t.Fatalf("decode relayed %s event: %v", m.EventType, err)
+		}
+		e.key, e.header = m.Key, string(m.EventType)
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestHTTPE2EEventOutbox(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"), activeMember("u5", "Eve"))
+	if events := s.relayEvents(); len(events) != 0 {
+		t.Fatalf("expected no events for a new team, got %+v", events)
+	}
+
+	created := s.createPR("pr-1", "Add refunds", "u1")
+	reviewers := created.PR.AssignedReviewers
+	if len(reviewers) != 2 {
+		t.Fatalf("expected two reviewers, got %v", reviewers)
+	}
+
+	events := s.relayEvents()
+	if len(events) != 3 || events[0].Event != "pr.created" || events[0].PullRequest == nil ||
+		events[0].PullRequest.Status != "OPEN" {
+		t.Fatalf("expected pr.created followed by assignments, got %+v", events)
+	}
+	for i, e := range events {
+		if e.key != "pr-1" || e.header != e.Event || e.PullRequestID != "pr-1" {
+			t.Fatalf("event %d not keyed by its pull request: %+v", i, e)
+		}
+	}
+	if events[1].Event != "reviewer.assigned" || events[2].Event != "reviewer.assigned" ||
+		!slices.Contains(reviewers, events[1].ReviewerID) || !slices.Contains(reviewers, events[2].ReviewerID) {
+		t.Fatalf("expected an assignment per reviewer %v, got %+v", reviewers, events[1:])
+	}
+
+	// a broker outage keeps events in the outbox until the next relay
+	idle := ""
+	for _, id := range []string{"u2", "u3", "u4", "u5"} {
+		if !slices.Contains(reviewers, id) {
+			idle = id
+			break
+		}
+	}
+	s.broker.setFailing(true)
+	s.postJSON("/users/setIsActive", map[string]any{"user_id": idle, "is_active": false}, http.StatusOK, nil)
+	if _, err := s.outbox.Relay(context.Background(), 10); err == nil {
+		t.Fatalf("expected relay to fail while the broker is down")
+	}
+	s.broker.setFailing(false)
+
+	events = s.relayEvents()
+	if len(events) != 1 || events[0].Event != "user.deactivated" || events[0].key != idle ||
+		events[0].UserID != idle || !slices.Equal(events[0].TeamNames, []string{"backend"}) {
+		t.Fatalf("expected user.deactivated for %s after the outage, got %+v", idle, events)
+	}
+	if events[0].PullRequestID != "" || events[0].PullRequest != nil {
+		t.Fatalf("user event must not carry a pull request: %+v", events[0])
+	}
+
+	var bulk bulkDeactivateResponse
+	s.postJSON("/users/deactivateTeamMembers", map[string]any{
+		"team_name": "backend",
+		"user_ids":  []string{reviewers[0]},
+	}, http.StatusOK, &bulk)
+	if len(bulk.Reassignments) != 1 {
+		t.Fatalf("expected one reassignment, got %+v", bulk.Reassignments)
+	}
+	events = s.relayEvents()
+	if len(events) != 2 || events[0].Event != "user.deactivated" || events[0].UserID != reviewers[0] ||
+		events[1].Event != "reviewer.reassigned" || events[1].key != "pr-1" ||
+		events[1].OldReviewerID != reviewers[0] || events[1].ReviewerID != bulk.Reassignments[0].NewUserID {
+		t.Fatalf("expected deactivation then reassignment, got %+v", events)
+	}
+
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+	events = s.relayEvents()
+	if len(events) != 1 || events[0].Event != "pr.merged" || events[0].PullRequest == nil ||
+		events[0].PullRequest.Status != "MERGED" {
+		t.Fatalf("expected a single pr.merged event, got %+v", events)
+	}
+}
+
+func TestHTTPE2EEventReplay(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"))
+	s.createPR("pr-1", "Add refunds", "u1")
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+
+	type eventsResponse struct {
+		Events []struct {
+			Cursor string       `json:"cursor"`
+			Event  relayedEvent `json:"event"`
+		} `json:"events"`
+		NextCursor string `json:"next_cursor"`
+	}
+	var all eventsResponse
+	s.getJSON("/events", http.StatusOK, &all)
+	var types []string
+	for _, e := range all.Events {
+		types = append(types, e.Event.Event)
+	}
+	if !slices.Equal(types, []string{"pr.created", "reviewer.assigned", "reviewer.assigned", "pr.merged"}) ||
+		all.NextCursor != all.Events[3].Cursor || all.Events[3].Event.PullRequest.Status != "MERGED" {
+		t.Fatalf("expected the full log in order, got %+v", all)
+	}
+
+	// Paging with cursors returns the same events, also after relaying them
+	s.relayEvents()
+	var paged []string
+	cursor := ""
+	for range 3 {
+		var page eventsResponse
+		s.getJSON("/events?limit=2&since="+cursor, http.StatusOK, &page)
+		for _, e := range page.Events {
+			paged = append(paged, e.Cursor)
+		}
+		cursor = page.NextCursor
+	}
+	if len(paged) != 4 || paged[0] != all.Events[0].Cursor || paged[3] != all.Events[3].Cursor || cursor != paged[3] {
+		t.Fatalf("expected pages to cover the log once, got %v ending at %q", paged, cursor)
+	}
+
+	s.postJSON("/users/setIsActive", map[string]any{"user_id": "u3", "is_active": false}, http.StatusOK, nil)
+	var tail eventsResponse
+	s.getJSON("/events?since="+cursor, http.StatusOK, &tail)
+	if len(tail.Events) != 1 || tail.Events[0].Event.Event != "user.deactivated" || tail.Events[0].Event.UserID != "u3" {
+		t.Fatalf("expected only the new event after the cursor, got %+v", tail)
+	}
+
+	s.getJSON("/events?since=abc", http.StatusBadRequest, nil)
+	s.getJSON("/events?since=1-x", http.StatusBadRequest, nil)
+	s.getJSON("/events?limit=1001", http.StatusBadRequest, nil)
+
+	// Without a broker events are kept for replay but never relayed
+	repo := memory.NewOutboxRepository()
+	logOnly := outbox.NewService(repo, memory.NewTransactor(), nil)
+	ctx := context.Background()
+	if err := logOnly.Publish(ctx, domain.Event{Type: domain.EventUserDeactivated, UserID: "u3", OccurredAt: time.Now()}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if sent, err := logOnly.Relay(ctx, 10); err != nil || sent != 0 {
+		t.Fatalf("expected nothing to relay, got %d (%v)", sent, err)
+	}
+	if pending, _ := repo.LockUnpublishedOutboxMessages(ctx, 10); len(pending) != 0 {
+		t.Fatalf("expected the event to be recorded as published, got %+v", pending)
+	}
+	if logged, err := logOnly.Replay(ctx, domain.EventCursor{}, 0); err != nil || len(logged) != 1 {
+		t.Fatalf("expected the event in the log, got %+v (%v)", logged, err)
+	}
+}
+
+// sseEvent is a Server-Sent Event read from a stream
+type sseEvent struct {
+	name string
+	data string
+}
+
+// openEventStream connects to the event stream and returns its events; the
+// channel is closed when the stream ends
+func (s *testServer) openEventStream(path string) <-chan sseEvent {
+	s.t.Helper()
+	resp, err := s.client.Get(s.base + path)
+	if err != nil {
+		s.t.Fatalf("open event stream: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		resp.Body.Close()
+		s.t.Fatalf("expected an event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := make(chan sseEvent, 64)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+		var current sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if current.name != "" {
+					events <- current
+				}
+				current = sseEvent{}
+			case strings.HasPrefix(line, "event: "):
+				current.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				current.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return events
+}
+
+func (s *testServer) nextStreamEvent(events <-chan sseEvent) sseEvent {
+	s.t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			s.t.Fatalf("event stream ended")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		s.t.Fatalf("timed out waiting for a streamed event")
+	}
+	return sseEvent{}
+}
+
+func TestHTTPE2EEventStream(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"))
+
+	first := s.openEventStream("/v1/events/stream")
+	second := s.openEventStream("/events/stream")
+
+	created := s.createPR("pr-1", "Add refunds", "u1")
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+
+	// Every subscriber receives the events in commit order, in the webhook body schema
+	for _, events := range []<-chan sseEvent{first, second} {
+		var names []string
+		for {
+			e := s.nextStreamEvent(events)
+			names = append(names, e.name)
+			var body struct {
+				Event         string `json:"event"`
+				OrgID         string `json:"org_id"`
+				PullRequestID string `json:"pull_request_id"`
+			}
+			if err := json.Unmarshal([]byte(e.data), &body); err != nil || body.Event != e.name || body.OrgID != domain.DefaultOrg || body.PullRequestID != "pr-1" {
+				t.Fatalf("unexpected streamed event %+v (%v)", e, err)
+			}
+			if e.name == string(domain.EventPRMerged) {
+				break
+			}
+		}
+		if names[0] != string(domain.EventPRCreated) || len(names) != len(created.PR.AssignedReviewers)+2 {
+			t.Fatalf("expected pr.created, the assignments and pr.merged, got %v", names)
+		}
+	}
+
+	// Closing the bus ends the streams, so shutdown does not wait on them
+	s.bus.Close()
+	select {
+	case _, ok := <-first:
+		if ok {
+			t.Fatalf("expected no more events")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the stream to end")
+	}
+}
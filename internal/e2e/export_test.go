@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"pr-service/internal/app/middleware"
+)
+
+func TestHTTPE2EExportImport(t *testing.T) {
+	source := newTestServer(t)
+	defer source.Close()
+
+	source.addTeam("platform", activeMember("u1", "Alice"))
+	source.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), inactiveMember("u3", "Carol"))
+	source.postJSON("/team/setParent", map[string]string{"team_name": "backend", "parent_team_name": "platform"}, http.StatusOK, nil)
+	source.createPR("pr-1", "Add search", "u1")
+	source.createPR("pr-2", "Fix login", "u2")
+	source.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-2"}, http.StatusOK, nil)
+
+	var dump map[string]any
+	source.getJSON("/v1/admin/export", http.StatusOK, &dump)
+	if dump["version"] != float64(1) || len(dump["teams"].([]any)) != 2 || len(dump["users"].([]any)) != 3 ||
+		len(dump["memberships"].([]any)) != 4 || len(dump["pull_requests"].([]any)) != 2 || len(dump["reviewers"].([]any)) != 1 {
+		t.Fatalf("unexpected export: %+v", dump)
+	}
+
+	target := newTestServer(t)
+	defer target.Close()
+
+	var imported struct {
+		Imported map[string]int `json:"imported"`
+	}
+	target.postJSON("/admin/import", dump, http.StatusOK, &imported)
+	if imported.Imported["teams"] != 2 || imported.Imported["reviewers"] != 1 {
+		t.Fatalf("unexpected import counts: %+v", imported)
+	}
+
+	var restored map[string]any
+	target.getJSON("/admin/export", http.StatusOK, &restored)
+	delete(dump, "exported_at")
+	delete(restored, "exported_at")
+	if !reflect.DeepEqual(dump, restored) {
+		t.Fatalf("restored data differs:\nsource: %+v\ntarget: %+v", dump, restored)
+	}
+
+	var tm struct {
+		Members []struct{} `json:"members"`
+	}
+	target.getJSON("/team/get?team_name=backend", http.StatusOK, &tm)
+	if len(tm.Members) != 3 {
+		t.Fatalf("expected the restored team with its members, got %+v", tm)
+	}
+	var conflict middleware.ErrorResponse
+	target.postJSON("/admin/import", dump, http.StatusConflict, &conflict)
+	if conflict.Error.Code != "CONFLICT" {
+		t.Fatalf("expected CONFLICT for an instance with data, got %+v", conflict)
+	}
+
+	// NDJSON dumps carry one typed record per line and restore the same data
+	req, err := http.NewRequest(http.MethodGet, source.base+"/admin/export?format=ndjson", nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	resp, err := source.client.Do(req)
+	if err != nil {
+		t.Fatalf("export ndjson: %v", err)
+	}
+	ndjson, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("unexpected ndjson export: status %d, type %q, err %v", resp.StatusCode, resp.Header.Get("Content-Type"), err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(ndjson)), "\n")
+	if len(lines) != 13 || !strings.HasPrefix(lines[0], `{"type":"header"`) {
+		t.Fatalf("expected a header and 12 records, got %d lines: %s", len(lines), ndjson)
+	}
+
+	clone := newTestServer(t)
+	defer clone.Close()
+	clone.post("/admin/import", "application/x-ndjson", bytes.NewReader(ndjson), http.StatusOK, &imported)
+	var cloned map[string]any
+	clone.getJSON("/admin/export", http.StatusOK, &cloned)
+	delete(cloned, "exported_at")
+	if !reflect.DeepEqual(dump, cloned) {
+		t.Fatalf("ndjson restore differs:\nsource: %+v\nclone: %+v", dump, cloned)
+	}
+
+	// References are checked before anything is written
+	empty := newTestServer(t)
+	defer empty.Close()
+	var invalid middleware.ErrorResponse
+	empty.post("/admin/import", "application/x-ndjson", strings.NewReader(
+		`{"type":"header","data":{"version":1}}`+"\n"+
+			`{"type":"user","data":{"user_id":"u1","username":"Alice","role":"member"}}`+"\n"+
+			`{"type":"pull_request","data":{"pull_request_id":"pr-1","pull_request_name":"X","author_id":"ghost","status":"OPEN"}}`+"\n",
+	), http.StatusBadRequest, &invalid)
+	if len(invalid.Error.Details) != 1 || invalid.Error.Details[0].Field != "pull_requests[0].author_id" {
+		t.Fatalf("expected the unknown author to be reported, got %+v", invalid)
+	}
+	empty.post("/admin/import", "application/x-ndjson", strings.NewReader(`{"type":"widget","data":{}}`), http.StatusBadRequest, nil)
+	var stillEmpty map[string]any
+	empty.getJSON("/admin/export", http.StatusOK, &stillEmpty)
+	if len(stillEmpty["users"].([]any)) != 0 {
+		t.Fatalf("expected a rejected import to write nothing, got %+v", stillEmpty)
+	}
+}
@@ -0,0 +1,174 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"pr-service/internal/notify"
+)
+
+type reviewRequest struct {
+	op     string
+	repo   string
+	number int
+	logins []string
+}
+
+type recordingRequester struct {
+	mu       sync.Mutex
+	requests []reviewRequest
+}
+
+func (r *recordingRequester) RequestReviewers(_ context.Context, repo string, number int, logins []string) error {
+	r.record(reviewRequest{op: "request", repo: repo, number: number, logins: logins})
+	return nil
+}
+
+func (r *recordingRequester) RemoveReviewRequests(_ context.Context, repo string, number int, logins []string) error {
+	r.record(reviewRequest{op: "remove", repo: repo, number: number, logins: logins})
+	return nil
+}
+
+func (r *recordingRequester) record(req reviewRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+}
+
+// take returns and forgets the recorded requests
+func (r *recordingRequester) take() []reviewRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	requests := r.requests
+	r.requests = nil
+	return requests
+}
+
+// flushGitHub writes back every queued event, as the write-back worker would
+func (s *testServer) flushGitHub() {
+	s.t.Helper()
+	for {
+		select {
+		case event := <-s.github.Pending():
+			if err := s.github.Send(context.Background(), event); err != nil {
+				s.t.Fatalf("write back to github: %v", err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func TestHTTPE2EGitHubWriteBack(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+
+	// PRs not created from GitHub are left alone
+	s.createPR("pr-1", "Add refunds", "u1")
+	s.flushGitHub()
+	if requests := s.ghReviews.take(); len(requests) != 0 {
+		t.Fatalf("expected no GitHub calls for a local PR, got %+v", requests)
+	}
+
+	var result struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	s.postGitHubEvent("pull_request", testGitHubSecret, map[string]any{
+		"action": "opened",
+		"number": 7,
+		"pull_request": map[string]any{
+			"title": "Fix rounding",
+			"user":  map[string]any{"login": "alice-gh"},
+		},
+		"repository": map[string]any{"name": "payments-api", "full_name": "acme/payments-api"},
+	}, http.StatusOK, &result)
+	s.flushGitHub()
+
+	// mapped users are requested by login, unmapped ones by user ID
+	login := map[string]string{"u2": "bob-gh", "u3": "u3", "u4": "u4"}
+	requested := make(map[string]bool)
+	for _, req := range s.ghReviews.take() {
+		if req.op != "request" || req.repo != "acme/payments-api" || req.number != 7 || len(req.logins) != 1 {
+			t.Fatalf("unexpected GitHub call %+v", req)
+		}
+		requested[req.logins[0]] = true
+	}
+	if len(requested) != len(result.PR.AssignedReviewers) {
+		t.Fatalf("expected reviews requested from %v, got %v", result.PR.AssignedReviewers, requested)
+	}
+	for _, id := range result.PR.AssignedReviewers {
+		if !requested[login[id]] {
+			t.Fatalf("expected a review request for %s, got %v", id, requested)
+		}
+	}
+
+	old := result.PR.AssignedReviewers[0]
+	var reassigned reassignResponse
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "acme/payments-api#7",
+		"old_user_id":     old,
+	}, http.StatusOK, &reassigned)
+	s.flushGitHub()
+
+	requests := s.ghReviews.take()
+	if len(requests) != 2 ||
+		requests[0].op != "remove" || requests[0].logins[0] != login[old] ||
+		requests[1].op != "request" || requests[1].logins[0] != login[reassigned.ReplacedBy] {
+		t.Fatalf("expected %s to be swapped for %s on GitHub, got %+v", old, reassigned.ReplacedBy, requests)
+	}
+}
+
+func TestHTTPE2EGitHubClient(t *testing.T) {
+	type call struct {
+		method    string
+		path      string
+		auth      string
+		reviewers []string
+	}
+	var calls []call
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		calls = append(calls, call{method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization"), reviewers: body.Reviewers})
+		if slices.Contains(body.Reviewers, "mallory") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"Reviews may only be requested from collaborators."}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	ctx := context.Background()
+	client := notify.NewGitHub("ghp-test", api.URL+"/", 0)
+	if err := client.RequestReviewers(ctx, "acme/payments-api", 7, []string{"bob"}); err != nil {
+		t.Fatalf("request reviewers: %v", err)
+	}
+	if err := client.RemoveReviewRequests(ctx, "acme/payments-api", 7, []string{"bob"}); err != nil {
+		t.Fatalf("remove review requests: %v", err)
+	}
+	want := []call{
+		{method: http.MethodPost, path: "/repos/acme/payments-api/pulls/7/requested_reviewers", auth: "Bearer ghp-test", reviewers: []string{"bob"}},
+		{method: http.MethodDelete, path: "/repos/acme/payments-api/pulls/7/requested_reviewers", auth: "Bearer ghp-test", reviewers: []string{"bob"}},
+	}
+	if !slices.EqualFunc(calls, want, func(a, b call) bool {
+		return a.method == b.method && a.path == b.path && a.auth == b.auth && slices.Equal(a.reviewers, b.reviewers)
+	}) {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+	if err := client.RequestReviewers(ctx, "acme/payments-api", 7, []string{"mallory"}); err == nil || !strings.Contains(err.Error(), "collaborators") {
+		t.Fatalf("expected the GitHub API error to be returned, got %v", err)
+	}
+}
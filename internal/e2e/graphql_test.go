@@ -0,0 +1,129 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"pr-service/internal/domain"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (s *testServer) graphQL(query string, variables map[string]any, expectedStatus int) graphQLResponse {
+	s.t.Helper()
+
+	var resp graphQLResponse
+	s.postJSON("/v1/graphql", map[string]any{"query": query, "variables": variables}, expectedStatus, &resp)
+	return resp
+}
+
+func TestHTTPE2EGraphQL(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), inactiveMember("u3", "Carol"))
+	s.createPR("pr-1", "Add refunds", "u1")
+
+	// A team, its members and their pending reviews in one request
+	resp := s.graphQL(`query Team($name: String!) {
+		team(team_name: $name) {
+			team_name
+			members { user_id is_active pending_reviews { pull_request_id pull_request { author { username } reviewers { user_id } } } }
+		}
+	}`, map[string]any{"name": "backend"}, http.StatusOK)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors %+v", resp.Errors)
+	}
+	var teamData struct {
+		Team struct {
+			TeamName string `json:"team_name"`
+			Members  []struct {
+				UserID         string `json:"user_id"`
+				IsActive       bool   `json:"is_active"`
+				PendingReviews []struct {
+					PullRequestID string `json:"pull_request_id"`
+					PullRequest   struct {
+						Author    struct{ Username string } `json:"author"`
+						Reviewers []struct {
+							UserID string `json:"user_id"`
+						} `json:"reviewers"`
+					} `json:"pull_request"`
+				} `json:"pending_reviews"`
+			} `json:"members"`
+		} `json:"team"`
+	}
+	if err := json.Unmarshal(resp.Data, &teamData); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	if teamData.Team.TeamName != "backend" || len(teamData.Team.Members) != 3 {
+		t.Fatalf("unexpected team %s", resp.Data)
+	}
+	for _, m := range teamData.Team.Members {
+		if m.UserID != "u2" {
+			if len(m.PendingReviews) != 0 {
+				t.Fatalf("only Bob can review pr-1, got %s", resp.Data)
+			}
+			continue
+		}
+		if len(m.PendingReviews) != 1 || m.PendingReviews[0].PullRequestID != "pr-1" ||
+			m.PendingReviews[0].PullRequest.Author.Username != "Alice" ||
+			len(m.PendingReviews[0].PullRequest.Reviewers) != 1 || m.PendingReviews[0].PullRequest.Reviewers[0].UserID != "u2" {
+			t.Fatalf("unexpected pending reviews %s", resp.Data)
+		}
+	}
+
+	// Missing objects resolve to null; aliases select the same field twice
+	resp = s.graphQL(`{ ghost: user(user_id: "nobody") { username } bob: user(user_id: "u2") { username reviews { status } }
+		teams { team_name } workload { user_id open_reviews } }`, nil, http.StatusOK)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors %+v", resp.Errors)
+	}
+	want := `{"bob":{"reviews":[{"status":"OPEN"}],"username":"Bob"},"ghost":null,"teams":[{"team_name":"backend"}],` +
+		`"workload":[{"open_reviews":1,"user_id":"u2"},{"open_reviews":0,"user_id":"u1"}]}`
+	if string(resp.Data) != want {
+		t.Fatalf("expected %s, got %s", want, resp.Data)
+	}
+
+	// Domain errors are reported with their code next to the data that resolved
+	resp = s.graphQL(`{ blank: pull_request(pull_request_id: " ") { status } pr: pull_request(pull_request_id: "pr-1") { status } }`, nil, http.StatusOK)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(domain.ErrorCodeInvalidArgument) {
+		t.Fatalf("expected an invalid argument error, got %+v", resp.Errors)
+	}
+	if string(resp.Data) != `{"blank":null,"pr":{"status":"OPEN"}}` {
+		t.Fatalf("unexpected data %s", resp.Data)
+	}
+
+	// Invalid queries are rejected without running any resolver
+	resp = s.graphQL(`{ team(team_name: "backend") { secrets } }`, nil, http.StatusBadRequest)
+	if len(resp.Errors) != 1 || resp.Data != nil || !strings.Contains(resp.Errors[0].Message, `"secrets"`) {
+		t.Fatalf("expected the query to be rejected, got %+v", resp)
+	}
+	s.postJSON("/graphql", map[string]any{}, http.StatusBadRequest, nil)
+
+	// Introspection describes the schema to GraphiQL and client generators
+	resp = s.graphQL(`{ __type(name: "Team") { fields { name } } }`, nil, http.StatusOK)
+	if len(resp.Errors) > 0 || !strings.Contains(string(resp.Data), `{"name":"members"}`) {
+		t.Fatalf("unexpected introspection result %+v", resp)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, s.base+"/v1/graphql/schema", nil)
+	schemaResp, err := s.client.Do(req)
+	if err != nil {
+		t.Fatalf("schema request failed: %v", err)
+	}
+	defer schemaResp.Body.Close()
+	sdl, _ := io.ReadAll(schemaResp.Body)
+	if schemaResp.StatusCode != http.StatusOK || !strings.Contains(string(sdl), "team(flatten: Boolean, team_name: String!): Team") {
+		t.Fatalf("unexpected schema %d: %s", schemaResp.StatusCode, sdl)
+	}
+}
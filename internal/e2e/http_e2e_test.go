@@ -2,14 +2,14 @@ package e2e
 
 import (
 	"bytes"
-	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
-	"sync"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,42 +17,34 @@ import (
 
 	"pr-service/internal/app/middleware"
 	"pr-service/internal/domain"
+	"pr-service/internal/eventbus"
 	"pr-service/internal/handler"
+	"pr-service/internal/metrics"
+	"pr-service/internal/notify"
+	"pr-service/internal/repository/memory"
 	"pr-service/internal/service/assignment"
+	"pr-service/internal/service/export"
+	"pr-service/internal/service/githubsync"
+	"pr-service/internal/service/jira"
+	"pr-service/internal/service/outbox"
 	"pr-service/internal/service/pullrequest"
+	"pr-service/internal/service/rollup"
+	"pr-service/internal/service/schedule"
+	"pr-service/internal/service/slack"
 	"pr-service/internal/service/team"
+	"pr-service/internal/service/teamchannel"
 	"pr-service/internal/service/user"
+	"pr-service/internal/service/webhook"
 )
 
 func TestHTTPE2E(t *testing.T) {
 	s := newTestServer(t)
 	defer s.Close()
 
-	teamPayload := map[string]any{
-		"team_name": "backend",
-		"members": []map[string]any{
-			{"user_id": "u1", "username": "Alice", "is_active": true},
-			{"user_id": "u2", "username": "Bob", "is_active": true},
-			{"user_id": "u3", "username": "Charlie", "is_active": true},
-			{"user_id": "u4", "username": "David", "is_active": true},
-		},
-	}
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"), activeMember("u4", "David"))
 
-	var teamResp teamResponse
-	s.postJSON("/team/add", teamPayload, http.StatusCreated, &teamResp)
-
-	createPR := func(id, name, author string) createPRResponse {
-		var resp createPRResponse
-		s.postJSON("/pullRequest/create", map[string]string{
-			"pull_request_id":   id,
-			"pull_request_name": name,
-			"author_id":         author,
-		}, http.StatusCreated, &resp)
-		return resp
-	}
-
-	pr1 := createPR("pr-1001", "Add search", "u1")
-	pr2 := createPR("pr-1002", "Refactor payments", "u1")
+	pr1 := s.createPR("pr-1001", "Add search", "u1")
+	pr2 := s.createPR("pr-1002", "Refactor payments", "u1")
 
 	if len(pr2.PR.AssignedReviewers) == 0 {
 		t.Fatalf("expected reviewers for pr-1002")
@@ -113,67 +105,257 @@ func TestHTTPE2E(t *testing.T) {
 	if !containsPR(newReview.PullRequests, "pr-1001") {
 		t.Fatalf("expected pr-1001 to be assigned to new reviewer %s", reassignment.NewUserID)
 	}
+
+	var activateResp bulkActivateResponse
+	s.postJSON("/users/activateTeamMembers", map[string]any{
+		"team_name": "backend",
+		"user_ids":  []string{targetReviewer, "u1"},
+	}, http.StatusOK, &activateResp)
+
+	if len(activateResp.ActivatedUserIDs) != 1 || activateResp.ActivatedUserIDs[0] != targetReviewer {
+		t.Fatalf("expected only %s to be reactivated, got %v", targetReviewer, activateResp.ActivatedUserIDs)
+	}
+	for _, member := range activateResp.TeamMembers {
+		if !member.IsActive {
+			t.Fatalf("expected %s to be active after bulk activation", member.UserID)
+		}
+	}
 }
 
-type testServer struct {
-	t      *testing.T
-	server *httptest.Server
-	client *http.Client
-	base   string
+func (s *testServer) getBinary(path, accept string) (string, []byte) {
+	s.t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, s.base+path, nil)
+	if err != nil {
+		s.t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		s.t.Fatalf("expected status 200, got %d: %s (%v)", resp.StatusCode, body, err)
+	}
+	if !strings.Contains(resp.Header.Get("Vary"), "Accept") {
+		s.t.Fatalf("expected Vary: Accept on a negotiated response")
+	}
+	return resp.Header.Get("Content-Type"), body
 }
 
-func newTestServer(t *testing.T) *testServer {
+type testServer struct {
+	t         *testing.T
+	server    *httptest.Server
+	client    *http.Client
+	base      string
+	scheduler *schedule.Service
+	rollup    *rollup.Service
+	teams     *team.Service
+	users     *user.Service
+	pr        *pullrequest.Service
+	teamRepo  *memory.TeamRepository
+	userRepo  *memory.UserRepository
+	prRepo    *memory.PRRepository
+	webhooks  *webhook.Service
+	slack     *slack.Service
+	slackDMs  *recordingMessenger
+	github    *githubsync.Service
+	ghReviews *recordingRequester
+	jira      *jira.Service
+	jiraNotes *recordingCommenter
+	channels  *teamchannel.Service
+	posts     *recordingChannel
+	outbox    *outbox.Service
+	broker    *recordingTransport
+	bus       *eventbus.Bus
+}
+
+// handleAPI registers an API route under /v1 and at its unversioned path, as the app does
+func handleAPI(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	method, path, _ := strings.Cut(pattern, " ")
+	mux.HandleFunc(method+" /v1"+path, handler)
+	mux.HandleFunc(pattern, handler)
+}
+
+func newTestServer(t *testing.T, prOpts ...pullrequest.Option) *testServer {
 	t.Helper()
 
-	userRepo := newMemoryUserRepo()
-	teamRepo := newMemoryTeamRepo(userRepo)
-	prRepo := newMemoryPRRepo()
+	userRepo := memory.NewUserRepository()
+	teamRepo := memory.NewTeamRepository(userRepo)
+	prRepo := memory.NewPRRepository(userRepo)
 
-	transactor := noopTransactor{}
+	transactor := memory.NewTransactor()
 	strategy := assignment.NewStrategyWithSource(rand.NewSource(1))
 
-	teamService := team.NewService(teamRepo, userRepo, transactor)
-	userService := user.NewService(userRepo, prRepo, transactor, strategy)
-	prService := pullrequest.NewService(prRepo, userRepo, transactor, strategy)
+	auditRepo := memory.NewAuditRepository()
+
+	webhookService := webhook.NewService(memory.NewWebhookRepository(), notify.NewSignedSender(time.Second),
+		webhook.WithRetryPolicy(testWebhookMaxAttempts, time.Minute, time.Hour))
+	slackDMs := &recordingMessenger{}
+	slackService := slack.NewService(slackDMs, prRepo, testSlackUsers, 0, 0)
+	ghReviews := &recordingRequester{}
+	githubSync := githubsync.NewService(ghReviews, map[string]string{"alice-gh": "u1", "bob-gh": "u2"}, []string{"acme"})
+	jiraNotes := &recordingCommenter{}
+	jiraService := jira.NewService(jiraNotes, prRepo)
+	posts := &recordingChannel{}
+	channelService := teamchannel.NewService(map[domain.NotificationChannelType]teamchannel.Channel{
+		domain.NotificationChannelSlack:   posts,
+		domain.NotificationChannelMSTeams: posts,
+	}, teamRepo, prRepo)
+	broker := &recordingTransport{}
+	outboxService := outbox.NewService(memory.NewOutboxRepository(), transactor, broker)
+	bus := eventbus.New()
+	teamService := team.NewService(teamRepo, userRepo, prRepo, auditRepo, transactor, strategy,
+		team.WithEventPublisher(webhookService), team.WithEventPublisher(outboxService), team.WithEventListener(slackService),
+		team.WithEventListener(channelService), team.WithEventListener(bus))
+	userService := user.NewService(userRepo, prRepo, auditRepo, transactor, strategy,
+		user.WithEventPublisher(webhookService), user.WithEventPublisher(outboxService), user.WithEventListener(slackService),
+		user.WithEventListener(channelService), user.WithEventListener(bus))
+	prOpts = append([]pullrequest.Option{
+		pullrequest.WithEventPublisher(webhookService),
+		pullrequest.WithEventPublisher(outboxService),
+		pullrequest.WithEventListener(slackService),
+		pullrequest.WithEventListener(githubSync),
+		pullrequest.WithEventListener(jiraService),
+		pullrequest.WithEventListener(channelService),
+		pullrequest.WithEventListener(bus),
+		pullrequest.WithTicketValidator(testTickets),
+	}, prOpts...)
+	prService := pullrequest.NewService(prRepo, userRepo, transactor, strategy, prOpts...)
+	scheduleService := schedule.NewService(memory.NewScheduledChangeRepository(), userService)
+	rollupService := rollup.NewService(memory.NewRollupRepository(prRepo), transactor, 0)
 
 	log := zap.NewNop()
 
 	teamHandler := handler.NewTeamHandler(teamService, log)
-	userHandler := handler.NewUserHandler(userService, log)
+	userHandler := handler.NewUserHandler(userService, scheduleService, log)
 	prHandler := handler.NewPRHandler(prService, log)
-	statsHandler := handler.NewStatsHandler(prService, log)
+	statsHandler := handler.NewStatsHandler(prService, rollupService, log)
+	githubHandler := handler.NewGitHubHandler(prService, testGitHubSecret, map[string]string{"alice-gh": "u1"}, log)
+	gitlabHandler := handler.NewGitLabHandler(prService, testGitLabToken, map[string]string{"alice-gl": "u1"}, log)
+	bitbucketHandler := handler.NewBitbucketHandler(prService, map[string]handler.BitbucketWorkspace{
+		"acme":   {Secret: "acme-secret", Users: map[string]string{"alice-bb": "u1"}},
+		"globex": {Secret: "globex-secret"},
+	}, log)
+	genericHandler, err := handler.NewGenericWebhookHandler(prService, testGenericAdapters, log)
+	if err != nil {
+		t.Fatalf("generic webhook adapters: %v", err)
+	}
+	webhookHandler := handler.NewOutboundWebhookHandler(webhookService, log)
+	eventsHandler := handler.NewEventsHandler(outboxService, bus, log)
+	reviewQueueHandler := handler.NewReviewQueueHandler(userService, bus, log)
+	graphqlHandler := handler.NewGraphQLHandler(teamService, userService, prService, log)
+	exportService := export.NewService(memory.NewExportRepository(teamRepo, userRepo, prRepo), transactor)
+	exportHandler := handler.NewExportHandler(exportService, log)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("POST /team/add", teamHandler.AddTeam)
-	mux.HandleFunc("GET /team/get", teamHandler.GetTeam)
-	mux.HandleFunc("POST /users/setIsActive", userHandler.SetIsActive)
-	mux.HandleFunc("GET /users/getReview", userHandler.GetReview)
-	mux.HandleFunc("POST /users/deactivateTeamMembers", userHandler.BulkDeactivateTeamMembers)
-	mux.HandleFunc("POST /pullRequest/create", prHandler.CreatePR)
-	mux.HandleFunc("POST /pullRequest/merge", prHandler.MergePR)
-	mux.HandleFunc("POST /pullRequest/reassign", prHandler.ReassignReviewer)
-	mux.HandleFunc("GET /stats/assignments", statsHandler.GetAssignmentStats)
+	handleAPI(mux, "POST /team/add", teamHandler.AddTeam)
+	handleAPI(mux, "GET /team/get", teamHandler.GetTeam)
+	handleAPI(mux, "GET /team/list", teamHandler.ListTeams)
+	handleAPI(mux, "POST /team/rename", teamHandler.RenameTeam)
+	handleAPI(mux, "POST /team/setParent", teamHandler.SetParentTeam)
+	handleAPI(mux, "POST /team/delete", teamHandler.DeleteTeam)
+	handleAPI(mux, "GET /team/deleted", teamHandler.ListDeletedTeams)
+	handleAPI(mux, "POST /team/restore", teamHandler.RestoreTeam)
+	handleAPI(mux, "POST /team/import", teamHandler.ImportTeam)
+	handleAPI(mux, "POST /team/merge", teamHandler.MergeTeams)
+	handleAPI(mux, "GET /team/auditLog", teamHandler.GetAuditLog)
+	handleAPI(mux, "GET /team/settings", teamHandler.GetSettings)
+	handleAPI(mux, "POST /team/setSettings", teamHandler.SetSettings)
+	handleAPI(mux, "POST /users/add", teamHandler.AddMember)
+	handleAPI(mux, "POST /users/setIsActive", userHandler.SetIsActive)
+	handleAPI(mux, "POST /users/setRole", userHandler.SetRole)
+	handleAPI(mux, "POST /users/delete", userHandler.DeleteUser)
+	handleAPI(mux, "GET /users/deleted", userHandler.ListDeletedUsers)
+	handleAPI(mux, "POST /users/restore", userHandler.RestoreUser)
+	handleAPI(mux, "POST /users/heartbeat", userHandler.Heartbeat)
+	handleAPI(mux, "GET /users/dormant", userHandler.ListDormantUsers)
+	handleAPI(mux, "GET /users/getReview", userHandler.GetReview)
+	handleAPI(mux, "GET /users/reviewQueue/wait", reviewQueueHandler.Wait)
+	handleAPI(mux, "GET /users/{id}/reviews.ics", userHandler.GetReviewCalendar)
+	handleAPI(mux, "POST /users/deactivateTeamMembers", userHandler.BulkDeactivateTeamMembers)
+	handleAPI(mux, "POST /users/activateTeamMembers", userHandler.BulkActivateTeamMembers)
+	handleAPI(mux, "POST /pullRequest/create", prHandler.CreatePR)
+	handleAPI(mux, "POST /pullRequest/merge", prHandler.MergePR)
+	handleAPI(mux, "POST /pullRequest/reassign", prHandler.ReassignReviewer)
+	handleAPI(mux, "POST /pullRequest/review", prHandler.RecordReview)
+	handleAPI(mux, "POST /pullRequest/delete", prHandler.DeletePR)
+	handleAPI(mux, "GET /pullRequest/list", prHandler.ListPRs)
+	handleAPI(mux, "POST /batch", prHandler.Batch)
+	handleAPI(mux, "GET /stats/assignments", statsHandler.GetAssignmentStats)
+	handleAPI(mux, "GET /stats/aging", statsHandler.GetAging)
+	handleAPI(mux, "GET /stats/authors", statsHandler.GetAuthorStats)
+	handleAPI(mux, "GET /stats/daily", statsHandler.GetDailyStats)
+	handleAPI(mux, "GET /stats/fairness", statsHandler.GetFairness)
+	handleAPI(mux, "GET /stats/pairs", statsHandler.GetReviewPairs)
+	handleAPI(mux, "GET /stats/reassignments", statsHandler.GetReassignmentStats)
+	handleAPI(mux, "GET /stats/timeToReview", statsHandler.GetTimeToReview)
+	handleAPI(mux, "GET /stats/timeToMerge", statsHandler.GetTimeToMerge)
+	handleAPI(mux, "GET /stats/workload", statsHandler.GetWorkload)
+	handleAPI(mux, "POST /webhooks/subscribe", webhookHandler.Subscribe)
+	handleAPI(mux, "GET /webhooks/list", webhookHandler.ListSubscriptions)
+	handleAPI(mux, "POST /webhooks/delete", webhookHandler.Delete)
+	handleAPI(mux, "GET /webhooks/deliveries", webhookHandler.ListDeliveries)
+	handleAPI(mux, "GET /events", eventsHandler.ListEvents)
+	handleAPI(mux, "GET /events/stream", eventsHandler.Stream)
+	handleAPI(mux, "POST /graphql", graphqlHandler.Query)
+	handleAPI(mux, "GET /graphql/schema", graphqlHandler.Schema)
+	handleAPI(mux, "GET /admin/export", exportHandler.Export)
+	handleAPI(mux, "POST /admin/import", exportHandler.Import)
+	handleAPI(mux, "POST /integrations/github/webhook", githubHandler.Webhook)
+	handleAPI(mux, "POST /integrations/gitlab/webhook", gitlabHandler.Webhook)
+	handleAPI(mux, "POST /integrations/bitbucket/webhook", bitbucketHandler.Webhook)
+	handleAPI(mux, "POST /integrations/generic/{name}/webhook", genericHandler.Webhook)
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
 	})
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc("GET /errors", handler.NewErrorCatalogHandler().List)
 
 	var handler http.Handler = mux
-	handler = middleware.Logging(log)(handler)
+	handler = middleware.Metrics(mux)(handler)
+	handler = middleware.Logging(log, nil)(handler)
 	handler = middleware.Recovery(log)(handler)
+	handler = middleware.RequestID(log)(handler)
 
 	server := httptest.NewServer(handler)
 
 	return &testServer{
-		t:      t,
-		server: server,
-		client: server.Client(),
-		base:   server.URL,
+		t:         t,
+		server:    server,
+		client:    server.Client(),
+		base:      server.URL,
+		scheduler: scheduleService,
+		rollup:    rollupService,
+		teams:     teamService,
+		users:     userService,
+		pr:        prService,
+		teamRepo:  teamRepo,
+		userRepo:  userRepo,
+		prRepo:    prRepo,
+		webhooks:  webhookService,
+		slack:     slackService,
+		slackDMs:  slackDMs,
+		github:    githubSync,
+		ghReviews: ghReviews,
+		jira:      jiraService,
+		jiraNotes: jiraNotes,
+		channels:  channelService,
+		posts:     posts,
+		outbox:    outboxService,
+		broker:    broker,
+		bus:       bus,
 	}
 }
 
 func (s *testServer) Close() {
+	s.bus.Close()
 	s.server.Close()
 }
 
@@ -189,11 +371,23 @@ func (s *testServer) postJSON(path string, body any, expectedStatus int, out any
 		buf = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, s.base+path, buf)
+	s.post(path, "application/json", buf, expectedStatus, out)
+}
+
+func (s *testServer) post(path, contentType string, body io.Reader, expectedStatus int, out any) {
+	s.t.Helper()
+
+	s.postWithHeaders(path, http.Header{"Content-Type": {contentType}}, body, expectedStatus, out)
+}
+
+func (s *testServer) postWithHeaders(path string, header http.Header, body io.Reader, expectedStatus int, out any) {
+	s.t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, s.base+path, body)
 	if err != nil {
 		s.t.Fatalf("failed to build request: %v", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	req.Header = header
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -213,6 +407,36 @@ func (s *testServer) postJSON(path string, body any, expectedStatus int, out any
 	}
 }
 
+// getCSV fetches path with the given Accept header and parses the CSV body
+func (s *testServer) getCSV(path, accept string) [][]string {
+	s.t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, s.base+path, nil)
+	if err != nil {
+		s.t.Fatalf("failed to build request: %v", err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		s.t.Fatalf("expected csv, got %d %s: %s", resp.StatusCode, resp.Header.Get("Content-Type"), string(bodyBytes))
+	}
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		s.t.Fatalf("failed to parse csv: %v", err)
+	}
+	return records
+}
+
 func (s *testServer) getJSON(path string, expectedStatus int, out any) {
 	s.t.Helper()
 
@@ -239,6 +463,46 @@ func (s *testServer) getJSON(path string, expectedStatus int, out any) {
 	}
 }
 
+// testMember is a team member created by addTeam
+type testMember struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+func activeMember(userID, username string) testMember {
+	return testMember{UserID: userID, Username: username, IsActive: true}
+}
+
+func inactiveMember(userID, username string) testMember {
+	return testMember{UserID: userID, Username: username}
+}
+
+// addTeam creates a team with the given members through /team/add
+func (s *testServer) addTeam(name string, members ...testMember) teamResponse {
+	s.t.Helper()
+
+	if members == nil {
+		members = []testMember{}
+	}
+	var resp teamResponse
+	s.postJSON("/team/add", map[string]any{"team_name": name, "members": members}, http.StatusCreated, &resp)
+	return resp
+}
+
+// createPR opens a pull request through /pullRequest/create
+func (s *testServer) createPR(id, name, authorID string) createPRResponse {
+	s.t.Helper()
+
+	var resp createPRResponse
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   id,
+		"pull_request_name": name,
+		"author_id":         authorID,
+	}, http.StatusCreated, &resp)
+	return resp
+}
+
 type teamResponse struct {
 	Team struct {
 		TeamName string `json:"team_name"`
@@ -250,6 +514,8 @@ type createPRResponse struct {
 		PullRequestID     string   `json:"pull_request_id"`
 		PullRequestName   string   `json:"pull_request_name"`
 		AuthorID          string   `json:"author_id"`
+		TeamName          string   `json:"team_name"`
+		TicketKey         string   `json:"ticket_key"`
 		Status            string   `json:"status"`
 		AssignedReviewers []string `json:"assigned_reviewers"`
 	} `json:"pr"`
@@ -258,6 +524,7 @@ type createPRResponse struct {
 type reassignResponse struct {
 	PR struct {
 		PullRequestID     string   `json:"pull_request_id"`
+		TeamName          string   `json:"team_name"`
 		AssignedReviewers []string `json:"assigned_reviewers"`
 	} `json:"pr"`
 	ReplacedBy string `json:"replaced_by"`
@@ -271,8 +538,28 @@ type mergeResponse struct {
 }
 
 type statsResponse struct {
-	ByUser map[string]int `json:"by_user"`
-	ByPR   map[string]int `json:"by_pr"`
+	ByUser []struct {
+		UserID string `json:"user_id"`
+		Count  int    `json:"count"`
+	} `json:"by_user"`
+	TotalUsers int `json:"total_users"`
+	ByPR       []struct {
+		PullRequestID string `json:"pull_request_id"`
+		Count         int    `json:"count"`
+	} `json:"by_pr"`
+	TotalPRs int            `json:"total_prs"`
+	ByRole   map[string]int `json:"by_role"`
+	ByTeam   map[string]int `json:"by_team"`
+}
+
+// userCount returns the assignment count of userID on the page, or 0
+func (s statsResponse) userCount(userID string) int {
+	for _, u := range s.ByUser {
+		if u.UserID == userID {
+			return u.Count
+		}
+	}
+	return 0
 }
 
 type bulkDeactivateResponse struct {
@@ -290,298 +577,82 @@ type bulkDeactivateResponse struct {
 	} `json:"team_members"`
 }
 
-type getReviewResponse struct {
-	UserID       string           `json:"user_id"`
-	PullRequests []pullRequestRef `json:"pull_requests"`
-}
-
-type pullRequestRef struct {
-	PullRequestID string `json:"pull_request_id"`
-}
-
-func containsPR(prs []pullRequestRef, id string) bool {
-	for _, pr := range prs {
-		if pr.PullRequestID == id {
-			return true
-		}
-	}
-	return false
-}
-
-// --- in-memory repositories and transactor ---
-
-type memoryTeamRepo struct {
-	mu       sync.RWMutex
-	teams    map[string]domain.Team
-	userRepo *memoryUserRepo
-}
-
-func newMemoryTeamRepo(userRepo *memoryUserRepo) *memoryTeamRepo {
-	return &memoryTeamRepo{
-		teams:    make(map[string]domain.Team),
-		userRepo: userRepo,
-	}
-}
-
-func (r *memoryTeamRepo) CreateTeam(_ context.Context, team domain.Team) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.teams[team.TeamName] = team
-	return nil
-}
-
-func (r *memoryTeamRepo) GetTeam(_ context.Context, teamName string) (domain.Team, error) {
-	r.mu.RLock()
-	team, ok := r.teams[teamName]
-	r.mu.RUnlock()
-	if !ok {
-		return domain.Team{}, domain.ErrNotFound
-	}
-	team.Members = r.userRepo.members(teamName)
-	return team, nil
+type listTeamsResponse struct {
+	Teams []struct {
+		TeamName          string `json:"team_name"`
+		MemberCount       int    `json:"member_count"`
+		ActiveMemberCount int    `json:"active_member_count"`
+	} `json:"teams"`
+	Total int `json:"total"`
 }
 
-func (r *memoryTeamRepo) TeamExists(_ context.Context, teamName string) (bool, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	_, ok := r.teams[teamName]
-	return ok, nil
-}
-
-type memoryUserRepo struct {
-	mu    sync.RWMutex
-	users map[string]domain.User
-}
-
-func newMemoryUserRepo() *memoryUserRepo {
-	return &memoryUserRepo{
-		users: make(map[string]domain.User),
-	}
-}
-
-func (r *memoryUserRepo) CreateOrUpdateUser(_ context.Context, user domain.User) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.users[user.UserID] = user
-	return nil
-}
-
-func (r *memoryUserRepo) UpdateUser(_ context.Context, user domain.User) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if _, ok := r.users[user.UserID]; !ok {
-		return domain.ErrNotFound
-	}
-	r.users[user.UserID] = user
-	return nil
-}
-
-func (r *memoryUserRepo) GetUser(_ context.Context, userID string) (domain.User, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	user, ok := r.users[userID]
-	if !ok {
-		return domain.User{}, domain.ErrNotFound
-	}
-	return user, nil
-}
-
-func (r *memoryUserRepo) GetTeamMembers(_ context.Context, teamName string) ([]domain.User, error) {
-	return r.members(teamName), nil
-}
-
-func (r *memoryUserRepo) DeactivateUsers(_ context.Context, teamName string, userIDs []string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	for _, id := range userIDs {
-		user, ok := r.users[id]
-		if !ok || user.TeamName != teamName {
-			return domain.ErrNotFound
-		}
-		user.IsActive = false
-		user.UpdatedAt = time.Now()
-		r.users[id] = user
-	}
-	return nil
-}
-
-func (r *memoryUserRepo) members(teamName string) []domain.User {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	result := make([]domain.User, 0)
-	for _, u := range r.users {
-		if u.TeamName == teamName {
-			result = append(result, u)
-		}
-	}
-	return result
-}
-
-type memoryPRRepo struct {
-	mu  sync.RWMutex
-	prs map[string]domain.PullRequest
-}
-
-func newMemoryPRRepo() *memoryPRRepo {
-	return &memoryPRRepo{
-		prs: make(map[string]domain.PullRequest),
-	}
-}
-
-func (r *memoryPRRepo) CreatePR(_ context.Context, pr domain.PullRequest) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if _, exists := r.prs[pr.PullRequestID]; exists {
-		return fmt.Errorf("pr exists: %s", pr.PullRequestID)
-	}
-	r.prs[pr.PullRequestID] = pr
-	return nil
-}
-
-func (r *memoryPRRepo) GetPR(_ context.Context, prID string) (domain.PullRequest, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	pr, ok := r.prs[prID]
-	if !ok {
-		return domain.PullRequest{}, domain.ErrNotFound
-	}
-	return clonePR(pr), nil
-}
-
-func (r *memoryPRRepo) UpdatePR(_ context.Context, pr domain.PullRequest) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if _, ok := r.prs[pr.PullRequestID]; !ok {
-		return domain.ErrNotFound
-	}
-	r.prs[pr.PullRequestID] = pr
-	return nil
-}
-
-func (r *memoryPRRepo) AssignReviewers(_ context.Context, prID string, reviewers []string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	pr, ok := r.prs[prID]
-	if !ok {
-		return domain.ErrNotFound
-	}
-	for _, reviewer := range reviewers {
-		if !containsString(pr.AssignedReviewers, reviewer) {
-			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer)
-		}
-	}
-	r.prs[prID] = pr
-	return nil
-}
-
-func (r *memoryPRRepo) RemoveReviewer(_ context.Context, prID string, userID string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	pr, ok := r.prs[prID]
-	if !ok {
-		return domain.ErrNotFound
-	}
-	filtered := make([]string, 0, len(pr.AssignedReviewers))
-	for _, reviewer := range pr.AssignedReviewers {
-		if reviewer != userID {
-			filtered = append(filtered, reviewer)
-		}
-	}
-	pr.AssignedReviewers = filtered
-	r.prs[prID] = pr
-	return nil
-}
-
-func (r *memoryPRRepo) AddReviewer(_ context.Context, prID string, userID string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	pr, ok := r.prs[prID]
-	if !ok {
-		return domain.ErrNotFound
-	}
-	if !containsString(pr.AssignedReviewers, userID) {
-		pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
-	}
-	r.prs[prID] = pr
-	return nil
-}
-
-func (r *memoryPRRepo) GetPRsByReviewer(_ context.Context, userID string) ([]domain.PullRequest, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	prs := make([]domain.PullRequest, 0)
-	for _, pr := range r.prs {
-		if containsString(pr.AssignedReviewers, userID) {
-			copied := clonePR(pr)
-			prs = append(prs, copied)
-		}
-	}
-	return prs, nil
+type deleteTeamResponse struct {
+	TeamName string `json:"team_name"`
+	Members  []struct {
+		UserID   string `json:"user_id"`
+		TeamName string `json:"team_name"`
+		IsActive bool   `json:"is_active"`
+	} `json:"members"`
+	Reassignments []struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+		NewUserID     string `json:"new_user_id"`
+	} `json:"reassignments"`
+	ClosedReviews []struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+	} `json:"closed_reviews"`
 }
 
-func (r *memoryPRRepo) PRExists(_ context.Context, prID string) (bool, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	_, ok := r.prs[prID]
-	return ok, nil
+type deleteUserResponse struct {
+	User struct {
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
+	} `json:"user"`
+	Reassignments []struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+		NewUserID     string `json:"new_user_id"`
+	} `json:"reassignments"`
+	ClosedReviews []struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+	} `json:"closed_reviews"`
 }
 
-func (r *memoryPRRepo) GetAssignmentStatsByUser(_ context.Context) (map[string]int, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	stats := make(map[string]int)
-	for _, pr := range r.prs {
-		for _, reviewer := range pr.AssignedReviewers {
-			stats[reviewer]++
-		}
-	}
-	return stats, nil
+type bulkActivateResponse struct {
+	TeamName         string   `json:"team_name"`
+	ActivatedUserIDs []string `json:"activated_user_ids"`
+	TeamMembers      []struct {
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
+	} `json:"team_members"`
 }
 
-func (r *memoryPRRepo) GetAssignmentStatsByPR(_ context.Context) (map[string]int, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	stats := make(map[string]int)
-	for id, pr := range r.prs {
-		stats[id] = len(pr.AssignedReviewers)
-	}
-	return stats, nil
+type scheduledChangeResponse struct {
+	ScheduledChange struct {
+		ID          int64     `json:"id"`
+		Kind        string    `json:"kind"`
+		UserIDs     []string  `json:"user_ids"`
+		EffectiveAt time.Time `json:"effective_at"`
+		Status      string    `json:"status"`
+	} `json:"scheduled_change"`
 }
 
-func (r *memoryPRRepo) GetOpenPRIDsByReviewer(_ context.Context, userID string) ([]string, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	ids := make([]string, 0)
-	for id, pr := range r.prs {
-		if pr.Status == domain.PRStatusMerged {
-			continue
-		}
-		if containsString(pr.AssignedReviewers, userID) {
-			ids = append(ids, id)
-		}
-	}
-	return ids, nil
+type getReviewResponse struct {
+	UserID       string           `json:"user_id"`
+	PullRequests []pullRequestRef `json:"pull_requests"`
 }
 
-func clonePR(pr domain.PullRequest) domain.PullRequest {
-	copied := pr
-	if pr.AssignedReviewers != nil {
-		copied.AssignedReviewers = append([]string(nil), pr.AssignedReviewers...)
-	}
-	return copied
+type pullRequestRef struct {
+	PullRequestID string `json:"pull_request_id"`
 }
 
-func containsString(items []string, target string) bool {
-	for _, item := range items {
-		if item == target {
+func containsPR(prs []pullRequestRef, id string) bool {
+	for _, pr := range prs {
+		if pr.PullRequestID == id {
 			return true
 		}
 	}
 	return false
 }
-
-type noopTransactor struct{}
-
-func (noopTransactor) Do(ctx context.Context, f func(ctx context.Context) error) error {
-	return f(ctx)
-}
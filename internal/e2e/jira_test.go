@@ -0,0 +1,238 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"pr-service/internal/notify"
+)
+
+// testTickets are the Jira issues the test ticket validator knows
+var testTickets = knownTickets{"PAY-1": true, "PAY-2": true}
+
+type knownTickets map[string]bool
+
+func (k knownTickets) IssueExists(_ context.Context, key string) (bool, error) {
+	return k[key], nil
+}
+
+type jiraComment struct {
+	key  string
+	body string
+}
+
+type recordingCommenter struct {
+	mu       sync.Mutex
+	comments []jiraComment
+}
+
+func (c *recordingCommenter) AddComment(_ context.Context, key, body string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.comments = append(c.comments, jiraComment{key: key, body: body})
+	return nil
+}
+
+// take returns and forgets the recorded comments
+func (c *recordingCommenter) take() []jiraComment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	comments := c.comments
+	c.comments = nil
+	return comments
+}
+
+// flushJira posts every queued Jira comment, as the comments worker would
+func (s *testServer) flushJira() {
+	s.t.Helper()
+	for {
+		select {
+		case event := <-s.jira.Pending():
+			if err := s.jira.Send(context.Background(), event); err != nil {
+				s.t.Fatalf("comment on jira: %v", err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func TestHTTPE2EJiraTickets(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+
+	// Malformed keys are rejected before Jira is asked, unknown ones by Jira
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-0", "pull_request_name": "Bad key", "author_id": "u1", "ticket_key": "PAY 1",
+	}, http.StatusBadRequest, nil)
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-0", "pull_request_name": "Unknown key", "author_id": "u1", "ticket_key": "PAY-404",
+	}, http.StatusBadRequest, &errResp)
+	if errResp.Error.Code != "TICKET_NOT_FOUND" {
+		t.Fatalf("expected TICKET_NOT_FOUND, got %+v", errResp)
+	}
+
+	var created createPRResponse
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add refunds", "author_id": "u1", "ticket_key": "pay-1",
+	}, http.StatusCreated, &created)
+	if created.PR.TicketKey != "PAY-1" {
+		t.Fatalf("expected the ticket key to be normalized, got %q", created.PR.TicketKey)
+	}
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-2", "pull_request_name": "Refund emails", "author_id": "u2", "ticket_key": "PAY-1",
+	}, http.StatusCreated, nil)
+	s.createPR("pr-3", "Untracked", "u3")
+	s.flushJira()
+
+	comments := s.jiraNotes.take()
+	if len(comments) != 2 || comments[0].key != "PAY-1" || comments[1].key != "PAY-1" ||
+		!strings.Contains(comments[0].body, "{{pr-1}}") ||
+		!strings.Contains(comments[0].body, strings.Join(created.PR.AssignedReviewers, ", ")) {
+		t.Fatalf("expected a linking comment per ticketed PR listing reviewers, got %+v", comments)
+	}
+
+	var reassigned reassignResponse
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     created.PR.AssignedReviewers[0],
+	}, http.StatusOK, &reassigned)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-3"}, http.StatusOK, nil)
+	s.flushJira()
+
+	comments = s.jiraNotes.take()
+	wantReassign := fmt.Sprintf("reviewer %s was replaced by %s", created.PR.AssignedReviewers[0], reassigned.ReplacedBy)
+	if len(comments) != 2 || !strings.Contains(comments[0].body, wantReassign) || !strings.Contains(comments[1].body, "was merged") {
+		t.Fatalf("expected reassignment and merge comments, got %+v", comments)
+	}
+
+	type listResponse struct {
+		PullRequests []struct {
+			PullRequestID     string   `json:"pull_request_id"`
+			TicketKey         string   `json:"ticket_key"`
+			Status            string   `json:"status"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pull_requests"`
+		Total      int    `json:"total"`
+		NextCursor string `json:"next_cursor"`
+	}
+	var list listResponse
+	s.getJSON("/pullRequest/list?ticket=pay-1", http.StatusOK, &list)
+	if list.Total != 2 || len(list.PullRequests) != 2 {
+		t.Fatalf("expected two PRs linked to PAY-1, got %+v", list)
+	}
+	for _, pr := range list.PullRequests {
+		if pr.TicketKey != "PAY-1" || len(pr.AssignedReviewers) == 0 {
+			t.Fatalf("unexpected PR in ticket listing %+v", pr)
+		}
+	}
+
+	s.getJSON("/pullRequest/list?ticket=PAY-1&status=open", http.StatusOK, &list)
+	if list.Total != 1 || list.PullRequests[0].PullRequestID != "pr-2" {
+		t.Fatalf("expected only the open PR of PAY-1, got %+v", list)
+	}
+	s.getJSON("/pullRequest/list?limit=1&offset=1", http.StatusOK, &list)
+	if list.Total != 3 || len(list.PullRequests) != 1 {
+		t.Fatalf("expected a one-PR page of three, got %+v", list)
+	}
+
+	// Cursors walk the listing in either order without repeating PRs
+	for order, want := range map[string]string{"desc": "pr-3,pr-2,pr-1", "asc": "pr-1,pr-2,pr-3"} {
+		var seen []string
+		path := "/pullRequest/list?limit=2&order=" + order
+		for pages := 0; ; pages++ {
+			if pages == 3 {
+				t.Fatalf("expected the %s listing to end after two pages", order)
+			}
+			s.getJSON(path, http.StatusOK, &list)
+			for _, pr := range list.PullRequests {
+				seen = append(seen, pr.PullRequestID)
+			}
+			if list.NextCursor == "" {
+				break
+			}
+			path = "/pullRequest/list?limit=2&order=" + order + "&cursor=" + list.NextCursor
+		}
+		if strings.Join(seen, ",") != want {
+			t.Fatalf("expected %s order %s, got %v", order, want, seen)
+		}
+	}
+	s.getJSON("/pullRequest/list?limit=1", http.StatusOK, &list)
+	s.getJSON("/pullRequest/list?offset=1&cursor="+list.NextCursor, http.StatusBadRequest, nil)
+	s.getJSON("/pullRequest/list?cursor=garbage", http.StatusBadRequest, nil)
+	s.getJSON("/pullRequest/list?ticket=PAY-2", http.StatusOK, &list)
+	if list.Total != 0 || list.PullRequests == nil {
+		t.Fatalf("expected an empty listing, got %+v", list)
+	}
+	s.getJSON("/pullRequest/list?ticket=not-a-key", http.StatusBadRequest, nil)
+	s.getJSON("/pullRequest/list?status=CLOSED", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EJiraClient(t *testing.T) {
+	var comment struct {
+		path string
+		user string
+		body string
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, _ := r.BasicAuth()
+		if user != "bot@example.com" || token != "jira-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PAY-1":
+			_, _ = w.Write([]byte(`{"key":"PAY-1","fields":{"summary":"Refunds"}}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/PAY-1/comment":
+			var body struct {
+				Body string `json:"body"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			comment.path, comment.user, comment.body = r.URL.Path, user, body.Body
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errorMessages":["Comment body can not be empty!"]}`))
+		}
+	}))
+	defer api.Close()
+
+	ctx := context.Background()
+	client := notify.NewJira(api.URL+"/", "bot@example.com", "jira-token", 0)
+	if exists, err := client.IssueExists(ctx, "PAY-1"); err != nil || !exists {
+		t.Fatalf("expected PAY-1 to exist, got %v (%v)", exists, err)
+	}
+	if exists, err := client.IssueExists(ctx, "PAY-404"); err != nil || exists {
+		t.Fatalf("expected PAY-404 to be missing, got %v (%v)", exists, err)
+	}
+	if err := client.AddComment(ctx, "PAY-1", "merged"); err != nil {
+		t.Fatalf("add comment: %v", err)
+	}
+	if comment.user != "bot@example.com" || comment.body != "merged" {
+		t.Fatalf("unexpected comment %+v", comment)
+	}
+	if err := client.AddComment(ctx, "PAY-2", ""); err == nil || !strings.Contains(err.Error(), "can not be empty") {
+		t.Fatalf("expected the Jira API error to be returned, got %v", err)
+	}
+	denied := notify.NewJira(api.URL, "", "wrong", 0)
+	if _, err := denied.IssueExists(ctx, "PAY-1"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected an authentication error, got %v", err)
+	}
+}
@@ -0,0 +1,1036 @@
+package e2e
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/yaml.v3"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/auth"
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/handler"
+	"pr-service/internal/lifecycle"
+	"pr-service/internal/maintenance"
+	"pr-service/internal/ratelimit"
+)
+
+func TestHTTPE2EValidationErrors(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	type fieldError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+	var resp struct {
+		Error struct {
+			Code    string       `json:"code"`
+			Message string       `json:"message"`
+			Details []fieldError `json:"details"`
+		} `json:"error"`
+		Results []struct {
+			Error struct {
+				Details []fieldError `json:"details"`
+			} `json:"error"`
+		} `json:"results"`
+	}
+
+	// Every offending field is named, with its path into the request
+	s.postJSON("/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true, "role": "owner"},
+			{"user_id": " ", "username": "Carol", "is_active": true},
+		},
+	}, http.StatusBadRequest, &resp)
+	want := []fieldError{
+		{Field: "members[1].role", Message: "must be member or lead"},
+		{Field: "members[2].user_id", Message: "must not be empty"},
+	}
+	if resp.Error.Code != string(domain.ErrorCodeInvalidArgument) || !slices.Equal(resp.Error.Details, want) ||
+		resp.Error.Message != "invalid argument: members[1].role: must be member or lead; members[2].user_id: must not be empty" {
+		t.Fatalf("unexpected validation error %+v", resp.Error)
+	}
+
+	resp.Error.Details = nil
+	s.post("/pullRequest/create", "application/json", strings.NewReader(`{"pull_request_id": 1}`), http.StatusBadRequest, &resp)
+	if !slices.Equal(resp.Error.Details, []fieldError{{Field: "body", Message: "must be a valid JSON object"}}) {
+		t.Fatalf("unexpected body error %+v", resp.Error)
+	}
+
+	s.getJSON("/pullRequest/list?limit=-1", http.StatusBadRequest, &resp)
+	if !slices.Equal(resp.Error.Details, []fieldError{{Field: "limit", Message: "must be a non-negative integer"}}) {
+		t.Fatalf("unexpected query error %+v", resp.Error)
+	}
+
+	// Batch operations report the fields of the failed operation
+	s.postJSON("/batch", map[string]any{"operations": []map[string]string{
+		{"op": "create", "pull_request_id": "pr-1", "ticket_key": "not a key"},
+	}}, http.StatusBadRequest, &resp)
+	want = []fieldError{
+		{Field: "pull_request_name", Message: "must not be empty"},
+		{Field: "author_id", Message: "must not be empty"},
+		{Field: "ticket_key", Message: "must be a Jira issue key such as PAY-42"},
+	}
+	if !slices.Equal(resp.Error.Details, want) || len(resp.Results) != 1 || !slices.Equal(resp.Results[0].Error.Details, want) {
+		t.Fatalf("unexpected batch validation error %+v", resp)
+	}
+
+	// Other errors carry no details
+	resp.Error.Details = nil
+	s.getJSON("/team/get?team_name=missing", http.StatusNotFound, &resp)
+	if resp.Error.Code != string(domain.ErrorCodeNotFound) || resp.Error.Details != nil {
+		t.Fatalf("unexpected not found error %+v", resp.Error)
+	}
+}
+
+func TestHTTPE2ERequestID(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	send := func(path, requestID string) (string, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, s.base+path, nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		if requestID != "" {
+			req.Header.Set("X-Request-Id", requestID)
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var body struct {
+			Error struct {
+				RequestID string `json:"request_id"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.Header.Get("X-Request-Id"), body.Error.RequestID
+	}
+
+	// Every response carries an ID, and error bodies repeat it for bug reports
+	header, body := send("/team/get?team_name=missing", "")
+	if len(header) != 32 || body != header {
+		t.Fatalf("expected a generated request ID in the header and body, got %q and %q", header, body)
+	}
+	if again, _ := send("/team/get?team_name=missing", ""); again == header {
+		t.Fatalf("expected a new request ID per request, got %q twice", again)
+	}
+	if header, body := send("/health", ""); header == "" || body != "" {
+		t.Fatalf("expected successful responses to carry the ID in the header only, got %q and %q", header, body)
+	}
+
+	// IDs set by a proxy are kept unless they are unsafe to log
+	if header, body := send("/team/get?team_name=missing", "edge-42"); header != "edge-42" || body != "edge-42" {
+		t.Fatalf("expected the incoming request ID to be kept, got %q and %q", header, body)
+	}
+	if header, _ := send("/health", "bad id"); header == "bad id" || len(header) != 32 {
+		t.Fatalf("expected an unsafe request ID to be replaced, got %q", header)
+	}
+}
+
+func TestHTTPE2EMetrics(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("metrics", activeMember("m1", "Alice"), activeMember("m2", "Bob"))
+	s.createPR("pr-metrics", "Add metrics", "m1")
+	s.getJSON("/team/get?team_name=missing", http.StatusNotFound, nil)
+	s.getJSON("/no/such/route", http.StatusNotFound, nil)
+
+	resp, err := s.client.Get(s.base + "/metrics")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Fatalf("expected a text exposition, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+
+	for _, expected := range []string{
+		`pr_service_http_requests_total{method="POST",route="/pullRequest/create",status="201"}`,
+		`pr_service_http_requests_total{method="GET",route="/team/get",status="404"}`,
+		`pr_service_http_requests_total{method="GET",route="unmatched",status="404"}`,
+		`pr_service_http_request_duration_seconds_bucket{method="POST",route="/team/add",status="201",le="+Inf"}`,
+		`pr_service_http_requests_in_flight{method="POST",route="/pullRequest/create"} 0`,
+		`pr_service_http_requests_in_flight{method="GET",route="/metrics"} 1`,
+		"# TYPE pr_service_reviewer_assignments_total counter",
+		"# TYPE pr_service_reviewer_reassignments_total counter",
+		"# TYPE pr_service_db_transaction_duration_seconds histogram",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("expected metrics to contain %q, got:\n%s", expected, body)
+		}
+	}
+	if strings.Contains(string(body), "/no/such/route") {
+		t.Fatalf("expected unmatched paths not to become labels")
+	}
+}
+
+func TestHTTPE2EAPIVersioning(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.postJSON("/v1/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		},
+	}, http.StatusCreated, nil)
+
+	// The unversioned paths keep serving the v1 API
+	var versioned, legacy struct {
+		TeamName string `json:"team_name"`
+		Members  []any  `json:"members"`
+	}
+	s.getJSON("/v1/team/get?team_name=backend", http.StatusOK, &versioned)
+	s.getJSON("/team/get?team_name=backend", http.StatusOK, &legacy)
+	if versioned.TeamName != "backend" || len(versioned.Members) != 2 || legacy.TeamName != versioned.TeamName ||
+		len(legacy.Members) != len(versioned.Members) {
+		t.Fatalf("expected both paths to return the team, got %+v and %+v", versioned, legacy)
+	}
+	s.postJSON("/v1/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add refunds",
+		"author_id":         "u1",
+	}, http.StatusCreated, nil)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+
+	// Probes and metrics stay unversioned, and unknown versions are not served
+	s.getJSON("/health", http.StatusOK, nil)
+	for _, path := range []string{"/v1/health", "/v2/team/get?team_name=backend"} {
+		resp, err := s.client.Get(s.base + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected %s to be 404, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestHTTPE2EDeprecationHeaders(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"))
+
+	since := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.Handle("GET /v1/team/get", middleware.Deprecated(middleware.Deprecation{
+		Since:  since,
+		Sunset: sunset,
+		Link:   "https://docs.example.com/migrations/team-get",
+	})(s.server.Config.Handler))
+	mux.Handle("GET /v1/team/list", middleware.Deprecated(middleware.Deprecation{
+		Since:  since,
+		Fields: []string{"teams[].parent", "total"},
+	})(s.server.Config.Handler))
+	mux.Handle("/", s.server.Config.Handler)
+	deprecated := httptest.NewServer(mux)
+	defer deprecated.Close()
+
+	get := func(path string) http.Header {
+		t.Helper()
+		resp, err := deprecated.Client().Get(deprecated.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected %s to be served, got %d", path, resp.StatusCode)
+		}
+		return resp.Header
+	}
+
+	// A deprecated route announces when it was deprecated and when it goes away
+	h := get("/v1/team/get?team_name=backend")
+	if h.Get("Deprecation") != "@1768435200" || h.Get("Sunset") != "Wed, 01 Jul 2026 00:00:00 GMT" ||
+		h.Get("Link") != `<https://docs.example.com/migrations/team-get>; rel="deprecation"` ||
+		h.Get(middleware.DeprecatedFieldsHeader) != "" {
+		t.Fatalf("expected deprecation headers on the route, got %v", h)
+	}
+
+	// Deprecated fields are named while the route stays supported
+	h = get("/v1/team/list")
+	if h.Get("Deprecation") != "@1768435200" || h.Get("Sunset") != "" ||
+		h.Get(middleware.DeprecatedFieldsHeader) != "teams[].parent, total" {
+		t.Fatalf("expected deprecated fields to be listed, got %v", h)
+	}
+
+	// Other routes are not marked
+	h = get("/health")
+	if h.Get("Deprecation") != "" || h.Get("Sunset") != "" {
+		t.Fatalf("expected no deprecation headers, got %v", h)
+	}
+
+	resp, err := s.client.Get(s.base + "/metrics")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+	if !strings.Contains(string(body), `pr_service_deprecated_requests_total{method="GET",route="/v1/team/get"} 1`) {
+		t.Fatalf("expected deprecated calls to be counted, got:\n%s", body)
+	}
+}
+
+func TestHTTPE2ERateLimit(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	limited := httptest.NewServer(middleware.RateLimit(ratelimit.New(2, time.Hour), zap.NewNop())(s.server.Config.Handler))
+	defer limited.Close()
+
+	get := func(path string) *http.Response {
+		t.Helper()
+		resp, err := limited.Client().Get(limited.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		return resp
+	}
+
+	// Every response reports the quota, including errors
+	for _, c := range []struct {
+		path      string
+		status    int
+		remaining string
+	}{
+		{"/team/list", http.StatusOK, "1"},
+		{"/team/get?team_name=missing", http.StatusNotFound, "0"},
+	} {
+		resp := get(c.path)
+		resp.Body.Close()
+		if resp.StatusCode != c.status || resp.Header.Get("RateLimit-Limit") != "2" ||
+			resp.Header.Get("RateLimit-Remaining") != c.remaining || resp.Header.Get("RateLimit-Reset") == "" {
+			t.Fatalf("%s: expected %d with quota headers, got %d %v", c.path, c.status, resp.StatusCode, resp.Header)
+		}
+	}
+
+	// Past the quota requests are rejected until the window ends
+	resp := get("/team/list")
+	var rejected middleware.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rejected); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+	reset, err := strconv.Atoi(resp.Header.Get("RateLimit-Reset"))
+	if resp.StatusCode != http.StatusTooManyRequests || rejected.Error.Code != "RATE_LIMITED" ||
+		resp.Header.Get("RateLimit-Remaining") != "0" || err != nil || reset <= 0 || reset > 3600 ||
+		resp.Header.Get("Retry-After") != resp.Header.Get("RateLimit-Reset") {
+		t.Fatalf("expected a 429 with a retry time, got %d %+v %v", resp.StatusCode, rejected, resp.Header)
+	}
+
+	// Probes are not limited
+	resp = get("/health")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("RateLimit-Limit") != "" {
+		t.Fatalf("expected probes to bypass the limit, got %d %v", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestHTTPE2ECORS(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	s := newTestServer(t)
+	defer s.Close()
+
+	// CORS runs outside authentication, as the app chains it
+	log := zap.NewNop()
+	oidc := auth.NewOIDC(provider.URL, "pr-service", "", "", nil, 0)
+	cors := middleware.CORS(middleware.CORSPolicy{
+		AllowedOrigins:   []string{"https://dash.example.com", "https://*.internal.example.com"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	server := httptest.NewServer(cors(middleware.Authenticate(oidc, log)(s.server.Config.Handler)))
+	defer server.Close()
+
+	do := func(method, path string, header http.Header) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header = header
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// Preflights of allowed origins are answered without a token
+	resp := do(http.MethodOptions, "/v1/pullRequest/create", http.Header{
+		"Origin":                         {"https://dash.example.com"},
+		"Access-Control-Request-Method":  {"POST"},
+		"Access-Control-Request-Headers": {"authorization, content-type"},
+	})
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != "https://dash.example.com" ||
+		resp.Header.Get("Access-Control-Allow-Methods") != "GET, POST" || resp.Header.Get("Access-Control-Allow-Credentials") != "true" ||
+		!strings.Contains(resp.Header.Get("Access-Control-Allow-Headers"), "Authorization") || resp.Header.Get("Access-Control-Max-Age") != "600" {
+		t.Fatalf("unexpected preflight response: %d %v", resp.StatusCode, resp.Header)
+	}
+
+	// Methods and headers outside the policy are refused
+	for _, header := range []http.Header{
+		{"Origin": {"https://dash.example.com"}, "Access-Control-Request-Method": {"DELETE"}},
+		{"Origin": {"https://dash.example.com"}, "Access-Control-Request-Method": {"POST"}, "Access-Control-Request-Headers": {"x-secret"}},
+		{"Origin": {"https://evil.example.com"}, "Access-Control-Request-Method": {"POST"}},
+	} {
+		resp := do(http.MethodOptions, "/team/list", header)
+		if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Methods") != "" {
+			t.Fatalf("expected preflight %v to be refused, got %d %v", header, resp.StatusCode, resp.Header)
+		}
+	}
+
+	// Actual responses, errors included, carry the origin and the readable headers
+	resp = do(http.MethodGet, "/team/list", http.Header{"Origin": {"https://ci.internal.example.com"}})
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("Access-Control-Allow-Origin") != "https://ci.internal.example.com" ||
+		!strings.Contains(resp.Header.Get("Access-Control-Expose-Headers"), "X-Request-Id") || resp.Header.Get("Vary") != "Origin" {
+		t.Fatalf("unexpected CORS response: %d %v", resp.StatusCode, resp.Header)
+	}
+	token := provider.sign(t, "RS256", "rsa-1", map[string]any{
+		"iss": provider.URL, "aud": "pr-service", "sub": "u1", "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	resp = do(http.MethodGet, "/team/list", http.Header{"Origin": {"https://internal.example.com"}, "Authorization": {"Bearer " + token}})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected no CORS headers for an origin outside the policy, got %d %v", resp.StatusCode, resp.Header)
+	}
+	resp = do(http.MethodGet, "/health", http.Header{})
+	if resp.Header.Get("Vary") != "" {
+		t.Fatalf("expected requests without an origin to pass through, got %v", resp.Header)
+	}
+}
+
+func TestHTTPE2ECompression(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+	for i := 0; i < 20; i++ {
+		s.addTeam(fmt.Sprintf("team-%02d", i), activeMember(fmt.Sprintf("u%02d", i), "Member"))
+	}
+
+	compressed := httptest.NewServer(middleware.Compress(middleware.CompressionPolicy{})(s.server.Config.Handler))
+	defer compressed.Close()
+	get := func(path, acceptEncoding string) (*http.Response, []byte) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, compressed.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		// Set explicitly so the client does not decompress transparently
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		resp, err := compressed.Client().Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		return resp, body
+	}
+
+	// Large lists are gzipped for clients that accept it
+	resp, body := get("/team/list?limit=100", "br;q=1.0, gzip;q=0.8")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Encoding") != "gzip" ||
+		resp.Header.Get("Content-Type") != "application/json" || resp.Header.Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected a gzipped list, got %d %v", resp.StatusCode, resp.Header)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("invalid gzip stream: %v", err)
+	}
+	var teams struct {
+		Teams []json.RawMessage `json:"teams"`
+	}
+	if err := json.NewDecoder(zr).Decode(&teams); err != nil || len(teams.Teams) != 20 {
+		t.Fatalf("unexpected decompressed list: %d teams, %v", len(teams.Teams), err)
+	}
+
+	// Other clients, small bodies and error responses below the minimum are sent as is
+	for _, c := range []struct {
+		path, acceptEncoding string
+	}{
+		{"/team/list?limit=100", ""},
+		{"/team/list?limit=100", "gzip;q=0, *"},
+		{"/health", "gzip"},
+		{"/team/get?team_name=missing", "gzip"},
+	} {
+		resp, body := get(c.path, c.acceptEncoding)
+		if resp.Header.Get("Content-Encoding") != "" || !json.Valid(body) {
+			t.Fatalf("%s with %q: expected a plain body, got %v", c.path, c.acceptEncoding, resp.Header)
+		}
+	}
+}
+
+func TestHTTPE2ERouteLimits(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	log := zap.NewNop()
+	mux := http.NewServeMux()
+	handleAPI(mux, "POST /team/add", middleware.Limit(middleware.RouteLimits{MaxBodySize: 256}, log)(s.server.Config.Handler).ServeHTTP)
+	mux.Handle("GET /slow", middleware.Limit(middleware.RouteLimits{Timeout: 20 * time.Millisecond}, log)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			middleware.WriteErrorResponse(w, fmt.Errorf("failed to list teams: %w", r.Context().Err()), log)
+		})))
+	mux.Handle("/", s.server.Config.Handler)
+	limited := httptest.NewServer(mux)
+	defer limited.Close()
+	s.base, s.client = limited.URL, limited.Client()
+
+	member := func(id string) map[string]any {
+		return map[string]any{"user_id": id, "username": "Member", "is_active": true}
+	}
+	s.addTeam("backend", activeMember("u1", "Member"))
+
+	// Bodies declared over the limit are refused before the handler runs
+	var members []map[string]any
+	for i := 0; i < 20; i++ {
+		members = append(members, member(fmt.Sprintf("u%02d", i)))
+	}
+	var tooLarge middleware.ErrorResponse
+	s.postJSON("/v1/team/add", map[string]any{"team_name": "platform", "members": members}, http.StatusRequestEntityTooLarge, &tooLarge)
+	if tooLarge.Error.Code != "PAYLOAD_TOO_LARGE" {
+		t.Fatalf("expected PAYLOAD_TOO_LARGE, got %+v", tooLarge)
+	}
+
+	// Streamed bodies are cut off at the limit
+	data, err := json.Marshal(map[string]any{"team_name": "platform", "members": members})
+	if err != nil {
+		t.Fatalf("failed to marshal request body: %v", err)
+	}
+	s.post("/team/add", "application/json", io.MultiReader(bytes.NewReader(data)), http.StatusBadRequest, nil)
+	s.getJSON("/team/get?team_name=platform", http.StatusNotFound, nil)
+
+	// Handlers past the deadline report a timeout
+	var timedOut middleware.ErrorResponse
+	s.getJSON("/slow", http.StatusServiceUnavailable, &timedOut)
+	if timedOut.Error.Code != "TIMEOUT" {
+		t.Fatalf("expected TIMEOUT, got %+v", timedOut)
+	}
+}
+
+func TestHTTPE2EReadiness(t *testing.T) {
+	database := &fakeDatabaseProbe{stats: db.PoolStats{AcquiredConns: 3, IdleConns: 1, TotalConns: 4, MaxConns: 4}}
+	health := handler.NewHealthHandler(handler.WithDatabase(database, zap.NewNop()))
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /livez", health.Check)
+	mux.HandleFunc("GET /readyz", health.Ready)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	s := &testServer{t: t, base: srv.URL, client: srv.Client()}
+
+	type readiness struct {
+		Status   string `json:"status"`
+		Draining bool   `json:"draining"`
+		Checks   []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"checks"`
+		Pool struct {
+			AcquiredConns int32   `json:"acquired_conns"`
+			MaxConns      int32   `json:"max_conns"`
+			Saturation    float64 `json:"saturation"`
+		} `json:"pool"`
+	}
+
+	// A saturated pool is reported but does not fail readiness
+	var ready readiness
+	s.getJSON("/readyz", http.StatusOK, &ready)
+	if ready.Status != "ready" || len(ready.Checks) != 1 || ready.Checks[0].Status != "ok" {
+		t.Fatalf("expected a passing database check, got %+v", ready)
+	}
+	if ready.Pool.AcquiredConns != 3 || ready.Pool.MaxConns != 4 || ready.Pool.Saturation != 0.75 {
+		t.Fatalf("unexpected pool stats: %+v", ready.Pool)
+	}
+
+	// An unreachable database fails readiness without leaking the cause
+	database.setErr(errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+	var unreachable readiness
+	s.getJSON("/readyz", http.StatusServiceUnavailable, &unreachable)
+	if unreachable.Status != "not_ready" || unreachable.Checks[0].Status != "fail" || strings.Contains(unreachable.Checks[0].Error, "10.0.0.5") {
+		t.Fatalf("expected a redacted failing database check, got %+v", unreachable)
+	}
+	s.getJSON("/livez", http.StatusOK, nil)
+
+	// Draining fails readiness while liveness keeps passing
+	database.setErr(nil)
+	health.SetDraining()
+	var draining readiness
+	s.getJSON("/readyz", http.StatusServiceUnavailable, &draining)
+	if draining.Status != "not_ready" || !draining.Draining {
+		t.Fatalf("expected draining to fail readiness, got %+v", draining)
+	}
+	s.getJSON("/livez", http.StatusOK, nil)
+}
+
+func TestHTTPE2ETrackInFlightRequests(t *testing.T) {
+	var requests lifecycle.Tracker
+	started, release := make(chan struct{}), make(chan struct{})
+	srv := httptest.NewServer(middleware.Track(&requests)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	defer srv.Close()
+
+	responses := make(chan int, 1)
+	go func() {
+		resp, err := srv.Client().Get(srv.URL)
+		if err != nil {
+			responses <- 0
+			return
+		}
+		resp.Body.Close()
+		responses <- resp.StatusCode
+	}()
+	<-started
+
+	// Shutdown waits for the request, bounded by its deadline
+	if got := requests.Active(); got != 1 {
+		t.Fatalf("expected 1 request in flight, got %d", got)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := requests.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to time out while the request runs, got %v", err)
+	}
+
+	close(release)
+	if err := requests.Wait(context.Background()); err != nil {
+		t.Fatalf("expected the wait to finish with the request, got %v", err)
+	}
+	if status := <-responses; status != http.StatusNoContent {
+		t.Fatalf("expected the request to complete, got status %d", status)
+	}
+}
+
+func TestHTTPE2EMaintenanceMode(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	// Routes are guarded by whether they write, as the app registers them
+	log := zap.NewNop()
+	sw, err := maintenance.NewSwitch("", "", 0)
+	if err != nil {
+		t.Fatalf("failed to create maintenance switch: %v", err)
+	}
+	maintenanceHandler := handler.NewMaintenanceHandler(sw, log)
+	mux := http.NewServeMux()
+	handleAPI(mux, "POST /team/add", middleware.Maintenance(sw, true, log)(s.server.Config.Handler).ServeHTTP)
+	handleAPI(mux, "GET /team/get", middleware.Maintenance(sw, false, log)(s.server.Config.Handler).ServeHTTP)
+	handleAPI(mux, "GET /admin/maintenance", maintenanceHandler.Get)
+	handleAPI(mux, "POST /admin/maintenance", maintenanceHandler.Set)
+	mux.Handle("/", s.server.Config.Handler)
+	guarded := httptest.NewServer(mux)
+	defer guarded.Close()
+	s.base, s.client = guarded.URL, guarded.Client()
+
+	addTeam := func(name string) *http.Response {
+		t.Helper()
+		body, err := json.Marshal(map[string]any{"team_name": name, "members": []map[string]any{{"user_id": "u-" + name, "username": "Member", "is_active": true}}})
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		resp, err := s.client.Post(s.base+"/team/add", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	type maintenanceState struct {
+		Maintenance struct {
+			Mode              string `json:"mode"`
+			Message           string `json:"message"`
+			RetryAfterSeconds int64  `json:"retry_after_seconds"`
+		} `json:"maintenance"`
+	}
+
+	if resp := addTeam("backend"); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected writes outside maintenance, got %d", resp.StatusCode)
+	}
+
+	// Read-only mode refuses writes with Retry-After and keeps reads up
+	var readOnly maintenanceState
+	s.postJSON("/admin/maintenance", map[string]any{"mode": "read_only", "message": "database migration", "retry_after": "2m"}, http.StatusOK, &readOnly)
+	if readOnly.Maintenance.Mode != "read_only" || readOnly.Maintenance.RetryAfterSeconds != 120 {
+		t.Fatalf("unexpected maintenance state: %+v", readOnly)
+	}
+	resp := addTeam("platform")
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") != "120" {
+		t.Fatalf("expected 503 with Retry-After 120, got %d %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	var refused middleware.ErrorResponse
+	s.postJSON("/v1/team/add", map[string]any{"team_name": "platform", "members": []map[string]any{}}, http.StatusServiceUnavailable, &refused)
+	if refused.Error.Code != "MAINTENANCE" || !strings.Contains(refused.Error.Message, "database migration") {
+		t.Fatalf("expected MAINTENANCE with the message, got %+v", refused)
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusOK, nil)
+
+	// Full maintenance refuses reads too, but not the switch itself
+	s.postJSON("/admin/maintenance", map[string]any{"mode": "full"}, http.StatusOK, nil)
+	s.getJSON("/team/get?team_name=backend", http.StatusServiceUnavailable, nil)
+	var full maintenanceState
+	s.getJSON("/admin/maintenance", http.StatusOK, &full)
+	if full.Maintenance.Mode != "full" || full.Maintenance.RetryAfterSeconds != int64(maintenance.DefaultRetryAfter/time.Second) {
+		t.Fatalf("unexpected maintenance state: %+v", full)
+	}
+
+	s.postJSON("/admin/maintenance", map[string]any{"mode": "paused"}, http.StatusBadRequest, nil)
+	s.postJSON("/admin/maintenance", map[string]any{"mode": "off"}, http.StatusOK, nil)
+	if resp := addTeam("platform"); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected writes after maintenance, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPE2ERequestLogSampling(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core)
+	policy := middleware.NewRequestLogPolicy(20*time.Millisecond, 1e-9)
+	requestLogHandler := handler.NewRequestLogHandler(policy, zap.NewNop())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /fast", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /fail", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadGateway) })
+	logged := middleware.Logging(log, policy)(mux)
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /admin/logging", requestLogHandler.Get)
+	admin.HandleFunc("POST /admin/logging", requestLogHandler.Set)
+	admin.Handle("/", logged)
+	srv := httptest.NewServer(admin)
+	defer srv.Close()
+	s := &testServer{t: t, base: srv.URL, client: srv.Client()}
+
+	reasons := func() map[string]string {
+		got := map[string]string{}
+		for _, entry := range logs.TakeAll() {
+			fields := entry.ContextMap()
+			got[fields["path"].(string)] = fields["log_reason"].(string)
+		}
+		return got
+	}
+
+	// Slow requests and server errors are logged; others are sampled away
+	for _, path := range []string{"/fast", "/slow", "/fail"} {
+		resp, err := s.client.Get(s.base + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if got := reasons(); !reflect.DeepEqual(got, map[string]string{"/slow": "slow", "/fail": "error"}) {
+		t.Fatalf("unexpected logged requests: %v", got)
+	}
+
+	// Thresholds change at runtime
+	type requestLog struct {
+		RequestLog struct {
+			SlowThresholdMs int64   `json:"slow_threshold_ms"`
+			SampleRatio     float64 `json:"sample_ratio"`
+		} `json:"request_log"`
+	}
+	var updated requestLog
+	s.postJSON("/admin/logging", map[string]any{"sample_ratio": 1}, http.StatusOK, &updated)
+	if updated.RequestLog.SampleRatio != 1 || updated.RequestLog.SlowThresholdMs != 20 {
+		t.Fatalf("unexpected thresholds: %+v", updated)
+	}
+	s.getJSON("/fast", http.StatusNoContent, nil)
+	if got := reasons(); got["/fast"] != "all" {
+		t.Fatalf("expected every request to be logged, got %v", got)
+	}
+
+	s.postJSON("/admin/logging", map[string]any{"slow_threshold": "fast"}, http.StatusBadRequest, nil)
+	s.postJSON("/admin/logging", map[string]any{"sample_ratio": 2}, http.StatusBadRequest, nil)
+	var current requestLog
+	s.getJSON("/admin/logging", http.StatusOK, &current)
+	if current != updated {
+		t.Fatalf("expected rejected updates to change nothing, got %+v", current)
+	}
+}
+
+func TestHTTPE2EDebugBodies(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core)
+	policy := middleware.NewRequestLogPolicy(0, 1)
+	requestLogHandler := handler.NewRequestLogHandler(policy, zap.NewNop())
+
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("POST /team/add", middleware.DebugBodies(policy, "POST /team/add", log)(http.HandlerFunc(echo)))
+	mux.Handle("POST /users/setIsActive", middleware.DebugBodies(policy, "POST /users/setIsActive", log)(http.HandlerFunc(echo)))
+	mux.HandleFunc("GET /admin/logging", requestLogHandler.Get)
+	mux.HandleFunc("POST /admin/logging", requestLogHandler.Set)
+	// Stands in for Authenticate: the test caller's role comes from a header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if role := r.Header.Get("X-Test-Role"); role != "" {
+			r = r.WithContext(domain.WithPrincipal(r.Context(), domain.Principal{UserID: "u1", Roles: []domain.Role{domain.Role(role)}}))
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	s := &testServer{t: t, base: srv.URL, client: srv.Client()}
+
+	post := func(path, role string, debug bool) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(`{"user_id":"u1","api_token":"hunter2","nested":{"Password":"p"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		if role != "" {
+			req.Header.Set("X-Test-Role", role)
+		}
+		if debug {
+			req.Header.Set(middleware.DebugBodiesHeader, "1")
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	logged := func() []map[string]any {
+		var got []map[string]any
+		for _, entry := range logs.FilterMessage("HTTP request bodies").TakeAll() {
+			got = append(got, entry.ContextMap())
+		}
+		logs.TakeAll()
+		return got
+	}
+
+	// Nothing is logged by default, and the header is ignored for non-admins
+	post("/team/add", "", true)
+	post("/team/add", string(domain.RoleLead), true)
+	if got := logged(); len(got) != 0 {
+		t.Fatalf("expected no bodies to be logged, got %v", got)
+	}
+
+	// Admins turn it on per request; credentials are redacted both ways
+	post("/team/add", string(domain.RoleAdmin), true)
+	got := logged()
+	if len(got) != 1 {
+		t.Fatalf("expected one logged request, got %v", got)
+	}
+	want := `{"api_token":"[REDACTED]","nested":{"Password":"[REDACTED]"},"user_id":"u1"}`
+	if got[0]["request_body"] != want || got[0]["response_body"] != want || got[0]["route"] != "POST /team/add" {
+		t.Fatalf("unexpected logged bodies: %v", got[0])
+	}
+
+	// Routes are turned on at runtime for every caller
+	var updated struct {
+		RequestLog struct {
+			DebugRoutes []string `json:"debug_routes"`
+		} `json:"request_log"`
+	}
+	s.postJSON("/admin/logging", map[string]any{"debug_routes": []string{"POST /users/setIsActive"}}, http.StatusOK, &updated)
+	if !reflect.DeepEqual(updated.RequestLog.DebugRoutes, []string{"POST /users/setIsActive"}) {
+		t.Fatalf("unexpected debug routes: %+v", updated)
+	}
+	post("/users/setIsActive", "", false)
+	post("/team/add", "", false)
+	if got := logged(); len(got) != 1 || got[0]["path"] != "/users/setIsActive" {
+		t.Fatalf("expected only the debugged route to be logged, got %v", got)
+	}
+
+	s.postJSON("/admin/logging", map[string]any{"debug_routes": []string{"/team/add"}}, http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2ESecurityHeaders(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	docsHandler := handler.NewDocsHandler("../../openapi.yml")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /docs", docsHandler.ServeSwaggerUI)
+	mux.Handle("/", s.server.Config.Handler)
+	secured := httptest.NewServer(middleware.SecurityHeaders(middleware.SecurityHeadersPolicy{
+		HSTSMaxAge:                365 * 24 * time.Hour,
+		HSTSIncludeSubdomains:     true,
+		DocsContentSecurityPolicy: handler.SwaggerUIContentSecurityPolicy(),
+		DocsPath:                  "/docs",
+	})(mux))
+	defer secured.Close()
+
+	get := func(path string) (http.Header, string) {
+		t.Helper()
+		resp, err := secured.Client().Get(secured.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		return resp.Header, string(body)
+	}
+
+	// API responses, errors included, forbid everything
+	h, _ := get("/team/get?team_name=missing")
+	want := map[string]string{
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "no-referrer",
+		"Content-Security-Policy":   middleware.DefaultContentSecurityPolicy,
+	}
+	for name, value := range want {
+		if got := h.Get(name); got != value {
+			t.Fatalf("expected %s %q, got %q", name, value, got)
+		}
+	}
+
+	// The docs page may run exactly its own inline script
+	h, body := get("/docs")
+	csp := h.Get("Content-Security-Policy")
+	start := strings.LastIndex(body, "<script>")
+	end := strings.LastIndex(body, "</script>")
+	if start < 0 || end < start {
+		t.Fatalf("inline script not found in docs page: %s", body)
+	}
+	sum := sha256.Sum256([]byte(body[start+len("<script>") : end]))
+	if hash := "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"; !strings.Contains(csp, hash) {
+		t.Fatalf("expected the docs policy to allow the inline script by %s, got %q", hash, csp)
+	}
+	if !strings.Contains(csp, "script-src https://unpkg.com") {
+		t.Fatalf("expected the docs policy to allow Swagger UI from unpkg.com, got %q", csp)
+	}
+}
+
+func TestHTTPE2EErrorCatalog(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	var catalog struct {
+		Errors []struct {
+			Code        string `json:"code"`
+			Status      int    `json:"status"`
+			Description string `json:"description"`
+		} `json:"errors"`
+	}
+	s.getJSON("/errors", http.StatusOK, &catalog)
+
+	statuses := make(map[string]int, len(catalog.Errors))
+	for _, e := range catalog.Errors {
+		if _, dup := statuses[e.Code]; dup || e.Status < 400 || e.Description == "" {
+			t.Fatalf("expected unique, described error codes, got %+v", catalog.Errors)
+		}
+		statuses[e.Code] = e.Status
+	}
+	if statuses["NOT_FOUND"] != http.StatusNotFound || statuses["RATE_LIMITED"] != http.StatusTooManyRequests ||
+		statuses["INTERNAL_ERROR"] != http.StatusInternalServerError {
+		t.Fatalf("expected codes with their statuses, got %v", statuses)
+	}
+
+	// Error responses carry a catalogued code with its status
+	var notFound middleware.ErrorResponse
+	s.getJSON("/team/get?team_name=missing", http.StatusNotFound, &notFound)
+	if statuses[notFound.Error.Code] != http.StatusNotFound {
+		t.Fatalf("expected %s to be catalogued as 404, got %v", notFound.Error.Code, statuses)
+	}
+
+	// The OpenAPI spec documents the same codes
+	spec, err := os.ReadFile("../../openapi.yml")
+	if err != nil {
+		t.Fatalf("failed to read the spec: %v", err)
+	}
+	var doc struct {
+		Components struct {
+			Schemas struct {
+				ErrorResponse struct {
+					Properties struct {
+						Error struct {
+							Properties struct {
+								Code struct {
+									Enum []string `yaml:"enum"`
+								} `yaml:"code"`
+							} `yaml:"properties"`
+						} `yaml:"error"`
+					} `yaml:"properties"`
+				} `yaml:"ErrorResponse"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		t.Fatalf("failed to parse the spec: %v", err)
+	}
+	documented := doc.Components.Schemas.ErrorResponse.Properties.Error.Properties.Code.Enum
+	for code := range statuses {
+		if !slices.Contains(documented, code) {
+			t.Fatalf("expected the spec to document %s, got %v", code, documented)
+		}
+	}
+}
+
+// fakeDatabaseProbe answers readiness pings with a configurable error
+type fakeDatabaseProbe struct {
+	mu    sync.Mutex
+	err   error
+	stats db.PoolStats
+}
+
+func (p *fakeDatabaseProbe) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func (p *fakeDatabaseProbe) Ping(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *fakeDatabaseProbe) PoolStats() db.PoolStats {
+	return p.stats
+}
@@ -0,0 +1,185 @@
+package e2e
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+	"pr-service/internal/handler"
+)
+
+func TestHTTPE2EPRQuery(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.addTeam("payments", activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+	for _, pr := range []map[string]string{
+		{"pull_request_id": "pr-1", "author_id": "u1", "repository": "acme/api"},
+		{"pull_request_id": "pr-2", "author_id": "u2", "repository": "acme/web"},
+		{"pull_request_id": "pr-3", "author_id": "u3", "repository": "acme/api"},
+	} {
+		pr["pull_request_name"] = "Change " + pr["pull_request_id"]
+		s.postJSON("/pullRequest/create", pr, http.StatusCreated, nil)
+	}
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-2"}, http.StatusOK, nil)
+	s.prRepo.BackdateCreation("pr-1", 72*time.Hour)
+
+	query := func(q string) string {
+		var list struct {
+			PullRequests []struct {
+				PullRequestID string `json:"pull_request_id"`
+			} `json:"pull_requests"`
+			Total int `json:"total"`
+		}
+		s.getJSON("/pullRequest/list?order=asc&q="+url.QueryEscape(q), http.StatusOK, &list)
+		ids := make([]string, len(list.PullRequests))
+		for i, pr := range list.PullRequests {
+			ids[i] = pr.PullRequestID
+		}
+		if list.Total != len(ids) {
+			t.Fatalf("q=%q: expected total %d, got %d", q, len(ids), list.Total)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	cases := map[string]string{
+		"":                                 "pr-1,pr-2,pr-3",
+		"status:open":                      "pr-1,pr-3",
+		"status:OPEN author:u1,u3":         "pr-1,pr-3",
+		"author:u1 author:u2":              "pr-1,pr-2",
+		`repo:"acme/api" team:payments`:    "pr-3",
+		"reviewer:u4":                      "pr-3",
+		"created:>" + yesterday:            "pr-2,pr-3",
+		"created:<=" + yesterday:           "pr-1",
+		"merged:>=2024-01-01":              "pr-2",
+		"status:merged repo:acme/api":      "",
+		"team:backend created:<2024-01-01": "",
+	}
+	for q, want := range cases {
+		if got := query(q); got != want {
+			t.Errorf("q=%q: expected %q, got %q", q, want, got)
+		}
+	}
+
+	var invalid middleware.ErrorResponse
+	s.getJSON("/pullRequest/list?q="+url.QueryEscape("label:payments"), http.StatusBadRequest, &invalid)
+	if len(invalid.Error.Details) != 1 || invalid.Error.Details[0].Field != "q" ||
+		!strings.Contains(invalid.Error.Details[0].Message, `unknown field "label"`) {
+		t.Fatalf("expected the unknown field to be reported, got %+v", invalid)
+	}
+	s.getJSON("/pullRequest/list?q="+url.QueryEscape("created:>tomorrow"), http.StatusBadRequest, nil)
+	s.getJSON("/pullRequest/list?status=open&q=status:merged", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EDeletePR(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.createPR("pr-1", "Add search", "u1")
+
+	var deleted createPRResponse
+	s.postJSON("/v1/pullRequest/delete", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, &deleted)
+	if deleted.PR.PullRequestID != "pr-1" || len(deleted.PR.AssignedReviewers) != 1 {
+		t.Fatalf("expected the deleted PR with its reviewer, got %+v", deleted.PR)
+	}
+
+	var reviews struct {
+		PullRequests []struct{} `json:"pull_requests"`
+	}
+	s.getJSON("/users/getReview?user_id=u2", http.StatusOK, &reviews)
+	if len(reviews.PullRequests) != 0 {
+		t.Fatalf("expected the reviewer to lose the deleted PR, got %+v", reviews)
+	}
+
+	s.postJSON("/pullRequest/delete", map[string]string{"pull_request_id": "pr-1"}, http.StatusNotFound, nil)
+	s.postJSON("/pullRequest/delete", map[string]string{"pull_request_id": " "}, http.StatusBadRequest, nil)
+	s.createPR("pr-1", "Add search again", "u1")
+}
+
+func TestHTTPE2EBatch(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+
+	type batchResult struct {
+		Op         string                  `json:"op"`
+		Status     string                  `json:"status"`
+		PR         *handler.PullRequestDTO `json:"pr"`
+		ReplacedBy string                  `json:"replaced_by"`
+		Error      *struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	type batchResponse struct {
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+		FailedIndex int           `json:"failed_index"`
+		Results     []batchResult `json:"results"`
+	}
+
+	// Later operations see the changes of earlier ones
+	created := s.createPR("pr-0", "Seed", "u1")
+	var ok batchResponse
+	s.postJSON("/v1/batch", map[string]any{"operations": []map[string]string{
+		{"op": "create", "pull_request_id": "pr-1", "pull_request_name": "Add refunds", "author_id": "u1", "repository": "payments"},
+		{"op": "reassign", "pull_request_id": "pr-0", "old_user_id": created.PR.AssignedReviewers[0]},
+		{"op": "merge", "pull_request_id": "pr-1"},
+	}}, http.StatusOK, &ok)
+	if len(ok.Results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", ok.Results)
+	}
+	for i, op := range []string{"create", "reassign", "merge"} {
+		if r := ok.Results[i]; r.Op != op || r.Status != "ok" || r.PR == nil || r.Error != nil {
+			t.Fatalf("unexpected result %d: %+v", i, r)
+		}
+	}
+	if pr := ok.Results[0].PR; pr.PullRequestID != "pr-1" || pr.Repository != "payments" || len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("unexpected created PR %+v", pr)
+	}
+	if r := ok.Results[1]; r.ReplacedBy == "" || r.ReplacedBy == created.PR.AssignedReviewers[0] ||
+		!slices.Contains(r.PR.AssignedReviewers, r.ReplacedBy) {
+		t.Fatalf("unexpected reassignment %+v", r)
+	}
+	if ok.Results[2].PR.Status != "MERGED" {
+		t.Fatalf("expected pr-1 to be merged, got %+v", ok.Results[2].PR)
+	}
+
+	// A failed operation fails the batch: it carries the error, the ones before
+	// it are rolled back and the ones after it are skipped
+	var failed batchResponse
+	s.postJSON("/batch", map[string]any{"operations": []map[string]string{
+		{"op": "create", "pull_request_id": "pr-2", "pull_request_name": "Add invoices", "author_id": "u1"},
+		{"op": "create", "pull_request_id": "pr-1", "pull_request_name": "Duplicate", "author_id": "u1"},
+		{"op": "merge", "pull_request_id": "pr-2"},
+	}}, http.StatusConflict, &failed)
+	if failed.Error == nil || failed.Error.Code != string(domain.ErrorCodePRExists) || failed.FailedIndex != 1 ||
+		!strings.HasPrefix(failed.Error.Message, "operation 1:") {
+		t.Fatalf("unexpected batch error %+v", failed)
+	}
+	statuses := make([]string, len(failed.Results))
+	for i, r := range failed.Results {
+		statuses[i] = r.Status
+	}
+	if !slices.Equal(statuses, []string{"rolled_back", "failed", "skipped"}) ||
+		failed.Results[1].Error == nil || failed.Results[1].Error.Code != string(domain.ErrorCodePRExists) {
+		t.Fatalf("unexpected batch results %+v", failed.Results)
+	}
+
+	s.postJSON("/batch", map[string]any{"operations": []map[string]string{{"op": "close", "pull_request_id": "pr-1"}}},
+		http.StatusBadRequest, &failed)
+	if failed.FailedIndex != 0 || failed.Error.Code != string(domain.ErrorCodeInvalidArgument) {
+		t.Fatalf("expected the unknown op to be rejected, got %+v", failed)
+	}
+	s.postJSON("/batch", map[string]any{"operations": []map[string]string{}}, http.StatusBadRequest, nil)
+}
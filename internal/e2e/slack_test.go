@@ -0,0 +1,221 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-service/internal/notify"
+)
+
+// testSlackUsers maps test users to Slack member IDs; u4 has no Slack account
+var testSlackUsers = map[string]string{"u1": "S1", "u2": "S2", "u3": "S3"}
+
+type slackDM struct {
+	channel string
+	text    string
+}
+
+type recordingMessenger struct {
+	mu       sync.Mutex
+	messages []slackDM
+}
+
+func (m *recordingMessenger) PostMessage(_ context.Context, channel, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, slackDM{channel: channel, text: text})
+	return nil
+}
+
+// take returns and forgets the recorded messages
+func (m *recordingMessenger) take() []slackDM {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	messages := m.messages
+	m.messages = nil
+	return messages
+}
+
+// flushSlack sends every queued Slack event, as the notifications worker would
+func (s *testServer) flushSlack() {
+	s.t.Helper()
+	for {
+		select {
+		case event := <-s.slack.Pending():
+			if err := s.slack.Send(context.Background(), event); err != nil {
+				s.t.Fatalf("send slack notification: %v", err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func TestHTTPE2ESlackNotifications(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+	created := s.createPR("pr-1", "Add refunds", "u1")
+	s.flushSlack()
+
+	reviewers := created.PR.AssignedReviewers
+	expected := 0
+	for _, id := range reviewers {
+		if _, ok := testSlackUsers[id]; ok {
+			expected++
+		}
+	}
+	messages := s.slackDMs.take()
+	if len(messages) != expected {
+		t.Fatalf("expected %d assignment DMs for reviewers %v, got %+v", expected, reviewers, messages)
+	}
+	for _, m := range messages {
+		if m.channel == "S1" || !strings.Contains(m.text, "You were assigned to review *Add refunds* (`pr-1`)") {
+			t.Fatalf("unexpected assignment DM %+v", m)
+		}
+	}
+
+	var reassigned reassignResponse
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     reviewers[0],
+	}, http.StatusOK, &reassigned)
+	s.flushSlack()
+
+	byChannel := make(map[string]string)
+	for _, m := range s.slackDMs.take() {
+		byChannel[m.channel] = m.text
+	}
+	if slackID, ok := testSlackUsers[reviewers[0]]; ok && !strings.Contains(byChannel[slackID], "taken off the review") {
+		t.Fatalf("expected %s to be told about the handoff, got %v", reviewers[0], byChannel)
+	}
+	if slackID, ok := testSlackUsers[reassigned.ReplacedBy]; ok && !strings.Contains(byChannel[slackID], "taking over from") {
+		t.Fatalf("expected %s to be told about the new review, got %v", reassigned.ReplacedBy, byChannel)
+	}
+
+	// A merged PR does not message anyone
+	s.createPR("pr-2", "Fix rounding", "u2")
+	s.flushSlack()
+	s.slackDMs.take()
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-2"}, http.StatusOK, nil)
+	s.flushSlack()
+	if messages := s.slackDMs.take(); len(messages) != 0 {
+		t.Fatalf("expected no DMs on merge, got %+v", messages)
+	}
+
+	// Only reviews left untouched past the threshold are reminded, and only once
+	current := []string{reviewers[1], reassigned.ReplacedBy}
+	for _, id := range current {
+		s.prRepo.BackdateAssignment("pr-1", id, 72*time.Hour)
+	}
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": current[0]}, http.StatusOK, nil)
+
+	ctx := context.Background()
+	found, err := s.slack.NotifyStaleReviews(ctx, time.Now(), 10)
+	if err != nil || found != 1 {
+		t.Fatalf("expected one stale review, got %d (%v)", found, err)
+	}
+	messages = s.slackDMs.take()
+	if slackID, ok := testSlackUsers[current[1]]; ok {
+		if len(messages) != 1 || messages[0].channel != slackID || !strings.Contains(messages[0].text, "has been waiting for your review") {
+			t.Fatalf("expected a stale reminder to %s, got %+v", slackID, messages)
+		}
+	} else if len(messages) != 0 {
+		t.Fatalf("expected no reminder for an unmapped reviewer, got %+v", messages)
+	}
+	if found, err := s.slack.NotifyStaleReviews(ctx, time.Now(), 10); err != nil || found != 0 {
+		t.Fatalf("expected stale reviews to be reminded once, got %d (%v)", found, err)
+	}
+}
+
+func TestHTTPE2ESlackDigest(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+	for _, pr := range []map[string]string{
+		{"pull_request_id": "pr-old", "pull_request_name": "Add refunds", "author_id": "u4"},
+		{"pull_request_id": "pr-soon", "pull_request_name": "Fix rounding", "author_id": "u4"},
+		{"pull_request_id": "pr-new", "pull_request_name": "Drop legacy API", "author_id": "u4"},
+		{"pull_request_id": "pr-done", "pull_request_name": "Bump deps", "author_id": "u4"},
+	} {
+		created := s.createPR(pr["pull_request_id"], pr["pull_request_name"], pr["author_id"])
+		// Pin the reviewers so every mapped user has the same reviews
+		for _, id := range created.PR.AssignedReviewers {
+			s.prRepo.RemoveReviewer(context.Background(), pr["pull_request_id"], id)
+		}
+		s.prRepo.AssignReviewers(context.Background(), pr["pull_request_id"], []string{"u1"})
+	}
+	s.flushSlack()
+	s.slackDMs.take()
+
+	now := time.Now()
+	s.prRepo.BackdateAssignment("pr-old", "u1", 30*time.Hour)
+	s.prRepo.BackdateAssignment("pr-soon", "u1", 20*time.Hour)
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-done", "user_id": "u1"}, http.StatusOK, nil)
+
+	sent, err := s.slack.SendDigests(context.Background(), now.Add(-time.Hour), now, now.Add(12*time.Hour))
+	if err != nil || sent != 1 {
+		t.Fatalf("expected one digest, got %d (%v)", sent, err)
+	}
+	messages := s.slackDMs.take()
+	if len(messages) != 1 || messages[0].channel != "S1" {
+		t.Fatalf("expected a digest to u1 only, got %+v", messages)
+	}
+	text := messages[0].text
+	overdue := strings.Index(text, "*Overdue*")
+	soon := strings.Index(text, "*Due before the next digest*")
+	fresh := strings.Index(text, "*Newly assigned*")
+	if !strings.HasPrefix(text, "Your review digest: 3 pending reviews.") || overdue < 0 || soon < overdue || fresh < soon ||
+		!strings.Contains(text[overdue:soon], "`pr-old`") || !strings.Contains(text[soon:fresh], "`pr-soon`") ||
+		!strings.Contains(text[fresh:], "`pr-new`") || strings.Contains(text, "pr-done") || strings.Contains(text, "Still waiting") {
+		t.Fatalf("unexpected digest:\n%s", text)
+	}
+
+	// Reviews assigned before the previous digest are no longer new
+	s.slack.SendDigests(context.Background(), now.Add(time.Minute), now.Add(time.Minute), now.Add(12*time.Hour))
+	if messages := s.slackDMs.take(); len(messages) != 1 || !strings.Contains(messages[0].text, "*Still waiting*") ||
+		strings.Contains(messages[0].text, "Newly assigned") {
+		t.Fatalf("expected pr-new to be still waiting, got %+v", messages)
+	}
+}
+
+func TestHTTPE2ESlackClient(t *testing.T) {
+	var received struct {
+		auth    string
+		channel string
+		text    string
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Channel string `json:"channel"`
+			Text    string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received.auth, received.channel, received.text = r.Header.Get("Authorization"), body.Channel, body.Text
+		if r.URL.Path != "/chat.postMessage" || body.Channel == "" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+
+	client := notify.NewSlack("xoxb-test", api.URL+"/", 0)
+	if err := client.PostMessage(context.Background(), "S1", "hello"); err != nil {
+		t.Fatalf("post message: %v", err)
+	}
+	if received.auth != "Bearer xoxb-test" || received.channel != "S1" || received.text != "hello" {
+		t.Fatalf("unexpected request %+v", received)
+	}
+	if err := client.PostMessage(context.Background(), "", "hello"); err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("expected the Slack API error to be returned, got %v", err)
+	}
+}
@@ -0,0 +1,830 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"pr-service/internal/cache"
+	"pr-service/internal/notify"
+	"pr-service/internal/service/pullrequest"
+	"pr-service/internal/service/report"
+	"pr-service/internal/service/rollup"
+)
+
+func TestHTTPE2EAssignmentStatsByTeam(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.addTeam("frontend", activeMember("u3", "Charlie"), activeMember("u4", "Dana"), activeMember("u5", "Eve"))
+	s.postJSON("/users/add", map[string]string{
+		"user_id":   "u2",
+		"username":  "Bob",
+		"team_name": "frontend",
+	}, http.StatusOK, nil)
+
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add search",
+		"author_id":         "u1",
+		"team_name":         "backend",
+	}, http.StatusCreated, nil)
+	s.createPR("pr-2", "Add button", "u3")
+
+	var stats statsResponse
+	s.getJSON("/stats/assignments", http.StatusOK, &stats)
+	// pr-1 goes to u2 (backend and frontend), pr-2 to two of u2, u4, u5 (frontend)
+	if stats.ByTeam["frontend"] != 3 {
+		t.Fatalf("expected three frontend assignments, got %v", stats.ByTeam)
+	}
+	if expected := stats.userCount("u2"); stats.ByTeam["backend"] != expected {
+		t.Fatalf("expected %d backend assignments, got %v", expected, stats.ByTeam)
+	}
+
+	var old statsResponse
+	s.getJSON("/stats/assignments?from=2000-01-01T00:00:00Z&to=2000-02-01T00:00:00Z", http.StatusOK, &old)
+	if len(old.ByUser) != 0 || len(old.ByPR) != 0 || len(old.ByTeam) != 0 {
+		t.Fatalf("expected no assignments in an old window, got %+v", old)
+	}
+	s.getJSON("/stats/assignments?from=2000-02-01T00:00:00Z&to=2000-01-01T00:00:00Z", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EAssignmentStatsPagination(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"))
+
+	// u3 reviews all three PRs, u2 the two by u1 and u1 the one by u2
+	for i, author := range []string{"u1", "u1", "u2"} {
+		s.createPR(fmt.Sprintf("pr-%d", i+1), "Change", author)
+	}
+
+	var top statsResponse
+	s.getJSON("/stats/assignments?limit=2", http.StatusOK, &top)
+	if top.TotalUsers != 3 || top.TotalPRs != 3 {
+		t.Fatalf("expected totals of 3 users and 3 PRs, got %d and %d", top.TotalUsers, top.TotalPRs)
+	}
+	if len(top.ByUser) != 2 || top.ByUser[0].UserID != "u3" || top.ByUser[1].UserID != "u2" {
+		t.Fatalf("expected u3 then u2 on the first page, got %+v", top.ByUser)
+	}
+	if len(top.ByPR) != 2 || top.ByPR[0].PullRequestID != "pr-1" {
+		t.Fatalf("expected PRs ordered by key among equal counts, got %+v", top.ByPR)
+	}
+
+	var next statsResponse
+	s.getJSON("/stats/assignments?limit=2&offset=2", http.StatusOK, &next)
+	if len(next.ByUser) != 1 || next.ByUser[0].UserID != "u1" || next.ByUser[0].Count != 1 {
+		t.Fatalf("expected u1 on the second page, got %+v", next.ByUser)
+	}
+
+	var asc statsResponse
+	s.getJSON("/stats/assignments?sort=count_asc&limit=1", http.StatusOK, &asc)
+	if len(asc.ByUser) != 1 || asc.ByUser[0].UserID != "u1" {
+		t.Fatalf("expected u1 first in ascending order, got %+v", asc.ByUser)
+	}
+
+	var byKey statsResponse
+	s.getJSON("/stats/assignments?sort=key", http.StatusOK, &byKey)
+	if len(byKey.ByUser) != 3 || byKey.ByUser[0].UserID != "u1" || byKey.ByUser[2].UserID != "u3" {
+		t.Fatalf("expected users ordered by id, got %+v", byKey.ByUser)
+	}
+
+	s.getJSON("/stats/assignments?sort=random", http.StatusBadRequest, nil)
+	s.getJSON("/stats/assignments?limit=-1", http.StatusBadRequest, nil)
+	s.getJSON("/stats/assignments?limit=100000", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EWorkloadStats(t *testing.T) {
+	s := newTestServer(t, pullrequest.WithReviewCapacity(2))
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"), inactiveMember("u4", "Dana"))
+
+	// Every PR by u1 goes to u2 and u3; the merged one no longer counts
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		s.createPR(id, "Change", "u1")
+	}
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-3"}, http.StatusOK, nil)
+
+	var workload struct {
+		Users []struct {
+			UserID      string  `json:"user_id"`
+			Username    string  `json:"username"`
+			OpenReviews int     `json:"open_reviews"`
+			Capacity    int     `json:"capacity"`
+			Utilization float64 `json:"utilization_percent"`
+		} `json:"users"`
+	}
+	s.getJSON("/stats/workload", http.StatusOK, &workload)
+	if len(workload.Users) != 3 {
+		t.Fatalf("expected the three active users, got %+v", workload.Users)
+	}
+	busiest := workload.Users[0]
+	if busiest.UserID != "u2" || busiest.OpenReviews != 2 || busiest.Capacity != 2 || busiest.Utilization != 100 {
+		t.Fatalf("expected u2 at full capacity first, got %+v", busiest)
+	}
+	if idle := workload.Users[2]; idle.UserID != "u1" || idle.OpenReviews != 0 || idle.Utilization != 0 {
+		t.Fatalf("expected idle u1 last, got %+v", idle)
+	}
+}
+
+func TestHTTPE2EAuthorStats(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"))
+
+	for i, author := range []string{"u1", "u1", "u2"} {
+		s.createPR(fmt.Sprintf("pr-%d", i+1), "Change", author)
+	}
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+	s.prRepo.BackdateCreation("pr-2", time.Hour)
+	s.postJSON("/pullRequest/review", map[string]string{
+		"pull_request_id": "pr-2",
+		"user_id":         "u2",
+	}, http.StatusOK, nil)
+
+	type authorStats struct {
+		AuthorID             string   `json:"author_id"`
+		TeamName             string   `json:"team_name"`
+		PRsCreated           int      `json:"prs_created"`
+		PRsMerged            int      `json:"prs_merged"`
+		MergeRate            float64  `json:"merge_rate"`
+		AvgReviewers         float64  `json:"avg_reviewers"`
+		AvgReviewWaitSeconds *float64 `json:"avg_review_wait_seconds"`
+	}
+	var stats struct {
+		ByAuthor []authorStats `json:"by_author"`
+		ByTeam   []authorStats `json:"by_team"`
+	}
+	s.getJSON("/stats/authors", http.StatusOK, &stats)
+
+	if len(stats.ByAuthor) != 2 {
+		t.Fatalf("expected two authors, got %+v", stats.ByAuthor)
+	}
+	alice := stats.ByAuthor[0]
+	if alice.AuthorID != "u1" || alice.PRsCreated != 2 || alice.PRsMerged != 1 || alice.MergeRate != 0.5 || alice.AvgReviewers != 2 {
+		t.Fatalf("unexpected stats for u1: %+v", alice)
+	}
+	if alice.AvgReviewWaitSeconds == nil || *alice.AvgReviewWaitSeconds < 3600 {
+		t.Fatalf("expected u1 PRs to wait about an hour for review, got %v", alice.AvgReviewWaitSeconds)
+	}
+	if bob := stats.ByAuthor[1]; bob.AuthorID != "u2" || bob.PRsCreated != 1 || bob.AvgReviewWaitSeconds != nil {
+		t.Fatalf("expected one unreviewed PR for u2, got %+v", bob)
+	}
+	if len(stats.ByTeam) != 1 || stats.ByTeam[0].TeamName != "backend" || stats.ByTeam[0].PRsCreated != 3 {
+		t.Fatalf("expected three backend PRs, got %+v", stats.ByTeam)
+	}
+
+	s.getJSON("/stats/authors?from=2000-02-01T00:00:00Z&to=2000-01-01T00:00:00Z", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EReviewPairs(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"))
+	s.addTeam("frontend", activeMember("u4", "Dana"), activeMember("u5", "Eve"))
+
+	for i, author := range []string{"u1", "u1", "u4"} {
+		s.createPR(fmt.Sprintf("pr-%d", i+1), "Change", author)
+	}
+
+	type pairsResponse struct {
+		Authors   []string `json:"authors"`
+		Reviewers []string `json:"reviewers"`
+		Counts    [][]int  `json:"counts"`
+	}
+	var all pairsResponse
+	s.getJSON("/stats/pairs", http.StatusOK, &all)
+	if !slices.Equal(all.Authors, []string{"u1", "u4"}) || !slices.Equal(all.Reviewers, []string{"u2", "u3", "u5"}) {
+		t.Fatalf("unexpected axes: %+v", all)
+	}
+	expected := [][]int{{2, 2, 0}, {0, 0, 1}}
+	for i := range expected {
+		if !slices.Equal(all.Counts[i], expected[i]) {
+			t.Fatalf("expected counts %v, got %v", expected, all.Counts)
+		}
+	}
+
+	var frontend pairsResponse
+	s.getJSON("/stats/pairs?team_name=frontend", http.StatusOK, &frontend)
+	if !slices.Equal(frontend.Authors, []string{"u4"}) || !slices.Equal(frontend.Reviewers, []string{"u5"}) {
+		t.Fatalf("expected only frontend pairs, got %+v", frontend)
+	}
+
+	s.getJSON("/stats/pairs?from=2000-02-01T00:00:00Z&to=2000-01-01T00:00:00Z", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EReassignmentStats(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"), activeMember("u4", "Dana"))
+	s.addTeam("frontend", activeMember("u5", "Eve"), activeMember("u6", "Frank"))
+
+	pr1 := s.createPR("pr-1", "Add search", "u1")
+	s.createPR("pr-2", "Add button", "u5")
+
+	// A backend reviewer is replaced; u6 has no replacement in frontend, so the review is closed
+	swapped := pr1.PR.AssignedReviewers[0]
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     swapped,
+	}, http.StatusOK, nil)
+	s.postJSON("/users/delete", map[string]string{"user_id": "u6"}, http.StatusOK, nil)
+
+	type reassignmentStats struct {
+		UserID   string `json:"user_id"`
+		TeamName string `json:"team_name"`
+		Total    int    `json:"total"`
+		Replaced int    `json:"replaced"`
+		Closed   int    `json:"closed"`
+	}
+	var stats struct {
+		ByUser []reassignmentStats `json:"by_user"`
+		ByTeam []reassignmentStats `json:"by_team"`
+	}
+	s.getJSON("/stats/reassignments", http.StatusOK, &stats)
+
+	expectedUsers := []reassignmentStats{
+		{UserID: swapped, Total: 1, Replaced: 1},
+		{UserID: "u6", Total: 1, Closed: 1},
+	}
+	if !slices.Equal(stats.ByUser, expectedUsers) {
+		t.Fatalf("expected %+v by user, got %+v", expectedUsers, stats.ByUser)
+	}
+	expectedTeams := []reassignmentStats{
+		{TeamName: "backend", Total: 1, Replaced: 1},
+		{TeamName: "frontend", Total: 1, Closed: 1},
+	}
+	if !slices.Equal(stats.ByTeam, expectedTeams) {
+		t.Fatalf("expected %+v by team, got %+v", expectedTeams, stats.ByTeam)
+	}
+
+	var old struct {
+		ByUser []reassignmentStats `json:"by_user"`
+	}
+	s.getJSON("/stats/reassignments?from=2000-01-01T00:00:00Z&to=2000-02-01T00:00:00Z", http.StatusOK, &old)
+	if len(old.ByUser) != 0 {
+		t.Fatalf("expected no reassignments in an old window, got %+v", old.ByUser)
+	}
+}
+
+func TestHTTPE2EWeeklyReportDelivery(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	received := make(chan []byte, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer hook.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		s.createPR(id, "Change", "u1")
+	}
+
+	// pr-1 is reviewed in time, pr-2 has waited past the SLA and pr-3 is not due yet
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u2"}, http.StatusOK, nil)
+	s.prRepo.BackdateAssignment("pr-2", "u2", 2*time.Hour)
+
+	service := report.NewService(s.pr, notify.NewWebhook(hook.URL, 0), time.Hour)
+	if err := service.DeliverWeeklyReport(context.Background(), time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("deliver weekly report: %v", err)
+	}
+
+	var payload struct {
+		Text   string `json:"text"`
+		Report struct {
+			Assignments map[string]int `json:"assignments_by_team"`
+			SLA         []struct {
+				TeamName   string  `json:"team_name"`
+				Due        int     `json:"due"`
+				Met        int     `json:"met"`
+				Compliance float64 `json:"compliance"`
+			} `json:"sla"`
+			Fairness []struct {
+				TeamName string `json:"team_name"`
+			} `json:"fairness"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal(<-received, &payload); err != nil {
+		t.Fatalf("decode webhook payload: %v", err)
+	}
+	if payload.Report.Assignments["backend"] != 3 {
+		t.Fatalf("expected three backend assignments, got %v", payload.Report.Assignments)
+	}
+	if len(payload.Report.SLA) != 1 || payload.Report.SLA[0].Due != 2 || payload.Report.SLA[0].Met != 1 {
+		t.Fatalf("expected one of two due reviews within the SLA, got %+v", payload.Report.SLA)
+	}
+	if len(payload.Report.Fairness) != 1 || payload.Report.Fairness[0].TeamName != "backend" {
+		t.Fatalf("expected backend fairness, got %+v", payload.Report.Fairness)
+	}
+	if !strings.Contains(payload.Text, "backend: 50% (1/2)") {
+		t.Fatalf("expected SLA line in the rendered text, got %q", payload.Text)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	service = report.NewService(s.pr, notify.NewWebhook(failing.URL, 0), time.Hour)
+	if err := service.DeliverWeeklyReport(context.Background(), time.Now()); err == nil {
+		t.Fatalf("expected a failed delivery to be reported")
+	}
+}
+
+func TestHTTPE2EStatsCache(t *testing.T) {
+	s := newTestServer(t, pullrequest.WithStatsCache(cache.New("stats", time.Hour)))
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.createPR("pr-1", "Add search", "u1")
+
+	path := "/stats/assignments?from=2000-01-01T00:00:00Z&to=2100-01-01T00:00:00Z"
+	var first statsResponse
+	s.getJSON(path, http.StatusOK, &first)
+	if first.TotalPRs != 1 {
+		t.Fatalf("expected one PR, got %+v", first)
+	}
+
+	// Changes behind the service's back are not seen until the entry expires...
+	s.prRepo.BackdateAssignment("pr-1", "u2", 100*365*24*time.Hour)
+	var cached statsResponse
+	s.getJSON(path, http.StatusOK, &cached)
+	if cached.TotalPRs != 1 {
+		t.Fatalf("expected the cached result, got %+v", cached)
+	}
+
+	// ...but writes through the service invalidate it
+	s.createPR("pr-2", "Add button", "u1")
+	var fresh statsResponse
+	s.getJSON(path, http.StatusOK, &fresh)
+	if fresh.TotalPRs != 1 || len(fresh.ByPR) != 1 || fresh.ByPR[0].PullRequestID != "pr-2" {
+		t.Fatalf("expected only pr-2 after invalidation, got %+v", fresh)
+	}
+}
+
+func TestHTTPE2EFairnessStats(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"), inactiveMember("u4", "Dana"))
+	s.addTeam("frontend", activeMember("u5", "Eve"), activeMember("u6", "Frank"))
+
+	s.createPR("pr-1", "Add search", "u1")
+	s.createPR("pr-2", "Add button", "u5")
+	s.createPR("pr-3", "Fix button", "u6")
+
+	type teamFairness struct {
+		TeamName      string   `json:"team_name"`
+		ActiveMembers int      `json:"active_members"`
+		Assignments   int      `json:"assignments"`
+		Gini          float64  `json:"gini"`
+		MaxMinRatio   *float64 `json:"max_min_ratio"`
+	}
+	var stats struct {
+		Teams []teamFairness `json:"teams"`
+	}
+	s.getJSON("/stats/fairness", http.StatusOK, &stats)
+	if len(stats.Teams) != 2 {
+		t.Fatalf("expected two teams, got %+v", stats.Teams)
+	}
+
+	backend := stats.Teams[0]
+	if backend.TeamName != "backend" || backend.ActiveMembers != 3 || backend.Assignments != 2 {
+		t.Fatalf("expected two assignments over three active backend members, got %+v", backend)
+	}
+	if backend.Gini < 0.33 || backend.Gini > 0.34 {
+		t.Fatalf("expected a backend gini of 1/3, got %f", backend.Gini)
+	}
+	if backend.MaxMinRatio != nil {
+		t.Fatalf("expected no max/min ratio while u1 has no reviews, got %f", *backend.MaxMinRatio)
+	}
+
+	frontend := stats.Teams[1]
+	if frontend.TeamName != "frontend" || frontend.Assignments != 2 || frontend.Gini != 0 {
+		t.Fatalf("expected an even frontend load, got %+v", frontend)
+	}
+	if frontend.MaxMinRatio == nil || *frontend.MaxMinRatio != 1 {
+		t.Fatalf("expected a frontend max/min ratio of 1, got %v", frontend.MaxMinRatio)
+	}
+
+	s.getJSON("/stats/fairness?from=2000-02-01T00:00:00Z&to=2000-01-01T00:00:00Z", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EOpenPRAging(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.addTeam("frontend", activeMember("u3", "Charlie"))
+
+	ages := map[string]time.Duration{
+		"pr-1": time.Hour,
+		"pr-2": 2 * 24 * time.Hour,
+		"pr-3": 5 * 24 * time.Hour,
+		"pr-4": 10 * 24 * time.Hour,
+		"pr-5": 30 * 24 * time.Hour,
+	}
+	for _, id := range []string{"pr-1", "pr-2", "pr-3", "pr-4", "pr-5"} {
+		s.createPR(id, "Change "+id, "u1")
+		s.prRepo.BackdateCreation(id, ages[id])
+	}
+	s.createPR("pr-6", "Frontend change", "u3")
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-5"}, http.StatusOK, nil)
+
+	type aging struct {
+		TeamName  string `json:"team_name"`
+		Under1d   int    `json:"under_1d"`
+		From1dTo3 int    `json:"from_1d_to_3d"`
+		From3dTo7 int    `json:"from_3d_to_7d"`
+		Over7d    int    `json:"over_7d"`
+		Total     int    `json:"total"`
+	}
+	var report struct {
+		AsOf  time.Time `json:"as_of"`
+		Teams []aging   `json:"teams"`
+	}
+	s.getJSON("/stats/aging", http.StatusOK, &report)
+	if report.AsOf.IsZero() || len(report.Teams) != 2 {
+		t.Fatalf("expected an aging report for two teams, got %+v", report)
+	}
+	expected := aging{TeamName: "backend", Under1d: 1, From1dTo3: 1, From3dTo7: 1, Over7d: 1, Total: 4}
+	if report.Teams[0] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, report.Teams[0])
+	}
+	if frontend := report.Teams[1]; frontend.TeamName != "frontend" || frontend.Under1d != 1 || frontend.Total != 1 {
+		t.Fatalf("expected one fresh frontend PR, got %+v", frontend)
+	}
+
+	rows := s.getCSV("/stats/aging?format=csv", "")
+	if len(rows) != 3 || rows[1][0] != "backend" || rows[1][5] != "4" {
+		t.Fatalf("expected csv rows for both teams, got %v", rows)
+	}
+}
+
+func TestHTTPE2EDailyStatsRollup(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"), activeMember("u4", "Dana"))
+
+	pr1 := s.createPR("pr-1", "Add search", "u1")
+	s.createPR("pr-2", "Fix search", "u1")
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     pr1.PR.AssignedReviewers[0],
+	}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-2"}, http.StatusOK, nil)
+
+	type day struct {
+		Day           string `json:"day"`
+		TeamName      string `json:"team_name"`
+		Assignments   int    `json:"assignments"`
+		Merges        int    `json:"merges"`
+		Reassignments int    `json:"reassignments"`
+	}
+	var daily struct {
+		Days []day `json:"days"`
+	}
+	s.getJSON("/stats/daily", http.StatusOK, &daily)
+	if len(daily.Days) != 0 {
+		t.Fatalf("expected no days before the rollup ran, got %+v", daily.Days)
+	}
+
+	tomorrow := time.Now().UTC().Add(24 * time.Hour)
+	rolled, err := s.rollup.RollUpDueDays(context.Background(), tomorrow)
+	if err != nil || rolled != rollup.DefaultBackfillDays {
+		t.Fatalf("expected %d backfilled days, got %d (%v)", rollup.DefaultBackfillDays, rolled, err)
+	}
+	if rolled, err := s.rollup.RollUpDueDays(context.Background(), tomorrow); err != nil || rolled != 0 {
+		t.Fatalf("expected nothing left to roll up, got %d (%v)", rolled, err)
+	}
+
+	s.getJSON("/stats/daily", http.StatusOK, &daily)
+	expected := day{
+		Day:           time.Now().UTC().Format(time.DateOnly),
+		TeamName:      "backend",
+		Assignments:   4,
+		Merges:        1,
+		Reassignments: 1,
+	}
+	if len(daily.Days) != 1 || daily.Days[0] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, daily.Days)
+	}
+
+	s.getJSON("/stats/daily?team_name=frontend", http.StatusOK, &daily)
+	if len(daily.Days) != 0 {
+		t.Fatalf("expected no days for another team, got %+v", daily.Days)
+	}
+	rows := s.getCSV("/stats/daily?format=csv", "")
+	if len(rows) != 2 || rows[1][1] != "backend" || rows[1][4] != "1" {
+		t.Fatalf("expected one csv row, got %v", rows)
+	}
+}
+
+func TestHTTPE2EStatsCSVExport(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.createPR("pr-1", "Add search", "u1")
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u2"}, http.StatusOK, nil)
+
+	assignments := s.getCSV("/stats/assignments?format=csv", "")
+	expected := [][]string{
+		{"group", "key", "count"},
+		{"by_user", "u2", "1"},
+		{"by_pr", "pr-1", "1"},
+		{"by_role", "member", "1"},
+		{"by_team", "backend", "1"},
+	}
+	if fmt.Sprint(assignments) != fmt.Sprint(expected) {
+		t.Fatalf("expected %v, got %v", expected, assignments)
+	}
+
+	review := s.getCSV("/stats/timeToReview", "application/json;q=0.5, text/csv")
+	if len(review) != 4 || review[0][0] != "group" || review[1][0] != "by_team" || review[2][1] != "u2" ||
+		review[3][0] != "by_repository" {
+		t.Fatalf("expected a header, a team, a user and a repository row, got %v", review)
+	}
+
+	fairness := s.getCSV("/stats/fairness?format=CSV", "")
+	if len(fairness) != 2 || fairness[1][0] != "backend" || fairness[1][4] != "" {
+		t.Fatalf("expected one backend row without a max/min ratio, got %v", fairness)
+	}
+
+	var stats statsResponse
+	s.getJSON("/stats/assignments?format=json", http.StatusOK, &stats)
+	if stats.userCount("u2") != 1 {
+		t.Fatalf("expected json when format=json, got %+v", stats)
+	}
+	s.getJSON("/stats/timeToMerge?format=xml", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EBinaryResponses(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.createPR("pr-1", "Add search", "u1")
+
+	// normalize re-encodes a decoded body as JSON, so integers and floats compare equal
+	normalize := func(v any) any {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out any
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	window := "from=2000-01-01T00:00:00Z&to=" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	for _, path := range []string{"/pullRequest/list", "/stats/assignments?" + window, "/team/list", "/users/getReview?user_id=u2"} {
+		var want any
+		s.getJSON(path, http.StatusOK, &want)
+
+		contentType, body := s.getBinary(path, "application/msgpack")
+		if contentType != "application/msgpack" {
+			t.Fatalf("%s: expected msgpack, got %q", path, contentType)
+		}
+		var got any
+		if err := msgpack.Unmarshal(body, &got); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if !reflect.DeepEqual(normalize(got), want) {
+			t.Fatalf("%s: msgpack body %v differs from json %v", path, got, want)
+		}
+
+		contentType, body = s.getBinary(path, "application/json;q=0.9, application/x-protobuf")
+		if contentType != "application/x-protobuf; messageType=google.protobuf.Struct" {
+			t.Fatalf("%s: expected protobuf, got %q", path, contentType)
+		}
+		doc := &structpb.Struct{}
+		if err := proto.Unmarshal(body, doc); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if !reflect.DeepEqual(normalize(doc.AsMap()), want) {
+			t.Fatalf("%s: protobuf body %v differs from json %v", path, doc.AsMap(), want)
+		}
+	}
+
+	// format=json keeps JSON regardless of Accept
+	contentType, _ := s.getBinary("/stats/assignments?format=json", "application/msgpack")
+	if contentType != "application/json" {
+		t.Fatalf("expected json with format=json, got %q", contentType)
+	}
+}
+
+func TestHTTPE2ETimeToReviewStats(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"))
+
+	s.createPR("pr-1", "Add search", "u1")
+	s.createPR("pr-2", "Fix search", "u1")
+
+	s.prRepo.BackdateAssignment("pr-1", "u2", 10*time.Minute)
+	s.prRepo.BackdateAssignment("pr-1", "u3", 30*time.Minute)
+	s.prRepo.BackdateAssignment("pr-2", "u2", 20*time.Minute)
+
+	type reviewResponse struct {
+		FirstActionAt time.Time `json:"first_action_at"`
+	}
+	var first, again reviewResponse
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u2"}, http.StatusOK, &first)
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u3"}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-2", "user_id": "u2"}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u2"}, http.StatusOK, &again)
+	if !again.FirstActionAt.Equal(first.FirstActionAt) {
+		t.Fatalf("expected the first action time to be kept, got %v then %v", first.FirstActionAt, again.FirstActionAt)
+	}
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-1", "user_id": "u1"}, http.StatusConflict, nil)
+
+	type latency struct {
+		TeamName   string  `json:"team_name"`
+		UserID     string  `json:"user_id"`
+		Count      int     `json:"count"`
+		P50Seconds float64 `json:"p50_seconds"`
+		P90Seconds float64 `json:"p90_seconds"`
+	}
+	var stats struct {
+		ByTeam []latency `json:"by_team"`
+		ByUser []latency `json:"by_user"`
+	}
+	s.getJSON("/stats/timeToReview", http.StatusOK, &stats)
+	if len(stats.ByTeam) != 1 || stats.ByTeam[0].TeamName != "backend" || stats.ByTeam[0].Count != 3 {
+		t.Fatalf("expected three backend reviews, got %+v", stats.ByTeam)
+	}
+	if p50 := stats.ByTeam[0].P50Seconds; p50 < 1190 || p50 > 1210 {
+		t.Fatalf("expected a 20 minute median, got %.0fs", p50)
+	}
+	if p90 := stats.ByTeam[0].P90Seconds; p90 < 1670 || p90 > 1690 {
+		t.Fatalf("expected an interpolated 28 minute p90, got %.0fs", p90)
+	}
+	if len(stats.ByUser) != 2 || stats.ByUser[0].UserID != "u2" || stats.ByUser[0].Count != 2 {
+		t.Fatalf("expected u2 with two reviews and u3 with one, got %+v", stats.ByUser)
+	}
+
+	s.getJSON("/stats/timeToReview?from=2000-01-01T00:00:00Z&to=2000-02-01T00:00:00Z", http.StatusOK, &stats)
+	if len(stats.ByTeam) != 0 || len(stats.ByUser) != 0 {
+		t.Fatalf("expected no reviews in an old window, got %+v", stats)
+	}
+	s.getJSON("/stats/timeToReview?from=2000-02-01T00:00:00Z&to=2000-01-01T00:00:00Z", http.StatusBadRequest, nil)
+	s.getJSON("/stats/timeToReview?from=yesterday", http.StatusBadRequest, nil)
+
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-2"}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-2", "user_id": "u2"}, http.StatusConflict, nil)
+}
+
+func TestHTTPE2ETimeToMergeStats(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+
+	s.createPR("pr-1", "Add search", "u1")
+	s.createPR("pr-2", "Fix search", "u1")
+	s.createPR("pr-3", "Add cache", "u2")
+	s.createPR("pr-4", "Still open", "u2")
+	s.prRepo.BackdateCreation("pr-1", time.Hour)
+	s.prRepo.BackdateCreation("pr-2", 3*time.Hour)
+	s.prRepo.BackdateCreation("pr-3", 2*time.Hour)
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": id}, http.StatusOK, nil)
+	}
+
+	type latency struct {
+		TeamName   string  `json:"team_name"`
+		AuthorID   string  `json:"author_id"`
+		WeekStart  string  `json:"week_start"`
+		Count      int     `json:"count"`
+		P50Seconds float64 `json:"p50_seconds"`
+	}
+	var stats struct {
+		ByTeam   []latency `json:"by_team"`
+		ByAuthor []latency `json:"by_author"`
+		ByWeek   []latency `json:"by_week"`
+	}
+	s.getJSON("/stats/timeToMerge", http.StatusOK, &stats)
+	if len(stats.ByTeam) != 1 || stats.ByTeam[0].TeamName != "backend" || stats.ByTeam[0].Count != 3 {
+		t.Fatalf("expected three merged backend PRs, got %+v", stats.ByTeam)
+	}
+	if p50 := stats.ByTeam[0].P50Seconds; p50 < 7190 || p50 > 7210 {
+		t.Fatalf("expected a two hour median, got %.0fs", p50)
+	}
+	if len(stats.ByAuthor) != 2 || stats.ByAuthor[0].AuthorID != "u1" || stats.ByAuthor[0].Count != 2 {
+		t.Fatalf("expected u1 with two merged PRs and u2 with one, got %+v", stats.ByAuthor)
+	}
+	if p50 := stats.ByAuthor[0].P50Seconds; p50 < 7190 || p50 > 7210 {
+		t.Fatalf("expected a two hour median for u1, got %.0fs", p50)
+	}
+	if len(stats.ByWeek) != 1 || stats.ByWeek[0].Count != 3 {
+		t.Fatalf("expected all merges in one week, got %+v", stats.ByWeek)
+	}
+	if week, err := time.Parse("2006-01-02", stats.ByWeek[0].WeekStart); err != nil || week.Weekday() != time.Monday {
+		t.Fatalf("expected the week to start on a Monday, got %q", stats.ByWeek[0].WeekStart)
+	}
+
+	s.getJSON("/stats/timeToMerge?from=2000-01-01T00:00:00Z&to=2000-02-01T00:00:00Z", http.StatusOK, &stats)
+	if len(stats.ByTeam) != 0 || len(stats.ByAuthor) != 0 || len(stats.ByWeek) != 0 {
+		t.Fatalf("expected no merges in an old window, got %+v", stats)
+	}
+	s.getJSON("/stats/timeToMerge?from=2000-02-01T00:00:00Z&to=2000-01-01T00:00:00Z", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2ERepositoryStats(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+
+	var created struct {
+		PR struct {
+			Repository string `json:"repository"`
+		} `json:"pr"`
+	}
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add refunds",
+		"author_id":         "u1",
+		"repository":        " payments-api ",
+	}, http.StatusCreated, &created)
+	if created.PR.Repository != "payments-api" {
+		t.Fatalf("expected the trimmed repository in the response, got %q", created.PR.Repository)
+	}
+	for _, pr := range []map[string]string{
+		{"pull_request_id": "pr-2", "pull_request_name": "Fix refunds", "author_id": "u2", "repository": "payments-api"},
+		{"pull_request_id": "pr-3", "pull_request_name": "Add banner", "author_id": "u2", "repository": "web"},
+	} {
+		s.postJSON("/pullRequest/create", pr, http.StatusCreated, nil)
+	}
+	s.prRepo.BackdateCreation("pr-1", time.Hour)
+	for _, id := range []string{"pr-1", "pr-3"} {
+		s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": id}, http.StatusOK, nil)
+	}
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-2", "user_id": "u1"}, http.StatusOK, nil)
+
+	type group struct {
+		AuthorID   string `json:"author_id"`
+		Repository string `json:"repository"`
+		Count      int    `json:"count"`
+		PRsCreated int    `json:"prs_created"`
+		PRsMerged  int    `json:"prs_merged"`
+	}
+	var stats struct {
+		ByAuthor     []group `json:"by_author"`
+		ByRepository []group `json:"by_repository"`
+	}
+	s.getJSON("/stats/authors", http.StatusOK, &stats)
+	if len(stats.ByRepository) != 2 ||
+		stats.ByRepository[0].Repository != "payments-api" || stats.ByRepository[0].PRsCreated != 2 ||
+		stats.ByRepository[1].Repository != "web" || stats.ByRepository[1].PRsMerged != 1 {
+		t.Fatalf("expected two payments-api PRs and one merged web PR, got %+v", stats.ByRepository)
+	}
+
+	s.getJSON("/stats/authors?repository=payments-api", http.StatusOK, &stats)
+	if len(stats.ByAuthor) != 2 || stats.ByAuthor[1].AuthorID != "u2" || stats.ByAuthor[1].PRsCreated != 1 {
+		t.Fatalf("expected the web PR to be filtered out, got %+v", stats.ByAuthor)
+	}
+	if len(stats.ByRepository) != 1 || stats.ByRepository[0].Repository != "payments-api" {
+		t.Fatalf("expected only payments-api, got %+v", stats.ByRepository)
+	}
+
+	s.getJSON("/stats/timeToMerge?repository=web", http.StatusOK, &stats)
+	if len(stats.ByAuthor) != 1 || stats.ByAuthor[0].AuthorID != "u2" ||
+		len(stats.ByRepository) != 1 || stats.ByRepository[0].Count != 1 {
+		t.Fatalf("expected only the web merge, got %+v", stats)
+	}
+
+	s.getJSON("/stats/timeToReview?repository=payments-api", http.StatusOK, &stats)
+	if len(stats.ByRepository) != 1 || stats.ByRepository[0].Repository != "payments-api" || stats.ByRepository[0].Count != 1 {
+		t.Fatalf("expected one payments-api review, got %+v", stats.ByRepository)
+	}
+	s.getJSON("/stats/timeToReview?repository=web", http.StatusOK, &stats)
+	if len(stats.ByRepository) != 0 {
+		t.Fatalf("expected no web reviews, got %+v", stats.ByRepository)
+	}
+}
@@ -0,0 +1,681 @@
+package e2e
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-service/internal/service/pullrequest"
+)
+
+func TestHTTPE2ETeamDelete(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.addTeam("platform", activeMember("p1", "Pam"))
+
+	var moveResp deleteTeamResponse
+	s.postJSON("/team/delete", map[string]string{
+		"team_name":        "platform",
+		"target_team_name": "backend",
+	}, http.StatusOK, &moveResp)
+	if len(moveResp.Members) != 1 || moveResp.Members[0].TeamName != "backend" {
+		t.Fatalf("expected platform members to move to backend, got %+v", moveResp.Members)
+	}
+	s.getJSON("/team/get?team_name=platform", http.StatusNotFound, nil)
+
+	pr := s.createPR("pr-1", "Add search", "u1")
+	if len(pr.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected two reviewers, got %v", pr.PR.AssignedReviewers)
+	}
+
+	var deleteResp deleteTeamResponse
+	s.postJSON("/team/delete", map[string]string{"team_name": "backend"}, http.StatusOK, &deleteResp)
+	if len(deleteResp.ClosedReviews) != 2 {
+		t.Fatalf("expected both reviews to be closed, got %+v", deleteResp.ClosedReviews)
+	}
+	for _, member := range deleteResp.Members {
+		if member.IsActive {
+			t.Fatalf("expected %s to be deactivated", member.UserID)
+		}
+	}
+
+	var review getReviewResponse
+	s.getJSON("/users/getReview?user_id="+pr.PR.AssignedReviewers[0], http.StatusOK, &review)
+	if containsPR(review.PullRequests, "pr-1") {
+		t.Fatalf("expected pr-1 to be removed from former reviewer")
+	}
+
+	s.postJSON("/team/delete", map[string]string{"team_name": "backend"}, http.StatusNotFound, nil)
+}
+
+func TestHTTPE2ERestoreDeleted(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"))
+	s.createPR("pr-1", "Add search", "u1")
+
+	s.postJSON("/users/delete", map[string]string{"user_id": "u3"}, http.StatusOK, nil)
+	s.postJSON("/team/delete", map[string]string{"team_name": "backend"}, http.StatusOK, nil)
+	s.getJSON("/team/get?team_name=backend", http.StatusNotFound, nil)
+
+	// The deleted team keeps its PRs in stats
+	var stats struct {
+		ByTeam []struct {
+			TeamName   string `json:"team_name"`
+			PRsCreated int    `json:"prs_created"`
+		} `json:"by_team"`
+	}
+	s.getJSON("/stats/authors", http.StatusOK, &stats)
+	if len(stats.ByTeam) != 1 || stats.ByTeam[0].TeamName != "backend" || stats.ByTeam[0].PRsCreated != 1 {
+		t.Fatalf("expected the PR of the deleted team in stats, got %+v", stats.ByTeam)
+	}
+
+	var deletedUsers struct {
+		Users []struct {
+			UserID    string     `json:"user_id"`
+			DeletedAt *time.Time `json:"deleted_at"`
+		} `json:"users"`
+		Total int `json:"total"`
+	}
+	s.getJSON("/users/deleted", http.StatusOK, &deletedUsers)
+	if deletedUsers.Total != 1 || deletedUsers.Users[0].UserID != "u3" || deletedUsers.Users[0].DeletedAt == nil {
+		t.Fatalf("expected u3 to be listed as deleted, got %+v", deletedUsers)
+	}
+
+	var deletedTeams struct {
+		Teams []struct {
+			TeamName  string    `json:"team_name"`
+			DeletedAt time.Time `json:"deleted_at"`
+		} `json:"teams"`
+		Total int `json:"total"`
+	}
+	s.getJSON("/team/deleted", http.StatusOK, &deletedTeams)
+	if deletedTeams.Total != 1 || deletedTeams.Teams[0].TeamName != "backend" || deletedTeams.Teams[0].DeletedAt.IsZero() {
+		t.Fatalf("expected backend to be listed as deleted, got %+v", deletedTeams)
+	}
+
+	var restoredTeam teamResponse
+	s.postJSON("/team/restore", map[string]string{"team_name": "backend"}, http.StatusOK, &restoredTeam)
+	if restoredTeam.Team.TeamName != "backend" {
+		t.Fatalf("unexpected restored team: %+v", restoredTeam)
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusOK, nil)
+	s.postJSON("/team/restore", map[string]string{"team_name": "backend"}, http.StatusNotFound, nil)
+
+	var restoredUser struct {
+		User struct {
+			UserID    string     `json:"user_id"`
+			IsActive  bool       `json:"is_active"`
+			DeletedAt *time.Time `json:"deleted_at"`
+		} `json:"user"`
+	}
+	s.postJSON("/users/restore", map[string]string{"user_id": "u3"}, http.StatusOK, &restoredUser)
+	if restoredUser.User.UserID != "u3" || restoredUser.User.IsActive || restoredUser.User.DeletedAt != nil {
+		t.Fatalf("expected u3 to come back inactive, got %+v", restoredUser.User)
+	}
+	s.postJSON("/users/restore", map[string]string{"user_id": "u3"}, http.StatusNotFound, nil)
+
+	s.getJSON("/users/deleted", http.StatusOK, &deletedUsers)
+	s.getJSON("/team/deleted", http.StatusOK, &deletedTeams)
+	if deletedUsers.Total != 0 || deletedTeams.Total != 0 {
+		t.Fatalf("expected nothing left deleted, got %d users and %d teams", deletedUsers.Total, deletedTeams.Total)
+	}
+}
+
+func TestHTTPE2EDeletedListPages(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	names := []string{"alpha", "beta", "gamma"}
+	for _, name := range names {
+		s.addTeam(name, activeMember(name+"-1", "Dev"))
+		s.postJSON("/users/delete", map[string]string{"user_id": name + "-1"}, http.StatusOK, nil)
+		s.postJSON("/team/delete", map[string]string{"team_name": name}, http.StatusOK, nil)
+	}
+
+	// Pages follow deletion time and are walked with next_cursor
+	var teams []string
+	for path := "/team/deleted?limit=2&order=asc"; path != ""; {
+		var page struct {
+			Teams []struct {
+				TeamName string `json:"team_name"`
+			} `json:"teams"`
+			Total      int    `json:"total"`
+			NextCursor string `json:"next_cursor"`
+		}
+		s.getJSON(path, http.StatusOK, &page)
+		if page.Total != 3 || len(page.Teams) > 2 {
+			t.Fatalf("unexpected page of deleted teams: %+v", page)
+		}
+		for _, team := range page.Teams {
+			teams = append(teams, team.TeamName)
+		}
+		path = ""
+		if page.NextCursor != "" {
+			path = "/team/deleted?limit=2&order=asc&cursor=" + page.NextCursor
+		}
+	}
+	if !slices.Equal(teams, names) {
+		t.Fatalf("expected deleted teams %v, got %v", names, teams)
+	}
+
+	var users []string
+	for path := "/users/deleted?limit=2&order=asc"; path != ""; {
+		var page struct {
+			Users []struct {
+				UserID string `json:"user_id"`
+			} `json:"users"`
+			Total      int    `json:"total"`
+			NextCursor string `json:"next_cursor"`
+		}
+		s.getJSON(path, http.StatusOK, &page)
+		if page.Total != 3 || len(page.Users) > 2 {
+			t.Fatalf("unexpected page of deleted users: %+v", page)
+		}
+		for _, user := range page.Users {
+			users = append(users, user.UserID)
+		}
+		path = ""
+		if page.NextCursor != "" {
+			path = "/users/deleted?limit=2&order=asc&cursor=" + page.NextCursor
+		}
+	}
+	if want := []string{"alpha-1", "beta-1", "gamma-1"}; !slices.Equal(users, want) {
+		t.Fatalf("expected deleted users %v, got %v", want, users)
+	}
+
+	var first struct {
+		NextCursor string `json:"next_cursor"`
+	}
+	s.getJSON("/team/deleted?limit=1", http.StatusOK, &first)
+	s.getJSON("/team/deleted?offset=1&cursor="+first.NextCursor, http.StatusBadRequest, nil)
+	s.getJSON("/users/deleted?cursor=garbage", http.StatusBadRequest, nil)
+	s.getJSON("/users/deleted?limit=101", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2ETeamList(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	for _, name := range []string{"backend", "frontend", "mobile"} {
+		s.addTeam(name, activeMember(name+"-1", "Lead"), inactiveMember(name+"-2", "Dev"))
+	}
+
+	var page listTeamsResponse
+	s.getJSON("/team/list?limit=2&offset=1", http.StatusOK, &page)
+	if page.Total != 3 {
+		t.Fatalf("expected total of 3 teams, got %d", page.Total)
+	}
+	if len(page.Teams) != 2 || page.Teams[0].TeamName != "frontend" {
+		t.Fatalf("unexpected page: %+v", page.Teams)
+	}
+	if page.Teams[0].MemberCount != 2 || page.Teams[0].ActiveMemberCount != 1 {
+		t.Fatalf("unexpected member counts: %+v", page.Teams[0])
+	}
+
+	s.getJSON("/team/list?limit=-1", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2ETeamRename(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	for _, name := range []string{"backend", "frontend"} {
+		s.addTeam(name, activeMember(name+"-1", "Alice"), activeMember(name+"-2", "Bob"))
+	}
+
+	s.postJSON("/team/rename", map[string]string{
+		"team_name":     "backend",
+		"new_team_name": "frontend",
+	}, http.StatusBadRequest, nil)
+
+	var renamed teamResponse
+	s.postJSON("/team/rename", map[string]string{
+		"team_name":     "backend",
+		"new_team_name": "core",
+	}, http.StatusOK, &renamed)
+	if renamed.Team.TeamName != "core" {
+		t.Fatalf("expected renamed team, got %s", renamed.Team.TeamName)
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusNotFound, nil)
+
+	pr := s.createPR("pr-1", "Add search", "backend-1")
+	if len(pr.PR.AssignedReviewers) != 1 || pr.PR.AssignedReviewers[0] != "backend-2" {
+		t.Fatalf("expected reviewer from renamed team, got %v", pr.PR.AssignedReviewers)
+	}
+}
+
+func TestHTTPE2ETeamRoles(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	var created struct {
+		Team struct {
+			Members []struct {
+				UserID string `json:"user_id"`
+				Role   string `json:"role"`
+			} `json:"members"`
+		} `json:"team"`
+	}
+	s.postJSON("/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice", "is_active": true, "role": "lead"},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		},
+	}, http.StatusCreated, &created)
+	for _, m := range created.Team.Members {
+		expected := map[string]string{"u1": "lead", "u2": "member"}[m.UserID]
+		if m.Role != expected {
+			t.Fatalf("expected %s to have role %s, got %s", m.UserID, expected, m.Role)
+		}
+	}
+
+	s.postJSON("/users/setRole", map[string]string{"user_id": "u2", "role": "owner"}, http.StatusBadRequest, nil)
+
+	var updated struct {
+		User struct {
+			Role string `json:"role"`
+		} `json:"user"`
+	}
+	s.postJSON("/users/setRole", map[string]string{"user_id": "u2", "role": "lead"}, http.StatusOK, &updated)
+	if updated.User.Role != "lead" {
+		t.Fatalf("expected u2 to become lead, got %s", updated.User.Role)
+	}
+
+	s.createPR("pr-1", "Add search", "u1")
+
+	var stats statsResponse
+	s.getJSON("/stats/assignments", http.StatusOK, &stats)
+	if stats.ByRole["lead"] != 1 {
+		t.Fatalf("expected one lead assignment, got %v", stats.ByRole)
+	}
+}
+
+func TestHTTPE2ESubTeams(t *testing.T) {
+	s := newTestServer(t, pullrequest.WithSubTeamReviewers(true))
+	defer s.Close()
+
+	s.addTeam("platform", activeMember("p1", "Pam"))
+
+	var child struct {
+		Team struct {
+			ParentTeamName string `json:"parent_team_name"`
+		} `json:"team"`
+	}
+	s.postJSON("/team/add", map[string]any{
+		"team_name":        "platform-db",
+		"parent_team_name": "platform",
+		"members": []map[string]any{
+			{"user_id": "d1", "username": "Dan", "is_active": true},
+			{"user_id": "d2", "username": "Dora", "is_active": true},
+		},
+	}, http.StatusCreated, &child)
+	if child.Team.ParentTeamName != "platform" {
+		t.Fatalf("expected parent team to be set, got %q", child.Team.ParentTeamName)
+	}
+
+	s.postJSON("/team/setParent", map[string]string{
+		"team_name":        "platform",
+		"parent_team_name": "platform-db",
+	}, http.StatusBadRequest, nil)
+
+	var flat struct {
+		Members []struct {
+			UserID   string `json:"user_id"`
+			TeamName string `json:"team_name"`
+		} `json:"members"`
+	}
+	s.getJSON("/team/get?team_name=platform&flatten=true", http.StatusOK, &flat)
+	if len(flat.Members) != 3 {
+		t.Fatalf("expected flattened roster of 3, got %+v", flat.Members)
+	}
+
+	pr := s.createPR("pr-1", "Tune indexes", "p1")
+	if len(pr.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected reviewers from sub-team, got %v", pr.PR.AssignedReviewers)
+	}
+}
+
+func TestHTTPE2EMultiTeamMembership(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.addTeam("platform", activeMember("u1", "Alice"), activeMember("u3", "Charlie"), activeMember("u4", "Dave"))
+
+	var backend struct {
+		Members []struct {
+			UserID string `json:"user_id"`
+		} `json:"members"`
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusOK, &backend)
+	if len(backend.Members) != 2 {
+		t.Fatalf("expected u1 to stay in backend, got %+v", backend.Members)
+	}
+
+	// Without team_name the PR belongs to the author's earliest team
+	pr := s.createPR("pr-1", "Add search", "u1")
+	if pr.PR.TeamName != "backend" || len(pr.PR.AssignedReviewers) != 1 || pr.PR.AssignedReviewers[0] != "u2" {
+		t.Fatalf("expected backend review by u2, got %+v", pr.PR)
+	}
+
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-2",
+		"pull_request_name": "Tune pools",
+		"author_id":         "u1",
+		"team_name":         "platform",
+	}, http.StatusCreated, &pr)
+	if pr.PR.TeamName != "platform" || len(pr.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected two platform reviewers, got %+v", pr.PR)
+	}
+	for _, reviewer := range pr.PR.AssignedReviewers {
+		if reviewer != "u3" && reviewer != "u4" {
+			t.Fatalf("expected platform reviewers only, got %v", pr.PR.AssignedReviewers)
+		}
+	}
+
+	s.postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-3",
+		"pull_request_name": "Foreign team",
+		"author_id":         "u2",
+		"team_name":         "platform",
+	}, http.StatusNotFound, nil)
+
+	// u2 also joins platform; reassignment on a platform PR still stays within platform
+	s.postJSON("/users/add", map[string]string{
+		"user_id":   "u2",
+		"username":  "Bob",
+		"team_name": "platform",
+	}, http.StatusOK, nil)
+
+	var reassigned reassignResponse
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-2",
+		"old_user_id":     pr.PR.AssignedReviewers[0],
+	}, http.StatusOK, &reassigned)
+	if reassigned.ReplacedBy != "u2" {
+		t.Fatalf("expected u2 as the only free platform member, got %q", reassigned.ReplacedBy)
+	}
+}
+
+func TestHTTPE2ETeamAuditLog(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+	s.addTeam("core", activeMember("u3", "Charlie"))
+
+	s.postJSON("/users/setIsActive", map[string]any{"user_id": "u2", "is_active": false}, http.StatusOK, nil)
+	s.postJSON("/users/setIsActive", map[string]any{"user_id": "u2", "is_active": false}, http.StatusOK, nil)
+	s.postJSON("/users/activateTeamMembers", map[string]any{
+		"team_name": "backend",
+		"user_ids":  []string{"u2"},
+	}, http.StatusOK, nil)
+	s.postJSON("/team/delete", map[string]string{
+		"team_name":        "backend",
+		"target_team_name": "core",
+	}, http.StatusOK, nil)
+
+	type auditLog struct {
+		Events []struct {
+			UserID       string `json:"user_id"`
+			TeamName     string `json:"team_name"`
+			Action       string `json:"action"`
+			FromTeamName string `json:"from_team_name"`
+		} `json:"events"`
+		Total int `json:"total"`
+	}
+
+	// History of the deleted team is kept, newest first, including moves out of it
+	var log auditLog
+	s.getJSON("/team/auditLog?team_name=backend", http.StatusOK, &log)
+	if log.Total != 6 || len(log.Events) != 6 {
+		t.Fatalf("expected six backend events, got %+v", log)
+	}
+	actions := make([]string, len(log.Events))
+	for i, e := range log.Events {
+		actions[i] = e.Action
+	}
+	if strings.Join(actions, ",") != "MOVED,MOVED,ACTIVATED,DEACTIVATED,ADDED,ADDED" {
+		t.Fatalf("unexpected backend history: %v", actions)
+	}
+	if log.Events[0].TeamName != "core" || log.Events[0].FromTeamName != "backend" {
+		t.Fatalf("expected a move from backend to core, got %+v", log.Events[0])
+	}
+
+	var page auditLog
+	s.getJSON("/team/auditLog?team_name=core&limit=1&offset=1", http.StatusOK, &page)
+	if page.Total != 3 || len(page.Events) != 1 || page.Events[0].Action != "MOVED" {
+		t.Fatalf("unexpected core page: %+v", page)
+	}
+
+	s.getJSON("/team/auditLog", http.StatusBadRequest, nil)
+	s.getJSON("/team/auditLog?team_name=core&limit=1000", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2ETeamUpsert(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("infra", activeMember("u9", "Ivan"))
+
+	roster := map[string]any{
+		"team_name": "sre",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		},
+	}
+	s.postJSON("/team/add?upsert=true", roster, http.StatusCreated, nil)
+	s.postJSON("/team/add?upsert=true", roster, http.StatusOK, nil)
+	s.postJSON("/team/add", roster, http.StatusBadRequest, nil)
+
+	s.addTeam("oncall", activeMember("u2", "Bob"))
+
+	// u2 leaves sre but stays in oncall; u3 joins; Alice is renamed and the team re-parented
+	var resp struct {
+		Team struct {
+			ParentTeamName string `json:"parent_team_name"`
+			Members        []struct {
+				UserID   string `json:"user_id"`
+				Username string `json:"username"`
+			} `json:"members"`
+		} `json:"team"`
+	}
+	s.postJSON("/team/add?upsert=true", map[string]any{
+		"team_name":        "sre",
+		"parent_team_name": "infra",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice Smith", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		},
+	}, http.StatusOK, &resp)
+	if resp.Team.ParentTeamName != "infra" || len(resp.Team.Members) != 2 {
+		t.Fatalf("expected synchronized sre under infra, got %+v", resp.Team)
+	}
+	for _, member := range resp.Team.Members {
+		if member.UserID == "u2" {
+			t.Fatalf("expected u2 to leave sre, got %+v", resp.Team.Members)
+		}
+		if member.UserID == "u1" && member.Username != "Alice Smith" {
+			t.Fatalf("expected u1 to be renamed, got %q", member.Username)
+		}
+	}
+
+	var oncall struct {
+		Members []struct {
+			UserID string `json:"user_id"`
+		} `json:"members"`
+	}
+	s.getJSON("/team/get?team_name=oncall", http.StatusOK, &oncall)
+	if len(oncall.Members) != 1 || oncall.Members[0].UserID != "u2" {
+		t.Fatalf("expected u2 to stay in oncall, got %+v", oncall.Members)
+	}
+
+	s.postJSON("/team/add?upsert=maybe", roster, http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2ETeamMerge(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("payments", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"))
+	s.addTeam("billing", activeMember("u3", "Charlie"), activeMember("u4", "Dave"))
+	s.addTeam("payments-api", activeMember("u5", "Eve"))
+	s.postJSON("/team/setParent", map[string]string{
+		"team_name":        "payments-api",
+		"parent_team_name": "payments",
+	}, http.StatusOK, nil)
+
+	pr := s.createPR("pr-1", "Refunds", "u1")
+	reviewers := pr.PR.AssignedReviewers
+
+	type mergeResponse struct {
+		DryRun              bool     `json:"dry_run"`
+		MovedUserIDs        []string `json:"moved_user_ids"`
+		SharedUserIDs       []string `json:"shared_user_ids"`
+		ReparentedTeams     []string `json:"reparented_teams"`
+		MovedPullRequestIDs []string `json:"moved_pull_request_ids"`
+		Team                struct {
+			TeamName string `json:"team_name"`
+			Members  []struct {
+				UserID string `json:"user_id"`
+			} `json:"members"`
+		} `json:"team"`
+	}
+
+	var preview mergeResponse
+	s.postJSON("/team/merge", map[string]any{
+		"source_team_name": "payments",
+		"target_team_name": "billing",
+		"dry_run":          true,
+	}, http.StatusOK, &preview)
+	sort.Strings(preview.MovedUserIDs)
+	if !preview.DryRun || len(preview.MovedUserIDs) != 2 || preview.MovedUserIDs[0] != "u1" ||
+		len(preview.SharedUserIDs) != 1 || preview.SharedUserIDs[0] != "u3" {
+		t.Fatalf("unexpected dry-run report: %+v", preview)
+	}
+	if len(preview.ReparentedTeams) != 1 || len(preview.MovedPullRequestIDs) != 1 {
+		t.Fatalf("expected one sub-team and one PR in dry-run, got %+v", preview)
+	}
+	s.getJSON("/team/get?team_name=payments", http.StatusOK, nil)
+
+	var merged mergeResponse
+	s.postJSON("/team/merge", map[string]any{
+		"source_team_name": "payments",
+		"target_team_name": "billing",
+	}, http.StatusOK, &merged)
+	if merged.DryRun || len(merged.Team.Members) != 4 {
+		t.Fatalf("expected four billing members after merge, got %+v", merged.Team)
+	}
+
+	s.getJSON("/team/get?team_name=payments", http.StatusNotFound, nil)
+
+	var child struct {
+		ParentTeamName string `json:"parent_team_name"`
+	}
+	s.getJSON("/team/get?team_name=payments-api", http.StatusOK, &child)
+	if child.ParentTeamName != "billing" {
+		t.Fatalf("expected payments-api under billing, got %q", child.ParentTeamName)
+	}
+
+	// Existing reviewers are kept; reassignment now draws from billing
+	var reassigned reassignResponse
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     reviewers[0],
+	}, http.StatusOK, &reassigned)
+	if reassigned.PR.TeamName != "billing" || reassigned.ReplacedBy != "u4" {
+		t.Fatalf("expected pr-1 to move to billing with u4 as reviewer, got %+v", reassigned)
+	}
+
+	s.postJSON("/team/merge", map[string]any{
+		"source_team_name": "billing",
+		"target_team_name": "billing",
+	}, http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2ETeamImport(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	var rejected struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+		RowErrors []struct {
+			Line    int    `json:"line"`
+			UserID  string `json:"user_id"`
+			Message string `json:"message"`
+		} `json:"row_errors"`
+	}
+	badCSV := "user_id,username,is_active,role\n" +
+		"u1,Alice,true,lead\n" +
+		"u2,,true,member\n" +
+		"u3,Charlie,maybe,\n" +
+		"u1,Alice again,,\n"
+	s.post("/team/import?team_name=backend", "text/csv", strings.NewReader(badCSV), http.StatusBadRequest, &rejected)
+	if rejected.Error.Code != "INVALID_ARGUMENT" || len(rejected.RowErrors) != 3 {
+		t.Fatalf("expected three row errors, got %+v", rejected)
+	}
+	lines := make([]int, 0, len(rejected.RowErrors))
+	for _, rowErr := range rejected.RowErrors {
+		lines = append(lines, rowErr.Line)
+	}
+	sort.Ints(lines)
+	if lines[0] != 3 || lines[1] != 4 || lines[2] != 5 {
+		t.Fatalf("expected errors on lines 3-5, got %v", lines)
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusNotFound, nil)
+
+	var imported struct {
+		Team struct {
+			TeamName string `json:"team_name"`
+			Members  []struct {
+				UserID   string `json:"user_id"`
+				IsActive bool   `json:"is_active"`
+				Role     string `json:"role"`
+			} `json:"members"`
+		} `json:"team"`
+		CreatedUserIDs []string `json:"created_user_ids"`
+		UpdatedUserIDs []string `json:"updated_user_ids"`
+	}
+	goodCSV := "username,user_id,role\nAlice,u1,lead\nBob,u2,\n"
+	s.post("/team/import?team_name=backend", "text/csv", strings.NewReader(goodCSV), http.StatusCreated, &imported)
+	if len(imported.Team.Members) != 2 || len(imported.CreatedUserIDs) != 2 {
+		t.Fatalf("expected two created members, got %+v", imported)
+	}
+
+	ndjson := `{"user_id":"u2","username":"Bob","is_active":false}` + "\n\n" +
+		`{"user_id":"u3","username":"Charlie"}` + "\n"
+	s.post("/team/import?team_name=backend", "application/x-ndjson", strings.NewReader(ndjson), http.StatusOK, &imported)
+	if len(imported.Team.Members) != 3 || len(imported.CreatedUserIDs) != 1 || len(imported.UpdatedUserIDs) != 1 {
+		t.Fatalf("expected u3 created and u2 updated, got %+v", imported)
+	}
+	for _, member := range imported.Team.Members {
+		switch member.UserID {
+		case "u1":
+			if member.Role != "lead" || !member.IsActive {
+				t.Fatalf("expected u1 to stay an active lead, got %+v", member)
+			}
+		case "u2":
+			if member.IsActive {
+				t.Fatalf("expected u2 to be deactivated by import")
+			}
+		}
+	}
+
+	s.post("/team/import?team_name=backend", "application/json", strings.NewReader(ndjson), http.StatusBadRequest, nil)
+	s.post("/team/import?team_name=backend&format=ndjson", "application/json",
+		strings.NewReader(`{"user_id":`), http.StatusBadRequest, &rejected)
+	if len(rejected.RowErrors) != 1 || rejected.RowErrors[0].Line != 1 {
+		t.Fatalf("expected malformed line to be reported, got %+v", rejected.RowErrors)
+	}
+}
@@ -0,0 +1,340 @@
+package e2e
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/service/user"
+)
+
+func TestHTTPE2EAddUser(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	for _, name := range []string{"backend", "frontend"} {
+		s.addTeam(name, activeMember(name+"-1", "Alice"))
+	}
+
+	s.postJSON("/users/add", map[string]string{
+		"user_id":   "u9",
+		"username":  "Nina",
+		"team_name": "missing",
+	}, http.StatusNotFound, nil)
+
+	var added struct {
+		User struct {
+			TeamName string   `json:"team_name"`
+			Teams    []string `json:"teams"`
+			IsActive bool     `json:"is_active"`
+			Role     string   `json:"role"`
+		} `json:"user"`
+	}
+	s.postJSON("/users/add", map[string]string{
+		"user_id":   "u9",
+		"username":  "Nina",
+		"team_name": "backend",
+		"role":      "lead",
+	}, http.StatusCreated, &added)
+	if !added.User.IsActive || added.User.Role != "lead" {
+		t.Fatalf("expected active lead, got %+v", added.User)
+	}
+
+	pr := s.createPR("pr-1", "Add search", "backend-1")
+	if len(pr.PR.AssignedReviewers) != 1 || pr.PR.AssignedReviewers[0] != "u9" {
+		t.Fatalf("expected new member to be assigned, got %v", pr.PR.AssignedReviewers)
+	}
+
+	s.postJSON("/users/add", map[string]any{
+		"user_id":   "u9",
+		"username":  "Nina",
+		"team_name": "frontend",
+		"is_active": false,
+	}, http.StatusOK, &added)
+	if added.User.TeamName != "frontend" || added.User.IsActive || added.User.Role != "lead" {
+		t.Fatalf("expected inactive lead in frontend, got %+v", added.User)
+	}
+	if len(added.User.Teams) != 2 || added.User.Teams[0] != "backend" || added.User.Teams[1] != "frontend" {
+		t.Fatalf("expected user to keep backend membership, got %v", added.User.Teams)
+	}
+}
+
+func TestHTTPE2EReviewQueueWait(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+
+	type queueResponse struct {
+		UserID       string           `json:"user_id"`
+		PullRequests []pullRequestRef `json:"pull_requests"`
+		Version      string           `json:"version"`
+		Changed      bool             `json:"changed"`
+	}
+
+	// A wait without changes times out with the queue as it is
+	var empty queueResponse
+	s.getJSON("/users/reviewQueue/wait?user_id=u2&timeout=50ms", http.StatusOK, &empty)
+	if empty.Changed || len(empty.PullRequests) != 0 || empty.Version == "" {
+		t.Fatalf("expected an unchanged empty queue, got %+v", empty)
+	}
+
+	// A wait from that version returns as soon as a review is assigned
+	done := make(chan queueResponse, 1)
+	started := time.Now()
+	go func() {
+		var resp queueResponse
+		s.getJSON("/v1/users/reviewQueue/wait?user_id=u2&timeout=10s&version="+empty.Version, http.StatusOK, &resp)
+		done <- resp
+	}()
+	s.createPR("pr-1", "Add search", "u1")
+	var changed queueResponse
+	select {
+	case changed = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the wait to return once the queue changed")
+	}
+	if !changed.Changed || !containsPR(changed.PullRequests, "pr-1") || changed.Version == empty.Version ||
+		time.Since(started) >= 5*time.Second {
+		t.Fatalf("expected the changed queue with pr-1, got %+v", changed)
+	}
+
+	// Events about other users do not end the wait
+	s.getJSON("/users/reviewQueue/wait?user_id=u1&timeout=50ms", http.StatusOK, &empty)
+	if empty.Changed || len(empty.PullRequests) != 0 {
+		t.Fatalf("expected the author's queue to stay empty, got %+v", empty)
+	}
+
+	// A stale version answers at once
+	var stale queueResponse
+	s.getJSON("/users/reviewQueue/wait?user_id=u2&timeout=10s&version=stale", http.StatusOK, &stale)
+	if !stale.Changed || stale.Version != changed.Version {
+		t.Fatalf("expected the current queue for a stale version, got %+v", stale)
+	}
+
+	s.getJSON("/users/reviewQueue/wait?user_id=u2&timeout=10m", http.StatusBadRequest, nil)
+	s.getJSON("/users/reviewQueue/wait?user_id=u2&timeout=soon", http.StatusBadRequest, nil)
+	s.getJSON("/users/reviewQueue/wait?timeout=1s", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EHeartbeatAndDormantReport(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), inactiveMember("u3", "Charlie"))
+
+	var seen struct {
+		User struct {
+			UserID     string     `json:"user_id"`
+			LastSeenAt *time.Time `json:"last_seen_at"`
+		} `json:"user"`
+	}
+	s.postJSON("/users/heartbeat", map[string]string{"user_id": "u1"}, http.StatusOK, &seen)
+	if seen.User.LastSeenAt == nil || time.Since(*seen.User.LastSeenAt) > time.Minute {
+		t.Fatalf("expected a fresh last_seen_at, got %+v", seen.User)
+	}
+	s.postJSON("/users/heartbeat", map[string]string{"user_id": "ghost"}, http.StatusNotFound, nil)
+
+	// Only active users are reported; u1 has just been seen and u3 is inactive
+	var report struct {
+		InactiveDays int `json:"inactive_days"`
+		Users        []struct {
+			UserID string `json:"user_id"`
+		} `json:"users"`
+		Total int `json:"total"`
+	}
+	s.getJSON("/users/dormant?inactive_days=7", http.StatusOK, &report)
+	if report.InactiveDays != 7 || report.Total != 1 || len(report.Users) != 1 || report.Users[0].UserID != "u2" {
+		t.Fatalf("expected only u2 to be dormant, got %+v", report)
+	}
+
+	s.getJSON("/users/dormant", http.StatusOK, &report)
+	if report.InactiveDays != 30 {
+		t.Fatalf("expected the default 30 day threshold, got %d", report.InactiveDays)
+	}
+	s.getJSON("/users/dormant?inactive_days=-1", http.StatusBadRequest, nil)
+}
+
+func TestHTTPE2EDeleteUser(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"))
+
+	s.createPR("pr-1", "Add search", "u1")
+
+	var first deleteUserResponse
+	s.postJSON("/users/delete", map[string]string{"user_id": "u2"}, http.StatusOK, &first)
+	if first.User.IsActive {
+		t.Fatalf("expected deleted user to be inactive")
+	}
+	if len(first.ClosedReviews) != 1 || first.ClosedReviews[0].UserID != "u2" {
+		t.Fatalf("expected u2 review to be closed, got %+v", first)
+	}
+
+	s.postJSON("/users/delete", map[string]string{"user_id": "u2"}, http.StatusNotFound, nil)
+	s.postJSON("/users/setIsActive", map[string]any{"user_id": "u2", "is_active": true}, http.StatusNotFound, nil)
+
+	var team struct {
+		Members []struct {
+			UserID string `json:"user_id"`
+		} `json:"members"`
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusOK, &team)
+	if len(team.Members) != 2 {
+		t.Fatalf("expected deleted user to disappear from roster, got %+v", team.Members)
+	}
+
+	var stats statsResponse
+	s.getJSON("/stats/assignments", http.StatusOK, &stats)
+	if len(stats.ByPR) != 1 {
+		t.Fatalf("expected stats to keep the PR, got %v", stats.ByPR)
+	}
+}
+
+func TestHTTPE2EScheduledDeactivation(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Charlie"), activeMember("u4", "Dave"))
+
+	pr := s.createPR("pr-1", "Add search", "u1")
+
+	leaving := pr.PR.AssignedReviewers[0]
+	effectiveAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	var scheduled scheduledChangeResponse
+	s.postJSON("/users/setIsActive", map[string]any{
+		"user_id":      leaving,
+		"is_active":    false,
+		"effective_at": effectiveAt,
+	}, http.StatusAccepted, &scheduled)
+	if scheduled.ScheduledChange.Status != string(domain.ScheduledChangePending) ||
+		!scheduled.ScheduledChange.EffectiveAt.Equal(effectiveAt) {
+		t.Fatalf("unexpected scheduled change: %+v", scheduled.ScheduledChange)
+	}
+
+	s.postJSON("/users/deactivateTeamMembers", map[string]any{
+		"team_name":    "backend",
+		"user_ids":     []string{"ghost"},
+		"effective_at": effectiveAt,
+	}, http.StatusNotFound, nil)
+
+	// Nothing is due yet, so the reviewer keeps the review
+	if applied, err := s.scheduler.ApplyDueChanges(context.Background(), time.Now(), 10); err != nil || applied != 0 {
+		t.Fatalf("expected no due changes, got %d (%v)", applied, err)
+	}
+
+	var reviews getReviewResponse
+	s.getJSON("/users/getReview?user_id="+leaving, http.StatusOK, &reviews)
+	if !containsPR(reviews.PullRequests, "pr-1") {
+		t.Fatalf("expected %s to keep pr-1 before the change is due", leaving)
+	}
+
+	if applied, err := s.scheduler.ApplyDueChanges(context.Background(), effectiveAt.Add(time.Minute), 10); err != nil || applied != 1 {
+		t.Fatalf("expected one applied change, got %d (%v)", applied, err)
+	}
+
+	s.getJSON("/users/getReview?user_id="+leaving, http.StatusOK, &reviews)
+	if containsPR(reviews.PullRequests, "pr-1") {
+		t.Fatalf("expected pr-1 to be handed off once %s is deactivated", leaving)
+	}
+
+	var team struct {
+		Members []struct {
+			UserID   string `json:"user_id"`
+			IsActive bool   `json:"is_active"`
+		} `json:"members"`
+	}
+	s.getJSON("/team/get?team_name=backend", http.StatusOK, &team)
+	for _, member := range team.Members {
+		if member.UserID == leaving && member.IsActive {
+			t.Fatalf("expected %s to be inactive after the scheduled change", leaving)
+		}
+	}
+
+	// A past effective_at applies immediately
+	var immediate struct {
+		User struct {
+			IsActive bool `json:"is_active"`
+		} `json:"user"`
+	}
+	s.postJSON("/users/setIsActive", map[string]any{
+		"user_id":      leaving,
+		"is_active":    true,
+		"effective_at": time.Now().Add(-time.Hour),
+	}, http.StatusOK, &immediate)
+	if !immediate.User.IsActive {
+		t.Fatalf("expected past effective_at to apply immediately")
+	}
+}
+
+func TestHTTPE2EReviewCalendar(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"))
+	settings := strings.TrimSpace(strings.Repeat("настройка ", 10))
+	longName := "Refunds, partial; " + settings
+	for id, name := range map[string]string{"pr-1": longName, "pr-2": "Refund emails", "pr-3": "Refund UI"} {
+		s.createPR(id, name, "u1")
+	}
+	s.prRepo.BackdateAssignment("pr-1", "u2", 2*time.Hour)
+	s.postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-2", "user_id": "u2"}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-3"}, http.StatusOK, nil)
+
+	getCalendar := func(userID string, expectedStatus int) string {
+		t.Helper()
+		resp, err := s.client.Get(s.base + "/users/" + userID + "/reviews.ics")
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != expectedStatus {
+			t.Fatalf("expected status %d, got %d: %s", expectedStatus, resp.StatusCode, body)
+		}
+		if expectedStatus == http.StatusOK && resp.Header.Get("Content-Type") != "text/calendar; charset=utf-8" {
+			t.Fatalf("unexpected content type %q", resp.Header.Get("Content-Type"))
+		}
+		return string(body)
+	}
+
+	// Only the open review without a first action is on the calendar
+	feed := getCalendar("u2", http.StatusOK)
+	if !strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(feed, "END:VCALENDAR\r\n") {
+		t.Fatalf("unexpected calendar framing %q", feed)
+	}
+	if strings.Count(feed, "BEGIN:VEVENT") != 1 || !strings.Contains(feed, "UID:review/pr-1/u2@pr-service\r\n") {
+		t.Fatalf("expected a single event for pr-1, got %q", feed)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Fatalf("expected folded UTF-8 lines of at most 75 octets, got %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	reviews, err := s.prRepo.GetPendingReviewsByReviewer(context.Background(), "u2")
+	if err != nil || len(reviews) != 1 {
+		t.Fatalf("expected one pending review for u2, got %v, %v", reviews, err)
+	}
+	due := reviews[0].AssignedAt.Add(user.DefaultReviewSLA).UTC().Format("20060102T150405Z")
+	if !strings.Contains(unfolded, "DTSTART:"+due+"\r\n") {
+		t.Fatalf("expected the event at the SLA due time %s, got %q", due, unfolded)
+	}
+	wantSummary := `SUMMARY:Review "Refunds\, partial\; ` + settings + `" (pr-1)`
+	if !strings.Contains(unfolded, wantSummary+"\r\n") || !strings.Contains(unfolded, `DESCRIPTION:Author: u1\nAssigned: `) {
+		t.Fatalf("expected escaped summary and description, got %q", unfolded)
+	}
+
+	if feed := getCalendar("u1", http.StatusOK); strings.Contains(feed, "BEGIN:VEVENT") {
+		t.Fatalf("expected an empty calendar for the author, got %q", feed)
+	}
+	getCalendar("unknown", http.StatusNotFound)
+}
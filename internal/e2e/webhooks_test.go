@@ -0,0 +1,567 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"pr-service/internal/handler"
+	"pr-service/internal/notify"
+)
+
+const testGitHubSecret = "github-secret"
+
+// postGitHubEvent delivers a GitHub webhook signed with secret
+func (s *testServer) postGitHubEvent(event, secret string, payload any, expectedStatus int, out any) {
+	s.t.Helper()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		s.t.Fatalf("failed to marshal payload: %v", err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	s.postWithHeaders("/integrations/github/webhook", http.Header{
+		"Content-Type":        {"application/json"},
+		"X-Github-Event":      {event},
+		"X-Hub-Signature-256": {"sha256=" + hex.EncodeToString(mac.Sum(nil))},
+	}, bytes.NewReader(body), expectedStatus, out)
+}
+
+func TestHTTPE2EGitHubWebhook(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+
+	event := func(action string, merged bool, login string) map[string]any {
+		return map[string]any{
+			"action": action,
+			"number": 42,
+			"pull_request": map[string]any{
+				"title":  "Add refunds",
+				"merged": merged,
+				"user":   map[string]any{"login": login},
+			},
+			"repository": map[string]any{"name": "payments-api", "full_name": "acme/payments-api"},
+		}
+	}
+	type webhookResult struct {
+		Result string `json:"result"`
+		PR     struct {
+			PullRequestID     string   `json:"pull_request_id"`
+			AuthorID          string   `json:"author_id"`
+			Repository        string   `json:"repository"`
+			Status            string   `json:"status"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+
+	s.postGitHubEvent("pull_request", "wrong-secret", event("opened", false, "alice-gh"), http.StatusUnauthorized, nil)
+	s.postGitHubEvent("ping", testGitHubSecret, map[string]any{"zen": "Keep it simple."}, http.StatusOK, nil)
+
+	var result webhookResult
+	s.postGitHubEvent("pull_request", testGitHubSecret, event("opened", false, "alice-gh"), http.StatusOK, &result)
+	if result.Result != "created" || result.PR.PullRequestID != "acme/payments-api#42" ||
+		result.PR.AuthorID != "u1" || result.PR.Repository != "payments-api" ||
+		len(result.PR.AssignedReviewers) != 1 || result.PR.AssignedReviewers[0] != "u2" {
+		t.Fatalf("expected the PR to be created for u1 with u2 reviewing, got %+v", result)
+	}
+
+	s.postGitHubEvent("pull_request", testGitHubSecret, event("opened", false, "alice-gh"), http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected a redelivered event to be ignored, got %+v", result)
+	}
+	s.postGitHubEvent("pull_request", testGitHubSecret, event("closed", false, "alice-gh"), http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected closing without merge to be ignored, got %+v", result)
+	}
+
+	s.postGitHubEvent("pull_request", testGitHubSecret, event("closed", true, "alice-gh"), http.StatusOK, &result)
+	if result.Result != "merged" || result.PR.Status != "MERGED" {
+		t.Fatalf("expected the PR to be merged, got %+v", result)
+	}
+
+	unknown := event("opened", false, "mallory-gh")
+	unknown["number"] = 43
+	s.postGitHubEvent("pull_request", testGitHubSecret, unknown, http.StatusNotFound, nil)
+}
+
+const testGitLabToken = "gitlab-token"
+
+func TestHTTPE2EGitLabWebhook(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+
+	deliver := func(token string, action string, expectedStatus int, out any) {
+		t.Helper()
+		body, err := json.Marshal(map[string]any{
+			"object_kind": "merge_request",
+			"user":        map[string]any{"username": "alice-gl"},
+			"project":     map[string]any{"name": "payments-api", "path_with_namespace": "acme/payments-api"},
+			"object_attributes": map[string]any{
+				"iid":    7,
+				"title":  "Add refunds",
+				"action": action,
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to marshal payload: %v", err)
+		}
+		s.postWithHeaders("/integrations/gitlab/webhook", http.Header{
+			"Content-Type":   {"application/json"},
+			"X-Gitlab-Event": {"Merge Request Hook"},
+			"X-Gitlab-Token": {token},
+		}, bytes.NewReader(body), expectedStatus, out)
+	}
+	var result struct {
+		Result string `json:"result"`
+		PR     struct {
+			PullRequestID string `json:"pull_request_id"`
+			AuthorID      string `json:"author_id"`
+			Repository    string `json:"repository"`
+			Status        string `json:"status"`
+		} `json:"pr"`
+	}
+
+	deliver("wrong-token", "open", http.StatusUnauthorized, nil)
+
+	deliver(testGitLabToken, "open", http.StatusOK, &result)
+	if result.Result != "created" || result.PR.PullRequestID != "acme/payments-api!7" ||
+		result.PR.AuthorID != "u1" || result.PR.Repository != "payments-api" {
+		t.Fatalf("expected the MR to be created for u1, got %+v", result)
+	}
+	deliver(testGitLabToken, "update", http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected updates to be ignored, got %+v", result)
+	}
+	deliver(testGitLabToken, "close", http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected closing without merge to be ignored, got %+v", result)
+	}
+	deliver(testGitLabToken, "reopen", http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected reopening a tracked MR to be ignored, got %+v", result)
+	}
+	deliver(testGitLabToken, "merge", http.StatusOK, &result)
+	if result.Result != "merged" || result.PR.Status != "MERGED" {
+		t.Fatalf("expected the MR to be merged, got %+v", result)
+	}
+}
+
+func TestHTTPE2EBitbucketWebhook(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+
+	deliver := func(eventKey, workspace, secret string, id int, nickname string, expectedStatus int, out any) {
+		t.Helper()
+		body, err := json.Marshal(map[string]any{
+			"pullrequest": map[string]any{
+				"id":     id,
+				"title":  "Add refunds",
+				"author": map[string]any{"nickname": nickname},
+			},
+			"repository": map[string]any{
+				"name":      "payments-api",
+				"full_name": workspace + "/payments-api",
+				"workspace": map[string]any{"slug": workspace},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to marshal payload: %v", err)
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		s.postWithHeaders("/integrations/bitbucket/webhook", http.Header{
+			"Content-Type":    {"application/json"},
+			"X-Event-Key":     {eventKey},
+			"X-Hub-Signature": {"sha256=" + hex.EncodeToString(mac.Sum(nil))},
+		}, bytes.NewReader(body), expectedStatus, out)
+	}
+	var result struct {
+		Result string `json:"result"`
+		PR     struct {
+			PullRequestID string `json:"pull_request_id"`
+			AuthorID      string `json:"author_id"`
+			Status        string `json:"status"`
+		} `json:"pr"`
+	}
+
+	deliver("pullrequest:created", "acme", "globex-secret", 1, "alice-bb", http.StatusUnauthorized, nil)
+	deliver("pullrequest:created", "initech", "acme-secret", 1, "alice-bb", http.StatusUnauthorized, nil)
+
+	deliver("pullrequest:created", "acme", "acme-secret", 1, "alice-bb", http.StatusOK, &result)
+	if result.Result != "created" || result.PR.PullRequestID != "acme/payments-api#1" || result.PR.AuthorID != "u1" {
+		t.Fatalf("expected the PR to be created for u1, got %+v", result)
+	}
+	deliver("pullrequest:created", "globex", "globex-secret", 1, "u2", http.StatusOK, &result)
+	if result.Result != "created" || result.PR.PullRequestID != "globex/payments-api#1" || result.PR.AuthorID != "u2" {
+		t.Fatalf("expected the unmapped nickname to be used as the user ID, got %+v", result)
+	}
+
+	deliver("pullrequest:rejected", "globex", "globex-secret", 1, "u2", http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected declined PRs to be ignored, got %+v", result)
+	}
+	deliver("pullrequest:fulfilled", "acme", "acme-secret", 1, "alice-bb", http.StatusOK, &result)
+	if result.Result != "merged" || result.PR.Status != "MERGED" {
+		t.Fatalf("expected the PR to be merged, got %+v", result)
+	}
+}
+
+// testGenericAdapters map the payloads of two made-up in-house systems
+var testGenericAdapters = map[string]handler.GenericAdapter{
+	"deploybot": {
+		Token:    "deploybot-token",
+		Action:   "$.event",
+		CreateOn: []string{"change.opened"},
+		MergeOn:  []string{"change.landed"},
+		Fields: map[string]string{
+			"pull_request_id":   "{$.project.key}-{$.change.number}",
+			"pull_request_name": "$.change['summary line']",
+			"author_id":         "$.change.owner.login",
+			"repository":        "$.project.key",
+			"ticket_key":        "$.change.tickets[0]",
+		},
+		Users: map[string]string{"alice": "u1"},
+	},
+	"signed": {
+		Secret:          "signed-secret",
+		SignatureHeader: "X-Signature",
+		Action:          "$.type",
+		MergeOn:         []string{"merged"},
+		Fields:          map[string]string{"pull_request_id": "$.id"},
+	},
+}
+
+func TestHTTPE2EGenericWebhook(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"))
+
+	deliver := func(adapter string, header http.Header, payload map[string]any, expectedStatus int, out any) {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("failed to marshal payload: %v", err)
+		}
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Content-Type", "application/json")
+		if secret := header.Get("X-Test-Secret"); secret != "" {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+		s.postWithHeaders("/integrations/generic/"+adapter+"/webhook", header, bytes.NewReader(body), expectedStatus, out)
+	}
+	change := func(event string, number int, owner string) map[string]any {
+		return map[string]any{
+			"event":   event,
+			"project": map[string]any{"key": "billing"},
+			"change": map[string]any{
+				"number":       number,
+				"summary line": "Add refunds",
+				"owner":        map[string]any{"login": owner},
+				"tickets":      []string{"pay-1"},
+			},
+		}
+	}
+	token := http.Header{"X-Webhook-Token": {"deploybot-token"}}
+	var result struct {
+		Result string `json:"result"`
+		PR     struct {
+			PullRequestID   string `json:"pull_request_id"`
+			PullRequestName string `json:"pull_request_name"`
+			AuthorID        string `json:"author_id"`
+			Repository      string `json:"repository"`
+			TicketKey       string `json:"ticket_key"`
+			Status          string `json:"status"`
+		} `json:"pr"`
+	}
+
+	deliver("deploybot", http.Header{"X-Webhook-Token": {"wrong"}}, change("change.opened", 7, "alice"), http.StatusUnauthorized, nil)
+	deliver("unknown", token, change("change.opened", 7, "alice"), http.StatusNotFound, nil)
+
+	deliver("deploybot", token, change("change.opened", 7, "alice"), http.StatusOK, &result)
+	if result.Result != "created" || result.PR.PullRequestID != "billing-7" || result.PR.PullRequestName != "Add refunds" ||
+		result.PR.AuthorID != "u1" || result.PR.Repository != "billing" || result.PR.TicketKey != "PAY-1" {
+		t.Fatalf("expected the mapped PR to be created for u1, got %+v", result)
+	}
+	deliver("deploybot", token, change("change.opened", 7, "alice"), http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected a redelivery to be ignored, got %+v", result)
+	}
+	deliver("deploybot", token, change("change.commented", 8, "u2"), http.StatusOK, &result)
+	if result.Result != "ignored" {
+		t.Fatalf("expected unmapped actions to be ignored, got %+v", result)
+	}
+
+	// A payload missing a mapped field is rejected
+	missing := change("change.opened", 8, "u2")
+	delete(missing["change"].(map[string]any), "owner")
+	deliver("deploybot", token, missing, http.StatusBadRequest, nil)
+
+	deliver("signed", http.Header{"X-Test-Secret": {"other-secret"}}, map[string]any{"type": "merged", "id": "billing-7"}, http.StatusUnauthorized, nil)
+	deliver("signed", http.Header{"X-Test-Secret": {"signed-secret"}}, map[string]any{"type": "merged", "id": "billing-7"}, http.StatusOK, &result)
+	if result.Result != "merged" || result.PR.PullRequestID != "billing-7" || result.PR.Status != "MERGED" {
+		t.Fatalf("expected the PR to be merged, got %+v", result)
+	}
+
+	for name, adapter := range map[string]handler.GenericAdapter{
+		"no credentials":  {Action: "$.a", MergeOn: []string{"m"}, Fields: map[string]string{"pull_request_id": "$.id"}},
+		"bad action path": {Token: "t", Action: "a", MergeOn: []string{"m"}, Fields: map[string]string{"pull_request_id": "$.id"}},
+		"no actions":      {Token: "t", Action: "$.a", Fields: map[string]string{"pull_request_id": "$.id"}},
+		"no id":           {Token: "t", Action: "$.a", MergeOn: []string{"m"}},
+		"create no name":  {Token: "t", Action: "$.a", CreateOn: []string{"c"}, Fields: map[string]string{"pull_request_id": "$.id", "author_id": "$.u"}},
+		"unknown field":   {Token: "t", Action: "$.a", MergeOn: []string{"m"}, Fields: map[string]string{"pull_request_id": "$.id", "status": "$.s"}},
+		"bad template":    {Token: "t", Action: "$.a", MergeOn: []string{"m"}, Fields: map[string]string{"pull_request_id": "pr-{$.id"}},
+		"ambiguous":       {Token: "t", Action: "$.a", CreateOn: []string{"x"}, MergeOn: []string{"x"}, Fields: map[string]string{"pull_request_id": "$.id", "pull_request_name": "$.n", "author_id": "$.u"}},
+	} {
+		if _, err := handler.NewGenericWebhookHandler(s.pr, map[string]handler.GenericAdapter{"bot": adapter}, zap.NewNop()); err == nil {
+			t.Errorf("%s: expected the adapter to be rejected", name)
+		}
+	}
+}
+
+// testWebhookMaxAttempts is the outbound webhook retry limit of the test server
+const testWebhookMaxAttempts = 3
+
+type receivedWebhook struct {
+	event     string
+	delivery  string
+	signature string
+	body      []byte
+}
+
+type webhookDeliveriesResponse struct {
+	Deliveries []struct {
+		ID             int64           `json:"id"`
+		Event          string          `json:"event"`
+		Payload        json.RawMessage `json:"payload"`
+		Status         string          `json:"status"`
+		Attempts       int             `json:"attempts"`
+		NextAttemptAt  string          `json:"next_attempt_at"`
+		LastError      string          `json:"last_error"`
+		ResponseStatus *int            `json:"response_status"`
+		DeliveredAt    string          `json:"delivered_at"`
+	} `json:"deliveries"`
+	Total int `json:"total"`
+}
+
+func TestHTTPE2EOutboundWebhooks(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	var (
+		mu       sync.Mutex
+		received []receivedWebhook
+		failNext = true
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if failNext {
+			failNext = false
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received = append(received, receivedWebhook{
+			event:     r.Header.Get(notify.EventHeader),
+			delivery:  r.Header.Get(notify.DeliveryHeader),
+			signature: r.Header.Get(notify.SignatureHeader),
+			body:      body,
+		})
+	}))
+	defer receiver.Close()
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer dead.Close()
+
+	s.postJSON("/webhooks/subscribe", map[string]any{
+		"url":         receiver.URL,
+		"event_types": []string{"pr.bogus"},
+	}, http.StatusBadRequest, nil)
+	s.postJSON("/webhooks/subscribe", map[string]any{
+		"url":         "ftp://example.com/hook",
+		"event_types": []string{"pr.created"},
+	}, http.StatusBadRequest, nil)
+
+	var sub struct {
+		Subscription struct {
+			ID         int64    `json:"id"`
+			Secret     string   `json:"secret"`
+			EventTypes []string `json:"event_types"`
+		} `json:"subscription"`
+	}
+	s.postJSON("/webhooks/subscribe", map[string]any{
+		"url":         receiver.URL,
+		"secret":      "s3cret",
+		"event_types": []string{"pr.created", "reviewer.reassigned", "pr.merged", "pr.created"},
+	}, http.StatusCreated, &sub)
+	if sub.Subscription.Secret != "s3cret" || len(sub.Subscription.EventTypes) != 3 {
+		t.Fatalf("unexpected subscription %+v", sub.Subscription)
+	}
+	var deadSub struct {
+		Subscription struct {
+			ID     int64  `json:"id"`
+			Secret string `json:"secret"`
+		} `json:"subscription"`
+	}
+	s.postJSON("/webhooks/subscribe", map[string]any{
+		"url":         dead.URL,
+		"event_types": []string{"pr.merged"},
+	}, http.StatusCreated, &deadSub)
+	if len(deadSub.Subscription.Secret) != 64 {
+		t.Fatalf("expected a generated secret, got %q", deadSub.Subscription.Secret)
+	}
+
+	var list struct {
+		Subscriptions []struct {
+			ID     int64  `json:"id"`
+			Secret string `json:"secret"`
+		} `json:"subscriptions"`
+	}
+	s.getJSON("/webhooks/list", http.StatusOK, &list)
+	if len(list.Subscriptions) != 2 || list.Subscriptions[0].Secret != "" {
+		t.Fatalf("expected two subscriptions without secrets, got %+v", list.Subscriptions)
+	}
+
+	s.addTeam("backend", activeMember("u1", "Alice"), activeMember("u2", "Bob"), activeMember("u3", "Carol"), activeMember("u4", "Dave"))
+	created := s.createPR("pr-1", "Change", "u1")
+	s.postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     created.PR.AssignedReviewers[0],
+	}, http.StatusOK, nil)
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+	// A repeated merge changes nothing and publishes nothing
+	s.postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK, nil)
+
+	ctx := context.Background()
+	now := time.Now()
+	if attempted, err := s.webhooks.DeliverDue(ctx, now, 10); err != nil || attempted != 4 {
+		t.Fatalf("expected four attempts, got %d (%v)", attempted, err)
+	}
+
+	var pending webhookDeliveriesResponse
+	s.getJSON(fmt.Sprintf("/webhooks/deliveries?subscription_id=%d&status=pending", sub.Subscription.ID), http.StatusOK, &pending)
+	if pending.Total != 1 || pending.Deliveries[0].Event != "pr.created" ||
+		pending.Deliveries[0].Attempts != 1 || pending.Deliveries[0].ResponseStatus == nil ||
+		*pending.Deliveries[0].ResponseStatus != http.StatusInternalServerError ||
+		pending.Deliveries[0].NextAttemptAt == "" {
+		t.Fatalf("expected the first delivery to be pending a retry, got %+v", pending)
+	}
+
+	// The retry is not due until the backoff has passed
+	if attempted, err := s.webhooks.DeliverDue(ctx, now, 10); err != nil || attempted != 0 {
+		t.Fatalf("expected no due deliveries, got %d (%v)", attempted, err)
+	}
+	// Both retries are due after a minute; the dead receiver then waits twice as long
+	now = now.Add(time.Minute)
+	if attempted, err := s.webhooks.DeliverDue(ctx, now, 10); err != nil || attempted != 2 {
+		t.Fatalf("expected two retries, got %d (%v)", attempted, err)
+	}
+	now = now.Add(time.Minute)
+	if attempted, err := s.webhooks.DeliverDue(ctx, now, 10); err != nil || attempted != 0 {
+		t.Fatalf("expected the backoff to double, got %d attempts (%v)", attempted, err)
+	}
+	now = now.Add(time.Minute)
+	if attempted, err := s.webhooks.DeliverDue(ctx, now, 10); err != nil || attempted != 1 {
+		t.Fatalf("expected the last retry of the dead receiver, got %d (%v)", attempted, err)
+	}
+
+	var deliveries webhookDeliveriesResponse
+	s.getJSON(fmt.Sprintf("/webhooks/deliveries?subscription_id=%d", sub.Subscription.ID), http.StatusOK, &deliveries)
+	if deliveries.Total != 3 {
+		t.Fatalf("expected three deliveries, got %+v", deliveries)
+	}
+	for _, d := range deliveries.Deliveries {
+		if d.Status != "DELIVERED" || d.DeliveredAt == "" || d.LastError != "" {
+			t.Fatalf("expected every delivery to succeed, got %+v", d)
+		}
+	}
+
+	var failed webhookDeliveriesResponse
+	s.getJSON(fmt.Sprintf("/webhooks/deliveries?subscription_id=%d", deadSub.Subscription.ID), http.StatusOK, &failed)
+	if failed.Total != 1 || failed.Deliveries[0].Status != "FAILED" ||
+		failed.Deliveries[0].Attempts != testWebhookMaxAttempts || failed.Deliveries[0].LastError == "" {
+		t.Fatalf("expected the dead receiver's delivery to fail after %d attempts, got %+v", testWebhookMaxAttempts, failed)
+	}
+	if attempted, err := s.webhooks.DeliverDue(ctx, now.Add(time.Hour), 10); err != nil || attempted != 0 {
+		t.Fatalf("expected failed deliveries to stay failed, got %d (%v)", attempted, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	events := make([]string, len(received))
+	for i, hook := range received {
+		events[i] = hook.event
+		if hook.signature != notify.Sign([]byte("s3cret"), hook.body) {
+			t.Fatalf("bad signature on %s delivery", hook.event)
+		}
+		if hook.delivery == "" {
+			t.Fatalf("missing delivery ID on %s delivery", hook.event)
+		}
+	}
+	if !slices.Equal(events, []string{"reviewer.reassigned", "pr.merged", "pr.created"}) {
+		t.Fatalf("unexpected delivered events %v", events)
+	}
+
+	var reassigned struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+		OldReviewerID string `json:"old_reviewer_id"`
+	}
+	if err := json.Unmarshal(received[0].body, &reassigned); err != nil {
+		t.Fatalf("decode reassignment payload: %v", err)
+	}
+	if reassigned.PullRequestID != "pr-1" || reassigned.OldReviewerID != created.PR.AssignedReviewers[0] || reassigned.ReviewerID == "" {
+		t.Fatalf("unexpected reassignment payload %+v", reassigned)
+	}
+	var opened struct {
+		Event       string `json:"event"`
+		PullRequest struct {
+			PullRequestID     string   `json:"pull_request_id"`
+			Status            string   `json:"status"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(received[2].body, &opened); err != nil {
+		t.Fatalf("decode created payload: %v", err)
+	}
+	if opened.Event != "pr.created" || opened.PullRequest.PullRequestID != "pr-1" ||
+		opened.PullRequest.Status != "OPEN" || len(opened.PullRequest.AssignedReviewers) != 2 {
+		t.Fatalf("unexpected created payload %+v", opened)
+	}
+
+	s.postJSON("/webhooks/delete", map[string]int64{"id": sub.Subscription.ID}, http.StatusOK, nil)
+	s.postJSON("/webhooks/delete", map[string]int64{"id": sub.Subscription.ID}, http.StatusNotFound, nil)
+	var removed webhookDeliveriesResponse
+	s.getJSON(fmt.Sprintf("/webhooks/deliveries?subscription_id=%d", sub.Subscription.ID), http.StatusOK, &removed)
+	if removed.Total != 0 {
+		t.Fatalf("expected the delivery log to be removed with the subscription, got %+v", removed)
+	}
+	s.getJSON("/webhooks/deliveries?subscription_id=x", http.StatusBadRequest, nil)
+}
@@ -0,0 +1,74 @@
+// Package event renders domain events in the JSON schema shared by every
+// outbound transport (webhooks, Kafka)
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+type pullRequestPayload struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name,omitempty"`
+	Repository        string     `json:"repository,omitempty"`
+	TicketKey         string     `json:"ticket_key,omitempty"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+}
+
+type payload struct {
+	Event         domain.EventType    `json:"event"`
+	OrgID         string              `json:"org_id,omitempty"`
+	OccurredAt    time.Time           `json:"occurred_at"`
+	PullRequestID string              `json:"pull_request_id,omitempty"`
+	PullRequest   *pullRequestPayload `json:"pull_request,omitempty"`
+	ReviewerID    string              `json:"reviewer_id,omitempty"`
+	OldReviewerID string              `json:"old_reviewer_id,omitempty"`
+	UserID        string              `json:"user_id,omitempty"`
+	TeamNames     []string            `json:"team_names,omitempty"`
+}
+
+// Marshal encodes e as a JSON event payload
+func Marshal(e domain.Event) ([]byte, error) {
+	p := payload{
+		Event:         e.Type,
+		OrgID:         e.OrgID,
+		OccurredAt:    e.OccurredAt,
+		PullRequestID: e.PullRequestID,
+		ReviewerID:    e.ReviewerID,
+		OldReviewerID: e.OldReviewerID,
+		UserID:        e.UserID,
+		TeamNames:     e.TeamNames,
+	}
+	if pr := e.PR; pr != nil {
+		reviewers := pr.AssignedReviewers
+		if reviewers == nil {
+			reviewers = []string{}
+		}
+		p.PullRequest = &pullRequestPayload{
+			PullRequestID:     pr.PullRequestID,
+			PullRequestName:   pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			TeamName:          pr.TeamName,
+			Repository:        pr.Repository,
+			TicketKey:         pr.TicketKey,
+			Status:            string(pr.Status),
+			AssignedReviewers: reviewers,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+		}
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", e.Type, err)
+	}
+	return data, nil
+}
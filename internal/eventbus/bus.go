@@ -0,0 +1,82 @@
+// Package eventbus fans committed domain events out to in-process subscribers
+// such as the event stream served to dashboards
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+)
+
+// DefaultBuffer is how many events a subscriber may fall behind by before
+// further events are dropped for it
+const DefaultBuffer = 256
+
+// Bus broadcasts events to its subscribers without blocking the publisher.
+// A subscriber that does not keep up misses events rather than slowing the
+// others down.
+type Bus struct {
+	mu     sync.Mutex
+	subs   map[chan domain.Event]struct{}
+	closed bool
+}
+
+// New creates a new event bus
+func New() *Bus {
+	return &Bus{subs: make(map[chan domain.Event]struct{})}
+}
+
+// Notify broadcasts committed events to every subscriber
+func (b *Bus) Notify(_ context.Context, events ...domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		for _, event := range events {
+			select {
+			case sub <- event:
+			default:
+				metrics.EventStreamDropped.Inc()
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel receiving events published from now on and a
+// function ending the subscription. The channel is closed when the
+// subscription ends or the bus is closed; a non-positive buffer uses DefaultBuffer.
+func (b *Bus) Subscribe(buffer int) (<-chan domain.Event, func()) {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	sub := make(chan domain.Event, buffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub)
+		return sub, func() {}
+	}
+	b.subs[sub] = struct{}{}
+
+	return sub, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[sub]; ok {
+			delete(b.subs, sub)
+			close(sub)
+		}
+	}
+}
+
+// Close ends every subscription, so long-lived streams finish on shutdown
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub)
+	}
+}
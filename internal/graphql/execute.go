@@ -0,0 +1,140 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// CodedError is a resolver error reported with a machine-readable code in its extensions
+type CodedError struct {
+	Code    string
+	Message string
+}
+
+func (e *CodedError) Error() string { return e.Message }
+
+// Extensions puts the code in the extensions of the reported error
+func (e *CodedError) Extensions() map[string]any {
+	return map[string]any{"code": e.Code}
+}
+
+// Response is the result of a request. Data is absent when the request was
+// rejected before execution and null when a non-null root field failed;
+// field errors are reported next to partial data.
+type Response struct {
+	Data   json.RawMessage            `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// Rejected reports whether the request was invalid and nothing was executed
+func (r *Response) Rejected() bool {
+	return r.Data == nil
+}
+
+// Execute validates and runs the operation of req, rejecting it when it is
+// invalid or nests fields deeper than the schema allows
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &Response{Errors: gqlerrors.FormatErrors(err)}
+	}
+	// Fragment cycles are rejected first: the rule checking overlapping fields
+	// recurses through fragment spreads and never returns on a cycle
+	for _, rules := range [][]graphql.ValidationRuleFn{{graphql.NoFragmentCyclesRule}, graphql.SpecifiedRules} {
+		if validation := graphql.ValidateDocument(&s.schema, doc, rules); !validation.IsValid {
+			return &Response{Errors: validation.Errors}
+		}
+	}
+	if queryDepth(doc) > s.maxDepth {
+		return &Response{Errors: gqlerrors.FormatErrors(fmt.Errorf("query is nested deeper than %d levels", s.maxDepth))}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	if result.Data == nil && !fieldErrors(result.Errors) {
+		// The operation or its variables were invalid
+		return &Response{Errors: result.Errors}
+	}
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return &Response{Data: json.RawMessage("null"), Errors: append(result.Errors, gqlerrors.FormatError(err))}
+	}
+	return &Response{Data: data, Errors: result.Errors}
+}
+
+// fieldErrors reports whether errs come from resolving fields rather than
+// from rejecting the request
+func fieldErrors(errs []gqlerrors.FormattedError) bool {
+	for _, err := range errs {
+		if len(err.Path) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// queryDepth returns how deeply the operations of a validated doc nest
+// fields. Introspection fields do not count, since tools query them several
+// levels deeper than any dashboard query.
+func queryDepth(doc *ast.Document) int {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[frag.Name.Value] = frag
+		}
+	}
+	deepest := 0
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			deepest = max(deepest, selectionDepth(op.SelectionSet, fragments))
+		}
+	}
+	return deepest
+}
+
+// selectionDepth follows fragment spreads, which validation guarantees not to
+// form cycles
+func selectionDepth(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition) int {
+	if set == nil {
+		return 0
+	}
+	deepest := 0
+	for _, sel := range set.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if !strings.HasPrefix(sel.Name.Value, "__") {
+				deepest = max(deepest, 1+selectionDepth(sel.SelectionSet, fragments))
+			}
+		case *ast.InlineFragment:
+			deepest = max(deepest, selectionDepth(sel.SelectionSet, fragments))
+		case *ast.FragmentSpread:
+			if frag, ok := fragments[sel.Name.Value]; ok {
+				deepest = max(deepest, selectionDepth(frag.SelectionSet, fragments))
+			}
+		}
+	}
+	return deepest
+}
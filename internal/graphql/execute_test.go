@@ -0,0 +1,232 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+type testBook struct {
+	Title  string
+	Pages  int
+	Author *testAuthor
+}
+
+type testAuthor struct {
+	Name  string
+	Books []testBook
+}
+
+func newTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	tolkien := &testAuthor{Name: "Tolkien"}
+	tolkien.Books = []testBook{{Title: "The Hobbit", Pages: 310, Author: tolkien}, {Title: "Silmarillion", Pages: 365, Author: tolkien}}
+
+	author := graphql.NewObject(graphql.ObjectConfig{Name: "Author", Fields: graphql.Fields{
+		"name": {Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+			return p.Source.(*testAuthor).Name, nil
+		}},
+	}})
+	book := graphql.NewObject(graphql.ObjectConfig{Name: "Book", Description: "A book", Fields: graphql.Fields{
+		"title": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (any, error) {
+			return p.Source.(testBook).Title, nil
+		}},
+		"pages": {Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (any, error) {
+			return p.Source.(testBook).Pages, nil
+		}},
+		"author": {Type: author, Resolve: func(p graphql.ResolveParams) (any, error) {
+			return p.Source.(testBook).Author, nil
+		}},
+		"isbn": {Type: graphql.NewNonNull(graphql.String), Resolve: func(graphql.ResolveParams) (any, error) {
+			return nil, &CodedError{Code: "NOT_FOUND", Message: "no isbn"}
+		}},
+	}})
+	author.AddFieldConfig("books", &graphql.Field{
+		Type: graphql.NewList(book),
+		Args: graphql.FieldConfigArgument{"first": {Type: graphql.Int}},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			books := p.Source.(*testAuthor).Books
+			if n, ok := p.Args["first"].(int); ok && n < len(books) {
+				books = books[:n]
+			}
+			return books, nil
+		},
+	})
+	query := graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+		"author": {
+			Type: author,
+			Args: graphql.FieldConfigArgument{"name": {Type: graphql.NewNonNull(graphql.String)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if p.Args["name"] == tolkien.Name {
+					return tolkien, nil
+				}
+				return nil, nil
+			},
+		},
+		"fail": {Type: graphql.String, Resolve: func(graphql.ResolveParams) (any, error) {
+			return nil, errors.New("boom")
+		}},
+	}})
+
+	schema, err := NewSchema(query, 4)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	return schema
+}
+
+func execute(t *testing.T, schema *Schema, query string, vars map[string]any) (string, []gqlerrors.FormattedError) {
+	t.Helper()
+	resp := schema.Execute(context.Background(), Request{Query: query, Variables: vars})
+	if resp.Rejected() {
+		return "", resp.Errors
+	}
+	return string(resp.Data), resp.Errors
+}
+
+func TestExecute(t *testing.T) {
+	schema := newTestSchema(t)
+
+	cases := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  string
+	}{
+		{
+			name:  "nested fields",
+			query: `{ author(name: "Tolkien") { books { pages title } name } }`,
+			want:  `{"author":{"books":[{"pages":310,"title":"The Hobbit"},{"pages":365,"title":"Silmarillion"}],"name":"Tolkien"}}`,
+		},
+		{
+			name:  "aliases and arguments",
+			query: `query { t: author(name: "Tolkien") { first: books(first: 1) { title } } nobody: author(name: "x") { name } }`,
+			want:  `{"nobody":null,"t":{"first":[{"title":"The Hobbit"}]}}`,
+		},
+		{
+			name:  "variables and defaults",
+			query: `query Books($who: String!, $n: Int = 1) { author(name: $who) { books(first: $n) { title } } }`,
+			vars:  map[string]any{"who": "Tolkien"},
+			want:  `{"author":{"books":[{"title":"The Hobbit"}]}}`,
+		},
+		{
+			name: "fragments, directives and __typename",
+			query: `query ($full: Boolean!) { author(name: "Tolkien") { ...Names books(first: 1) { ... on Book { __typename title } pages @include(if: $full) } } }
+				fragment Names on Author { name @skip(if: false) }`,
+			vars: map[string]any{"full": false},
+			want: `{"author":{"books":[{"__typename":"Book","title":"The Hobbit"}],"name":"Tolkien"}}`,
+		},
+	}
+	for _, tc := range cases {
+		data, errs := execute(t, schema, tc.query, tc.vars)
+		if len(errs) > 0 || data != tc.want {
+			t.Errorf("%s: got %s %v, want %s", tc.name, data, errs, tc.want)
+		}
+	}
+}
+
+func TestExecuteFieldErrors(t *testing.T) {
+	schema := newTestSchema(t)
+
+	// A failed non-null field nulls its nearest nullable parent, here each book
+	data, errs := execute(t, schema, `{ fail author(name: "Tolkien") { name books { title isbn } } }`, nil)
+	if data != `{"author":{"books":[null,null],"name":"Tolkien"},"fail":null}` {
+		t.Fatalf("unexpected data %s", data)
+	}
+	if len(errs) != 3 {
+		encoded, _ := json.Marshal(errs)
+		t.Fatalf("unexpected errors %s", encoded)
+	}
+	var coded int
+	for _, err := range errs {
+		if err.Extensions["code"] != "NOT_FOUND" {
+			continue
+		}
+		coded++
+		if path, _ := json.Marshal(err.Path); !strings.HasPrefix(string(path), `["author","books",`) {
+			t.Fatalf("unexpected error path %s", path)
+		}
+	}
+	if coded != 2 {
+		encoded, _ := json.Marshal(errs)
+		t.Fatalf("expected both isbn errors to carry their code, got %s", encoded)
+	}
+}
+
+func TestExecuteRejected(t *testing.T) {
+	schema := newTestSchema(t)
+
+	for query, want := range map[string]string{
+		`{ author(name: "Tolkien") { name `:        "Syntax Error",
+		`{ author(name: "Tolkien") { nickname } }`: `Cannot query field "nickname"`,
+		`{ author { name } }`:                      `argument "name" of type "String!" is required`,
+		`{ author(name: "Tolkien") }`:              "must have a sub selection",
+		`{ author(name: "Tolkien") { books { title author { books { title } } } } }`: "nested deeper than 4 levels",
+		`mutation { fail }`:                     "not configured for mutations",
+		`{ ...F } fragment F on Query { ...F }`: `Cannot spread fragment "F" within itself`,
+		`query ($n: Int!) { fail(n: $n) }`:      `Unknown argument "n"`,
+		`query ($n: Int) { fail }`:              `Variable "$n" is never used`,
+	} {
+		if _, errs := execute(t, schema, query, nil); len(errs) != 1 || !strings.Contains(errs[0].Message, want) {
+			t.Errorf("%s: expected rejection %q, got %+v", query, want, errs)
+		}
+	}
+
+	// Variables are checked when the operation runs, before any resolver
+	if _, errs := execute(t, schema, `query ($who: String!) { author(name: $who) { name } }`, nil); len(errs) != 1 ||
+		!strings.Contains(errs[0].Message, `"$who" of required type "String!" was not provided`) {
+		t.Errorf("expected a missing variable to be rejected, got %+v", errs)
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	schema := newTestSchema(t)
+
+	data, errs := execute(t, schema, `{ __schema { queryType { name } } __type(name: "Book") { description fields { name } } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors %+v", errs)
+	}
+	var got struct {
+		Schema struct {
+			QueryType struct{ Name string } `json:"queryType"`
+		} `json:"__schema"`
+		Type struct {
+			Description string
+			Fields      []struct{ Name string }
+		} `json:"__type"`
+	}
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	if got.Schema.QueryType.Name != "Query" || got.Type.Description != "A book" || len(got.Type.Fields) != 4 {
+		t.Fatalf("unexpected introspection result %s", data)
+	}
+
+	// Introspection nests type references deeper than the depth limit
+	deep := `{ __schema { types { fields { type { ofType { ofType { ofType { ofType { name } } } } } } } } }`
+	if _, errs := execute(t, schema, deep, nil); len(errs) > 0 {
+		t.Fatalf("expected introspection to ignore the depth limit, got %+v", errs)
+	}
+}
+
+func TestSDL(t *testing.T) {
+	sdl := newTestSchema(t).SDL()
+	for _, want := range []string{
+		"schema {\n  query: Query\n}",
+		"type Author {\n  books(first: Int): [Book]\n  name: String\n}",
+		"\"\"\"A book\"\"\"\ntype Book {\n",
+		"  author(name: String!): Author\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL misses %q:\n%s", want, sdl)
+		}
+	}
+	if strings.Contains(sdl, "__") {
+		t.Errorf("SDL should not describe introspection types:\n%s", sdl)
+	}
+}
@@ -0,0 +1,104 @@
+// Package graphql serves read-only GraphQL queries with graphql-go, which
+// parses, validates and executes them and answers introspection. On top of it
+// the package bounds how deeply queries nest, reports resolver errors with a
+// machine-readable code and renders the schema in SDL.
+package graphql
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// DefaultMaxDepth bounds how deeply queries may nest fields when no limit is set
+const DefaultMaxDepth = 10
+
+// Schema is an executable schema rooted at a query type
+type Schema struct {
+	schema   graphql.Schema
+	maxDepth int
+}
+
+// NewSchema validates the types reachable from query and creates a schema;
+// a non-positive maxDepth uses DefaultMaxDepth
+func NewSchema(query *graphql.Object, maxDepth int) (*Schema, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{schema: schema, maxDepth: maxDepth}, nil
+}
+
+// SDL renders the schema in the GraphQL schema definition language, with
+// types, fields and arguments sorted by name
+func (s *Schema) SDL() string {
+	var b strings.Builder
+	b.WriteString("schema {\n  query: " + s.schema.QueryType().Name() + "\n}\n")
+
+	typeMap := s.schema.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch t := typeMap[name].(type) {
+		case *graphql.Scalar:
+			if isBuiltIn(name) {
+				continue
+			}
+			b.WriteString("\n")
+			writeDescription(&b, "", t.Description())
+			b.WriteString("scalar " + name + "\n")
+		case *graphql.Object:
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			b.WriteString("\n")
+			writeDescription(&b, "", t.Description())
+			b.WriteString("type " + name + " {\n")
+			fields := t.Fields()
+			fieldNames := make([]string, 0, len(fields))
+			for fieldName := range fields {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				f := fields[fieldName]
+				writeDescription(&b, "  ", f.Description)
+				b.WriteString("  " + fieldName)
+				if len(f.Args) > 0 {
+					args := make([]string, len(f.Args))
+					for i, a := range f.Args {
+						args[i] = a.Name() + ": " + a.Type.String()
+					}
+					sort.Strings(args)
+					b.WriteString("(" + strings.Join(args, ", ") + ")")
+				}
+				b.WriteString(": " + f.Type.String() + "\n")
+			}
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+// isBuiltIn reports whether name is a scalar every GraphQL server defines
+func isBuiltIn(name string) bool {
+	switch name {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return true
+	}
+	return false
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	b.WriteString(indent + `"""` + strings.ReplaceAll(description, `"""`, `\"""`) + `"""` + "\n")
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+
+	"go.uber.org/zap"
+)
+
+type auditService interface {
+	List(ctx context.Context, filter domain.AuditFilter, page pagination.Page) (pagination.Result[domain.AuditEntry], error)
+}
+
+// AuditHandler serves the log of state-changing API requests
+type AuditHandler struct {
+	service auditService
+	logger  *zap.Logger
+}
+
+// NewAuditHandler creates a new audit log handler
+func NewAuditHandler(service auditService, logger *zap.Logger) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// AuditEntryDTO is one recorded request; ErrorCode is set for rejected ones
+type AuditEntryDTO struct {
+	ID          int64  `json:"id"`
+	Actor       string `json:"actor,omitempty"`
+	Method      string `json:"method"`
+	Route       string `json:"route"`
+	Path        string `json:"path"`
+	PayloadHash string `json:"payload_hash"`
+	Status      int    `json:"status"`
+	ErrorCode   string `json:"error_code,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type listAuditEntriesResponse struct {
+	Entries    []AuditEntryDTO `json:"entries"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+// List handles GET /admin/audit?actor=...&route=...&failed=...&from=...&to=...&limit=...&cursor=...&order=...
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.ParseRequest(r, pagination.OrderDesc)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	result, err := h.service.List(r.Context(), filter, page)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := listAuditEntriesResponse{
+		Entries:    make([]AuditEntryDTO, len(result.Items)),
+		Total:      result.Total,
+		NextCursor: result.NextCursor,
+	}
+	for i, entry := range result.Items {
+		resp.Entries[i] = AuditEntryDTO{
+			ID:          entry.ID,
+			Actor:       entry.Actor,
+			Method:      entry.Method,
+			Route:       entry.Route,
+			Path:        entry.Path,
+			PayloadHash: entry.PayloadHash,
+			Status:      entry.Status,
+			ErrorCode:   entry.ErrorCode,
+			RequestID:   entry.RequestID,
+			CreatedAt:   entry.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// parseAuditFilter reads the optional filters of the audit log listing
+func parseAuditFilter(r *http.Request) (domain.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := domain.AuditFilter{
+		Actor: strings.TrimSpace(query.Get("actor")),
+		Route: strings.TrimSpace(query.Get("route")),
+	}
+	var v domain.Validator
+
+	if query.Get("failed") != "" {
+		failed, err := parseBoolQuery(r, "failed")
+		if err != nil {
+			return domain.AuditFilter{}, err
+		}
+		filter.Failed = &failed
+	}
+	if raw := strings.TrimSpace(query.Get("from")); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		v.Check(err == nil, "from", "must be an RFC 3339 timestamp")
+		filter.From = from
+	}
+	if raw := strings.TrimSpace(query.Get("to")); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		v.Check(err == nil, "to", "must be an RFC 3339 timestamp")
+		filter.To = to
+	}
+
+	if err := v.Err(); err != nil {
+		return domain.AuditFilter{}, err
+	}
+	return filter, nil
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// BitbucketWorkspace configures webhooks of one Bitbucket Cloud workspace.
+// Users maps Bitbucket nicknames to user IDs; unmapped nicknames are used as user IDs.
+type BitbucketWorkspace struct {
+	Secret string
+	Users  map[string]string
+}
+
+// BitbucketHandler receives Bitbucket Cloud pull request webhooks and drives the PR lifecycle from them
+type BitbucketHandler struct {
+	service    prLifecycleService
+	workspaces map[string]BitbucketWorkspace
+	logger     *zap.Logger
+}
+
+// NewBitbucketHandler creates a Bitbucket webhook handler. Payloads must be
+// signed with the secret of the workspace they come from.
+func NewBitbucketHandler(service prLifecycleService, workspaces map[string]BitbucketWorkspace, logger *zap.Logger) *BitbucketHandler {
+	return &BitbucketHandler{
+		service:    service,
+		workspaces: workspaces,
+		logger:     logger,
+	}
+}
+
+type bitbucketPullRequestEvent struct {
+	PullRequest struct {
+		ID     int    `json:"id"`
+		Title  string `json:"title"`
+		Author struct {
+			Nickname string `json:"nickname"`
+		} `json:"author"`
+	} `json:"pullrequest"`
+	Repository struct {
+		Name      string `json:"name"`
+		FullName  string `json:"full_name"`
+		Workspace struct {
+			Slug string `json:"slug"`
+		} `json:"workspace"`
+	} `json:"repository"`
+}
+
+// bitbucketAction is what a Bitbucket event does to the PR lifecycle
+type bitbucketAction int
+
+const (
+	bitbucketIgnore bitbucketAction = iota
+	bitbucketOpen
+	bitbucketMerge
+)
+
+// mapBitbucketEvent translates a pull request event into a lifecycle action and
+// the PR it applies to. Declined PRs stay open here, so their events are ignored.
+func mapBitbucketEvent(eventKey string, event bitbucketPullRequestEvent, users map[string]string) (bitbucketAction, webhookPR) {
+	pr := webhookPR{
+		ID:         fmt.Sprintf("%s#%d", event.Repository.FullName, event.PullRequest.ID),
+		Name:       event.PullRequest.Title,
+		AuthorID:   webhookUserID(users, event.PullRequest.Author.Nickname),
+		Repository: event.Repository.Name,
+	}
+
+	switch eventKey {
+	case "pullrequest:created":
+		return bitbucketOpen, pr
+	case "pullrequest:fulfilled":
+		return bitbucketMerge, pr
+	default:
+		return bitbucketIgnore, pr
+	}
+}
+
+// Webhook handles POST /integrations/bitbucket/webhook.
+// Created pull requests are created, fulfilled ones are merged; declined
+// pull requests and other events are acknowledged and ignored.
+func (h *BitbucketHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	// The workspace selects the secret, so it is read before the signature is checked
+	var event bitbucketPullRequestEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	workspace, ok := h.workspaces[event.Repository.Workspace.Slug]
+	if !ok || !validHMACSignature([]byte(workspace.Secret), r.Header.Get("X-Hub-Signature"), payload) {
+		middleware.WriteErrorResponse(w, domain.ErrUnauthorized, h.logger)
+		return
+	}
+
+	action, pr := mapBitbucketEvent(r.Header.Get("X-Event-Key"), event, workspace.Users)
+	if action != bitbucketIgnore && (event.PullRequest.ID <= 0 || event.Repository.FullName == "") {
+		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		return
+	}
+
+	switch action {
+	case bitbucketOpen:
+		openWebhookPR(w, r, h.service, pr, h.logger)
+	case bitbucketMerge:
+		mergeWebhookPR(w, r, h.service, pr.ID, h.logger)
+	default:
+		writeWebhookResponse(w, webhookResponse{Result: webhookIgnored}, h.logger)
+	}
+}
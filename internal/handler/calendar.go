@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pr-service/internal/domain"
+)
+
+// icalTimeLayout is the UTC DATE-TIME form of RFC 5545
+const icalTimeLayout = "20060102T150405Z"
+
+// icalLineLimit is the RFC 5545 limit on content line length in octets, excluding CRLF
+const icalLineLimit = 75
+
+// renderReviewCalendar renders the pending reviews of userID as an iCalendar
+// feed with one event at the due time of each review. Event UIDs are stable
+// per review, so calendar clients update entries in place on refresh.
+func renderReviewCalendar(userID string, reviews []domain.ReviewAssignment, now time.Time) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeICalLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//pr-service//Review assignments//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICalText("Reviews of "+userID))
+	for _, review := range reviews {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeICalText(fmt.Sprintf("review/%s/%s@pr-service", review.PullRequestID, review.UserID)))
+		line("DTSTAMP", now.UTC().Format(icalTimeLayout))
+		line("DTSTART", review.DueAt.UTC().Format(icalTimeLayout))
+		line("SUMMARY", escapeICalText(fmt.Sprintf("Review %q (%s)", review.PullRequestName, review.PullRequestID)))
+		description := fmt.Sprintf("Author: %s\nAssigned: %s", review.AuthorID, review.AssignedAt.UTC().Format(time.RFC3339))
+		if review.TeamName != "" {
+			description += "\nTeam: " + review.TeamName
+		}
+		if review.Repository != "" {
+			description += "\nRepository: " + review.Repository
+		}
+		line("DESCRIPTION", escapeICalText(description))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// escapeICalText escapes a TEXT property value
+func escapeICalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// writeICalLine writes a CRLF-terminated content line, folding it at the
+// octet limit without splitting a UTF-8 sequence
+func writeICalLine(b *strings.Builder, s string) {
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines spend one octet on the leading space
+		limit = icalLineLimit - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+// writeCalendar writes an iCalendar feed that calendar clients can subscribe to
+func writeCalendar(w http.ResponseWriter, name, body string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.ics"`, name))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(body))
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pr-service/internal/db"
+)
+
+// poolStatsSource is the database as GET /admin/db/pool sees it
+type poolStatsSource interface {
+	PoolStats() db.PoolStats
+	ReplicaPoolStats() (db.PoolStats, bool)
+}
+
+// DBPoolHandler shows the state of the database connection pools, so their
+// size can be tuned from the load they actually see
+type DBPoolHandler struct {
+	source poolStatsSource
+}
+
+// NewDBPoolHandler creates a new database pool handler
+func NewDBPoolHandler(source poolStatsSource) *DBPoolHandler {
+	return &DBPoolHandler{source: source}
+}
+
+// DBPoolDTO describes a connection pool. Counters and wait times accumulate
+// since the service started; empty acquires are those that found no idle
+// connection and waited for one.
+type DBPoolDTO struct {
+	Name                  string  `json:"name"`
+	AcquiredConns         int32   `json:"acquired_conns"`
+	IdleConns             int32   `json:"idle_conns"`
+	ConstructingConns     int32   `json:"constructing_conns"`
+	TotalConns            int32   `json:"total_conns"`
+	MaxConns              int32   `json:"max_conns"`
+	Saturation            float64 `json:"saturation"`
+	Acquires              int64   `json:"acquires"`
+	EmptyAcquires         int64   `json:"empty_acquires"`
+	CanceledAcquires      int64   `json:"canceled_acquires"`
+	AcquireWaitMs         float64 `json:"acquire_wait_ms"`
+	EmptyAcquireWaitMs    float64 `json:"empty_acquire_wait_ms"`
+	AvgEmptyAcquireWaitMs float64 `json:"avg_empty_acquire_wait_ms"`
+	NewConns              int64   `json:"new_conns"`
+	LifetimeDestroys      int64   `json:"lifetime_destroys"`
+	IdleDestroys          int64   `json:"idle_destroys"`
+}
+
+type dbPoolsResponse struct {
+	Pools []DBPoolDTO `json:"pools"`
+}
+
+// Get handles GET /admin/db/pool: the primary pool, and the read replica pool
+// when one is configured
+func (h *DBPoolHandler) Get(w http.ResponseWriter, r *http.Request) {
+	resp := dbPoolsResponse{Pools: []DBPoolDTO{toDBPoolDTO("primary", h.source.PoolStats())}}
+	if stats, ok := h.source.ReplicaPoolStats(); ok {
+		resp.Pools = append(resp.Pools, toDBPoolDTO("replica", stats))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toDBPoolDTO(name string, stats db.PoolStats) DBPoolDTO {
+	dto := DBPoolDTO{
+		Name:               name,
+		AcquiredConns:      stats.AcquiredConns,
+		IdleConns:          stats.IdleConns,
+		ConstructingConns:  stats.ConstructingConns,
+		TotalConns:         stats.TotalConns,
+		MaxConns:           stats.MaxConns,
+		Saturation:         stats.Saturation(),
+		Acquires:           stats.Acquires,
+		EmptyAcquires:      stats.EmptyAcquires,
+		CanceledAcquires:   stats.CanceledAcquires,
+		AcquireWaitMs:      float64(stats.AcquireWait.Microseconds()) / 1000,
+		EmptyAcquireWaitMs: float64(stats.EmptyAcquireWait.Microseconds()) / 1000,
+		NewConns:           stats.NewConns,
+		LifetimeDestroys:   stats.LifetimeDestroys,
+		IdleDestroys:       stats.IdleDestroys,
+	}
+	if stats.EmptyAcquires > 0 {
+		dto.AvgEmptyAcquireWaitMs = dto.EmptyAcquireWaitMs / float64(stats.EmptyAcquires)
+	}
+	return dto
+}
@@ -1,10 +1,40 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	"os"
 )
 
+// swaggerUIScript starts Swagger UI on the docs page. The page's
+// Content-Security-Policy allows it by its hash.
+const swaggerUIScript = `
+    window.onload = function() {
+      SwaggerUIBundle({
+        url: "/openapi.yml",
+        dom_id: '#swagger-ui',
+        presets: [
+          SwaggerUIBundle.presets.apis,
+          SwaggerUIStandalonePreset
+        ],
+        layout: "BaseLayout"
+      });
+    };
+  `
+
+// SwaggerUIContentSecurityPolicy returns a Content-Security-Policy that lets
+// the docs page load Swagger UI from unpkg.com and run its inline start-up
+// script, and nothing else
+func SwaggerUIContentSecurityPolicy() string {
+	sum := sha256.Sum256([]byte(swaggerUIScript))
+	return fmt.Sprintf("default-src 'none'; script-src https://unpkg.com 'sha256-%s'; "+
+		"style-src https://unpkg.com 'unsafe-inline'; img-src 'self' data: https://unpkg.com; "+
+		"font-src https://unpkg.com; connect-src 'self'; frame-ancestors 'none'",
+		base64.StdEncoding.EncodeToString(sum[:]))
+}
+
 // DocsHandler serves OpenAPI documentation
 type DocsHandler struct {
 	openapiPath string
@@ -41,19 +71,7 @@ func (h *DocsHandler) ServeSwaggerUI(w http.ResponseWriter, r *http.Request) {
   <div id="swagger-ui"></div>
   <script src="https://unpkg.com/swagger-ui-dist@5.10.3/swagger-ui-bundle.js"></script>
   <script src="https://unpkg.com/swagger-ui-dist@5.10.3/swagger-ui-standalone-preset.js"></script>
-  <script>
-    window.onload = function() {
-      SwaggerUIBundle({
-        url: "/openapi.yml",
-        dom_id: '#swagger-ui',
-        presets: [
-          SwaggerUIBundle.presets.apis,
-          SwaggerUIStandalonePreset
-        ],
-        layout: "BaseLayout"
-      });
-    };
-  </script>
+  <script>` + swaggerUIScript + `</script>
 </body>
 </html>`
 
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pr-service/internal/domain"
+)
+
+// ErrorCatalogHandler serves the catalog of API error codes
+type ErrorCatalogHandler struct{}
+
+// NewErrorCatalogHandler creates an error catalog handler
+func NewErrorCatalogHandler() *ErrorCatalogHandler {
+	return &ErrorCatalogHandler{}
+}
+
+type errorCatalogEntry struct {
+	Code        string `json:"code"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+}
+
+type errorCatalogResponse struct {
+	Errors []errorCatalogEntry `json:"errors"`
+}
+
+// List handles GET /errors. It returns every code error responses can carry,
+// with its HTTP status, so clients can handle them exhaustively.
+func (h *ErrorCatalogHandler) List(w http.ResponseWriter, r *http.Request) {
+	resp := errorCatalogResponse{Errors: make([]errorCatalogEntry, len(domain.ErrorRegistry))}
+	for i, info := range domain.ErrorRegistry {
+		resp.Errors[i] = errorCatalogEntry{
+			Code:        string(info.Code),
+			Status:      info.Status,
+			Description: info.Description,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+	"pr-service/internal/event"
+
+	"go.uber.org/zap"
+)
+
+type eventLogService interface {
+	Replay(ctx context.Context, since domain.EventCursor, limit int) ([]domain.OutboxMessage, error)
+}
+
+type eventStream interface {
+	Subscribe(buffer int) (<-chan domain.Event, func())
+}
+
+// streamKeepAlive is how often an idle event stream sends a comment, so that
+// proxies and clients do not time the connection out
+const streamKeepAlive = 15 * time.Second
+
+// EventsHandler serves the persisted domain event log and the live event stream
+type EventsHandler struct {
+	service eventLogService
+	stream  eventStream
+	logger  *zap.Logger
+}
+
+// NewEventsHandler creates a new events handler
+func NewEventsHandler(service eventLogService, stream eventStream, logger *zap.Logger) *EventsHandler {
+	return &EventsHandler{
+		service: service,
+		stream:  stream,
+		logger:  logger,
+	}
+}
+
+// LoggedEventDTO is an event of the log; Event has the outbound webhook body schema
+type LoggedEventDTO struct {
+	Cursor string          `json:"cursor"`
+	Event  json.RawMessage `json:"event"`
+}
+
+type listEventsResponse struct {
+	Events     []LoggedEventDTO `json:"events"`
+	NextCursor string           `json:"next_cursor"`
+}
+
+// ListEvents handles GET /events?since=...&limit=...
+func (h *EventsHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	var since domain.EventCursor
+	if raw := strings.TrimSpace(r.URL.Query().Get("since")); raw != "" {
+		cursor, err := domain.ParseEventCursor(raw)
+		if err != nil {
+			middleware.WriteErrorResponse(w, err, h.logger)
+			return
+		}
+		since = cursor
+	}
+	limit, err := parseIntQuery(r, "limit")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	messages, err := h.service.Replay(r.Context(), since, limit)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	// An empty page keeps the position, so clients can poll with next_cursor
+	resp := listEventsResponse{
+		Events:     make([]LoggedEventDTO, len(messages)),
+		NextCursor: since.String(),
+	}
+	for i, m := range messages {
+		resp.Events[i] = LoggedEventDTO{Cursor: m.Cursor().String(), Event: m.Payload}
+		resp.NextCursor = resp.Events[i].Cursor
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// Stream handles GET /events/stream, sending events as Server-Sent Events as
+// they are committed. Each event has the outbound webhook body schema as data
+// and its type as the SSE event name. Events missed while disconnected are not
+// resent; clients catch up through GET /events.
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// The server write timeout would otherwise cut every stream off
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	// The bus carries the events of every organization
+	org := domain.OrgFromContext(r.Context())
+	events, unsubscribe := h.stream.Subscribe(0)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		h.logger.Warn("Event stream does not support flushing", zap.Error(err))
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if e.OrgID != org {
+				continue
+			}
+			data, err := event.Marshal(e)
+			if err != nil {
+				h.logger.Error("Failed to encode streamed event", zap.String("event", string(e.Type)), zap.Error(err))
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const contentTypeNDJSON = "application/x-ndjson"
+
+// NDJSON record types; a dump starts with one header record
+const (
+	recordHeader      = "header"
+	recordTeam        = "team"
+	recordUser        = "user"
+	recordMembership  = "membership"
+	recordPullRequest = "pull_request"
+	recordReviewer    = "reviewer"
+)
+
+type exportService interface {
+	Export(ctx context.Context) (domain.DataExport, error)
+	Import(ctx context.Context, data domain.DataExport) error
+}
+
+// ExportHandler serves full data dumps and restores them
+type ExportHandler struct {
+	service exportService
+	logger  *zap.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(service exportService, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type ExportHeaderDTO struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+type ExportedTeamDTO struct {
+	TeamName            string                  `json:"team_name"`
+	ParentTeamName      string                  `json:"parent_team_name,omitempty"`
+	NotificationChannel *NotificationChannelDTO `json:"notification_channel,omitempty"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UpdatedAt           time.Time               `json:"updated_at"`
+	DeletedAt           *time.Time              `json:"deleted_at,omitempty"`
+}
+
+type ExportedUserDTO struct {
+	UserID     string     `json:"user_id"`
+	Username   string     `json:"username"`
+	IsActive   bool       `json:"is_active"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+type ExportedMembershipDTO struct {
+	TeamName string    `json:"team_name"`
+	UserID   string    `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type ExportedPullRequestDTO struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	TeamName        string     `json:"team_name,omitempty"`
+	Repository      string     `json:"repository,omitempty"`
+	TicketKey       string     `json:"ticket_key,omitempty"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"created_at"`
+	MergedAt        *time.Time `json:"merged_at,omitempty"`
+}
+
+type ExportedReviewerDTO struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	UserID          string     `json:"user_id"`
+	AssignedAt      time.Time  `json:"assigned_at"`
+	FirstActionAt   *time.Time `json:"first_action_at,omitempty"`
+	StaleNotifiedAt *time.Time `json:"stale_notified_at,omitempty"`
+	EscalatedAt     *time.Time `json:"escalated_at,omitempty"`
+}
+
+// DataExportDTO is the JSON form of a dump. Timestamps keep their full
+// precision so a restored instance matches the source.
+type DataExportDTO struct {
+	ExportHeaderDTO
+	Teams        []ExportedTeamDTO        `json:"teams"`
+	Users        []ExportedUserDTO        `json:"users"`
+	Memberships  []ExportedMembershipDTO  `json:"memberships"`
+	PullRequests []ExportedPullRequestDTO `json:"pull_requests"`
+	Reviewers    []ExportedReviewerDTO    `json:"reviewers"`
+}
+
+// exportRecord is one NDJSON line: {"type": "team", "data": {...}}
+type exportRecord struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type importResponse struct {
+	Imported map[string]int `json:"imported"`
+}
+
+// Export handles GET /admin/export. The dump is one JSON document, or with
+// format=ndjson or Accept: application/x-ndjson one typed record per line.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ndjson, err := wantsNDJSON(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	data, err := h.service.Export(r.Context())
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	dump := mapExportToDTO(data)
+
+	w.Header().Add("Vary", "Accept")
+	if !ndjson {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(dump); err != nil {
+			h.logger.Error("failed to encode export", zap.Error(err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeNDJSON)
+	w.WriteHeader(http.StatusOK)
+	if err := writeNDJSON(w, dump); err != nil {
+		h.logger.Error("failed to encode export", zap.Error(err))
+	}
+}
+
+// Import handles POST /admin/import with a dump in either export format,
+// selected by Content-Type. The instance must not have any data yet.
+func (h *ExportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var (
+		dump DataExportDTO
+		err  error
+	)
+	if mediaType == contentTypeNDJSON {
+		dump, err = readNDJSON(r)
+	} else if decodeErr := json.NewDecoder(r.Body).Decode(&dump); decodeErr != nil {
+		err = errInvalidBody
+	}
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	data := mapDTOToExport(dump)
+	if err := h.service.Import(r.Context(), data); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := importResponse{Imported: map[string]int{
+		"teams":         len(data.Teams),
+		"users":         len(data.Users),
+		"memberships":   len(data.Memberships),
+		"pull_requests": len(data.PullRequests),
+		"reviewers":     len(data.Reviewers),
+	}}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// wantsNDJSON reads the export format from the format query parameter,
+// falling back to the Accept header
+func wantsNDJSON(r *http.Request) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))) {
+	case "json":
+		return false, nil
+	case "ndjson":
+		return true, nil
+	case "":
+	default:
+		return false, domain.NewValidationError("format", "must be one of json, ndjson")
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && params["q"] != "0" && mediaType == contentTypeNDJSON {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func writeNDJSON(w http.ResponseWriter, dump DataExportDTO) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	write := func(recordType string, data any) error {
+		return encoder.Encode(struct {
+			Type string `json:"type"`
+			Data any    `json:"data"`
+		}{recordType, data})
+	}
+
+	if err := write(recordHeader, dump.ExportHeaderDTO); err != nil {
+		return err
+	}
+	for _, team := range dump.Teams {
+		if err := write(recordTeam, team); err != nil {
+			return err
+		}
+	}
+	for _, user := range dump.Users {
+		if err := write(recordUser, user); err != nil {
+			return err
+		}
+	}
+	for _, m := range dump.Memberships {
+		if err := write(recordMembership, m); err != nil {
+			return err
+		}
+	}
+	for _, pr := range dump.PullRequests {
+		if err := write(recordPullRequest, pr); err != nil {
+			return err
+		}
+	}
+	for _, rv := range dump.Reviewers {
+		if err := write(recordReviewer, rv); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// readNDJSON collects typed records into a dump; records may come in any order
+func readNDJSON(r *http.Request) (DataExportDTO, error) {
+	var dump DataExportDTO
+	decoder := json.NewDecoder(r.Body)
+	for line := 1; decoder.More(); line++ {
+		field := fmt.Sprintf("records[%d]", line)
+
+		var record exportRecord
+		if err := decoder.Decode(&record); err != nil {
+			return DataExportDTO{}, domain.NewValidationError(field, "must be a JSON object with type and data")
+		}
+
+		var err error
+		switch record.Type {
+		case recordHeader:
+			err = json.Unmarshal(record.Data, &dump.ExportHeaderDTO)
+		case recordTeam:
+			err = appendRecord(record.Data, &dump.Teams)
+		case recordUser:
+			err = appendRecord(record.Data, &dump.Users)
+		case recordMembership:
+			err = appendRecord(record.Data, &dump.Memberships)
+		case recordPullRequest:
+			err = appendRecord(record.Data, &dump.PullRequests)
+		case recordReviewer:
+			err = appendRecord(record.Data, &dump.Reviewers)
+		default:
+			return DataExportDTO{}, domain.NewValidationError(field+".type", "must be one of header, team, user, membership, pull_request, reviewer")
+		}
+		if err != nil {
+			return DataExportDTO{}, domain.NewValidationError(field+".data", "must match the record type")
+		}
+	}
+	return dump, nil
+}
+
+func appendRecord[T any](data json.RawMessage, records *[]T) error {
+	var record T
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	*records = append(*records, record)
+	return nil
+}
+
+func mapExportToDTO(data domain.DataExport) DataExportDTO {
+	dump := DataExportDTO{
+		ExportHeaderDTO: ExportHeaderDTO{Version: data.Version, ExportedAt: data.ExportedAt},
+		Teams:           make([]ExportedTeamDTO, len(data.Teams)),
+		Users:           make([]ExportedUserDTO, len(data.Users)),
+		Memberships:     make([]ExportedMembershipDTO, len(data.Memberships)),
+		PullRequests:    make([]ExportedPullRequestDTO, len(data.PullRequests)),
+		Reviewers:       make([]ExportedReviewerDTO, len(data.Reviewers)),
+	}
+	for i, team := range data.Teams {
+		dump.Teams[i] = ExportedTeamDTO{
+			TeamName:       team.TeamName,
+			ParentTeamName: team.ParentTeamName,
+			CreatedAt:      team.CreatedAt,
+			UpdatedAt:      team.UpdatedAt,
+			DeletedAt:      team.DeletedAt,
+		}
+		if team.NotificationChannelType != "" {
+			dump.Teams[i].NotificationChannel = &NotificationChannelDTO{
+				Type:       string(team.NotificationChannelType),
+				WebhookURL: team.NotificationChannelURL,
+			}
+		}
+	}
+	for i, user := range data.Users {
+		dump.Users[i] = ExportedUserDTO{
+			UserID:     user.UserID,
+			Username:   user.Username,
+			IsActive:   user.IsActive,
+			Role:       string(user.Role),
+			CreatedAt:  user.CreatedAt,
+			UpdatedAt:  user.UpdatedAt,
+			DeletedAt:  user.DeletedAt,
+			LastSeenAt: user.LastSeenAt,
+		}
+	}
+	for i, m := range data.Memberships {
+		dump.Memberships[i] = ExportedMembershipDTO(m)
+	}
+	for i, pr := range data.PullRequests {
+		dump.PullRequests[i] = ExportedPullRequestDTO{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			TeamName:        pr.TeamName,
+			Repository:      pr.Repository,
+			TicketKey:       pr.TicketKey,
+			Status:          string(pr.Status),
+			CreatedAt:       pr.CreatedAt,
+			MergedAt:        pr.MergedAt,
+		}
+	}
+	for i, rv := range data.Reviewers {
+		dump.Reviewers[i] = ExportedReviewerDTO(rv)
+	}
+	return dump
+}
+
+func mapDTOToExport(dump DataExportDTO) domain.DataExport {
+	data := domain.DataExport{
+		Version:      dump.Version,
+		ExportedAt:   dump.ExportedAt,
+		Teams:        make([]domain.ExportedTeam, len(dump.Teams)),
+		Users:        make([]domain.ExportedUser, len(dump.Users)),
+		Memberships:  make([]domain.ExportedMembership, len(dump.Memberships)),
+		PullRequests: make([]domain.ExportedPullRequest, len(dump.PullRequests)),
+		Reviewers:    make([]domain.ExportedReviewer, len(dump.Reviewers)),
+	}
+	for i, team := range dump.Teams {
+		data.Teams[i] = domain.ExportedTeam{
+			TeamName:       strings.TrimSpace(team.TeamName),
+			ParentTeamName: strings.TrimSpace(team.ParentTeamName),
+			CreatedAt:      team.CreatedAt,
+			UpdatedAt:      team.UpdatedAt,
+			DeletedAt:      team.DeletedAt,
+		}
+		if ch := team.NotificationChannel; ch != nil {
+			data.Teams[i].NotificationChannelType = domain.NotificationChannelType(strings.ToLower(strings.TrimSpace(ch.Type)))
+			data.Teams[i].NotificationChannelURL = strings.TrimSpace(ch.WebhookURL)
+		}
+	}
+	for i, user := range dump.Users {
+		data.Users[i] = domain.ExportedUser{
+			UserID:     strings.TrimSpace(user.UserID),
+			Username:   user.Username,
+			IsActive:   user.IsActive,
+			Role:       domain.UserRole(user.Role),
+			CreatedAt:  user.CreatedAt,
+			UpdatedAt:  user.UpdatedAt,
+			DeletedAt:  user.DeletedAt,
+			LastSeenAt: user.LastSeenAt,
+		}
+	}
+	for i, m := range dump.Memberships {
+		data.Memberships[i] = domain.ExportedMembership(m)
+	}
+	for i, pr := range dump.PullRequests {
+		data.PullRequests[i] = domain.ExportedPullRequest{
+			PullRequestID:   strings.TrimSpace(pr.PullRequestID),
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			TeamName:        pr.TeamName,
+			Repository:      pr.Repository,
+			TicketKey:       pr.TicketKey,
+			Status:          domain.PRStatus(pr.Status),
+			CreatedAt:       pr.CreatedAt,
+			MergedAt:        pr.MergedAt,
+		}
+	}
+	for i, rv := range dump.Reviewers {
+		data.Reviewers[i] = domain.ExportedReviewer(rv)
+	}
+	return data
+}
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+	"pr-service/internal/jsonpath"
+
+	"go.uber.org/zap"
+)
+
+// Default headers of generic webhook adapters
+const (
+	DefaultGenericTokenHeader     = "X-Webhook-Token"
+	DefaultGenericSignatureHeader = "X-Webhook-Signature-256"
+)
+
+// GenericAdapter maps the JSON payloads of an in-house system onto the PR lifecycle.
+//
+// Requests must carry Token in TokenHeader, a sha256=<hex> HMAC of the payload
+// keyed with Secret in SignatureHeader, or both when both are set.
+//
+// Action is a path to the value that selects what happens: values listed in
+// CreateOn create the PR, values in MergeOn merge it, anything else is ignored.
+// Fields maps PR fields (pull_request_id, pull_request_name, author_id,
+// team_name, repository, ticket_key) to mapping rules. A rule starting with "$"
+// is a path; otherwise it is a template in which each {$path} is replaced by
+// the value at the path, such as "{$.repo.name}#{$.change.number}". Users maps
+// the author values to user IDs; unmapped values are used as user IDs as is.
+type GenericAdapter struct {
+	Token           string
+	TokenHeader     string
+	Secret          string
+	SignatureHeader string
+	Action          string
+	CreateOn        []string
+	MergeOn         []string
+	Fields          map[string]string
+	Users           map[string]string
+}
+
+// Fields a generic adapter can map
+const (
+	genericFieldID         = "pull_request_id"
+	genericFieldName       = "pull_request_name"
+	genericFieldAuthorID   = "author_id"
+	genericFieldTeamName   = "team_name"
+	genericFieldRepository = "repository"
+	genericFieldTicketKey  = "ticket_key"
+)
+
+// genericFields lists the mappable fields, the PR ID first
+var genericFields = []string{
+	genericFieldID, genericFieldName, genericFieldAuthorID,
+	genericFieldTeamName, genericFieldRepository, genericFieldTicketKey,
+}
+
+// genericRule is a compiled mapping rule: literal text interleaved with paths
+type genericRule struct {
+	literals []string
+	paths    []jsonpath.Path
+}
+
+// compileGenericRule compiles a path or a template with {$path} placeholders
+func compileGenericRule(rule string) (genericRule, error) {
+	rule = strings.TrimSpace(rule)
+	if strings.HasPrefix(rule, "$") {
+		path, err := jsonpath.Compile(rule)
+		if err != nil {
+			return genericRule{}, err
+		}
+		return genericRule{literals: []string{"", ""}, paths: []jsonpath.Path{path}}, nil
+	}
+
+	var compiled genericRule
+	rest := rule
+	for {
+		start := strings.Index(rest, "{$")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return genericRule{}, fmt.Errorf("unterminated placeholder in %q", rule)
+		}
+		path, err := jsonpath.Compile(rest[start+1 : start+end])
+		if err != nil {
+			return genericRule{}, err
+		}
+		compiled.literals = append(compiled.literals, rest[:start])
+		compiled.paths = append(compiled.paths, path)
+		rest = rest[start+end+1:]
+	}
+	compiled.literals = append(compiled.literals, rest)
+	return compiled, nil
+}
+
+// eval renders the rule against doc; ok is false when a path holds no scalar
+func (g genericRule) eval(doc any) (string, bool) {
+	var b strings.Builder
+	for i, path := range g.paths {
+		b.WriteString(g.literals[i])
+		value, ok := path.LookupString(doc)
+		if !ok {
+			return "", false
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(g.literals[len(g.literals)-1])
+	return b.String(), true
+}
+
+type genericAdapter struct {
+	token           []byte
+	tokenHeader     string
+	secret          []byte
+	signatureHeader string
+	action          jsonpath.Path
+	createOn        []string
+	mergeOn         []string
+	fields          map[string]genericRule
+	users           map[string]string
+}
+
+// GenericWebhookHandler receives webhooks of in-house systems through configured adapters
+type GenericWebhookHandler struct {
+	service  prLifecycleService
+	adapters map[string]genericAdapter
+	logger   *zap.Logger
+}
+
+// NewGenericWebhookHandler creates a generic webhook handler with adapters keyed
+// by name. It fails on an adapter without credentials or with an invalid rule.
+func NewGenericWebhookHandler(service prLifecycleService, adapters map[string]GenericAdapter, logger *zap.Logger) (*GenericWebhookHandler, error) {
+	h := &GenericWebhookHandler{
+		service:  service,
+		adapters: make(map[string]genericAdapter, len(adapters)),
+		logger:   logger,
+	}
+	for name, cfg := range adapters {
+		adapter, err := compileGenericAdapter(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("generic webhook adapter %q: %w", name, err)
+		}
+		h.adapters[name] = adapter
+	}
+	return h, nil
+}
+
+func compileGenericAdapter(cfg GenericAdapter) (genericAdapter, error) {
+	if cfg.Token == "" && cfg.Secret == "" {
+		return genericAdapter{}, errors.New("a token or a secret is required")
+	}
+	action, err := jsonpath.Compile(cfg.Action)
+	if err != nil {
+		return genericAdapter{}, fmt.Errorf("action: %w", err)
+	}
+	if len(cfg.CreateOn) == 0 && len(cfg.MergeOn) == 0 {
+		return genericAdapter{}, errors.New("no create_on or merge_on action values")
+	}
+	for _, value := range cfg.CreateOn {
+		if slices.Contains(cfg.MergeOn, value) {
+			return genericAdapter{}, fmt.Errorf("action value %q both creates and merges", value)
+		}
+	}
+	if cfg.Fields[genericFieldID] == "" {
+		return genericAdapter{}, fmt.Errorf("field %s is required", genericFieldID)
+	}
+	if len(cfg.CreateOn) > 0 && (cfg.Fields[genericFieldName] == "" || cfg.Fields[genericFieldAuthorID] == "") {
+		return genericAdapter{}, fmt.Errorf("fields %s and %s are required to create PRs", genericFieldName, genericFieldAuthorID)
+	}
+
+	adapter := genericAdapter{
+		token:           []byte(cfg.Token),
+		tokenHeader:     cfg.TokenHeader,
+		secret:          []byte(cfg.Secret),
+		signatureHeader: cfg.SignatureHeader,
+		action:          action,
+		createOn:        cfg.CreateOn,
+		mergeOn:         cfg.MergeOn,
+		fields:          make(map[string]genericRule, len(cfg.Fields)),
+		users:           cfg.Users,
+	}
+	if adapter.tokenHeader == "" {
+		adapter.tokenHeader = DefaultGenericTokenHeader
+	}
+	if adapter.signatureHeader == "" {
+		adapter.signatureHeader = DefaultGenericSignatureHeader
+	}
+	for field, rule := range cfg.Fields {
+		if !slices.Contains(genericFields, field) {
+			return genericAdapter{}, fmt.Errorf("unknown field %q", field)
+		}
+		compiled, err := compileGenericRule(rule)
+		if err != nil {
+			return genericAdapter{}, fmt.Errorf("field %s: %w", field, err)
+		}
+		adapter.fields[field] = compiled
+	}
+	return adapter, nil
+}
+
+// authorized checks every credential the adapter is configured with
+func (a genericAdapter) authorized(r *http.Request, payload []byte) bool {
+	if len(a.token) > 0 && subtle.ConstantTimeCompare([]byte(r.Header.Get(a.tokenHeader)), a.token) != 1 {
+		return false
+	}
+	if len(a.secret) > 0 && !validHMACSignature(a.secret, r.Header.Get(a.signatureHeader), payload) {
+		return false
+	}
+	return true
+}
+
+// field renders a mapped field; unmapped fields are empty
+func (a genericAdapter) field(name string, doc any) (string, bool) {
+	rule, ok := a.fields[name]
+	if !ok {
+		return "", true
+	}
+	value, ok := rule.eval(doc)
+	return strings.TrimSpace(value), ok
+}
+
+// Webhook handles POST /integrations/generic/{name}/webhook
+func (h *GenericWebhookHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	adapter, ok := h.adapters[r.PathValue("name")]
+	if !ok {
+		middleware.WriteErrorResponse(w, domain.ErrNotFound, h.logger)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	if !adapter.authorized(r, payload) {
+		middleware.WriteErrorResponse(w, domain.ErrUnauthorized, h.logger)
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	action, _ := adapter.action.LookupString(doc)
+	create, merge := slices.Contains(adapter.createOn, action), slices.Contains(adapter.mergeOn, action)
+	if !create && !merge {
+		writeWebhookResponse(w, webhookResponse{Result: webhookIgnored}, h.logger)
+		return
+	}
+
+	// Merges only need the PR ID, so their payloads may omit the other fields
+	needed := genericFields
+	if merge {
+		needed = needed[:1]
+	}
+	values := make(map[string]string, len(needed))
+	for _, name := range needed {
+		value, ok := adapter.field(name, doc)
+		if !ok {
+			h.logger.Info("Generic webhook payload does not match the mapping",
+				zap.String("adapter", r.PathValue("name")),
+				zap.String("field", name),
+			)
+			middleware.WriteErrorResponse(w, domain.NewValidationError(name, "is not found in the payload"), h.logger)
+			return
+		}
+		values[name] = value
+	}
+	if values[genericFieldID] == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError(genericFieldID, "must not be empty"), h.logger)
+		return
+	}
+
+	if merge {
+		mergeWebhookPR(w, r, h.service, values[genericFieldID], h.logger)
+		return
+	}
+	openWebhookPR(w, r, h.service, webhookPR{
+		ID:         values[genericFieldID],
+		Name:       values[genericFieldName],
+		AuthorID:   webhookUserID(adapter.users, values[genericFieldAuthorID]),
+		TeamName:   values[genericFieldTeamName],
+		Repository: values[genericFieldRepository],
+		TicketKey:  values[genericFieldTicketKey],
+	}, h.logger)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// GitHubHandler receives GitHub webhooks and drives the PR lifecycle from them
+type GitHubHandler struct {
+	service prLifecycleService
+	secret  []byte
+	users   map[string]string
+	logger  *zap.Logger
+}
+
+// NewGitHubHandler creates a GitHub webhook handler. Payloads must be signed
+// with secret; users maps GitHub logins to user IDs, and unmapped logins are
+// used as user IDs as is.
+func NewGitHubHandler(service prLifecycleService, secret string, users map[string]string, logger *zap.Logger) *GitHubHandler {
+	return &GitHubHandler{
+		service: service,
+		secret:  []byte(secret),
+		users:   users,
+		logger:  logger,
+	}
+}
+
+type gitHubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// Webhook handles POST /integrations/github/webhook.
+// Opened and reopened pull requests are created, merged ones are merged;
+// other events and actions are acknowledged and ignored.
+func (h *GitHubHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	if !validHMACSignature(h.secret, r.Header.Get("X-Hub-Signature-256"), payload) {
+		middleware.WriteErrorResponse(w, domain.ErrUnauthorized, h.logger)
+		return
+	}
+
+	if r.Header.Get("X-GitHub-Event") != "pull_request" {
+		writeWebhookResponse(w, webhookResponse{Result: webhookIgnored}, h.logger)
+		return
+	}
+
+	var event gitHubPullRequestEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.Number <= 0 || event.Repository.FullName == "" {
+		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		return
+	}
+	prID := fmt.Sprintf("%s#%d", event.Repository.FullName, event.Number)
+
+	switch {
+	case event.Action == "opened" || event.Action == "reopened":
+		openWebhookPR(w, r, h.service, webhookPR{
+			ID:         prID,
+			Name:       event.PullRequest.Title,
+			AuthorID:   webhookUserID(h.users, event.PullRequest.User.Login),
+			Repository: event.Repository.Name,
+		}, h.logger)
+	case event.Action == "closed" && event.PullRequest.Merged:
+		mergeWebhookPR(w, r, h.service, prID, h.logger)
+	default:
+		// PRs closed without merging stay open here until merged or reopened
+		writeWebhookResponse(w, webhookResponse{Result: webhookIgnored}, h.logger)
+	}
+}
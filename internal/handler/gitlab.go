@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// GitLabHandler receives GitLab merge request webhooks and drives the PR lifecycle from them
+type GitLabHandler struct {
+	service prLifecycleService
+	token   []byte
+	users   map[string]string
+	logger  *zap.Logger
+}
+
+// NewGitLabHandler creates a GitLab webhook handler. Requests must carry token
+// in X-Gitlab-Token; users maps GitLab usernames to user IDs, and unmapped
+// usernames are used as user IDs as is.
+func NewGitLabHandler(service prLifecycleService, token string, users map[string]string, logger *zap.Logger) *GitLabHandler {
+	return &GitLabHandler{
+		service: service,
+		token:   []byte(token),
+		users:   users,
+		logger:  logger,
+	}
+}
+
+type gitLabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		Name              string `json:"name"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// Webhook handles POST /integrations/gitlab/webhook.
+// Opened and reopened merge requests are created, merged ones are merged;
+// other events and actions are acknowledged and ignored.
+func (h *GitLabHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Gitlab-Token")), h.token) != 1 {
+		middleware.WriteErrorResponse(w, domain.ErrUnauthorized, h.logger)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	var event gitLabMergeRequestEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	if event.ObjectKind != "merge_request" {
+		writeWebhookResponse(w, webhookResponse{Result: webhookIgnored}, h.logger)
+		return
+	}
+	if event.ObjectAttributes.IID <= 0 || event.Project.PathWithNamespace == "" {
+		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		return
+	}
+	prID := fmt.Sprintf("%s!%d", event.Project.PathWithNamespace, event.ObjectAttributes.IID)
+
+	switch event.ObjectAttributes.Action {
+	case "open", "reopen":
+		openWebhookPR(w, r, h.service, webhookPR{
+			ID:         prID,
+			Name:       event.ObjectAttributes.Title,
+			AuthorID:   webhookUserID(h.users, event.User.Username),
+			Repository: event.Project.Name,
+		}, h.logger)
+	case "merge":
+		mergeWebhookPR(w, r, h.service, prID, h.logger)
+	default:
+		// MRs closed without merging stay open here until merged or reopened
+		writeWebhookResponse(w, webhookResponse{Result: webhookIgnored}, h.logger)
+	}
+}
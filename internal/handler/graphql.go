@@ -0,0 +1,431 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"pr-service/internal/domain"
+	gql "pr-service/internal/graphql"
+	"pr-service/internal/pagination"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"go.uber.org/zap"
+)
+
+type graphTeamService interface {
+	GetTeam(ctx context.Context, teamName string, flatten bool) (domain.Team, error)
+	ListTeams(ctx context.Context, limit, offset int) ([]domain.TeamSummary, int, error)
+}
+
+type graphUserService interface {
+	GetUser(ctx context.Context, userID string) (domain.User, error)
+	GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	GetPendingReviews(ctx context.Context, userID string) ([]domain.ReviewAssignment, error)
+}
+
+type graphPRService interface {
+	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
+	ListPRs(ctx context.Context, filter domain.PRFilter, page pagination.Page) (pagination.Result[domain.PullRequest], error)
+	GetWorkload(ctx context.Context) ([]domain.ReviewerWorkload, error)
+	GetOpenPRAging(ctx context.Context) ([]domain.PRAging, time.Time, error)
+}
+
+// maxGraphQLBytes limits the size of a GraphQL request body
+const maxGraphQLBytes = 1 << 20
+
+// GraphQLHandler serves a read-only GraphQL schema over teams, users, PRs and
+// stats, so that dashboards can fetch nested data in one request
+type GraphQLHandler struct {
+	teams  graphTeamService
+	users  graphUserService
+	prs    graphPRService
+	schema *gql.Schema
+	logger *zap.Logger
+}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(teams graphTeamService, users graphUserService, prs graphPRService, logger *zap.Logger) *GraphQLHandler {
+	h := &GraphQLHandler{
+		teams:  teams,
+		users:  users,
+		prs:    prs,
+		logger: logger,
+	}
+	schema, err := gql.NewSchema(h.queryType(), 0)
+	if err != nil {
+		panic("invalid GraphQL schema: " + err.Error())
+	}
+	h.schema = schema
+	return h
+}
+
+// Query handles POST /graphql
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req gql.Request
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBytes))
+	if err := decoder.Decode(&req); err != nil || req.Query == "" {
+		writeGraphQLResponse(w, http.StatusBadRequest, &gql.Response{
+			Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError("request body must be a JSON object with a query")},
+		})
+		return
+	}
+
+	resp := h.schema.Execute(r.Context(), req)
+	status := http.StatusOK
+	if resp.Rejected() {
+		status = http.StatusBadRequest
+	}
+	writeGraphQLResponse(w, status, resp)
+}
+
+// Schema handles GET /graphql/schema, describing the schema in SDL
+func (h *GraphQLHandler) Schema(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(h.schema.SDL()))
+}
+
+func writeGraphQLResponse(w http.ResponseWriter, status int, resp *gql.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// teamNode is a team known by name, loaded in full only when fields beyond
+// the name are selected
+type teamNode struct {
+	name string
+	team *domain.Team
+}
+
+func (h *GraphQLHandler) queryType() *graphql.Object {
+	var team, user, pr, review, workload, aging *graphql.Object
+
+	team = graphql.NewObject(graphql.ObjectConfig{Name: "Team", Description: "A team and its members", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"team_name": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(*teamNode).name, nil
+			}},
+			"parent_team_name": {Type: graphql.String, Resolve: h.teamField(func(t domain.Team) any {
+				return optional(t.ParentTeamName)
+			})},
+			"members":    {Type: listOf(user), Resolve: h.teamField(func(t domain.Team) any { return t.Members })},
+			"created_at": {Type: graphql.NewNonNull(graphql.DateTime), Resolve: h.teamField(func(t domain.Team) any { return t.CreatedAt })},
+		}
+	})})
+
+	user = graphql.NewObject(graphql.ObjectConfig{Name: "User", Description: "A team member", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"user_id":      {Type: graphql.NewNonNull(graphql.ID), Resolve: userField(func(u domain.User) any { return u.UserID })},
+			"username":     {Type: graphql.NewNonNull(graphql.String), Resolve: userField(func(u domain.User) any { return u.Username })},
+			"team_name":    {Type: graphql.String, Resolve: userField(func(u domain.User) any { return optional(u.TeamName) })},
+			"is_active":    {Type: graphql.NewNonNull(graphql.Boolean), Resolve: userField(func(u domain.User) any { return u.IsActive })},
+			"role":         {Type: graphql.NewNonNull(graphql.String), Resolve: userField(func(u domain.User) any { return string(u.Role) })},
+			"last_seen_at": {Type: graphql.DateTime, Resolve: userField(func(u domain.User) any { return u.LastSeenAt })},
+			"reviews": {
+				Description: "PRs the user is assigned to review", Type: listOf(pr),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					prs, err := h.users.GetPRsByReviewer(p.Context, p.Source.(domain.User).UserID)
+					return prs, h.resolveError(err)
+				},
+			},
+			"pending_reviews": {
+				Description: "Open reviews waiting for the user's first action, oldest first", Type: listOf(review),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					reviews, err := h.users.GetPendingReviews(p.Context, p.Source.(domain.User).UserID)
+					return reviews, h.resolveError(err)
+				},
+			},
+		}
+	})})
+
+	pr = graphql.NewObject(graphql.ObjectConfig{Name: "PullRequest", Description: "A pull request and its reviewers", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"pull_request_id":   {Type: graphql.NewNonNull(graphql.ID), Resolve: prField(func(p domain.PullRequest) any { return p.PullRequestID })},
+			"pull_request_name": {Type: graphql.NewNonNull(graphql.String), Resolve: prField(func(p domain.PullRequest) any { return p.PullRequestName })},
+			"author_id":         {Type: graphql.NewNonNull(graphql.ID), Resolve: prField(func(p domain.PullRequest) any { return p.AuthorID })},
+			"author": {Type: user, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.user(p.Context, p.Source.(domain.PullRequest).AuthorID)
+			}},
+			"team_name":  {Type: graphql.String, Resolve: prField(func(p domain.PullRequest) any { return optional(p.TeamName) })},
+			"repository": {Type: graphql.String, Resolve: prField(func(p domain.PullRequest) any { return optional(p.Repository) })},
+			"ticket_key": {Type: graphql.String, Resolve: prField(func(p domain.PullRequest) any { return optional(p.TicketKey) })},
+			"status":     {Type: graphql.NewNonNull(graphql.String), Resolve: prField(func(p domain.PullRequest) any { return string(p.Status) })},
+			"created_at": {Type: graphql.NewNonNull(graphql.DateTime), Resolve: prField(func(p domain.PullRequest) any { return p.CreatedAt })},
+			"merged_at":  {Type: graphql.DateTime, Resolve: prField(func(p domain.PullRequest) any { return p.MergedAt })},
+			"assigned_reviewers": {
+				Type: listOf(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					full, err := h.withReviewers(p.Context, p.Source.(domain.PullRequest))
+					return full.AssignedReviewers, err
+				},
+			},
+			"reviewers": {
+				Type: listOf(user),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					full, err := h.withReviewers(p.Context, p.Source.(domain.PullRequest))
+					if err != nil {
+						return nil, err
+					}
+					reviewers := make([]domain.User, 0, len(full.AssignedReviewers))
+					for _, id := range full.AssignedReviewers {
+						u, err := h.user(p.Context, id)
+						if err != nil {
+							return nil, err
+						}
+						if u != nil {
+							reviewers = append(reviewers, u.(domain.User))
+						}
+					}
+					return reviewers, nil
+				},
+			},
+		}
+	})})
+
+	review = graphql.NewObject(graphql.ObjectConfig{Name: "Review", Description: "An open review waiting for the reviewer's first action", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"pull_request_id":   {Type: graphql.NewNonNull(graphql.ID), Resolve: reviewField(func(r domain.ReviewAssignment) any { return r.PullRequestID })},
+			"pull_request_name": {Type: graphql.NewNonNull(graphql.String), Resolve: reviewField(func(r domain.ReviewAssignment) any { return r.PullRequestName })},
+			"author_id":         {Type: graphql.NewNonNull(graphql.ID), Resolve: reviewField(func(r domain.ReviewAssignment) any { return r.AuthorID })},
+			"team_name":         {Type: graphql.String, Resolve: reviewField(func(r domain.ReviewAssignment) any { return optional(r.TeamName) })},
+			"repository":        {Type: graphql.String, Resolve: reviewField(func(r domain.ReviewAssignment) any { return optional(r.Repository) })},
+			"assigned_at":       {Type: graphql.NewNonNull(graphql.DateTime), Resolve: reviewField(func(r domain.ReviewAssignment) any { return r.AssignedAt })},
+			"due_at":            {Type: graphql.NewNonNull(graphql.DateTime), Resolve: reviewField(func(r domain.ReviewAssignment) any { return r.DueAt })},
+			"pull_request": {Type: pr, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.pullRequest(p.Context, p.Source.(domain.ReviewAssignment).PullRequestID)
+			}},
+		}
+	})})
+
+	workload = graphql.NewObject(graphql.ObjectConfig{Name: "ReviewerWorkload", Description: "Open reviews of an active reviewer against their capacity", Fields: graphql.FieldsThunk(func() graphql.Fields {
+		return graphql.Fields{
+			"user_id":      {Type: graphql.NewNonNull(graphql.ID), Resolve: workloadField(func(w domain.ReviewerWorkload) any { return w.UserID })},
+			"username":     {Type: graphql.NewNonNull(graphql.String), Resolve: workloadField(func(w domain.ReviewerWorkload) any { return w.Username })},
+			"open_reviews": {Type: graphql.NewNonNull(graphql.Int), Resolve: workloadField(func(w domain.ReviewerWorkload) any { return w.OpenReviews })},
+			"capacity": {Type: graphql.Int, Resolve: workloadField(func(w domain.ReviewerWorkload) any {
+				if w.Capacity <= 0 {
+					return nil
+				}
+				return w.Capacity
+			})},
+			"utilization": {Type: graphql.Float, Resolve: workloadField(func(w domain.ReviewerWorkload) any {
+				if w.Capacity <= 0 {
+					return nil
+				}
+				return w.Utilization
+			})},
+			"user": {Type: user, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.user(p.Context, p.Source.(domain.ReviewerWorkload).UserID)
+			}},
+		}
+	})})
+
+	aging = graphql.NewObject(graphql.ObjectConfig{Name: "PRAging", Description: "Open PRs of a team by age", Fields: graphql.Fields{
+		"team_name":           {Type: graphql.NewNonNull(graphql.String), Resolve: agingField(func(a domain.PRAging) any { return a.TeamName })},
+		"under_one_day":       {Type: graphql.NewNonNull(graphql.Int), Resolve: agingField(func(a domain.PRAging) any { return a.UnderOneDay })},
+		"one_to_three_days":   {Type: graphql.NewNonNull(graphql.Int), Resolve: agingField(func(a domain.PRAging) any { return a.OneToThreeDays })},
+		"three_to_seven_days": {Type: graphql.NewNonNull(graphql.Int), Resolve: agingField(func(a domain.PRAging) any { return a.ThreeToSevenDays })},
+		"over_seven_days":     {Type: graphql.NewNonNull(graphql.Int), Resolve: agingField(func(a domain.PRAging) any { return a.OverSevenDays })},
+		"total":               {Type: graphql.NewNonNull(graphql.Int), Resolve: agingField(func(a domain.PRAging) any { return a.Total() })},
+	}})
+
+	return graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+		"team": {
+			Type: team,
+			Args: graphql.FieldConfigArgument{
+				"team_name": {Type: graphql.NewNonNull(graphql.String)},
+				"flatten":   {Type: graphql.Boolean, Description: "Include members of all sub-teams"},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				flatten, _ := p.Args["flatten"].(bool)
+				t, err := h.teams.GetTeam(p.Context, stringArg(p, "team_name"), flatten)
+				if errors.Is(err, domain.ErrNotFound) {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, h.resolveError(err)
+				}
+				return &teamNode{name: t.TeamName, team: &t}, nil
+			},
+		},
+		"teams": {
+			Type: listOf(team),
+			Args: graphql.FieldConfigArgument{"limit": {Type: graphql.Int}, "offset": {Type: graphql.Int}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				limit, _ := p.Args["limit"].(int)
+				offset, _ := p.Args["offset"].(int)
+				summaries, _, err := h.teams.ListTeams(p.Context, limit, offset)
+				if err != nil {
+					return nil, h.resolveError(err)
+				}
+				nodes := make([]*teamNode, len(summaries))
+				for i, s := range summaries {
+					nodes[i] = &teamNode{name: s.TeamName}
+				}
+				return nodes, nil
+			},
+		},
+		"user": {
+			Type: user, Args: graphql.FieldConfigArgument{"user_id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.user(p.Context, stringArg(p, "user_id"))
+			},
+		},
+		"pull_request": {
+			Type: pr, Args: graphql.FieldConfigArgument{"pull_request_id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.pullRequest(p.Context, stringArg(p, "pull_request_id"))
+			},
+		},
+		"pull_requests": {
+			Type: listOf(pr), Description: "PRs, newest first",
+			Args: graphql.FieldConfigArgument{
+				"status":     {Type: graphql.String},
+				"ticket_key": {Type: graphql.String},
+				"limit":      {Type: graphql.Int},
+				"offset":     {Type: graphql.Int},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				limit, _ := p.Args["limit"].(int)
+				offset, _ := p.Args["offset"].(int)
+				var filter domain.PRFilter
+				if key := stringArg(p, "ticket_key"); key != "" {
+					filter.TicketKeys = []string{key}
+				}
+				if status := stringArg(p, "status"); status != "" {
+					filter.Statuses = []domain.PRStatus{domain.PRStatus(status)}
+				}
+				result, err := h.prs.ListPRs(p.Context, filter, pagination.Page{Limit: limit, Offset: offset})
+				return result.Items, h.resolveError(err)
+			},
+		},
+		"workload": {
+			Type: listOf(workload),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				loads, err := h.prs.GetWorkload(p.Context)
+				return loads, h.resolveError(err)
+			},
+		},
+		"pr_aging": {
+			Type: listOf(aging),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				buckets, _, err := h.prs.GetOpenPRAging(p.Context)
+				return buckets, h.resolveError(err)
+			},
+		},
+	}})
+}
+
+// teamField resolves a field of a team, loading the team on first use
+func (h *GraphQLHandler) teamField(get func(domain.Team) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		node := p.Source.(*teamNode)
+		if node.team == nil {
+			t, err := h.teams.GetTeam(p.Context, node.name, false)
+			if err != nil {
+				return nil, h.resolveError(err)
+			}
+			node.team = &t
+		}
+		return get(*node.team), nil
+	}
+}
+
+// user returns the user with userID, or nil when there is none
+func (h *GraphQLHandler) user(ctx context.Context, userID string) (any, error) {
+	u, err := h.users.GetUser(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, h.resolveError(err)
+	}
+	return u, nil
+}
+
+// pullRequest returns the PR with prID, or nil when there is none
+func (h *GraphQLHandler) pullRequest(ctx context.Context, prID string) (any, error) {
+	pr, err := h.prs.GetPR(ctx, prID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, h.resolveError(err)
+	}
+	return pr, nil
+}
+
+// withReviewers returns pr with its reviewers; PRs listed by reviewer come without them
+func (h *GraphQLHandler) withReviewers(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
+	if pr.AssignedReviewers != nil && (len(pr.AssignedReviewers) > 0 || pr.IsMerged()) {
+		return pr, nil
+	}
+	full, err := h.prs.GetPR(ctx, pr.PullRequestID)
+	if err != nil {
+		return pr, h.resolveError(err)
+	}
+	return full, nil
+}
+
+// resolveError reports domain errors with their code and hides internal ones
+func (h *GraphQLHandler) resolveError(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := domain.GetErrorCode(err)
+	if code == "" {
+		h.logger.Error("GraphQL resolver failed", zap.Error(err))
+		return &gql.CodedError{Code: string(domain.ErrorCodeInternal), Message: "internal server error"}
+	}
+	return &gql.CodedError{Code: string(code), Message: err.Error()}
+}
+
+func userField(get func(domain.User) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(domain.User)), nil }
+}
+
+func prField(get func(domain.PullRequest) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(domain.PullRequest)), nil
+	}
+}
+
+func reviewField(get func(domain.ReviewAssignment) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(domain.ReviewAssignment)), nil
+	}
+}
+
+func workloadField(get func(domain.ReviewerWorkload) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(domain.ReviewerWorkload)), nil
+	}
+}
+
+func agingField(get func(domain.PRAging) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(domain.PRAging)), nil }
+}
+
+// stringArg returns the string argument name, or "" when absent
+func stringArg(p graphql.ResolveParams, name string) string {
+	s, _ := p.Args[name].(string)
+	return s
+}
+
+// listOf is a non-null list of non-null items
+func listOf(t graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+// optional resolves empty strings to null
+func optional(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -1,19 +1,60 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"sync/atomic"
 	"time"
+
+	"pr-service/internal/db"
+
+	"go.uber.org/zap"
 )
 
-// HealthHandler returns service readiness information.
+// readinessTimeout bounds the database ping of a readiness check
+const readinessTimeout = 2 * time.Second
+
+// databaseProbe is the database as readiness checks see it
+type databaseProbe interface {
+	Ping(ctx context.Context) error
+	PoolStats() db.PoolStats
+}
+
+// HealthHandler answers liveness and readiness probes. The process is live
+// while it serves requests at all; it is ready while the database answers
+// and it is not shutting down.
 type HealthHandler struct {
 	startedAt time.Time
+	database  databaseProbe
+	draining  atomic.Bool
+	logger    *zap.Logger
+}
+
+// HealthOption configures optional HealthHandler behaviour
+type HealthOption func(*HealthHandler)
+
+// WithDatabase makes readiness depend on database reaching d
+func WithDatabase(d databaseProbe, logger *zap.Logger) HealthOption {
+	return func(h *HealthHandler) {
+		h.database = d
+		h.logger = logger
+	}
 }
 
 // NewHealthHandler creates a health handler instance.
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{startedAt: time.Now()}
+func NewHealthHandler(opts ...HealthOption) *HealthHandler {
+	h := &HealthHandler{startedAt: time.Now(), logger: zap.NewNop()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// SetDraining marks the service as shutting down, which fails readiness so
+// load balancers stop routing new requests to it
+func (h *HealthHandler) SetDraining() {
+	h.draining.Store(true)
 }
 
 type healthResponse struct {
@@ -22,7 +63,31 @@ type healthResponse struct {
 	UptimeSec int64  `json:"uptime_seconds"`
 }
 
-// Check responds with a basic health payload.
+type readinessCheck struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+type poolStatsDTO struct {
+	AcquiredConns int32   `json:"acquired_conns"`
+	IdleConns     int32   `json:"idle_conns"`
+	TotalConns    int32   `json:"total_conns"`
+	MaxConns      int32   `json:"max_conns"`
+	Saturation    float64 `json:"saturation"`
+}
+
+type readinessResponse struct {
+	Status    string           `json:"status"`
+	Timestamp string           `json:"timestamp"`
+	Draining  bool             `json:"draining"`
+	Checks    []readinessCheck `json:"checks"`
+	Pool      *poolStatsDTO    `json:"pool,omitempty"`
+}
+
+// Check responds with a basic health payload. It serves GET /livez and the
+// older GET /health.
 func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 	resp := healthResponse{
 		Status:    "ok",
@@ -34,3 +99,52 @@ func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(resp)
 }
+
+// Ready handles GET /readyz: 200 when the database answers a ping and the
+// service is not shutting down, 503 otherwise. The response lists the checks
+// and the connection pool usage.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	resp := readinessResponse{
+		Status:    "ready",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Draining:  h.draining.Load(),
+		Checks:    []readinessCheck{},
+	}
+	if resp.Draining {
+		resp.Status = "not_ready"
+	}
+
+	if h.database != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		start := time.Now()
+		err := h.database.Ping(ctx)
+		cancel()
+
+		check := readinessCheck{Name: "database", Status: "ok", DurationMs: float64(time.Since(start).Microseconds()) / 1000}
+		if err != nil {
+			// Probes are public, so connection details stay in the logs
+			h.logger.Warn("Readiness check failed", zap.String("check", check.Name), zap.Error(err))
+			check.Status, check.Error = "fail", "database is unreachable"
+			resp.Status = "not_ready"
+		}
+		resp.Checks = append(resp.Checks, check)
+
+		stats := h.database.PoolStats()
+		resp.Pool = &poolStatsDTO{
+			AcquiredConns: stats.AcquiredConns,
+			IdleConns:     stats.IdleConns,
+			TotalConns:    stats.TotalConns,
+			MaxConns:      stats.MaxConns,
+			Saturation:    stats.Saturation(),
+		}
+	}
+
+	status := http.StatusOK
+	if resp.Status != "ready" {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+	"pr-service/internal/maintenance"
+
+	"go.uber.org/zap"
+)
+
+// MaintenanceHandler switches the service in and out of maintenance mode
+type MaintenanceHandler struct {
+	sw     *maintenance.Switch
+	logger *zap.Logger
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(sw *maintenance.Switch, logger *zap.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		sw:     sw,
+		logger: logger,
+	}
+}
+
+type SetMaintenanceRequest struct {
+	Mode       string `json:"mode"`
+	Message    string `json:"message,omitempty"`
+	RetryAfter string `json:"retry_after,omitempty"`
+}
+
+// MaintenanceDTO describes the maintenance mode
+type MaintenanceDTO struct {
+	Mode              string `json:"mode"`
+	Message           string `json:"message,omitempty"`
+	RetryAfterSeconds int64  `json:"retry_after_seconds"`
+	Since             string `json:"since"`
+}
+
+type maintenanceResponse struct {
+	Maintenance MaintenanceDTO `json:"maintenance"`
+}
+
+// Get handles GET /admin/maintenance
+func (h *MaintenanceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, h.sw.State())
+}
+
+// Set handles POST /admin/maintenance
+func (h *MaintenanceHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req SetMaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	if strings.TrimSpace(req.Mode) == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("mode", "is required"), h.logger)
+		return
+	}
+
+	var retryAfter time.Duration
+	if raw := strings.TrimSpace(req.RetryAfter); raw != "" {
+		var err error
+		if retryAfter, err = time.ParseDuration(raw); err != nil || retryAfter <= 0 {
+			middleware.WriteErrorResponse(w, domain.NewValidationError("retry_after", "must be a positive duration such as 10m"), h.logger)
+			return
+		}
+	}
+
+	state, err := h.sw.Set(maintenance.Mode(strings.TrimSpace(req.Mode)), strings.TrimSpace(req.Message), retryAfter)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	h.logger.Warn("Maintenance mode changed", zap.String("mode", string(state.Mode)), zap.String("message", state.Message))
+	h.respond(w, state)
+}
+
+func (h *MaintenanceHandler) respond(w http.ResponseWriter, state maintenance.State) {
+	resp := maintenanceResponse{Maintenance: MaintenanceDTO{
+		Mode:              string(state.Mode),
+		Message:           state.Message,
+		RetryAfterSeconds: int64(state.RetryAfter / time.Second),
+		Since:             state.Since.Format(time.RFC3339),
+	}}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
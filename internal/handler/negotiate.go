@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Binary media types offered by read-heavy endpoints in addition to JSON
+const (
+	contentTypeMsgpack  = "application/msgpack"
+	contentTypeProtobuf = "application/x-protobuf"
+)
+
+// protobufMessageType names the message of protobuf bodies in their content type
+var protobufMessageType = (&structpb.Struct{}).ProtoReflect().Descriptor().FullName()
+
+// negotiateBinary returns the first binary media type listed in Accept, or ""
+// for JSON. An explicit format=json query parameter always selects JSON.
+func negotiateBinary(r *http.Request) string {
+	if strings.EqualFold(strings.TrimSpace(r.URL.Query().Get("format")), "json") {
+		return ""
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		switch mediaType {
+		case contentTypeMsgpack, "application/x-msgpack":
+			return contentTypeMsgpack
+		case contentTypeProtobuf, "application/protobuf":
+			return contentTypeProtobuf
+		}
+	}
+	return ""
+}
+
+// writeNegotiated writes resp with status 200 as JSON, MessagePack or a
+// google.protobuf.Struct, as negotiated by the Accept header. Binary bodies
+// carry the same fields as the JSON one.
+func writeNegotiated(w http.ResponseWriter, r *http.Request, resp any, logger *zap.Logger) {
+	w.Header().Add("Vary", "Accept")
+	contentType := negotiateBinary(r)
+	if contentType == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			logger.Error("failed to encode response", zap.Error(err))
+		}
+		return
+	}
+
+	body, err := encodeBinary(contentType, resp)
+	if err != nil {
+		logger.Error("failed to encode response", zap.String("content_type", contentType), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if contentType == contentTypeProtobuf {
+		contentType += "; messageType=" + string(protobufMessageType)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// encodeBinary goes through the JSON encoding of resp, so binary bodies honor
+// the same field names, omitempty and time formats as JSON ones. Numbers are
+// doubles, as in JSON; MessagePack writes the whole ones as integers.
+func encodeBinary(contentType string, resp any) ([]byte, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if contentType == contentTypeMsgpack {
+		var buf bytes.Buffer
+		enc := msgpack.NewEncoder(&buf)
+		enc.SetSortMapKeys(true)
+		enc.UseCompactInts(true)
+		enc.UseCompactFloats(true)
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	msg, err := structpb.NewStruct(doc)
+	if err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type outboundWebhookService interface {
+	Subscribe(ctx context.Context, rawURL, secret string, eventTypes []domain.EventType) (domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, subscriptionID int64, status domain.WebhookDeliveryStatus, limit, offset int) ([]domain.WebhookDelivery, int, error)
+}
+
+// OutboundWebhookHandler handles registration of outbound webhooks and their delivery log
+type OutboundWebhookHandler struct {
+	service outboundWebhookService
+	logger  *zap.Logger
+}
+
+// NewOutboundWebhookHandler creates a new outbound webhook handler
+func NewOutboundWebhookHandler(service outboundWebhookService, logger *zap.Logger) *OutboundWebhookHandler {
+	return &OutboundWebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type SubscribeWebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+}
+
+type DeleteWebhookRequest struct {
+	ID int64 `json:"id"`
+}
+
+// WebhookSubscriptionDTO describes a subscription; Secret is only returned on creation
+type WebhookSubscriptionDTO struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type WebhookDeliveryDTO struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	URL            string          `json:"url"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"next_attempt_at,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    string          `json:"delivered_at,omitempty"`
+}
+
+type subscriptionResponse struct {
+	Subscription WebhookSubscriptionDTO `json:"subscription"`
+}
+
+type listSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscriptionDTO `json:"subscriptions"`
+}
+
+type listDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryDTO `json:"deliveries"`
+	Total      int                  `json:"total"`
+}
+
+// Subscribe handles POST /webhooks/subscribe
+func (h *OutboundWebhookHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req SubscribeWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	eventTypes := make([]domain.EventType, len(req.EventTypes))
+	for i, t := range req.EventTypes {
+		eventTypes[i] = domain.EventType(strings.TrimSpace(t))
+	}
+
+	sub, err := h.service.Subscribe(r.Context(), req.URL, req.Secret, eventTypes)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := subscriptionResponse{Subscription: mapSubscriptionToDTO(sub)}
+	resp.Subscription.Secret = sub.Secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListSubscriptions handles GET /webhooks/list
+func (h *OutboundWebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.ListSubscriptions(r.Context())
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := listSubscriptionsResponse{Subscriptions: make([]WebhookSubscriptionDTO, len(subs))}
+	for i, sub := range subs {
+		resp.Subscriptions[i] = mapSubscriptionToDTO(sub)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Delete handles POST /webhooks/delete
+func (h *OutboundWebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req DeleteWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	if req.ID <= 0 {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("id", "must be positive"), h.logger)
+		return
+	}
+
+	if err := h.service.Unsubscribe(r.Context(), req.ID); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(req)
+}
+
+// ListDeliveries handles GET /webhooks/deliveries?subscription_id=...&status=...&limit=...&offset=...
+func (h *OutboundWebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	subscriptionID, err := strconv.ParseInt(strings.TrimSpace(query.Get("subscription_id")), 10, 64)
+	if err != nil || subscriptionID <= 0 {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("subscription_id", "must be a positive integer"), h.logger)
+		return
+	}
+	status := domain.WebhookDeliveryStatus(strings.ToUpper(strings.TrimSpace(query.Get("status"))))
+	limit, err := parseIntQuery(r, "limit")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	offset, err := parseIntQuery(r, "offset")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	deliveries, total, err := h.service.ListDeliveries(r.Context(), subscriptionID, status, limit, offset)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := listDeliveriesResponse{
+		Deliveries: make([]WebhookDeliveryDTO, len(deliveries)),
+		Total:      total,
+	}
+	for i, d := range deliveries {
+		resp.Deliveries[i] = mapDeliveryToDTO(d)
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+func mapSubscriptionToDTO(sub domain.WebhookSubscription) WebhookSubscriptionDTO {
+	eventTypes := make([]string, len(sub.EventTypes))
+	for i, t := range sub.EventTypes {
+		eventTypes[i] = string(t)
+	}
+
+	return WebhookSubscriptionDTO{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  sub.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func mapDeliveryToDTO(d domain.WebhookDelivery) WebhookDeliveryDTO {
+	dto := WebhookDeliveryDTO{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		URL:            d.URL,
+		Event:          string(d.EventType),
+		Payload:        json.RawMessage(d.Payload),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastError:      d.LastError,
+		ResponseStatus: d.ResponseStatus,
+		CreatedAt:      d.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		dto.NextAttemptAt = d.NextAttemptAt.UTC().Format(time.RFC3339)
+	}
+	if d.DeliveredAt != nil {
+		dto.DeliveredAt = d.DeliveredAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
@@ -3,20 +3,27 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"pr-service/internal/app/middleware"
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+	"pr-service/internal/prquery"
 
 	"go.uber.org/zap"
 )
 
 type prService interface {
-	CreatePR(ctx context.Context, prID, prName, authorID string) (domain.PullRequest, error)
+	CreatePR(ctx context.Context, prID, prName, authorID, teamName, repository, ticketKey string) (domain.PullRequest, error)
+	ListPRs(ctx context.Context, filter domain.PRFilter, page pagination.Page) (pagination.Result[domain.PullRequest], error)
 	MergePR(ctx context.Context, prID string) (domain.PullRequest, error)
+	DeletePR(ctx context.Context, prID string) (domain.PullRequest, error)
 	ReassignReviewer(ctx context.Context, prID, oldUserID string) (domain.PullRequest, string, error)
+	RecordReview(ctx context.Context, prID, userID string) (domain.PullRequest, time.Time, error)
+	Batch(ctx context.Context, ops []domain.BatchOperation) ([]domain.BatchResult, error)
 }
 
 // PRHandler handles pull request HTTP requests
@@ -39,21 +46,42 @@ type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	TeamName        string `json:"team_name,omitempty"`
+	Repository      string `json:"repository,omitempty"`
+	TicketKey       string `json:"ticket_key,omitempty"`
 }
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 }
 
+type DeletePRRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
 type ReassignRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"` // per OpenAPI schema (not old_reviewer_id)
 }
 
+type ReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type reviewResponse struct {
+	PR            PullRequestDTO `json:"pr"`
+	UserID        string         `json:"user_id"`
+	FirstActionAt string         `json:"first_action_at"`
+}
+
 type PullRequestDTO struct {
 	PullRequestID     string   `json:"pull_request_id"`
 	PullRequestName   string   `json:"pull_request_name"`
 	AuthorID          string   `json:"author_id"`
+	TeamName          string   `json:"team_name,omitempty"`
+	Repository        string   `json:"repository,omitempty"`
+	TicketKey         string   `json:"ticket_key,omitempty"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
 	Status            string   `json:"status"`
 	CreatedAt         *string  `json:"createdAt,omitempty"`
@@ -64,16 +92,66 @@ type prEnvelope struct {
 	PR PullRequestDTO `json:"pr"`
 }
 
+type listPRsResponse struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	Total        int              `json:"total"`
+	NextCursor   string           `json:"next_cursor"`
+}
+
 type ReassignResponse struct {
 	PR         PullRequestDTO `json:"pr"`
 	ReplacedBy string         `json:"replaced_by"`
 }
 
+type BatchRequest struct {
+	Operations []BatchOperationDTO `json:"operations"`
+}
+
+// BatchOperationDTO is a create, merge or reassign request tagged with its op
+type BatchOperationDTO struct {
+	Op              string `json:"op"`
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name,omitempty"`
+	AuthorID        string `json:"author_id,omitempty"`
+	TeamName        string `json:"team_name,omitempty"`
+	Repository      string `json:"repository,omitempty"`
+	TicketKey       string `json:"ticket_key,omitempty"`
+	OldUserID       string `json:"old_user_id,omitempty"`
+}
+
+// Batch result statuses
+const (
+	batchStatusOK         = "ok"
+	batchStatusFailed     = "failed"
+	batchStatusRolledBack = "rolled_back"
+	batchStatusSkipped    = "skipped"
+)
+
+type batchResultDTO struct {
+	Op         string                  `json:"op"`
+	Status     string                  `json:"status"`
+	PR         *PullRequestDTO         `json:"pr,omitempty"`
+	ReplacedBy string                  `json:"replaced_by,omitempty"`
+	Error      *middleware.ErrorDetail `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchResultDTO `json:"results"`
+}
+
+// batchErrorResponse extends the error response with the failed operation
+// and the outcome of every operation
+type batchErrorResponse struct {
+	Error       middleware.ErrorDetail `json:"error"`
+	FailedIndex int                    `json:"failed_index"`
+	Results     []batchResultDTO       `json:"results"`
+}
+
 // CreatePR handles POST /pullRequest/create
 func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	var req CreatePRRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
 		return
 	}
 
@@ -83,7 +161,8 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.service.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	pr, err := h.service.CreatePR(
+		r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.TeamName, req.Repository, req.TicketKey)
 	if err != nil {
 		middleware.WriteErrorResponse(w, err, h.logger)
 		return
@@ -98,11 +177,57 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ListPRs handles GET /pullRequest/list?q=...&ticket=...&status=...&limit=...&cursor=...&order=...
+// The ticket and status parameters are shorthands for the same fields of q.
+func (h *PRHandler) ListPRs(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.ParseRequest(r, pagination.OrderDesc)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	filter, err := prquery.Parse(r.URL.Query().Get(prquery.Param))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	if ticket := strings.TrimSpace(r.URL.Query().Get("ticket")); ticket != "" {
+		if len(filter.TicketKeys) > 0 {
+			middleware.WriteErrorResponse(w, domain.NewValidationError("ticket", "must not be combined with a ticket term in q"), h.logger)
+			return
+		}
+		filter.TicketKeys = []string{ticket}
+	}
+	if status := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("status"))); status != "" {
+		if len(filter.Statuses) > 0 {
+			middleware.WriteErrorResponse(w, domain.NewValidationError("status", "must not be combined with a status term in q"), h.logger)
+			return
+		}
+		filter.Statuses = []domain.PRStatus{domain.PRStatus(status)}
+	}
+
+	result, err := h.service.ListPRs(r.Context(), filter, page)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := listPRsResponse{
+		PullRequests: make([]PullRequestDTO, len(result.Items)),
+		Total:        result.Total,
+		NextCursor:   result.NextCursor,
+	}
+	for i, pr := range result.Items {
+		resp.PullRequests[i] = mapPRToDTO(pr)
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
 // MergePR handles POST /pullRequest/merge
 func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	var req MergePRRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
 		return
 	}
 
@@ -127,11 +252,40 @@ func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DeletePR handles POST /pullRequest/delete, an admin operation
+func (h *PRHandler) DeletePR(w http.ResponseWriter, r *http.Request) {
+	var req DeletePRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.PullRequestID = strings.TrimSpace(req.PullRequestID)
+	if req.PullRequestID == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("pull_request_id", "must not be empty"), h.logger)
+		return
+	}
+
+	pr, err := h.service.DeletePR(r.Context(), req.PullRequestID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := prEnvelope{PR: mapPRToDTO(pr)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to encode delete PR response", zap.Error(err))
+	}
+}
+
 // ReassignReviewer handles POST /pullRequest/reassign
 func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req ReassignRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
 		return
 	}
 
@@ -159,12 +313,145 @@ func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// RecordReview handles POST /pullRequest/review
+func (h *PRHandler) RecordReview(w http.ResponseWriter, r *http.Request) {
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.PullRequestID = strings.TrimSpace(req.PullRequestID)
+	if req.PullRequestID == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("pull_request_id", "must not be empty"), h.logger)
+		return
+	}
+	userID, err := selfUserID(r.Context(), strings.TrimSpace(req.UserID))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	req.UserID = userID
+
+	pr, firstActionAt, err := h.service.RecordReview(r.Context(), req.PullRequestID, req.UserID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := reviewResponse{
+		PR:            mapPRToDTO(pr),
+		UserID:        req.UserID,
+		FirstActionAt: firstActionAt.Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to encode review response", zap.Error(err))
+	}
+}
+
+// Batch handles POST /batch
+func (h *PRHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var req BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	if len(req.Operations) == 0 {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("operations", "must not be empty"), h.logger)
+		return
+	}
+
+	ops := make([]domain.BatchOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = domain.BatchOperation{
+			Type:            domain.BatchOpType(strings.ToLower(strings.TrimSpace(op.Op))),
+			PullRequestID:   op.PullRequestID,
+			PullRequestName: op.PullRequestName,
+			AuthorID:        op.AuthorID,
+			TeamName:        op.TeamName,
+			Repository:      op.Repository,
+			TicketKey:       strings.TrimSpace(op.TicketKey),
+			OldUserID:       op.OldUserID,
+		}
+	}
+
+	results, err := h.service.Batch(r.Context(), ops)
+	var batchErr *domain.BatchError
+	if errors.As(err, &batchErr) {
+		h.writeBatchError(w, ops, batchErr)
+		return
+	}
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := batchResponse{Results: make([]batchResultDTO, len(results))}
+	for i, result := range results {
+		pr := mapPRToDTO(result.PR)
+		resp.Results[i] = batchResultDTO{
+			Op:         string(ops[i].Type),
+			Status:     batchStatusOK,
+			PR:         &pr,
+			ReplacedBy: result.ReplacedBy,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to encode batch response", zap.Error(err))
+	}
+}
+
+// writeBatchError responds with the status of the failed operation: the ones
+// before it were rolled back and the ones after it were not run
+func (h *PRHandler) writeBatchError(w http.ResponseWriter, ops []domain.BatchOperation, batchErr *domain.BatchError) {
+	statusCode := domain.GetHTTPStatus(batchErr)
+	if statusCode == http.StatusInternalServerError {
+		h.logger.Error("Internal server error", zap.Error(batchErr), zap.Int("status", statusCode))
+	}
+	detail := middleware.NewErrorDetail(batchErr)
+	detail.RequestID = w.Header().Get(middleware.RequestIDHeader)
+
+	resp := batchErrorResponse{
+		Error:       detail,
+		FailedIndex: batchErr.Index,
+		Results:     make([]batchResultDTO, len(ops)),
+	}
+	for i, op := range ops {
+		result := batchResultDTO{Op: string(op.Type)}
+		switch {
+		case i < batchErr.Index:
+			result.Status = batchStatusRolledBack
+		case i == batchErr.Index:
+			result.Status = batchStatusFailed
+			result.Error = &detail
+		default:
+			result.Status = batchStatusSkipped
+		}
+		resp.Results[i] = result
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to encode batch error response", zap.Error(err))
+	}
+}
+
 // Helper to map domain.PullRequest to DTO
 func mapPRToDTO(pr domain.PullRequest) PullRequestDTO {
 	dto := PullRequestDTO{
 		PullRequestID:     pr.PullRequestID,
 		PullRequestName:   pr.PullRequestName,
 		AuthorID:          pr.AuthorID,
+		TeamName:          pr.TeamName,
+		Repository:        pr.Repository,
+		TicketKey:         pr.TicketKey,
 		AssignedReviewers: pr.AssignedReviewers,
 		Status:            string(pr.Status),
 	}
@@ -187,20 +474,22 @@ func normalizeCreatePRRequest(req *CreatePRRequest) {
 	req.PullRequestID = strings.TrimSpace(req.PullRequestID)
 	req.PullRequestName = strings.TrimSpace(req.PullRequestName)
 	req.AuthorID = strings.TrimSpace(req.AuthorID)
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	req.Repository = strings.TrimSpace(req.Repository)
+	req.TicketKey = strings.TrimSpace(req.TicketKey)
 }
 
 func validateCreatePRRequest(req CreatePRRequest) error {
-	if req.PullRequestID == "" ||
-		req.PullRequestName == "" ||
-		req.AuthorID == "" {
-		return domain.ErrInvalidArgument
-	}
-	return nil
+	var v domain.Validator
+	v.Required(req.PullRequestID, "pull_request_id")
+	v.Required(req.PullRequestName, "pull_request_name")
+	v.Required(req.AuthorID, "author_id")
+	return v.Err()
 }
 
 func validateMergeRequest(req MergePRRequest) error {
 	if req.PullRequestID == "" {
-		return domain.ErrInvalidArgument
+		return domain.NewValidationError("pull_request_id", "must not be empty")
 	}
 	return nil
 }
@@ -211,8 +500,8 @@ func normalizeReassignRequest(req *ReassignRequest) {
 }
 
 func validateReassignRequest(req ReassignRequest) error {
-	if req.PullRequestID == "" || req.OldUserID == "" {
-		return domain.ErrInvalidArgument
-	}
-	return nil
+	var v domain.Validator
+	v.Required(req.PullRequestID, "pull_request_id")
+	v.Required(req.OldUserID, "old_user_id")
+	return v.Err()
 }
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// RequestLogHandler changes which requests are logged while the service runs
+type RequestLogHandler struct {
+	policy *middleware.RequestLogPolicy
+	logger *zap.Logger
+}
+
+// NewRequestLogHandler creates a new request log handler
+func NewRequestLogHandler(policy *middleware.RequestLogPolicy, logger *zap.Logger) *RequestLogHandler {
+	return &RequestLogHandler{
+		policy: policy,
+		logger: logger,
+	}
+}
+
+type SetRequestLogRequest struct {
+	SlowThreshold *string   `json:"slow_threshold,omitempty"`
+	SampleRatio   *float64  `json:"sample_ratio,omitempty"`
+	DebugRoutes   *[]string `json:"debug_routes,omitempty"`
+}
+
+// RequestLogDTO describes the request logging thresholds and the routes whose
+// bodies are logged
+type RequestLogDTO struct {
+	SlowThresholdMs int64    `json:"slow_threshold_ms"`
+	SampleRatio     float64  `json:"sample_ratio"`
+	DebugRoutes     []string `json:"debug_routes"`
+}
+
+type requestLogResponse struct {
+	RequestLog RequestLogDTO `json:"request_log"`
+}
+
+// Get handles GET /admin/logging
+func (h *RequestLogHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.respond(w)
+}
+
+// Set handles POST /admin/logging; omitted fields keep their value
+func (h *RequestLogHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req SetRequestLogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	slow, ratio := h.policy.Thresholds()
+	if req.SlowThreshold != nil {
+		var err error
+		if slow, err = time.ParseDuration(strings.TrimSpace(*req.SlowThreshold)); err != nil || slow < 0 {
+			middleware.WriteErrorResponse(w, domain.NewValidationError("slow_threshold", "must be a duration such as 500ms, or 0 to disable"), h.logger)
+			return
+		}
+	}
+	if req.SampleRatio != nil {
+		if ratio = *req.SampleRatio; ratio < 0 || ratio > 1 {
+			middleware.WriteErrorResponse(w, domain.NewValidationError("sample_ratio", "must be between 0 and 1"), h.logger)
+			return
+		}
+	}
+
+	if req.DebugRoutes != nil {
+		for _, pattern := range *req.DebugRoutes {
+			method, path, ok := strings.Cut(pattern, " ")
+			if !ok || method == "" || !strings.HasPrefix(path, "/") {
+				middleware.WriteErrorResponse(w, domain.NewValidationError("debug_routes", "must be route patterns such as \"POST /team/add\""), h.logger)
+				return
+			}
+		}
+	}
+
+	h.policy.SetThresholds(slow, ratio)
+	if req.DebugRoutes != nil {
+		h.policy.SetDebugRoutes(*req.DebugRoutes)
+	}
+	h.logger.Info("Request logging changed", zap.Duration("slow_threshold", slow), zap.Float64("sample_ratio", ratio),
+		zap.Strings("debug_routes", h.policy.DebugRoutes()))
+	h.respond(w)
+}
+
+func (h *RequestLogHandler) respond(w http.ResponseWriter) {
+	slow, ratio := h.policy.Thresholds()
+	resp := requestLogResponse{RequestLog: RequestLogDTO{
+		SlowThresholdMs: slow.Milliseconds(),
+		SampleRatio:     ratio,
+		DebugRoutes:     h.policy.DebugRoutes(),
+	}}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
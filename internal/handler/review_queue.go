@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultQueueWait is how long a queue wait blocks when no timeout is given
+	DefaultQueueWait = 30 * time.Second
+	// MaxQueueWait caps the timeout of a queue wait
+	MaxQueueWait = 120 * time.Second
+	// queueRecheck is how often a waiting request re-reads the queue, so it
+	// also sees changes made through other instances, whose events it does not get
+	queueRecheck = 5 * time.Second
+)
+
+type reviewQueueService interface {
+	GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
+}
+
+// ReviewQueueHandler serves long-polling waits on a reviewer's queue
+type ReviewQueueHandler struct {
+	service reviewQueueService
+	stream  eventStream
+	logger  *zap.Logger
+}
+
+// NewReviewQueueHandler creates a new review queue handler
+func NewReviewQueueHandler(service reviewQueueService, stream eventStream, logger *zap.Logger) *ReviewQueueHandler {
+	return &ReviewQueueHandler{
+		service: service,
+		stream:  stream,
+		logger:  logger,
+	}
+}
+
+type reviewQueueResponse struct {
+	UserID       string             `json:"user_id"`
+	PullRequests []PullRequestShort `json:"pull_requests"`
+	Version      string             `json:"version"`
+	Changed      bool               `json:"changed"`
+}
+
+// Wait handles GET /users/reviewQueue/wait?user_id=...&timeout=...&version=...
+// It answers once the user's review queue differs from version, or from the
+// queue at the time of the request when no version is given, or when the
+// timeout elapses with changed=false. Clients pass the returned version to
+// their next wait so changes in between are not missed.
+func (h *ReviewQueueHandler) Wait(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	userID := strings.TrimSpace(query.Get("user_id"))
+	if err := validateUserID(userID); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	timeout, err := parseQueueWait(query.Get("timeout"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server write timeout would otherwise cut long waits off
+	if err := rc.SetWriteDeadline(time.Now().Add(timeout + queueRecheck)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	// Subscribe before the first read so no change slips in between; the bus
+	// carries the events of every organization
+	org := domain.OrgFromContext(r.Context())
+	events, unsubscribe := h.stream.Subscribe(0)
+	defer unsubscribe()
+
+	prs, err := h.service.GetPRsByReviewer(r.Context(), userID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	baseline := strings.TrimSpace(query.Get("version"))
+	if baseline == "" {
+		baseline = queueVersion(prs)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	recheck := time.NewTicker(queueRecheck)
+	defer recheck.Stop()
+
+	for queueVersion(prs) == baseline {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline.C:
+			h.writeQueue(w, r, userID, prs, false)
+			return
+		case e, ok := <-events:
+			if !ok {
+				// The bus closes on shutdown; answer with the queue as it is
+				h.writeQueue(w, r, userID, prs, false)
+				return
+			}
+			if e.OrgID != org || !touchesQueue(e, userID) {
+				continue
+			}
+		case <-recheck.C:
+		}
+
+		if prs, err = h.service.GetPRsByReviewer(r.Context(), userID); err != nil {
+			middleware.WriteErrorResponse(w, err, h.logger)
+			return
+		}
+	}
+	h.writeQueue(w, r, userID, prs, true)
+}
+
+func (h *ReviewQueueHandler) writeQueue(w http.ResponseWriter, r *http.Request, userID string, prs []domain.PullRequest, changed bool) {
+	resp := reviewQueueResponse{
+		UserID:       userID,
+		PullRequests: make([]PullRequestShort, len(prs)),
+		Version:      queueVersion(prs),
+		Changed:      changed,
+	}
+	for i, pr := range prs {
+		resp.PullRequests[i] = PullRequestShort{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Status:          string(pr.Status),
+		}
+	}
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// parseQueueWait reads a timeout given as a duration ("30s") or in seconds
+func parseQueueWait(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return DefaultQueueWait, nil
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return 0, domain.NewValidationError("timeout", "must be a duration such as 30s")
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout <= 0 || timeout > MaxQueueWait {
+		return 0, domain.NewValidationError("timeout", "must be positive and at most "+MaxQueueWait.String())
+	}
+	return timeout, nil
+}
+
+// queueVersion fingerprints the parts of a queue shown to clients
+func queueVersion(prs []domain.PullRequest) string {
+	entries := make([]string, len(prs))
+	for i, pr := range prs {
+		entries[i] = pr.PullRequestID + "\x00" + pr.PullRequestName + "\x00" + pr.AuthorID + "\x00" + string(pr.Status)
+	}
+	slices.Sort(entries)
+	sum := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// touchesQueue reports whether e may change userID's review queue
+func touchesQueue(e domain.Event, userID string) bool {
+	if e.ReviewerID == userID || e.OldReviewerID == userID || e.UserID == userID {
+		return true
+	}
+	return e.PR != nil && slices.Contains(e.PR.AssignedReviewers, userID)
+}
@@ -2,53 +2,772 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
 
 	"go.uber.org/zap"
 )
 
 type prStatsService interface {
-	GetAssignmentStats(ctx context.Context) (map[string]int, map[string]int, error)
+	GetAssignmentStats(
+		ctx context.Context,
+		from, to time.Time,
+		sort domain.StatsSort,
+		limit, offset int,
+	) (domain.AssignmentStats, error)
+	GetAssignmentStatsByRole(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetAssignmentStatsByTeam(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetFairnessStats(ctx context.Context, from, to time.Time) ([]domain.TeamFairness, error)
+	GetOpenPRAging(ctx context.Context) ([]domain.PRAging, time.Time, error)
+	GetWorkload(ctx context.Context) ([]domain.ReviewerWorkload, error)
+	GetReassignmentStats(ctx context.Context, from, to time.Time) ([]domain.ReassignmentStats, []domain.ReassignmentStats, error)
+	GetReviewMatrix(ctx context.Context, from, to time.Time, teamName string) (domain.ReviewMatrix, error)
+	GetTimeToReviewStats(
+		ctx context.Context,
+		from, to time.Time,
+		repository string,
+	) ([]domain.LatencyStats, []domain.LatencyStats, []domain.LatencyStats, error)
+	GetTimeToMergeStats(
+		ctx context.Context,
+		from, to time.Time,
+		repository string,
+	) ([]domain.LatencyStats, []domain.LatencyStats, []domain.LatencyStats, []domain.LatencyStats, error)
+	GetAuthorStats(
+		ctx context.Context,
+		from, to time.Time,
+		repository string,
+	) ([]domain.AuthorStats, []domain.AuthorStats, []domain.AuthorStats, error)
 }
 
+type dailyStatsService interface {
+	GetDailyStats(ctx context.Context, from, to time.Time, teamName string) ([]domain.DailyStats, error)
+}
+
+// defaultStatsWindow is the period covered by time-based stats when from is omitted
+const defaultStatsWindow = 30 * 24 * time.Hour
+
 // StatsHandler handles statistics endpoints
 type StatsHandler struct {
-	prService prStatsService
-	logger    *zap.Logger
+	prService    prStatsService
+	dailyService dailyStatsService
+	logger       *zap.Logger
 }
 
 // NewStatsHandler creates a new stats handler
-func NewStatsHandler(prService prStatsService, logger *zap.Logger) *StatsHandler {
+func NewStatsHandler(prService prStatsService, dailyService dailyStatsService, logger *zap.Logger) *StatsHandler {
 	return &StatsHandler{
-		prService: prService,
-		logger:    logger,
+		prService:    prService,
+		dailyService: dailyService,
+		logger:       logger,
 	}
 }
 
+type userAssignmentsDTO struct {
+	UserID string `json:"user_id"`
+	Count  int    `json:"count"`
+}
+
+type prAssignmentsDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	Count         int    `json:"count"`
+}
+
 type assignmentStatsResponse struct {
-	ByUser map[string]int `json:"by_user"`
-	ByPR   map[string]int `json:"by_pr"`
+	From       time.Time            `json:"from"`
+	To         time.Time            `json:"to"`
+	ByUser     []userAssignmentsDTO `json:"by_user"`
+	TotalUsers int                  `json:"total_users"`
+	ByPR       []prAssignmentsDTO   `json:"by_pr"`
+	TotalPRs   int                  `json:"total_prs"`
+	ByRole     map[string]int       `json:"by_role"`
+	ByTeam     map[string]int       `json:"by_team"`
 }
 
-// GetAssignmentStats returns assignment statistics
+// GetAssignmentStats handles GET /stats/assignments?from=...&to=...&sort=...&limit=...&offset=...
+// by_user and by_pr are paged independently with the same limit and offset.
 func (h *StatsHandler) GetAssignmentStats(w http.ResponseWriter, r *http.Request) {
-	byUser, byPR, err := h.prService.GetAssignmentStats(r.Context())
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	limit, err := parseIntQuery(r, "limit")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	offset, err := parseIntQuery(r, "offset")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	sort := domain.StatsSort(strings.TrimSpace(r.URL.Query().Get("sort")))
+
+	stats, err := h.prService.GetAssignmentStats(r.Context(), from, to, sort, limit, offset)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	byRole, err := h.prService.GetAssignmentStatsByRole(r.Context(), from, to)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	byTeam, err := h.prService.GetAssignmentStatsByTeam(r.Context(), from, to)
 	if err != nil {
 		middleware.WriteErrorResponse(w, err, h.logger)
 		return
 	}
 
+	if asCSV {
+		rows := keyCountRows("by_user", stats.ByUser)
+		rows = append(rows, keyCountRows("by_pr", stats.ByPR)...)
+		rows = append(rows, countRows("by_role", byRole)...)
+		rows = append(rows, countRows("by_team", byTeam)...)
+		h.writeCSV(w, "assignments", []string{"group", "key", "count"}, rows)
+		return
+	}
+
 	response := assignmentStatsResponse{
-		ByUser: byUser,
-		ByPR:   byPR,
+		From:       from,
+		To:         to,
+		ByUser:     make([]userAssignmentsDTO, len(stats.ByUser)),
+		TotalUsers: stats.TotalUsers,
+		ByPR:       make([]prAssignmentsDTO, len(stats.ByPR)),
+		TotalPRs:   stats.TotalPRs,
+		ByRole:     byRole,
+		ByTeam:     byTeam,
+	}
+	for i, c := range stats.ByUser {
+		response.ByUser[i] = userAssignmentsDTO{UserID: c.Key, Count: c.Count}
+	}
+	for i, c := range stats.ByPR {
+		response.ByPR[i] = prAssignmentsDTO{PullRequestID: c.Key, Count: c.Count}
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type teamFairnessDTO struct {
+	TeamName      string   `json:"team_name"`
+	ActiveMembers int      `json:"active_members"`
+	Assignments   int      `json:"assignments"`
+	Gini          float64  `json:"gini"`
+	MaxMinRatio   *float64 `json:"max_min_ratio"`
+}
+
+type fairnessResponse struct {
+	From  time.Time         `json:"from"`
+	To    time.Time         `json:"to"`
+	Teams []teamFairnessDTO `json:"teams"`
+}
+
+// GetFairness handles GET /stats/fairness?from=...&to=...
+func (h *StatsHandler) GetFairness(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	teams, err := h.prService.GetFairnessStats(r.Context(), from, to)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if asCSV {
+		rows := make([][]string, len(teams))
+		for i, t := range teams {
+			ratio := ""
+			if t.MaxMinRatio != nil {
+				ratio = formatFloat(*t.MaxMinRatio)
+			}
+			rows[i] = []string{t.TeamName, strconv.Itoa(t.ActiveMembers), strconv.Itoa(t.Assignments), formatFloat(t.Gini), ratio}
+		}
+		h.writeCSV(w, "fairness", []string{"team_name", "active_members", "assignments", "gini", "max_min_ratio"}, rows)
+		return
+	}
+
+	response := fairnessResponse{
+		From:  from,
+		To:    to,
+		Teams: make([]teamFairnessDTO, len(teams)),
+	}
+	for i, t := range teams {
+		response.Teams[i] = teamFairnessDTO{
+			TeamName:      t.TeamName,
+			ActiveMembers: t.ActiveMembers,
+			Assignments:   t.Assignments,
+			Gini:          t.Gini,
+			MaxMinRatio:   t.MaxMinRatio,
+		}
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type prAgingDTO struct {
+	TeamName         string `json:"team_name"`
+	UnderOneDay      int    `json:"under_1d"`
+	OneToThreeDays   int    `json:"from_1d_to_3d"`
+	ThreeToSevenDays int    `json:"from_3d_to_7d"`
+	OverSevenDays    int    `json:"over_7d"`
+	Total            int    `json:"total"`
+}
+
+type agingResponse struct {
+	AsOf  time.Time    `json:"as_of"`
+	Teams []prAgingDTO `json:"teams"`
+}
+
+// GetAging handles GET /stats/aging
+func (h *StatsHandler) GetAging(w http.ResponseWriter, r *http.Request) {
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	aging, asOf, err := h.prService.GetOpenPRAging(r.Context())
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	response := agingResponse{
+		AsOf:  asOf,
+		Teams: make([]prAgingDTO, len(aging)),
+	}
+	for i, a := range aging {
+		response.Teams[i] = prAgingDTO{
+			TeamName:         a.TeamName,
+			UnderOneDay:      a.UnderOneDay,
+			OneToThreeDays:   a.OneToThreeDays,
+			ThreeToSevenDays: a.ThreeToSevenDays,
+			OverSevenDays:    a.OverSevenDays,
+			Total:            a.Total(),
+		}
+	}
+
+	if asCSV {
+		rows := make([][]string, len(response.Teams))
+		for i, t := range response.Teams {
+			rows[i] = []string{
+				t.TeamName,
+				strconv.Itoa(t.UnderOneDay),
+				strconv.Itoa(t.OneToThreeDays),
+				strconv.Itoa(t.ThreeToSevenDays),
+				strconv.Itoa(t.OverSevenDays),
+				strconv.Itoa(t.Total),
+			}
+		}
+		h.writeCSV(w, "aging", []string{"team_name", "under_1d", "from_1d_to_3d", "from_3d_to_7d", "over_7d", "total"}, rows)
+		return
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type reviewPairsResponse struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Authors   []string  `json:"authors"`
+	Reviewers []string  `json:"reviewers"`
+	Counts    [][]int   `json:"counts"`
+}
+
+// GetReviewPairs handles GET /stats/pairs?from=...&to=...&team_name=...
+// counts[i][j] is how often reviewers[j] was assigned to PRs by authors[i].
+func (h *StatsHandler) GetReviewPairs(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	matrix, err := h.prService.GetReviewMatrix(r.Context(), from, to, r.URL.Query().Get("team_name"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if asCSV {
+		// One row per author, one column per reviewer
+		rows := make([][]string, len(matrix.Authors))
+		for i, author := range matrix.Authors {
+			rows[i] = make([]string, 0, len(matrix.Reviewers)+1)
+			rows[i] = append(rows[i], author)
+			for _, count := range matrix.Counts[i] {
+				rows[i] = append(rows[i], strconv.Itoa(count))
+			}
+		}
+		h.writeCSV(w, "pairs", append([]string{"author_id"}, matrix.Reviewers...), rows)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Error("failed to encode response", zap.Error(err))
+	response := reviewPairsResponse{
+		From:      from,
+		To:        to,
+		Authors:   matrix.Authors,
+		Reviewers: matrix.Reviewers,
+		Counts:    matrix.Counts,
 	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type reassignmentStatsDTO struct {
+	UserID   string `json:"user_id,omitempty"`
+	TeamName string `json:"team_name,omitempty"`
+	Total    int    `json:"total"`
+	Replaced int    `json:"replaced"`
+	Closed   int    `json:"closed"`
+}
+
+type reassignmentStatsResponse struct {
+	From   time.Time              `json:"from"`
+	To     time.Time              `json:"to"`
+	ByUser []reassignmentStatsDTO `json:"by_user"`
+	ByTeam []reassignmentStatsDTO `json:"by_team"`
+}
+
+// GetReassignmentStats handles GET /stats/reassignments?from=...&to=...
+func (h *StatsHandler) GetReassignmentStats(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	byUser, byTeam, err := h.prService.GetReassignmentStats(r.Context(), from, to)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if asCSV {
+		rows := reassignmentRows("by_user", byUser)
+		rows = append(rows, reassignmentRows("by_team", byTeam)...)
+		h.writeCSV(w, "reassignments", []string{"group", "key", "total", "replaced", "closed"}, rows)
+		return
+	}
+
+	response := reassignmentStatsResponse{
+		From:   from,
+		To:     to,
+		ByUser: make([]reassignmentStatsDTO, len(byUser)),
+		ByTeam: make([]reassignmentStatsDTO, len(byTeam)),
+	}
+	for i, s := range byUser {
+		response.ByUser[i] = mapReassignmentStats(s)
+		response.ByUser[i].UserID = s.Key
+	}
+	for i, s := range byTeam {
+		response.ByTeam[i] = mapReassignmentStats(s)
+		response.ByTeam[i].TeamName = s.Key
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+func mapReassignmentStats(s domain.ReassignmentStats) reassignmentStatsDTO {
+	return reassignmentStatsDTO{
+		Total:    s.Total,
+		Replaced: s.Replaced(),
+		Closed:   s.Closed,
+	}
+}
+
+type reviewerWorkloadDTO struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	OpenReviews int     `json:"open_reviews"`
+	Capacity    int     `json:"capacity"`
+	Utilization float64 `json:"utilization_percent"`
+}
+
+type workloadResponse struct {
+	Users []reviewerWorkloadDTO `json:"users"`
+}
+
+// GetWorkload handles GET /stats/workload
+func (h *StatsHandler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	workloads, err := h.prService.GetWorkload(r.Context())
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if asCSV {
+		rows := make([][]string, len(workloads))
+		for i, wl := range workloads {
+			rows[i] = []string{
+				wl.UserID,
+				wl.Username,
+				strconv.Itoa(wl.OpenReviews),
+				strconv.Itoa(wl.Capacity),
+				formatFloat(wl.Utilization),
+			}
+		}
+		h.writeCSV(w, "workload", []string{"user_id", "username", "open_reviews", "capacity", "utilization_percent"}, rows)
+		return
+	}
+
+	response := workloadResponse{
+		Users: make([]reviewerWorkloadDTO, len(workloads)),
+	}
+	for i, wl := range workloads {
+		response.Users[i] = reviewerWorkloadDTO{
+			UserID:      wl.UserID,
+			Username:    wl.Username,
+			OpenReviews: wl.OpenReviews,
+			Capacity:    wl.Capacity,
+			Utilization: wl.Utilization,
+		}
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type dailyStatsDTO struct {
+	Day           string `json:"day"`
+	TeamName      string `json:"team_name"`
+	Assignments   int    `json:"assignments"`
+	Merges        int    `json:"merges"`
+	Reassignments int    `json:"reassignments"`
+}
+
+type dailyStatsResponse struct {
+	From time.Time       `json:"from"`
+	To   time.Time       `json:"to"`
+	Days []dailyStatsDTO `json:"days"`
+}
+
+// GetDailyStats handles GET /stats/daily?from=...&to=...&team_name=...
+// Results come from the nightly rollup, so the current day is not included.
+func (h *StatsHandler) GetDailyStats(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	days, err := h.dailyService.GetDailyStats(r.Context(), from, to, r.URL.Query().Get("team_name"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	response := dailyStatsResponse{
+		From: from,
+		To:   to,
+		Days: make([]dailyStatsDTO, len(days)),
+	}
+	for i, d := range days {
+		response.Days[i] = dailyStatsDTO{
+			Day:           d.Day.Format(time.DateOnly),
+			TeamName:      d.TeamName,
+			Assignments:   d.Assignments,
+			Merges:        d.Merges,
+			Reassignments: d.Reassignments,
+		}
+	}
+
+	if asCSV {
+		rows := make([][]string, len(response.Days))
+		for i, d := range response.Days {
+			rows[i] = []string{d.Day, d.TeamName, strconv.Itoa(d.Assignments), strconv.Itoa(d.Merges), strconv.Itoa(d.Reassignments)}
+		}
+		h.writeCSV(w, "daily", []string{"day", "team_name", "assignments", "merges", "reassignments"}, rows)
+		return
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type latencyStatsDTO struct {
+	TeamName   string  `json:"team_name,omitempty"`
+	UserID     string  `json:"user_id,omitempty"`
+	AuthorID   string  `json:"author_id,omitempty"`
+	Repository string  `json:"repository,omitempty"`
+	WeekStart  string  `json:"week_start,omitempty"`
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+}
+
+type timeToReviewResponse struct {
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	ByTeam       []latencyStatsDTO `json:"by_team"`
+	ByUser       []latencyStatsDTO `json:"by_user"`
+	ByRepository []latencyStatsDTO `json:"by_repository"`
+}
+
+// GetTimeToReview handles GET /stats/timeToReview?from=...&to=...&repository=...
+func (h *StatsHandler) GetTimeToReview(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	byTeam, byUser, byRepository, err := h.prService.GetTimeToReviewStats(r.Context(), from, to, r.URL.Query().Get("repository"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if asCSV {
+		rows := append(latencyRows("by_team", byTeam), latencyRows("by_user", byUser)...)
+		rows = append(rows, latencyRows("by_repository", byRepository)...)
+		h.writeCSV(w, "time_to_review", latencyCSVHeader, rows)
+		return
+	}
+
+	response := timeToReviewResponse{
+		From:         from,
+		To:           to,
+		ByTeam:       make([]latencyStatsDTO, len(byTeam)),
+		ByUser:       make([]latencyStatsDTO, len(byUser)),
+		ByRepository: make([]latencyStatsDTO, len(byRepository)),
+	}
+	for i, s := range byTeam {
+		response.ByTeam[i] = mapLatencyStats(s)
+		response.ByTeam[i].TeamName = s.Key
+	}
+	for i, s := range byUser {
+		response.ByUser[i] = mapLatencyStats(s)
+		response.ByUser[i].UserID = s.Key
+	}
+	for i, s := range byRepository {
+		response.ByRepository[i] = mapLatencyStats(s)
+		response.ByRepository[i].Repository = s.Key
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type timeToMergeResponse struct {
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	ByTeam       []latencyStatsDTO `json:"by_team"`
+	ByAuthor     []latencyStatsDTO `json:"by_author"`
+	ByRepository []latencyStatsDTO `json:"by_repository"`
+	ByWeek       []latencyStatsDTO `json:"by_week"`
+}
+
+// GetTimeToMerge handles GET /stats/timeToMerge?from=...&to=...&repository=...
+func (h *StatsHandler) GetTimeToMerge(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	byTeam, byAuthor, byRepository, byWeek, err := h.prService.GetTimeToMergeStats(
+		r.Context(), from, to, r.URL.Query().Get("repository"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if asCSV {
+		rows := latencyRows("by_team", byTeam)
+		rows = append(rows, latencyRows("by_author", byAuthor)...)
+		rows = append(rows, latencyRows("by_repository", byRepository)...)
+		rows = append(rows, latencyRows("by_week", byWeek)...)
+		h.writeCSV(w, "time_to_merge", latencyCSVHeader, rows)
+		return
+	}
+
+	response := timeToMergeResponse{
+		From:         from,
+		To:           to,
+		ByTeam:       make([]latencyStatsDTO, len(byTeam)),
+		ByAuthor:     make([]latencyStatsDTO, len(byAuthor)),
+		ByRepository: make([]latencyStatsDTO, len(byRepository)),
+		ByWeek:       make([]latencyStatsDTO, len(byWeek)),
+	}
+	for i, s := range byTeam {
+		response.ByTeam[i] = mapLatencyStats(s)
+		response.ByTeam[i].TeamName = s.Key
+	}
+	for i, s := range byAuthor {
+		response.ByAuthor[i] = mapLatencyStats(s)
+		response.ByAuthor[i].AuthorID = s.Key
+	}
+	for i, s := range byRepository {
+		response.ByRepository[i] = mapLatencyStats(s)
+		response.ByRepository[i].Repository = s.Key
+	}
+	for i, s := range byWeek {
+		response.ByWeek[i] = mapLatencyStats(s)
+		response.ByWeek[i].WeekStart = s.Key
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+type authorStatsDTO struct {
+	AuthorID             string   `json:"author_id,omitempty"`
+	TeamName             string   `json:"team_name,omitempty"`
+	Repository           string   `json:"repository,omitempty"`
+	PRsCreated           int      `json:"prs_created"`
+	PRsMerged            int      `json:"prs_merged"`
+	MergeRate            float64  `json:"merge_rate"`
+	AvgReviewers         float64  `json:"avg_reviewers"`
+	AvgReviewWaitSeconds *float64 `json:"avg_review_wait_seconds"`
+}
+
+type authorStatsResponse struct {
+	From         time.Time        `json:"from"`
+	To           time.Time        `json:"to"`
+	ByAuthor     []authorStatsDTO `json:"by_author"`
+	ByTeam       []authorStatsDTO `json:"by_team"`
+	ByRepository []authorStatsDTO `json:"by_repository"`
+}
+
+// GetAuthorStats handles GET /stats/authors?from=...&to=...&repository=...
+func (h *StatsHandler) GetAuthorStats(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseStatsWindow(r)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	asCSV, ok := wantsCSV(r)
+	if !ok {
+		middleware.WriteErrorResponse(w, errUnsupportedFormat, h.logger)
+		return
+	}
+
+	byAuthor, byTeam, byRepository, err := h.prService.GetAuthorStats(r.Context(), from, to, r.URL.Query().Get("repository"))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if asCSV {
+		rows := authorStatsRows("by_author", byAuthor)
+		rows = append(rows, authorStatsRows("by_team", byTeam)...)
+		rows = append(rows, authorStatsRows("by_repository", byRepository)...)
+		h.writeCSV(w, "authors", authorStatsCSVHeader, rows)
+		return
+	}
+
+	response := authorStatsResponse{
+		From:         from,
+		To:           to,
+		ByAuthor:     make([]authorStatsDTO, len(byAuthor)),
+		ByTeam:       make([]authorStatsDTO, len(byTeam)),
+		ByRepository: make([]authorStatsDTO, len(byRepository)),
+	}
+	for i, s := range byAuthor {
+		response.ByAuthor[i] = mapAuthorStats(s)
+		response.ByAuthor[i].AuthorID = s.Key
+	}
+	for i, s := range byTeam {
+		response.ByTeam[i] = mapAuthorStats(s)
+		response.ByTeam[i].TeamName = s.Key
+	}
+	for i, s := range byRepository {
+		response.ByRepository[i] = mapAuthorStats(s)
+		response.ByRepository[i].Repository = s.Key
+	}
+
+	writeNegotiated(w, r, response, h.logger)
+}
+
+func mapAuthorStats(s domain.AuthorStats) authorStatsDTO {
+	return authorStatsDTO{
+		PRsCreated:           s.PRsCreated,
+		PRsMerged:            s.PRsMerged,
+		MergeRate:            s.MergeRate(),
+		AvgReviewers:         s.AvgReviewers,
+		AvgReviewWaitSeconds: s.AvgReviewWaitSeconds,
+	}
+}
+
+func mapLatencyStats(s domain.LatencyStats) latencyStatsDTO {
+	return latencyStatsDTO{
+		Count:      s.Count,
+		P50Seconds: s.P50,
+		P90Seconds: s.P90,
+		P99Seconds: s.P99,
+	}
+}
+
+// parseStatsWindow reads the optional RFC3339 from/to query parameters.
+// to defaults to now and from to defaultStatsWindow before to.
+func parseStatsWindow(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if raw := strings.TrimSpace(r.URL.Query().Get("to")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, domain.NewValidationError("to", "must be an RFC 3339 timestamp")
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultStatsWindow)
+	if raw := strings.TrimSpace(r.URL.Query().Get("from")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, domain.NewValidationError("from", "must be an RFC 3339 timestamp")
+		}
+		from = parsed
+	}
+
+	return from, to, nil
 }
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// errUnsupportedFormat reports a stats format other than csv or json
+var errUnsupportedFormat = domain.NewValidationError("format", "must be csv or json")
+
+// wantsCSV reports whether a stats endpoint should answer with CSV. The format
+// query parameter (csv or json) wins over the Accept header; ok is false for an
+// unknown format.
+func wantsCSV(r *http.Request) (asCSV bool, ok bool) {
+	if raw := strings.TrimSpace(r.URL.Query().Get("format")); raw != "" {
+		switch strings.ToLower(raw) {
+		case "csv":
+			return true, true
+		case "json":
+			return false, true
+		default:
+			return false, false
+		}
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == "text/csv" {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// writeCSV writes a header row followed by rows as a downloadable CSV file
+func (h *StatsHandler) writeCSV(w http.ResponseWriter, name string, header []string, rows [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		h.logger.Error("failed to write csv", zap.Error(err))
+		return
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		h.logger.Error("failed to write csv", zap.Error(err))
+	}
+}
+
+// countRows flattens a count map into (group, key, count) rows ordered by key
+func countRows(group string, counts map[string]int) [][]string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, []string{group, key, strconv.Itoa(counts[key])})
+	}
+	return rows
+}
+
+// keyCountRows flattens counts into (group, key, count) rows, keeping their order
+func keyCountRows(group string, counts []domain.KeyCount) [][]string {
+	rows := make([][]string, 0, len(counts))
+	for _, c := range counts {
+		rows = append(rows, []string{group, c.Key, strconv.Itoa(c.Count)})
+	}
+	return rows
+}
+
+// latencyRows flattens latency stats into (group, key, count, p50, p90, p99) rows
+func latencyRows(group string, stats []domain.LatencyStats) [][]string {
+	rows := make([][]string, 0, len(stats))
+	for _, s := range stats {
+		rows = append(rows, []string{
+			group,
+			s.Key,
+			strconv.Itoa(s.Count),
+			formatFloat(s.P50),
+			formatFloat(s.P90),
+			formatFloat(s.P99),
+		})
+	}
+	return rows
+}
+
+// reassignmentRows flattens reassignment stats into (group, key, total, replaced, closed) rows
+func reassignmentRows(group string, stats []domain.ReassignmentStats) [][]string {
+	rows := make([][]string, 0, len(stats))
+	for _, s := range stats {
+		rows = append(rows, []string{
+			group,
+			s.Key,
+			strconv.Itoa(s.Total),
+			strconv.Itoa(s.Replaced()),
+			strconv.Itoa(s.Closed),
+		})
+	}
+	return rows
+}
+
+// authorStatsRows flattens author stats into rows matching authorStatsCSVHeader;
+// an unknown review wait is left empty
+func authorStatsRows(group string, stats []domain.AuthorStats) [][]string {
+	rows := make([][]string, 0, len(stats))
+	for _, s := range stats {
+		wait := ""
+		if s.AvgReviewWaitSeconds != nil {
+			wait = formatFloat(*s.AvgReviewWaitSeconds)
+		}
+		rows = append(rows, []string{
+			group,
+			s.Key,
+			strconv.Itoa(s.PRsCreated),
+			strconv.Itoa(s.PRsMerged),
+			formatFloat(s.MergeRate()),
+			formatFloat(s.AvgReviewers),
+			wait,
+		})
+	}
+	return rows
+}
+
+var authorStatsCSVHeader = []string{
+	"group", "key", "prs_created", "prs_merged", "merge_rate", "avg_reviewers", "avg_review_wait_seconds",
+}
+
+var latencyCSVHeader = []string{"group", "key", "count", "p50_seconds", "p90_seconds", "p99_seconds"}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -3,20 +3,43 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"pr-service/internal/app/middleware"
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
 
 	"go.uber.org/zap"
 )
 
 type teamService interface {
-	CreateTeam(ctx context.Context, teamName string, members []domain.User) (domain.Team, error)
-	GetTeam(ctx context.Context, teamName string) (domain.Team, error)
+	CreateTeam(ctx context.Context, teamName, parentTeamName string, members []domain.User) (domain.Team, error)
+	UpsertTeam(ctx context.Context, teamName, parentTeamName string, members []domain.User) (domain.Team, bool, error)
+	GetTeam(ctx context.Context, teamName string, flatten bool) (domain.Team, error)
+	SetParentTeam(ctx context.Context, teamName, parentTeamName string) (domain.Team, error)
+	AddMember(ctx context.Context, userID, username, teamName string, isActive *bool, role domain.UserRole) (domain.User, bool, error)
+	DeleteTeam(ctx context.Context, teamName, targetTeam string) (domain.Team, []domain.Reassignment, error)
+	ListTeams(ctx context.Context, limit, offset int) ([]domain.TeamSummary, int, error)
+	ListDeletedTeams(ctx context.Context, page pagination.Page) (pagination.Result[domain.Team], error)
+	RestoreTeam(ctx context.Context, teamName string) (domain.Team, error)
+	RenameTeam(ctx context.Context, oldName, newName string) (domain.Team, error)
+	ImportTeam(ctx context.Context, teamName string, format domain.RosterFormat, data io.Reader) (domain.RosterImport, error)
+	MergeTeams(ctx context.Context, sourceTeam, targetTeam string, dryRun bool) (domain.TeamMerge, error)
+	GetAuditLog(ctx context.Context, teamName string, limit, offset int) ([]domain.MembershipEvent, int, error)
+	GetSettings(ctx context.Context, teamName string) (domain.TeamSettings, error)
+	UpdateSettings(ctx context.Context, settings domain.TeamSettings) (domain.TeamSettings, error)
 }
 
+// maxRosterBytes limits the size of an uploaded roster
+const maxRosterBytes = 1 << 20
+
 // TeamHandler handles team-related HTTP requests
 type TeamHandler struct {
 	service teamService
@@ -37,22 +60,152 @@ type TeamMemberDTO struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Role     string `json:"role,omitempty"`
+	TeamName string `json:"team_name,omitempty"`
 }
 
 type TeamDTO struct {
-	TeamName string          `json:"team_name"`
-	Members  []TeamMemberDTO `json:"members"`
+	TeamName       string          `json:"team_name"`
+	ParentTeamName string          `json:"parent_team_name,omitempty"`
+	Members        []TeamMemberDTO `json:"members"`
+}
+
+type AddMemberRequest struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive *bool  `json:"is_active,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+type SetParentTeamRequest struct {
+	TeamName       string `json:"team_name"`
+	ParentTeamName string `json:"parent_team_name"`
+}
+
+// NotificationChannelDTO describes a team chat channel; WebhookURL is a secret
+// and only returned when the settings are updated
+type NotificationChannelDTO struct {
+	Type       string `json:"type"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+type TeamSettingsDTO struct {
+	TeamName            string                  `json:"team_name"`
+	NotificationChannel *NotificationChannelDTO `json:"notification_channel"`
+}
+
+type teamSettingsResponse struct {
+	Settings TeamSettingsDTO `json:"settings"`
 }
 
 type createTeamResponse struct {
 	Team TeamDTO `json:"team"`
 }
 
-// AddTeam handles POST /team/add
+type TeamSummaryDTO struct {
+	TeamName          string `json:"team_name"`
+	MemberCount       int    `json:"member_count"`
+	ActiveMemberCount int    `json:"active_member_count"`
+}
+
+type listTeamsResponse struct {
+	Teams []TeamSummaryDTO `json:"teams"`
+	Total int              `json:"total"`
+}
+
+type DeletedTeamDTO struct {
+	TeamName       string    `json:"team_name"`
+	ParentTeamName string    `json:"parent_team_name,omitempty"`
+	DeletedAt      time.Time `json:"deleted_at"`
+}
+
+type listDeletedTeamsResponse struct {
+	Teams      []DeletedTeamDTO `json:"teams"`
+	Total      int              `json:"total"`
+	NextCursor string           `json:"next_cursor"`
+}
+
+type RestoreTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+type MembershipEventDTO struct {
+	ID           int64  `json:"id"`
+	TeamName     string `json:"team_name"`
+	UserID       string `json:"user_id"`
+	Action       string `json:"action"`
+	FromTeamName string `json:"from_team_name,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type auditLogResponse struct {
+	Events []MembershipEventDTO `json:"events"`
+	Total  int                  `json:"total"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName       string `json:"team_name"`
+	TargetTeamName string `json:"target_team_name,omitempty"`
+}
+
+type deleteTeamResponse struct {
+	TeamName       string            `json:"team_name"`
+	TargetTeamName string            `json:"target_team_name,omitempty"`
+	Members        []UserResponse    `json:"members"`
+	Reassignments  []reassignmentDTO `json:"reassignments"`
+	ClosedReviews  []closedReviewDTO `json:"closed_reviews"`
+}
+
+type MergeTeamsRequest struct {
+	SourceTeamName string `json:"source_team_name"`
+	TargetTeamName string `json:"target_team_name"`
+	DryRun         bool   `json:"dry_run"`
+}
+
+type mergeTeamsResponse struct {
+	SourceTeamName      string   `json:"source_team_name"`
+	TargetTeamName      string   `json:"target_team_name"`
+	DryRun              bool     `json:"dry_run"`
+	MovedUserIDs        []string `json:"moved_user_ids"`
+	SharedUserIDs       []string `json:"shared_user_ids"`
+	ReparentedTeams     []string `json:"reparented_teams"`
+	MovedPullRequestIDs []string `json:"moved_pull_request_ids"`
+	Team                TeamDTO  `json:"team"`
+}
+
+type importTeamResponse struct {
+	Team           TeamDTO  `json:"team"`
+	CreatedUserIDs []string `json:"created_user_ids"`
+	UpdatedUserIDs []string `json:"updated_user_ids"`
+}
+
+type rosterRowErrorDTO struct {
+	Line    int    `json:"line"`
+	UserID  string `json:"user_id,omitempty"`
+	Message string `json:"message"`
+}
+
+type rosterErrorResponse struct {
+	Error     middleware.ErrorDetail `json:"error"`
+	RowErrors []rosterRowErrorDTO    `json:"row_errors"`
+}
+
+type closedReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// AddTeam handles POST /team/add?upsert=...
 func (h *TeamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 	var req TeamDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
 		return
 	}
 
@@ -69,10 +222,27 @@ func (h *TeamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 		userID := strings.TrimSpace(m.UserID)
 		username := strings.TrimSpace(m.Username)
 		members[i] = domain.NewUser(userID, username, teamName, m.IsActive)
+		if role := strings.TrimSpace(m.Role); role != "" {
+			members[i].Role = domain.UserRole(role)
+		}
+	}
+
+	upsert, err := parseBoolQuery(r, "upsert")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
 	}
 
 	// Call service
-	createdTeam, err := h.service.CreateTeam(r.Context(), teamName, members)
+	var (
+		createdTeam domain.Team
+		created     = true
+	)
+	if upsert {
+		createdTeam, created, err = h.service.UpsertTeam(r.Context(), teamName, req.ParentTeamName, members)
+	} else {
+		createdTeam, err = h.service.CreateTeam(r.Context(), teamName, req.ParentTeamName, members)
+	}
 	if err != nil {
 		middleware.WriteErrorResponse(w, err, h.logger)
 		return
@@ -80,26 +250,505 @@ func (h *TeamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 
 	resp := createTeamResponse{Team: mapTeamToDTO(createdTeam)}
 
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(resp)
 }
 
-// GetTeam handles GET /team/get?team_name=...
+// GetTeam handles GET /team/get?team_name=...&flatten=...
 func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	teamName := r.URL.Query().Get("team_name")
 	if teamName == "" {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, domain.NewValidationError("team_name", "must not be empty"), h.logger)
+		return
+	}
+
+	flatten, err := parseBoolQuery(r, "flatten")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
 		return
 	}
 
-	team, err := h.service.GetTeam(r.Context(), teamName)
+	team, err := h.service.GetTeam(r.Context(), teamName, flatten)
 	if err != nil {
 		middleware.WriteErrorResponse(w, err, h.logger)
 		return
 	}
 
 	resp := mapTeamToDTO(team)
+	if flatten {
+		// Flattened rosters mix teams, so tell members apart by their own team
+		for i, m := range team.Members {
+			resp.Members[i].TeamName = m.TeamName
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// MergeTeams handles POST /team/merge
+func (h *TeamHandler) MergeTeams(w http.ResponseWriter, r *http.Request) {
+	var req MergeTeamsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.SourceTeamName = strings.TrimSpace(req.SourceTeamName)
+	req.TargetTeamName = strings.TrimSpace(req.TargetTeamName)
+	var v domain.Validator
+	v.Required(req.SourceTeamName, "source_team_name")
+	v.Required(req.TargetTeamName, "target_team_name")
+	if err := v.Err(); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	merge, err := h.service.MergeTeams(r.Context(), req.SourceTeamName, req.TargetTeamName, req.DryRun)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := mergeTeamsResponse{
+		SourceTeamName:      merge.SourceTeam,
+		TargetTeamName:      merge.TargetTeam,
+		DryRun:              merge.DryRun,
+		MovedUserIDs:        merge.MovedUserIDs,
+		SharedUserIDs:       merge.SharedUserIDs,
+		ReparentedTeams:     merge.ReparentedTeams,
+		MovedPullRequestIDs: merge.MovedPRIDs,
+		Team:                mapTeamToDTO(merge.Team),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ImportTeam handles POST /team/import?team_name=...
+// The roster format comes from Content-Type (text/csv or application/x-ndjson)
+// or from the format query parameter.
+func (h *TeamHandler) ImportTeam(w http.ResponseWriter, r *http.Request) {
+	teamName := strings.TrimSpace(r.URL.Query().Get("team_name"))
+	format, ok := rosterFormat(r)
+	var v domain.Validator
+	v.Required(teamName, "team_name")
+	v.Check(ok, "format", "must be csv or ndjson")
+	if err := v.Err(); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxRosterBytes)
+	imported, err := h.service.ImportTeam(r.Context(), teamName, format, body)
+	if err != nil {
+		var rosterErr *domain.RosterError
+		if errors.As(err, &rosterErr) {
+			h.writeRosterError(w, rosterErr)
+			return
+		}
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	status := http.StatusOK
+	if imported.TeamCreated {
+		status = http.StatusCreated
+	}
+
+	resp := importTeamResponse{
+		Team:           mapTeamToDTO(imported.Team),
+		CreatedUserIDs: imported.CreatedUserIDs,
+		UpdatedUserIDs: imported.UpdatedUserIDs,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *TeamHandler) writeRosterError(w http.ResponseWriter, rosterErr *domain.RosterError) {
+	resp := rosterErrorResponse{
+		Error: middleware.ErrorDetail{
+			Code:    string(domain.ErrorCodeInvalidArgument),
+			Message: rosterErr.Error(),
+		},
+		RowErrors: make([]rosterRowErrorDTO, len(rosterErr.Rows)),
+	}
+	for i, row := range rosterErr.Rows {
+		resp.RowErrors[i] = rosterRowErrorDTO{
+			Line:    row.Line,
+			UserID:  row.UserID,
+			Message: row.Message,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// rosterFormat picks the roster encoding from the format query parameter or Content-Type
+func rosterFormat(r *http.Request) (domain.RosterFormat, bool) {
+	if raw := strings.TrimSpace(r.URL.Query().Get("format")); raw != "" {
+		format := domain.RosterFormat(strings.ToLower(raw))
+		return format, format.IsValid()
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", false
+	}
+	switch mediaType {
+	case "text/csv":
+		return domain.RosterFormatCSV, true
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return domain.RosterFormatNDJSON, true
+	default:
+		return "", false
+	}
+}
+
+// AddMember handles POST /users/add
+func (h *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	var req AddMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.UserID = strings.TrimSpace(req.UserID)
+	req.Username = strings.TrimSpace(req.Username)
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	role := domain.UserRole(strings.TrimSpace(req.Role))
+	var v domain.Validator
+	v.Required(req.UserID, "user_id")
+	v.Required(req.Username, "username")
+	v.Required(req.TeamName, "team_name")
+	checkRole(&v, req.Role, "role")
+	if err := v.Err(); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	user, created, err := h.service.AddMember(r.Context(), req.UserID, req.Username, req.TeamName, req.IsActive, role)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	resp := userEnvelope{User: mapUserToResponse(user)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetParentTeam handles POST /team/setParent
+func (h *TeamHandler) SetParentTeam(w http.ResponseWriter, r *http.Request) {
+	var req SetParentTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	if req.TeamName == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("team_name", "must not be empty"), h.logger)
+		return
+	}
+
+	team, err := h.service.SetParentTeam(r.Context(), req.TeamName, req.ParentTeamName)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := createTeamResponse{Team: mapTeamToDTO(team)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetSettings handles GET /team/settings?team_name=...
+func (h *TeamHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("team_name", "must not be empty"), h.logger)
+		return
+	}
+
+	settings, err := h.service.GetSettings(r.Context(), teamName)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := teamSettingsResponse{Settings: mapTeamSettingsToDTO(settings, false)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetSettings handles POST /team/setSettings; a null notification_channel removes the channel
+func (h *TeamHandler) SetSettings(w http.ResponseWriter, r *http.Request) {
+	var req TeamSettingsDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	if req.TeamName == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("team_name", "must not be empty"), h.logger)
+		return
+	}
+
+	settings := domain.TeamSettings{TeamName: req.TeamName}
+	if ch := req.NotificationChannel; ch != nil {
+		settings.NotificationChannel = &domain.NotificationChannel{
+			Type:       domain.NotificationChannelType(strings.ToLower(strings.TrimSpace(ch.Type))),
+			WebhookURL: ch.WebhookURL,
+		}
+	}
+
+	settings, err := h.service.UpdateSettings(r.Context(), settings)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := teamSettingsResponse{Settings: mapTeamSettingsToDTO(settings, true)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func mapTeamSettingsToDTO(settings domain.TeamSettings, withWebhookURL bool) TeamSettingsDTO {
+	dto := TeamSettingsDTO{TeamName: settings.TeamName}
+	if ch := settings.NotificationChannel; ch != nil {
+		dto.NotificationChannel = &NotificationChannelDTO{Type: string(ch.Type)}
+		if withWebhookURL {
+			dto.NotificationChannel.WebhookURL = ch.WebhookURL
+		}
+	}
+	return dto
+}
+
+// ListTeams handles GET /team/list?limit=...&offset=...
+func (h *TeamHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntQuery(r, "limit")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	offset, err := parseIntQuery(r, "offset")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	teams, total, err := h.service.ListTeams(r.Context(), limit, offset)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := listTeamsResponse{
+		Teams: make([]TeamSummaryDTO, len(teams)),
+		Total: total,
+	}
+	for i, t := range teams {
+		resp.Teams[i] = TeamSummaryDTO{
+			TeamName:          t.TeamName,
+			MemberCount:       t.MemberCount,
+			ActiveMemberCount: t.ActiveMemberCount,
+		}
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// GetAuditLog handles GET /team/auditLog?team_name=...&limit=...&offset=...
+func (h *TeamHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	teamName := strings.TrimSpace(r.URL.Query().Get("team_name"))
+	if teamName == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("team_name", "must not be empty"), h.logger)
+		return
+	}
+	limit, err := parseIntQuery(r, "limit")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	offset, err := parseIntQuery(r, "offset")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	events, total, err := h.service.GetAuditLog(r.Context(), teamName, limit, offset)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := auditLogResponse{
+		Events: make([]MembershipEventDTO, len(events)),
+		Total:  total,
+	}
+	for i, e := range events {
+		resp.Events[i] = MembershipEventDTO{
+			ID:           e.ID,
+			TeamName:     e.TeamName,
+			UserID:       e.UserID,
+			Action:       string(e.Action),
+			FromTeamName: e.FromTeamName,
+			CreatedAt:    e.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// RenameTeam handles POST /team/rename
+func (h *TeamHandler) RenameTeam(w http.ResponseWriter, r *http.Request) {
+	var req RenameTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	req.NewTeamName = strings.TrimSpace(req.NewTeamName)
+	var v domain.Validator
+	v.Required(req.TeamName, "team_name")
+	v.Required(req.NewTeamName, "new_team_name")
+	if err := v.Err(); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	team, err := h.service.RenameTeam(r.Context(), req.TeamName, req.NewTeamName)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := createTeamResponse{Team: mapTeamToDTO(team)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteTeam handles POST /team/delete
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	var req DeleteTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	req.TargetTeamName = strings.TrimSpace(req.TargetTeamName)
+	if req.TeamName == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("team_name", "must not be empty"), h.logger)
+		return
+	}
+
+	team, reassignments, err := h.service.DeleteTeam(r.Context(), req.TeamName, req.TargetTeamName)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := deleteTeamResponse{
+		TeamName:       team.TeamName,
+		TargetTeamName: req.TargetTeamName,
+		Members:        make([]UserResponse, len(team.Members)),
+	}
+
+	for i, member := range team.Members {
+		resp.Members[i] = mapUserToResponse(member)
+	}
+
+	resp.Reassignments, resp.ClosedReviews = mapReassignmentReport(reassignments)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListDeletedTeams handles GET /team/deleted?limit=...&cursor=...&order=...
+func (h *TeamHandler) ListDeletedTeams(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.ParseRequest(r, pagination.OrderDesc)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	result, err := h.service.ListDeletedTeams(r.Context(), page)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := listDeletedTeamsResponse{
+		Teams:      make([]DeletedTeamDTO, len(result.Items)),
+		Total:      result.Total,
+		NextCursor: result.NextCursor,
+	}
+	for i, t := range result.Items {
+		resp.Teams[i] = DeletedTeamDTO{
+			TeamName:       t.TeamName,
+			ParentTeamName: t.ParentTeamName,
+		}
+		if t.DeletedAt != nil {
+			resp.Teams[i].DeletedAt = *t.DeletedAt
+		}
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// RestoreTeam handles POST /team/restore
+func (h *TeamHandler) RestoreTeam(w http.ResponseWriter, r *http.Request) {
+	var req RestoreTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	if req.TeamName == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("team_name", "must not be empty"), h.logger)
+		return
+	}
+
+	team, err := h.service.RestoreTeam(r.Context(), req.TeamName)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := createTeamResponse{Team: mapTeamToDTO(team)}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -113,31 +762,66 @@ func mapTeamToDTO(team domain.Team) TeamDTO {
 			UserID:   m.UserID,
 			Username: m.Username,
 			IsActive: m.IsActive,
+			Role:     string(m.Role),
 		}
 	}
 
 	return TeamDTO{
-		TeamName: team.TeamName,
-		Members:  members,
+		TeamName:       team.TeamName,
+		ParentTeamName: team.ParentTeamName,
+		Members:        members,
 	}
 }
 
 func validateTeamRequest(req TeamDTO) error {
-	teamName := strings.TrimSpace(req.TeamName)
-	if teamName == "" {
-		return domain.ErrInvalidArgument
+	var v domain.Validator
+	v.Required(req.TeamName, "team_name")
+	v.Check(len(req.Members) > 0, "members", "must not be empty")
+
+	for i, member := range req.Members {
+		field := fmt.Sprintf("members[%d]", i)
+		v.Required(member.UserID, field+".user_id")
+		v.Required(member.Username, field+".username")
+		checkRole(&v, member.Role, field+".role")
+	}
+
+	return v.Err()
+}
+
+// checkRole records a violation when role is set to an unknown role
+func checkRole(v *domain.Validator, role, field string) {
+	role = strings.TrimSpace(role)
+	v.Check(role == "" || domain.UserRole(role).IsValid(), field,
+		fmt.Sprintf("must be %s or %s", domain.UserRoleMember, domain.UserRoleLead))
+}
+
+// errInvalidBody reports a request body that is not the expected JSON object
+var errInvalidBody = domain.NewValidationError("body", "must be a valid JSON object")
+
+// parseBoolQuery reads an optional boolean query parameter, returning false when absent
+func parseBoolQuery(r *http.Request, name string) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, nil
 	}
 
-	if len(req.Members) == 0 {
-		return domain.ErrInvalidArgument
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, domain.NewValidationError(name, "must be true or false")
 	}
+	return value, nil
+}
 
-	for _, member := range req.Members {
-		if strings.TrimSpace(member.UserID) == "" ||
-			strings.TrimSpace(member.Username) == "" {
-			return domain.ErrInvalidArgument
-		}
+// parseIntQuery reads an optional non-negative integer query parameter, returning 0 when absent
+func parseIntQuery(r *http.Request, name string) (int, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return 0, nil
 	}
 
-	return nil
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, domain.NewValidationError(name, "must be a non-negative integer")
+	}
+	return value, nil
 }
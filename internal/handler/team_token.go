@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+
+	"go.uber.org/zap"
+)
+
+type teamTokenService interface {
+	Issue(ctx context.Context, teamName, name string, ttl time.Duration) (domain.TeamToken, string, error)
+	List(ctx context.Context, teamName string, page pagination.Page) (pagination.Result[domain.TeamToken], error)
+	Revoke(ctx context.Context, id int64) (domain.TeamToken, error)
+}
+
+// TeamTokenHandler handles bearer tokens restricted to one team
+type TeamTokenHandler struct {
+	service teamTokenService
+	logger  *zap.Logger
+}
+
+// NewTeamTokenHandler creates a new team token handler
+func NewTeamTokenHandler(service teamTokenService, logger *zap.Logger) *TeamTokenHandler {
+	return &TeamTokenHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type IssueTeamTokenRequest struct {
+	TeamName  string `json:"team_name"`
+	Name      string `json:"name"`
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+type RevokeTeamTokenRequest struct {
+	ID int64 `json:"id"`
+}
+
+// TeamTokenDTO describes a team token; Token is only returned on issue
+type TeamTokenDTO struct {
+	ID        int64  `json:"id"`
+	TeamName  string `json:"team_name"`
+	Name      string `json:"name"`
+	Token     string `json:"token,omitempty"`
+	CreatedAt string `json:"created_at"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+type teamTokenResponse struct {
+	Token TeamTokenDTO `json:"token"`
+}
+
+type listTeamTokensResponse struct {
+	TeamName   string         `json:"team_name"`
+	Tokens     []TeamTokenDTO `json:"tokens"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+// Issue handles POST /team/tokens/issue
+func (h *TeamTokenHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	var req IssueTeamTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	var ttl time.Duration
+	if raw := strings.TrimSpace(req.ExpiresIn); raw != "" {
+		var err error
+		if ttl, err = time.ParseDuration(raw); err != nil || ttl <= 0 {
+			middleware.WriteErrorResponse(w, domain.NewValidationError("expires_in", "must be a positive duration such as 720h"), h.logger)
+			return
+		}
+	}
+
+	token, secret, err := h.service.Issue(r.Context(), req.TeamName, req.Name, ttl)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := teamTokenResponse{Token: mapTeamTokenToDTO(token)}
+	resp.Token.Token = secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// List handles GET /team/tokens/list?team_name=...&limit=...&cursor=...&order=...
+func (h *TeamTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.ParseRequest(r, pagination.OrderAsc)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	teamName := strings.TrimSpace(r.URL.Query().Get("team_name"))
+	result, err := h.service.List(r.Context(), teamName, page)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := listTeamTokensResponse{
+		TeamName:   teamName,
+		Tokens:     make([]TeamTokenDTO, len(result.Items)),
+		Total:      result.Total,
+		NextCursor: result.NextCursor,
+	}
+	for i, token := range result.Items {
+		resp.Tokens[i] = mapTeamTokenToDTO(token)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Revoke handles POST /team/tokens/revoke
+func (h *TeamTokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	var req RevokeTeamTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+	if req.ID <= 0 {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("id", "must be positive"), h.logger)
+		return
+	}
+
+	token, err := h.service.Revoke(r.Context(), req.ID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(teamTokenResponse{Token: mapTeamTokenToDTO(token)})
+}
+
+func mapTeamTokenToDTO(token domain.TeamToken) TeamTokenDTO {
+	dto := TeamTokenDTO{
+		ID:        token.ID,
+		TeamName:  token.TeamName,
+		Name:      token.Name,
+		CreatedAt: token.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if token.ExpiresAt != nil {
+		dto.ExpiresAt = token.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
@@ -3,47 +3,105 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"pr-service/internal/app/middleware"
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
 
 	"go.uber.org/zap"
 )
 
 type userService interface {
 	SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error)
+	SetRole(ctx context.Context, userID string, role domain.UserRole) (domain.User, error)
+	DeleteUser(ctx context.Context, userID string) (domain.User, []domain.Reassignment, error)
 	GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	GetPendingReviews(ctx context.Context, userID string) ([]domain.ReviewAssignment, error)
 	BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (domain.Team, []string, []domain.Reassignment, error)
+	BulkActivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (domain.Team, []string, error)
+	Heartbeat(ctx context.Context, userID string) (domain.User, error)
+	ListDormantUsers(ctx context.Context, dormantAfter time.Duration, limit, offset int) ([]domain.User, int, time.Duration, error)
+	ListDeletedUsers(ctx context.Context, page pagination.Page) (pagination.Result[domain.User], error)
+	RestoreUser(ctx context.Context, userID string) (domain.User, error)
+}
+
+type scheduleService interface {
+	ScheduleSetIsActive(ctx context.Context, userID string, isActive bool, effectiveAt time.Time) (domain.ScheduledChange, error)
+	ScheduleBulkDeactivation(ctx context.Context, teamName string, userIDs []string, effectiveAt time.Time) (domain.ScheduledChange, error)
 }
 
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	service userService
-	logger  *zap.Logger
+	service   userService
+	scheduler scheduleService
+	logger    *zap.Logger
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(service userService, logger *zap.Logger) *UserHandler {
+func NewUserHandler(service userService, scheduler scheduleService, logger *zap.Logger) *UserHandler {
 	return &UserHandler{
-		service: service,
-		logger:  logger,
+		service:   service,
+		scheduler: scheduler,
+		logger:    logger,
 	}
 }
 
 // User DTOs matching OpenAPI schema with snake_case
 
 type SetIsActiveRequest struct {
-	UserID   string `json:"user_id"`
-	IsActive bool   `json:"is_active"`
+	UserID      string     `json:"user_id"`
+	IsActive    bool       `json:"is_active"`
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
+}
+
+type SetRoleRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+type DeleteUserRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type deleteUserResponse struct {
+	User          UserResponse      `json:"user"`
+	Reassignments []reassignmentDTO `json:"reassignments"`
+	ClosedReviews []closedReviewDTO `json:"closed_reviews"`
 }
 
 type UserResponse struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID     string     `json:"user_id"`
+	Username   string     `json:"username"`
+	TeamName   string     `json:"team_name"`
+	Teams      []string   `json:"teams"`
+	IsActive   bool       `json:"is_active"`
+	Role       string     `json:"role"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
+
+type HeartbeatRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type dormantUsersResponse struct {
+	InactiveDays int            `json:"inactive_days"`
+	Users        []UserResponse `json:"users"`
+	Total        int            `json:"total"`
+}
+
+type deletedUsersResponse struct {
+	Users      []UserResponse `json:"users"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+type RestoreUserRequest struct {
+	UserID string `json:"user_id"`
 }
 
 type PullRequestShort struct {
@@ -53,7 +111,7 @@ type PullRequestShort struct {
 	Status          string `json:"status"`
 }
 
-type setIsActiveResponse struct {
+type userEnvelope struct {
 	User UserResponse `json:"user"`
 }
 
@@ -63,8 +121,9 @@ type getReviewResponse struct {
 }
 
 type BulkDeactivateRequest struct {
-	TeamName string   `json:"team_name"`
-	UserIDs  []string `json:"user_ids"`
+	TeamName    string     `json:"team_name"`
+	UserIDs     []string   `json:"user_ids"`
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
 }
 
 type bulkDeactivateResponse struct {
@@ -74,12 +133,37 @@ type bulkDeactivateResponse struct {
 	TeamMembers        []bulkTeamMemberDTO `json:"team_members"`
 }
 
+type BulkActivateRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type bulkActivateResponse struct {
+	TeamName         string              `json:"team_name"`
+	ActivatedUserIDs []string            `json:"activated_user_ids"`
+	TeamMembers      []bulkTeamMemberDTO `json:"team_members"`
+}
+
 type bulkTeamMemberDTO struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
 }
 
+type scheduledChangeDTO struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	TeamName    string    `json:"team_name,omitempty"`
+	UserIDs     []string  `json:"user_ids"`
+	IsActive    bool      `json:"is_active"`
+	EffectiveAt time.Time `json:"effective_at"`
+	Status      string    `json:"status"`
+}
+
+type scheduledChangeResponse struct {
+	ScheduledChange scheduledChangeDTO `json:"scheduled_change"`
+}
+
 type reassignmentDTO struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
@@ -90,7 +174,7 @@ type reassignmentDTO struct {
 func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	var req SetIsActiveRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
 		return
 	}
 
@@ -100,24 +184,209 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isScheduled(req.EffectiveAt) {
+		change, err := h.scheduler.ScheduleSetIsActive(r.Context(), req.UserID, req.IsActive, *req.EffectiveAt)
+		if err != nil {
+			middleware.WriteErrorResponse(w, err, h.logger)
+			return
+		}
+		writeScheduledChange(w, change)
+		return
+	}
+
 	user, err := h.service.SetIsActive(r.Context(), req.UserID, req.IsActive)
 	if err != nil {
 		middleware.WriteErrorResponse(w, err, h.logger)
 		return
 	}
 
-	resp := setIsActiveResponse{User: mapUserToResponse(user)}
+	resp := userEnvelope{User: mapUserToResponse(user)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetRole handles POST /users/setRole
+func (h *UserHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	var req SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.UserID = strings.TrimSpace(req.UserID)
+	role := domain.UserRole(strings.TrimSpace(req.Role))
+	var v domain.Validator
+	v.Required(req.UserID, "user_id")
+	v.Required(req.Role, "role")
+	checkRole(&v, req.Role, "role")
+	if err := v.Err(); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	user, err := h.service.SetRole(r.Context(), req.UserID, role)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := userEnvelope{User: mapUserToResponse(user)}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(resp)
 }
 
+// Heartbeat handles POST /users/heartbeat
+func (h *UserHandler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	var req HeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	userID, err := selfUserID(r.Context(), strings.TrimSpace(req.UserID))
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	req.UserID = userID
+
+	user, err := h.service.Heartbeat(r.Context(), req.UserID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := userEnvelope{User: mapUserToResponse(user)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListDormantUsers handles GET /users/dormant?inactive_days=...&limit=...&offset=...
+func (h *UserHandler) ListDormantUsers(w http.ResponseWriter, r *http.Request) {
+	days, err := parseIntQuery(r, "inactive_days")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	limit, err := parseIntQuery(r, "limit")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+	offset, err := parseIntQuery(r, "offset")
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	users, total, dormantAfter, err := h.service.ListDormantUsers(r.Context(), time.Duration(days)*24*time.Hour, limit, offset)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := dormantUsersResponse{
+		InactiveDays: int(dormantAfter / (24 * time.Hour)),
+		Users:        make([]UserResponse, len(users)),
+		Total:        total,
+	}
+	for i, u := range users {
+		resp.Users[i] = mapUserToResponse(u)
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// DeleteUser handles POST /users/delete
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	var req DeleteUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.UserID = strings.TrimSpace(req.UserID)
+	if err := validateUserID(req.UserID); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	user, reassignments, err := h.service.DeleteUser(r.Context(), req.UserID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := deleteUserResponse{User: mapUserToResponse(user)}
+	resp.Reassignments, resp.ClosedReviews = mapReassignmentReport(reassignments)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListDeletedUsers handles GET /users/deleted?limit=...&cursor=...&order=...
+func (h *UserHandler) ListDeletedUsers(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.ParseRequest(r, pagination.OrderDesc)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	result, err := h.service.ListDeletedUsers(r.Context(), page)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := deletedUsersResponse{
+		Users:      make([]UserResponse, len(result.Items)),
+		Total:      result.Total,
+		NextCursor: result.NextCursor,
+	}
+	for i, u := range result.Items {
+		resp.Users[i] = mapUserToResponse(u)
+	}
+
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// RestoreUser handles POST /users/restore
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	var req RestoreUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.UserID = strings.TrimSpace(req.UserID)
+	if err := validateUserID(req.UserID); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	user, err := h.service.RestoreUser(r.Context(), req.UserID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(userEnvelope{User: mapUserToResponse(user)})
+}
+
 // GetReview handles GET /users/getReview?user_id=...
 func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	userID := strings.TrimSpace(r.URL.Query().Get("user_id"))
 	if err := validateUserID(userID); err != nil {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, err, h.logger)
 		return
 	}
 
@@ -143,38 +412,141 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 		PullRequests: result,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(resp)
+	writeNegotiated(w, r, resp, h.logger)
+}
+
+// GetReviewCalendar handles GET /users/{id}/reviews.ics with an iCalendar feed
+// of the user's reviews waiting for a first action, placed at their due dates
+func (h *UserHandler) GetReviewCalendar(w http.ResponseWriter, r *http.Request) {
+	userID := strings.TrimSpace(r.PathValue("id"))
+	if userID == "" {
+		middleware.WriteErrorResponse(w, domain.NewValidationError("id", "must not be empty"), h.logger)
+		return
+	}
+
+	reviews, err := h.service.GetPendingReviews(r.Context(), userID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	writeCalendar(w, "reviews", renderReviewCalendar(userID, reviews, time.Now()))
 }
 
 func mapUserToResponse(user domain.User) UserResponse {
+	teams := user.Teams
+	if teams == nil {
+		teams = []string{}
+	}
+
 	return UserResponse{
-		UserID:   user.UserID,
-		Username: user.Username,
-		TeamName: user.TeamName,
-		IsActive: user.IsActive,
+		UserID:     user.UserID,
+		Username:   user.Username,
+		TeamName:   user.TeamName,
+		Teams:      teams,
+		IsActive:   user.IsActive,
+		Role:       string(user.Role),
+		LastSeenAt: user.LastSeenAt,
+		DeletedAt:  user.DeletedAt,
 	}
 }
 
+// mapReassignmentReport splits a handoff report into replaced and closed reviews
+func mapReassignmentReport(reassignments []domain.Reassignment) ([]reassignmentDTO, []closedReviewDTO) {
+	replaced := make([]reassignmentDTO, 0, len(reassignments))
+	closed := make([]closedReviewDTO, 0)
+	for _, reassignment := range reassignments {
+		if reassignment.IsClosed() {
+			closed = append(closed, closedReviewDTO{
+				PullRequestID: reassignment.PullRequestID,
+				UserID:        reassignment.OldUserID,
+			})
+			continue
+		}
+		replaced = append(replaced, reassignmentDTO{
+			PullRequestID: reassignment.PullRequestID,
+			OldUserID:     reassignment.OldUserID,
+			NewUserID:     reassignment.NewUserID,
+		})
+	}
+	return replaced, closed
+}
+
+// isScheduled reports whether a change should be deferred; past or missing times apply immediately
+func isScheduled(effectiveAt *time.Time) bool {
+	return effectiveAt != nil && effectiveAt.After(time.Now())
+}
+
+func writeScheduledChange(w http.ResponseWriter, change domain.ScheduledChange) {
+	resp := scheduledChangeResponse{
+		ScheduledChange: scheduledChangeDTO{
+			ID:          change.ID,
+			Kind:        string(change.Kind),
+			TeamName:    change.TeamName,
+			UserIDs:     change.UserIDs,
+			IsActive:    change.IsActive,
+			EffectiveAt: change.EffectiveAt,
+			Status:      string(change.Status),
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resp)
+}
+
 func validateUserID(userID string) error {
 	if strings.TrimSpace(userID) == "" {
-		return domain.ErrInvalidArgument
+		return domain.NewValidationError("user_id", "must not be empty")
 	}
 	return nil
 }
 
+func validateBulkRequest(teamName string, userIDs []string) error {
+	var v domain.Validator
+	v.Required(teamName, "team_name")
+	v.Check(len(userIDs) > 0, "user_ids", "must not be empty")
+	for i, userID := range userIDs {
+		v.Required(userID, fmt.Sprintf("user_ids[%d]", i))
+	}
+	return v.Err()
+}
+
+// selfUserID resolves the user a self-service request acts for. With an
+// authenticated caller the body may omit the user ID but cannot name anyone
+// else; without one the body's user ID is required.
+func selfUserID(ctx context.Context, userID string) (string, error) {
+	caller, ok := middleware.CallerFromContext(ctx)
+	if !ok {
+		return userID, validateUserID(userID)
+	}
+	if userID != "" && userID != caller {
+		return "", domain.ErrForbidden
+	}
+	return caller, nil
+}
+
 // BulkDeactivateTeamMembers handles POST /users/deactivateTeamMembers
 func (h *UserHandler) BulkDeactivateTeamMembers(w http.ResponseWriter, r *http.Request) {
 	var req BulkDeactivateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
 		return
 	}
 
 	req.TeamName = strings.TrimSpace(req.TeamName)
-	if req.TeamName == "" || len(req.UserIDs) == 0 {
-		middleware.WriteErrorResponse(w, domain.ErrInvalidArgument, h.logger)
+	if err := validateBulkRequest(req.TeamName, req.UserIDs); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	if isScheduled(req.EffectiveAt) {
+		change, err := h.scheduler.ScheduleBulkDeactivation(r.Context(), req.TeamName, req.UserIDs, *req.EffectiveAt)
+		if err != nil {
+			middleware.WriteErrorResponse(w, err, h.logger)
+			return
+		}
+		writeScheduledChange(w, change)
 		return
 	}
 
@@ -211,3 +583,42 @@ func (h *UserHandler) BulkDeactivateTeamMembers(w http.ResponseWriter, r *http.R
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(resp)
 }
+
+// BulkActivateTeamMembers handles POST /users/activateTeamMembers
+func (h *UserHandler) BulkActivateTeamMembers(w http.ResponseWriter, r *http.Request) {
+	var req BulkActivateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		middleware.WriteErrorResponse(w, errInvalidBody, h.logger)
+		return
+	}
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	if err := validateBulkRequest(req.TeamName, req.UserIDs); err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	team, activated, err := h.service.BulkActivateTeamMembers(r.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, h.logger)
+		return
+	}
+
+	resp := bulkActivateResponse{
+		TeamName:         team.TeamName,
+		ActivatedUserIDs: activated,
+		TeamMembers:      make([]bulkTeamMemberDTO, len(team.Members)),
+	}
+
+	for i, member := range team.Members {
+		resp.TeamMembers[i] = bulkTeamMemberDTO{
+			UserID:   member.UserID,
+			Username: member.Username,
+			IsActive: member.IsActive,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"pr-service/internal/app/middleware"
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type prLifecycleService interface {
+	CreatePR(ctx context.Context, prID, prName, authorID, teamName, repository, ticketKey string) (domain.PullRequest, error)
+	MergePR(ctx context.Context, prID string) (domain.PullRequest, error)
+}
+
+// maxWebhookBytes limits the size of an incoming webhook payload
+const maxWebhookBytes = 1 << 20
+
+// Webhook results reported back to the sender
+const (
+	webhookCreated = "created"
+	webhookMerged  = "merged"
+	webhookIgnored = "ignored"
+)
+
+type webhookResponse struct {
+	Result string          `json:"result"`
+	PR     *PullRequestDTO `json:"pr,omitempty"`
+}
+
+// webhookPR is a pull request opened on a code hosting service
+type webhookPR struct {
+	ID         string
+	Name       string
+	AuthorID   string
+	TeamName   string
+	Repository string
+	TicketKey  string
+}
+
+// openWebhookPR creates the PR; one that is already tracked (a redelivery or
+// a reopened PR) is ignored
+func openWebhookPR(w http.ResponseWriter, r *http.Request, service prLifecycleService, opened webhookPR, logger *zap.Logger) {
+	pr, err := service.CreatePR(r.Context(), opened.ID, opened.Name, opened.AuthorID, opened.TeamName, opened.Repository, opened.TicketKey)
+	if errors.Is(err, domain.ErrPRExists) {
+		writeWebhookResponse(w, webhookResponse{Result: webhookIgnored}, logger)
+		return
+	}
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, logger)
+		return
+	}
+
+	dto := mapPRToDTO(pr)
+	writeWebhookResponse(w, webhookResponse{Result: webhookCreated, PR: &dto}, logger)
+}
+
+// mergeWebhookPR marks the PR as merged
+func mergeWebhookPR(w http.ResponseWriter, r *http.Request, service prLifecycleService, prID string, logger *zap.Logger) {
+	pr, err := service.MergePR(r.Context(), prID)
+	if err != nil {
+		middleware.WriteErrorResponse(w, err, logger)
+		return
+	}
+
+	dto := mapPRToDTO(pr)
+	writeWebhookResponse(w, webhookResponse{Result: webhookMerged, PR: &dto}, logger)
+}
+
+// webhookUserID maps a login on a code hosting service to a user ID;
+// unmapped logins are used as user IDs as is
+func webhookUserID(users map[string]string, login string) string {
+	if userID, ok := users[login]; ok {
+		return userID
+	}
+	return login
+}
+
+// validHMACSignature checks a sha256=<hex> HMAC of the payload
+func validHMACSignature(secret []byte, header string, payload []byte) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func writeWebhookResponse(w http.ResponseWriter, resp webhookResponse, logger *zap.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Error("failed to encode webhook response", zap.Error(err))
+	}
+}
@@ -0,0 +1,122 @@
+// Package jsonpath evaluates the subset of JSONPath that addresses a single
+// value: member names and array indexes, such as "$.pull_request.user.login",
+// "$['head commit'].id" or "$.reviewers[0]". Wildcards, slices and filters are
+// not supported.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step is a member name or, when isIndex is set, an array index
+type step struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// Path is a compiled path expression
+type Path struct {
+	expr  string
+	steps []step
+}
+
+// Compile parses a path expression. The root "$" is required.
+func Compile(expr string) (Path, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(expr), "$")
+	if !ok {
+		return Path{}, fmt.Errorf("jsonpath %q: must start with $", expr)
+	}
+
+	p := Path{expr: expr}
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" || name == "*" {
+				return Path{}, fmt.Errorf("jsonpath %q: empty or wildcard member name", expr)
+			}
+			p.steps = append(p.steps, step{name: name})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return Path{}, fmt.Errorf("jsonpath %q: unterminated member name", expr)
+			}
+			p.steps = append(p.steps, step{name: rest[2:end]})
+			rest = rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return Path{}, fmt.Errorf("jsonpath %q: unterminated index", expr)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return Path{}, fmt.Errorf("jsonpath %q: index must be a non-negative integer", expr)
+			}
+			p.steps = append(p.steps, step{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			return Path{}, fmt.Errorf("jsonpath %q: unexpected %q", expr, rest)
+		}
+	}
+	return p, nil
+}
+
+// String returns the source expression
+func (p Path) String() string {
+	return p.expr
+}
+
+// Lookup returns the value at the path in a document decoded by encoding/json
+// into an interface value; ok is false when the path does not exist
+func (p Path) Lookup(doc any) (value any, ok bool) {
+	value = doc
+	for _, s := range p.steps {
+		if s.isIndex {
+			items, isArray := value.([]any)
+			if !isArray || s.index >= len(items) {
+				return nil, false
+			}
+			value = items[s.index]
+			continue
+		}
+		members, isObject := value.(map[string]any)
+		if !isObject {
+			return nil, false
+		}
+		if value, ok = members[s.name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// LookupString returns the scalar at the path as a string: strings as is,
+// numbers (decoded as float64 or json.Number) and booleans in their JSON form.
+// ok is false when the path does not exist or holds null, an object or an array.
+func (p Path) LookupString(doc any) (string, bool) {
+	value, ok := p.Lookup(doc)
+	if !ok {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,59 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLookupString(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{
+		"event": "change.merged",
+		"change": {"id": 1042, "title": "Fix rounding", "draft": false, "labels": ["billing", "urgent"]},
+		"head commit": {"id": "9fceb02"},
+		"ratio": 0.5,
+		"missing": null
+	}`))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"$.event":             "change.merged",
+		"$.change.id":         "1042",
+		"$['change'].title":   "Fix rounding",
+		"$.change.draft":      "false",
+		"$.change.labels[1]":  "urgent",
+		"$['head commit'].id": "9fceb02",
+		"$.ratio":             "0.5",
+	}
+	for expr, want := range cases {
+		path, err := Compile(expr)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", expr, err)
+			continue
+		}
+		if got, ok := path.LookupString(doc); !ok || got != want {
+			t.Errorf("%s = %q (%v), want %q", expr, got, ok, want)
+		}
+	}
+
+	for _, expr := range []string{"$.change", "$.change.labels", "$.missing", "$.nope", "$.change.labels[2]", "$.event.length", "$[0]"} {
+		path, err := Compile(expr)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", expr, err)
+			continue
+		}
+		if got, ok := path.LookupString(doc); ok {
+			t.Errorf("%s = %q, want no scalar", expr, got)
+		}
+	}
+
+	for _, expr := range []string{"", "event", "$.", "$..event", "$.*", "$[-1]", "$[x]", "$['event'", "$[0", "$event"} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package kafka publishes outbox messages to a Kafka topic with franz-go.
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	// DefaultTopic receives events when no topic is configured
+	DefaultTopic = "pr-service.events"
+	// DefaultClientID identifies the producer to brokers when none is configured
+	DefaultClientID = "pr-service"
+	// DefaultTimeout bounds a broker round trip and the broker-side replication wait
+	DefaultTimeout = 10 * time.Second
+
+	// EventHeader is the record header carrying the event type
+	EventHeader = "event"
+)
+
+// Producer publishes messages to one topic. Messages are partitioned by key
+// with the murmur2 hash of the Java client, so all events of a pull request
+// land in the same partition and stay ordered, and are acknowledged by all
+// in-sync replicas.
+type Producer struct {
+	topic  string
+	client *kgo.Client
+}
+
+// NewProducer creates a producer bootstrapped from brokers ("host:port"). An empty
+// topic uses DefaultTopic, an empty clientID DefaultClientID and non-positive
+// timeout DefaultTimeout. Brokers are not contacted until the first send.
+func NewProducer(brokers []string, topic, clientID string, timeout time.Duration) (*Producer, error) {
+	if topic == "" {
+		topic = DefaultTopic
+	}
+	if clientID == "" {
+		clientID = DefaultClientID
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.DefaultProduceTopic(topic),
+		kgo.ClientID(clientID),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)),
+		kgo.DialTimeout(timeout),
+		kgo.ProduceRequestTimeout(timeout),
+		kgo.RecordDeliveryTimeout(timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	return &Producer{topic: topic, client: client}, nil
+}
+
+// Send publishes messages and returns once every partition leader acknowledged
+// its share. On error some partitions may have stored their messages, so a
+// retry can produce duplicates: delivery is at least once.
+func (p *Producer) Send(ctx context.Context, messages []domain.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	records := make([]*kgo.Record, len(messages))
+	for i, m := range messages {
+		records[i] = &kgo.Record{
+			Value:   m.Payload,
+			Headers: []kgo.RecordHeader{{Key: EventHeader, Value: []byte(m.EventType)}},
+		}
+		if m.Key != "" {
+			records[i].Key = []byte(m.Key)
+		}
+	}
+	if err := p.client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce to %s: %w", p.topic, err)
+	}
+	return nil
+}
+
+// Close closes the broker connections. Send waits for its records, so
+// nothing is left buffered to flush.
+func (p *Producer) Close() error {
+	p.client.Close()
+	return nil
+}
@@ -0,0 +1,166 @@
+package kafka
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+var testMessages = []domain.OutboxMessage{
+	{EventType: domain.EventPRCreated, Key: "pr-1", Payload: []byte(`{"n":1}`)},
+	{EventType: domain.EventPRCreated, Key: "pr-2", Payload: []byte(`{"n":2}`)},
+	{EventType: domain.EventReviewerAssigned, Key: "pr-1", Payload: []byte(`{"n":3}`)},
+	{EventType: domain.EventUserDeactivated, Key: "u7", Payload: []byte(`{"n":4}`)},
+	{EventType: domain.EventPRMerged, Key: "pr-1", Payload: []byte(`{"n":5}`)},
+}
+
+func newCluster(t *testing.T) *kfake.Cluster {
+	t.Helper()
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(3, "pr-events"))
+	if err != nil {
+		t.Fatalf("start cluster: %v", err)
+	}
+	t.Cleanup(cluster.Close)
+	return cluster
+}
+
+func newProducer(t *testing.T, cluster *kfake.Cluster, timeout time.Duration) *Producer {
+	t.Helper()
+	producer, err := NewProducer(cluster.ListenAddrs(), "pr-events", "e2e", timeout)
+	if err != nil {
+		t.Fatalf("create producer: %v", err)
+	}
+	t.Cleanup(func() { producer.Close() })
+	return producer
+}
+
+// consume reads n records of the topic from the beginning
+func consume(t *testing.T, cluster *kfake.Cluster, n int) []*kgo.Record {
+	t.Helper()
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(cluster.ListenAddrs()...),
+		kgo.ConsumeTopics("pr-events"),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	if err != nil {
+		t.Fatalf("create consumer: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var records []*kgo.Record
+	for len(records) < n {
+		fetches := client.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("expected %d records, got %d", n, len(records))
+		}
+		records = append(records, fetches.Records()...)
+	}
+	return records
+}
+
+func TestProducerSend(t *testing.T) {
+	cluster := newCluster(t)
+	var mu sync.Mutex
+	var acks []int16
+	cluster.ControlKey(int16(kmsg.Produce), func(req kmsg.Request) (kmsg.Response, error, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		acks = append(acks, req.(*kmsg.ProduceRequest).Acks)
+		return nil, nil, false
+	})
+
+	if err := newProducer(t, cluster, time.Second).Send(context.Background(), testMessages); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	records := consume(t, cluster, len(testMessages))
+	partitionOf := make(map[string]int32)
+	perKey := make(map[string][]string)
+	for _, r := range records {
+		key := string(r.Key)
+		if p, ok := partitionOf[key]; ok && p != r.Partition {
+			t.Fatalf("key %s spread over partitions %d and %d", key, p, r.Partition)
+		}
+		partitionOf[key] = r.Partition
+		perKey[key] = append(perKey[key], string(r.Value))
+		if len(r.Headers) != 1 || r.Headers[0].Key != EventHeader {
+			t.Fatalf("expected the event type header, got %+v", r.Headers)
+		}
+		if key == "u7" && string(r.Headers[0].Value) != string(domain.EventUserDeactivated) {
+			t.Fatalf("unexpected event header %s", r.Headers[0].Value)
+		}
+	}
+	if !slices.Equal(perKey["pr-1"], []string{`{"n":1}`, `{"n":3}`, `{"n":5}`}) {
+		t.Fatalf("expected pr-1 records in order, got %v", perKey["pr-1"])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(acks) == 0 || slices.ContainsFunc(acks, func(a int16) bool { return a != -1 }) {
+		t.Fatalf("expected every produce request to wait for all in-sync replicas, got acks %v", acks)
+	}
+}
+
+func TestProducerBrokerErrors(t *testing.T) {
+	cluster := newCluster(t)
+	// the client refreshes metadata at most every five seconds, so the
+	// retry needs the default timeout
+	producer := newProducer(t, cluster, 0)
+	ctx := context.Background()
+
+	// a lost leadership is retried by the client after refreshing metadata
+	cluster.ControlKey(int16(kmsg.Produce), rejectProduce(6))
+	if err := producer.Send(ctx, testMessages[:1]); err != nil {
+		t.Fatalf("expected the retriable error to be retried, got %v", err)
+	}
+
+	// a refused write fails the send and is not retried
+	cluster.ControlKey(int16(kmsg.Produce), rejectProduce(29))
+	err := producer.Send(ctx, testMessages[1:2])
+	if err == nil || !strings.Contains(err.Error(), "TOPIC_AUTHORIZATION_FAILED") {
+		t.Fatalf("expected the broker error, got %v", err)
+	}
+
+	if err := producer.Send(ctx, testMessages[2:3]); err != nil {
+		t.Fatalf("send after error: %v", err)
+	}
+	var values []string
+	for _, r := range consume(t, cluster, 2) {
+		values = append(values, string(r.Value))
+	}
+	slices.Sort(values)
+	if !slices.Equal(values, []string{`{"n":1}`, `{"n":3}`}) {
+		t.Fatalf("expected only the accepted records, got %v", values)
+	}
+}
+
+// rejectProduce answers one produce request with code for every partition
+func rejectProduce(code int16) func(kmsg.Request) (kmsg.Response, error, bool) {
+	return func(req kmsg.Request) (kmsg.Response, error, bool) {
+		produce := req.(*kmsg.ProduceRequest)
+		resp := produce.ResponseKind().(*kmsg.ProduceResponse)
+		for _, topic := range produce.Topics {
+			rt := kmsg.NewProduceResponseTopic()
+			rt.Topic = topic.Topic
+			for _, partition := range topic.Partitions {
+				rp := kmsg.NewProduceResponseTopicPartition()
+				rp.Partition = partition.Partition
+				rp.ErrorCode = code
+				rt.Partitions = append(rt.Partitions, rp)
+			}
+			resp.Topics = append(resp.Topics, rt)
+		}
+		return resp, nil, true
+	}
+}
@@ -0,0 +1,111 @@
+// Package ldap reads a directory with go-ldap: simple bind and paged subtree
+// searches over ldap:// or ldaps://.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	// DefaultTimeout bounds connecting and each server round trip
+	DefaultTimeout = 10 * time.Second
+	// DefaultPageSize is the number of entries requested per search page
+	DefaultPageSize = 500
+)
+
+// Entry is a search result; attribute names are lower-cased
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Get returns the first value of attribute name, or "" if the entry has none
+func (e Entry) Get(name string) string {
+	if values := e.Attributes[strings.ToLower(name)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Client searches an LDAP server. Every search opens its own connection, which
+// suits the infrequent bulk reads of a directory sync.
+type Client struct {
+	url      string
+	bindDN   string
+	password string
+	timeout  time.Duration
+}
+
+// NewClient creates a client for the server at rawURL ("ldap://host[:port]" or
+// "ldaps://host[:port]") binding as bindDN; an empty bindDN binds anonymously.
+// Non-positive timeout uses DefaultTimeout.
+func NewClient(rawURL, bindDN, password string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Client{
+		url:      rawURL,
+		bindDN:   bindDN,
+		password: password,
+		timeout:  timeout,
+	}
+}
+
+// Search returns the entries under baseDN matching filter with the requested
+// attributes. The parentheses around a filter may be left out. Referrals to
+// other servers are not followed.
+func (c *Client) Search(ctx context.Context, baseDN, filter string, attributes []string) ([]Entry, error) {
+	if !strings.HasPrefix(filter, "(") {
+		filter = "(" + filter + ")"
+	}
+	if _, err := ldap.CompileFilter(filter); err != nil {
+		return nil, err
+	}
+
+	conn, err := ldap.DialURL(c.url,
+		ldap.DialWithDialer(&net.Dialer{Timeout: c.timeout}),
+		ldap.DialWithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ldap server: %w", err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(c.timeout)
+	// go-ldap takes no context; closing the connection fails the pending request
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if c.bindDN == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(c.bindDN, c.password)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind as %q: %w", c.bindDN, err)
+	}
+
+	req := ldap.NewSearchRequest(baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(c.timeout/time.Second), false, filter, attributes, nil)
+	result, err := conn.SearchWithPaging(req, DefaultPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %q: %w", baseDN, err)
+	}
+
+	entries := make([]Entry, 0, len(result.Entries))
+	for _, e := range result.Entries {
+		entry := Entry{DN: e.DN, Attributes: make(map[string][]string, len(e.Attributes))}
+		for _, attr := range e.Attributes {
+			name := strings.ToLower(attr.Name)
+			entry.Attributes[name] = append(entry.Attributes[name], attr.Values...)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
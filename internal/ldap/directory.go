@@ -0,0 +1,126 @@
+package ldap
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"pr-service/internal/domain"
+)
+
+// DirectoryConfig describes where users and groups live in the directory and
+// which attributes carry their identifiers. Empty values use the defaults of
+// an OpenLDAP-style schema (inetOrgPerson users in groupOfNames groups).
+type DirectoryConfig struct {
+	UserBaseDN         string
+	UserFilter         string
+	UserIDAttribute    string
+	UsernameAttribute  string
+	GroupBaseDN        string
+	GroupFilter        string
+	GroupNameAttribute string
+	MemberAttribute    string
+}
+
+// Directory reads teams from an LDAP directory: each group matching the group
+// filter becomes a team whose members are the users matching the user filter
+type Directory struct {
+	client *Client
+	cfg    DirectoryConfig
+}
+
+// NewDirectory creates a directory reading through client
+func NewDirectory(client *Client, cfg DirectoryConfig) *Directory {
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = "(objectClass=inetOrgPerson)"
+	}
+	if cfg.UserIDAttribute == "" {
+		cfg.UserIDAttribute = "uid"
+	}
+	if cfg.UsernameAttribute == "" {
+		cfg.UsernameAttribute = "cn"
+	}
+	if cfg.GroupFilter == "" {
+		cfg.GroupFilter = "(objectClass=groupOfNames)"
+	}
+	if cfg.GroupNameAttribute == "" {
+		cfg.GroupNameAttribute = "cn"
+	}
+	if cfg.MemberAttribute == "" {
+		cfg.MemberAttribute = "member"
+	}
+
+	return &Directory{client: client, cfg: cfg}
+}
+
+// Groups returns the directory's groups with their members. Member values are
+// matched against user DNs (member, uniqueMember) and then user IDs (memberUid);
+// members that match no user, such as nested groups or filtered-out accounts,
+// are skipped.
+func (d *Directory) Groups(ctx context.Context) ([]domain.DirectoryGroup, error) {
+	users, err := d.client.Search(ctx, d.cfg.UserBaseDN, d.cfg.UserFilter,
+		[]string{d.cfg.UserIDAttribute, d.cfg.UsernameAttribute})
+	if err != nil {
+		return nil, err
+	}
+
+	byDN := make(map[string]domain.User, len(users))
+	byID := make(map[string]domain.User, len(users))
+	for _, entry := range users {
+		id := strings.TrimSpace(entry.Get(d.cfg.UserIDAttribute))
+		if id == "" {
+			continue
+		}
+		name := strings.TrimSpace(entry.Get(d.cfg.UsernameAttribute))
+		if name == "" {
+			name = id
+		}
+		user := domain.User{UserID: id, Username: name, IsActive: true}
+		byDN[normalizeDN(entry.DN)] = user
+		byID[id] = user
+	}
+
+	entries, err := d.client.Search(ctx, d.cfg.GroupBaseDN, d.cfg.GroupFilter,
+		[]string{d.cfg.GroupNameAttribute, d.cfg.MemberAttribute})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]domain.DirectoryGroup, 0, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSpace(entry.Get(d.cfg.GroupNameAttribute))
+		if name == "" {
+			continue
+		}
+
+		group := domain.DirectoryGroup{TeamName: name, Members: []domain.User{}}
+		seen := make(map[string]struct{})
+		for _, value := range entry.Attributes[strings.ToLower(d.cfg.MemberAttribute)] {
+			user, ok := byDN[normalizeDN(value)]
+			if !ok {
+				user, ok = byID[strings.TrimSpace(value)]
+			}
+			if !ok {
+				continue
+			}
+			if _, dup := seen[user.UserID]; dup {
+				continue
+			}
+			seen[user.UserID] = struct{}{}
+			group.Members = append(group.Members, user)
+		}
+		sort.Slice(group.Members, func(i, j int) bool { return group.Members[i].UserID < group.Members[j].UserID })
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// normalizeDN makes DNs comparable regardless of case and spacing around separators
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		attr, value, _ := strings.Cut(part, "=")
+		parts[i] = strings.ToLower(strings.TrimSpace(attr)) + "=" + strings.ToLower(strings.TrimSpace(value))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,77 @@
+// Package lifecycle tracks running work so shutdown can wait for it.
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker counts running operations and lets shutdown wait until none are
+// left. Unlike sync.WaitGroup, operations may start while someone waits, and
+// waiting is bounded by a context. The zero value is ready to use.
+type Tracker struct {
+	mu     sync.Mutex
+	active int
+	idle   chan struct{}
+}
+
+// Start records the start of an operation; the returned function records its
+// end and must be called exactly once
+func (t *Tracker) Start() (done func()) {
+	t.mu.Lock()
+	t.active++
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(t.done)
+	}
+}
+
+func (t *Tracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// Go runs f in a goroutine tracked as one operation
+func (t *Tracker) Go(f func()) {
+	done := t.Start()
+	go func() {
+		defer done()
+		f()
+	}()
+}
+
+// Active returns the number of running operations
+func (t *Tracker) Active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active
+}
+
+// Wait blocks until no operations are running or ctx is done, returning the
+// context's error in the latter case
+func (t *Tracker) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.active == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
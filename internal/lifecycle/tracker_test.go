@@ -0,0 +1,65 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTrackerWaitReturnsWhenIdle(t *testing.T) {
+	var tr Tracker
+	if err := tr.Wait(context.Background()); err != nil {
+		t.Fatalf("expected an idle tracker to return at once, got %v", err)
+	}
+
+	release := make(chan struct{})
+	tr.Go(func() { <-release })
+	done := tr.Start()
+	if got := tr.Active(); got != 2 {
+		t.Fatalf("expected 2 active operations, got %d", got)
+	}
+
+	waited := make(chan error, 1)
+	go func() { waited <- tr.Wait(context.Background()) }()
+
+	done()
+	select {
+	case err := <-waited:
+		t.Fatalf("expected Wait to block while an operation runs, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatalf("expected Wait to succeed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after the last operation ended")
+	}
+	if got := tr.Active(); got != 0 {
+		t.Fatalf("expected no active operations, got %d", got)
+	}
+}
+
+func TestTrackerWaitHonoursContext(t *testing.T) {
+	var tr Tracker
+	done := tr.Start()
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tr.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+
+	// Operations started after a timed out wait are tracked as usual
+	second := tr.Start()
+	done()
+	second()
+	if err := tr.Wait(context.Background()); err != nil {
+		t.Fatalf("expected Wait to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package maintenance holds the maintenance mode of the service.
+package maintenance
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+// Mode limits which requests the API serves
+type Mode string
+
+const (
+	// Off serves every request
+	Off Mode = "off"
+	// ReadOnly rejects requests that change state
+	ReadOnly Mode = "read_only"
+	// Full rejects every API request; probes, metrics and docs stay up
+	Full Mode = "full"
+)
+
+// DefaultRetryAfter is suggested to rejected clients when no delay is set
+const DefaultRetryAfter = 5 * time.Minute
+
+// State is the current maintenance mode
+type State struct {
+	Mode Mode
+	// Message is shown to rejected clients
+	Message string
+	// RetryAfter is sent in the Retry-After header of rejected requests
+	RetryAfter time.Duration
+	// Since is when the mode was last changed
+	Since time.Time
+}
+
+// Rejects reports whether a request to a route is refused in this state;
+// write tells whether the route changes state
+func (s State) Rejects(write bool) bool {
+	switch s.Mode {
+	case Full:
+		return true
+	case ReadOnly:
+		return write
+	default:
+		return false
+	}
+}
+
+// Switch holds the maintenance state of this instance. It is kept in memory,
+// since maintenance is typically needed while the database is unavailable.
+type Switch struct {
+	now func() time.Time
+
+	mu    sync.RWMutex
+	state State
+}
+
+// NewSwitch creates a switch in mode; an empty mode means Off
+func NewSwitch(mode Mode, message string, retryAfter time.Duration) (*Switch, error) {
+	s := &Switch{now: time.Now}
+	if _, err := s.Set(mode, message, retryAfter); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// State returns the current state
+func (s *Switch) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Set changes the mode; an empty mode means Off and a zero retryAfter the
+// default
+func (s *Switch) Set(mode Mode, message string, retryAfter time.Duration) (State, error) {
+	if mode == "" {
+		mode = Off
+	}
+	if mode != Off && mode != ReadOnly && mode != Full {
+		return State{}, domain.NewValidationError("mode", fmt.Sprintf("must be one of %s, %s, %s", Off, ReadOnly, Full))
+	}
+	if retryAfter < 0 {
+		return State{}, domain.NewValidationError("retry_after", "must not be negative")
+	}
+	if retryAfter == 0 {
+		retryAfter = DefaultRetryAfter
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = State{Mode: mode, Message: message, RetryAfter: retryAfter, Since: s.now().UTC()}
+	return s.state, nil
+}
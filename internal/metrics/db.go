@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	"pr-service/internal/breaker"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dbStats reads connection pool statistics, by pool ("primary" or "replica"),
+// and the state of the database circuit breaker at scrape time
+var dbStats = newDBCollector()
+
+func init() {
+	prometheus.MustRegister(dbStats)
+}
+
+// RegisterPoolStats exposes connection pool statistics of pool under name. A
+// later pool of the same name replaces the earlier one.
+func RegisterPoolStats(name string, pool *pgxpool.Pool) {
+	dbStats.mu.Lock()
+	defer dbStats.mu.Unlock()
+	dbStats.pools[name] = pool
+}
+
+// RegisterCircuitBreaker exposes the state of the database circuit breaker b
+func RegisterCircuitBreaker(b *breaker.Breaker) {
+	dbStats.mu.Lock()
+	defer dbStats.mu.Unlock()
+	dbStats.breaker = b
+}
+
+type dbCollector struct {
+	mu      sync.Mutex
+	pools   map[string]*pgxpool.Pool
+	breaker *breaker.Breaker
+
+	acquired         *prometheus.Desc
+	idle             *prometheus.Desc
+	constructing     *prometheus.Desc
+	total            *prometheus.Desc
+	max              *prometheus.Desc
+	acquires         *prometheus.Desc
+	acquireWait      *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	emptyAcquireWait *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	newConns         *prometheus.Desc
+	destroyedConns   *prometheus.Desc
+	circuitState     *prometheus.Desc
+}
+
+func newDBCollector() *dbCollector {
+	pool := []string{"pool"}
+	return &dbCollector{
+		pools: make(map[string]*pgxpool.Pool),
+
+		acquired: prometheus.NewDesc("pr_service_db_pool_acquired_connections",
+			"Connections currently in use.", pool, nil),
+		idle: prometheus.NewDesc("pr_service_db_pool_idle_connections",
+			"Idle connections in the pool.", pool, nil),
+		constructing: prometheus.NewDesc("pr_service_db_pool_constructing_connections",
+			"Connections being opened.", pool, nil),
+		total: prometheus.NewDesc("pr_service_db_pool_total_connections",
+			"Open connections in the pool.", pool, nil),
+		max: prometheus.NewDesc("pr_service_db_pool_max_connections",
+			"Maximum size of the pool.", pool, nil),
+		acquires: prometheus.NewDesc("pr_service_db_pool_acquires_total",
+			"Connections acquired from the pool.", pool, nil),
+		acquireWait: prometheus.NewDesc("pr_service_db_pool_acquire_wait_seconds_total",
+			"Time spent acquiring connections.", pool, nil),
+		emptyAcquires: prometheus.NewDesc("pr_service_db_pool_empty_acquires_total",
+			"Acquires that waited because no idle connection was available.", pool, nil),
+		emptyAcquireWait: prometheus.NewDesc("pr_service_db_pool_empty_acquire_wait_seconds_total",
+			"Time spent waiting for a connection because no idle connection was available.", pool, nil),
+		canceledAcquires: prometheus.NewDesc("pr_service_db_pool_canceled_acquires_total",
+			"Acquires canceled by their context while waiting.", pool, nil),
+		newConns: prometheus.NewDesc("pr_service_db_pool_new_connections_total",
+			"Connections opened by the pool.", pool, nil),
+		destroyedConns: prometheus.NewDesc("pr_service_db_pool_destroyed_connections_total",
+			"Connections closed by the pool, by reason: max_lifetime or max_idle.", []string{"pool", "reason"}, nil),
+		circuitState: prometheus.NewDesc("pr_service_db_circuit_state",
+			"State of the database circuit breaker: 0 closed, 1 open, 2 half-open.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *dbCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.acquired, c.idle, c.constructing, c.total, c.max,
+		c.acquires, c.acquireWait, c.emptyAcquires, c.emptyAcquireWait,
+		c.canceledAcquires, c.newConns, c.destroyedConns, c.circuitState,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *dbCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	names := make([]string, 0, len(c.pools))
+	for name := range c.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pools := make([]*pgxpool.Pool, len(names))
+	for i, name := range names {
+		pools[i] = c.pools[name]
+	}
+	b := c.breaker
+	c.mu.Unlock()
+
+	gauge := func(d *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v, labels...)
+	}
+	counter := func(d *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v, labels...)
+	}
+	for i, name := range names {
+		s := pools[i].Stat()
+		gauge(c.acquired, float64(s.AcquiredConns()), name)
+		gauge(c.idle, float64(s.IdleConns()), name)
+		gauge(c.constructing, float64(s.ConstructingConns()), name)
+		gauge(c.total, float64(s.TotalConns()), name)
+		gauge(c.max, float64(s.MaxConns()), name)
+		counter(c.acquires, float64(s.AcquireCount()), name)
+		counter(c.acquireWait, s.AcquireDuration().Seconds(), name)
+		counter(c.emptyAcquires, float64(s.EmptyAcquireCount()), name)
+		counter(c.emptyAcquireWait, s.EmptyAcquireWaitTime().Seconds(), name)
+		counter(c.canceledAcquires, float64(s.CanceledAcquireCount()), name)
+		counter(c.newConns, float64(s.NewConnsCount()), name)
+		counter(c.destroyedConns, float64(s.MaxLifetimeDestroyCount()), name, "max_lifetime")
+		counter(c.destroyedConns, float64(s.MaxIdleDestroyCount()), name, "max_idle")
+	}
+	if b != nil {
+		gauge(c.circuitState, float64(b.State()))
+	}
+}
@@ -0,0 +1,153 @@
+// Package metrics defines the service metrics and serves them for Prometheus
+// scraping.
+package metrics
+
+import (
+	"net/http"
+
+	"pr-service/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefBuckets are histogram buckets in seconds suited for request and query latencies
+var DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var (
+	// HTTPRequests counts handled requests by method, route pattern and status code
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_http_requests_total",
+		Help: "HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by method, route pattern and status code
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pr_service_http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, by method, route and status code.",
+		Buckets: DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestsInFlight tracks requests being served by method and route
+	// pattern, including open event streams and long polls
+	HTTPRequestsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pr_service_http_requests_in_flight",
+		Help: "HTTP requests being served, by method and route.",
+	}, []string{"method", "route"})
+
+	// ReviewerAssignments counts reviewers assigned to newly created PRs
+	ReviewerAssignments = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_reviewer_assignments_total",
+		Help: "Reviewers assigned to newly created pull requests.",
+	})
+
+	// ReviewerReassignments counts reviewers taken off open PRs, by whether a
+	// replacement was found ("replaced") or the review was dropped ("closed")
+	ReviewerReassignments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_reviewer_reassignments_total",
+		Help: "Reviewers taken off open pull requests, by outcome.",
+	}, []string{"outcome"})
+
+	// CacheRequests counts cache lookups by cache name and result (hit or miss)
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_cache_requests_total",
+		Help: "Cache lookups, by cache and result.",
+	}, []string{"cache", "result"})
+
+	// TransactionDuration observes database transaction latency by outcome (commit or rollback)
+	TransactionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pr_service_db_transaction_duration_seconds",
+		Help:    "Database transaction latency in seconds, by outcome.",
+		Buckets: DefBuckets,
+	}, []string{"outcome"})
+
+	// SlackNotifications counts Slack direct messages by result: "sent", "failed",
+	// or "dropped" when the notification queue was full
+	SlackNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_slack_notifications_total",
+		Help: "Slack direct messages, by result.",
+	}, []string{"result"})
+
+	// GitHubReviewRequests counts review request changes written back to GitHub by
+	// result: "sent", "failed", or "dropped" when the write-back queue was full
+	GitHubReviewRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_github_review_requests_total",
+		Help: "Review request changes written back to GitHub, by result.",
+	}, []string{"result"})
+
+	// JiraComments counts comments posted on linked Jira issues by result:
+	// "sent", "failed", or "dropped" when the comment queue was full
+	JiraComments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_jira_comments_total",
+		Help: "Comments posted on linked Jira issues, by result.",
+	}, []string{"result"})
+
+	// TeamChannelMessages counts messages posted to team chat channels by channel
+	// type and result: "sent", "failed", or "dropped" when the queue was full, which
+	// is counted under channel "unknown" as the team is not resolved yet
+	TeamChannelMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_team_channel_messages_total",
+		Help: "Messages posted to team chat channels, by channel type and result.",
+	}, []string{"channel", "result"})
+
+	// EventStreamDropped counts events dropped for event stream subscribers that fell behind
+	EventStreamDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_event_stream_dropped_total",
+		Help: "Events dropped for event stream subscribers that fell behind.",
+	})
+
+	// DeprecatedRequests counts calls to deprecated routes, so their remaining
+	// users show up before the sunset
+	DeprecatedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_deprecated_requests_total",
+		Help: "Requests to deprecated routes or routes with deprecated fields, by method and route.",
+	}, []string{"method", "route"})
+
+	// ReviewEscalations counts overdue reviews escalated to the on-call tool by
+	// result: "sent" or "failed"
+	ReviewEscalations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_review_escalations_total",
+		Help: "Overdue reviews escalated to the on-call tool, by result.",
+	}, []string{"result"})
+
+	// DBCircuitRejections counts database calls failed fast while the circuit
+	// breaker was open
+	DBCircuitRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_db_circuit_rejections_total",
+		Help: "Database calls rejected without reaching the database while the circuit breaker was open.",
+	})
+
+	// DBTransactionRetries counts transactions rerun after a transient error
+	DBTransactionRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_db_transaction_retries_total",
+		Help: "Transactions retried after a serialization failure, deadlock or dropped connection.",
+	})
+)
+
+func init() {
+	// Series with a known, fixed set of labels are exported from the start, so
+	// rates over them do not miss their first increment
+	for _, outcome := range []string{"replaced", "closed"} {
+		ReviewerReassignments.WithLabelValues(outcome)
+	}
+	for _, outcome := range []string{"commit", "rollback"} {
+		TransactionDuration.WithLabelValues(outcome)
+	}
+}
+
+// Handler serves the default Prometheus registry
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RecordReassignments counts reviewer replacements and closed reviews
+func RecordReassignments(reassignments []domain.Reassignment) {
+	for _, r := range reassignments {
+		if r.IsClosed() {
+			ReviewerReassignments.WithLabelValues("closed").Inc()
+			continue
+		}
+		ReviewerReassignments.WithLabelValues("replaced").Inc()
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-service/internal/breaker"
+	"pr-service/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordReassignments(t *testing.T) {
+	closed := testutil.ToFloat64(ReviewerReassignments.WithLabelValues("closed"))
+	replaced := testutil.ToFloat64(ReviewerReassignments.WithLabelValues("replaced"))
+
+	RecordReassignments([]domain.Reassignment{
+		{OldUserID: "u1", NewUserID: "u2"},
+		{OldUserID: "u3"},
+		{OldUserID: "u4"},
+	})
+
+	if got := testutil.ToFloat64(ReviewerReassignments.WithLabelValues("closed")) - closed; got != 2 {
+		t.Fatalf("expected 2 closed reviews, got %v", got)
+	}
+	if got := testutil.ToFloat64(ReviewerReassignments.WithLabelValues("replaced")) - replaced; got != 1 {
+		t.Fatalf("expected 1 replaced reviewer, got %v", got)
+	}
+}
+
+func TestDBCollector(t *testing.T) {
+	c := newDBCollector()
+	if n := testutil.CollectAndCount(c); n != 0 {
+		t.Fatalf("expected no series before a pool is registered, got %d", n)
+	}
+
+	// The pool connects lazily, so its stats can be read without a database
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/test?pool_max_conns=7")
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	c.pools["primary"] = pool
+	c.breaker = breaker.New(5, time.Minute)
+
+	if problems, err := testutil.CollectAndLint(c); err != nil || len(problems) > 0 {
+		t.Fatalf("expected collected metrics to lint cleanly, got %v %v", problems, err)
+	}
+	expected := `
+# HELP pr_service_db_pool_max_connections Maximum size of the pool.
+# TYPE pr_service_db_pool_max_connections gauge
+pr_service_db_pool_max_connections{pool="primary"} 7
+# HELP pr_service_db_circuit_state State of the database circuit breaker: 0 closed, 1 open, 2 half-open.
+# TYPE pr_service_db_circuit_state gauge
+pr_service_db_circuit_state 0
+`
+	err = testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"pr_service_db_pool_max_connections", "pr_service_db_circuit_state")
+	if err != nil {
+		t.Fatalf("unexpected metrics: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package migrate applies the SQL migrations embedded in the binary with
+// goose. Applied versions are recorded in goose's goose_db_version table, so
+// the goose CLI and the service can take turns on the same database.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"pr-service/internal/db"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"go.uber.org/zap"
+)
+
+// Status is a migration and when it was applied; AppliedAt is zero for
+// pending migrations
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	provider *goose.Provider
+	logger   *zap.Logger
+}
+
+// New creates a migrator of the goose migrations at the root of fsys. Replicas
+// starting together take turns through a Postgres advisory lock, so each
+// migration is applied once. Close releases its connections.
+func New(pool *pgxpool.Pool, fsys fs.FS, logger *zap.Logger) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+	sqlDB := stdlib.OpenDBFromPool(pool)
+	provider, err := goose.NewProvider(goose.DialectPostgres, sqlDB, fsys, goose.WithSessionLocker(locker))
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return &Migrator{provider: provider, logger: logger}, nil
+}
+
+// Close releases the connections of the migrator to the pool
+func (m *Migrator) Close() error {
+	return m.provider.Close()
+}
+
+// Up applies every pending migration in version order and returns how many
+// were applied. It stops at the first migration that fails.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	results, err := m.provider.Up(db.AllOrgs(ctx))
+	for _, result := range results {
+		m.logResult(result)
+	}
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		for _, result := range partial.Applied {
+			m.logResult(result)
+		}
+		return len(partial.Applied), err
+	}
+	return len(results), err
+}
+
+// Down rolls back the most recently applied migration and returns its name,
+// or false when none is applied
+func (m *Migrator) Down(ctx context.Context) (string, bool, error) {
+	result, err := m.provider.Down(db.AllOrgs(ctx))
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	m.logResult(result)
+	return result.Source.Path, true, nil
+}
+
+// Status lists every migration with when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, err := m.provider.Status(db.AllOrgs(ctx))
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(migrations))
+	for _, migration := range migrations {
+		statuses = append(statuses, Status{
+			Version:   migration.Source.Version,
+			Name:      migration.Source.Path,
+			AppliedAt: migration.AppliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) logResult(result *goose.MigrationResult) {
+	m.logger.Info("Applied migration",
+		zap.String("migration", result.Source.Path),
+		zap.String("direction", result.Direction),
+		zap.Duration("duration", result.Duration),
+	)
+}
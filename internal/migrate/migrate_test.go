@@ -0,0 +1,52 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"pr-service/migrations"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// newTestPool returns a pool that never connects; loading migrations does
+// not touch the database
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/test")
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestNewLoadsEmbeddedMigrations(t *testing.T) {
+	m, err := New(newTestPool(t), migrations.FS, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer m.Close()
+
+	sources := m.provider.ListSources()
+	if len(sources) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	for i, source := range sources {
+		if source.Version != int64(i+1) {
+			t.Errorf("%s: version %d, want %d", source.Path, source.Version, i+1)
+		}
+	}
+}
+
+func TestNewRejectsDuplicateVersions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"00001_a.sql": {Data: []byte("-- +goose Up\nSELECT 1;\n")},
+		"1_b.sql":     {Data: []byte("-- +goose Up\nSELECT 1;\n")},
+	}
+	if _, err := New(newTestPool(t), fsys, zap.NewNop()); err == nil {
+		t.Fatal("expected an error for duplicate versions")
+	}
+}
@@ -0,0 +1,156 @@
+// Package nats publishes outbox messages to NATS with nats.go, optionally
+// waiting for JetStream to acknowledge them.
+package nats
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// DefaultURL is the server used when none is configured
+	DefaultURL = nats.DefaultURL
+	// DefaultSubject prefixes the subjects events are published to when none is configured
+	DefaultSubject = "pr-service.events"
+	// DefaultName identifies the connection to the server when none is configured
+	DefaultName = "pr-service"
+	// DefaultTimeout bounds connecting and waiting for acknowledgements
+	DefaultTimeout = 10 * time.Second
+
+	// EventHeader carries the event type
+	EventHeader = "X-PR-Service-Event"
+	// KeyHeader carries the message key
+	KeyHeader = "X-PR-Service-Key"
+	// MsgIDHeader lets JetStream drop duplicates of retried messages
+	MsgIDHeader = jetstream.MsgIDHeader
+)
+
+// Publisher publishes messages to "<subject>.<event type>". With JetStream every
+// message waits for the stream's acknowledgement; otherwise a flush confirms
+// the server received the batch.
+type Publisher struct {
+	url       string
+	redacted  string
+	subject   string
+	name      string
+	jetStream bool
+	timeout   time.Duration
+
+	mu   sync.Mutex
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+// NewPublisher creates a publisher for the server at rawURL ("nats://[user:pass@]host[:port]").
+// Empty values use the package defaults; non-positive timeout uses DefaultTimeout.
+// The server is not contacted until the first send.
+func NewPublisher(rawURL, subject, name string, jetStream bool, timeout time.Duration) *Publisher {
+	if rawURL == "" {
+		rawURL = DefaultURL
+	}
+	if subject == "" {
+		subject = DefaultSubject
+	}
+	if name == "" {
+		name = DefaultName
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	redacted := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		redacted = u.Redacted()
+	}
+
+	return &Publisher{
+		url:       rawURL,
+		redacted:  redacted,
+		subject:   strings.TrimSuffix(subject, "."),
+		name:      name,
+		jetStream: jetStream,
+		timeout:   timeout,
+	}
+}
+
+// Send publishes messages in order and returns once they are acknowledged.
+// A failed send may leave some messages published, so delivery is at least
+// once; JetStream drops the duplicates within its duplicate window.
+func (p *Publisher) Send(ctx context.Context, messages []domain.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// nats.go reconnects on its own and only closes the connection once it
+	// gives up; the next send then dials again
+	if p.conn == nil || p.conn.IsClosed() {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	for _, m := range messages {
+		msg := nats.NewMsg(p.subject + "." + string(m.EventType))
+		msg.Header.Set(EventHeader, string(m.EventType))
+		msg.Header.Set(KeyHeader, m.Key)
+		msg.Header.Set(MsgIDHeader, strconv.FormatInt(m.ID, 10))
+		msg.Data = m.Payload
+
+		if p.jetStream {
+			if _, err := p.js.PublishMsg(ctx, msg); err != nil {
+				return fmt.Errorf("nats: failed to publish message %d to jetstream: %w", m.ID, err)
+			}
+			continue
+		}
+		if err := p.conn.PublishMsg(msg); err != nil {
+			return fmt.Errorf("nats: failed to publish message %d: %w", m.ID, err)
+		}
+	}
+	if !p.jetStream {
+		if err := p.conn.FlushWithContext(ctx); err != nil {
+			return fmt.Errorf("nats server %s: %w", p.redacted, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the server connection
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.js = nil, nil
+	}
+	return nil
+}
+
+func (p *Publisher) connect() error {
+	conn, err := nats.Connect(p.url, nats.Name(p.name), nats.Timeout(p.timeout))
+	if err != nil {
+		return fmt.Errorf("failed to connect to nats server %s: %w", p.redacted, err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("nats: failed to create jetstream context: %w", err)
+	}
+	p.conn, p.js = conn, js
+	return nil
+}
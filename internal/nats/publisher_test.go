@@ -0,0 +1,160 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+var testMessages = []domain.OutboxMessage{
+	{ID: 1, EventType: domain.EventPRCreated, Key: "pr-1", Payload: []byte(`{"n":1}`)},
+	{ID: 2, EventType: domain.EventReviewerAssigned, Key: "pr-1", Payload: []byte(`{"n":2}`)},
+	{ID: 3, EventType: domain.EventUserDeactivated, Key: "u7", Payload: []byte(`{"n":3}`)},
+}
+
+// runServer starts an in-process server with JetStream that accepts svc:secret
+func runServer(t *testing.T) *server.Server {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		Username:  "svc",
+		Password:  "secret",
+	})
+	if err != nil {
+		t.Fatalf("create server: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("server did not start")
+	}
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func serverURL(s *server.Server, userinfo string) string {
+	return strings.Replace(s.ClientURL(), "nats://", "nats://"+userinfo+"@", 1)
+}
+
+func connect(t *testing.T, s *server.Server) *nats.Conn {
+	t.Helper()
+	conn, err := nats.Connect(serverURL(s, "svc:secret"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func TestPublisherJetStream(t *testing.T) {
+	s := runServer(t)
+	js, err := jetstream.New(connect(t, s))
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+	ctx := context.Background()
+	stream, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "REVIEWS", Subjects: []string{"reviews.>"}})
+	if err != nil {
+		t.Fatalf("create stream: %v", err)
+	}
+
+	publisher := NewPublisher(serverURL(s, "svc:secret"), "reviews.", "e2e", true, time.Second)
+	defer publisher.Close()
+	if err := publisher.Send(ctx, testMessages); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	for i, m := range testMessages {
+		got, err := stream.GetMsg(ctx, uint64(i+1))
+		if err != nil {
+			t.Fatalf("get message %d: %v", i+1, err)
+		}
+		if got.Subject != "reviews."+string(m.EventType) || string(got.Data) != string(m.Payload) ||
+			got.Header.Get(EventHeader) != string(m.EventType) || got.Header.Get(KeyHeader) != m.Key ||
+			got.Header.Get(MsgIDHeader) != []string{"1", "2", "3"}[i] {
+			t.Fatalf("message %d stored as %+v", i, got)
+		}
+	}
+	connz, err := s.Connz(&server.ConnzOptions{})
+	if err != nil {
+		t.Fatalf("connz: %v", err)
+	}
+	var named bool
+	for _, c := range connz.Conns {
+		named = named || c.Name == "e2e"
+	}
+	if !named {
+		t.Fatalf("expected a connection named e2e, got %+v", connz.Conns)
+	}
+
+	// a retried batch is dropped by the duplicate window
+	if err := publisher.Send(ctx, testMessages); err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	if info, err := stream.Info(ctx); err != nil || info.State.Msgs != uint64(len(testMessages)) {
+		t.Fatalf("expected duplicates to be dropped, got %+v (%v)", info, err)
+	}
+
+	// without a stream capturing the subject nothing acknowledges the message
+	orphan := NewPublisher(serverURL(s, "svc:secret"), "orphans", "", true, time.Second)
+	defer orphan.Close()
+	if err := orphan.Send(ctx, testMessages[:1]); !errors.Is(err, jetstream.ErrNoStreamResponse) {
+		t.Fatalf("expected a missing stream to fail the send, got %v", err)
+	}
+}
+
+func TestPublisherCore(t *testing.T) {
+	s := runServer(t)
+	sub, err := connect(t, s).SubscribeSync(DefaultSubject + ".>")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	publisher := NewPublisher(serverURL(s, "svc:secret"), "", "", false, time.Second)
+	defer publisher.Close()
+	if err := publisher.Send(context.Background(), testMessages); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	// a closed publisher dials again on the next send
+	publisher.Close()
+	if err := publisher.Send(context.Background(), testMessages[2:]); err != nil {
+		t.Fatalf("send after close: %v", err)
+	}
+
+	for i, m := range append(testMessages, testMessages[2]) {
+		got, err := sub.NextMsg(time.Second)
+		if err != nil {
+			t.Fatalf("message %d: %v", i, err)
+		}
+		if got.Subject != DefaultSubject+"."+string(m.EventType) || string(got.Data) != string(m.Payload) ||
+			got.Header.Get(KeyHeader) != m.Key {
+			t.Fatalf("message %d published as %+v", i, got)
+		}
+	}
+}
+
+func TestPublisherConnectError(t *testing.T) {
+	s := runServer(t)
+
+	publisher := NewPublisher(serverURL(s, "svc:wrong"), "", "", false, time.Second)
+	defer publisher.Close()
+	err := publisher.Send(context.Background(), testMessages)
+	if !errors.Is(err, nats.ErrAuthorization) {
+		t.Fatalf("expected an authorization error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "wrong") {
+		t.Fatalf("expected the password to be redacted, got %v", err)
+	}
+}
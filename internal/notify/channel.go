@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SlackChannel posts messages to Slack incoming webhooks
+type SlackChannel struct {
+	client *http.Client
+}
+
+// NewSlackChannel creates a Slack incoming webhook poster; non-positive timeout uses DefaultTimeout
+func NewSlackChannel(timeout time.Duration) *SlackChannel {
+	return &SlackChannel{client: newChannelClient(timeout)}
+}
+
+// Post sends text to the channel of webhookURL
+func (c *SlackChannel) Post(ctx context.Context, webhookURL, text string) error {
+	return postChannelMessage(ctx, c.client, "slack", webhookURL, map[string]string{"text": text})
+}
+
+// MSTeamsChannel posts messages to Microsoft Teams incoming webhooks as Adaptive
+// Cards, which both Workflows webhooks and Office 365 connectors accept
+type MSTeamsChannel struct {
+	client *http.Client
+}
+
+// NewMSTeamsChannel creates a Microsoft Teams webhook poster; non-positive timeout uses DefaultTimeout
+func NewMSTeamsChannel(timeout time.Duration) *MSTeamsChannel {
+	return &MSTeamsChannel{client: newChannelClient(timeout)}
+}
+
+// Post sends text to the channel of webhookURL
+func (c *MSTeamsChannel) Post(ctx context.Context, webhookURL, text string) error {
+	payload := map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body": []map[string]any{
+					{"type": "TextBlock", "text": text, "wrap": true},
+				},
+			},
+		}},
+	}
+	return postChannelMessage(ctx, c.client, "msteams", webhookURL, payload)
+}
+
+// MattermostChannel posts messages to Mattermost incoming webhooks
+type MattermostChannel struct {
+	client *http.Client
+}
+
+// NewMattermostChannel creates a Mattermost webhook poster; non-positive timeout uses DefaultTimeout
+func NewMattermostChannel(timeout time.Duration) *MattermostChannel {
+	return &MattermostChannel{client: newChannelClient(timeout)}
+}
+
+// Post sends text to the channel of webhookURL
+func (c *MattermostChannel) Post(ctx context.Context, webhookURL, text string) error {
+	return postChannelMessage(ctx, c.client, "mattermost", webhookURL, map[string]string{"text": text, "username": "pr-service"})
+}
+
+func newChannelClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// postChannelMessage posts payload as JSON and fails on any non-2xx response,
+// quoting the start of the response body, which these webhooks use for errors
+func postChannelMessage(ctx context.Context, client *http.Client, tool, webhookURL string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s message: %w", tool, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build %s request: %w", tool, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The webhook URL carries the credentials, keep it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post %s message: %w", tool, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s responded with status %d: %s", tool, resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
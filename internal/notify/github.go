@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the GitHub REST API base URL used when none is configured
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHub calls the GitHub REST API with a token
+type GitHub struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewGitHub creates a GitHub client. An empty baseURL uses DefaultGitHubAPIURL
+// (set it for GitHub Enterprise); non-positive timeout uses DefaultTimeout.
+func NewGitHub(token, baseURL string, timeout time.Duration) *GitHub {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &GitHub{
+		token:   token,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// RequestReviewers requests reviews from logins on pull request number of repo ("owner/name")
+func (g *GitHub) RequestReviewers(ctx context.Context, repo string, number int, logins []string) error {
+	return g.reviewers(ctx, http.MethodPost, repo, number, logins)
+}
+
+// RemoveReviewRequests withdraws the review requests of logins on pull request number of repo
+func (g *GitHub) RemoveReviewRequests(ctx context.Context, repo string, number int, logins []string) error {
+	return g.reviewers(ctx, http.MethodDelete, repo, number, logins)
+}
+
+func (g *GitHub) reviewers(ctx context.Context, method, repo string, number int, logins []string) error {
+	body, err := json.Marshal(map[string][]string{"reviewers": logins})
+	if err != nil {
+		return fmt.Errorf("failed to encode github request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", g.baseURL, repo, number)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build github request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call github: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var result struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&result)
+		return fmt.Errorf("github responded with status %d: %s", resp.StatusCode, result.Message)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Jira calls the Jira REST API. Jira Cloud authenticates with an account email
+// and API token (basic auth); Jira Data Center with a personal access token.
+type Jira struct {
+	baseURL string
+	email   string
+	token   string
+	client  *http.Client
+}
+
+// NewJira creates a Jira client for the instance at baseURL. With an empty email
+// token is sent as a bearer personal access token; non-positive timeout uses DefaultTimeout.
+func NewJira(baseURL, email, token string, timeout time.Duration) *Jira {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Jira{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		email:   email,
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// IssueExists reports whether the issue with key exists and is visible to the client
+func (j *Jira) IssueExists(ctx context.Context, key string) (bool, error) {
+	resp, err := j.do(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields=summary", nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return true, nil
+	default:
+		return false, jiraError(resp)
+	}
+}
+
+// AddComment posts body as a comment on the issue with key
+func (j *Jira) AddComment(ctx context.Context, key, body string) error {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("failed to encode jira comment: %w", err)
+	}
+
+	resp, err := j.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return jiraError(resp)
+	}
+	return nil
+}
+
+func (j *Jira) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, j.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build jira request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.email != "" {
+		req.SetBasicAuth(j.email, j.token)
+	} else if j.token != "" {
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call jira: %w", err)
+	}
+	return resp, nil
+}
+
+// jiraError reports a failed response with the messages Jira returned
+func jiraError(resp *http.Response) error {
+	var result struct {
+		ErrorMessages []string `json:"errorMessages"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&result)
+	return fmt.Errorf("jira responded with status %d: %s", resp.StatusCode, strings.Join(result.ErrorMessages, "; "))
+}
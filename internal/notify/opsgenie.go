@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+const (
+	// DefaultOpsgenieAPIURL is the Opsgenie API base URL used when none is configured;
+	// EU accounts use https://api.eu.opsgenie.com
+	DefaultOpsgenieAPIURL = "https://api.opsgenie.com"
+	// DefaultOpsgeniePriority is the alert priority used when none is configured
+	DefaultOpsgeniePriority = "P3"
+	// opsgenieMessageLimit is the maximum length of an alert message
+	opsgenieMessageLimit = 130
+)
+
+// Opsgenie creates alerts through the Opsgenie Alert API
+type Opsgenie struct {
+	apiKey   string
+	baseURL  string
+	priority string
+	client   *http.Client
+}
+
+// NewOpsgenie creates an Opsgenie client for an API integration key. Empty baseURL
+// and priority use the defaults; non-positive timeout uses DefaultTimeout.
+func NewOpsgenie(apiKey, baseURL, priority string, timeout time.Duration) *Opsgenie {
+	if baseURL == "" {
+		baseURL = DefaultOpsgenieAPIURL
+	}
+	if priority == "" {
+		priority = DefaultOpsgeniePriority
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Opsgenie{
+		apiKey:   apiKey,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		priority: priority,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Escalate creates an alert for an overdue review. The alias is derived from the
+// review, so Opsgenie deduplicates repeated escalations while the alert is open.
+func (o *Opsgenie) Escalate(ctx context.Context, escalation domain.Escalation) error {
+	message := escalationSummary(escalation)
+	if len(message) > opsgenieMessageLimit {
+		message = message[:opsgenieMessageLimit-3] + "..."
+	}
+	tags := []string{"pr-service", "review-sla"}
+	if escalation.TeamName != "" {
+		tags = append(tags, escalation.TeamName)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"message":     message,
+		"alias":       escalationKey(escalation),
+		"description": escalationSummary(escalation),
+		"source":      "pr-service",
+		"entity":      escalation.PullRequestID,
+		"priority":    o.priority,
+		"tags":        tags,
+		"details":     escalationDetails(escalation),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode opsgenie alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/v2/alerts", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build opsgenie request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+o.apiKey)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call opsgenie: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var result struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&result)
+		return fmt.Errorf("opsgenie responded with status %d: %s", resp.StatusCode, result.Message)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+const (
+	// DefaultPagerDutyEventsURL is the PagerDuty Events API v2 endpoint used when none is configured
+	DefaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	// DefaultPagerDutySeverity is the alert severity used when none is configured
+	DefaultPagerDutySeverity = "warning"
+)
+
+// PagerDuty triggers alerts through the PagerDuty Events API v2
+type PagerDuty struct {
+	routingKey string
+	url        string
+	severity   string
+	client     *http.Client
+}
+
+// NewPagerDuty creates a PagerDuty client for the service integration routingKey.
+// Empty url and severity use the defaults; non-positive timeout uses DefaultTimeout.
+func NewPagerDuty(routingKey, url, severity string, timeout time.Duration) *PagerDuty {
+	if url == "" {
+		url = DefaultPagerDutyEventsURL
+	}
+	if severity == "" {
+		severity = DefaultPagerDutySeverity
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &PagerDuty{
+		routingKey: routingKey,
+		url:        url,
+		severity:   severity,
+		client:     &http.Client{Timeout: timeout},
+	}
+}
+
+// Escalate triggers an alert for an overdue review. The dedup key is derived from
+// the review, so PagerDuty folds repeated escalations into one incident.
+func (p *PagerDuty) Escalate(ctx context.Context, escalation domain.Escalation) error {
+	body, err := json.Marshal(map[string]any{
+		"routing_key":  p.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    escalationKey(escalation),
+		"payload": map[string]any{
+			"summary":        escalationSummary(escalation),
+			"source":         "pr-service",
+			"severity":       p.severity,
+			"timestamp":      escalation.DueAt.UTC().Format(time.RFC3339),
+			"component":      escalation.PullRequestID,
+			"group":          escalation.TeamName,
+			"class":          "review_sla_breach",
+			"custom_details": escalationDetails(escalation),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode pagerduty event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build pagerduty request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call pagerduty: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var result struct {
+			Message string   `json:"message"`
+			Errors  []string `json:"errors"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&result)
+		return fmt.Errorf("pagerduty responded with status %d: %s %v", resp.StatusCode, result.Message, result.Errors)
+	}
+	return nil
+}
+
+// escalationKey identifies the review an escalation is about
+func escalationKey(escalation domain.Escalation) string {
+	return fmt.Sprintf("pr-service/review/%s/%s", escalation.PullRequestID, escalation.UserID)
+}
+
+func escalationSummary(escalation domain.Escalation) string {
+	return fmt.Sprintf("Review of %q (%s) by %s is overdue since %s",
+		escalation.PullRequestName, escalation.PullRequestID, escalation.UserID,
+		escalation.DueAt.UTC().Format("2006-01-02 15:04 UTC"))
+}
+
+func escalationDetails(escalation domain.Escalation) map[string]string {
+	return map[string]string{
+		"pull_request_id":   escalation.PullRequestID,
+		"pull_request_name": escalation.PullRequestName,
+		"team_name":         escalation.TeamName,
+		"reviewer_id":       escalation.UserID,
+		"assigned_at":       escalation.AssignedAt.UTC().Format(time.RFC3339),
+		"due_at":            escalation.DueAt.UTC().Format(time.RFC3339),
+	}
+}
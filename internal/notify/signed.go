@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body keyed with the subscription secret
+	SignatureHeader = "X-PR-Service-Signature"
+	// EventHeader carries the event type of the payload
+	EventHeader = "X-PR-Service-Event"
+	// DeliveryHeader carries the delivery ID, stable across retries so receivers can deduplicate
+	DeliveryHeader = "X-PR-Service-Delivery"
+)
+
+// SignedSender posts pre-encoded JSON payloads signed with a per-receiver secret
+type SignedSender struct {
+	client *http.Client
+}
+
+// NewSignedSender creates a signed sender; non-positive timeout uses DefaultTimeout
+func NewSignedSender(timeout time.Duration) *SignedSender {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &SignedSender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts body to url and returns the response status, or 0 when no response
+// was received. Any non-2xx response is an error.
+func (s *SignedSender) Send(ctx context.Context, url, secret, event string, deliveryID int64, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(SignatureHeader, Sign([]byte(secret), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the SignatureHeader value of body for secret
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultSlackAPIURL is the Slack Web API base URL used when none is configured
+const DefaultSlackAPIURL = "https://slack.com/api"
+
+// Slack sends messages through the Slack Web API with a bot token
+type Slack struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewSlack creates a Slack client. An empty baseURL uses DefaultSlackAPIURL;
+// non-positive timeout uses DefaultTimeout.
+func NewSlack(token, baseURL string, timeout time.Duration) *Slack {
+	if baseURL == "" {
+		baseURL = DefaultSlackAPIURL
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Slack{
+		token:   token,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// PostMessage posts text to channel, which may be a Slack user ID to send a direct message.
+// Slack reports API errors with HTTP 200 and ok=false, which are returned as errors too.
+func (s *Slack) PostMessage(ctx context.Context, channel, text string) error {
+	body, err := json.Marshal(map[string]string{"channel": channel, "text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack responded with status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack rejected message: %s", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Package pagination is the paging contract of list endpoints. Pages are
+// requested with limit, an opaque cursor and an order; responses return
+// next_cursor, which is empty on the last page. Cursors are keyset positions,
+// so pages stay stable while rows are inserted, unlike offsets.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+const (
+	// DefaultLimit is the page size when the client does not specify one
+	DefaultLimit = 50
+	// MaxLimit caps the page size
+	MaxLimit = 100
+)
+
+// Order is the direction of the sort key of a listing
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// SQL returns the ORDER BY direction
+func (o Order) SQL() string {
+	if o == OrderAsc {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// After returns the comparison selecting sort keys past a cursor's key
+func (o Order) After() string {
+	if o == OrderAsc {
+		return ">"
+	}
+	return "<"
+}
+
+// Cursor is the position after the last item of a page: its sort key and an
+// ID that breaks ties between equal keys. Ties are always broken by ascending ID.
+type Cursor struct {
+	Key string `json:"k"`
+	ID  string `json:"i"`
+}
+
+// TimeCursor returns the cursor of an item sorted by a timestamp
+func TimeCursor(t time.Time, id string) Cursor {
+	return Cursor{Key: t.UTC().Format(time.RFC3339Nano), ID: id}
+}
+
+// Time parses the key of a cursor made by TimeCursor
+func (c Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, errInvalidCursor
+	}
+	return t, nil
+}
+
+// Encode returns the opaque form handed to clients
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a cursor produced by Encode
+func Decode(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return Cursor{}, errInvalidCursor
+	}
+	return c, nil
+}
+
+var errInvalidCursor = domain.NewValidationError("cursor", "must be a next_cursor returned by the listing")
+
+// Page is a request for a page of a listing. Offset is kept for endpoints that
+// predate cursors and cannot be combined with After.
+type Page struct {
+	Limit  int
+	Offset int
+	After  *Cursor
+	Order  Order
+}
+
+// Fetch is the number of rows to query: one more than the page, which tells
+// whether another page follows
+func (p Page) Fetch() int {
+	return p.Limit + 1
+}
+
+// ParseRequest reads the limit, offset, cursor and order query parameters
+// and normalizes the page
+func ParseRequest(r *http.Request, defaultOrder Order) (Page, error) {
+	query := r.URL.Query()
+	var page Page
+	var v domain.Validator
+
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		v.Check(err == nil, "limit", "must be a non-negative integer")
+		page.Limit = limit
+	}
+	if raw := strings.TrimSpace(query.Get("offset")); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		v.Check(err == nil, "offset", "must be a non-negative integer")
+		page.Offset = offset
+	}
+	if raw := strings.TrimSpace(query.Get("cursor")); raw != "" {
+		cursor, err := Decode(raw)
+		if err != nil {
+			return Page{}, err
+		}
+		page.After = &cursor
+	}
+	page.Order = Order(strings.ToLower(strings.TrimSpace(query.Get("order"))))
+
+	if err := v.Err(); err != nil {
+		return Page{}, err
+	}
+	return page.Normalize(defaultOrder)
+}
+
+// Normalize checks a page and fills in DefaultLimit and defaultOrder
+func (p Page) Normalize(defaultOrder Order) (Page, error) {
+	var v domain.Validator
+	v.Check(p.Limit >= 0, "limit", "must be a non-negative integer")
+	v.Check(p.Limit <= MaxLimit, "limit", fmt.Sprintf("must be at most %d", MaxLimit))
+	v.Check(p.Offset >= 0, "offset", "must be a non-negative integer")
+	v.Check(p.Offset == 0 || p.After == nil, "offset", "cannot be combined with cursor")
+	v.Check(p.Order == "" || p.Order == OrderAsc || p.Order == OrderDesc, "order", "must be asc or desc")
+	if err := v.Err(); err != nil {
+		return Page{}, err
+	}
+
+	if p.Limit == 0 {
+		p.Limit = DefaultLimit
+	}
+	if p.Order == "" {
+		p.Order = defaultOrder
+	}
+	return p, nil
+}
+
+// Result is a page of a listing with the total number of matching items
+type Result[T any] struct {
+	Items      []T
+	Total      int
+	NextCursor string
+}
+
+// Trim cuts items fetched with Page.Fetch down to the page and returns the
+// encoded cursor of the next page, or "" when this is the last one
+func Trim[T any](items []T, page Page, cursorOf func(T) Cursor) ([]T, string) {
+	if len(items) <= page.Limit {
+		return items, ""
+	}
+	items = items[:page.Limit]
+	return items, cursorOf(items[len(items)-1]).Encode()
+}
@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.FixedZone("MSK", 3*3600))
+	cursor, err := Decode(TimeCursor(created, "pr-7").Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cursor.Time()
+	if err != nil || !got.Equal(created) || cursor.ID != "pr-7" {
+		t.Fatalf("decoded %+v (%v, %v), want %v and pr-7", cursor, got, err, created)
+	}
+
+	for _, raw := range []string{"%%%", "bm90IGpzb24", "e30"} {
+		if _, err := Decode(raw); !errors.Is(err, domain.ErrInvalidArgument) {
+			t.Errorf("Decode(%q) = %v, want invalid argument", raw, err)
+		}
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	cursor := Cursor{Key: "k", ID: "id"}.Encode()
+	cases := map[string]Page{
+		"":                            {Limit: DefaultLimit, Order: OrderDesc},
+		"?limit=5&order=ASC":          {Limit: 5, Order: OrderAsc},
+		"?limit=0&offset=10":          {Limit: DefaultLimit, Offset: 10, Order: OrderDesc},
+		"?cursor=" + cursor:           {Limit: DefaultLimit, After: &Cursor{Key: "k", ID: "id"}, Order: OrderDesc},
+		"?limit=100&cursor=" + cursor: {Limit: MaxLimit, After: &Cursor{Key: "k", ID: "id"}, Order: OrderDesc},
+	}
+	for query, want := range cases {
+		got, err := ParseRequest(httptest.NewRequest("GET", "/list"+query, nil), OrderDesc)
+		if err != nil {
+			t.Errorf("%q: %v", query, err)
+			continue
+		}
+		if got.Limit != want.Limit || got.Offset != want.Offset || got.Order != want.Order ||
+			(got.After == nil) != (want.After == nil) || got.After != nil && *got.After != *want.After {
+			t.Errorf("%q = %+v, want %+v", query, got, want)
+		}
+	}
+
+	for _, query := range []string{"?limit=-1", "?limit=101", "?limit=x", "?offset=-1", "?order=up", "?cursor=x", "?offset=1&cursor=" + cursor} {
+		if _, err := ParseRequest(httptest.NewRequest("GET", "/list"+query, nil), OrderDesc); !errors.Is(err, domain.ErrInvalidArgument) {
+			t.Errorf("%q = %v, want invalid argument", query, err)
+		}
+	}
+}
+
+func TestTrim(t *testing.T) {
+	page := Page{Limit: 2}
+	id := func(s string) Cursor { return Cursor{ID: s} }
+
+	items, next := Trim([]string{"a", "b"}, page, id)
+	if len(items) != 2 || next != "" {
+		t.Fatalf("full last page = %v, %q", items, next)
+	}
+	items, next = Trim([]string{"a", "b", "c"}, page, id)
+	if len(items) != 2 || next != id("b").Encode() {
+		t.Fatalf("page with more = %v, %q", items, next)
+	}
+}
@@ -0,0 +1,186 @@
+// Package prquery parses the PR search syntax of the list endpoint, such as
+// `status:OPEN author:u1,u2 created:>=2024-01-01 repo:"acme/api"`, into a
+// domain.PRFilter. Terms are field:value pairs separated by spaces and combine
+// with AND; comma-separated or repeated values of one field are alternatives,
+// except that repeated created and merged bounds narrow the range. The query
+// text never reaches SQL: the repository binds the filter as parameters.
+package prquery
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+// Param is the query parameter the search is read from; errors name it
+const Param = "q"
+
+// MaxTerms caps the number of terms in one query
+const MaxTerms = 32
+
+// Fields lists the supported field names in the order they are documented
+var Fields = []string{"status", "author", "team", "repo", "ticket", "reviewer", "created", "merged"}
+
+const dateLayout = "2006-01-02"
+
+// Parse parses q into a filter. An empty q matches every PR.
+func Parse(q string) (domain.PRFilter, error) {
+	var filter domain.PRFilter
+
+	terms, err := split(q)
+	if err != nil {
+		return domain.PRFilter{}, err
+	}
+	if len(terms) > MaxTerms {
+		return domain.PRFilter{}, invalid("must have at most %d terms", MaxTerms)
+	}
+
+	for _, term := range terms {
+		field, value, ok := strings.Cut(term, ":")
+		if !ok || field == "" {
+			return domain.PRFilter{}, invalid("term %q must have the form field:value", term)
+		}
+		if value == "" {
+			return domain.PRFilter{}, invalid("term %q must have a value", term)
+		}
+
+		switch strings.ToLower(field) {
+		case "status":
+			for _, v := range values(value) {
+				status := domain.PRStatus(strings.ToUpper(v))
+				if status != domain.PRStatusOpen && status != domain.PRStatusMerged {
+					return domain.PRFilter{}, invalid("status %q must be one of OPEN, MERGED", v)
+				}
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		case "author":
+			filter.AuthorIDs = append(filter.AuthorIDs, values(value)...)
+		case "team":
+			filter.TeamNames = append(filter.TeamNames, values(value)...)
+		case "repo", "repository":
+			filter.Repositories = append(filter.Repositories, values(value)...)
+		case "ticket":
+			filter.TicketKeys = append(filter.TicketKeys, values(value)...)
+		case "reviewer":
+			filter.ReviewerIDs = append(filter.ReviewerIDs, values(value)...)
+		case "created":
+			if err := narrow(&filter.CreatedFrom, &filter.CreatedTo, value); err != nil {
+				return domain.PRFilter{}, err
+			}
+		case "merged":
+			if err := narrow(&filter.MergedFrom, &filter.MergedTo, value); err != nil {
+				return domain.PRFilter{}, err
+			}
+		default:
+			return domain.PRFilter{}, invalid("unknown field %q, use one of %s", field, strings.Join(Fields, ", "))
+		}
+	}
+	return filter, nil
+}
+
+// split breaks q into terms at spaces outside double quotes
+func split(q string) ([]string, error) {
+	var (
+		terms   []string
+		term    strings.Builder
+		inQuote bool
+	)
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			term.WriteRune(r)
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, invalid("has an unterminated quote")
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+// values splits a comma-separated value list; a quoted value is taken whole
+func values(value string) []string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return []string{value[1 : len(value)-1]}
+	}
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// narrow intersects the half-open range [*from, *to) with a bound such as
+// ">2024-01-01", "<=2024-03-01T12:00:00Z" or a bare date, which means that day
+func narrow(from, to **time.Time, value string) error {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(value, candidate) {
+			op, value = candidate, value[len(candidate):]
+			break
+		}
+	}
+
+	at, step, err := parseTime(value)
+	if err != nil {
+		return err
+	}
+	next := at.Add(step)
+
+	switch op {
+	case ">=":
+		setLater(from, at)
+	case ">":
+		setLater(from, next)
+	case "<":
+		setEarlier(to, at)
+	case "<=":
+		setEarlier(to, next)
+	default:
+		setLater(from, at)
+		setEarlier(to, next)
+	}
+	return nil
+}
+
+// parseTime reads a date or an RFC 3339 timestamp and returns its precision:
+// a day for dates and a microsecond, the database's resolution, for timestamps
+func parseTime(value string) (time.Time, time.Duration, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, 24 * time.Hour, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), time.Microsecond, nil
+	}
+	return time.Time{}, 0, invalid("time %q must be a YYYY-MM-DD date or an RFC 3339 timestamp", value)
+}
+
+func setLater(bound **time.Time, t time.Time) {
+	if *bound == nil || t.After(**bound) {
+		*bound = &t
+	}
+}
+
+func setEarlier(bound **time.Time, t time.Time) {
+	if *bound == nil || t.Before(**bound) {
+		*bound = &t
+	}
+}
+
+func invalid(format string, args ...any) error {
+	return domain.NewValidationError(Param, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,79 @@
+package prquery
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestParse(t *testing.T) {
+	noon := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	afterNoon := noon.Add(time.Microsecond)
+
+	cases := map[string]domain.PRFilter{
+		"": {},
+		"status:open author:u1,u2 author:u3": {
+			Statuses:  []domain.PRStatus{domain.PRStatusOpen},
+			AuthorIDs: []string{"u1", "u2", "u3"},
+		},
+		`team:backend repo:"acme/api, v2" ticket:PAY-1 reviewer:u9`: {
+			TeamNames:    []string{"backend"},
+			Repositories: []string{"acme/api, v2"},
+			TicketKeys:   []string{"PAY-1"},
+			ReviewerIDs:  []string{"u9"},
+		},
+		"created:>2024-01-01 created:<=2024-01-31": {
+			CreatedFrom: date(2024, 1, 2),
+			CreatedTo:   date(2024, 2, 1),
+		},
+		"created:2024-01-01 created:>=2023-12-01": {
+			CreatedFrom: date(2024, 1, 1),
+			CreatedTo:   date(2024, 1, 2),
+		},
+		"Merged:>2024-03-01T12:00:00Z": {
+			MergedFrom: &afterNoon,
+		},
+		"merged:<2024-03-01T15:00:00+03:00": {
+			MergedTo: &noon,
+		},
+	}
+	for q, want := range cases {
+		got, err := Parse(q)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", q, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", q, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"label:payments":                      `unknown field "label"`,
+		"payments":                            "must have the form field:value",
+		"author:":                             "must have a value",
+		"status:CLOSED":                       `status "CLOSED" must be one of OPEN, MERGED`,
+		"created:>yesterday":                  `time "yesterday" must be`,
+		`repo:"acme/api`:                      "unterminated quote",
+		strings.Repeat("team:a ", MaxTerms+1): "at most 32 terms",
+	}
+	for q, want := range cases {
+		_, err := Parse(q)
+		var validationErr *domain.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Violations[0].Field != Param ||
+			!strings.Contains(validationErr.Violations[0].Constraint, want) {
+			t.Errorf("Parse(%q) = %v, want a %s violation containing %q", q, err, Param, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package ratelimit provides an in-process fixed-window request limiter.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Status describes a client's quota after a request was counted
+type Status struct {
+	// Allowed reports whether the request fits into the quota
+	Allowed bool
+	// Limit is the number of requests allowed per window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is the time until the current window ends and the quota refills
+	Reset time.Duration
+}
+
+// Limiter allows each key Limit requests per window. Windows are aligned for
+// all keys, so counters of the previous window are dropped at once and memory
+// stays bounded by the number of clients seen in one window.
+type Limiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+// New creates a limiter allowing limit requests per window; limit <= 0 or
+// window <= 0 returns nil, which allows everything
+func New(limit int, window time.Duration) *Limiter {
+	return NewWithClock(limit, window, time.Now)
+}
+
+// NewWithClock is New with a custom clock, for tests
+func NewWithClock(limit int, window time.Duration, now func() time.Time) *Limiter {
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+	return &Limiter{
+		limit:  limit,
+		window: window,
+		now:    now,
+		counts: make(map[string]int),
+	}
+}
+
+// Allow counts a request by key and reports whether it is within the quota.
+// Rejected requests are not counted against the next window.
+func (l *Limiter) Allow(key string) Status {
+	if l == nil {
+		return Status{Allowed: true}
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if start := now.Truncate(l.window); !start.Equal(l.start) {
+		l.start = start
+		clear(l.counts)
+	}
+
+	used := l.counts[key]
+	allowed := used < l.limit
+	if allowed {
+		used++
+		l.counts[key] = used
+	}
+	return Status{
+		Allowed:   allowed,
+		Limit:     l.limit,
+		Remaining: l.limit - used,
+		Reset:     l.start.Add(l.window).Sub(now),
+	}
+}
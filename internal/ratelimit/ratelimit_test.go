@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 10, 0, time.UTC)
+	l := NewWithClock(2, time.Minute, func() time.Time { return now })
+
+	for i, want := range []Status{
+		{Allowed: true, Limit: 2, Remaining: 1, Reset: 50 * time.Second},
+		{Allowed: true, Limit: 2, Remaining: 0, Reset: 50 * time.Second},
+		{Allowed: false, Limit: 2, Remaining: 0, Reset: 50 * time.Second},
+	} {
+		if got := l.Allow("u1"); got != want {
+			t.Fatalf("request %d: got %+v, want %+v", i+1, got, want)
+		}
+	}
+
+	// Keys have separate quotas
+	if got := l.Allow("u2"); !got.Allowed || got.Remaining != 1 {
+		t.Fatalf("expected another key to be allowed, got %+v", got)
+	}
+
+	// The quota refills when the window ends
+	now = now.Add(50 * time.Second)
+	if got := l.Allow("u1"); !got.Allowed || got.Remaining != 1 || got.Reset != time.Minute {
+		t.Fatalf("expected a fresh window, got %+v", got)
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	l := New(0, time.Minute)
+	if l != nil {
+		t.Fatalf("expected a zero limit to disable limiting")
+	}
+	if got := l.Allow("u1"); !got.Allowed {
+		t.Fatalf("expected a nil limiter to allow everything, got %+v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type auditLogRepository struct {
+	BaseRepository
+}
+
+// NewAuditLogRepository creates a new API request audit log repository
+func NewAuditLogRepository(cm db.EngineFactory) AuditLogRepository {
+	return &auditLogRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// RecordAuditEntry appends an entry to the audit log
+func (r *auditLogRepository) RecordAuditEntry(ctx context.Context, entry domain.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor, method, route, path, payload_hash, status, error_code, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, entry.Actor, entry.Method, entry.Route, entry.Path,
+		entry.PayloadHash, entry.Status, entry.ErrorCode, entry.RequestID, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// auditFilterCondition selects the entries matching the filter arguments built
+// by auditFilterArgs, numbered from $1
+const auditFilterCondition = `
+	($1 = '' OR actor = $1)
+	AND ($2 = '' OR route = $2)
+	AND ($3::boolean IS NULL OR (status >= 400) = $3)
+	AND ($4::timestamp IS NULL OR created_at >= $4)
+	AND ($5::timestamp IS NULL OR created_at < $5)
+`
+
+const auditFilterParams = 5
+
+func auditFilterArgs(filter domain.AuditFilter) []any {
+	var from, to *time.Time
+	if !filter.From.IsZero() {
+		from = &filter.From
+	}
+	if !filter.To.IsZero() {
+		to = &filter.To
+	}
+	return []any{filter.Actor, filter.Route, filter.Failed, from, to}
+}
+
+// ListAuditEntries returns up to page.Fetch() entries matching filter, ordered
+// by ID, which follows the order they were recorded in, and the total number
+// of matching entries
+func (r *auditLogRepository) ListAuditEntries(ctx context.Context, filter domain.AuditFilter, page pagination.Page) ([]domain.AuditEntry, int, error) {
+	args := auditFilterArgs(filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM audit_log WHERE ` + auditFilterCondition
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	var afterID *int64
+	if page.After != nil {
+		id, err := strconv.ParseInt(page.After.ID, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+		}
+		afterID = &id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, actor, method, route, path, payload_hash, status, error_code, request_id, created_at
+		FROM audit_log
+		WHERE %[3]s
+			AND ($%[6]d::bigint IS NULL OR id %[1]s $%[6]d)
+		ORDER BY id %[2]s
+		LIMIT $%[4]d OFFSET $%[5]d
+	`, page.Order.After(), page.Order.SQL(), auditFilterCondition,
+		auditFilterParams+1, auditFilterParams+2, auditFilterParams+3)
+	args = append(args, page.Fetch(), page.Offset, afterID)
+	var entries []domain.AuditEntry
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &entries, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type auditRepository struct {
+	BaseRepository
+}
+
+// NewAuditRepository creates a new membership audit log repository
+func NewAuditRepository(cm db.EngineFactory) AuditRepository {
+	return &auditRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// RecordMembershipEvents appends events to the audit log in one statement
+func (r *auditRepository) RecordMembershipEvents(ctx context.Context, events []domain.MembershipEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var (
+		teamNames = make([]string, len(events))
+		userIDs   = make([]string, len(events))
+		actions   = make([]string, len(events))
+		fromTeams = make([]string, len(events))
+	)
+	for i, event := range events {
+		teamNames[i] = event.TeamName
+		userIDs[i] = event.UserID
+		actions[i] = string(event.Action)
+		fromTeams[i] = event.FromTeamName
+	}
+
+	query := `
+		INSERT INTO membership_audit_log (team_name, user_id, action, from_team_name, created_at)
+		SELECT team_name, user_id, action, NULLIF(from_team_name, ''), $5
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[])
+			AS e(team_name, user_id, action, from_team_name)
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, teamNames, userIDs, actions, fromTeams, events[0].CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record membership events: %w", err)
+	}
+	return nil
+}
+
+// ListMembershipEvents returns a page of a team's audit log, newest first,
+// including moves out of the team, and the total number of entries
+func (r *auditRepository) ListMembershipEvents(ctx context.Context, teamName string, limit, offset int) ([]domain.MembershipEvent, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM membership_audit_log
+		WHERE team_name = $1 OR from_team_name = $1
+	`
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("failed to count membership events: %w", err)
+	}
+
+	query := `
+		SELECT id, team_name, user_id, action, COALESCE(from_team_name, '') AS from_team_name, created_at
+		FROM membership_audit_log
+		WHERE team_name = $1 OR from_team_name = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`
+	var events []domain.MembershipEvent
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &events, query, teamName, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list membership events: %w", err)
+	}
+
+	return events, total, nil
+}
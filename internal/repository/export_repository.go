@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type exportRepository struct {
+	BaseRepository
+}
+
+// NewExportRepository creates a new repository for full data dumps
+func NewExportRepository(cm db.EngineFactory) ExportRepository {
+	return &exportRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// ExportData reads every team, user, membership, PR and reviewer assignment.
+// Run it in a transaction to get a consistent snapshot.
+func (r *exportRepository) ExportData(ctx context.Context) (domain.DataExport, error) {
+	var data domain.DataExport
+
+	teamsQuery := `
+		SELECT team_name, COALESCE(parent_team_name, '') AS parent_team_name,
+			COALESCE(notification_channel_type, '') AS notification_channel_type,
+			COALESCE(notification_channel_url, '') AS notification_channel_url,
+			created_at, updated_at, deleted_at
+		FROM teams
+		ORDER BY team_name
+	`
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &data.Teams, teamsQuery); err != nil {
+		return domain.DataExport{}, fmt.Errorf("failed to export teams: %w", err)
+	}
+
+	usersQuery := `
+		SELECT user_id, username, is_active, role, created_at, updated_at, deleted_at, last_seen_at
+		FROM users
+		ORDER BY user_id
+	`
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &data.Users, usersQuery); err != nil {
+		return domain.DataExport{}, fmt.Errorf("failed to export users: %w", err)
+	}
+
+	membershipsQuery := `
+		SELECT team_name, user_id, joined_at
+		FROM team_members
+		ORDER BY team_name, user_id
+	`
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &data.Memberships, membershipsQuery); err != nil {
+		return domain.DataExport{}, fmt.Errorf("failed to export memberships: %w", err)
+	}
+
+	prsQuery := `
+		SELECT pull_request_id, pull_request_name, author_id, COALESCE(team_name, '') AS team_name,
+			COALESCE(repository, '') AS repository, COALESCE(ticket_key, '') AS ticket_key,
+			status, created_at, merged_at
+		FROM pull_requests
+		ORDER BY created_at, pull_request_id
+	`
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &data.PullRequests, prsQuery); err != nil {
+		return domain.DataExport{}, fmt.Errorf("failed to export pull requests: %w", err)
+	}
+
+	reviewersQuery := `
+		SELECT pull_request_id, user_id, assigned_at, first_action_at, stale_notified_at, escalated_at
+		FROM pr_reviewers
+		ORDER BY pull_request_id, assigned_at, user_id
+	`
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &data.Reviewers, reviewersQuery); err != nil {
+		return domain.DataExport{}, fmt.Errorf("failed to export reviewers: %w", err)
+	}
+
+	return data, nil
+}
+
+// HasData reports whether any team, user or PR exists
+func (r *exportRepository) HasData(ctx context.Context) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM teams)
+			OR EXISTS(SELECT 1 FROM users)
+			OR EXISTS(SELECT 1 FROM pull_requests)
+	`
+	var exists bool
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &exists, query); err != nil {
+		return false, fmt.Errorf("failed to check for existing data: %w", err)
+	}
+	return exists, nil
+}
+
+// ImportData inserts a dump, one statement per table in dependency order.
+// Run it in a transaction so a failed import leaves nothing behind.
+func (r *exportRepository) ImportData(ctx context.Context, data domain.DataExport) error {
+	if err := r.importTeams(ctx, data.Teams); err != nil {
+		return err
+	}
+	if err := r.importUsers(ctx, data.Users); err != nil {
+		return err
+	}
+	if err := r.importMemberships(ctx, data.Memberships); err != nil {
+		return err
+	}
+	if err := r.importPullRequests(ctx, data.PullRequests); err != nil {
+		return err
+	}
+	return r.importReviewers(ctx, data.Reviewers)
+}
+
+func (r *exportRepository) importTeams(ctx context.Context, teams []domain.ExportedTeam) error {
+	if len(teams) == 0 {
+		return nil
+	}
+
+	var (
+		names        = make([]string, len(teams))
+		parents      = make([]string, len(teams))
+		channelTypes = make([]string, len(teams))
+		channelURLs  = make([]string, len(teams))
+		createdAts   = make([]time.Time, len(teams))
+		updatedAts   = make([]time.Time, len(teams))
+		deletedAts   = make([]*time.Time, len(teams))
+	)
+	for i, team := range teams {
+		names[i] = team.TeamName
+		parents[i] = team.ParentTeamName
+		channelTypes[i] = string(team.NotificationChannelType)
+		channelURLs[i] = team.NotificationChannelURL
+		createdAts[i] = team.CreatedAt
+		updatedAts[i] = team.UpdatedAt
+		deletedAts[i] = team.DeletedAt
+	}
+
+	// Parent references are checked at the end of the statement, so teams
+	// may come in any order
+	query := `
+		WITH org AS (
+			INSERT INTO organizations (org_id)
+			VALUES (current_org_id())
+			ON CONFLICT DO NOTHING
+		)
+		INSERT INTO teams (team_name, parent_team_name, notification_channel_type,
+			notification_channel_url, created_at, updated_at, deleted_at)
+		SELECT team_name, NULLIF(parent_team_name, ''), NULLIF(channel_type, ''),
+			NULLIF(channel_url, ''), created_at, updated_at, deleted_at
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::timestamp[], $6::timestamp[], $7::timestamp[])
+			AS t(team_name, parent_team_name, channel_type, channel_url, created_at, updated_at, deleted_at)
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, names, parents, channelTypes, channelURLs, createdAts, updatedAts, deletedAts); err != nil {
+		return fmt.Errorf("failed to import teams: %w", err)
+	}
+	return nil
+}
+
+func (r *exportRepository) importUsers(ctx context.Context, users []domain.ExportedUser) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	var (
+		userIDs     = make([]string, len(users))
+		usernames   = make([]string, len(users))
+		active      = make([]bool, len(users))
+		roles       = make([]string, len(users))
+		createdAts  = make([]time.Time, len(users))
+		updatedAts  = make([]time.Time, len(users))
+		deletedAts  = make([]*time.Time, len(users))
+		lastSeenAts = make([]*time.Time, len(users))
+	)
+	for i, user := range users {
+		userIDs[i] = user.UserID
+		usernames[i] = user.Username
+		active[i] = user.IsActive
+		roles[i] = string(user.Role)
+		createdAts[i] = user.CreatedAt
+		updatedAts[i] = user.UpdatedAt
+		deletedAts[i] = user.DeletedAt
+		lastSeenAts[i] = user.LastSeenAt
+	}
+
+	query := `
+		INSERT INTO users (user_id, username, is_active, role, created_at, updated_at, deleted_at, last_seen_at)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::boolean[], $4::text[],
+			$5::timestamp[], $6::timestamp[], $7::timestamp[], $8::timestamp[])
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, userIDs, usernames, active, roles, createdAts, updatedAts, deletedAts, lastSeenAts)
+	if err != nil {
+		return fmt.Errorf("failed to import users: %w", err)
+	}
+	return nil
+}
+
+func (r *exportRepository) importMemberships(ctx context.Context, memberships []domain.ExportedMembership) error {
+	if len(memberships) == 0 {
+		return nil
+	}
+
+	var (
+		teamNames = make([]string, len(memberships))
+		userIDs   = make([]string, len(memberships))
+		joinedAts = make([]time.Time, len(memberships))
+	)
+	for i, m := range memberships {
+		teamNames[i] = m.TeamName
+		userIDs[i] = m.UserID
+		joinedAts[i] = m.JoinedAt
+	}
+
+	query := `
+		INSERT INTO team_members (team_name, user_id, joined_at)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::timestamp[])
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, teamNames, userIDs, joinedAts); err != nil {
+		return fmt.Errorf("failed to import memberships: %w", err)
+	}
+	return nil
+}
+
+func (r *exportRepository) importPullRequests(ctx context.Context, prs []domain.ExportedPullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	var (
+		prIDs        = make([]string, len(prs))
+		names        = make([]string, len(prs))
+		authorIDs    = make([]string, len(prs))
+		teamNames    = make([]string, len(prs))
+		repositories = make([]string, len(prs))
+		ticketKeys   = make([]string, len(prs))
+		statuses     = make([]string, len(prs))
+		createdAts   = make([]time.Time, len(prs))
+		mergedAts    = make([]*time.Time, len(prs))
+	)
+	for i, pr := range prs {
+		prIDs[i] = pr.PullRequestID
+		names[i] = pr.PullRequestName
+		authorIDs[i] = pr.AuthorID
+		teamNames[i] = pr.TeamName
+		repositories[i] = pr.Repository
+		ticketKeys[i] = pr.TicketKey
+		statuses[i] = string(pr.Status)
+		createdAts[i] = pr.CreatedAt
+		mergedAts[i] = pr.MergedAt
+	}
+
+	// Imported history gets monthly partitions before its rows go in, rather
+	// than landing in the default partition
+	query := `
+		SELECT create_pull_request_partitions(MIN(created_at), MAX(created_at))
+		FROM unnest($1::timestamp[]) AS p(created_at)
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, createdAts); err != nil {
+		return fmt.Errorf("failed to create pull request partitions: %w", err)
+	}
+
+	query = `
+		WITH p AS (
+			SELECT *
+			FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[],
+				$7::text[], $8::timestamp[], $9::timestamp[])
+				AS p(pull_request_id, pull_request_name, author_id, team_name, repository, ticket_key,
+					status, created_at, merged_at)
+		), ids AS (
+			INSERT INTO pull_request_ids (pull_request_id, created_at)
+			SELECT pull_request_id, created_at FROM p
+		)
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, team_name,
+			repository, ticket_key, status, created_at, merged_at)
+		SELECT pull_request_id, pull_request_name, author_id, NULLIF(team_name, ''),
+			NULLIF(repository, ''), NULLIF(ticket_key, ''), status, created_at, merged_at
+		FROM p
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, prIDs, names, authorIDs, teamNames, repositories, ticketKeys, statuses, createdAts, mergedAts)
+	if err != nil {
+		return fmt.Errorf("failed to import pull requests: %w", err)
+	}
+	return nil
+}
+
+func (r *exportRepository) importReviewers(ctx context.Context, reviewers []domain.ExportedReviewer) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	var (
+		prIDs            = make([]string, len(reviewers))
+		userIDs          = make([]string, len(reviewers))
+		assignedAts      = make([]time.Time, len(reviewers))
+		firstActionAts   = make([]*time.Time, len(reviewers))
+		staleNotifiedAts = make([]*time.Time, len(reviewers))
+		escalatedAts     = make([]*time.Time, len(reviewers))
+	)
+	for i, rv := range reviewers {
+		prIDs[i] = rv.PullRequestID
+		userIDs[i] = rv.UserID
+		assignedAts[i] = rv.AssignedAt
+		firstActionAts[i] = rv.FirstActionAt
+		staleNotifiedAts[i] = rv.StaleNotifiedAt
+		escalatedAts[i] = rv.EscalatedAt
+	}
+
+	query := `
+		WITH imported AS (
+			INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at, first_action_at, stale_notified_at, escalated_at)
+			SELECT * FROM unnest($1::text[], $2::text[], $3::timestamp[], $4::timestamp[], $5::timestamp[], $6::timestamp[])
+			RETURNING pull_request_id, user_id
+		), ` + workloadChange("imported", "") + `
+		SELECT COUNT(*) FROM imported
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, prIDs, userIDs, assignedAts, firstActionAts, staleNotifiedAts, escalatedAts)
+	if err != nil {
+		return fmt.Errorf("failed to import reviewers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+)
+
+// AuditLogRepository keeps the log of state-changing API requests
+type AuditLogRepository struct {
+	mu      sync.Mutex
+	entries []domain.AuditEntry
+}
+
+// NewAuditLogRepository creates a new in-memory audit log repository
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+func (r *AuditLogRepository) RecordAuditEntry(_ context.Context, entry domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = int64(len(r.entries) + 1)
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *AuditLogRepository) ListAuditEntries(_ context.Context, filter domain.AuditFilter, page pagination.Page) ([]domain.AuditEntry, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []domain.AuditEntry
+	for _, entry := range r.entries {
+		if (filter.Actor != "" && entry.Actor != filter.Actor) || (filter.Route != "" && entry.Route != filter.Route) ||
+			(filter.Failed != nil && entry.Succeeded() == *filter.Failed) ||
+			(!filter.From.IsZero() && entry.CreatedAt.Before(filter.From)) || (!filter.To.IsZero() && !entry.CreatedAt.Before(filter.To)) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	if page.Order == pagination.OrderDesc {
+		slices.Reverse(matched)
+	}
+	total := len(matched)
+
+	var out []domain.AuditEntry
+	for _, entry := range matched {
+		if page.After != nil {
+			after, err := strconv.ParseInt(page.After.ID, 10, 64)
+			if err != nil {
+				return nil, 0, err
+			}
+			if (page.Order == pagination.OrderDesc && entry.ID >= after) || (page.Order == pagination.OrderAsc && entry.ID <= after) {
+				continue
+			}
+		}
+		out = append(out, entry)
+	}
+	if page.Offset < len(out) {
+		out = out[page.Offset:]
+	} else {
+		out = nil
+	}
+	if len(out) > page.Fetch() {
+		out = out[:page.Fetch()]
+	}
+	return out, total, nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"pr-service/internal/domain"
+)
+
+// AuditRepository keeps the membership audit log
+type AuditRepository struct {
+	mu     sync.Mutex
+	events []domain.MembershipEvent
+}
+
+// NewAuditRepository creates a new in-memory audit repository
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+func (r *AuditRepository) RecordMembershipEvents(_ context.Context, events []domain.MembershipEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range events {
+		event.ID = int64(len(r.events) + 1)
+		r.events = append(r.events, event)
+	}
+	return nil
+}
+
+func (r *AuditRepository) ListMembershipEvents(_ context.Context, teamName string, limit, offset int) ([]domain.MembershipEvent, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matched := make([]domain.MembershipEvent, 0)
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i].TeamName == teamName || r.events[i].FromTeamName == teamName {
+			matched = append(matched, r.events[i])
+		}
+	}
+	total := len(matched)
+	offset = min(offset, total)
+	end := min(offset+limit, total)
+	return matched[offset:end], total, nil
+}
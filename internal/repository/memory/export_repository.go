@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"pr-service/internal/domain"
+)
+
+// ExportRepository dumps and restores the in-memory team, user and PR repositories
+type ExportRepository struct {
+	teams *TeamRepository
+	users *UserRepository
+	prs   *PRRepository
+}
+
+// NewExportRepository creates a new in-memory export repository over the
+// given repositories
+func NewExportRepository(teams *TeamRepository, users *UserRepository, prs *PRRepository) *ExportRepository {
+	return &ExportRepository{teams: teams, users: users, prs: prs}
+}
+
+func (r *ExportRepository) ExportData(_ context.Context) (domain.DataExport, error) {
+	var data domain.DataExport
+
+	r.teams.mu.RLock()
+	for _, teams := range []map[string]domain.Team{r.teams.teams, r.teams.deleted} {
+		for _, team := range teams {
+			exported := domain.ExportedTeam{
+				TeamName:       team.TeamName,
+				ParentTeamName: team.ParentTeamName,
+				CreatedAt:      team.CreatedAt,
+				UpdatedAt:      team.UpdatedAt,
+				DeletedAt:      team.DeletedAt,
+			}
+			if ch, ok := r.teams.channels[team.TeamName]; ok {
+				exported.NotificationChannelType = ch.Type
+				exported.NotificationChannelURL = ch.WebhookURL
+			}
+			data.Teams = append(data.Teams, exported)
+		}
+	}
+	r.teams.mu.RUnlock()
+	sort.Slice(data.Teams, func(i, j int) bool { return data.Teams[i].TeamName < data.Teams[j].TeamName })
+
+	r.users.mu.RLock()
+	for _, user := range r.users.users {
+		data.Users = append(data.Users, domain.ExportedUser{
+			UserID:     user.UserID,
+			Username:   user.Username,
+			IsActive:   user.IsActive,
+			Role:       user.Role,
+			CreatedAt:  user.CreatedAt,
+			UpdatedAt:  user.UpdatedAt,
+			DeletedAt:  user.DeletedAt,
+			LastSeenAt: user.LastSeenAt,
+		})
+		for _, teamName := range r.users.memberships[user.UserID] {
+			data.Memberships = append(data.Memberships, domain.ExportedMembership{
+				TeamName: teamName,
+				UserID:   user.UserID,
+				JoinedAt: user.CreatedAt,
+			})
+		}
+	}
+	r.users.mu.RUnlock()
+	sort.Slice(data.Users, func(i, j int) bool { return data.Users[i].UserID < data.Users[j].UserID })
+	sort.Slice(data.Memberships, func(i, j int) bool {
+		a, b := data.Memberships[i], data.Memberships[j]
+		return a.TeamName < b.TeamName || a.TeamName == b.TeamName && a.UserID < b.UserID
+	})
+
+	r.prs.mu.RLock()
+	for _, pr := range r.prs.prs {
+		data.PullRequests = append(data.PullRequests, domain.ExportedPullRequest{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			TeamName:        pr.TeamName,
+			Repository:      pr.Repository,
+			TicketKey:       pr.TicketKey,
+			Status:          pr.Status,
+			CreatedAt:       pr.CreatedAt,
+			MergedAt:        pr.MergedAt,
+		})
+		for _, userID := range pr.AssignedReviewers {
+			key := reviewKey{pr.PullRequestID, userID}
+			reviewer := domain.ExportedReviewer{PullRequestID: pr.PullRequestID, UserID: userID, AssignedAt: r.prs.assignedAt[key]}
+			if actedAt, ok := r.prs.actedAt[key]; ok {
+				reviewer.FirstActionAt = &actedAt
+			}
+			data.Reviewers = append(data.Reviewers, reviewer)
+		}
+	}
+	r.prs.mu.RUnlock()
+	sort.Slice(data.PullRequests, func(i, j int) bool {
+		return data.PullRequests[i].PullRequestID < data.PullRequests[j].PullRequestID
+	})
+
+	return data, nil
+}
+
+func (r *ExportRepository) HasData(_ context.Context) (bool, error) {
+	r.teams.mu.RLock()
+	hasTeams := len(r.teams.teams) > 0 || len(r.teams.deleted) > 0
+	r.teams.mu.RUnlock()
+	r.users.mu.RLock()
+	hasUsers := len(r.users.users) > 0
+	r.users.mu.RUnlock()
+	r.prs.mu.RLock()
+	hasPRs := len(r.prs.prs) > 0
+	r.prs.mu.RUnlock()
+	return hasTeams || hasUsers || hasPRs, nil
+}
+
+func (r *ExportRepository) ImportData(_ context.Context, data domain.DataExport) error {
+	r.teams.mu.Lock()
+	for _, team := range data.Teams {
+		teams := r.teams.teams
+		if team.DeletedAt != nil {
+			teams = r.teams.deleted
+		}
+		teams[team.TeamName] = domain.Team{
+			TeamName:       team.TeamName,
+			ParentTeamName: team.ParentTeamName,
+			CreatedAt:      team.CreatedAt,
+			UpdatedAt:      team.UpdatedAt,
+			DeletedAt:      team.DeletedAt,
+		}
+		if team.NotificationChannelType != "" {
+			r.teams.channels[team.TeamName] = domain.NotificationChannel{
+				Type:       team.NotificationChannelType,
+				WebhookURL: team.NotificationChannelURL,
+			}
+		}
+	}
+	r.teams.mu.Unlock()
+
+	r.users.mu.Lock()
+	for _, user := range data.Users {
+		r.users.users[user.UserID] = domain.User{
+			UserID:     user.UserID,
+			Username:   user.Username,
+			IsActive:   user.IsActive,
+			Role:       user.Role,
+			CreatedAt:  user.CreatedAt,
+			UpdatedAt:  user.UpdatedAt,
+			DeletedAt:  user.DeletedAt,
+			LastSeenAt: user.LastSeenAt,
+		}
+	}
+	for _, m := range data.Memberships {
+		r.users.memberships[m.UserID] = append(r.users.memberships[m.UserID], m.TeamName)
+	}
+	r.users.mu.Unlock()
+
+	r.prs.mu.Lock()
+	for _, pr := range data.PullRequests {
+		r.prs.prs[pr.PullRequestID] = domain.PullRequest{
+			PullRequestID:     pr.PullRequestID,
+			PullRequestName:   pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			TeamName:          pr.TeamName,
+			Repository:        pr.Repository,
+			TicketKey:         pr.TicketKey,
+			Status:            pr.Status,
+			AssignedReviewers: []string{},
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+		}
+	}
+	for _, reviewer := range data.Reviewers {
+		pr := r.prs.prs[reviewer.PullRequestID]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+		r.prs.prs[reviewer.PullRequestID] = pr
+		key := reviewKey{reviewer.PullRequestID, reviewer.UserID}
+		r.prs.assignedAt[key] = reviewer.AssignedAt
+		if reviewer.FirstActionAt != nil {
+			r.prs.actedAt[key] = *reviewer.FirstActionAt
+		}
+	}
+	r.prs.mu.Unlock()
+	return nil
+}
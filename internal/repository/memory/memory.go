@@ -0,0 +1,59 @@
+// Package memory implements the repositories in process memory, for demos,
+// local development without Postgres and tests. Data is lost when the process
+// exits.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"pr-service/internal/repository"
+)
+
+var (
+	_ repository.TeamRepository            = (*TeamRepository)(nil)
+	_ repository.UserRepository            = (*UserRepository)(nil)
+	_ repository.PRRepository              = (*PRRepository)(nil)
+	_ repository.ScheduledChangeRepository = (*ScheduledChangeRepository)(nil)
+	_ repository.AuditRepository           = (*AuditRepository)(nil)
+	_ repository.RollupRepository          = (*RollupRepository)(nil)
+	_ repository.ExportRepository          = (*ExportRepository)(nil)
+	_ repository.WebhookRepository         = (*WebhookRepository)(nil)
+	_ repository.TeamTokenRepository       = (*TeamTokenRepository)(nil)
+	_ repository.AuditLogRepository        = (*AuditLogRepository)(nil)
+	_ repository.OutboxRepository          = (*OutboxRepository)(nil)
+)
+
+type txKey struct{}
+
+// Transactor runs transactions one at a time, so a transaction sees no
+// writes of others while it runs. Writes are not rolled back when a
+// transaction fails.
+type Transactor struct {
+	mu sync.Mutex
+}
+
+// NewTransactor creates a new in-memory transactor
+func NewTransactor() *Transactor {
+	return &Transactor{}
+}
+
+// Do runs f in a transaction; calls nested in the transaction of an outer Do
+// join it
+func (t *Transactor) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return f(ctx)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return f(context.WithValue(ctx, txKey{}, struct{}{}))
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
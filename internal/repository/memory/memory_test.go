@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestTransactorRunsTransactionsOneAtATime(t *testing.T) {
+	tx := NewTransactor()
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		running int
+		maxSeen int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = tx.Do(context.Background(), func(ctx context.Context) error {
+				mu.Lock()
+				running++
+				maxSeen = max(maxSeen, running)
+				mu.Unlock()
+
+				// A nested call joins the transaction instead of waiting for it
+				err := tx.Do(ctx, func(context.Context) error { return nil })
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	if maxSeen != 1 {
+		t.Fatalf("expected one transaction at a time, saw %d", maxSeen)
+	}
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+// OutboxRepository keeps the event outbox; its IDs double as transaction IDs
+type OutboxRepository struct {
+	mu       sync.Mutex
+	nextID   int64
+	messages []domain.OutboxMessage
+}
+
+// NewOutboxRepository creates a new in-memory outbox repository
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+func (r *OutboxRepository) AppendOutboxMessages(_ context.Context, messages []domain.OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range messages {
+		r.nextID++
+		m.ID, m.TxID = r.nextID, uint64(r.nextID)
+		r.messages = append(r.messages, m)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) LockUnpublishedOutboxMessages(_ context.Context, limit int) ([]domain.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pending []domain.OutboxMessage
+	for _, m := range r.messages {
+		if m.PublishedAt == nil && len(pending) < limit {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+func (r *OutboxRepository) ListOutboxMessages(_ context.Context, after domain.EventCursor, limit int) ([]domain.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	messages := make([]domain.OutboxMessage, 0)
+	for _, m := range r.messages {
+		if m.ID > after.ID && len(messages) < limit {
+			messages = append(messages, m)
+		}
+	}
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkOutboxMessagesPublished(_ context.Context, ids []int64, publishedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.messages {
+		if slices.Contains(ids, r.messages[i].ID) {
+			r.messages[i].PublishedAt = &publishedAt
+		}
+	}
+	return nil
+}
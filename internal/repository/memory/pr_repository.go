@@ -0,0 +1,943 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+)
+
+// PRRepository keeps pull requests, their reviewer assignments and the
+// reassignment log. Archived PRs are only kept in archived.
+type PRRepository struct {
+	mu            sync.RWMutex
+	prs           map[string]domain.PullRequest
+	archived      map[string]domain.PullRequest
+	assignedAt    map[reviewKey]time.Time
+	actedAt       map[reviewKey]time.Time
+	staleNotified map[reviewKey]bool
+	escalated     map[reviewKey]bool
+	reassignments []loggedReassignment
+	userRepo      *UserRepository
+}
+
+type loggedReassignment struct {
+	domain.Reassignment
+	at time.Time
+}
+
+type reviewKey struct {
+	prID   string
+	userID string
+}
+
+// NewPRRepository creates a new in-memory PR repository; users resolve
+// reviewer teams for the stats
+func NewPRRepository(userRepo *UserRepository) *PRRepository {
+	r := &PRRepository{
+		prs:           make(map[string]domain.PullRequest),
+		archived:      make(map[string]domain.PullRequest),
+		assignedAt:    make(map[reviewKey]time.Time),
+		actedAt:       make(map[reviewKey]time.Time),
+		staleNotified: make(map[reviewKey]bool),
+		escalated:     make(map[reviewKey]bool),
+		userRepo:      userRepo,
+	}
+	if userRepo.teams != nil {
+		userRepo.teams.prRepo = r
+	}
+	return r
+}
+
+// retarget mirrors ON UPDATE CASCADE / ON DELETE SET NULL on pull_requests.team_name.
+func (r *PRRepository) retarget(oldTeam, newTeam string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, pr := range r.prs {
+		if pr.TeamName == oldTeam {
+			pr.TeamName = newTeam
+			r.prs[id] = pr
+		}
+	}
+}
+
+func (r *PRRepository) CreatePR(_ context.Context, pr domain.PullRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.prs[pr.PullRequestID]; exists {
+		return fmt.Errorf("pr exists: %s", pr.PullRequestID)
+	}
+	// Like pull_requests, the PR row carries no reviewers; AssignReviewers adds them
+	pr.AssignedReviewers = []string{}
+	r.prs[pr.PullRequestID] = pr
+	return nil
+}
+
+func (r *PRRepository) GetPR(_ context.Context, prID string) (domain.PullRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pr, ok := r.prs[prID]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	return clonePR(pr), nil
+}
+
+func (r *PRRepository) GetPRForUpdate(ctx context.Context, prID string) (domain.PullRequest, error) {
+	return r.GetPR(ctx, prID)
+}
+
+func (r *PRRepository) UpdatePR(_ context.Context, pr domain.PullRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.prs[pr.PullRequestID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if current.Version != pr.Version {
+		return domain.ErrVersionConflict
+	}
+	pr.Version++
+	r.prs[pr.PullRequestID] = pr
+	return nil
+}
+
+func (r *PRRepository) AssignReviewers(_ context.Context, prID string, reviewers []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr, ok := r.prs[prID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	for _, reviewer := range reviewers {
+		if !containsString(pr.AssignedReviewers, reviewer) {
+			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer)
+			r.assignedAt[reviewKey{prID, reviewer}] = time.Now()
+		}
+	}
+	r.prs[prID] = pr
+	return nil
+}
+
+func (r *PRRepository) RemoveReviewer(_ context.Context, prID string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr, ok := r.prs[prID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	filtered := make([]string, 0, len(pr.AssignedReviewers))
+	for _, reviewer := range pr.AssignedReviewers {
+		if reviewer != userID {
+			filtered = append(filtered, reviewer)
+		}
+	}
+	pr.AssignedReviewers = filtered
+	delete(r.assignedAt, reviewKey{prID, userID})
+	delete(r.actedAt, reviewKey{prID, userID})
+	r.prs[prID] = pr
+	return nil
+}
+
+func (r *PRRepository) AddReviewer(_ context.Context, prID string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr, ok := r.prs[prID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if !containsString(pr.AssignedReviewers, userID) {
+		pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+		r.assignedAt[reviewKey{prID, userID}] = time.Now()
+	}
+	r.prs[prID] = pr
+	return nil
+}
+
+func (r *PRRepository) RecordReviewerAction(_ context.Context, prID, userID string, at time.Time) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := reviewKey{prID, userID}
+	if _, ok := r.assignedAt[key]; !ok {
+		return time.Time{}, domain.ErrNotFound
+	}
+	if first, ok := r.actedAt[key]; ok {
+		return first, nil
+	}
+	r.actedAt[key] = at
+	return at, nil
+}
+
+func (r *PRRepository) GetTimeToFirstReviewByTeam(_ context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToFirstReview(from, to, repository, func(pr domain.PullRequest, _ string) string { return pr.TeamName }), nil
+}
+
+func (r *PRRepository) GetTimeToFirstReviewByUser(_ context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToFirstReview(from, to, repository, func(_ domain.PullRequest, userID string) string { return userID }), nil
+}
+
+func (r *PRRepository) GetTimeToFirstReviewByRepository(_ context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToFirstReview(from, to, repository, func(pr domain.PullRequest, _ string) string { return pr.Repository }), nil
+}
+
+func (r *PRRepository) GetTimeToMergeByTeam(_ context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(from, to, repository, func(pr domain.PullRequest) string { return pr.TeamName }), nil
+}
+
+func (r *PRRepository) GetTimeToMergeByAuthor(_ context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(from, to, repository, func(pr domain.PullRequest) string { return pr.AuthorID }), nil
+}
+
+func (r *PRRepository) GetTimeToMergeByRepository(_ context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(from, to, repository, func(pr domain.PullRequest) string { return pr.Repository }), nil
+}
+
+func (r *PRRepository) GetTimeToMergeByWeek(_ context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(from, to, repository, func(pr domain.PullRequest) string {
+		merged := pr.MergedAt.UTC()
+		weekday := (int(merged.Weekday()) + 6) % 7
+		return merged.AddDate(0, 0, -weekday).Format("2006-01-02")
+	}), nil
+}
+
+func (r *PRRepository) GetAuthorStatsByAuthor(_ context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error) {
+	return r.authorStats(from, to, repository, func(pr domain.PullRequest) string { return pr.AuthorID }), nil
+}
+
+func (r *PRRepository) GetAuthorStatsByTeam(_ context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error) {
+	return r.authorStats(from, to, repository, func(pr domain.PullRequest) string { return pr.TeamName }), nil
+}
+
+func (r *PRRepository) GetAuthorStatsByRepository(_ context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error) {
+	return r.authorStats(from, to, repository, func(pr domain.PullRequest) string { return pr.Repository }), nil
+}
+
+func (r *PRRepository) authorStats(from, to time.Time, repository string, keyOf func(domain.PullRequest) string) []domain.AuthorStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	type totals struct {
+		stats     domain.AuthorStats
+		reviewers int
+		waited    []float64
+	}
+	groups := make(map[string]*totals)
+	for id, pr := range r.prs {
+		if pr.CreatedAt.Before(from) || !pr.CreatedAt.Before(to) || !inRepository(pr, repository) {
+			continue
+		}
+		key := keyOf(pr)
+		g, ok := groups[key]
+		if !ok {
+			g = &totals{stats: domain.AuthorStats{Key: key}}
+			groups[key] = g
+		}
+		g.stats.PRsCreated++
+		if pr.IsMerged() {
+			g.stats.PRsMerged++
+		}
+		g.reviewers += len(pr.AssignedReviewers)
+		var first time.Time
+		for _, reviewer := range pr.AssignedReviewers {
+			if acted, ok := r.actedAt[reviewKey{id, reviewer}]; ok && (first.IsZero() || acted.Before(first)) {
+				first = acted
+			}
+		}
+		if !first.IsZero() {
+			g.waited = append(g.waited, first.Sub(pr.CreatedAt).Seconds())
+		}
+	}
+
+	result := make([]domain.AuthorStats, 0, len(groups))
+	for _, g := range groups {
+		g.stats.AvgReviewers = float64(g.reviewers) / float64(g.stats.PRsCreated)
+		if len(g.waited) > 0 {
+			var sum float64
+			for _, w := range g.waited {
+				sum += w
+			}
+			avg := sum / float64(len(g.waited))
+			g.stats.AvgReviewWaitSeconds = &avg
+		}
+		result = append(result, g.stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// BackdateCreation moves a PR's creation time into the past, so tests can
+// exercise time-based features without waiting.
+func (r *PRRepository) BackdateCreation(prID string, by time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr := r.prs[prID]
+	pr.CreatedAt = pr.CreatedAt.Add(-by)
+	r.prs[prID] = pr
+}
+
+func (r *PRRepository) timeToMerge(from, to time.Time, repository string, keyOf func(domain.PullRequest) string) []domain.LatencyStats {
+	r.mu.RLock()
+	groups := make(map[string][]float64)
+	for _, pr := range r.prs {
+		if !pr.IsMerged() || pr.MergedAt.Before(from) || !pr.MergedAt.Before(to) || !inRepository(pr, repository) {
+			continue
+		}
+		key := keyOf(pr)
+		groups[key] = append(groups[key], pr.MergedAt.Sub(pr.CreatedAt).Seconds())
+	}
+	r.mu.RUnlock()
+	return latencyStats(groups)
+}
+
+func (r *PRRepository) ClaimStaleReviews(_ context.Context, assignedBefore, _ time.Time, limit int) ([]domain.StaleReview, error) {
+	return r.claimReviews(assignedBefore, limit, r.staleNotified), nil
+}
+
+func (r *PRRepository) ClaimOverdueReviews(_ context.Context, assignedBefore, _ time.Time, limit int) ([]domain.StaleReview, error) {
+	return r.claimReviews(assignedBefore, limit, r.escalated), nil
+}
+
+// claimReviews marks up to limit unacted reviews assigned before assignedBefore in claimed
+func (r *PRRepository) claimReviews(assignedBefore time.Time, limit int, claimed map[reviewKey]bool) []domain.StaleReview {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reviews := make([]domain.StaleReview, 0)
+	for _, pr := range r.prs {
+		if pr.IsMerged() {
+			continue
+		}
+		for _, userID := range pr.AssignedReviewers {
+			key := reviewKey{pr.PullRequestID, userID}
+			_, acted := r.actedAt[key]
+			if acted || claimed[key] || r.assignedAt[key].After(assignedBefore) {
+				continue
+			}
+			reviews = append(reviews, domain.StaleReview{
+				PullRequestID:   pr.PullRequestID,
+				PullRequestName: pr.PullRequestName,
+				TeamName:        pr.TeamName,
+				UserID:          userID,
+				AssignedAt:      r.assignedAt[key],
+			})
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].AssignedAt.Before(reviews[j].AssignedAt) })
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+	for _, review := range reviews {
+		claimed[reviewKey{review.PullRequestID, review.UserID}] = true
+	}
+	return reviews
+}
+
+// BackdateAssignment moves a reviewer's assignment time into the past, so
+// tests can exercise time-based features without waiting.
+func (r *PRRepository) BackdateAssignment(prID, userID string, by time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := reviewKey{prID, userID}
+	r.assignedAt[key] = r.assignedAt[key].Add(-by)
+}
+
+func (r *PRRepository) timeToFirstReview(
+	from, to time.Time,
+	repository string,
+	keyOf func(domain.PullRequest, string) string,
+) []domain.LatencyStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	groups := make(map[string][]float64)
+	for key, acted := range r.actedAt {
+		pr := r.prs[key.prID]
+		if acted.Before(from) || !acted.Before(to) || !inRepository(pr, repository) {
+			continue
+		}
+		group := keyOf(pr, key.userID)
+		groups[group] = append(groups[group], acted.Sub(r.assignedAt[key]).Seconds())
+	}
+
+	return latencyStats(groups)
+}
+
+// inRepository mirrors the optional repository filter of the stats queries.
+func inRepository(pr domain.PullRequest, repository string) bool {
+	return repository == "" || pr.Repository == repository
+}
+
+// latencyStats summarizes grouped durations like the SQL percentile_cont queries.
+func latencyStats(groups map[string][]float64) []domain.LatencyStats {
+	stats := make([]domain.LatencyStats, 0, len(groups))
+	for key, seconds := range groups {
+		sort.Float64s(seconds)
+		stats = append(stats, domain.LatencyStats{
+			Key:   key,
+			Count: len(seconds),
+			P50:   percentileCont(seconds, 0.5),
+			P90:   percentileCont(seconds, 0.9),
+			P99:   percentileCont(seconds, 0.99),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats
+}
+
+// percentileCont mirrors PostgreSQL percentile_cont over sorted values.
+func percentileCont(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	lower := int(pos)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+func (r *PRRepository) GetPRsByReviewer(_ context.Context, userID string) ([]domain.PullRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prs := make([]domain.PullRequest, 0)
+	for _, pr := range r.prs {
+		if containsString(pr.AssignedReviewers, userID) {
+			copied := clonePR(pr)
+			prs = append(prs, copied)
+		}
+	}
+	return prs, nil
+}
+
+func (r *PRRepository) GetPendingReviewsByReviewer(_ context.Context, userID string) ([]domain.ReviewAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reviews := make([]domain.ReviewAssignment, 0)
+	for _, pr := range r.prs {
+		key := reviewKey{pr.PullRequestID, userID}
+		if _, acted := r.actedAt[key]; pr.IsMerged() || acted || !containsString(pr.AssignedReviewers, userID) {
+			continue
+		}
+		reviews = append(reviews, domain.ReviewAssignment{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			TeamName:        pr.TeamName,
+			Repository:      pr.Repository,
+			UserID:          userID,
+			AssignedAt:      r.assignedAt[key],
+		})
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].AssignedAt.Equal(reviews[j].AssignedAt) {
+			return reviews[i].AssignedAt.Before(reviews[j].AssignedAt)
+		}
+		return reviews[i].PullRequestID < reviews[j].PullRequestID
+	})
+	return reviews, nil
+}
+
+func (r *PRRepository) GetPendingReviewsByReviewers(ctx context.Context, userIDs []string) ([]domain.ReviewAssignment, error) {
+	sorted := append([]string(nil), userIDs...)
+	sort.Strings(sorted)
+	reviews := make([]domain.ReviewAssignment, 0)
+	for _, userID := range sorted {
+		pending, err := r.GetPendingReviewsByReviewer(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, pending...)
+	}
+	return reviews, nil
+}
+
+func (r *PRRepository) ListPRs(_ context.Context, filter domain.PRFilter, page pagination.Page) ([]domain.PullRequest, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var after time.Time
+	if page.After != nil {
+		var err error
+		if after, err = page.After.Time(); err != nil {
+			return nil, 0, err
+		}
+	}
+	// before reports whether a sorts before b in the page's order, ties by ascending ID
+	before := func(a time.Time, aID string, b time.Time, bID string) bool {
+		if !a.Equal(b) {
+			return a.After(b) == (page.Order == pagination.OrderDesc)
+		}
+		return aID < bID
+	}
+
+	prs := make([]domain.PullRequest, 0)
+	total := 0
+	for _, pr := range r.prs {
+		if matchesPRFilter(pr, filter) {
+			total++
+			if page.After == nil || before(after, page.After.ID, pr.CreatedAt, pr.PullRequestID) {
+				prs = append(prs, clonePR(pr))
+			}
+		}
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		return before(prs[i].CreatedAt, prs[i].PullRequestID, prs[j].CreatedAt, prs[j].PullRequestID)
+	})
+	offset := min(page.Offset, len(prs))
+	return prs[offset:min(offset+page.Fetch(), len(prs))], total, nil
+}
+
+// matchesPRFilter mirrors prFilterCondition of the SQL repository
+func matchesPRFilter(pr domain.PullRequest, filter domain.PRFilter) bool {
+	anyOf := func(values []string, value string) bool {
+		return len(values) == 0 || containsString(values, value)
+	}
+	within := func(from, to *time.Time, at *time.Time) bool {
+		if from == nil && to == nil {
+			return true
+		}
+		return at != nil && (from == nil || !at.Before(*from)) && (to == nil || at.Before(*to))
+	}
+	reviewed := len(filter.ReviewerIDs) == 0
+	for _, reviewer := range pr.AssignedReviewers {
+		reviewed = reviewed || containsString(filter.ReviewerIDs, reviewer)
+	}
+	return anyOf(filter.TicketKeys, pr.TicketKey) &&
+		(len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, pr.Status)) &&
+		anyOf(filter.AuthorIDs, pr.AuthorID) && anyOf(filter.TeamNames, pr.TeamName) &&
+		anyOf(filter.Repositories, pr.Repository) && reviewed &&
+		within(filter.CreatedFrom, filter.CreatedTo, &pr.CreatedAt) &&
+		within(filter.MergedFrom, filter.MergedTo, pr.MergedAt)
+}
+
+func (r *PRRepository) DeletePR(_ context.Context, prID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.prs[prID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.prs, prID)
+	for _, reviews := range []map[reviewKey]time.Time{r.assignedAt, r.actedAt} {
+		for key := range reviews {
+			if key.prID == prID {
+				delete(reviews, key)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *PRRepository) ArchiveMergedPRs(_ context.Context, mergedBefore time.Time, limit int) (int, error) {
+	return r.removeMerged(mergedBefore, limit, true), nil
+}
+
+func (r *PRRepository) DeleteMergedPRs(_ context.Context, mergedBefore time.Time, limit int) (int, error) {
+	return r.removeMerged(mergedBefore, limit, false), nil
+}
+
+// removeMerged removes up to limit PRs merged before mergedBefore, oldest
+// first, with their reviews and reassignments, keeping the PRs in archived
+// when archive is set
+func (r *PRRepository) removeMerged(mergedBefore time.Time, limit int, archive bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := make([]domain.PullRequest, 0)
+	for _, pr := range r.prs {
+		if pr.IsMerged() && pr.MergedAt != nil && pr.MergedAt.Before(mergedBefore) {
+			batch = append(batch, pr)
+		}
+	}
+	sort.Slice(batch, func(i, j int) bool { return batch[i].MergedAt.Before(*batch[j].MergedAt) })
+	batch = batch[:min(limit, len(batch))]
+
+	removed := make(map[string]bool, len(batch))
+	for _, pr := range batch {
+		removed[pr.PullRequestID] = true
+		delete(r.prs, pr.PullRequestID)
+		if _, ok := r.archived[pr.PullRequestID]; archive && !ok {
+			r.archived[pr.PullRequestID] = pr
+		}
+	}
+	for _, reviews := range []map[reviewKey]time.Time{r.assignedAt, r.actedAt} {
+		for key := range reviews {
+			if removed[key.prID] {
+				delete(reviews, key)
+			}
+		}
+	}
+	r.reassignments = slices.DeleteFunc(r.reassignments, func(logged loggedReassignment) bool {
+		return removed[logged.PullRequestID]
+	})
+	return len(batch)
+}
+
+func (r *PRRepository) PRExists(_ context.Context, prID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.prs[prID]
+	return ok, nil
+}
+
+func (r *PRRepository) GetAssignmentStatsByUser(
+	ctx context.Context,
+	from, to time.Time,
+	order domain.StatsSort,
+	limit, offset int,
+) ([]domain.KeyCount, int, error) {
+	counts, err := r.assignmentCountsByUser(ctx, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := pageCounts(counts, order, limit, offset)
+	return page, total, nil
+}
+
+func (r *PRRepository) GetAssignmentStatsByPR(
+	_ context.Context,
+	from, to time.Time,
+	order domain.StatsSort,
+	limit, offset int,
+) ([]domain.KeyCount, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	counts := make(map[string]int)
+	for id, pr := range r.prs {
+		for _, reviewer := range pr.AssignedReviewers {
+			if r.assignedWithin(id, reviewer, from, to) {
+				counts[id]++
+			}
+		}
+	}
+	page, total := pageCounts(counts, order, limit, offset)
+	return page, total, nil
+}
+
+// pageCounts sorts counts the way the SQL repository does and returns one page and the total.
+func pageCounts(counts map[string]int, order domain.StatsSort, limit, offset int) ([]domain.KeyCount, int) {
+	all := make([]domain.KeyCount, 0, len(counts))
+	for key, count := range counts {
+		all = append(all, domain.KeyCount{Key: key, Count: count})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Count != all[j].Count {
+			switch order {
+			case domain.StatsSortCountDesc:
+				return all[i].Count > all[j].Count
+			case domain.StatsSortCountAsc:
+				return all[i].Count < all[j].Count
+			}
+		}
+		return all[i].Key < all[j].Key
+	})
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := min(offset+limit, len(all))
+	return all[offset:end], len(all)
+}
+
+func (r *PRRepository) assignmentCountsByUser(_ context.Context, from, to time.Time) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stats := make(map[string]int)
+	for id, pr := range r.prs {
+		for _, reviewer := range pr.AssignedReviewers {
+			if r.assignedWithin(id, reviewer, from, to) {
+				stats[reviewer]++
+			}
+		}
+	}
+	return stats, nil
+}
+
+// assignedWithin reports whether the reviewer was assigned within [from, to); callers hold the lock.
+func (r *PRRepository) assignedWithin(prID, userID string, from, to time.Time) bool {
+	at := r.assignedAt[reviewKey{prID, userID}]
+	return !at.Before(from) && at.Before(to)
+}
+
+func (r *PRRepository) GetAssignmentStatsByRole(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	byUser, err := r.assignmentCountsByUser(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]int)
+	for userID, count := range byUser {
+		user, err := r.userRepo.GetUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		stats[string(user.Role)] += count
+	}
+	return stats, nil
+}
+
+func (r *PRRepository) GetAssignmentStatsByTeam(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	byUser, err := r.assignmentCountsByUser(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]int)
+	for userID, count := range byUser {
+		user, err := r.userRepo.GetUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range user.Teams {
+			stats[team] += count
+		}
+	}
+	return stats, nil
+}
+
+func (r *PRRepository) GetReviewerLoads(ctx context.Context, from, to time.Time) ([]domain.ReviewerLoad, error) {
+	byUser, err := r.assignmentCountsByUser(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	r.userRepo.mu.RLock()
+	loads := make([]domain.ReviewerLoad, 0)
+	for id, user := range r.userRepo.users {
+		if !user.IsActive || user.IsDeleted() {
+			continue
+		}
+		for _, team := range r.userRepo.memberships[id] {
+			loads = append(loads, domain.ReviewerLoad{TeamName: team, UserID: id, Count: byUser[id]})
+		}
+	}
+	r.userRepo.mu.RUnlock()
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].TeamName != loads[j].TeamName {
+			return loads[i].TeamName < loads[j].TeamName
+		}
+		return loads[i].UserID < loads[j].UserID
+	})
+	return loads, nil
+}
+
+func (r *PRRepository) GetReviewPairs(_ context.Context, from, to time.Time, teamName string) ([]domain.ReviewPair, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	counts := make(map[[2]string]int)
+	for id, pr := range r.prs {
+		if teamName != "" && pr.TeamName != teamName {
+			continue
+		}
+		for _, reviewer := range pr.AssignedReviewers {
+			if r.assignedWithin(id, reviewer, from, to) {
+				counts[[2]string{pr.AuthorID, reviewer}]++
+			}
+		}
+	}
+	pairs := make([]domain.ReviewPair, 0, len(counts))
+	for key, count := range counts {
+		pairs = append(pairs, domain.ReviewPair{AuthorID: key[0], ReviewerID: key[1], Count: count})
+	}
+	return pairs, nil
+}
+
+func (r *PRRepository) GetReassignmentStatsByUser(_ context.Context, from, to time.Time) ([]domain.ReassignmentStats, error) {
+	return r.reassignmentStats(from, to, func(ra domain.Reassignment) string { return ra.OldUserID }), nil
+}
+
+func (r *PRRepository) GetReassignmentStatsByTeam(_ context.Context, from, to time.Time) ([]domain.ReassignmentStats, error) {
+	return r.reassignmentStats(from, to, func(ra domain.Reassignment) string { return r.prs[ra.PullRequestID].TeamName }), nil
+}
+
+func (r *PRRepository) reassignmentStats(from, to time.Time, keyOf func(domain.Reassignment) string) []domain.ReassignmentStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	groups := make(map[string]*domain.ReassignmentStats)
+	for _, logged := range r.reassignments {
+		if logged.at.Before(from) || !logged.at.Before(to) {
+			continue
+		}
+		key := keyOf(logged.Reassignment)
+		g, ok := groups[key]
+		if !ok {
+			g = &domain.ReassignmentStats{Key: key}
+			groups[key] = g
+		}
+		g.Total++
+		if logged.IsClosed() {
+			g.Closed++
+		}
+	}
+	stats := make([]domain.ReassignmentStats, 0, len(groups))
+	for _, g := range groups {
+		stats = append(stats, *g)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+func (r *PRRepository) GetReviewSLA(_ context.Context, from, to, now time.Time, sla time.Duration) ([]domain.ReviewSLA, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	byTeam := make(map[string]*domain.ReviewSLA)
+	for id, pr := range r.prs {
+		for _, reviewer := range pr.AssignedReviewers {
+			if !r.assignedWithin(id, reviewer, from, to) {
+				continue
+			}
+			stats, ok := byTeam[pr.TeamName]
+			if !ok {
+				stats = &domain.ReviewSLA{TeamName: pr.TeamName}
+				byTeam[pr.TeamName] = stats
+			}
+			key := reviewKey{id, reviewer}
+			acted, reviewed := r.actedAt[key]
+			if reviewed || !r.assignedAt[key].After(now.Add(-sla)) {
+				stats.Due++
+			}
+			if reviewed && acted.Sub(r.assignedAt[key]) <= sla {
+				stats.Met++
+			}
+		}
+	}
+	result := make([]domain.ReviewSLA, 0, len(byTeam))
+	for _, stats := range byTeam {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TeamName < result[j].TeamName })
+	return result, nil
+}
+
+func (r *PRRepository) GetOpenReviewCounts(_ context.Context) ([]domain.ReviewerWorkload, error) {
+	r.mu.RLock()
+	open := make(map[string]int)
+	for _, pr := range r.prs {
+		if pr.IsMerged() {
+			continue
+		}
+		for _, reviewer := range pr.AssignedReviewers {
+			open[reviewer]++
+		}
+	}
+	r.mu.RUnlock()
+
+	r.userRepo.mu.RLock()
+	workloads := make([]domain.ReviewerWorkload, 0)
+	for id, user := range r.userRepo.users {
+		if !user.IsActive || user.IsDeleted() {
+			continue
+		}
+		workloads = append(workloads, domain.ReviewerWorkload{UserID: id, Username: user.Username, OpenReviews: open[id]})
+	}
+	r.userRepo.mu.RUnlock()
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].OpenReviews != workloads[j].OpenReviews {
+			return workloads[i].OpenReviews > workloads[j].OpenReviews
+		}
+		return workloads[i].UserID < workloads[j].UserID
+	})
+	return workloads, nil
+}
+
+func (r *PRRepository) GetOpenPRAging(_ context.Context, now time.Time) ([]domain.PRAging, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	byTeam := make(map[string]*domain.PRAging)
+	for _, pr := range r.prs {
+		if pr.IsMerged() {
+			continue
+		}
+		aging, ok := byTeam[pr.TeamName]
+		if !ok {
+			aging = &domain.PRAging{TeamName: pr.TeamName}
+			byTeam[pr.TeamName] = aging
+		}
+		switch age := now.Sub(pr.CreatedAt); {
+		case age < 24*time.Hour:
+			aging.UnderOneDay++
+		case age < 3*24*time.Hour:
+			aging.OneToThreeDays++
+		case age < 7*24*time.Hour:
+			aging.ThreeToSevenDays++
+		default:
+			aging.OverSevenDays++
+		}
+	}
+	result := make([]domain.PRAging, 0, len(byTeam))
+	for _, aging := range byTeam {
+		result = append(result, *aging)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TeamName < result[j].TeamName })
+	return result, nil
+}
+
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error {
+	for _, ra := range reassignments {
+		if err := r.RemoveReviewer(ctx, ra.PullRequestID, ra.OldUserID); err != nil {
+			return err
+		}
+		if ra.NewUserID == "" {
+			continue
+		}
+		if err := r.AddReviewer(ctx, ra.PullRequestID, ra.NewUserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PRRepository) RecordReassignments(_ context.Context, reassignments []domain.Reassignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, ra := range reassignments {
+		r.reassignments = append(r.reassignments, loggedReassignment{Reassignment: ra, at: now})
+	}
+	return nil
+}
+
+func (r *PRRepository) GetOpenPRsByReviewers(_ context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prs := make([]domain.PullRequest, 0)
+	for _, pr := range r.prs {
+		if pr.Status == domain.PRStatusMerged {
+			continue
+		}
+		if slices.ContainsFunc(pr.AssignedReviewers, func(id string) bool { return containsString(userIDs, id) }) {
+			prs = append(prs, clonePR(pr))
+		}
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		if !prs[i].CreatedAt.Equal(prs[j].CreatedAt) {
+			return prs[i].CreatedAt.Before(prs[j].CreatedAt)
+		}
+		return prs[i].PullRequestID < prs[j].PullRequestID
+	})
+	return prs, nil
+}
+
+func (r *PRRepository) GetOpenPRIDsByTeam(_ context.Context, teamName string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0)
+	for id, pr := range r.prs {
+		if pr.Status != domain.PRStatusMerged && pr.TeamName == teamName {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (r *PRRepository) MovePRsToTeam(_ context.Context, fromTeam, toTeam string) error {
+	r.retarget(fromTeam, toTeam)
+	return nil
+}
+
+func clonePR(pr domain.PullRequest) domain.PullRequest {
+	copied := pr
+	if pr.AssignedReviewers != nil {
+		copied.AssignedReviewers = append([]string(nil), pr.AssignedReviewers...)
+	}
+	return copied
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+// RollupRepository aggregates PRRepository data per UTC day like the SQL rollup
+type RollupRepository struct {
+	mu     sync.Mutex
+	prRepo *PRRepository
+	days   map[time.Time][]domain.DailyStats
+}
+
+// NewRollupRepository creates a new in-memory rollup repository over prRepo
+func NewRollupRepository(prRepo *PRRepository) *RollupRepository {
+	return &RollupRepository{prRepo: prRepo, days: make(map[time.Time][]domain.DailyStats)}
+}
+
+func (r *RollupRepository) LastRolledUpDay(_ context.Context) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var last time.Time
+	for day := range r.days {
+		if day.After(last) {
+			last = day
+		}
+	}
+	return last, nil
+}
+
+func (r *RollupRepository) RollUpDay(_ context.Context, day time.Time) error {
+	day = day.UTC().Truncate(24 * time.Hour)
+	within := func(at time.Time) bool {
+		return !at.Before(day) && at.Before(day.Add(24*time.Hour))
+	}
+
+	r.prRepo.mu.RLock()
+	byTeam := make(map[string]*domain.DailyStats)
+	stats := func(teamName string) *domain.DailyStats {
+		if _, ok := byTeam[teamName]; !ok {
+			byTeam[teamName] = &domain.DailyStats{Day: day, TeamName: teamName}
+		}
+		return byTeam[teamName]
+	}
+	for key, at := range r.prRepo.assignedAt {
+		if within(at) {
+			stats(r.prRepo.prs[key.prID].TeamName).Assignments++
+		}
+	}
+	for _, pr := range r.prRepo.prs {
+		if pr.IsMerged() && within(*pr.MergedAt) {
+			stats(pr.TeamName).Merges++
+		}
+	}
+	for _, ra := range r.prRepo.reassignments {
+		if within(ra.at) {
+			stats(r.prRepo.prs[ra.PullRequestID].TeamName).Reassignments++
+		}
+	}
+	r.prRepo.mu.RUnlock()
+
+	rows := make([]domain.DailyStats, 0, len(byTeam))
+	for _, row := range byTeam {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].TeamName < rows[j].TeamName })
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.days[day] = rows
+	return nil
+}
+
+func (r *RollupRepository) GetDailyStats(_ context.Context, from, to time.Time, teamName string) ([]domain.DailyStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]domain.DailyStats, 0)
+	for day, rows := range r.days {
+		if day.Before(from) || !day.Before(to) {
+			continue
+		}
+		for _, row := range rows {
+			if teamName == "" || row.TeamName == teamName {
+				result = append(result, row)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Day.Equal(result[j].Day) {
+			return result[i].Day.Before(result[j].Day)
+		}
+		return result[i].TeamName < result[j].TeamName
+	})
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+// ScheduledChangeRepository keeps deferred activity changes
+type ScheduledChangeRepository struct {
+	mu      sync.Mutex
+	nextID  int64
+	changes map[int64]domain.ScheduledChange
+}
+
+// NewScheduledChangeRepository creates a new in-memory scheduled change repository
+func NewScheduledChangeRepository() *ScheduledChangeRepository {
+	return &ScheduledChangeRepository{changes: make(map[int64]domain.ScheduledChange)}
+}
+
+func (r *ScheduledChangeRepository) CreateScheduledChange(_ context.Context, change domain.ScheduledChange) (domain.ScheduledChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	change.ID = r.nextID
+	r.changes[change.ID] = change
+	return change, nil
+}
+
+func (r *ScheduledChangeRepository) ClaimDueScheduledChanges(_ context.Context, now time.Time, limit int) ([]domain.ScheduledChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	due := make([]domain.ScheduledChange, 0)
+	for _, change := range r.changes {
+		if change.Status == domain.ScheduledChangePending && !change.EffectiveAt.After(now) {
+			due = append(due, change)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].Status = domain.ScheduledChangeProcessing
+		r.changes[due[i].ID] = due[i]
+	}
+	return due, nil
+}
+
+func (r *ScheduledChangeRepository) CompleteScheduledChange(_ context.Context, id int64, status domain.ScheduledChangeStatus, errMsg string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	change, ok := r.changes[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	now := time.Now()
+	change.Status = status
+	change.Error = errMsg
+	change.AppliedAt = &now
+	r.changes[id] = change
+	return nil
+}
@@ -0,0 +1,268 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+)
+
+// TeamRepository keeps teams and their settings; members are kept by
+// UserRepository. Deleted teams move to deleted, keeping their settings.
+type TeamRepository struct {
+	mu       sync.RWMutex
+	teams    map[string]domain.Team
+	deleted  map[string]domain.Team
+	channels map[string]domain.NotificationChannel
+	userRepo *UserRepository
+	prRepo   *PRRepository
+}
+
+// NewTeamRepository creates a new in-memory team repository whose members are
+// kept by userRepo
+func NewTeamRepository(userRepo *UserRepository) *TeamRepository {
+	r := &TeamRepository{
+		teams:    make(map[string]domain.Team),
+		deleted:  make(map[string]domain.Team),
+		channels: make(map[string]domain.NotificationChannel),
+		userRepo: userRepo,
+	}
+	userRepo.teams = r
+	return r
+}
+
+func (r *TeamRepository) CreateTeam(_ context.Context, team domain.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.teams[team.TeamName]; ok {
+		return domain.ErrTeamExists
+	}
+	if _, ok := r.deleted[team.TeamName]; ok {
+		delete(r.deleted, team.TeamName)
+		delete(r.channels, team.TeamName)
+	}
+	r.teams[team.TeamName] = team
+	return nil
+}
+
+func (r *TeamRepository) GetTeam(_ context.Context, teamName string) (domain.Team, error) {
+	r.mu.RLock()
+	team, ok := r.teams[teamName]
+	r.mu.RUnlock()
+	if !ok {
+		return domain.Team{}, domain.ErrNotFound
+	}
+	team.Members = r.userRepo.members(teamName)
+	return team, nil
+}
+
+func (r *TeamRepository) TeamExists(_ context.Context, teamName string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.teams[teamName]
+	return ok, nil
+}
+
+func (r *TeamRepository) DeleteTeam(_ context.Context, teamName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	team, ok := r.teams[teamName]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	now := time.Now()
+	team.DeletedAt = &now
+	team.UpdatedAt = now
+	delete(r.teams, teamName)
+	r.deleted[teamName] = team
+	r.reparent(teamName, "")
+	r.userRepo.detach(teamName)
+	return nil
+}
+
+func (r *TeamRepository) ListDeletedTeams(_ context.Context, page pagination.Page) ([]domain.Team, int, error) {
+	var after time.Time
+	if page.After != nil {
+		var err error
+		if after, err = page.After.Time(); err != nil {
+			return nil, 0, err
+		}
+	}
+	before := deletionOrder(page)
+
+	r.mu.RLock()
+	total := len(r.deleted)
+	teams := make([]domain.Team, 0, total)
+	for _, team := range r.deleted {
+		if page.After == nil || before(after, page.After.ID, *team.DeletedAt, team.TeamName) {
+			teams = append(teams, team)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(teams, func(i, j int) bool {
+		return before(*teams[i].DeletedAt, teams[i].TeamName, *teams[j].DeletedAt, teams[j].TeamName)
+	})
+
+	offset := min(page.Offset, len(teams))
+	return teams[offset:min(offset+page.Fetch(), len(teams))], total, nil
+}
+
+func (r *TeamRepository) RestoreTeam(_ context.Context, teamName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	team, ok := r.deleted[teamName]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.deleted, teamName)
+	team.DeletedAt = nil
+	team.UpdatedAt = time.Now()
+	r.teams[teamName] = team
+	return nil
+}
+
+func (r *TeamRepository) ListTeams(_ context.Context, limit, offset int) ([]domain.TeamSummary, int, error) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.teams))
+	for name := range r.teams {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	total := len(names)
+	if offset > total {
+		offset = total
+	}
+	end := min(offset+limit, total)
+
+	summaries := make([]domain.TeamSummary, 0, end-offset)
+	for _, name := range names[offset:end] {
+		summary := domain.TeamSummary{TeamName: name}
+		for _, member := range r.userRepo.members(name) {
+			summary.MemberCount++
+			if member.IsActive {
+				summary.ActiveMemberCount++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, total, nil
+}
+
+func (r *TeamRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	team, ok := r.teams[oldName]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if _, ok := r.deleted[newName]; ok {
+		return domain.ErrTeamExists
+	}
+	delete(r.teams, oldName)
+	team.TeamName = newName
+	r.teams[newName] = team
+	if ch, ok := r.channels[oldName]; ok {
+		delete(r.channels, oldName)
+		r.channels[newName] = ch
+	}
+	r.reparent(oldName, newName)
+	if r.prRepo != nil {
+		r.prRepo.retarget(oldName, newName)
+	}
+	return r.userRepo.MoveTeamMembers(ctx, oldName, newName)
+}
+
+func (r *TeamRepository) GetTeamSettings(_ context.Context, teamName string) (domain.TeamSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.teams[teamName]; !ok {
+		return domain.TeamSettings{}, domain.ErrNotFound
+	}
+	settings := domain.TeamSettings{TeamName: teamName}
+	if ch, ok := r.channels[teamName]; ok {
+		settings.NotificationChannel = &ch
+	}
+	return settings, nil
+}
+
+func (r *TeamRepository) SetTeamSettings(_ context.Context, settings domain.TeamSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.teams[settings.TeamName]; !ok {
+		return domain.ErrNotFound
+	}
+	if settings.NotificationChannel == nil {
+		delete(r.channels, settings.TeamName)
+	} else {
+		r.channels[settings.TeamName] = *settings.NotificationChannel
+	}
+	return nil
+}
+
+func (r *TeamRepository) SetParentTeam(_ context.Context, teamName, parentTeamName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	team, ok := r.teams[teamName]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	team.ParentTeamName = parentTeamName
+	r.teams[teamName] = team
+	return nil
+}
+
+func (r *TeamRepository) GetAncestorTeamNames(_ context.Context, teamName string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ancestors := make([]string, 0)
+	for parent := r.teams[teamName].ParentTeamName; parent != ""; parent = r.teams[parent].ParentTeamName {
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors, nil
+}
+
+func (r *TeamRepository) GetChildTeamNames(_ context.Context, teamName string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0)
+	for name, team := range r.teams {
+		if team.ParentTeamName == teamName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// reparent mirrors ON UPDATE CASCADE / ON DELETE SET NULL on teams.parent_team_name.
+// Callers must hold r.mu.
+func (r *TeamRepository) reparent(oldParent, newParent string) {
+	for _, teams := range []map[string]domain.Team{r.teams, r.deleted} {
+		for name, team := range teams {
+			if team.ParentTeamName == oldParent {
+				team.ParentTeamName = newParent
+				teams[name] = team
+			}
+		}
+	}
+}
+
+// subTree returns a team name followed by the names of all its descendants.
+func (r *TeamRepository) subTree(teamName string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := []string{teamName}
+	for i := 0; i < len(names); i++ {
+		for name, team := range r.teams {
+			if team.ParentTeamName == names[i] {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+)
+
+// TeamTokenRepository keeps team tokens keyed by the hash of their secret.
+// Like the other in-memory repositories it only holds the default
+// organization.
+type TeamTokenRepository struct {
+	mu     sync.Mutex
+	nextID int64
+	tokens map[int64]domain.TeamToken
+	hashes map[int64]string
+}
+
+// NewTeamTokenRepository creates a new in-memory team token repository
+func NewTeamTokenRepository() *TeamTokenRepository {
+	return &TeamTokenRepository{tokens: make(map[int64]domain.TeamToken), hashes: make(map[int64]string)}
+}
+
+func (r *TeamTokenRepository) CreateTeamToken(_ context.Context, token domain.TeamToken, hash string) (domain.TeamToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	token.ID = r.nextID
+	token.OrgID = domain.DefaultOrg
+	r.tokens[token.ID] = token
+	r.hashes[token.ID] = hash
+	return token, nil
+}
+
+func (r *TeamTokenRepository) GetTeamToken(_ context.Context, id int64) (domain.TeamToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok {
+		return domain.TeamToken{}, domain.ErrNotFound
+	}
+	return token, nil
+}
+
+func (r *TeamTokenRepository) GetTeamTokenByHash(_ context.Context, hash string) (domain.TeamToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, h := range r.hashes {
+		if h == hash {
+			return r.tokens[id], nil
+		}
+	}
+	return domain.TeamToken{}, domain.ErrNotFound
+}
+
+func (r *TeamTokenRepository) ListTeamTokens(_ context.Context, teamName string, page pagination.Page) ([]domain.TeamToken, int, error) {
+	var after int64
+	if page.After != nil {
+		var err error
+		if after, err = strconv.ParseInt(page.After.ID, 10, 64); err != nil {
+			return nil, 0, err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tokens := make([]domain.TeamToken, 0)
+	total := 0
+	for _, token := range r.tokens {
+		if token.TeamName != teamName {
+			continue
+		}
+		total++
+		if page.After != nil && ((page.Order == pagination.OrderAsc && token.ID <= after) || (page.Order == pagination.OrderDesc && token.ID >= after)) {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return (tokens[i].ID < tokens[j].ID) == (page.Order == pagination.OrderAsc) })
+	offset := min(page.Offset, len(tokens))
+	return tokens[offset:min(offset+page.Fetch(), len(tokens))], total, nil
+}
+
+func (r *TeamTokenRepository) DeleteTeamToken(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.tokens, id)
+	delete(r.hashes, id)
+	return nil
+}
@@ -0,0 +1,294 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+)
+
+// UserRepository keeps users and their team memberships
+type UserRepository struct {
+	mu          sync.RWMutex
+	users       map[string]domain.User
+	memberships map[string][]string
+	teams       *TeamRepository
+}
+
+// NewUserRepository creates a new in-memory user repository
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:       make(map[string]domain.User),
+		memberships: make(map[string][]string),
+	}
+}
+
+func (r *UserRepository) CreateOrUpdateUser(_ context.Context, user domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.DeletedAt = nil
+	r.users[user.UserID] = user
+	return nil
+}
+
+func (r *UserRepository) AddTeamMember(_ context.Context, teamName, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !containsString(r.memberships[userID], teamName) {
+		r.memberships[userID] = append(r.memberships[userID], teamName)
+	}
+	return nil
+}
+
+func (r *UserRepository) CreateOrUpdateUsers(ctx context.Context, users []domain.User) error {
+	for _, user := range users {
+		if err := r.CreateOrUpdateUser(ctx, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) AddTeamMembers(ctx context.Context, teamName string, userIDs []string) error {
+	for _, userID := range userIDs {
+		if err := r.AddTeamMember(ctx, teamName, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) RemoveTeamMember(_ context.Context, teamName, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := make([]string, 0, len(r.memberships[userID]))
+	for _, name := range r.memberships[userID] {
+		if name != teamName {
+			kept = append(kept, name)
+		}
+	}
+	r.memberships[userID] = kept
+	return nil
+}
+
+func (r *UserRepository) UpdateUser(_ context.Context, user domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.users[user.UserID]; !ok || existing.IsDeleted() {
+		return domain.ErrNotFound
+	}
+	r.users[user.UserID] = user
+	return nil
+}
+
+func (r *UserRepository) GetUser(_ context.Context, userID string) (domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.users[userID]
+	if !ok || user.IsDeleted() {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return r.withTeams(user, ""), nil
+}
+
+func (r *UserRepository) SoftDeleteUser(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[userID]
+	if !ok || user.IsDeleted() {
+		return domain.ErrNotFound
+	}
+	user.MarkDeleted()
+	r.users[userID] = user
+	return nil
+}
+
+func (r *UserRepository) ListDeletedUsers(_ context.Context, page pagination.Page) ([]domain.User, int, error) {
+	var after time.Time
+	if page.After != nil {
+		var err error
+		if after, err = page.After.Time(); err != nil {
+			return nil, 0, err
+		}
+	}
+	before := deletionOrder(page)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	deleted := make([]domain.User, 0)
+	total := 0
+	for _, u := range r.users {
+		if !u.IsDeleted() {
+			continue
+		}
+		total++
+		if page.After == nil || before(after, page.After.ID, *u.DeletedAt, u.UserID) {
+			deleted = append(deleted, r.withTeams(u, ""))
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool {
+		return before(*deleted[i].DeletedAt, deleted[i].UserID, *deleted[j].DeletedAt, deleted[j].UserID)
+	})
+	offset := min(page.Offset, len(deleted))
+	return deleted[offset:min(offset+page.Fetch(), len(deleted))], total, nil
+}
+
+// deletionOrder reports whether an item deleted at a sorts before one deleted
+// at b in the page's order, ties by ascending ID
+func deletionOrder(page pagination.Page) func(a time.Time, aID string, b time.Time, bID string) bool {
+	return func(a time.Time, aID string, b time.Time, bID string) bool {
+		if !a.Equal(b) {
+			return a.After(b) == (page.Order == pagination.OrderDesc)
+		}
+		return aID < bID
+	}
+}
+
+func (r *UserRepository) RestoreUser(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[userID]
+	if !ok || !user.IsDeleted() {
+		return domain.ErrNotFound
+	}
+	user.DeletedAt = nil
+	user.UpdatedAt = time.Now()
+	r.users[userID] = user
+	return nil
+}
+
+func (r *UserRepository) TouchLastSeen(_ context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[userID]
+	if !ok || user.IsDeleted() {
+		return domain.ErrNotFound
+	}
+	user.MarkSeen(at)
+	r.users[userID] = user
+	return nil
+}
+
+func (r *UserRepository) ListDormantUsers(_ context.Context, seenBefore time.Time, limit, offset int) ([]domain.User, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	dormant := make([]domain.User, 0)
+	for _, u := range r.users {
+		if u.IsDeleted() || !u.IsActive {
+			continue
+		}
+		if u.LastSeenAt == nil || u.LastSeenAt.Before(seenBefore) {
+			dormant = append(dormant, r.withTeams(u, ""))
+		}
+	}
+	sort.Slice(dormant, func(i, j int) bool { return dormant[i].UserID < dormant[j].UserID })
+	total := len(dormant)
+	offset = min(offset, total)
+	end := min(offset+limit, total)
+	return dormant[offset:end], total, nil
+}
+
+func (r *UserRepository) GetTeamMembers(_ context.Context, teamName string) ([]domain.User, error) {
+	return r.members(teamName), nil
+}
+
+func (r *UserRepository) DeactivateUsers(_ context.Context, teamName string, userIDs []string) error {
+	return r.setActive(teamName, userIDs, false)
+}
+
+func (r *UserRepository) ActivateUsers(_ context.Context, teamName string, userIDs []string) error {
+	return r.setActive(teamName, userIDs, true)
+}
+
+func (r *UserRepository) setActive(teamName string, userIDs []string, isActive bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range userIDs {
+		user, ok := r.users[id]
+		if !ok || !containsString(r.memberships[id], teamName) {
+			return domain.ErrNotFound
+		}
+		user.IsActive = isActive
+		user.UpdatedAt = time.Now()
+		r.users[id] = user
+	}
+	return nil
+}
+
+func (r *UserRepository) GetTeamTreeMembers(_ context.Context, teamName string) ([]domain.User, error) {
+	result := make([]domain.User, 0)
+	seen := make(map[string]struct{})
+	for _, name := range r.teams.subTree(teamName) {
+		for _, member := range r.members(name) {
+			if _, ok := seen[member.UserID]; ok {
+				continue
+			}
+			seen[member.UserID] = struct{}{}
+			result = append(result, member)
+		}
+	}
+	return result, nil
+}
+
+func (r *UserRepository) MoveTeamMembers(_ context.Context, fromTeam, toTeam string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, teams := range r.memberships {
+		if !containsString(teams, fromTeam) {
+			continue
+		}
+		moved := make([]string, 0, len(teams))
+		for _, name := range teams {
+			if name == fromTeam {
+				name = toTeam
+			}
+			if !containsString(moved, name) {
+				moved = append(moved, name)
+			}
+		}
+		r.memberships[id] = moved
+	}
+	return nil
+}
+
+// detach mirrors ON DELETE CASCADE on team_members.team_name.
+func (r *UserRepository) detach(teamName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, teams := range r.memberships {
+		kept := make([]string, 0, len(teams))
+		for _, name := range teams {
+			if name != teamName {
+				kept = append(kept, name)
+			}
+		}
+		r.memberships[id] = kept
+	}
+}
+
+func (r *UserRepository) members(teamName string) []domain.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]domain.User, 0)
+	for id, u := range r.users {
+		if containsString(r.memberships[id], teamName) && !u.IsDeleted() {
+			result = append(result, r.withTeams(u, teamName))
+		}
+	}
+	return result
+}
+
+// withTeams fills memberships the way the SQL repository does: TeamName is the
+// requested team, or the earliest joined one when none is requested.
+// Callers must hold r.mu.
+func (r *UserRepository) withTeams(user domain.User, teamName string) domain.User {
+	user.Teams = append([]string{}, r.memberships[user.UserID]...)
+	user.TeamName = teamName
+	if teamName == "" && len(user.Teams) > 0 {
+		user.TeamName = user.Teams[0]
+	}
+	return user
+}
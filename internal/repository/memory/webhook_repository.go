@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+// WebhookRepository keeps outbound webhook subscriptions and their delivery log
+type WebhookRepository struct {
+	mu            sync.Mutex
+	nextSubID     int64
+	nextID        int64
+	subscriptions map[int64]domain.WebhookSubscription
+	deliveries    map[int64]domain.WebhookDelivery
+}
+
+// NewWebhookRepository creates a new in-memory webhook repository
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		subscriptions: make(map[int64]domain.WebhookSubscription),
+		deliveries:    make(map[int64]domain.WebhookDelivery),
+	}
+}
+
+func (r *WebhookRepository) CreateWebhookSubscription(_ context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextSubID++
+	sub.ID = r.nextSubID
+	r.subscriptions[sub.ID] = sub
+	return sub, nil
+}
+
+func (r *WebhookRepository) ListWebhookSubscriptions(_ context.Context) ([]domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := make([]domain.WebhookSubscription, 0, len(r.subscriptions))
+	for _, sub := range r.subscriptions {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	return subs, nil
+}
+
+func (r *WebhookRepository) DeleteWebhookSubscription(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.subscriptions[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.subscriptions, id)
+	for deliveryID, d := range r.deliveries {
+		if d.SubscriptionID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueWebhookDeliveries(_ context.Context, eventType domain.EventType, payload []byte, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queued := 0
+	for _, sub := range r.subscriptions {
+		if !sub.Wants(eventType) {
+			continue
+		}
+		r.nextID++
+		r.deliveries[r.nextID] = domain.WebhookDelivery{
+			ID:             r.nextID,
+			SubscriptionID: sub.ID,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+func (r *WebhookRepository) ClaimDueWebhookDeliveries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	due := make([]domain.WebhookDelivery, 0)
+	for _, d := range r.deliveries {
+		if d.Status == domain.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].Attempts++
+		due[i].NextAttemptAt = now.Add(lease)
+		r.deliveries[due[i].ID] = due[i]
+		sub := r.subscriptions[due[i].SubscriptionID]
+		due[i].URL, due[i].Secret = sub.URL, sub.Secret
+	}
+	return due, nil
+}
+
+func (r *WebhookRepository) RecordWebhookAttempt(_ context.Context, delivery domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	delivery.URL, delivery.Secret = "", ""
+	r.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (r *WebhookRepository) ListWebhookDeliveries(
+	_ context.Context,
+	subscriptionID int64,
+	status domain.WebhookDeliveryStatus,
+	limit, offset int,
+) ([]domain.WebhookDelivery, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matched := make([]domain.WebhookDelivery, 0)
+	for _, d := range r.deliveries {
+		if d.SubscriptionID == subscriptionID && (status == "" || d.Status == status) {
+			d.URL = r.subscriptions[d.SubscriptionID].URL
+			matched = append(matched, d)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID > matched[j].ID })
+	total := len(matched)
+	if offset >= total {
+		return []domain.WebhookDelivery{}, total, nil
+	}
+	matched = matched[offset:]
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, total, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"pr-service/internal/db"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type orgRepository struct {
+	BaseRepository
+}
+
+// NewOrgRepository creates a new organization repository
+func NewOrgRepository(cm db.EngineFactory) OrgRepository {
+	return &orgRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// ListOrgs returns every organization that has created a team, the default
+// organization included
+func (r *orgRepository) ListOrgs(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT org_id
+		FROM organizations
+		ORDER BY org_id
+	`
+	var orgs []string
+	if err := pgxscan.Select(ctx, r.Engine(ctx), &orgs, query); err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return orgs, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type outboxRepository struct {
+	BaseRepository
+}
+
+// NewOutboxRepository creates a new event outbox repository
+func NewOutboxRepository(cm db.EngineFactory) OutboxRepository {
+	return &outboxRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// AppendOutboxMessages records messages in the outbox in one statement. Messages
+// with PublishedAt set are only kept in the event log and never relayed.
+func (r *outboxRepository) AppendOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var (
+		eventTypes = make([]string, len(messages))
+		keys       = make([]string, len(messages))
+		payloads   = make([]string, len(messages))
+		createdAt  = make([]time.Time, len(messages))
+		published  = make([]*time.Time, len(messages))
+	)
+	for i, m := range messages {
+		eventTypes[i] = string(m.EventType)
+		keys[i] = m.Key
+		payloads[i] = string(m.Payload)
+		createdAt[i] = m.CreatedAt
+		published[i] = m.PublishedAt
+	}
+
+	query := `
+		INSERT INTO event_outbox (event_type, message_key, payload, created_at, published_at)
+		SELECT event_type, message_key, payload::jsonb, created_at, published_at
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::timestamp[], $5::timestamp[])
+			WITH ORDINALITY AS m(event_type, message_key, payload, created_at, published_at, n)
+		ORDER BY n
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, eventTypes, keys, payloads, createdAt, published); err != nil {
+		return fmt.Errorf("failed to append outbox messages: %w", err)
+	}
+	return nil
+}
+
+// LockUnpublishedOutboxMessages returns up to limit of the oldest unpublished messages
+// and locks them until the transaction ends. Concurrent relays wait for the lock
+// instead of skipping ahead, so messages are relayed in order.
+func (r *outboxRepository) LockUnpublishedOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	query := `
+		SELECT id, event_type, message_key AS key, payload, created_at, published_at
+		FROM event_outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE
+	`
+	var messages []domain.OutboxMessage
+	if err := pgxscan.Select(ctx, r.Engine(ctx), &messages, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to lock outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+// MarkOutboxMessagesPublished records that the messages with ids were relayed at publishedAt
+func (r *outboxRepository) MarkOutboxMessagesPublished(ctx context.Context, ids []int64, publishedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE event_outbox
+		SET published_at = $2
+		WHERE id = ANY($1)
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, ids, publishedAt); err != nil {
+		return fmt.Errorf("failed to mark outbox messages published: %w", err)
+	}
+	return nil
+}
+
+// ListOutboxMessages returns up to limit messages of the event log after the
+// cursor in log order. Messages of transactions newer than the oldest one still
+// running are held back until it finishes, so a later read never finds a
+// message behind the last cursor returned.
+func (r *outboxRepository) ListOutboxMessages(ctx context.Context, after domain.EventCursor, limit int) ([]domain.OutboxMessage, error) {
+	query := `
+		SELECT id, txid AS tx_id, event_type, message_key AS key, payload, created_at, published_at
+		FROM event_outbox
+		WHERE (txid, id) > ($1::xid8, $2)
+			AND txid < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY txid, id
+		LIMIT $3
+	`
+	var messages []domain.OutboxMessage
+	if err := pgxscan.Select(ctx, r.Engine(ctx), &messages, query, after.TxID, after.ID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list outbox messages: %w", err)
+	}
+	return messages, nil
+}
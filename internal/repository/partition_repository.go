@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/db"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type partitionRepository struct {
+	BaseRepository
+}
+
+// NewPartitionRepository creates a new repository for the partitions of pull_requests
+func NewPartitionRepository(cm db.EngineFactory) PartitionRepository {
+	return &partitionRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// CreatePRPartitions creates the missing monthly partitions of pull_requests from the
+// month of from through the month of through and returns how many it created. Months
+// that already have rows in the default partition are left to it.
+func (r *partitionRepository) CreatePRPartitions(ctx context.Context, from, through time.Time) (int, error) {
+	query := `
+		SELECT create_pull_request_partitions($1, $2)
+	`
+	var created int
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &created, query, from, through); err != nil {
+		return 0, fmt.Errorf("failed to create pull request partitions: %w", err)
+	}
+	return created, nil
+}
@@ -0,0 +1,115 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/migrate"
+	"pr-service/internal/repository"
+	"pr-service/migrations"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// databaseURLEnv names the Postgres database the repository tests run
+// against; they are skipped when it is unset
+const databaseURLEnv = "TEST_DATABASE_URL"
+
+// testDB is a migrated schema of its own in the test database
+type testDB struct {
+	pool *pgxpool.Pool
+	cm   *db.ContextManager
+}
+
+// newTestDB creates a schema for t, applies the migrations to it and drops it
+// when t ends, so tests don't see each other's rows
+func newTestDB(t *testing.T) *testDB {
+	t.Helper()
+	url := os.Getenv(databaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", databaseURLEnv)
+	}
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer admin.Close(ctx)
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(context.Background(), url)
+		if err != nil {
+			t.Errorf("connect: %v", err)
+			return
+		}
+		defer conn.Close(context.Background())
+		if _, err := conn.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parse %s: %v", databaseURLEnv, err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := db.Connect(ctx, cfg, db.RetryPolicy{MaxAttempts: 1}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrator, err := migrate.New(pool, migrations.FS, zap.NewNop())
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	defer migrator.Close()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return &testDB{pool: pool, cm: db.NewContextManager(pool, zap.NewNop())}
+}
+
+// addUsers creates active users with the given IDs
+func (d *testDB) addUsers(t *testing.T, ctx context.Context, userIDs ...string) {
+	t.Helper()
+	users := repository.NewUserRepository(d.cm)
+	for _, id := range userIDs {
+		if err := users.CreateOrUpdateUser(ctx, domain.NewUser(id, id, "", true)); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+}
+
+// addPR creates an open PR of author in team, which may be empty, and assigns
+// it reviewers
+func (d *testDB) addPR(t *testing.T, ctx context.Context, prID, author, team string, reviewers ...string) {
+	t.Helper()
+	prs := repository.NewPRRepository(d.cm)
+	pr := domain.PullRequest{
+		PullRequestID:   prID,
+		PullRequestName: "Change " + prID,
+		AuthorID:        author,
+		TeamName:        team,
+		Status:          domain.PRStatusOpen,
+		CreatedAt:       time.Now(),
+	}
+	if err := prs.CreatePR(ctx, pr); err != nil {
+		t.Fatalf("create PR %s: %v", prID, err)
+	}
+	if err := prs.AssignReviewers(ctx, prID, reviewers); err != nil {
+		t.Fatalf("assign reviewers of %s: %v", prID, err)
+	}
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"pr-service/internal/db"
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 )
@@ -20,13 +22,19 @@ func NewPRRepository(cm db.EngineFactory) PRRepository {
 	}
 }
 
+// CreatePR registers the PR ID, which keeps IDs unique across the monthly
+// partitions of pull_requests, and inserts the PR
 func (r *prRepository) CreatePR(ctx context.Context, pr domain.PullRequest) error {
 	query := `
-		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		WITH id AS (
+			INSERT INTO pull_request_ids (pull_request_id, created_at)
+			VALUES ($1, $8)
+		)
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, team_name, repository, ticket_key, status, created_at, merged_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9)
 	`
 	_, err := r.Engine(ctx).Exec(ctx, query,
-		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.CreatedAt, pr.MergedAt)
+		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.TeamName, pr.Repository, pr.TicketKey, pr.Status, pr.CreatedAt, pr.MergedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create PR: %w", err)
 	}
@@ -34,14 +42,54 @@ func (r *prRepository) CreatePR(ctx context.Context, pr domain.PullRequest) erro
 }
 
 func (r *prRepository) GetPR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	return r.getPR(ctx, prID, "")
+}
+
+// GetPRForUpdate gets a PR and locks its row until the transaction ends, so
+// concurrent changes to its reviewers and status run one after another
+func (r *prRepository) GetPRForUpdate(ctx context.Context, prID string) (domain.PullRequest, error) {
+	return r.getPR(ctx, prID, "FOR UPDATE")
+}
+
+// prPartition matches the row of the PR with ID $1 by its partition key too,
+// so a lookup by ID only probes the partition of its month
+const prPartition = `pull_request_id = $1
+		AND created_at = (SELECT created_at FROM pull_request_ids WHERE pull_request_id = $1)`
+
+// workloadChange returns a CTE named workload that adds sign one to the open
+// reviews in reviewer_workload of every reviewer in the CTE named source,
+// which holds pull_request_id and user_id pairs, whose PR is open. It sees the
+// PRs as they were before the statement. Rows are locked in user order, so
+// concurrent changes don't deadlock.
+func workloadChange(source, sign string) string {
+	return `workload AS (
+			INSERT INTO reviewer_workload AS w (user_id, open_reviews)
+			SELECT c.user_id, ` + sign + `COUNT(*)
+			FROM ` + source + ` c
+			INNER JOIN pull_requests pr ON pr.pull_request_id = c.pull_request_id AND pr.status = 'OPEN'
+			GROUP BY c.user_id
+			ORDER BY c.user_id
+			ON CONFLICT (user_id, org_id) DO UPDATE SET open_reviews = w.open_reviews + EXCLUDED.open_reviews
+		)`
+}
+
+// getPR gets a PR with its reviewers, reading the PR row with the given
+// locking clause
+func (r *prRepository) getPR(ctx context.Context, prID, locking string) (domain.PullRequest, error) {
 	// Get PR details
 	prQuery := `
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		SELECT pull_request_id, pull_request_name, author_id, COALESCE(team_name, '') AS team_name,
+			COALESCE(repository, '') AS repository, COALESCE(ticket_key, '') AS ticket_key, status, created_at, merged_at, version
 		FROM pull_requests
-		WHERE pull_request_id = $1
-	`
+		WHERE ` + prPartition + `
+	` + locking
+	// Locking reads take their lock on the primary
+	engine := r.ReadEngine(ctx)
+	if locking != "" {
+		engine = r.Engine(ctx)
+	}
 	var pr domain.PullRequest
-	err := pgxscan.Get(ctx, r.Engine(ctx), &pr, prQuery, prID)
+	err := pgxscan.Get(ctx, engine, &pr, prQuery, prID)
 	if err != nil {
 		if pgxscan.NotFound(err) {
 			return domain.PullRequest{}, domain.ErrNotFound
@@ -57,7 +105,7 @@ func (r *prRepository) GetPR(ctx context.Context, prID string) (domain.PullReque
 		ORDER BY assigned_at
 	`
 	var reviewers []string
-	err = pgxscan.Select(ctx, r.Engine(ctx), &reviewers, reviewersQuery, prID)
+	err = pgxscan.Select(ctx, engine, &reviewers, reviewersQuery, prID)
 	if err != nil {
 		return domain.PullRequest{}, fmt.Errorf("failed to get PR reviewers: %w", err)
 	}
@@ -66,18 +114,46 @@ func (r *prRepository) GetPR(ctx context.Context, prID string) (domain.PullReque
 	return pr, nil
 }
 
+// UpdatePR updates a PR read at pr.Version and bumps its version. It fails
+// with ErrVersionConflict when the PR changed since it was read. A PR that
+// gets merged leaves the open reviews of its reviewers.
 func (r *prRepository) UpdatePR(ctx context.Context, pr domain.PullRequest) error {
 	query := `
-		UPDATE pull_requests
-		SET pull_request_name = $2, author_id = $3, status = $4, merged_at = $5
-		WHERE pull_request_id = $1
+		WITH updated AS (
+			UPDATE pull_requests
+			SET pull_request_name = $2, author_id = $3, status = $4, merged_at = $5, version = version + 1
+			WHERE ` + prPartition + ` AND version = $6
+			RETURNING pull_request_id, created_at, status
+		), changed AS (
+			SELECT u.pull_request_id, CASE WHEN u.status = 'OPEN' THEN 1 ELSE -1 END AS delta
+			FROM updated u
+			INNER JOIN pull_requests old
+				ON old.pull_request_id = u.pull_request_id AND old.created_at = u.created_at
+			WHERE old.status <> u.status
+		), workload AS (
+			INSERT INTO reviewer_workload AS w (user_id, open_reviews)
+			SELECT rev.user_id, c.delta
+			FROM changed c
+			INNER JOIN pr_reviewers rev ON rev.pull_request_id = c.pull_request_id
+			ORDER BY rev.user_id
+			ON CONFLICT (user_id, org_id) DO UPDATE SET open_reviews = w.open_reviews + EXCLUDED.open_reviews
+		)
+		SELECT COUNT(*) FROM updated
 	`
-	tag, err := r.Engine(ctx).Exec(ctx, query,
-		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.MergedAt)
+	var updated int
+	err := pgxscan.Get(ctx, r.Engine(ctx), &updated, query,
+		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.MergedAt, pr.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update PR: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
+	if updated == 0 {
+		exists, err := r.PRExists(ctx, pr.PullRequestID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrVersionConflict
+		}
 		return domain.ErrNotFound
 	}
 	return nil
@@ -89,28 +165,35 @@ func (r *prRepository) AssignReviewers(ctx context.Context, prID string, reviewe
 	}
 
 	query := `
-		INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at)
-		VALUES ($1, $2, NOW())
+		WITH assigned AS (
+			INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at)
+			SELECT $1, user_id, NOW()
+			FROM unnest($2::text[]) AS r(user_id)
+			RETURNING pull_request_id, user_id
+		), ` + workloadChange("assigned", "") + `
+		SELECT COUNT(*) FROM assigned
 	`
-	for _, userID := range reviewers {
-		_, err := r.Engine(ctx).Exec(ctx, query, prID, userID)
-		if err != nil {
-			return fmt.Errorf("failed to assign reviewer %s: %w", userID, err)
-		}
+	_, err := r.Engine(ctx).Exec(ctx, query, prID, reviewers)
+	if err != nil {
+		return fmt.Errorf("failed to assign reviewers: %w", err)
 	}
 	return nil
 }
 
 func (r *prRepository) RemoveReviewer(ctx context.Context, prID string, userID string) error {
 	query := `
-		DELETE FROM pr_reviewers
-		WHERE pull_request_id = $1 AND user_id = $2
+		WITH removed AS (
+			DELETE FROM pr_reviewers
+			WHERE pull_request_id = $1 AND user_id = $2
+			RETURNING pull_request_id, user_id
+		), ` + workloadChange("removed", "-") + `
+		SELECT COUNT(*) FROM removed
 	`
-	tag, err := r.Engine(ctx).Exec(ctx, query, prID, userID)
-	if err != nil {
+	var removed int
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &removed, query, prID, userID); err != nil {
 		return fmt.Errorf("failed to remove reviewer: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
+	if removed == 0 {
 		return domain.ErrNotFound
 	}
 	return nil
@@ -118,9 +201,13 @@ func (r *prRepository) RemoveReviewer(ctx context.Context, prID string, userID s
 
 func (r *prRepository) AddReviewer(ctx context.Context, prID string, userID string) error {
 	query := `
-		INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (pull_request_id, user_id) DO NOTHING
+		WITH added AS (
+			INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (pull_request_id, user_id, org_id) DO NOTHING
+			RETURNING pull_request_id, user_id
+		), ` + workloadChange("added", "") + `
+		SELECT COUNT(*) FROM added
 	`
 	_, err := r.Engine(ctx).Exec(ctx, query, prID, userID)
 	if err != nil {
@@ -131,60 +218,369 @@ func (r *prRepository) AddReviewer(ctx context.Context, prID string, userID stri
 
 func (r *prRepository) GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error) {
 	query := `
-		SELECT DISTINCT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at
+		SELECT ` + prColumnsWithReviewers + `
 		FROM pull_requests pr
-		INNER JOIN pr_reviewers rev ON pr.pull_request_id = rev.pull_request_id
-		WHERE rev.user_id = $1
+		WHERE EXISTS (
+			SELECT 1 FROM pr_reviewers r
+			WHERE r.pull_request_id = pr.pull_request_id AND r.user_id = $1
+		)
 		ORDER BY pr.created_at DESC
 	`
 	var prs []domain.PullRequest
-	err := pgxscan.Select(ctx, r.Engine(ctx), &prs, query, userID)
+	err := pgxscan.Select(ctx, r.ReadEngine(ctx), &prs, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PRs by reviewer: %w", err)
 	}
+	return prs, nil
+}
+
+// prColumnsWithReviewers selects a PR aliased pr with its reviewers in
+// assignment order, so PR lists need no query per PR
+const prColumnsWithReviewers = `pr.pull_request_id, pr.pull_request_name, pr.author_id, COALESCE(pr.team_name, '') AS team_name,
+			COALESCE(pr.repository, '') AS repository, COALESCE(pr.ticket_key, '') AS ticket_key,
+			pr.status, pr.created_at, pr.merged_at, pr.version,
+			ARRAY(
+				SELECT rev.user_id FROM pr_reviewers rev
+				WHERE rev.pull_request_id = pr.pull_request_id
+				ORDER BY rev.assigned_at
+			) AS assigned_reviewers`
 
-	for i := range prs {
-		prs[i].AssignedReviewers = []string{}
+// GetPendingReviewsByReviewer returns the reviews of open PRs assigned to userID
+// that are still waiting for the reviewer's first action, oldest assignment first
+func (r *prRepository) GetPendingReviewsByReviewer(ctx context.Context, userID string) ([]domain.ReviewAssignment, error) {
+	query := `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, COALESCE(pr.team_name, '') AS team_name,
+			COALESCE(pr.repository, '') AS repository, rev.user_id, rev.assigned_at
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE rev.user_id = $1 AND pr.status = 'OPEN' AND rev.first_action_at IS NULL
+		ORDER BY rev.assigned_at, pr.pull_request_id
+	`
+	var reviews []domain.ReviewAssignment
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &reviews, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get pending reviews by reviewer: %w", err)
 	}
+	return reviews, nil
+}
 
-	return prs, nil
+// GetPendingReviewsByReviewers returns the reviews of open PRs assigned to any of
+// userIDs that are still waiting for the reviewer's first action, grouped by
+// reviewer with the oldest assignment first
+func (r *prRepository) GetPendingReviewsByReviewers(ctx context.Context, userIDs []string) ([]domain.ReviewAssignment, error) {
+	query := `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, COALESCE(pr.team_name, '') AS team_name,
+			COALESCE(pr.repository, '') AS repository, rev.user_id, rev.assigned_at
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE rev.user_id = ANY($1) AND pr.status = 'OPEN' AND rev.first_action_at IS NULL
+		ORDER BY rev.user_id, rev.assigned_at, pr.pull_request_id
+	`
+	var reviews []domain.ReviewAssignment
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &reviews, query, userIDs); err != nil {
+		return nil, fmt.Errorf("failed to get pending reviews by reviewers: %w", err)
+	}
+	return reviews, nil
+}
+
+// prFilterCondition matches domain.PRFilter against pull_requests aliased pr.
+// The SQL is fixed and every value is bound, so filters built from user input
+// never change the statement. Its parameters are the first ones of the query,
+// in the order of prFilterArgs.
+const prFilterCondition = `
+	(cardinality($1::text[]) = 0 OR pr.ticket_key = ANY($1))
+	AND (cardinality($2::text[]) = 0 OR pr.status = ANY($2))
+	AND (cardinality($3::text[]) = 0 OR pr.author_id = ANY($3))
+	AND (cardinality($4::text[]) = 0 OR pr.team_name = ANY($4))
+	AND (cardinality($5::text[]) = 0 OR pr.repository = ANY($5))
+	AND (cardinality($6::text[]) = 0 OR EXISTS (
+		SELECT 1 FROM pr_reviewers fr
+		WHERE fr.pull_request_id = pr.pull_request_id AND fr.user_id = ANY($6)
+	))
+	AND ($7::timestamptz IS NULL OR pr.created_at >= $7)
+	AND ($8::timestamptz IS NULL OR pr.created_at < $8)
+	AND ($9::timestamptz IS NULL OR pr.merged_at >= $9)
+	AND ($10::timestamptz IS NULL OR pr.merged_at < $10)
+`
+
+// prFilterParams is the number of parameters taken by prFilterCondition
+const prFilterParams = 10
+
+func prFilterArgs(filter domain.PRFilter) []any {
+	statuses := make([]string, len(filter.Statuses))
+	for i, status := range filter.Statuses {
+		statuses[i] = string(status)
+	}
+	return []any{
+		nonNil(filter.TicketKeys), statuses, nonNil(filter.AuthorIDs), nonNil(filter.TeamNames),
+		nonNil(filter.Repositories), nonNil(filter.ReviewerIDs),
+		filter.CreatedFrom, filter.CreatedTo, filter.MergedFrom, filter.MergedTo,
+	}
+}
+
+// nonNil binds an unset list as an empty array rather than NULL
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// ListPRs returns up to page.Fetch() PRs matching filter, ordered by creation
+// time, with their reviewers, and the total number of matching PRs
+func (r *prRepository) ListPRs(ctx context.Context, filter domain.PRFilter, page pagination.Page) ([]domain.PullRequest, int, error) {
+	args := prFilterArgs(filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM pull_requests pr WHERE ` + prFilterCondition
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count PRs: %w", err)
+	}
+
+	var afterTime *time.Time
+	var afterID string
+	if page.After != nil {
+		t, err := page.After.Time()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list PRs: %w", err)
+		}
+		afterTime, afterID = &t, page.After.ID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+prColumnsWithReviewers+`
+		FROM pull_requests pr
+		WHERE %[3]s
+			AND ($%[6]d::timestamptz IS NULL OR pr.created_at %[1]s $%[6]d
+				OR (pr.created_at = $%[6]d AND pr.pull_request_id > $%[7]d))
+		ORDER BY pr.created_at %[2]s, pr.pull_request_id
+		LIMIT $%[4]d OFFSET $%[5]d
+	`, page.Order.After(), page.Order.SQL(), prFilterCondition,
+		prFilterParams+1, prFilterParams+2, prFilterParams+3, prFilterParams+4)
+	args = append(args, page.Fetch(), page.Offset, afterTime, afterID)
+	var prs []domain.PullRequest
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &prs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list PRs: %w", err)
+	}
+
+	return prs, total, nil
+}
+
+// DeletePR removes a PR by its ID; the PR and its reviewer assignments are
+// dropped by the foreign key cascade, and an open PR leaves the open reviews
+// of its reviewers
+func (r *prRepository) DeletePR(ctx context.Context, prID string) error {
+	query := `
+		WITH removed AS (
+			SELECT pull_request_id, user_id
+			FROM pr_reviewers
+			WHERE pull_request_id = $1
+		), ` + workloadChange("removed", "-") + `
+		DELETE FROM pull_request_ids
+		WHERE pull_request_id = $1
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, prID)
+	if err != nil {
+		return fmt.Errorf("failed to delete PR: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ArchiveMergedPRs moves up to limit PRs merged before mergedBefore, oldest
+// first, to the archive tables along with their reviewer assignments and
+// reassignments, and returns how many were moved. Rows locked by another
+// archiving run are skipped. An archived PR whose ID was reused keeps its
+// archived row.
+func (r *prRepository) ArchiveMergedPRs(ctx context.Context, mergedBefore time.Time, limit int) (int, error) {
+	query := `
+		WITH batch AS (
+			SELECT pull_request_id
+			FROM pull_requests
+			WHERE status = 'MERGED' AND merged_at < $1
+			ORDER BY merged_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), reviewers AS (
+			INSERT INTO pr_reviewers_archive
+			SELECT pr_reviewers.*
+			FROM pr_reviewers
+			INNER JOIN batch USING (pull_request_id)
+			ON CONFLICT DO NOTHING
+		), reassignments AS (
+			DELETE FROM reviewer_reassignments
+			WHERE pull_request_id IN (SELECT pull_request_id FROM batch)
+			RETURNING *
+		), archived_reassignments AS (
+			INSERT INTO reviewer_reassignments_archive
+			SELECT * FROM reassignments
+			ON CONFLICT DO NOTHING
+		), archived AS (
+			DELETE FROM pull_requests
+			WHERE pull_request_id IN (SELECT pull_request_id FROM batch)
+			RETURNING *
+		), ids AS (
+			DELETE FROM pull_request_ids
+			WHERE pull_request_id IN (SELECT pull_request_id FROM batch)
+		), inserted AS (
+			INSERT INTO pull_requests_archive (pull_request_id, pull_request_name, author_id, status,
+				created_at, merged_at, team_name, repository, ticket_key, version, org_id)
+			SELECT pull_request_id, pull_request_name, author_id, status,
+				created_at, merged_at, team_name, repository, ticket_key, version, org_id
+			FROM archived
+			ON CONFLICT DO NOTHING
+		)
+		SELECT COUNT(*) FROM archived
+	`
+	var archived int
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &archived, query, mergedBefore, limit); err != nil {
+		return 0, fmt.Errorf("failed to archive merged PRs: %w", err)
+	}
+	return archived, nil
+}
+
+// DeleteMergedPRs deletes up to limit PRs merged before mergedBefore, oldest
+// first, with their reviewer assignments and reassignments, and returns how
+// many were deleted. Deleting their IDs deletes the PRs and assignments by
+// the foreign key cascade.
+func (r *prRepository) DeleteMergedPRs(ctx context.Context, mergedBefore time.Time, limit int) (int, error) {
+	query := `
+		WITH batch AS (
+			SELECT pull_request_id
+			FROM pull_requests
+			WHERE status = 'MERGED' AND merged_at < $1
+			ORDER BY merged_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), reassignments AS (
+			DELETE FROM reviewer_reassignments
+			WHERE pull_request_id IN (SELECT pull_request_id FROM batch)
+		), deleted AS (
+			DELETE FROM pull_request_ids
+			WHERE pull_request_id IN (SELECT pull_request_id FROM batch)
+			RETURNING pull_request_id
+		)
+		SELECT COUNT(*) FROM deleted
+	`
+	var deleted int
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &deleted, query, mergedBefore, limit); err != nil {
+		return 0, fmt.Errorf("failed to delete merged PRs: %w", err)
+	}
+	return deleted, nil
 }
 
-// PRExists checks if a PR exists
+// PRExists checks if a PR exists by its registered ID, without searching the
+// partitions of pull_requests
 func (r *prRepository) PRExists(ctx context.Context, prID string) (bool, error) {
 	query := `
-		SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)
+		SELECT EXISTS(SELECT 1 FROM pull_request_ids WHERE pull_request_id = $1)
 	`
 	var exists bool
-	err := pgxscan.Get(ctx, r.Engine(ctx), &exists, query, prID)
+	err := pgxscan.Get(ctx, r.ReadEngine(ctx), &exists, query, prID)
 	if err != nil {
 		return false, fmt.Errorf("failed to check PR existence: %w", err)
 	}
 	return exists, nil
 }
 
-// GetAssignmentStatsByUser returns assignment count per user
-func (r *prRepository) GetAssignmentStatsByUser(ctx context.Context) (map[string]int, error) {
+// GetAssignmentStatsByUser returns a page of per-user counts of assignments made within [from, to)
+// and the number of users with assignments
+func (r *prRepository) GetAssignmentStatsByUser(
+	ctx context.Context,
+	from, to time.Time,
+	sort domain.StatsSort,
+	limit, offset int,
+) ([]domain.KeyCount, int, error) {
+	stats, total, err := r.assignmentCounts(ctx, "user_id", from, to, sort, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get assignment stats by user: %w", err)
+	}
+	return stats, total, nil
+}
+
+// GetAssignmentStatsByPR returns a page of per-PR counts of assignments made within [from, to)
+// and the number of PRs with assignments
+func (r *prRepository) GetAssignmentStatsByPR(
+	ctx context.Context,
+	from, to time.Time,
+	sort domain.StatsSort,
+	limit, offset int,
+) ([]domain.KeyCount, int, error) {
+	stats, total, err := r.assignmentCounts(ctx, "pull_request_id", from, to, sort, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get assignment stats by PR: %w", err)
+	}
+	return stats, total, nil
+}
+
+// assignmentCounts groups pr_reviewers by keyColumn, which must be a trusted column name.
+// Like the other stats over a window it counts the assignments made within [from, to),
+// which reviewer_workload, holding only the current open reviews, cannot answer; the
+// assigned_at index keeps it to the rows of the window.
+func (r *prRepository) assignmentCounts(
+	ctx context.Context,
+	keyColumn string,
+	from, to time.Time,
+	sort domain.StatsSort,
+	limit, offset int,
+) ([]domain.KeyCount, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(DISTINCT ` + keyColumn + `)
+		FROM pr_reviewers
+		WHERE assigned_at >= $1 AND assigned_at < $2
+	`
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery, from, to); err != nil {
+		return nil, 0, err
+	}
+
+	order := "count DESC, key"
+	switch sort {
+	case domain.StatsSortCountAsc:
+		order = "count ASC, key"
+	case domain.StatsSortKey:
+		order = "key"
+	}
+
 	query := `
-		SELECT user_id, COUNT(*) as assignment_count
+		SELECT ` + keyColumn + ` AS key, COUNT(*) AS count
 		FROM pr_reviewers
-		GROUP BY user_id
-		ORDER BY assignment_count DESC
+		WHERE assigned_at >= $1 AND assigned_at < $2
+		GROUP BY ` + keyColumn + `
+		ORDER BY ` + order + `
+		LIMIT $3 OFFSET $4
 	`
-	rows, err := r.Engine(ctx).Query(ctx, query)
+	var stats []domain.KeyCount
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &stats, query, from, to, limit, offset); err != nil {
+		return nil, 0, err
+	}
+	return stats, total, nil
+}
+
+// GetAssignmentStatsByRole returns assignment count per reviewer team role for assignments made within [from, to)
+func (r *prRepository) GetAssignmentStatsByRole(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	query := `
+		SELECT u.role, COUNT(*) as assignment_count
+		FROM pr_reviewers rev
+		INNER JOIN users u ON u.user_id = rev.user_id
+		WHERE rev.assigned_at >= $1 AND rev.assigned_at < $2
+		GROUP BY u.role
+	`
+	rows, err := r.ReadEngine(ctx).Query(ctx, query, from, to)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get assignment stats by user: %w", err)
+		return nil, fmt.Errorf("failed to get assignment stats by role: %w", err)
 	}
 	defer rows.Close()
 
 	stats := make(map[string]int)
 	for rows.Next() {
-		var userID string
+		var role string
 		var count int
-		if err := rows.Scan(&userID, &count); err != nil {
+		if err := rows.Scan(&role, &count); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-		stats[userID] = count
+		stats[role] = count
 	}
 
 	if err := rows.Err(); err != nil {
@@ -194,28 +590,31 @@ func (r *prRepository) GetAssignmentStatsByUser(ctx context.Context) (map[string
 	return stats, nil
 }
 
-// GetAssignmentStatsByPR returns assignment count per PR
-func (r *prRepository) GetAssignmentStatsByPR(ctx context.Context) (map[string]int, error) {
+// GetAssignmentStatsByTeam returns assignment count per reviewer team for assignments made within [from, to).
+// A reviewer in several teams counts towards each of them.
+func (r *prRepository) GetAssignmentStatsByTeam(ctx context.Context, from, to time.Time) (map[string]int, error) {
 	query := `
-		SELECT pull_request_id, COUNT(*) as reviewer_count
-		FROM pr_reviewers
-		GROUP BY pull_request_id
-		ORDER BY reviewer_count DESC
+		SELECT tm.team_name, COUNT(*) as assignment_count
+		FROM pr_reviewers rev
+		INNER JOIN users u ON u.user_id = rev.user_id
+		INNER JOIN team_members tm ON tm.user_id = u.user_id
+		WHERE rev.assigned_at >= $1 AND rev.assigned_at < $2
+		GROUP BY tm.team_name
 	`
-	rows, err := r.Engine(ctx).Query(ctx, query)
+	rows, err := r.ReadEngine(ctx).Query(ctx, query, from, to)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get assignment stats by PR: %w", err)
+		return nil, fmt.Errorf("failed to get assignment stats by team: %w", err)
 	}
 	defer rows.Close()
 
 	stats := make(map[string]int)
 	for rows.Next() {
-		var prID string
+		var teamName string
 		var count int
-		if err := rows.Scan(&prID, &count); err != nil {
+		if err := rows.Scan(&teamName, &count); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-		stats[prID] = count
+		stats[teamName] = count
 	}
 
 	if err := rows.Err(); err != nil {
@@ -225,19 +624,498 @@ func (r *prRepository) GetAssignmentStatsByPR(ctx context.Context) (map[string]i
 	return stats, nil
 }
 
-// GetOpenPRIDsByReviewer returns IDs of open PRs assigned to reviewer.
-func (r *prRepository) GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
+// GetReviewerLoads returns the number of assignments made within [from, to) for every
+// active member of every team, including members with no assignments
+func (r *prRepository) GetReviewerLoads(ctx context.Context, from, to time.Time) ([]domain.ReviewerLoad, error) {
+	query := `
+		SELECT tm.team_name, u.user_id, COUNT(rev.pull_request_id) AS count
+		FROM team_members tm
+		INNER JOIN users u ON u.user_id = tm.user_id
+		LEFT JOIN pr_reviewers rev ON rev.user_id = u.user_id
+			AND rev.assigned_at >= $1 AND rev.assigned_at < $2
+		WHERE u.is_active AND u.deleted_at IS NULL
+		GROUP BY tm.team_name, u.user_id
+		ORDER BY tm.team_name, u.user_id
+	`
+	var loads []domain.ReviewerLoad
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &loads, query, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get reviewer loads: %w", err)
+	}
+	return loads, nil
+}
+
+// GetReviewPairs returns author→reviewer assignment counts for assignments made within
+// [from, to), optionally limited to PRs of teamName. A PR is created before anything
+// happens to it, so the stats queries bound its created_at by to as well, which skips
+// the partitions of later months.
+func (r *prRepository) GetReviewPairs(ctx context.Context, from, to time.Time, teamName string) ([]domain.ReviewPair, error) {
+	query := `
+		SELECT pr.author_id, rev.user_id AS reviewer_id, COUNT(*) AS count
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE rev.assigned_at >= $1 AND rev.assigned_at < $2
+			AND pr.created_at < $2
+			AND ($3 = '' OR pr.team_name = $3)
+		GROUP BY pr.author_id, rev.user_id
+		ORDER BY pr.author_id, rev.user_id
+	`
+	var pairs []domain.ReviewPair
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &pairs, query, from, to, teamName); err != nil {
+		return nil, fmt.Errorf("failed to get review pairs: %w", err)
+	}
+	return pairs, nil
+}
+
+// GetReviewSLA counts, per PR team, assignments made within [from, to) that are due
+// as of now under sla and those that got a first action within sla
+func (r *prRepository) GetReviewSLA(ctx context.Context, from, to, now time.Time, sla time.Duration) ([]domain.ReviewSLA, error) {
+	query := `
+		SELECT COALESCE(pr.team_name, '') AS team_name,
+			COUNT(*) FILTER (WHERE rev.first_action_at IS NOT NULL
+				OR rev.assigned_at <= $3::timestamp - make_interval(secs => $4)) AS due,
+			COUNT(*) FILTER (WHERE rev.first_action_at IS NOT NULL
+				AND EXTRACT(EPOCH FROM rev.first_action_at - rev.assigned_at) <= $4) AS met
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE rev.assigned_at >= $1 AND rev.assigned_at < $2
+			AND pr.created_at < $2
+		GROUP BY 1
+		ORDER BY 1
+	`
+	var stats []domain.ReviewSLA
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &stats, query, from, to, now, sla.Seconds()); err != nil {
+		return nil, fmt.Errorf("failed to get review SLA: %w", err)
+	}
+	return stats, nil
+}
+
+// GetOpenReviewCounts returns the number of open PRs every active user is reviewing,
+// including users with none, busiest first. Capacity and Utilization are left empty.
+// The counts are read from reviewer_workload, which the writes keep up to date.
+func (r *prRepository) GetOpenReviewCounts(ctx context.Context) ([]domain.ReviewerWorkload, error) {
+	query := `
+		SELECT u.user_id, u.username, COALESCE(w.open_reviews, 0) AS open_reviews
+		FROM users u
+		LEFT JOIN reviewer_workload w ON w.user_id = u.user_id
+		WHERE u.is_active AND u.deleted_at IS NULL
+		ORDER BY open_reviews DESC, u.user_id
+	`
+	var workloads []domain.ReviewerWorkload
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &workloads, query); err != nil {
+		return nil, fmt.Errorf("failed to get open review counts: %w", err)
+	}
+	return workloads, nil
+}
+
+// GetOpenPRAging counts open PRs per team in age buckets relative to now:
+// under a day, one to three days, three to seven days and over a week
+func (r *prRepository) GetOpenPRAging(ctx context.Context, now time.Time) ([]domain.PRAging, error) {
+	query := `
+		SELECT COALESCE(team_name, '') AS team_name,
+			COUNT(*) FILTER (WHERE created_at > $1::timestamp - INTERVAL '1 day') AS under_one_day,
+			COUNT(*) FILTER (WHERE created_at <= $1::timestamp - INTERVAL '1 day'
+				AND created_at > $1::timestamp - INTERVAL '3 days') AS one_to_three_days,
+			COUNT(*) FILTER (WHERE created_at <= $1::timestamp - INTERVAL '3 days'
+				AND created_at > $1::timestamp - INTERVAL '7 days') AS three_to_seven_days,
+			COUNT(*) FILTER (WHERE created_at <= $1::timestamp - INTERVAL '7 days') AS over_seven_days
+		FROM pull_requests
+		WHERE status = 'OPEN'
+		GROUP BY COALESCE(team_name, '')
+		ORDER BY team_name
+	`
+	var aging []domain.PRAging
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &aging, query, now); err != nil {
+		return nil, fmt.Errorf("failed to get open PR aging: %w", err)
+	}
+	return aging, nil
+}
+
+// ReplaceReviewers applies reviewer replacements with one statement to remove
+// the old reviewers and one to add the new ones; an empty NewUserID only
+// removes. It fails with ErrNotFound when an old reviewer isn't assigned.
+func (r *prRepository) ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error {
+	if len(reassignments) == 0 {
+		return nil
+	}
+
+	var (
+		prIDs      = make([]string, len(reassignments))
+		oldUserIDs = make([]string, len(reassignments))
+		newUserIDs = make([]string, len(reassignments))
+	)
+	for i, ra := range reassignments {
+		prIDs[i] = ra.PullRequestID
+		oldUserIDs[i] = ra.OldUserID
+		newUserIDs[i] = ra.NewUserID
+	}
+
+	removeQuery := `
+		WITH removed AS (
+			DELETE FROM pr_reviewers rev
+			USING unnest($1::text[], $2::text[]) AS ra(pull_request_id, user_id)
+			WHERE rev.pull_request_id = ra.pull_request_id AND rev.user_id = ra.user_id
+			RETURNING rev.pull_request_id, rev.user_id
+		), ` + workloadChange("removed", "-") + `
+		SELECT COUNT(*) FROM removed
+	`
+	var removed int
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &removed, removeQuery, prIDs, oldUserIDs); err != nil {
+		return fmt.Errorf("failed to remove reviewers: %w", err)
+	}
+	if removed != len(reassignments) {
+		return domain.ErrNotFound
+	}
+
+	addQuery := `
+		WITH added AS (
+			INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at)
+			SELECT pull_request_id, user_id, NOW()
+			FROM unnest($1::text[], $2::text[]) AS ra(pull_request_id, user_id)
+			WHERE user_id <> ''
+			ON CONFLICT (pull_request_id, user_id, org_id) DO NOTHING
+			RETURNING pull_request_id, user_id
+		), ` + workloadChange("added", "") + `
+		SELECT COUNT(*) FROM added
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, addQuery, prIDs, newUserIDs); err != nil {
+		return fmt.Errorf("failed to add reviewers: %w", err)
+	}
+	return nil
+}
+
+// RecordReassignments appends reviewer replacements and closed reviews to the reassignment log
+func (r *prRepository) RecordReassignments(ctx context.Context, reassignments []domain.Reassignment) error {
+	if len(reassignments) == 0 {
+		return nil
+	}
+
+	var (
+		prIDs      = make([]string, len(reassignments))
+		oldUserIDs = make([]string, len(reassignments))
+		newUserIDs = make([]string, len(reassignments))
+	)
+	for i, ra := range reassignments {
+		prIDs[i] = ra.PullRequestID
+		oldUserIDs[i] = ra.OldUserID
+		newUserIDs[i] = ra.NewUserID
+	}
+
+	query := `
+		INSERT INTO reviewer_reassignments (pull_request_id, old_user_id, new_user_id, created_at)
+		SELECT pull_request_id, old_user_id, NULLIF(new_user_id, ''), NOW()
+		FROM unnest($1::text[], $2::text[], $3::text[]) AS ra(pull_request_id, old_user_id, new_user_id)
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, prIDs, oldUserIDs, newUserIDs); err != nil {
+		return fmt.Errorf("failed to record reassignments: %w", err)
+	}
+	return nil
+}
+
+// GetReassignmentStatsByUser counts reviewers taken off PRs within [from, to) per removed reviewer
+func (r *prRepository) GetReassignmentStatsByUser(ctx context.Context, from, to time.Time) ([]domain.ReassignmentStats, error) {
+	return r.reassignmentStats(ctx, "ra.old_user_id", from, to)
+}
+
+// GetReassignmentStatsByTeam counts reviewers taken off PRs within [from, to) per PR team
+func (r *prRepository) GetReassignmentStatsByTeam(ctx context.Context, from, to time.Time) ([]domain.ReassignmentStats, error) {
+	return r.reassignmentStats(ctx, "COALESCE(pr.team_name, '')", from, to)
+}
+
+// reassignmentStats groups the reassignment log by keyExpr, which must be a trusted SQL expression
+func (r *prRepository) reassignmentStats(ctx context.Context, keyExpr string, from, to time.Time) ([]domain.ReassignmentStats, error) {
+	query := `
+		SELECT ` + keyExpr + ` AS key,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE ra.new_user_id IS NULL) AS closed
+		FROM reviewer_reassignments ra
+		LEFT JOIN pull_requests pr ON pr.pull_request_id = ra.pull_request_id AND pr.created_at < $2
+		WHERE ra.created_at >= $1 AND ra.created_at < $2
+		GROUP BY 1
+		ORDER BY total DESC, key
+	`
+	var stats []domain.ReassignmentStats
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &stats, query, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get reassignment stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetOpenPRsByReviewers returns open PRs assigned to any of userIDs with their
+// reviewers, oldest first
+func (r *prRepository) GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
 	query := `
-		SELECT pr.pull_request_id
+		SELECT ` + prColumnsWithReviewers + `
 		FROM pull_requests pr
-		INNER JOIN pr_reviewers rev ON pr.pull_request_id = rev.pull_request_id
-		WHERE rev.user_id = $1 AND pr.status = 'OPEN'
-		ORDER BY pr.created_at ASC
+		WHERE pr.status = 'OPEN' AND EXISTS (
+			SELECT 1 FROM pr_reviewers r
+			WHERE r.pull_request_id = pr.pull_request_id AND r.user_id = ANY($1)
+		)
+		ORDER BY pr.created_at, pr.pull_request_id
+	`
+	var prs []domain.PullRequest
+	err := pgxscan.Select(ctx, r.ReadEngine(ctx), &prs, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open PRs by reviewers: %w", err)
+	}
+	return prs, nil
+}
+
+// GetOpenPRIDsByTeam returns IDs of open PRs that belong to a team.
+func (r *prRepository) GetOpenPRIDsByTeam(ctx context.Context, teamName string) ([]string, error) {
+	query := `
+		SELECT pull_request_id
+		FROM pull_requests
+		WHERE team_name = $1 AND status = 'OPEN'
+		ORDER BY created_at ASC
 	`
 	var prIDs []string
-	err := pgxscan.Select(ctx, r.Engine(ctx), &prIDs, query, userID)
+	err := pgxscan.Select(ctx, r.ReadEngine(ctx), &prIDs, query, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get open PRs by reviewer: %w", err)
+		return nil, fmt.Errorf("failed to get open PRs by team: %w", err)
 	}
 	return prIDs, nil
 }
+
+// MovePRsToTeam reassigns every PR of fromTeam to toTeam, keeping reviewers as they are.
+func (r *prRepository) MovePRsToTeam(ctx context.Context, fromTeam, toTeam string) error {
+	query := `
+		UPDATE pull_requests
+		SET team_name = $2, version = version + 1
+		WHERE team_name = $1
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, fromTeam, toTeam)
+	if err != nil {
+		return fmt.Errorf("failed to move PRs to team: %w", err)
+	}
+	return nil
+}
+
+// RecordReviewerAction stores when a reviewer first acted on a PR and returns it.
+// Later actions keep the first timestamp.
+func (r *prRepository) RecordReviewerAction(ctx context.Context, prID, userID string, at time.Time) (time.Time, error) {
+	query := `
+		UPDATE pr_reviewers
+		SET first_action_at = COALESCE(first_action_at, $3)
+		WHERE pull_request_id = $1 AND user_id = $2
+		RETURNING first_action_at
+	`
+	var firstActionAt time.Time
+	err := pgxscan.Get(ctx, r.Engine(ctx), &firstActionAt, query, prID, userID, at)
+	if err != nil {
+		if pgxscan.NotFound(err) {
+			return time.Time{}, domain.ErrNotFound
+		}
+		return time.Time{}, fmt.Errorf("failed to record reviewer action: %w", err)
+	}
+	return firstActionAt, nil
+}
+
+// ClaimStaleReviews marks up to limit open reviews assigned before assignedBefore and not
+// acted on yet as notified at now and returns them, so each stale review is reported once.
+// SKIP LOCKED lets several instances sweep concurrently.
+func (r *prRepository) ClaimStaleReviews(ctx context.Context, assignedBefore, now time.Time, limit int) ([]domain.StaleReview, error) {
+	reviews, err := r.claimReviews(ctx, "stale_notified_at", assignedBefore, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim stale reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// ClaimOverdueReviews marks up to limit open reviews assigned before assignedBefore and not
+// acted on yet as escalated at now and returns them, so each overdue review is escalated once.
+// Escalation is tracked apart from stale reminders, which usually fire earlier.
+func (r *prRepository) ClaimOverdueReviews(ctx context.Context, assignedBefore, now time.Time, limit int) ([]domain.StaleReview, error) {
+	reviews, err := r.claimReviews(ctx, "escalated_at", assignedBefore, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim overdue reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// claimReviews sets marker, a timestamp column of pr_reviewers, to now on up to limit
+// open reviews assigned before assignedBefore that are not acted on or marked yet, and
+// returns them
+func (r *prRepository) claimReviews(ctx context.Context, marker string, assignedBefore, now time.Time, limit int) ([]domain.StaleReview, error) {
+	query := `
+		UPDATE pr_reviewers rev
+		SET ` + marker + ` = $2
+		FROM pull_requests pr
+		WHERE pr.pull_request_id = rev.pull_request_id
+			AND (rev.pull_request_id, rev.user_id) IN (
+				SELECT r.pull_request_id, r.user_id
+				FROM pr_reviewers r
+				JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+				WHERE p.status = 'OPEN'
+					AND r.first_action_at IS NULL
+					AND r.` + marker + ` IS NULL
+					AND r.assigned_at <= $1
+				ORDER BY r.assigned_at
+				LIMIT $3
+				FOR UPDATE OF r SKIP LOCKED
+			)
+		RETURNING rev.pull_request_id, pr.pull_request_name, COALESCE(pr.team_name, '') AS team_name, rev.user_id, rev.assigned_at
+	`
+	var reviews []domain.StaleReview
+	if err := pgxscan.Select(ctx, r.Engine(ctx), &reviews, query, assignedBefore, now, limit); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetTimeToFirstReviewByTeam returns assignment-to-first-action percentiles per PR team
+// for reviews first acted on within [from, to), optionally limited to one repository
+func (r *prRepository) GetTimeToFirstReviewByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToFirstReview(ctx, "COALESCE(pr.team_name, '')", from, to, repository)
+}
+
+// GetTimeToFirstReviewByUser returns assignment-to-first-action percentiles per reviewer
+// for reviews first acted on within [from, to), optionally limited to one repository
+func (r *prRepository) GetTimeToFirstReviewByUser(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToFirstReview(ctx, "rev.user_id", from, to, repository)
+}
+
+// GetTimeToFirstReviewByRepository returns assignment-to-first-action percentiles per PR repository
+// for reviews first acted on within [from, to), optionally limited to one repository
+func (r *prRepository) GetTimeToFirstReviewByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToFirstReview(ctx, "COALESCE(pr.repository, '')", from, to, repository)
+}
+
+// timeToFirstReview aggregates first-review latency grouped by keyExpr, which must be a trusted SQL expression
+func (r *prRepository) timeToFirstReview(
+	ctx context.Context,
+	keyExpr string,
+	from, to time.Time,
+	repository string,
+) ([]domain.LatencyStats, error) {
+	query := `
+		WITH latencies AS (
+			SELECT ` + keyExpr + ` AS key,
+				EXTRACT(EPOCH FROM rev.first_action_at - rev.assigned_at)::float8 AS seconds
+			FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			WHERE rev.first_action_at >= $1 AND rev.first_action_at < $2
+				AND pr.created_at < $2
+				AND ($3 = '' OR pr.repository = $3)
+		)
+		SELECT key, COUNT(*) AS count,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds) AS p50,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY seconds) AS p90,
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY seconds) AS p99
+		FROM latencies
+		GROUP BY key
+		ORDER BY key
+	`
+	var stats []domain.LatencyStats
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &stats, query, from, to, repository); err != nil {
+		return nil, fmt.Errorf("failed to get time to first review: %w", err)
+	}
+	return stats, nil
+}
+
+// GetTimeToMergeByTeam returns created-to-merged percentiles per PR team for PRs merged
+// within [from, to), optionally limited to one repository
+func (r *prRepository) GetTimeToMergeByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(ctx, "COALESCE(team_name, '')", from, to, repository)
+}
+
+// GetTimeToMergeByAuthor returns created-to-merged percentiles per author for PRs merged
+// within [from, to), optionally limited to one repository
+func (r *prRepository) GetTimeToMergeByAuthor(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(ctx, "author_id", from, to, repository)
+}
+
+// GetTimeToMergeByRepository returns created-to-merged percentiles per repository for PRs merged
+// within [from, to), optionally limited to one repository
+func (r *prRepository) GetTimeToMergeByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(ctx, "COALESCE(repository, '')", from, to, repository)
+}
+
+// GetTimeToMergeByWeek returns created-to-merged percentiles per merge week (keyed by
+// the Monday the week starts on, YYYY-MM-DD) for PRs merged within [from, to),
+// optionally limited to one repository
+func (r *prRepository) GetTimeToMergeByWeek(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error) {
+	return r.timeToMerge(ctx, "to_char(date_trunc('week', merged_at), 'YYYY-MM-DD')", from, to, repository)
+}
+
+// GetAuthorStatsByAuthor returns creation, merge and review stats of PRs created
+// within [from, to) grouped by author, optionally limited to one repository
+func (r *prRepository) GetAuthorStatsByAuthor(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error) {
+	return r.authorStats(ctx, "pr.author_id", from, to, repository)
+}
+
+// GetAuthorStatsByTeam returns creation, merge and review stats of PRs created
+// within [from, to) grouped by the PR's team, optionally limited to one repository
+func (r *prRepository) GetAuthorStatsByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error) {
+	return r.authorStats(ctx, "COALESCE(pr.team_name, '')", from, to, repository)
+}
+
+// GetAuthorStatsByRepository returns creation, merge and review stats of PRs created
+// within [from, to) grouped by repository, optionally limited to one repository
+func (r *prRepository) GetAuthorStatsByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error) {
+	return r.authorStats(ctx, "COALESCE(pr.repository, '')", from, to, repository)
+}
+
+// authorStats aggregates PRs grouped by keyExpr, which must be a trusted SQL expression
+func (r *prRepository) authorStats(
+	ctx context.Context,
+	keyExpr string,
+	from, to time.Time,
+	repository string,
+) ([]domain.AuthorStats, error) {
+	query := `
+		WITH prs AS (
+			SELECT ` + keyExpr + ` AS key, pr.status, pr.created_at,
+				COUNT(rev.user_id) AS reviewers,
+				MIN(rev.first_action_at) AS first_action_at
+			FROM pull_requests pr
+			LEFT JOIN pr_reviewers rev ON rev.pull_request_id = pr.pull_request_id
+			WHERE pr.created_at >= $1 AND pr.created_at < $2
+				AND ($3 = '' OR pr.repository = $3)
+			GROUP BY pr.pull_request_id
+		)
+		SELECT key,
+			COUNT(*) AS prs_created,
+			COUNT(*) FILTER (WHERE status = 'MERGED') AS prs_merged,
+			AVG(reviewers)::float8 AS avg_reviewers,
+			AVG(EXTRACT(EPOCH FROM first_action_at - created_at))::float8 AS avg_review_wait_seconds
+		FROM prs
+		GROUP BY key
+		ORDER BY key
+	`
+	var stats []domain.AuthorStats
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &stats, query, from, to, repository); err != nil {
+		return nil, fmt.Errorf("failed to get author stats: %w", err)
+	}
+	return stats, nil
+}
+
+// timeToMerge aggregates merge latency grouped by keyExpr, which must be a trusted SQL expression
+func (r *prRepository) timeToMerge(
+	ctx context.Context,
+	keyExpr string,
+	from, to time.Time,
+	repository string,
+) ([]domain.LatencyStats, error) {
+	query := `
+		WITH latencies AS (
+			SELECT ` + keyExpr + ` AS key,
+				EXTRACT(EPOCH FROM merged_at - created_at)::float8 AS seconds
+			FROM pull_requests
+			WHERE status = 'MERGED' AND merged_at >= $1 AND merged_at < $2
+				AND created_at < $2
+				AND ($3 = '' OR repository = $3)
+		)
+		SELECT key, COUNT(*) AS count,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds) AS p50,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY seconds) AS p90,
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY seconds) AS p99
+		FROM latencies
+		GROUP BY key
+		ORDER BY key
+	`
+	var stats []domain.LatencyStats
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &stats, query, from, to, repository); err != nil {
+		return nil, fmt.Errorf("failed to get time to merge: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,161 @@
+package repository_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/repository"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+func TestClaimStaleReviews(t *testing.T) {
+	d := newTestDB(t)
+	ctx := context.Background()
+	d.addUsers(t, ctx, "u1", "u2")
+	if err := repository.NewTeamRepository(d.cm).CreateTeam(ctx, domain.NewTeam("backend", nil)); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	d.addPR(t, ctx, "pr-team", "u1", "backend", "u2")
+	// a PR created without a team has a NULL team_name
+	d.addPR(t, ctx, "pr-no-team", "u1", "", "u2")
+
+	prs := repository.NewPRRepository(d.cm)
+	now := time.Now()
+	reviews, err := prs.ClaimStaleReviews(ctx, now.Add(time.Minute), now, 10)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	teams := map[string]string{}
+	for _, r := range reviews {
+		teams[r.PullRequestID] = r.TeamName
+	}
+	if len(reviews) != 2 || teams["pr-team"] != "backend" || teams["pr-no-team"] != "" {
+		t.Fatalf("expected both reviews with their teams, got %+v", reviews)
+	}
+
+	// claimed reviews are not claimed again
+	reviews, err = prs.ClaimStaleReviews(ctx, now.Add(time.Minute), now, 10)
+	if err != nil || len(reviews) != 0 {
+		t.Fatalf("expected nothing left to claim, got %+v (%v)", reviews, err)
+	}
+}
+
+func TestClaimOverdueReviews(t *testing.T) {
+	d := newTestDB(t)
+	ctx := context.Background()
+	d.addUsers(t, ctx, "u1", "u2")
+	d.addPR(t, ctx, "pr-no-team", "u1", "", "u2")
+
+	prs := repository.NewPRRepository(d.cm)
+	now := time.Now()
+	// a stale reminder doesn't keep the review from being escalated
+	if _, err := prs.ClaimStaleReviews(ctx, now.Add(time.Minute), now, 10); err != nil {
+		t.Fatalf("claim stale: %v", err)
+	}
+	reviews, err := prs.ClaimOverdueReviews(ctx, now.Add(time.Minute), now, 10)
+	if err != nil {
+		t.Fatalf("claim overdue: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].UserID != "u2" || reviews[0].TeamName != "" {
+		t.Fatalf("expected the review of u2 without a team, got %+v", reviews)
+	}
+
+	reviews, err = prs.ClaimOverdueReviews(ctx, now.Add(time.Minute), now, 10)
+	if err != nil || len(reviews) != 0 {
+		t.Fatalf("expected nothing left to claim, got %+v (%v)", reviews, err)
+	}
+}
+
+func TestGetPRForUpdateSerializesTransactions(t *testing.T) {
+	d := newTestDB(t)
+	ctx := context.Background()
+	d.addUsers(t, ctx, "u1", "u2", "u3")
+	d.addPR(t, ctx, "pr-1", "u1", "", "u2")
+	prs := repository.NewPRRepository(d.cm)
+
+	// The first transaction locks the PR and replaces its reviewer once the
+	// second one is waiting for the lock, as a reassignment does
+	locked := make(chan int32)
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- d.cm.Do(ctx, func(txCtx context.Context) error {
+			if _, err := prs.GetPRForUpdate(txCtx, "pr-1"); err != nil {
+				return err
+			}
+			var pid int32
+			if err := pgxscan.Get(txCtx, d.cm.Get(txCtx), &pid, `SELECT pg_backend_pid()`); err != nil {
+				return err
+			}
+			locked <- pid
+			<-release
+			if err := prs.RemoveReviewer(txCtx, "pr-1", "u2"); err != nil {
+				return err
+			}
+			return prs.AddReviewer(txCtx, "pr-1", "u3")
+		})
+	}()
+	var pid int32
+	select {
+	case pid = <-locked:
+	case err := <-first:
+		t.Fatalf("first transaction: %v", err)
+	}
+
+	type read struct {
+		pr  domain.PullRequest
+		err error
+	}
+	second := make(chan read, 1)
+	go func() {
+		var got read
+		got.err = d.cm.Do(ctx, func(txCtx context.Context) error {
+			var err error
+			got.pr, err = prs.GetPRForUpdate(txCtx, "pr-1")
+			return err
+		})
+		second <- got
+	}()
+	waitUntilBlocked(t, d, pid)
+	select {
+	case got := <-second:
+		t.Fatalf("second transaction read the locked PR: %+v (%v)", got.pr, got.err)
+	default:
+	}
+
+	close(release)
+	if err := <-first; err != nil {
+		t.Fatalf("first transaction: %v", err)
+	}
+	got := <-second
+	if got.err != nil {
+		t.Fatalf("second transaction: %v", got.err)
+	}
+	// The reviewers are read after the lock is taken; reading them before the
+	// first commit would let two reassignments pick the same replacement
+	if !slices.Equal(got.pr.AssignedReviewers, []string{"u3"}) {
+		t.Fatalf("expected the second transaction to see the committed reviewers [u3], got %v", got.pr.AssignedReviewers)
+	}
+}
+
+// waitUntilBlocked waits until a session waits for a lock held by the
+// backend pid
+func waitUntilBlocked(t *testing.T, d *testDB, pid int32) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	query := `SELECT EXISTS (SELECT 1 FROM pg_stat_activity WHERE $1::int = ANY(pg_blocking_pids(pid)))`
+	for {
+		var blocked bool
+		if err := pgxscan.Get(ctx, d.pool, &blocked, query, pid); err != nil {
+			t.Fatalf("no session blocked on backend %d: %v", pid, err)
+		}
+		if blocked {
+			return
+		}
+	}
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"pr-service/internal/db"
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
 )
 
 // TeamRepository defines methods for team data access
@@ -12,29 +17,121 @@ type TeamRepository interface {
 	CreateTeam(ctx context.Context, team domain.Team) error
 	GetTeam(ctx context.Context, teamName string) (domain.Team, error)
 	TeamExists(ctx context.Context, teamName string) (bool, error)
+	DeleteTeam(ctx context.Context, teamName string) error
+	ListDeletedTeams(ctx context.Context, page pagination.Page) ([]domain.Team, int, error)
+	RestoreTeam(ctx context.Context, teamName string) error
+	ListTeams(ctx context.Context, limit, offset int) ([]domain.TeamSummary, int, error)
+	RenameTeam(ctx context.Context, oldName, newName string) error
+	SetParentTeam(ctx context.Context, teamName, parentTeamName string) error
+	GetAncestorTeamNames(ctx context.Context, teamName string) ([]string, error)
+	GetChildTeamNames(ctx context.Context, teamName string) ([]string, error)
+	GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error)
+	SetTeamSettings(ctx context.Context, settings domain.TeamSettings) error
 }
 
 // UserRepository defines methods for user data access
 type UserRepository interface {
 	CreateOrUpdateUser(ctx context.Context, user domain.User) error
+	CreateOrUpdateUsers(ctx context.Context, users []domain.User) error
+	AddTeamMember(ctx context.Context, teamName, userID string) error
+	AddTeamMembers(ctx context.Context, teamName string, userIDs []string) error
+	RemoveTeamMember(ctx context.Context, teamName, userID string) error
 	UpdateUser(ctx context.Context, user domain.User) error
 	GetUser(ctx context.Context, userID string) (domain.User, error)
 	GetTeamMembers(ctx context.Context, teamName string) ([]domain.User, error)
+	GetTeamTreeMembers(ctx context.Context, teamName string) ([]domain.User, error)
 	DeactivateUsers(ctx context.Context, teamName string, userIDs []string) error
+	ActivateUsers(ctx context.Context, teamName string, userIDs []string) error
+	MoveTeamMembers(ctx context.Context, fromTeam, toTeam string) error
+	SoftDeleteUser(ctx context.Context, userID string) error
+	ListDeletedUsers(ctx context.Context, page pagination.Page) ([]domain.User, int, error)
+	RestoreUser(ctx context.Context, userID string) error
+	TouchLastSeen(ctx context.Context, userID string, at time.Time) error
+	ListDormantUsers(ctx context.Context, seenBefore time.Time, limit, offset int) ([]domain.User, int, error)
 }
 
 type PRRepository interface {
 	CreatePR(ctx context.Context, pr domain.PullRequest) error
 	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
+	GetPRForUpdate(ctx context.Context, prID string) (domain.PullRequest, error)
 	UpdatePR(ctx context.Context, pr domain.PullRequest) error
 	AssignReviewers(ctx context.Context, prID string, reviewers []string) error
 	RemoveReviewer(ctx context.Context, prID string, userID string) error
 	AddReviewer(ctx context.Context, prID string, userID string) error
 	GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	GetPendingReviewsByReviewer(ctx context.Context, userID string) ([]domain.ReviewAssignment, error)
+	GetPendingReviewsByReviewers(ctx context.Context, userIDs []string) ([]domain.ReviewAssignment, error)
+	ListPRs(ctx context.Context, filter domain.PRFilter, page pagination.Page) ([]domain.PullRequest, int, error)
 	PRExists(ctx context.Context, prID string) (bool, error)
-	GetAssignmentStatsByUser(ctx context.Context) (map[string]int, error)
-	GetAssignmentStatsByPR(ctx context.Context) (map[string]int, error)
-	GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error)
+	DeletePR(ctx context.Context, prID string) error
+	ArchiveMergedPRs(ctx context.Context, mergedBefore time.Time, limit int) (int, error)
+	DeleteMergedPRs(ctx context.Context, mergedBefore time.Time, limit int) (int, error)
+	GetAssignmentStatsByUser(ctx context.Context, from, to time.Time, sort domain.StatsSort, limit, offset int) ([]domain.KeyCount, int, error)
+	GetAssignmentStatsByPR(ctx context.Context, from, to time.Time, sort domain.StatsSort, limit, offset int) ([]domain.KeyCount, int, error)
+	GetAssignmentStatsByRole(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetAssignmentStatsByTeam(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetReviewerLoads(ctx context.Context, from, to time.Time) ([]domain.ReviewerLoad, error)
+	GetReviewPairs(ctx context.Context, from, to time.Time, teamName string) ([]domain.ReviewPair, error)
+	GetReassignmentStatsByUser(ctx context.Context, from, to time.Time) ([]domain.ReassignmentStats, error)
+	GetReassignmentStatsByTeam(ctx context.Context, from, to time.Time) ([]domain.ReassignmentStats, error)
+	GetReviewSLA(ctx context.Context, from, to, now time.Time, sla time.Duration) ([]domain.ReviewSLA, error)
+	GetOpenReviewCounts(ctx context.Context) ([]domain.ReviewerWorkload, error)
+	GetOpenPRAging(ctx context.Context, now time.Time) ([]domain.PRAging, error)
+	ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error
+	RecordReassignments(ctx context.Context, reassignments []domain.Reassignment) error
+	GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error)
+	GetOpenPRIDsByTeam(ctx context.Context, teamName string) ([]string, error)
+	MovePRsToTeam(ctx context.Context, fromTeam, toTeam string) error
+	RecordReviewerAction(ctx context.Context, prID, userID string, at time.Time) (time.Time, error)
+	GetTimeToFirstReviewByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToFirstReviewByUser(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToFirstReviewByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetAuthorStatsByAuthor(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error)
+	GetAuthorStatsByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error)
+	GetAuthorStatsByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error)
+	GetTimeToMergeByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToMergeByAuthor(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToMergeByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToMergeByWeek(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	ClaimStaleReviews(ctx context.Context, assignedBefore, now time.Time, limit int) ([]domain.StaleReview, error)
+	ClaimOverdueReviews(ctx context.Context, assignedBefore, now time.Time, limit int) ([]domain.StaleReview, error)
+}
+
+// ScheduledChangeRepository defines methods for deferred activity changes
+type ScheduledChangeRepository interface {
+	CreateScheduledChange(ctx context.Context, change domain.ScheduledChange) (domain.ScheduledChange, error)
+	ClaimDueScheduledChanges(ctx context.Context, now time.Time, limit int) ([]domain.ScheduledChange, error)
+	CompleteScheduledChange(ctx context.Context, id int64, status domain.ScheduledChangeStatus, errMsg string) error
+}
+
+// AuditRepository defines methods for the membership audit log
+type AuditRepository interface {
+	RecordMembershipEvents(ctx context.Context, events []domain.MembershipEvent) error
+	ListMembershipEvents(ctx context.Context, teamName string, limit, offset int) ([]domain.MembershipEvent, int, error)
+}
+
+// RollupRepository defines methods for per-day stats aggregates
+type RollupRepository interface {
+	LastRolledUpDay(ctx context.Context) (time.Time, error)
+	RollUpDay(ctx context.Context, day time.Time) error
+	GetDailyStats(ctx context.Context, from, to time.Time, teamName string) ([]domain.DailyStats, error)
+}
+
+// PartitionRepository defines methods for the monthly partitions of pull_requests
+type PartitionRepository interface {
+	CreatePRPartitions(ctx context.Context, from, through time.Time) (int, error)
+}
+
+// OrgRepository defines methods for the organizations with data
+type OrgRepository interface {
+	ListOrgs(ctx context.Context) ([]string, error)
+}
+
+// ExportRepository defines methods for full data dumps and their restore
+type ExportRepository interface {
+	ExportData(ctx context.Context) (domain.DataExport, error)
+	HasData(ctx context.Context) (bool, error)
+	ImportData(ctx context.Context, data domain.DataExport) error
 }
 
 type BaseRepository struct {
@@ -48,3 +145,49 @@ func NewBaseRepository(cm db.EngineFactory) BaseRepository {
 func (r *BaseRepository) Engine(ctx context.Context) db.Engine {
 	return r.cm.Get(ctx)
 }
+
+// ReadEngine returns the engine for queries that don't write, which may be
+// served by the read replica
+func (r *BaseRepository) ReadEngine(ctx context.Context) db.Engine {
+	return r.cm.GetReadOnly(ctx)
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// WebhookRepository defines methods for outbound webhook subscriptions and their delivery log
+type WebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	EnqueueWebhookDeliveries(ctx context.Context, eventType domain.EventType, payload []byte, now time.Time) (int, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status domain.WebhookDeliveryStatus, limit, offset int) ([]domain.WebhookDelivery, int, error)
+}
+
+// TeamTokenRepository defines methods for bearer tokens restricted to one team
+type TeamTokenRepository interface {
+	CreateTeamToken(ctx context.Context, token domain.TeamToken, hash string) (domain.TeamToken, error)
+	GetTeamToken(ctx context.Context, id int64) (domain.TeamToken, error)
+	GetTeamTokenByHash(ctx context.Context, hash string) (domain.TeamToken, error)
+	ListTeamTokens(ctx context.Context, teamName string, page pagination.Page) ([]domain.TeamToken, int, error)
+	DeleteTeamToken(ctx context.Context, id int64) error
+}
+
+// AuditLogRepository defines methods for the log of state-changing API requests
+type AuditLogRepository interface {
+	RecordAuditEntry(ctx context.Context, entry domain.AuditEntry) error
+	ListAuditEntries(ctx context.Context, filter domain.AuditFilter, page pagination.Page) ([]domain.AuditEntry, int, error)
+}
+
+// OutboxRepository defines methods for the event outbox relayed to the message broker
+type OutboxRepository interface {
+	AppendOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error
+	LockUnpublishedOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+	MarkOutboxMessagesPublished(ctx context.Context, ids []int64, publishedAt time.Time) error
+	ListOutboxMessages(ctx context.Context, after domain.EventCursor, limit int) ([]domain.OutboxMessage, error)
+}
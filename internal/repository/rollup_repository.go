@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type rollupRepository struct {
+	BaseRepository
+}
+
+// NewRollupRepository creates a new daily stats rollup repository
+func NewRollupRepository(cm db.EngineFactory) RollupRepository {
+	return &rollupRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// LastRolledUpDay returns the latest day that has been rolled up, or a zero time if none
+func (r *rollupRepository) LastRolledUpDay(ctx context.Context) (time.Time, error) {
+	query := `
+		SELECT MAX(day)
+		FROM stats_rollups
+	`
+	var day *time.Time
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &day, query); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last rolled up day: %w", err)
+	}
+	if day == nil {
+		return time.Time{}, nil
+	}
+	return *day, nil
+}
+
+// RollUpDay recomputes the aggregates of a day from raw data and marks the day as rolled up.
+// Running it again for the same day replaces the previous aggregates.
+func (r *rollupRepository) RollUpDay(ctx context.Context, day time.Time) error {
+	query := `
+		DELETE FROM daily_team_stats
+		WHERE day = $1::date
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, day); err != nil {
+		return fmt.Errorf("failed to roll up day: %w", err)
+	}
+
+	query = `
+		INSERT INTO daily_team_stats (day, team_name, assignments, merges, reassignments)
+		SELECT $1::date, team_name, SUM(assignments), SUM(merges), SUM(reassignments)
+		FROM (
+			SELECT COALESCE(pr.team_name, '') AS team_name,
+				COUNT(*) AS assignments, 0 AS merges, 0 AS reassignments
+			FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			WHERE rev.assigned_at >= $1::date AND rev.assigned_at < $1::date + 1
+				AND pr.created_at < $1::date + 1
+			GROUP BY COALESCE(pr.team_name, '')
+			UNION ALL
+			SELECT COALESCE(team_name, ''), 0, COUNT(*), 0
+			FROM pull_requests
+			WHERE merged_at >= $1::date AND merged_at < $1::date + 1
+				AND created_at < $1::date + 1
+			GROUP BY COALESCE(team_name, '')
+			UNION ALL
+			SELECT COALESCE(pr.team_name, ''), 0, 0, COUNT(*)
+			FROM reviewer_reassignments ra
+			LEFT JOIN pull_requests pr ON pr.pull_request_id = ra.pull_request_id AND pr.created_at < $1::date + 1
+			WHERE ra.created_at >= $1::date AND ra.created_at < $1::date + 1
+			GROUP BY COALESCE(pr.team_name, '')
+		) activity
+		GROUP BY team_name
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, day); err != nil {
+		return fmt.Errorf("failed to roll up day: %w", err)
+	}
+
+	query = `
+		INSERT INTO stats_rollups (day, rolled_up_at)
+		VALUES ($1::date, NOW())
+		ON CONFLICT (day, org_id) DO UPDATE SET rolled_up_at = EXCLUDED.rolled_up_at
+	`
+	if _, err := r.Engine(ctx).Exec(ctx, query, day); err != nil {
+		return fmt.Errorf("failed to mark day as rolled up: %w", err)
+	}
+	return nil
+}
+
+// GetDailyStats returns rolled up days within [from, to), optionally for one team
+func (r *rollupRepository) GetDailyStats(ctx context.Context, from, to time.Time, teamName string) ([]domain.DailyStats, error) {
+	query := `
+		SELECT day, team_name, assignments, merges, reassignments
+		FROM daily_team_stats
+		WHERE day >= $1::date AND day < $2::date AND ($3 = '' OR team_name = $3)
+		ORDER BY day, team_name
+	`
+	var stats []domain.DailyStats
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &stats, query, from, to, teamName); err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	return stats, nil
+}
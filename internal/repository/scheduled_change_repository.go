@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type scheduledChangeRepository struct {
+	BaseRepository
+}
+
+// NewScheduledChangeRepository creates a new scheduled change repository
+func NewScheduledChangeRepository(cm db.EngineFactory) ScheduledChangeRepository {
+	return &scheduledChangeRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// CreateScheduledChange stores a pending change and returns it with its ID
+func (r *scheduledChangeRepository) CreateScheduledChange(ctx context.Context, change domain.ScheduledChange) (domain.ScheduledChange, error) {
+	query := `
+		INSERT INTO scheduled_status_changes (kind, team_name, user_ids, is_active, effective_at, status, created_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	err := pgxscan.Get(ctx, r.Engine(ctx), &change.ID, query,
+		change.Kind, change.TeamName, change.UserIDs, change.IsActive, change.EffectiveAt, change.Status, change.CreatedAt)
+	if err != nil {
+		return domain.ScheduledChange{}, fmt.Errorf("failed to create scheduled change: %w", err)
+	}
+	return change, nil
+}
+
+// ClaimDueScheduledChanges marks up to limit pending changes due at now as processing and returns them.
+// SKIP LOCKED lets several instances poll the table without applying a change twice.
+func (r *scheduledChangeRepository) ClaimDueScheduledChanges(ctx context.Context, now time.Time, limit int) ([]domain.ScheduledChange, error) {
+	query := `
+		UPDATE scheduled_status_changes
+		SET status = 'PROCESSING'
+		WHERE id IN (
+			SELECT id
+			FROM scheduled_status_changes
+			WHERE status = 'PENDING' AND effective_at <= $1
+			ORDER BY effective_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, kind, COALESCE(team_name, '') AS team_name, user_ids, is_active,
+			effective_at, status, COALESCE(error, '') AS error, created_at, applied_at
+	`
+	var changes []domain.ScheduledChange
+	if err := pgxscan.Select(ctx, r.Engine(ctx), &changes, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim scheduled changes: %w", err)
+	}
+	return changes, nil
+}
+
+// CompleteScheduledChange records the outcome of a processed change
+func (r *scheduledChangeRepository) CompleteScheduledChange(ctx context.Context, id int64, status domain.ScheduledChangeStatus, errMsg string) error {
+	query := `
+		UPDATE scheduled_status_changes
+		SET status = $2, error = NULLIF($3, ''), applied_at = NOW()
+		WHERE id = $1
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, id, status, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to complete scheduled change: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
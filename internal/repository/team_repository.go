@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"pr-service/internal/db"
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 )
@@ -21,16 +23,34 @@ func NewTeamRepository(cm db.EngineFactory) TeamRepository {
 	}
 }
 
-// CreateTeam creates a new team
+// CreateTeam creates a new team. A deleted team of the same name is replaced,
+// keeping its PRs but not its settings. The organization of the team is
+// recorded when it has no teams yet.
 func (r *teamRepository) CreateTeam(ctx context.Context, team domain.Team) error {
 	query := `
-		INSERT INTO teams (team_name, created_at, updated_at)
-		VALUES ($1, $2, $3)
+		WITH org AS (
+			INSERT INTO organizations (org_id)
+			VALUES (current_org_id())
+			ON CONFLICT DO NOTHING
+		)
+		INSERT INTO teams (team_name, parent_team_name, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4)
+		ON CONFLICT (team_name, org_id) DO UPDATE SET
+			parent_team_name = EXCLUDED.parent_team_name,
+			notification_channel_type = NULL,
+			notification_channel_url = NULL,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = NULL
+		WHERE teams.deleted_at IS NOT NULL
 	`
-	_, err := r.Engine(ctx).Exec(ctx, query, team.TeamName, team.CreatedAt, team.UpdatedAt)
+	tag, err := r.Engine(ctx).Exec(ctx, query, team.TeamName, team.ParentTeamName, team.CreatedAt, team.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create team: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTeamExists
+	}
 	return nil
 }
 
@@ -39,11 +59,11 @@ func (r *teamRepository) GetTeam(ctx context.Context, teamName string) (domain.T
 	// First, check if team exists
 	var team domain.Team
 	teamQuery := `
-		SELECT team_name, created_at, updated_at
+		SELECT team_name, COALESCE(parent_team_name, '') AS parent_team_name, created_at, updated_at
 		FROM teams
-		WHERE team_name = $1
+		WHERE team_name = $1 AND deleted_at IS NULL
 	`
-	err := pgxscan.Get(ctx, r.Engine(ctx), &team, teamQuery, teamName)
+	err := pgxscan.Get(ctx, r.ReadEngine(ctx), &team, teamQuery, teamName)
 	if err != nil {
 		if pgxscan.NotFound(err) {
 			return domain.Team{}, domain.ErrNotFound
@@ -53,13 +73,15 @@ func (r *teamRepository) GetTeam(ctx context.Context, teamName string) (domain.T
 
 	// Get team members
 	membersQuery := `
-		SELECT user_id, username, team_name, is_active, created_at, updated_at
-		FROM users
-		WHERE team_name = $1
-		ORDER BY username
+		SELECT u.user_id, u.username, tm.team_name,` + userTeamsColumn + `,
+			u.is_active, u.role, u.created_at, u.updated_at, u.last_seen_at
+		FROM team_members tm
+		INNER JOIN users u ON u.user_id = tm.user_id
+		WHERE tm.team_name = $1 AND u.deleted_at IS NULL
+		ORDER BY u.username
 	`
 	var members []domain.User
-	err = pgxscan.Select(ctx, r.Engine(ctx), &members, membersQuery, teamName)
+	err = pgxscan.Select(ctx, r.ReadEngine(ctx), &members, membersQuery, teamName)
 	if err != nil {
 		return domain.Team{}, fmt.Errorf("failed to get team members: %w", err)
 	}
@@ -71,12 +93,259 @@ func (r *teamRepository) GetTeam(ctx context.Context, teamName string) (domain.T
 // TeamExists checks if a team exists
 func (r *teamRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	query := `
-		SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)
+		SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1 AND deleted_at IS NULL)
 	`
 	var exists bool
-	err := pgxscan.Get(ctx, r.Engine(ctx), &exists, query, teamName)
+	err := pgxscan.Get(ctx, r.ReadEngine(ctx), &exists, query, teamName)
 	if err != nil {
 		return false, fmt.Errorf("failed to check team existence: %w", err)
 	}
 	return exists, nil
 }
+
+// DeleteTeam marks a team as deleted. The row is kept so that its PRs keep
+// their team in stats; remaining memberships and team tokens are dropped and
+// sub-teams become root teams, as if the row was gone.
+func (r *teamRepository) DeleteTeam(ctx context.Context, teamName string) error {
+	query := `
+		WITH deleted AS (
+			UPDATE teams
+			SET deleted_at = NOW(), updated_at = NOW()
+			WHERE team_name = $1 AND deleted_at IS NULL
+			RETURNING team_name
+		), members AS (
+			DELETE FROM team_members
+			WHERE team_name IN (SELECT team_name FROM deleted)
+		), tokens AS (
+			DELETE FROM team_tokens
+			WHERE team_name IN (SELECT team_name FROM deleted)
+		), children AS (
+			UPDATE teams
+			SET parent_team_name = NULL, updated_at = NOW()
+			WHERE parent_team_name IN (SELECT team_name FROM deleted)
+		)
+		SELECT COUNT(*) FROM deleted
+	`
+	var deleted int
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &deleted, query, teamName); err != nil {
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+	if deleted == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListDeletedTeams returns up to page.Fetch() deleted teams, ordered by
+// deletion time, and the total number of deleted teams
+func (r *teamRepository) ListDeletedTeams(ctx context.Context, page pagination.Page) ([]domain.Team, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*) FROM teams WHERE deleted_at IS NOT NULL
+	`
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted teams: %w", err)
+	}
+
+	var afterTime *time.Time
+	var afterName string
+	if page.After != nil {
+		t, err := page.After.Time()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list deleted teams: %w", err)
+		}
+		afterTime, afterName = &t, page.After.ID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT team_name, COALESCE(parent_team_name, '') AS parent_team_name, created_at, updated_at, deleted_at
+		FROM teams
+		WHERE deleted_at IS NOT NULL
+			AND ($3::timestamptz IS NULL OR deleted_at %[1]s $3
+				OR (deleted_at = $3 AND team_name > $4))
+		ORDER BY deleted_at %[2]s, team_name
+		LIMIT $1 OFFSET $2
+	`, page.Order.After(), page.Order.SQL())
+	var teams []domain.Team
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &teams, query, page.Fetch(), page.Offset, afterTime, afterName); err != nil {
+		return nil, 0, fmt.Errorf("failed to list deleted teams: %w", err)
+	}
+	return teams, total, nil
+}
+
+// RestoreTeam clears the deletion of a team. It comes back without members,
+// which were dropped when it was deleted.
+func (r *teamRepository) RestoreTeam(ctx context.Context, teamName string) error {
+	query := `
+		UPDATE teams
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE team_name = $1 AND deleted_at IS NOT NULL
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, teamName)
+	if err != nil {
+		return fmt.Errorf("failed to restore team: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListTeams returns a page of teams with member counts and the total number of teams
+func (r *teamRepository) ListTeams(ctx context.Context, limit, offset int) ([]domain.TeamSummary, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*) FROM teams WHERE deleted_at IS NULL
+	`
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery); err != nil {
+		return nil, 0, fmt.Errorf("failed to count teams: %w", err)
+	}
+
+	query := `
+		SELECT t.team_name,
+			COUNT(u.user_id) AS member_count,
+			COUNT(u.user_id) FILTER (WHERE u.is_active) AS active_member_count,
+			t.created_at
+		FROM teams t
+		LEFT JOIN team_members tm ON tm.team_name = t.team_name
+		LEFT JOIN users u ON u.user_id = tm.user_id AND u.deleted_at IS NULL
+		WHERE t.deleted_at IS NULL
+		GROUP BY t.team_name, t.created_at
+		ORDER BY t.team_name
+		LIMIT $1 OFFSET $2
+	`
+	var teams []domain.TeamSummary
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &teams, query, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	return teams, total, nil
+}
+
+// RenameTeam changes a team's name. Memberships and PRs follow via ON UPDATE
+// CASCADE. The name of a deleted team is still taken.
+func (r *teamRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	query := `
+		UPDATE teams
+		SET team_name = $2, updated_at = NOW()
+		WHERE team_name = $1 AND deleted_at IS NULL
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, oldName, newName)
+	if isUniqueViolation(err) {
+		return domain.ErrTeamExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rename team: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// SetParentTeam attaches a team to a parent team; an empty parent makes it a root team
+func (r *teamRepository) SetParentTeam(ctx context.Context, teamName, parentTeamName string) error {
+	query := `
+		UPDATE teams
+		SET parent_team_name = NULLIF($2, ''), updated_at = NOW()
+		WHERE team_name = $1 AND deleted_at IS NULL
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, teamName, parentTeamName)
+	if err != nil {
+		return fmt.Errorf("failed to set parent team: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// GetTeamSettings returns the settings of a team
+func (r *teamRepository) GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	query := `
+		SELECT team_name, COALESCE(notification_channel_type, '') AS channel_type,
+			COALESCE(notification_channel_url, '') AS channel_url
+		FROM teams
+		WHERE team_name = $1 AND deleted_at IS NULL
+	`
+	var row struct {
+		TeamName    string
+		ChannelType string
+		ChannelURL  string
+	}
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &row, query, teamName); err != nil {
+		if pgxscan.NotFound(err) {
+			return domain.TeamSettings{}, domain.ErrNotFound
+		}
+		return domain.TeamSettings{}, fmt.Errorf("failed to get team settings: %w", err)
+	}
+
+	settings := domain.TeamSettings{TeamName: row.TeamName}
+	if row.ChannelType != "" {
+		settings.NotificationChannel = &domain.NotificationChannel{
+			Type:       domain.NotificationChannelType(row.ChannelType),
+			WebhookURL: row.ChannelURL,
+		}
+	}
+	return settings, nil
+}
+
+// SetTeamSettings replaces the settings of a team
+func (r *teamRepository) SetTeamSettings(ctx context.Context, settings domain.TeamSettings) error {
+	var channelType, channelURL string
+	if ch := settings.NotificationChannel; ch != nil {
+		channelType, channelURL = string(ch.Type), ch.WebhookURL
+	}
+	query := `
+		UPDATE teams
+		SET notification_channel_type = NULLIF($2, ''), notification_channel_url = NULLIF($3, ''), updated_at = NOW()
+		WHERE team_name = $1 AND deleted_at IS NULL
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, settings.TeamName, channelType, channelURL)
+	if err != nil {
+		return fmt.Errorf("failed to set team settings: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// GetAncestorTeamNames returns the chain of parents of a team, nearest first
+func (r *teamRepository) GetAncestorTeamNames(ctx context.Context, teamName string) ([]string, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_team_name AS team_name, 1 AS depth
+			FROM teams
+			WHERE team_name = $1 AND parent_team_name IS NOT NULL
+			UNION
+			SELECT t.parent_team_name, a.depth + 1
+			FROM teams t
+			INNER JOIN ancestors a ON t.team_name = a.team_name
+			WHERE t.parent_team_name IS NOT NULL AND a.depth < 100
+		)
+		SELECT team_name
+		FROM ancestors
+		ORDER BY depth
+	`
+	var names []string
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &names, query, teamName); err != nil {
+		return nil, fmt.Errorf("failed to get ancestor teams: %w", err)
+	}
+	return names, nil
+}
+
+// GetChildTeamNames returns the direct sub-teams of a team
+func (r *teamRepository) GetChildTeamNames(ctx context.Context, teamName string) ([]string, error) {
+	query := `
+		SELECT team_name
+		FROM teams
+		WHERE parent_team_name = $1 AND deleted_at IS NULL
+		ORDER BY team_name
+	`
+	var names []string
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &names, query, teamName); err != nil {
+		return nil, fmt.Errorf("failed to get child teams: %w", err)
+	}
+	return names, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type teamTokenRepository struct {
+	BaseRepository
+}
+
+// NewTeamTokenRepository creates a new team token repository
+func NewTeamTokenRepository(cm db.EngineFactory) TeamTokenRepository {
+	return &teamTokenRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// CreateTeamToken stores a token under the hash of its secret and returns it
+// with its ID and organization
+func (r *teamTokenRepository) CreateTeamToken(ctx context.Context, token domain.TeamToken, hash string) (domain.TeamToken, error) {
+	query := `
+		INSERT INTO team_tokens (team_name, name, token_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, org_id, team_name, name, created_at, expires_at
+	`
+	err := pgxscan.Get(ctx, r.Engine(ctx), &token, query, token.TeamName, token.Name, hash, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return domain.TeamToken{}, fmt.Errorf("failed to create team token: %w", err)
+	}
+	return token, nil
+}
+
+// GetTeamToken returns a token by ID
+func (r *teamTokenRepository) GetTeamToken(ctx context.Context, id int64) (domain.TeamToken, error) {
+	query := `
+		SELECT id, org_id, team_name, name, created_at, expires_at
+		FROM team_tokens
+		WHERE id = $1
+	`
+	var token domain.TeamToken
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &token, query, id); err != nil {
+		if pgxscan.NotFound(err) {
+			return domain.TeamToken{}, domain.ErrNotFound
+		}
+		return domain.TeamToken{}, fmt.Errorf("failed to get team token: %w", err)
+	}
+	return token, nil
+}
+
+// GetTeamTokenByHash returns the token whose secret has hash, whichever
+// organization it belongs to, since the caller's organization is only known
+// from the token
+func (r *teamTokenRepository) GetTeamTokenByHash(ctx context.Context, hash string) (domain.TeamToken, error) {
+	ctx = db.AllOrgs(ctx)
+	query := `
+		SELECT id, org_id, team_name, name, created_at, expires_at
+		FROM team_tokens
+		WHERE token_hash = $1
+	`
+	var token domain.TeamToken
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &token, query, hash); err != nil {
+		if pgxscan.NotFound(err) {
+			return domain.TeamToken{}, domain.ErrNotFound
+		}
+		return domain.TeamToken{}, fmt.Errorf("failed to get team token: %w", err)
+	}
+	return token, nil
+}
+
+// ListTeamTokens returns up to page.Fetch() tokens of a team, ordered by ID,
+// which follows the order they were issued in, and the number of its tokens
+func (r *teamTokenRepository) ListTeamTokens(ctx context.Context, teamName string, page pagination.Page) ([]domain.TeamToken, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM team_tokens WHERE team_name = $1`
+	if err := pgxscan.Get(ctx, r.Engine(ctx), &total, countQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("failed to count team tokens: %w", err)
+	}
+
+	var afterID *int64
+	if page.After != nil {
+		id, err := strconv.ParseInt(page.After.ID, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list team tokens: %w", err)
+		}
+		afterID = &id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, org_id, team_name, name, created_at, expires_at
+		FROM team_tokens
+		WHERE team_name = $1
+			AND ($4::bigint IS NULL OR id %[1]s $4)
+		ORDER BY id %[2]s
+		LIMIT $2 OFFSET $3
+	`, page.Order.After(), page.Order.SQL())
+	var tokens []domain.TeamToken
+	if err := pgxscan.Select(ctx, r.Engine(ctx), &tokens, query, teamName, page.Fetch(), page.Offset, afterID); err != nil {
+		return nil, 0, fmt.Errorf("failed to list team tokens: %w", err)
+	}
+	return tokens, total, nil
+}
+
+// DeleteTeamToken revokes a token
+func (r *teamTokenRepository) DeleteTeamToken(ctx context.Context, id int64) error {
+	tag, err := r.Engine(ctx).Exec(ctx, `DELETE FROM team_tokens WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete team token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"pr-service/internal/db"
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 )
@@ -20,34 +22,139 @@ func NewUserRepository(cm db.EngineFactory) UserRepository {
 	}
 }
 
+// userTeamsColumn selects every team of the user aliased as u, earliest membership first
+const userTeamsColumn = `
+	COALESCE((
+		SELECT array_agg(m.team_name ORDER BY m.joined_at, m.team_name)
+		FROM team_members m
+		WHERE m.user_id = u.user_id
+	), '{}') AS teams`
+
 func (r *userRepository) CreateOrUpdateUser(ctx context.Context, user domain.User) error {
 	query := `
-		INSERT INTO users (user_id, username, team_name, is_active, created_at, updated_at)
+		INSERT INTO users (user_id, username, is_active, role, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (user_id) 
+		ON CONFLICT (user_id, org_id)
 		DO UPDATE SET
 			username = EXCLUDED.username,
-			team_name = EXCLUDED.team_name,
 			is_active = EXCLUDED.is_active,
-			updated_at = EXCLUDED.updated_at
+			role = EXCLUDED.role,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = NULL
 	`
 	_, err := r.Engine(ctx).Exec(ctx, query,
-		user.UserID, user.Username, user.TeamName, user.IsActive, user.CreatedAt, user.UpdatedAt)
+		user.UserID, user.Username, user.IsActive, user.Role, user.CreatedAt, user.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create or update user: %w", err)
 	}
 	return nil
 }
 
+// CreateOrUpdateUsers upserts users in one statement; when a user is listed
+// more than once, the last entry wins
+func (r *userRepository) CreateOrUpdateUsers(ctx context.Context, users []domain.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	last := make(map[string]int, len(users))
+	for i, user := range users {
+		last[user.UserID] = i
+	}
+	var (
+		userIDs    = make([]string, 0, len(last))
+		usernames  = make([]string, 0, len(last))
+		active     = make([]bool, 0, len(last))
+		roles      = make([]string, 0, len(last))
+		createdAts = make([]time.Time, 0, len(last))
+		updatedAts = make([]time.Time, 0, len(last))
+	)
+	for i, user := range users {
+		if last[user.UserID] != i {
+			continue
+		}
+		userIDs = append(userIDs, user.UserID)
+		usernames = append(usernames, user.Username)
+		active = append(active, user.IsActive)
+		roles = append(roles, string(user.Role))
+		createdAts = append(createdAts, user.CreatedAt)
+		updatedAts = append(updatedAts, user.UpdatedAt)
+	}
+
+	query := `
+		INSERT INTO users (user_id, username, is_active, role, created_at, updated_at)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::boolean[], $4::text[], $5::timestamp[], $6::timestamp[])
+		ON CONFLICT (user_id, org_id)
+		DO UPDATE SET
+			username = EXCLUDED.username,
+			is_active = EXCLUDED.is_active,
+			role = EXCLUDED.role,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = NULL
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, userIDs, usernames, active, roles, createdAts, updatedAts)
+	if err != nil {
+		return fmt.Errorf("failed to create or update users: %w", err)
+	}
+	return nil
+}
+
+// AddTeamMember adds a user to a team; existing memberships are left untouched
+func (r *userRepository) AddTeamMember(ctx context.Context, teamName, userID string) error {
+	query := `
+		INSERT INTO team_members (team_name, user_id, joined_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (team_name, user_id, org_id) DO NOTHING
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, teamName, userID)
+	if err != nil {
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+	return nil
+}
+
+// AddTeamMembers adds users to a team in one statement; existing memberships
+// are left untouched
+func (r *userRepository) AddTeamMembers(ctx context.Context, teamName string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO team_members (team_name, user_id, joined_at)
+		SELECT $1, user_id, NOW()
+		FROM unnest($2::text[]) AS m(user_id)
+		ON CONFLICT (team_name, user_id, org_id) DO NOTHING
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, teamName, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to add team members: %w", err)
+	}
+	return nil
+}
+
+// RemoveTeamMember removes a user from a team, keeping their other memberships
+func (r *userRepository) RemoveTeamMember(ctx context.Context, teamName, userID string) error {
+	query := `
+		DELETE FROM team_members
+		WHERE team_name = $1 AND user_id = $2
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, teamName, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove team member: %w", err)
+	}
+	return nil
+}
+
 // UpdateUser updates user information
 func (r *userRepository) UpdateUser(ctx context.Context, user domain.User) error {
 	query := `
 		UPDATE users
-		SET username = $2, team_name = $3, is_active = $4, updated_at = $5
-		WHERE user_id = $1
+		SET username = $2, is_active = $3, role = $4, updated_at = $5
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 	tag, err := r.Engine(ctx).Exec(ctx, query,
-		user.UserID, user.Username, user.TeamName, user.IsActive, user.UpdatedAt)
+		user.UserID, user.Username, user.IsActive, user.Role, user.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -57,14 +164,23 @@ func (r *userRepository) UpdateUser(ctx context.Context, user domain.User) error
 	return nil
 }
 
+// GetUser returns a user with all memberships; TeamName is the earliest joined team
 func (r *userRepository) GetUser(ctx context.Context, userID string) (domain.User, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active, created_at, updated_at
-		FROM users
-		WHERE user_id = $1
+		SELECT u.user_id, u.username,
+			COALESCE((
+				SELECT m.team_name
+				FROM team_members m
+				WHERE m.user_id = u.user_id
+				ORDER BY m.joined_at, m.team_name
+				LIMIT 1
+			), '') AS team_name,` + userTeamsColumn + `,
+			u.is_active, u.role, u.created_at, u.updated_at, u.last_seen_at
+		FROM users u
+		WHERE u.user_id = $1 AND u.deleted_at IS NULL
 	`
 	var user domain.User
-	err := pgxscan.Get(ctx, r.Engine(ctx), &user, query, userID)
+	err := pgxscan.Get(ctx, r.ReadEngine(ctx), &user, query, userID)
 	if err != nil {
 		if pgxscan.NotFound(err) {
 			return domain.User{}, domain.ErrNotFound
@@ -76,19 +192,54 @@ func (r *userRepository) GetUser(ctx context.Context, userID string) (domain.Use
 
 func (r *userRepository) GetTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active, created_at, updated_at
-		FROM users
-		WHERE team_name = $1
-		ORDER BY username
+		SELECT u.user_id, u.username, tm.team_name,` + userTeamsColumn + `,
+			u.is_active, u.role, u.created_at, u.updated_at, u.last_seen_at
+		FROM team_members tm
+		INNER JOIN users u ON u.user_id = tm.user_id
+		WHERE tm.team_name = $1 AND u.deleted_at IS NULL
+		ORDER BY u.username
 	`
 	var users []domain.User
-	err := pgxscan.Select(ctx, r.Engine(ctx), &users, query, teamName)
+	err := pgxscan.Select(ctx, r.ReadEngine(ctx), &users, query, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get team members: %w", err)
 	}
 	return users, nil
 }
 
+// GetTeamTreeMembers returns members of a team and of all its sub-teams.
+// A user in several teams of the tree is listed once.
+func (r *userRepository) GetTeamTreeMembers(ctx context.Context, teamName string) ([]domain.User, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT team_name
+			FROM teams
+			WHERE team_name = $1
+			UNION
+			SELECT t.team_name
+			FROM teams t
+			INNER JOIN tree ON t.parent_team_name = tree.team_name
+		)
+		SELECT * FROM (
+			SELECT DISTINCT ON (u.user_id)
+				u.user_id, u.username, tm.team_name,` + userTeamsColumn + `,
+				u.is_active, u.role, u.created_at, u.updated_at, u.last_seen_at
+			FROM team_members tm
+			INNER JOIN tree ON tm.team_name = tree.team_name
+			INNER JOIN users u ON u.user_id = tm.user_id
+			WHERE u.deleted_at IS NULL
+			ORDER BY u.user_id, (tm.team_name = $1) DESC, tm.team_name
+		) members
+		ORDER BY username
+	`
+	var users []domain.User
+	err := pgxscan.Select(ctx, r.ReadEngine(ctx), &users, query, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team tree members: %w", err)
+	}
+	return users, nil
+}
+
 // DeactivateUsers marks provided team members as inactive.
 func (r *userRepository) DeactivateUsers(ctx context.Context, teamName string, userIDs []string) error {
 	if len(userIDs) == 0 {
@@ -96,9 +247,10 @@ func (r *userRepository) DeactivateUsers(ctx context.Context, teamName string, u
 	}
 
 	query := `
-		UPDATE users
+		UPDATE users u
 		SET is_active = false, updated_at = NOW()
-		WHERE team_name = $1 AND user_id = ANY($2)
+		FROM team_members tm
+		WHERE tm.user_id = u.user_id AND tm.team_name = $1 AND u.user_id = ANY($2)
 	`
 	_, err := r.Engine(ctx).Exec(ctx, query, teamName, userIDs)
 	if err != nil {
@@ -106,3 +258,185 @@ func (r *userRepository) DeactivateUsers(ctx context.Context, teamName string, u
 	}
 	return nil
 }
+
+// ActivateUsers marks provided team members as active.
+func (r *userRepository) ActivateUsers(ctx context.Context, teamName string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE users u
+		SET is_active = true, updated_at = NOW()
+		FROM team_members tm
+		WHERE tm.user_id = u.user_id AND tm.team_name = $1 AND u.user_id = ANY($2) AND u.deleted_at IS NULL
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, teamName, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to activate users: %w", err)
+	}
+	return nil
+}
+
+// MoveTeamMembers reassigns every member of fromTeam to toTeam.
+// Members already in toTeam simply lose their fromTeam membership.
+func (r *userRepository) MoveTeamMembers(ctx context.Context, fromTeam, toTeam string) error {
+	query := `
+		INSERT INTO team_members (team_name, user_id, joined_at)
+		SELECT $2, user_id, joined_at
+		FROM team_members
+		WHERE team_name = $1
+		ON CONFLICT (team_name, user_id, org_id) DO NOTHING
+	`
+	_, err := r.Engine(ctx).Exec(ctx, query, fromTeam, toTeam)
+	if err != nil {
+		return fmt.Errorf("failed to move team members: %w", err)
+	}
+
+	query = `
+		DELETE FROM team_members
+		WHERE team_name = $1
+	`
+	_, err = r.Engine(ctx).Exec(ctx, query, fromTeam)
+	if err != nil {
+		return fmt.Errorf("failed to move team members: %w", err)
+	}
+	return nil
+}
+
+// SoftDeleteUser deactivates a user and marks the row as deleted.
+// The row is kept so that review history and stats stay intact.
+func (r *userRepository) SoftDeleteUser(ctx context.Context, userID string) error {
+	query := `
+		UPDATE users
+		SET is_active = false, deleted_at = NOW(), updated_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListDeletedUsers returns up to page.Fetch() soft-deleted users, ordered by
+// deletion time, and the total number of such users
+func (r *userRepository) ListDeletedUsers(ctx context.Context, page pagination.Page) ([]domain.User, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL
+	`
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted users: %w", err)
+	}
+
+	var afterTime *time.Time
+	var afterID string
+	if page.After != nil {
+		t, err := page.After.Time()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list deleted users: %w", err)
+		}
+		afterTime, afterID = &t, page.After.ID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT u.user_id, u.username,
+			COALESCE((
+				SELECT m.team_name
+				FROM team_members m
+				WHERE m.user_id = u.user_id
+				ORDER BY m.joined_at, m.team_name
+				LIMIT 1
+			), '') AS team_name,`+userTeamsColumn+`,
+			u.is_active, u.role, u.created_at, u.updated_at, u.deleted_at, u.last_seen_at
+		FROM users u
+		WHERE u.deleted_at IS NOT NULL
+			AND ($3::timestamptz IS NULL OR u.deleted_at %[1]s $3
+				OR (u.deleted_at = $3 AND u.user_id > $4))
+		ORDER BY u.deleted_at %[2]s, u.user_id
+		LIMIT $1 OFFSET $2
+	`, page.Order.After(), page.Order.SQL())
+	var users []domain.User
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &users, query, page.Fetch(), page.Offset, afterTime, afterID); err != nil {
+		return nil, 0, fmt.Errorf("failed to list deleted users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// RestoreUser clears the deletion of a user. The user stays inactive, with
+// the team memberships it had when it was deleted.
+func (r *userRepository) RestoreUser(ctx context.Context, userID string) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// TouchLastSeen records a heartbeat of a user
+func (r *userRepository) TouchLastSeen(ctx context.Context, userID string, at time.Time) error {
+	query := `
+		UPDATE users
+		SET last_seen_at = $2
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, userID, at)
+	if err != nil {
+		return fmt.Errorf("failed to touch last seen: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListDormantUsers returns a page of active users not seen since seenBefore,
+// never seen users first, and the total number of such users
+func (r *userRepository) ListDormantUsers(ctx context.Context, seenBefore time.Time, limit, offset int) ([]domain.User, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM users
+		WHERE deleted_at IS NULL AND is_active
+			AND (last_seen_at IS NULL OR last_seen_at < $1)
+	`
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery, seenBefore); err != nil {
+		return nil, 0, fmt.Errorf("failed to count dormant users: %w", err)
+	}
+
+	query := `
+		SELECT u.user_id, u.username,
+			COALESCE((
+				SELECT m.team_name
+				FROM team_members m
+				WHERE m.user_id = u.user_id
+				ORDER BY m.joined_at, m.team_name
+				LIMIT 1
+			), '') AS team_name,` + userTeamsColumn + `,
+			u.is_active, u.role, u.created_at, u.updated_at, u.last_seen_at
+		FROM users u
+		WHERE u.deleted_at IS NULL AND u.is_active
+			AND (u.last_seen_at IS NULL OR u.last_seen_at < $1)
+		ORDER BY u.last_seen_at ASC NULLS FIRST, u.user_id
+		LIMIT $2 OFFSET $3
+	`
+	var users []domain.User
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &users, query, seenBefore, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list dormant users: %w", err)
+	}
+
+	return users, total, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type webhookRepository struct {
+	BaseRepository
+}
+
+// NewWebhookRepository creates a new outbound webhook repository
+func NewWebhookRepository(cm db.EngineFactory) WebhookRepository {
+	return &webhookRepository{
+		BaseRepository: NewBaseRepository(cm),
+	}
+}
+
+// webhookSubscriptionRow mirrors webhook_subscriptions; event types are scanned as plain strings
+type webhookSubscriptionRow struct {
+	ID         int64
+	URL        string
+	Secret     string
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+func (row webhookSubscriptionRow) toDomain() domain.WebhookSubscription {
+	eventTypes := make([]domain.EventType, len(row.EventTypes))
+	for i, t := range row.EventTypes {
+		eventTypes[i] = domain.EventType(t)
+	}
+	return domain.WebhookSubscription{
+		ID:         row.ID,
+		URL:        row.URL,
+		Secret:     row.Secret,
+		EventTypes: eventTypes,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+// CreateWebhookSubscription stores a subscription and returns it with its ID
+func (r *webhookRepository) CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	eventTypes := make([]string, len(sub.EventTypes))
+	for i, t := range sub.EventTypes {
+		eventTypes[i] = string(t)
+	}
+
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	err := pgxscan.Get(ctx, r.Engine(ctx), &sub.ID, query, sub.URL, sub.Secret, eventTypes, sub.CreatedAt)
+	if err != nil {
+		return domain.WebhookSubscription{}, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// ListWebhookSubscriptions returns all subscriptions, oldest first
+func (r *webhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, event_types, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+	var rows []webhookSubscriptionRow
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	subs := make([]domain.WebhookSubscription, len(rows))
+	for i, row := range rows {
+		subs[i] = row.toDomain()
+	}
+	return subs, nil
+}
+
+// DeleteWebhookSubscription removes a subscription together with its delivery log
+func (r *webhookRepository) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	tag, err := r.Engine(ctx).Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// EnqueueWebhookDeliveries queues payload for every subscription of eventType and
+// returns how many deliveries were queued. Called inside the transaction of the
+// change that produced the event, so an event is queued only if the change commits.
+func (r *webhookRepository) EnqueueWebhookDeliveries(ctx context.Context, eventType domain.EventType, payload []byte, now time.Time) (int, error) {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, payload, status, next_attempt_at, created_at)
+		SELECT id, $1, $2, 'PENDING', $3, $3
+		FROM webhook_subscriptions
+		WHERE $1 = ANY(event_types)
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, eventType, payload, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDueWebhookDeliveries counts an attempt for up to limit pending deliveries due at now
+// and returns them. Their next attempt is pushed out by lease, so a delivery whose
+// worker died is retried once the lease expires; SKIP LOCKED keeps instances apart.
+func (r *webhookRepository) ClaimDueWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = $2
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.id, d.subscription_id, s.url, s.secret, d.event_type, d.payload, d.status,
+			d.attempts, d.next_attempt_at, COALESCE(d.last_error, '') AS last_error,
+			d.response_status, d.created_at, d.delivered_at
+	`
+	var deliveries []domain.WebhookDelivery
+	if err := pgxscan.Select(ctx, r.Engine(ctx), &deliveries, query, now, now.Add(lease), limit); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt stores the outcome of a delivery attempt
+func (r *webhookRepository) RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, next_attempt_at = $3, last_error = NULLIF($4, ''),
+			response_status = $5, delivered_at = $6
+		WHERE id = $1
+	`
+	tag, err := r.Engine(ctx).Exec(ctx, query, delivery.ID, delivery.Status, delivery.NextAttemptAt,
+		delivery.LastError, delivery.ResponseStatus, delivery.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns a page of a subscription's delivery log, newest first,
+// optionally filtered by status, and the total number of matching deliveries
+func (r *webhookRepository) ListWebhookDeliveries(
+	ctx context.Context,
+	subscriptionID int64,
+	status domain.WebhookDeliveryStatus,
+	limit, offset int,
+) ([]domain.WebhookDelivery, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+	`
+	if err := pgxscan.Get(ctx, r.ReadEngine(ctx), &total, countQuery, subscriptionID, status); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := `
+		SELECT d.id, d.subscription_id, s.url, d.event_type, d.payload, d.status, d.attempts,
+			d.next_attempt_at, COALESCE(d.last_error, '') AS last_error, d.response_status,
+			d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.subscription_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.id DESC
+		LIMIT $3 OFFSET $4
+	`
+	var deliveries []domain.WebhookDelivery
+	if err := pgxscan.Select(ctx, r.ReadEngine(ctx), &deliveries, query, subscriptionID, status, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
@@ -3,6 +3,7 @@ package assignment
 import (
 	"context"
 	"math/rand"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,20 +12,42 @@ import (
 
 // Strategy implements reviewer selection algorithms
 type Strategy struct {
-	rng *rand.Rand
-	mu  sync.Mutex
+	rng          *rand.Rand
+	mu           sync.Mutex
+	dormantAfter time.Duration
+}
+
+// Option configures optional Strategy behaviour
+type Option func(*Strategy)
+
+// WithDormantAfter makes selection prefer members seen within d over dormant
+// ones; dormant members are only picked when nobody else is available.
+// Zero disables the preference.
+func WithDormantAfter(d time.Duration) Option {
+	return func(s *Strategy) {
+		s.dormantAfter = d
+	}
 }
 
 // NewStrategy creates a new assignment strategy
-func NewStrategy() *Strategy {
-	return NewStrategyWithSource(rand.NewSource(time.Now().UnixNano()))
+func NewStrategy(opts ...Option) *Strategy {
+	return NewStrategyWithSource(rand.NewSource(time.Now().UnixNano()), opts...)
 }
 
 // NewStrategyWithSource allows building strategy with custom random source (useful in tests).
-func NewStrategyWithSource(src rand.Source) *Strategy {
-	return &Strategy{
+func NewStrategyWithSource(src rand.Source, opts ...Option) *Strategy {
+	s := &Strategy{
 		rng: rand.New(src),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// DormantAfter returns how long a member may go unseen before being deprioritized
+func (s *Strategy) DormantAfter() time.Duration {
+	return s.dormantAfter
 }
 
 // SelectReviewers selects up to 2 active reviewers from team, excluding author
@@ -46,6 +69,14 @@ func (s *Strategy) SelectReviewers(
 	})
 	s.mu.Unlock()
 
+	// Recently seen members go first, keeping the shuffled order within each group
+	if s.dormantAfter > 0 {
+		now := time.Now()
+		slices.SortStableFunc(candidates, func(a, b domain.User) int {
+			return dormancyRank(a, now, s.dormantAfter) - dormancyRank(b, now, s.dormantAfter)
+		})
+	}
+
 	// Select up to 2
 	maxReviewers := 2
 	if len(candidates) < maxReviewers {
@@ -87,9 +118,30 @@ func (s *Strategy) SelectReplacementReviewer(
 		return "", domain.ErrNoCandidate
 	}
 
+	if s.dormantAfter > 0 {
+		now := time.Now()
+		recent := make([]domain.User, 0, len(filtered))
+		for _, c := range filtered {
+			if !c.IsDormant(now, s.dormantAfter) {
+				recent = append(recent, c)
+			}
+		}
+		if len(recent) > 0 {
+			filtered = recent
+		}
+	}
+
 	// Random selection
 	s.mu.Lock()
 	idx := s.rng.Intn(len(filtered))
 	s.mu.Unlock()
 	return filtered[idx].UserID, nil
 }
+
+// dormancyRank orders recently seen members before dormant ones
+func dormancyRank(u domain.User, now time.Time, dormantAfter time.Duration) int {
+	if u.IsDormant(now, dormantAfter) {
+		return 1
+	}
+	return 0
+}
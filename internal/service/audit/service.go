@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+)
+
+type auditLogRepository interface {
+	RecordAuditEntry(ctx context.Context, entry domain.AuditEntry) error
+	ListAuditEntries(ctx context.Context, filter domain.AuditFilter, page pagination.Page) ([]domain.AuditEntry, int, error)
+}
+
+// Service keeps the log of state-changing API requests, filled by the audit
+// middleware and read by admins
+type Service struct {
+	repo auditLogRepository
+	now  func() time.Time
+}
+
+// NewService creates a new audit log service
+func NewService(repo auditLogRepository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Record appends entry to the log, stamped with the current time
+func (s *Service) Record(ctx context.Context, entry domain.AuditEntry) error {
+	entry.CreatedAt = s.now()
+	return s.repo.RecordAuditEntry(ctx, entry)
+}
+
+// List returns a page of the entries matching filter, newest first by default
+func (s *Service) List(ctx context.Context, filter domain.AuditFilter, page pagination.Page) (pagination.Result[domain.AuditEntry], error) {
+	page, err := page.Normalize(pagination.OrderDesc)
+	if err != nil {
+		return pagination.Result[domain.AuditEntry]{}, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return pagination.Result[domain.AuditEntry]{}, domain.NewValidationError("to", "must be after from")
+	}
+
+	entries, total, err := s.repo.ListAuditEntries(ctx, filter, page)
+	if err != nil {
+		return pagination.Result[domain.AuditEntry]{}, err
+	}
+	entries, next := pagination.Trim(entries, page, func(e domain.AuditEntry) pagination.Cursor {
+		id := strconv.FormatInt(e.ID, 10)
+		return pagination.Cursor{Key: id, ID: id}
+	})
+	return pagination.Result[domain.AuditEntry]{Items: entries, Total: total, NextCursor: next}, nil
+}
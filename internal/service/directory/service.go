@@ -0,0 +1,123 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"pr-service/internal/domain"
+)
+
+type source interface {
+	Groups(ctx context.Context) ([]domain.DirectoryGroup, error)
+}
+
+type teamService interface {
+	GetTeam(ctx context.Context, teamName string, flatten bool) (domain.Team, error)
+	UpsertTeam(ctx context.Context, teamName, parentTeamName string, members []domain.User) (domain.Team, bool, error)
+}
+
+type userService interface {
+	BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (domain.Team, []string, []domain.Reassignment, error)
+}
+
+// Service syncs teams from a directory service. Each directory group becomes a
+// team of the same name; teams without a group are left alone. Members of a
+// synced team who are missing from every group are deactivated and their open
+// reviews reassigned, so a directory outage that returns no groups is refused.
+type Service struct {
+	source source
+	teams  teamService
+	users  userService
+}
+
+// NewService creates a new directory sync service
+func NewService(source source, teams teamService, users userService) *Service {
+	return &Service{
+		source: source,
+		teams:  teams,
+		users:  users,
+	}
+}
+
+// Sync reads the directory and applies it: users that disappeared are deactivated
+// through the bulk deactivation flow, then each team's roster is replaced with the
+// group's members. Teams are synced independently; failures are joined and the
+// teams that did sync are still reported.
+func (s *Service) Sync(ctx context.Context) (domain.DirectorySync, error) {
+	groups, err := s.source.Groups(ctx)
+	if err != nil {
+		return domain.DirectorySync{}, fmt.Errorf("failed to read directory: %w", err)
+	}
+	if len(groups) == 0 {
+		return domain.DirectorySync{}, errors.New("directory returned no groups; refusing to deactivate every member")
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].TeamName < groups[j].TeamName })
+
+	present := make(map[string]struct{})
+	for _, group := range groups {
+		for _, member := range group.Members {
+			present[member.UserID] = struct{}{}
+		}
+	}
+
+	result := domain.DirectorySync{TeamsCreated: []string{}, Deactivated: []string{}, Reassignments: []domain.Reassignment{}}
+	var errs []error
+	for _, group := range groups {
+		if err := s.syncTeam(ctx, group, present, &result); err != nil {
+			errs = append(errs, fmt.Errorf("team %s: %w", group.TeamName, err))
+			continue
+		}
+		result.TeamsSynced++
+	}
+	return result, errors.Join(errs...)
+}
+
+func (s *Service) syncTeam(ctx context.Context, group domain.DirectoryGroup, present map[string]struct{}, result *domain.DirectorySync) error {
+	current, err := s.teams.GetTeam(ctx, group.TeamName, false)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	existing := make(map[string]domain.User, len(current.Members))
+	var gone []string
+	for _, member := range current.Members {
+		existing[member.UserID] = member
+		if _, ok := present[member.UserID]; !ok && member.IsActive {
+			gone = append(gone, member.UserID)
+		}
+	}
+	if len(gone) > 0 {
+		_, deactivated, reassignments, err := s.users.BulkDeactivateTeamMembers(ctx, group.TeamName, gone)
+		if err != nil {
+			return err
+		}
+		result.Deactivated = append(result.Deactivated, deactivated...)
+		result.Reassignments = append(result.Reassignments, reassignments...)
+	}
+
+	// an empty group only shrinks the team; a team cannot be synced to no members
+	if len(group.Members) == 0 {
+		return nil
+	}
+
+	// existing members keep their role and a local deactivation
+	members := make([]domain.User, len(group.Members))
+	for i, member := range group.Members {
+		member.TeamName = group.TeamName
+		if prev, ok := existing[member.UserID]; ok {
+			member.Role = prev.Role
+			member.IsActive = prev.IsActive
+		}
+		members[i] = member
+	}
+	_, created, err := s.teams.UpsertTeam(ctx, group.TeamName, "", members)
+	if err != nil {
+		return err
+	}
+	if created {
+		result.TeamsCreated = append(result.TeamsCreated, group.TeamName)
+	}
+	return nil
+}
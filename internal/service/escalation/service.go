@@ -0,0 +1,77 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+)
+
+type prRepository interface {
+	ClaimOverdueReviews(ctx context.Context, assignedBefore, now time.Time, limit int) ([]domain.StaleReview, error)
+}
+
+// escalator raises an overdue review with an on-call tool such as PagerDuty or Opsgenie
+type escalator interface {
+	Escalate(ctx context.Context, escalation domain.Escalation) error
+}
+
+const (
+	// DefaultReviewSLA is the first-review SLA used when none is configured
+	DefaultReviewSLA = 24 * time.Hour
+	// DefaultMargin is how far past the SLA a review may go before it is escalated,
+	// when not configured
+	DefaultMargin = 4 * time.Hour
+)
+
+// Service escalates reviews that breached their first-review SLA by more than a margin
+type Service struct {
+	prRepo    prRepository
+	escalator escalator
+	reviewSLA time.Duration
+	margin    time.Duration
+}
+
+// NewService creates a new escalation service; non-positive reviewSLA and margin
+// fall back to DefaultReviewSLA and DefaultMargin
+func NewService(prRepo prRepository, escalator escalator, reviewSLA, margin time.Duration) *Service {
+	if reviewSLA <= 0 {
+		reviewSLA = DefaultReviewSLA
+	}
+	if margin <= 0 {
+		margin = DefaultMargin
+	}
+
+	return &Service{
+		prRepo:    prRepo,
+		escalator: escalator,
+		reviewSLA: reviewSLA,
+		margin:    margin,
+	}
+}
+
+// EscalateOverdueReviews escalates up to limit open reviews still waiting for the
+// reviewer's first action more than the margin after the SLA ran out, and returns
+// how many were found. Each review is escalated once; failures are reported but
+// not retried.
+func (s *Service) EscalateOverdueReviews(ctx context.Context, now time.Time, limit int) (int, error) {
+	reviews, err := s.prRepo.ClaimOverdueReviews(ctx, now.Add(-s.reviewSLA-s.margin), now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, review := range reviews {
+		escalation := domain.Escalation{StaleReview: review, DueAt: review.AssignedAt.Add(s.reviewSLA)}
+		if err := s.escalator.Escalate(ctx, escalation); err != nil {
+			metrics.ReviewEscalations.WithLabelValues("failed").Inc()
+			errs = append(errs, fmt.Errorf("failed to escalate review of %s by %s: %w", review.PullRequestID, review.UserID, err))
+			continue
+		}
+		metrics.ReviewEscalations.WithLabelValues("sent").Inc()
+	}
+	return len(reviews), errors.Join(errs...)
+}
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-service/internal/cache"
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+)
+
+type exportRepository interface {
+	ExportData(ctx context.Context) (domain.DataExport, error)
+	HasData(ctx context.Context) (bool, error)
+	ImportData(ctx context.Context, data domain.DataExport) error
+}
+
+// Service dumps the whole dataset and restores such dumps into an empty instance
+type Service struct {
+	repo       exportRepository
+	transactor db.Transactioner
+	statsCache *cache.Cache
+	userCache  *cache.Cache
+	now        func() time.Time
+}
+
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithStatsCache invalidates c after an import
+func WithStatsCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.statsCache = c
+	}
+}
+
+// WithUserCache invalidates c after an import
+func WithUserCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.userCache = c
+	}
+}
+
+// NewService creates a new export service
+func NewService(repo exportRepository, transactor db.Transactioner, opts ...Option) *Service {
+	s := &Service{
+		repo:       repo,
+		transactor: transactor,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Export reads every table in one repeatable read transaction so the dump is
+// consistent
+func (s *Service) Export(ctx context.Context) (domain.DataExport, error) {
+	var data domain.DataExport
+	snapshotCtx := db.WithTxOptions(db.WithTxProfile(ctx, db.ProfileStats), db.TxOptions{Isolation: db.RepeatableRead})
+	err := s.transactor.Do(snapshotCtx, func(txCtx context.Context) error {
+		var err error
+		data, err = s.repo.ExportData(txCtx)
+		return err
+	})
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+
+	data.Version = domain.ExportFormatVersion
+	data.ExportedAt = s.now().UTC()
+	return data, nil
+}
+
+// Import validates data and inserts it in one transaction. Imports only go
+// into an instance without teams, users or PRs; merging two datasets would
+// need conflict rules the dump does not carry.
+func (s *Service) Import(ctx context.Context, data domain.DataExport) error {
+	if err := validate(data); err != nil {
+		return err
+	}
+
+	err := s.transactor.Do(db.WithTxProfile(ctx, db.ProfileBulk), func(txCtx context.Context) error {
+		hasData, err := s.repo.HasData(txCtx)
+		if err != nil {
+			return err
+		}
+		if hasData {
+			return fmt.Errorf("%w: import requires an instance without teams, users or pull requests", domain.ErrConflict)
+		}
+		return s.repo.ImportData(txCtx, data)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.statsCache.Invalidate()
+	s.userCache.Invalidate()
+	return nil
+}
+
+// validate checks the dump is self-contained: every reference points at a
+// record of the same dump and no record appears twice
+func validate(data domain.DataExport) error {
+	var v domain.Validator
+	v.Check(data.Version == domain.ExportFormatVersion, "version", fmt.Sprintf("must be %d", domain.ExportFormatVersion))
+
+	teams := make(map[string]struct{}, len(data.Teams))
+	for i, team := range data.Teams {
+		field := fmt.Sprintf("teams[%d]", i)
+		v.Required(team.TeamName, field+".team_name")
+		_, dup := teams[team.TeamName]
+		v.Check(!dup, field+".team_name", "must be unique")
+		teams[team.TeamName] = struct{}{}
+
+		hasType, hasURL := team.NotificationChannelType != "", team.NotificationChannelURL != ""
+		v.Check(!hasType || team.NotificationChannelType.IsValid(), field+".notification_channel.type", "must be one of slack, msteams, mattermost")
+		v.Check(hasType == hasURL, field+".notification_channel", "must have both type and webhook_url")
+	}
+	for i, team := range data.Teams {
+		if team.ParentTeamName == "" {
+			continue
+		}
+		field := fmt.Sprintf("teams[%d].parent_team_name", i)
+		_, ok := teams[team.ParentTeamName]
+		v.Check(ok, field, "must name a team of the export")
+		v.Check(team.ParentTeamName != team.TeamName, field, "must differ from team_name")
+	}
+
+	users := make(map[string]struct{}, len(data.Users))
+	for i, user := range data.Users {
+		field := fmt.Sprintf("users[%d]", i)
+		v.Required(user.UserID, field+".user_id")
+		v.Required(user.Username, field+".username")
+		v.Check(user.Role.IsValid(), field+".role", "must be one of member, lead")
+		_, dup := users[user.UserID]
+		v.Check(!dup, field+".user_id", "must be unique")
+		users[user.UserID] = struct{}{}
+	}
+
+	memberships := make(map[[2]string]struct{}, len(data.Memberships))
+	for i, m := range data.Memberships {
+		field := fmt.Sprintf("memberships[%d]", i)
+		_, ok := teams[m.TeamName]
+		v.Check(ok, field+".team_name", "must name a team of the export")
+		_, ok = users[m.UserID]
+		v.Check(ok, field+".user_id", "must name a user of the export")
+		key := [2]string{m.TeamName, m.UserID}
+		_, dup := memberships[key]
+		v.Check(!dup, field, "must be unique")
+		memberships[key] = struct{}{}
+	}
+
+	prs := make(map[string]struct{}, len(data.PullRequests))
+	for i, pr := range data.PullRequests {
+		field := fmt.Sprintf("pull_requests[%d]", i)
+		v.Required(pr.PullRequestID, field+".pull_request_id")
+		v.Required(pr.PullRequestName, field+".pull_request_name")
+		_, ok := users[pr.AuthorID]
+		v.Check(ok, field+".author_id", "must name a user of the export")
+		if pr.TeamName != "" {
+			_, ok = teams[pr.TeamName]
+			v.Check(ok, field+".team_name", "must name a team of the export")
+		}
+		v.Check(pr.Status == domain.PRStatusOpen || pr.Status == domain.PRStatusMerged, field+".status", "must be one of OPEN, MERGED")
+		_, dup := prs[pr.PullRequestID]
+		v.Check(!dup, field+".pull_request_id", "must be unique")
+		prs[pr.PullRequestID] = struct{}{}
+	}
+
+	reviewers := make(map[[2]string]struct{}, len(data.Reviewers))
+	for i, rv := range data.Reviewers {
+		field := fmt.Sprintf("reviewers[%d]", i)
+		_, ok := prs[rv.PullRequestID]
+		v.Check(ok, field+".pull_request_id", "must name a pull request of the export")
+		_, ok = users[rv.UserID]
+		v.Check(ok, field+".user_id", "must name a user of the export")
+		key := [2]string{rv.PullRequestID, rv.UserID}
+		_, dup := reviewers[key]
+		v.Check(!dup, field, "must be unique")
+		reviewers[key] = struct{}{}
+	}
+
+	return v.Err()
+}
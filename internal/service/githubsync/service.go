@@ -0,0 +1,128 @@
+package githubsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+)
+
+type reviewRequester interface {
+	RequestReviewers(ctx context.Context, repo string, number int, logins []string) error
+	RemoveReviewRequests(ctx context.Context, repo string, number int, logins []string) error
+}
+
+// queueSize bounds the events waiting to be written back; further events are dropped
+const queueSize = 1024
+
+// Service mirrors reviewer assignments onto GitHub pull requests by requesting
+// reviews from the assigned users and withdrawing the requests of replaced ones.
+// Only PRs created from GitHub, whose IDs look like "owner/repo#number", are synced.
+type Service struct {
+	client reviewRequester
+	logins map[string]string
+	owners []string
+	queue  chan domain.Event
+}
+
+// NewService creates a new GitHub write-back service. users maps GitHub logins
+// to user IDs like the webhook receiver does; users without a login are
+// requested by user ID. A non-empty owners limits syncing to those owners.
+func NewService(client reviewRequester, users map[string]string, owners []string) *Service {
+	logins := make(map[string]string, len(users))
+	for login, userID := range users {
+		logins[userID] = login
+	}
+
+	return &Service{
+		client: client,
+		logins: logins,
+		owners: owners,
+		queue:  make(chan domain.Event, queueSize),
+	}
+}
+
+// Notify queues committed events for writing back without blocking the caller.
+// Events are dropped when the queue is full.
+func (s *Service) Notify(_ context.Context, events ...domain.Event) {
+	for _, event := range events {
+		select {
+		case s.queue <- event:
+		default:
+			metrics.GitHubReviewRequests.WithLabelValues("dropped").Inc()
+		}
+	}
+}
+
+// Pending returns the queue of events waiting to be written back
+func (s *Service) Pending() <-chan domain.Event {
+	return s.queue
+}
+
+// Send writes an assignment or reassignment back to the GitHub pull request:
+// the new reviewer is requested and the replaced one's request is withdrawn
+func (s *Service) Send(ctx context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.EventReviewerAssigned, domain.EventReviewerReassigned:
+	default:
+		return nil
+	}
+
+	repo, number, ok := s.pullRequest(event.PullRequestID)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	if event.OldReviewerID != "" {
+		errs = append(errs, s.record(s.client.RemoveReviewRequests(ctx, repo, number, []string{s.login(event.OldReviewerID)})))
+	}
+	if event.ReviewerID != "" {
+		errs = append(errs, s.record(s.client.RequestReviewers(ctx, repo, number, []string{s.login(event.ReviewerID)})))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to sync reviewers of %s: %w", event.PullRequestID, err)
+	}
+	return nil
+}
+
+// pullRequest parses a GitHub PR ID ("owner/repo#number") of a synced owner
+func (s *Service) pullRequest(prID string) (string, int, bool) {
+	repo, num, ok := strings.Cut(prID, "#")
+	if !ok {
+		return "", 0, false
+	}
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	if len(s.owners) > 0 && !slices.Contains(s.owners, owner) {
+		return "", 0, false
+	}
+	return repo, number, true
+}
+
+func (s *Service) login(userID string) string {
+	if login, ok := s.logins[userID]; ok {
+		return login
+	}
+	return userID
+}
+
+func (s *Service) record(err error) error {
+	if err != nil {
+		metrics.GitHubReviewRequests.WithLabelValues("failed").Inc()
+		return err
+	}
+	metrics.GitHubReviewRequests.WithLabelValues("sent").Inc()
+	return nil
+}
@@ -0,0 +1,100 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+)
+
+type commenter interface {
+	AddComment(ctx context.Context, key, body string) error
+}
+
+type prRepository interface {
+	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
+}
+
+// queueSize bounds the events waiting to be commented; further events are dropped
+const queueSize = 1024
+
+// Service comments on the Jira issue linked to a PR when reviewers are assigned
+// or reassigned and when the PR is merged
+type Service struct {
+	commenter commenter
+	prRepo    prRepository
+	queue     chan domain.Event
+}
+
+// NewService creates a new Jira comment service
+func NewService(commenter commenter, prRepo prRepository) *Service {
+	return &Service{
+		commenter: commenter,
+		prRepo:    prRepo,
+		queue:     make(chan domain.Event, queueSize),
+	}
+}
+
+// Notify queues committed events for commenting without blocking the caller.
+// Events are dropped when the queue is full.
+func (s *Service) Notify(_ context.Context, events ...domain.Event) {
+	for _, event := range events {
+		select {
+		case s.queue <- event:
+		default:
+			metrics.JiraComments.WithLabelValues("dropped").Inc()
+		}
+	}
+}
+
+// Pending returns the queue of events waiting to be commented
+func (s *Service) Pending() <-chan domain.Event {
+	return s.queue
+}
+
+// Send comments on the issue linked to the event's PR, if any. Initial
+// assignments are covered by the pr.created comment, which lists the reviewers.
+func (s *Service) Send(ctx context.Context, event domain.Event) error {
+	var pr domain.PullRequest
+	switch event.Type {
+	case domain.EventPRCreated, domain.EventPRMerged:
+		if event.PR == nil {
+			return nil
+		}
+		pr = *event.PR
+	case domain.EventReviewerReassigned:
+		var err error
+		if pr, err = s.prRepo.GetPR(ctx, event.PullRequestID); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if pr.TicketKey == "" {
+		return nil
+	}
+
+	title := fmt.Sprintf("Pull request *%s* ({{%s}})", pr.PullRequestName, pr.PullRequestID)
+	var body string
+	switch event.Type {
+	case domain.EventPRCreated:
+		reviewers := "none available"
+		if len(pr.AssignedReviewers) > 0 {
+			reviewers = strings.Join(pr.AssignedReviewers, ", ")
+		}
+		body = fmt.Sprintf("%s by %s was linked to this issue. Reviewers: %s.", title, pr.AuthorID, reviewers)
+	case domain.EventReviewerReassigned:
+		body = fmt.Sprintf("%s: reviewer %s was replaced by %s.", title, event.OldReviewerID, event.ReviewerID)
+	case domain.EventPRMerged:
+		body = fmt.Sprintf("%s was merged.", title)
+	}
+
+	if err := s.commenter.AddComment(ctx, pr.TicketKey, body); err != nil {
+		metrics.JiraComments.WithLabelValues("failed").Inc()
+		return fmt.Errorf("failed to comment on %s: %w", pr.TicketKey, err)
+	}
+	metrics.JiraComments.WithLabelValues("sent").Inc()
+	return nil
+}
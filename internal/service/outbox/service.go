@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/event"
+)
+
+type outboxRepository interface {
+	AppendOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error
+	LockUnpublishedOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+	MarkOutboxMessagesPublished(ctx context.Context, ids []int64, publishedAt time.Time) error
+	ListOutboxMessages(ctx context.Context, after domain.EventCursor, limit int) ([]domain.OutboxMessage, error)
+}
+
+// transport delivers messages to the message broker, returning once they are stored
+type transport interface {
+	Send(ctx context.Context, messages []domain.OutboxMessage) error
+}
+
+const (
+	// DefaultReplayLimit is used when the caller does not specify a page size
+	DefaultReplayLimit = 100
+	// MaxReplayLimit caps the page size of event log replays
+	MaxReplayLimit = 1000
+)
+
+// Service records domain events in the outbox, which doubles as the event log
+// integrators replay, and relays them to a message broker
+type Service struct {
+	repo       outboxRepository
+	transactor db.Transactioner
+	transport  transport
+}
+
+// NewService creates a new outbox service. Without a transport events are only
+// kept in the event log.
+func NewService(repo outboxRepository, transactor db.Transactioner, transport transport) *Service {
+	return &Service{
+		repo:       repo,
+		transactor: transactor,
+		transport:  transport,
+	}
+}
+
+// Publish records events in the outbox. Callers publish inside the transaction
+// of the change, so only committed changes are relayed.
+func (s *Service) Publish(ctx context.Context, events ...domain.Event) error {
+	messages := make([]domain.OutboxMessage, 0, len(events))
+	for _, e := range events {
+		payload, err := event.Marshal(e)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, domain.OutboxMessage{
+			EventType: e.Type,
+			Key:       e.Key(),
+			Payload:   payload,
+			CreatedAt: e.OccurredAt,
+		})
+	}
+	if s.transport == nil {
+		// Nothing to relay to: record the events as already published
+		now := time.Now()
+		for i := range messages {
+			messages[i].PublishedAt = &now
+		}
+	}
+	return s.repo.AppendOutboxMessages(ctx, messages)
+}
+
+// Relay sends up to limit of the oldest unpublished messages to the broker and
+// returns how many were sent. The messages stay locked until the broker
+// acknowledged them, so a failed send leaves them for the next relay.
+// Messages of every organization are relayed; their payloads name it.
+func (s *Service) Relay(ctx context.Context, limit int) (int, error) {
+	if s.transport == nil {
+		return 0, nil
+	}
+	ctx = db.AllOrgs(ctx)
+
+	var sent int
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		messages, err := s.repo.LockUnpublishedOutboxMessages(txCtx, limit)
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		if err := s.transport.Send(txCtx, messages); err != nil {
+			return err
+		}
+
+		ids := make([]int64, len(messages))
+		for i, m := range messages {
+			ids[i] = m.ID
+		}
+		if err := s.repo.MarkOutboxMessagesPublished(txCtx, ids, time.Now()); err != nil {
+			return err
+		}
+		sent = len(messages)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, nil
+}
+
+// Replay returns up to limit events of the log recorded after since, oldest
+// first. Clients resume from the cursor of the last event returned.
+func (s *Service) Replay(ctx context.Context, since domain.EventCursor, limit int) ([]domain.OutboxMessage, error) {
+	if limit < 0 || limit > MaxReplayLimit {
+		return nil, domain.ErrInvalidArgument
+	}
+	if limit == 0 {
+		limit = DefaultReplayLimit
+	}
+	return s.repo.ListOutboxMessages(ctx, since, limit)
+}
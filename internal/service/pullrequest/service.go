@@ -2,37 +2,158 @@ package pullrequest
 
 import (
 	"context"
+	"slices"
 	"strings"
+	"time"
 
+	"pr-service/internal/cache"
 	"pr-service/internal/db"
 	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+	"pr-service/internal/pagination"
 	"pr-service/internal/service/assignment"
+	"pr-service/internal/tracing"
 )
 
 type prRepository interface {
 	CreatePR(ctx context.Context, pr domain.PullRequest) error
 	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
+	GetPRForUpdate(ctx context.Context, prID string) (domain.PullRequest, error)
 	UpdatePR(ctx context.Context, pr domain.PullRequest) error
 	AssignReviewers(ctx context.Context, prID string, reviewers []string) error
 	RemoveReviewer(ctx context.Context, prID string, userID string) error
 	AddReviewer(ctx context.Context, prID string, userID string) error
+	RecordReassignments(ctx context.Context, reassignments []domain.Reassignment) error
 	GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	ListPRs(ctx context.Context, filter domain.PRFilter, page pagination.Page) ([]domain.PullRequest, int, error)
 	PRExists(ctx context.Context, prID string) (bool, error)
-	GetAssignmentStatsByUser(ctx context.Context) (map[string]int, error)
-	GetAssignmentStatsByPR(ctx context.Context) (map[string]int, error)
+	DeletePR(ctx context.Context, prID string) error
+	GetAssignmentStatsByUser(ctx context.Context, from, to time.Time, sort domain.StatsSort, limit, offset int) ([]domain.KeyCount, int, error)
+	GetAssignmentStatsByPR(ctx context.Context, from, to time.Time, sort domain.StatsSort, limit, offset int) ([]domain.KeyCount, int, error)
+	GetAssignmentStatsByRole(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetAssignmentStatsByTeam(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetReviewerLoads(ctx context.Context, from, to time.Time) ([]domain.ReviewerLoad, error)
+	GetReviewPairs(ctx context.Context, from, to time.Time, teamName string) ([]domain.ReviewPair, error)
+	GetReassignmentStatsByUser(ctx context.Context, from, to time.Time) ([]domain.ReassignmentStats, error)
+	GetReassignmentStatsByTeam(ctx context.Context, from, to time.Time) ([]domain.ReassignmentStats, error)
+	GetReviewSLA(ctx context.Context, from, to, now time.Time, sla time.Duration) ([]domain.ReviewSLA, error)
+	GetOpenReviewCounts(ctx context.Context) ([]domain.ReviewerWorkload, error)
+	GetOpenPRAging(ctx context.Context, now time.Time) ([]domain.PRAging, error)
+	RecordReviewerAction(ctx context.Context, prID, userID string, at time.Time) (time.Time, error)
+	GetTimeToFirstReviewByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToFirstReviewByUser(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToFirstReviewByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetAuthorStatsByAuthor(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error)
+	GetAuthorStatsByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error)
+	GetAuthorStatsByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.AuthorStats, error)
+	GetTimeToMergeByTeam(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToMergeByAuthor(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToMergeByRepository(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
+	GetTimeToMergeByWeek(ctx context.Context, from, to time.Time, repository string) ([]domain.LatencyStats, error)
 }
 
 type userRepository interface {
 	GetUser(ctx context.Context, userID string) (domain.User, error)
 	GetTeamMembers(ctx context.Context, teamName string) ([]domain.User, error)
+	GetTeamTreeMembers(ctx context.Context, teamName string) ([]domain.User, error)
 }
 
+type ticketValidator interface {
+	IssueExists(ctx context.Context, key string) (bool, error)
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event) error
+}
+
+type eventListener interface {
+	Notify(ctx context.Context, events ...domain.Event)
+}
+
+const (
+	// DefaultStatsLimit is the page size of keyed stats when the caller does not specify one
+	DefaultStatsLimit = 100
+	// MaxStatsLimit caps the page size of keyed stats
+	MaxStatsLimit = 1000
+	// DefaultReviewCapacity is the number of open reviews a user can take on when not configured
+	DefaultReviewCapacity = 5
+	// MaxBatchSize caps the number of operations in a batch
+	MaxBatchSize = 1000
+)
+
 // Service handles pull request business logic
 type Service struct {
-	prRepo         prRepository
-	userRepo       userRepository
-	transactor     db.Transactioner
-	assignStrategy *assignment.Strategy
+	prRepo          prRepository
+	userRepo        userRepository
+	transactor      db.Transactioner
+	assignStrategy  *assignment.Strategy
+	includeSubTeams bool
+	reviewCapacity  int
+	statsCache      *cache.Cache
+	userCache       *cache.Cache
+	publishers      []eventPublisher
+	listeners       []eventListener
+	tickets         ticketValidator
+}
+
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithSubTeamReviewers makes assignment consider members of the team's sub-teams
+func WithSubTeamReviewers(enabled bool) Option {
+	return func(s *Service) {
+		s.includeSubTeams = enabled
+	}
+}
+
+// WithReviewCapacity sets how many open reviews a user can take on, used to report
+// workload utilization. Non-positive values keep DefaultReviewCapacity.
+func WithReviewCapacity(capacity int) Option {
+	return func(s *Service) {
+		if capacity > 0 {
+			s.reviewCapacity = capacity
+		}
+	}
+}
+
+// WithStatsCache serves stats queries from c. The service invalidates c on its
+// own writes; services sharing c must do the same. Cached values are shared
+// between callers of one organization and must not be modified.
+func WithStatsCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.statsCache = c
+	}
+}
+
+// WithUserCache serves the author and team member lookups of assignment from
+// c, keyed by organization. The service doesn't write users; the services
+// that do must invalidate c.
+func WithUserCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.userCache = c
+	}
+}
+
+// WithEventPublisher publishes PR lifecycle events with p inside the transaction of the change
+func WithEventPublisher(p eventPublisher) Option {
+	return func(s *Service) {
+		s.publishers = append(s.publishers, p)
+	}
+}
+
+// WithEventListener hands events to l once the change that caused them has
+// committed. l is called on the request path and must not block.
+func WithEventListener(l eventListener) Option {
+	return func(s *Service) {
+		s.listeners = append(s.listeners, l)
+	}
+}
+
+// WithTicketValidator makes CreatePR reject ticket keys v does not know
+func WithTicketValidator(v ticketValidator) Option {
+	return func(s *Service) {
+		s.tickets = v
+	}
 }
 
 // NewService creates a new PR service
@@ -41,56 +162,118 @@ func NewService(
 	userRepo userRepository,
 	transactor db.Transactioner,
 	assignStrategy *assignment.Strategy,
+	opts ...Option,
 ) *Service {
-	return &Service{
+	s := &Service{
 		prRepo:         prRepo,
 		userRepo:       userRepo,
 		transactor:     transactor,
 		assignStrategy: assignStrategy,
+		reviewCapacity: DefaultReviewCapacity,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// CreatePR creates PR and auto-assigns reviewers
+// CreatePR creates PR and auto-assigns reviewers from the PR's team.
+// teamName is optional and defaults to the author's earliest joined team;
+// when set, the author must be a member of it. repository is an optional
+// free-form label used to group stats. ticketKey optionally links a Jira
+// issue, which must exist when a ticket validator is configured.
 func (s *Service) CreatePR(
 	ctx context.Context,
-	prID, prName, authorID string,
+	prID, prName, authorID, teamName, repository, ticketKey string,
 ) (domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest.CreatePR")
+	defer span.End()
+	defer s.statsCache.Invalidate()
+
+	pr, events, err := s.createPR(ctx, prID, prName, authorID, teamName, repository, ticketKey)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	metrics.ReviewerAssignments.Add(float64(len(pr.AssignedReviewers)))
+	s.notify(ctx, events...)
+
+	return pr, nil
+}
+
+// createPR creates a PR and returns the events to hand to listeners once committed
+func (s *Service) createPR(
+	ctx context.Context,
+	prID, prName, authorID, teamName, repository, ticketKey string,
+) (domain.PullRequest, []domain.Event, error) {
 	prID = strings.TrimSpace(prID)
 	prName = strings.TrimSpace(prName)
 	authorID = strings.TrimSpace(authorID)
-	if prID == "" || prName == "" || authorID == "" {
-		return domain.PullRequest{}, domain.ErrInvalidArgument
+	teamName = strings.TrimSpace(teamName)
+	repository = strings.TrimSpace(repository)
+	var v domain.Validator
+	v.Required(prID, "pull_request_id")
+	v.Required(prName, "pull_request_name")
+	v.Required(authorID, "author_id")
+	if ticketKey != "" {
+		key, ok := domain.NormalizeTicketKey(ticketKey)
+		v.Check(ok, "ticket_key", "must be a Jira issue key such as PAY-42")
+		ticketKey = key
+	}
+	if err := v.Err(); err != nil {
+		return domain.PullRequest{}, nil, err
 	}
 
 	// Check if PR already exists
 	exists, err := s.prRepo.PRExists(ctx, prID)
 	if err != nil {
-		return domain.PullRequest{}, err
+		return domain.PullRequest{}, nil, err
 	}
 	if exists {
-		return domain.PullRequest{}, domain.ErrPRExists
+		return domain.PullRequest{}, nil, domain.ErrPRExists
 	}
 
-	// Get author and their team
-	author, err := s.userRepo.GetUser(ctx, authorID)
+	// Get author and resolve the PR's team
+	author, err := s.getUser(ctx, authorID)
 	if err != nil {
-		return domain.PullRequest{}, err
+		return domain.PullRequest{}, nil, err
 	}
 
-	teamMembers, err := s.userRepo.GetTeamMembers(ctx, author.TeamName)
+	if teamName == "" {
+		teamName = author.TeamName
+	} else if !author.IsMemberOf(teamName) {
+		return domain.PullRequest{}, nil, domain.ErrNotFound
+	}
+	if err := domain.AuthorizeTeam(ctx, teamName); err != nil {
+		return domain.PullRequest{}, nil, err
+	}
+
+	team, err := s.candidateTeam(ctx, teamName)
 	if err != nil {
-		return domain.PullRequest{}, err
+		return domain.PullRequest{}, nil, err
 	}
 
-	team := domain.Team{TeamName: author.TeamName, Members: teamMembers}
+	if ticketKey != "" && s.tickets != nil {
+		exists, err := s.tickets.IssueExists(ctx, ticketKey)
+		if err != nil {
+			return domain.PullRequest{}, nil, err
+		}
+		if !exists {
+			return domain.PullRequest{}, nil, domain.ErrTicketNotFound
+		}
+	}
 
 	// Select reviewers
 	reviewerIDs := s.assignStrategy.SelectReviewers(ctx, team, authorID)
 
 	// Create PR
-	pr := domain.NewPullRequest(prID, prName, authorID)
+	pr := domain.NewPullRequest(prID, prName, authorID, teamName)
+	pr.Repository = repository
+	pr.TicketKey = ticketKey
 	pr.AssignedReviewers = reviewerIDs
 
+	events := append([]domain.Event{domain.NewPREvent(domain.EventPRCreated, pr)},
+		domain.NewAssignmentEvents(prID, reviewerIDs)...)
+
 	// Create PR and assign reviewers in transaction
 	err = s.transactor.Do(ctx, func(txCtx context.Context) error {
 		if err := s.prRepo.CreatePR(txCtx, pr); err != nil {
@@ -103,85 +286,236 @@ func (s *Service) CreatePR(
 			}
 		}
 
-		return nil
+		return s.publish(txCtx, events...)
 	})
 
 	if err != nil {
-		return domain.PullRequest{}, err
+		return domain.PullRequest{}, nil, err
 	}
 
-	return pr, nil
+	return pr, events, nil
 }
 
-// MergePR marks PR as merged (idempotent)
-func (s *Service) MergePR(ctx context.Context, prID string) (domain.PullRequest, error) {
+// GetPR retrieves a PR with its reviewers by ID
+func (s *Service) GetPR(ctx context.Context, prID string) (domain.PullRequest, error) {
 	prID = strings.TrimSpace(prID)
 	if prID == "" {
 		return domain.PullRequest{}, domain.ErrInvalidArgument
 	}
 
-	pr, err := s.prRepo.GetPR(ctx, prID)
+	return s.prRepo.GetPR(ctx, prID)
+}
+
+// DeletePR removes a PR and its reviewer assignments and returns the removed
+// PR. Reassignments already logged for it stay in the stats history.
+func (s *Service) DeletePR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest.DeletePR")
+	defer span.End()
+	defer s.statsCache.Invalidate()
+
+	prID = strings.TrimSpace(prID)
+	if prID == "" {
+		return domain.PullRequest{}, domain.ErrInvalidArgument
+	}
+
+	var pr domain.PullRequest
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		var err error
+		if pr, err = s.prRepo.GetPR(txCtx, prID); err != nil {
+			return err
+		}
+		return s.prRepo.DeletePR(txCtx, prID)
+	})
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
+	return pr, nil
+}
 
-	// Merge is idempotent - if already merged, just return current state
-	pr.Merge()
+// ListPRs returns a page of PRs matching filter, newest first unless the page
+// asks otherwise, with the total number of matching PRs and the next page's cursor
+func (s *Service) ListPRs(ctx context.Context, filter domain.PRFilter, page pagination.Page) (pagination.Result[domain.PullRequest], error) {
+	page, err := page.Normalize(pagination.OrderDesc)
+	if err != nil {
+		return pagination.Result[domain.PullRequest]{}, err
+	}
+	if page.After != nil {
+		if _, err := page.After.Time(); err != nil {
+			return pagination.Result[domain.PullRequest]{}, err
+		}
+	}
+	ticketKeys := make([]string, len(filter.TicketKeys))
+	for i, key := range filter.TicketKeys {
+		normalized, ok := domain.NormalizeTicketKey(key)
+		if !ok {
+			return pagination.Result[domain.PullRequest]{}, domain.ErrInvalidArgument
+		}
+		ticketKeys[i] = normalized
+	}
+	filter.TicketKeys = ticketKeys
+	for _, status := range filter.Statuses {
+		if status != domain.PRStatusOpen && status != domain.PRStatusMerged {
+			return pagination.Result[domain.PullRequest]{}, domain.ErrInvalidArgument
+		}
+	}
 
-	if err := s.prRepo.UpdatePR(ctx, pr); err != nil {
+	prs, total, err := s.prRepo.ListPRs(ctx, filter, page)
+	if err != nil {
+		return pagination.Result[domain.PullRequest]{}, err
+	}
+	prs, next := pagination.Trim(prs, page, func(pr domain.PullRequest) pagination.Cursor {
+		return pagination.TimeCursor(pr.CreatedAt, pr.PullRequestID)
+	})
+	return pagination.Result[domain.PullRequest]{Items: prs, Total: total, NextCursor: next}, nil
+}
+
+// MergePR marks PR as merged (idempotent)
+func (s *Service) MergePR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest.MergePR")
+	defer span.End()
+	defer s.statsCache.Invalidate()
+
+	pr, events, err := s.mergePR(ctx, prID)
+	if err != nil {
 		return domain.PullRequest{}, err
 	}
+	s.notify(ctx, events...)
 
 	return pr, nil
 }
 
-// ReassignReviewer replaces reviewer with another from their team
-func (s *Service) ReassignReviewer(
-	ctx context.Context,
-	prID, oldUserID string,
-) (domain.PullRequest, string, error) {
+// mergePR merges a PR and returns the events to hand to listeners once committed
+func (s *Service) mergePR(ctx context.Context, prID string) (domain.PullRequest, []domain.Event, error) {
 	prID = strings.TrimSpace(prID)
-	oldUserID = strings.TrimSpace(oldUserID)
-	if prID == "" || oldUserID == "" {
-		return domain.PullRequest{}, "", domain.ErrInvalidArgument
+	if prID == "" {
+		return domain.PullRequest{}, nil, domain.NewValidationError("pull_request_id", "must not be empty")
 	}
 
-	pr, err := s.prRepo.GetPR(ctx, prID)
+	// The PR row stays locked until commit, so a concurrent reassignment
+	// can't slip in between the merge check and the update
+	var (
+		pr     domain.PullRequest
+		events []domain.Event
+	)
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		var err error
+		if pr, err = s.prRepo.GetPRForUpdate(txCtx, prID); err != nil {
+			return err
+		}
+		if err := domain.AuthorizeTeam(txCtx, pr.TeamName); err != nil {
+			return err
+		}
+
+		// Merge is idempotent - if already merged, just return current state
+		if pr.IsMerged() {
+			return nil
+		}
+		pr.Merge()
+		event := domain.NewPREvent(domain.EventPRMerged, pr)
+		events = []domain.Event{event}
+
+		if err := s.prRepo.UpdatePR(txCtx, pr); err != nil {
+			return err
+		}
+		pr.Version++
+		return s.publish(txCtx, event)
+	})
 	if err != nil {
-		return domain.PullRequest{}, "", err
+		return domain.PullRequest{}, nil, err
 	}
 
-	if !pr.CanReassign() {
-		return domain.PullRequest{}, "", domain.ErrPRMerged
-	}
+	return pr, events, nil
+}
 
-	if !pr.IsReviewerAssigned(oldUserID) {
-		return domain.PullRequest{}, "", domain.ErrNotAssigned
-	}
+// ReassignReviewer replaces reviewer with another member of the PR's team.
+// PRs without a team fall back to the old reviewer's team.
+func (s *Service) ReassignReviewer(
+	ctx context.Context,
+	prID, oldUserID string,
+) (domain.PullRequest, string, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest.ReassignReviewer")
+	defer span.End()
+	defer s.statsCache.Invalidate()
 
-	// Get old reviewer's team
-	oldUser, err := s.userRepo.GetUser(ctx, oldUserID)
+	pr, newUserID, events, err := s.reassignReviewer(ctx, prID, oldUserID)
 	if err != nil {
 		return domain.PullRequest{}, "", err
 	}
+	metrics.ReviewerReassignments.WithLabelValues("replaced").Inc()
+	s.notify(ctx, events...)
 
-	teamMembers, err := s.userRepo.GetTeamMembers(ctx, oldUser.TeamName)
-	if err != nil {
-		return domain.PullRequest{}, "", err
+	return pr, newUserID, nil
+}
+
+// reassignReviewer replaces a reviewer and returns the events to hand to
+// listeners once committed
+func (s *Service) reassignReviewer(
+	ctx context.Context,
+	prID, oldUserID string,
+) (domain.PullRequest, string, []domain.Event, error) {
+	prID = strings.TrimSpace(prID)
+	oldUserID = strings.TrimSpace(oldUserID)
+	var v domain.Validator
+	v.Required(prID, "pull_request_id")
+	v.Required(oldUserID, "old_user_id")
+	if err := v.Err(); err != nil {
+		return domain.PullRequest{}, "", nil, err
 	}
 
-	team := domain.Team{TeamName: oldUser.TeamName, Members: teamMembers}
+	// The PR row stays locked until commit, so concurrent reassignments of
+	// the same PR see each other's replacements instead of picking from the
+	// same reviewer list
+	var (
+		pr        domain.PullRequest
+		newUserID string
+		events    []domain.Event
+	)
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		var err error
+		if pr, err = s.prRepo.GetPRForUpdate(txCtx, prID); err != nil {
+			return err
+		}
 
-	// Exclude author and current reviewers
-	excludeIDs := append(pr.AssignedReviewers, pr.AuthorID)
+		if !pr.CanReassign() {
+			return domain.ErrPRMerged
+		}
 
-	newUserID, err := s.assignStrategy.SelectReplacementReviewer(ctx, team, excludeIDs)
-	if err != nil {
-		return domain.PullRequest{}, "", err
-	}
+		if !pr.IsReviewerAssigned(oldUserID) {
+			return domain.ErrNotAssigned
+		}
+
+		teamName := pr.TeamName
+		if teamName == "" {
+			oldUser, err := s.getUser(txCtx, oldUserID)
+			if err != nil {
+				return err
+			}
+			teamName = oldUser.TeamName
+		}
+		if err := domain.AuthorizeTeam(txCtx, teamName); err != nil {
+			return err
+		}
+
+		team, err := s.candidateTeam(txCtx, teamName)
+		if err != nil {
+			return err
+		}
+
+		// Exclude author and current reviewers
+		excludeIDs := append(slices.Clone(pr.AssignedReviewers), pr.AuthorID)
+
+		newUserID, err = s.assignStrategy.SelectReplacementReviewer(txCtx, team, excludeIDs)
+		if err != nil {
+			return err
+		}
+
+		reassignments := []domain.Reassignment{{
+			PullRequestID: prID,
+			OldUserID:     oldUserID,
+			NewUserID:     newUserID,
+		}}
+		events = domain.NewReassignmentEvents(reassignments)
 
-	// Replace reviewer in transaction
-	err = s.transactor.Do(ctx, func(txCtx context.Context) error {
 		// Remove old reviewer
 		if err := s.prRepo.RemoveReviewer(txCtx, prID, oldUserID); err != nil {
 			return err
@@ -192,19 +526,110 @@ func (s *Service) ReassignReviewer(
 			return err
 		}
 
-		return nil
+		if err := s.prRepo.RecordReassignments(txCtx, reassignments); err != nil {
+			return err
+		}
+		return s.publish(txCtx, events...)
 	})
 
 	if err != nil {
-		return domain.PullRequest{}, "", err
+		return domain.PullRequest{}, "", nil, err
 	}
 
 	// Update domain model
 	if err := pr.ReplaceReviewer(oldUserID, newUserID); err != nil {
-		return domain.PullRequest{}, "", err
+		return domain.PullRequest{}, "", nil, err
 	}
 
-	return pr, newUserID, nil
+	return pr, newUserID, events, nil
+}
+
+// Batch runs ops in order in one transaction, for migration and automation
+// scripts. When an operation fails the batch is rolled back and a
+// *domain.BatchError names the operation.
+func (s *Service) Batch(ctx context.Context, ops []domain.BatchOperation) ([]domain.BatchResult, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest.Batch")
+	defer span.End()
+
+	if len(ops) == 0 || len(ops) > MaxBatchSize {
+		return nil, domain.ErrInvalidArgument
+	}
+	defer s.statsCache.Invalidate()
+
+	var (
+		results []domain.BatchResult
+		events  []domain.Event
+	)
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		results = make([]domain.BatchResult, 0, len(ops))
+		events = nil
+		for i, op := range ops {
+			result, opEvents, err := s.runBatchOperation(txCtx, op)
+			if err != nil {
+				return &domain.BatchError{Index: i, Err: err}
+			}
+			results = append(results, result)
+			events = append(events, opEvents...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		switch op.Type {
+		case domain.BatchOpCreate:
+			metrics.ReviewerAssignments.Add(float64(len(results[i].PR.AssignedReviewers)))
+		case domain.BatchOpReassign:
+			metrics.ReviewerReassignments.WithLabelValues("replaced").Inc()
+		}
+	}
+	s.notify(ctx, events...)
+
+	return results, nil
+}
+
+func (s *Service) runBatchOperation(ctx context.Context, op domain.BatchOperation) (domain.BatchResult, []domain.Event, error) {
+	var (
+		result domain.BatchResult
+		events []domain.Event
+		err    error
+	)
+	switch op.Type {
+	case domain.BatchOpCreate:
+		result.PR, events, err = s.createPR(ctx,
+			op.PullRequestID, op.PullRequestName, op.AuthorID, op.TeamName, op.Repository, op.TicketKey)
+	case domain.BatchOpMerge:
+		result.PR, events, err = s.mergePR(ctx, op.PullRequestID)
+	case domain.BatchOpReassign:
+		result.PR, result.ReplacedBy, events, err = s.reassignReviewer(ctx, op.PullRequestID, op.OldUserID)
+	default:
+		err = domain.NewValidationError("op", "must be create, merge or reassign")
+	}
+	return result, events, err
+}
+
+// getUser looks a user up through the user cache
+func (s *Service) getUser(ctx context.Context, userID string) (domain.User, error) {
+	return cache.Load(s.userCache, s.userCache.Key(domain.OrgFromContext(ctx), "user", userID), func() (domain.User, error) {
+		return s.userRepo.GetUser(ctx, userID)
+	})
+}
+
+// candidateTeam builds the reviewer pool for a team, including sub-teams when enabled
+func (s *Service) candidateTeam(ctx context.Context, teamName string) (domain.Team, error) {
+	members, err := cache.Load(s.userCache, s.userCache.Key(domain.OrgFromContext(ctx), "members", teamName, s.includeSubTeams), func() ([]domain.User, error) {
+		if s.includeSubTeams {
+			return s.userRepo.GetTeamTreeMembers(ctx, teamName)
+		}
+		return s.userRepo.GetTeamMembers(ctx, teamName)
+	})
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	return domain.Team{TeamName: teamName, Members: members}, nil
 }
 
 // GetPRsByReviewer returns PRs where user is assigned as reviewer
@@ -220,17 +645,352 @@ func (s *Service) GetPRsByReviewer(
 	return s.prRepo.GetPRsByReviewer(ctx, userID)
 }
 
-// GetAssignmentStats returns statistics about reviewer assignments
-func (s *Service) GetAssignmentStats(ctx context.Context) (map[string]int, map[string]int, error) {
-	byUser, err := s.prRepo.GetAssignmentStatsByUser(ctx)
+// GetAssignmentStats returns a page of per-user and per-PR counts of assignments
+// made within [from, to), ordered by sort (most assignments first by default)
+func (s *Service) GetAssignmentStats(
+	ctx context.Context,
+	from, to time.Time,
+	sort domain.StatsSort,
+	limit, offset int,
+) (domain.AssignmentStats, error) {
+	if !from.Before(to) || limit < 0 || offset < 0 || limit > MaxStatsLimit {
+		return domain.AssignmentStats{}, domain.ErrInvalidArgument
+	}
+	if sort == "" {
+		sort = domain.StatsSortCountDesc
+	}
+	if !sort.IsValid() {
+		return domain.AssignmentStats{}, domain.ErrInvalidArgument
+	}
+	if limit == 0 {
+		limit = DefaultStatsLimit
+	}
+
+	key := s.statsCache.Key(domain.OrgFromContext(ctx), "assignments", from, to, sort, limit, offset)
+	return cache.Load(s.statsCache, key, func() (domain.AssignmentStats, error) {
+		var (
+			stats domain.AssignmentStats
+			err   error
+		)
+		stats.ByUser, stats.TotalUsers, err = s.prRepo.GetAssignmentStatsByUser(ctx, from, to, sort, limit, offset)
+		if err != nil {
+			return domain.AssignmentStats{}, err
+		}
+
+		stats.ByPR, stats.TotalPRs, err = s.prRepo.GetAssignmentStatsByPR(ctx, from, to, sort, limit, offset)
+		if err != nil {
+			return domain.AssignmentStats{}, err
+		}
+
+		return stats, nil
+	})
+}
+
+// GetAssignmentStatsByRole returns reviewer assignment counts made within [from, to)
+// broken down by team role
+func (s *Service) GetAssignmentStatsByRole(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	if !from.Before(to) {
+		return nil, domain.ErrInvalidArgument
+	}
+	return cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "assignments_by_role", from, to), func() (map[string]int, error) {
+		return s.prRepo.GetAssignmentStatsByRole(ctx, from, to)
+	})
+}
+
+// GetAssignmentStatsByTeam returns reviewer assignment counts made within [from, to)
+// broken down by reviewer team
+func (s *Service) GetAssignmentStatsByTeam(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	if !from.Before(to) {
+		return nil, domain.ErrInvalidArgument
+	}
+	return cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "assignments_by_team", from, to), func() (map[string]int, error) {
+		return s.prRepo.GetAssignmentStatsByTeam(ctx, from, to)
+	})
+}
+
+// GetOpenPRAging returns open PR counts per team bucketed by age as of now
+func (s *Service) GetOpenPRAging(ctx context.Context) ([]domain.PRAging, time.Time, error) {
+	now := time.Now().UTC()
+	aging, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "aging", now), func() ([]domain.PRAging, error) {
+		return s.prRepo.GetOpenPRAging(ctx, now)
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return aging, now, nil
+}
+
+// GetReviewMatrix returns author→reviewer counts of assignments made within [from, to),
+// optionally limited to PRs of one team
+func (s *Service) GetReviewMatrix(ctx context.Context, from, to time.Time, teamName string) (domain.ReviewMatrix, error) {
+	if !from.Before(to) {
+		return domain.ReviewMatrix{}, domain.ErrInvalidArgument
+	}
+
+	teamName = strings.TrimSpace(teamName)
+	pairs, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "pairs", from, to, teamName), func() ([]domain.ReviewPair, error) {
+		return s.prRepo.GetReviewPairs(ctx, from, to, teamName)
+	})
+	if err != nil {
+		return domain.ReviewMatrix{}, err
+	}
+	return domain.NewReviewMatrix(pairs), nil
+}
+
+// GetReassignmentStats returns how often reviewers were taken off PRs within [from, to),
+// per removed reviewer and per PR team, most frequent first
+func (s *Service) GetReassignmentStats(
+	ctx context.Context,
+	from, to time.Time,
+) ([]domain.ReassignmentStats, []domain.ReassignmentStats, error) {
+	if !from.Before(to) {
+		return nil, nil, domain.ErrInvalidArgument
+	}
+
+	byUser, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "reassignments_by_user", from, to), func() ([]domain.ReassignmentStats, error) {
+		return s.prRepo.GetReassignmentStatsByUser(ctx, from, to)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	byPR, err := s.prRepo.GetAssignmentStatsByPR(ctx)
+	byTeam, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "reassignments_by_team", from, to), func() ([]domain.ReassignmentStats, error) {
+		return s.prRepo.GetReassignmentStatsByTeam(ctx, from, to)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return byUser, byPR, nil
+	return byUser, byTeam, nil
+}
+
+// GetReviewSLAStats returns per-team compliance of assignments made within [from, to)
+// with a first-review SLA, as of now
+func (s *Service) GetReviewSLAStats(ctx context.Context, from, to time.Time, sla time.Duration) ([]domain.ReviewSLA, error) {
+	if !from.Before(to) || sla <= 0 {
+		return nil, domain.ErrInvalidArgument
+	}
+	return s.prRepo.GetReviewSLA(ctx, from, to, time.Now().UTC(), sla)
+}
+
+// GetWorkload returns every active user's open review count and utilization of
+// the configured review capacity, busiest first
+func (s *Service) GetWorkload(ctx context.Context) ([]domain.ReviewerWorkload, error) {
+	counts, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "workload"), func() ([]domain.ReviewerWorkload, error) {
+		return s.prRepo.GetOpenReviewCounts(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := make([]domain.ReviewerWorkload, len(counts))
+	for i, c := range counts {
+		workloads[i] = domain.NewReviewerWorkload(c.UserID, c.Username, c.OpenReviews, s.reviewCapacity)
+	}
+	return workloads, nil
+}
+
+// GetFairnessStats returns how evenly assignments made within [from, to) are spread
+// across active members of each team
+func (s *Service) GetFairnessStats(ctx context.Context, from, to time.Time) ([]domain.TeamFairness, error) {
+	if !from.Before(to) {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	loads, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "reviewer_loads", from, to), func() ([]domain.ReviewerLoad, error) {
+		return s.prRepo.GetReviewerLoads(ctx, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]string, 0)
+	counts := make(map[string][]int)
+	for _, load := range loads {
+		if _, ok := counts[load.TeamName]; !ok {
+			teams = append(teams, load.TeamName)
+		}
+		counts[load.TeamName] = append(counts[load.TeamName], load.Count)
+	}
+
+	fairness := make([]domain.TeamFairness, 0, len(teams))
+	for _, team := range teams {
+		fairness = append(fairness, domain.NewTeamFairness(team, counts[team]))
+	}
+	return fairness, nil
+}
+
+// RecordReview marks that a reviewer acted on an open PR and returns when they
+// first did so; repeated calls keep the first timestamp
+func (s *Service) RecordReview(ctx context.Context, prID, userID string) (domain.PullRequest, time.Time, error) {
+	ctx, span := tracing.Start(ctx, "pullrequest.RecordReview")
+	defer span.End()
+	defer s.statsCache.Invalidate()
+
+	prID = strings.TrimSpace(prID)
+	userID = strings.TrimSpace(userID)
+	if prID == "" || userID == "" {
+		return domain.PullRequest{}, time.Time{}, domain.ErrInvalidArgument
+	}
+
+	pr, err := s.prRepo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, time.Time{}, err
+	}
+	if pr.IsMerged() {
+		return domain.PullRequest{}, time.Time{}, domain.ErrPRMerged
+	}
+	if !pr.IsReviewerAssigned(userID) {
+		return domain.PullRequest{}, time.Time{}, domain.ErrNotAssigned
+	}
+
+	firstActionAt, err := s.prRepo.RecordReviewerAction(ctx, prID, userID, time.Now())
+	if err != nil {
+		return domain.PullRequest{}, time.Time{}, err
+	}
+
+	return pr, firstActionAt, nil
+}
+
+// GetTimeToReviewStats returns time-to-first-review percentiles per team, per
+// reviewer and per repository for reviews first acted on within [from, to).
+// A non-empty repository limits the stats to PRs of that repository.
+func (s *Service) GetTimeToReviewStats(
+	ctx context.Context,
+	from, to time.Time,
+	repository string,
+) ([]domain.LatencyStats, []domain.LatencyStats, []domain.LatencyStats, error) {
+	if !from.Before(to) {
+		return nil, nil, nil, domain.ErrInvalidArgument
+	}
+	repository = strings.TrimSpace(repository)
+
+	byTeam, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "time_to_review_by_team", from, to, repository), func() ([]domain.LatencyStats, error) {
+		return s.prRepo.GetTimeToFirstReviewByTeam(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	byUser, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "time_to_review_by_user", from, to, repository), func() ([]domain.LatencyStats, error) {
+		return s.prRepo.GetTimeToFirstReviewByUser(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	byRepository, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "time_to_review_by_repository", from, to, repository), func() ([]domain.LatencyStats, error) {
+		return s.prRepo.GetTimeToFirstReviewByRepository(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return byTeam, byUser, byRepository, nil
+}
+
+// GetTimeToMergeStats returns created-to-merged percentiles per team, per author,
+// per repository and per merge week for PRs merged within [from, to).
+// A non-empty repository limits the stats to PRs of that repository.
+func (s *Service) GetTimeToMergeStats(
+	ctx context.Context,
+	from, to time.Time,
+	repository string,
+) ([]domain.LatencyStats, []domain.LatencyStats, []domain.LatencyStats, []domain.LatencyStats, error) {
+	if !from.Before(to) {
+		return nil, nil, nil, nil, domain.ErrInvalidArgument
+	}
+	repository = strings.TrimSpace(repository)
+
+	byTeam, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "time_to_merge_by_team", from, to, repository), func() ([]domain.LatencyStats, error) {
+		return s.prRepo.GetTimeToMergeByTeam(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	byAuthor, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "time_to_merge_by_author", from, to, repository), func() ([]domain.LatencyStats, error) {
+		return s.prRepo.GetTimeToMergeByAuthor(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	byRepository, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "time_to_merge_by_repository", from, to, repository), func() ([]domain.LatencyStats, error) {
+		return s.prRepo.GetTimeToMergeByRepository(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	byWeek, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "time_to_merge_by_week", from, to, repository), func() ([]domain.LatencyStats, error) {
+		return s.prRepo.GetTimeToMergeByWeek(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return byTeam, byAuthor, byRepository, byWeek, nil
+}
+
+// GetAuthorStats returns creation, merge and review stats of PRs created within
+// [from, to) grouped by author, by team and by repository.
+// A non-empty repository limits the stats to PRs of that repository.
+func (s *Service) GetAuthorStats(
+	ctx context.Context,
+	from, to time.Time,
+	repository string,
+) ([]domain.AuthorStats, []domain.AuthorStats, []domain.AuthorStats, error) {
+	if !from.Before(to) {
+		return nil, nil, nil, domain.ErrInvalidArgument
+	}
+	repository = strings.TrimSpace(repository)
+
+	byAuthor, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "authors_by_author", from, to, repository), func() ([]domain.AuthorStats, error) {
+		return s.prRepo.GetAuthorStatsByAuthor(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	byTeam, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "authors_by_team", from, to, repository), func() ([]domain.AuthorStats, error) {
+		return s.prRepo.GetAuthorStatsByTeam(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	byRepository, err := cache.Load(s.statsCache, s.statsCache.Key(domain.OrgFromContext(ctx), "authors_by_repository", from, to, repository), func() ([]domain.AuthorStats, error) {
+		return s.prRepo.GetAuthorStatsByRepository(ctx, from, to, repository)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return byAuthor, byTeam, byRepository, nil
+}
+
+// publish hands events to the configured publishers
+func (s *Service) publish(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	domain.SetEventOrg(ctx, events)
+	for _, p := range s.publishers {
+		if err := p.Publish(ctx, events...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify hands committed events to the configured listeners
+func (s *Service) notify(ctx context.Context, events ...domain.Event) {
+	if len(events) == 0 {
+		return
+	}
+	domain.SetEventOrg(ctx, events)
+	for _, l := range s.listeners {
+		l.Notify(ctx, events...)
+	}
 }
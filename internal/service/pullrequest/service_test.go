@@ -0,0 +1,281 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pr-service/internal/cache"
+	"pr-service/internal/domain"
+	"pr-service/internal/service/assignment"
+)
+
+// txLocks holds the row locks taken in one transaction
+type txLocks struct {
+	unlock []func()
+}
+
+type txLocksKey struct{}
+
+// lockingTransactor releases the row locks a transaction took when it ends,
+// like a database does on commit
+type lockingTransactor struct{}
+
+func (lockingTransactor) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txLocksKey{}).(*txLocks); ok {
+		return f(ctx)
+	}
+	locks := &txLocks{}
+	defer func() {
+		for _, unlock := range locks.unlock {
+			unlock()
+		}
+	}()
+	return f(context.WithValue(ctx, txLocksKey{}, locks))
+}
+
+// fakePRRepo keeps PRs in memory; GetPRForUpdate locks the PR until the
+// transaction ends. Methods the tests don't use panic through the nil
+// embedded interface.
+type fakePRRepo struct {
+	prRepository
+
+	mu    sync.Mutex
+	prs   map[string]domain.PullRequest
+	locks map[string]*sync.Mutex
+}
+
+func newFakePRRepo(prs ...domain.PullRequest) *fakePRRepo {
+	r := &fakePRRepo{prs: make(map[string]domain.PullRequest), locks: make(map[string]*sync.Mutex)}
+	for _, pr := range prs {
+		r.prs[pr.PullRequestID] = pr
+		r.locks[pr.PullRequestID] = &sync.Mutex{}
+	}
+	return r
+}
+
+func (r *fakePRRepo) GetPR(_ context.Context, prID string) (domain.PullRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr, ok := r.prs[prID]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	return pr, nil
+}
+
+func (r *fakePRRepo) GetPRForUpdate(ctx context.Context, prID string) (domain.PullRequest, error) {
+	r.mu.Lock()
+	lock, ok := r.locks[prID]
+	r.mu.Unlock()
+	if !ok {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if locks, ok := ctx.Value(txLocksKey{}).(*txLocks); ok {
+		lock.Lock()
+		locks.unlock = append(locks.unlock, lock.Unlock)
+	}
+	return r.GetPR(ctx, prID)
+}
+
+func (r *fakePRRepo) UpdatePR(_ context.Context, pr domain.PullRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.prs[pr.PullRequestID]
+	if current.Version != pr.Version {
+		return domain.ErrVersionConflict
+	}
+	pr.AssignedReviewers = current.AssignedReviewers
+	pr.Version++
+	r.prs[pr.PullRequestID] = pr
+	return nil
+}
+
+// bump changes a PR behind the service's back, as a writer that doesn't
+// lock the row would
+func (r *fakePRRepo) bump(prID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr := r.prs[prID]
+	pr.Version++
+	r.prs[prID] = pr
+}
+
+// bumpingPRRepo changes the PR right after the service has read it
+type bumpingPRRepo struct {
+	*fakePRRepo
+}
+
+func (r bumpingPRRepo) GetPRForUpdate(ctx context.Context, prID string) (domain.PullRequest, error) {
+	pr, err := r.fakePRRepo.GetPRForUpdate(ctx, prID)
+	r.bump(prID)
+	return pr, err
+}
+
+// waitingPRRepo reports on waiting when a transaction is about to lock a PR
+type waitingPRRepo struct {
+	*fakePRRepo
+	waiting chan<- struct{}
+}
+
+func (r waitingPRRepo) GetPRForUpdate(ctx context.Context, prID string) (domain.PullRequest, error) {
+	r.waiting <- struct{}{}
+	return r.fakePRRepo.GetPRForUpdate(ctx, prID)
+}
+
+func (r *fakePRRepo) RemoveReviewer(_ context.Context, prID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr := r.prs[prID]
+	i := slices.Index(pr.AssignedReviewers, userID)
+	if i < 0 {
+		return domain.ErrNotFound
+	}
+	pr.AssignedReviewers = slices.Delete(slices.Clone(pr.AssignedReviewers), i, i+1)
+	r.prs[prID] = pr
+	return nil
+}
+
+func (r *fakePRRepo) AddReviewer(_ context.Context, prID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pr := r.prs[prID]
+	if !slices.Contains(pr.AssignedReviewers, userID) {
+		pr.AssignedReviewers = append(slices.Clone(pr.AssignedReviewers), userID)
+	}
+	r.prs[prID] = pr
+	return nil
+}
+
+func (r *fakePRRepo) RecordReassignments(context.Context, []domain.Reassignment) error {
+	return nil
+}
+
+type fakeUserRepo struct {
+	userRepository
+	members []domain.User
+	lookups atomic.Int32
+}
+
+func (r *fakeUserRepo) GetTeamMembers(context.Context, string) ([]domain.User, error) {
+	r.lookups.Add(1)
+	return r.members, nil
+}
+
+func TestMergeWaitsForReassignment(t *testing.T) {
+	users := &fakeUserRepo{}
+	for i := 0; i < 4; i++ {
+		id := fmt.Sprintf("u%d", i)
+		users.members = append(users.members, domain.NewUser(id, id, "backend", true))
+	}
+	pr := domain.NewPullRequest("pr-1", "Feature", "u0", "backend")
+	pr.AssignedReviewers = []string{"u1"}
+	prs := newFakePRRepo(pr)
+	waiting := make(chan struct{})
+	service := NewService(waitingPRRepo{prs, waiting}, users, lockingTransactor{}, assignment.NewStrategyWithSource(rand.NewSource(1)))
+
+	// A reassignment holding the PR lock keeps the merge waiting
+	ctx := context.Background()
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- lockingTransactor{}.Do(ctx, func(txCtx context.Context) error {
+			if _, err := prs.GetPRForUpdate(txCtx, "pr-1"); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+
+	merged := make(chan error, 1)
+	go func() {
+		_, err := service.MergePR(ctx, "pr-1")
+		merged <- err
+	}()
+	<-waiting
+	select {
+	case err := <-merged:
+		t.Fatalf("merge finished while the PR was locked: %v", err)
+	default:
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("locking transaction failed: %v", err)
+	}
+	if err := <-merged; err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if got, _ := prs.GetPR(ctx, "pr-1"); !got.IsMerged() {
+		t.Fatalf("expected the PR to be merged, got %s", got.Status)
+	}
+}
+
+func TestMergeRefusesStaleVersion(t *testing.T) {
+	pr := domain.NewPullRequest("pr-1", "Feature", "u0", "backend")
+	prs := newFakePRRepo(pr)
+	strategy := assignment.NewStrategyWithSource(rand.NewSource(1))
+
+	merged, err := NewService(prs, &fakeUserRepo{}, lockingTransactor{}, strategy).MergePR(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if merged.Version != 2 {
+		t.Fatalf("expected the merge to bump the version to 2, got %d", merged.Version)
+	}
+
+	// A write made from a version that changed since it was read is refused
+	stale := newFakePRRepo(pr)
+	_, err = NewService(bumpingPRRepo{stale}, &fakeUserRepo{}, lockingTransactor{}, strategy).MergePR(context.Background(), "pr-1")
+	if !errors.Is(err, domain.ErrVersionConflict) || domain.GetErrorCode(err) != domain.ErrorCodeConflict {
+		t.Fatalf("expected a CONFLICT error, got %v", err)
+	}
+	if got, _ := stale.GetPR(context.Background(), "pr-1"); got.IsMerged() {
+		t.Fatal("expected the stale merge to change nothing")
+	}
+}
+
+func TestReassignUsesUserCache(t *testing.T) {
+	users := &fakeUserRepo{}
+	for i := 0; i < 4; i++ {
+		id := fmt.Sprintf("u%d", i)
+		users.members = append(users.members, domain.NewUser(id, id, "backend", true))
+	}
+	pr := domain.NewPullRequest("pr-1", "Feature", "u0", "backend")
+	pr.AssignedReviewers = []string{"u1"}
+	prs := newFakePRRepo(pr)
+	userCache := cache.New("users", time.Minute)
+	service := NewService(prs, users, lockingTransactor{}, assignment.NewStrategyWithSource(rand.NewSource(1)), WithUserCache(userCache))
+
+	reassign := func() {
+		t.Helper()
+		got, _ := prs.GetPR(context.Background(), "pr-1")
+		if _, _, err := service.ReassignReviewer(context.Background(), "pr-1", got.AssignedReviewers[0]); err != nil {
+			t.Fatalf("reassign failed: %v", err)
+		}
+	}
+
+	reassign()
+	reassign()
+	if n := users.lookups.Load(); n != 1 {
+		t.Fatalf("expected the members to be loaded once, got %d lookups", n)
+	}
+
+	// A write to users drops the cached members
+	userCache.Invalidate()
+	reassign()
+	if n := users.lookups.Load(); n != 2 {
+		t.Fatalf("expected the members to be reloaded after invalidation, got %d lookups", n)
+	}
+}
@@ -0,0 +1,200 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+const (
+	// DefaultSchedule delivers the weekly report on Mondays at 09:00 UTC
+	DefaultSchedule = "0 9 * * 1"
+
+	// DefaultReviewSLA is the first-review SLA used when none is configured
+	DefaultReviewSLA = 24 * time.Hour
+
+	// period is the span covered by a weekly report
+	period = 7 * 24 * time.Hour
+)
+
+type statsService interface {
+	GetAssignmentStatsByTeam(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetReviewSLAStats(ctx context.Context, from, to time.Time, sla time.Duration) ([]domain.ReviewSLA, error)
+	GetFairnessStats(ctx context.Context, from, to time.Time) ([]domain.TeamFairness, error)
+}
+
+type sender interface {
+	Post(ctx context.Context, payload any) error
+}
+
+// Service builds periodic review summaries and pushes them to a webhook
+type Service struct {
+	stats     statsService
+	sender    sender
+	reviewSLA time.Duration
+}
+
+// NewService creates a new report service; reviewSLA <= 0 falls back to DefaultReviewSLA
+func NewService(stats statsService, sender sender, reviewSLA time.Duration) *Service {
+	if reviewSLA <= 0 {
+		reviewSLA = DefaultReviewSLA
+	}
+
+	return &Service{
+		stats:     stats,
+		sender:    sender,
+		reviewSLA: reviewSLA,
+	}
+}
+
+// BuildWeeklyReport summarizes assignments, SLA compliance and fairness over the week before now
+func (s *Service) BuildWeeklyReport(ctx context.Context, now time.Time) (domain.WeeklyReport, error) {
+	to := now.UTC()
+	from := to.Add(-period)
+
+	assignments, err := s.stats.GetAssignmentStatsByTeam(ctx, from, to)
+	if err != nil {
+		return domain.WeeklyReport{}, err
+	}
+
+	sla, err := s.stats.GetReviewSLAStats(ctx, from, to, s.reviewSLA)
+	if err != nil {
+		return domain.WeeklyReport{}, err
+	}
+
+	fairness, err := s.stats.GetFairnessStats(ctx, from, to)
+	if err != nil {
+		return domain.WeeklyReport{}, err
+	}
+
+	return domain.WeeklyReport{
+		From:        from,
+		To:          to,
+		ReviewSLA:   s.reviewSLA,
+		Assignments: assignments,
+		SLA:         sla,
+		Fairness:    fairness,
+	}, nil
+}
+
+// DeliverWeeklyReport builds the weekly report and posts it to the webhook.
+// The payload carries a Slack-compatible text rendering and the raw figures.
+func (s *Service) DeliverWeeklyReport(ctx context.Context, now time.Time) error {
+	report, err := s.BuildWeeklyReport(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Post(ctx, newPayload(report))
+}
+
+type payload struct {
+	Text   string        `json:"text"`
+	Report reportPayload `json:"report"`
+}
+
+type reportPayload struct {
+	From             time.Time         `json:"from"`
+	To               time.Time         `json:"to"`
+	ReviewSLASeconds float64           `json:"review_sla_seconds"`
+	Assignments      map[string]int    `json:"assignments_by_team"`
+	SLA              []slaPayload      `json:"sla"`
+	Fairness         []fairnessPayload `json:"fairness"`
+}
+
+type slaPayload struct {
+	TeamName   string  `json:"team_name"`
+	Due        int     `json:"due"`
+	Met        int     `json:"met"`
+	Compliance float64 `json:"compliance"`
+}
+
+type fairnessPayload struct {
+	TeamName      string   `json:"team_name"`
+	ActiveMembers int      `json:"active_members"`
+	Assignments   int      `json:"assignments"`
+	Gini          float64  `json:"gini"`
+	MaxMinRatio   *float64 `json:"max_min_ratio"`
+}
+
+func newPayload(report domain.WeeklyReport) payload {
+	p := payload{
+		Text: Render(report),
+		Report: reportPayload{
+			From:             report.From,
+			To:               report.To,
+			ReviewSLASeconds: report.ReviewSLA.Seconds(),
+			Assignments:      report.Assignments,
+			SLA:              make([]slaPayload, len(report.SLA)),
+			Fairness:         make([]fairnessPayload, len(report.Fairness)),
+		},
+	}
+	for i, sla := range report.SLA {
+		p.Report.SLA[i] = slaPayload{
+			TeamName:   sla.TeamName,
+			Due:        sla.Due,
+			Met:        sla.Met,
+			Compliance: sla.Compliance(),
+		}
+	}
+	for i, f := range report.Fairness {
+		p.Report.Fairness[i] = fairnessPayload{
+			TeamName:      f.TeamName,
+			ActiveMembers: f.ActiveMembers,
+			Assignments:   f.Assignments,
+			Gini:          f.Gini,
+			MaxMinRatio:   f.MaxMinRatio,
+		}
+	}
+	return p
+}
+
+// Render formats a report as Slack mrkdwn text
+func Render(report domain.WeeklyReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Weekly review summary* %s – %s\n",
+		report.From.Format(time.DateOnly), report.To.Format(time.DateOnly))
+
+	teams := make([]string, 0, len(report.Assignments))
+	for team := range report.Assignments {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	b.WriteString("\n*Assignments*\n")
+	if len(teams) == 0 {
+		b.WriteString("No reviewers assigned\n")
+	}
+	for _, team := range teams {
+		fmt.Fprintf(&b, "• %s: %d\n", teamLabel(team), report.Assignments[team])
+	}
+
+	fmt.Fprintf(&b, "\n*First review within %s*\n", report.ReviewSLA)
+	if len(report.SLA) == 0 {
+		b.WriteString("No reviews due\n")
+	}
+	for _, sla := range report.SLA {
+		fmt.Fprintf(&b, "• %s: %.0f%% (%d/%d)\n", teamLabel(sla.TeamName), sla.Compliance()*100, sla.Met, sla.Due)
+	}
+
+	b.WriteString("\n*Load fairness (Gini, 0 is even)*\n")
+	if len(report.Fairness) == 0 {
+		b.WriteString("No active teams\n")
+	}
+	for _, f := range report.Fairness {
+		fmt.Fprintf(&b, "• %s: %.2f across %d members\n", teamLabel(f.TeamName), f.Gini, f.ActiveMembers)
+	}
+
+	return b.String()
+}
+
+func teamLabel(team string) string {
+	if team == "" {
+		return "(no team)"
+	}
+	return team
+}
@@ -0,0 +1,101 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/cache"
+	"pr-service/internal/db"
+)
+
+// DefaultBatchSize is used when no batch size is configured
+const DefaultBatchSize = 500
+
+type prRepository interface {
+	ArchiveMergedPRs(ctx context.Context, mergedBefore time.Time, limit int) (int, error)
+	DeleteMergedPRs(ctx context.Context, mergedBefore time.Time, limit int) (int, error)
+}
+
+// Service moves merged PRs past the retention age out of the tables the
+// service and its stats read from
+type Service struct {
+	repo       prRepository
+	transactor db.Transactioner
+	maxAge     time.Duration
+	batchSize  int
+	delete     bool
+	statsCache *cache.Cache
+}
+
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithDelete deletes PRs past the retention age instead of archiving them
+func WithDelete() Option {
+	return func(s *Service) {
+		s.delete = true
+	}
+}
+
+// WithStatsCache invalidates c after PRs were removed
+func WithStatsCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.statsCache = c
+	}
+}
+
+// NewService creates a new retention service for PRs merged more than maxAge
+// ago; batchSize <= 0 falls back to DefaultBatchSize
+func NewService(repo prRepository, transactor db.Transactioner, maxAge time.Duration, batchSize int, opts ...Option) *Service {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	s := &Service{
+		repo:       repo,
+		transactor: transactor,
+		maxAge:     maxAge,
+		batchSize:  batchSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RemoveExpiredPRs archives, or deletes, every PR merged more than the
+// retention age before now, one transaction per batch so no lock is held for
+// long, and returns the number of PRs removed
+func (s *Service) RemoveExpiredPRs(ctx context.Context, now time.Time) (int, error) {
+	mergedBefore := now.Add(-s.maxAge)
+	removed := 0
+	defer func() {
+		if removed > 0 {
+			s.statsCache.Invalidate()
+		}
+	}()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+
+		var n int
+		err := s.transactor.Do(db.WithTxProfile(ctx, db.ProfileBulk), func(txCtx context.Context) error {
+			var err error
+			if s.delete {
+				n, err = s.repo.DeleteMergedPRs(txCtx, mergedBefore, s.batchSize)
+			} else {
+				n, err = s.repo.ArchiveMergedPRs(txCtx, mergedBefore, s.batchSize)
+			}
+			return err
+		})
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+		if n < s.batchSize {
+			return removed, nil
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/repository/memory"
+)
+
+// newRepo creates a repository with an open PR and count PRs merged i days
+// before now
+func newRepo(t *testing.T, now time.Time, count int) *memory.PRRepository {
+	t.Helper()
+	repo := memory.NewPRRepository(memory.NewUserRepository())
+	ctx := context.Background()
+	if err := repo.CreatePR(ctx, domain.NewPullRequest("open", "Open", "u1", "backend")); err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	for i := 0; i < count; i++ {
+		pr := domain.NewPullRequest(fmt.Sprintf("pr-%d", i), "Merged", "u1", "backend")
+		mergedAt := now.AddDate(0, 0, -i)
+		pr.Status = domain.PRStatusMerged
+		pr.MergedAt = &mergedAt
+		if err := repo.CreatePR(ctx, pr); err != nil {
+			t.Fatalf("CreatePR: %v", err)
+		}
+	}
+	return repo
+}
+
+func TestRemoveExpiredPRs(t *testing.T) {
+	now := time.Now()
+	for name, opts := range map[string][]Option{"archive": nil, "delete": {WithDelete()}} {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t, now, 10)
+			// PRs merged more than three days ago go, in batches of two
+			service := NewService(repo, memory.NewTransactor(), 3*24*time.Hour+time.Minute, 2, opts...)
+
+			removed, err := service.RemoveExpiredPRs(context.Background(), now)
+			if err != nil {
+				t.Fatalf("RemoveExpiredPRs: %v", err)
+			}
+			if removed != 6 {
+				t.Fatalf("expected six PRs removed, got %d", removed)
+			}
+			for id, kept := range map[string]bool{"open": true, "pr-3": true, "pr-4": false, "pr-9": false} {
+				if exists, _ := repo.PRExists(context.Background(), id); exists != kept {
+					t.Errorf("%s: expected exists %v, got %v", id, kept, exists)
+				}
+			}
+
+			removed, err = service.RemoveExpiredPRs(context.Background(), now)
+			if err != nil || removed != 0 {
+				t.Fatalf("expected nothing left to remove, got %d, %v", removed, err)
+			}
+		})
+	}
+}
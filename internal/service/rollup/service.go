@@ -0,0 +1,91 @@
+package rollup
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+)
+
+// DefaultBackfillDays is how many past days are rolled up when no day has been rolled up yet
+const DefaultBackfillDays = 30
+
+const day = 24 * time.Hour
+
+type rollupRepository interface {
+	LastRolledUpDay(ctx context.Context) (time.Time, error)
+	RollUpDay(ctx context.Context, day time.Time) error
+	GetDailyStats(ctx context.Context, from, to time.Time, teamName string) ([]domain.DailyStats, error)
+}
+
+// Service rolls raw review activity up into per-day aggregates and serves them
+type Service struct {
+	repo         rollupRepository
+	transactor   db.Transactioner
+	backfillDays int
+}
+
+// NewService creates a new rollup service; backfillDays <= 0 falls back to DefaultBackfillDays
+func NewService(repo rollupRepository, transactor db.Transactioner, backfillDays int) *Service {
+	if backfillDays <= 0 {
+		backfillDays = DefaultBackfillDays
+	}
+
+	return &Service{
+		repo:         repo,
+		transactor:   transactor,
+		backfillDays: backfillDays,
+	}
+}
+
+// RollUpDueDays rolls up every complete UTC day after the last rolled up one,
+// one transaction per day, and returns the number of days rolled up. The
+// current day is never rolled up since its data is still changing.
+func (s *Service) RollUpDueDays(ctx context.Context, now time.Time) (int, error) {
+	today := now.UTC().Truncate(day)
+
+	last, err := s.repo.LastRolledUpDay(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	next := today.AddDate(0, 0, -s.backfillDays)
+	if !last.IsZero() {
+		next = last.UTC().Truncate(day).AddDate(0, 0, 1)
+	}
+
+	rolled := 0
+	for ; next.Before(today); next = next.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return rolled, err
+		}
+
+		err := s.transactor.Do(db.WithTxProfile(ctx, db.ProfileStats), func(txCtx context.Context) error {
+			return s.repo.RollUpDay(txCtx, next)
+		})
+		if err != nil {
+			return rolled, err
+		}
+		rolled++
+	}
+
+	return rolled, nil
+}
+
+// GetDailyStats returns rolled up days overlapping [from, to), optionally for one team.
+// Days not rolled up yet, including today, are not included.
+func (s *Service) GetDailyStats(ctx context.Context, from, to time.Time, teamName string) ([]domain.DailyStats, error) {
+	if !from.Before(to) {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	from = from.UTC().Truncate(day)
+	to = to.UTC()
+	if !to.Equal(to.Truncate(day)) {
+		to = to.Truncate(day).Add(day)
+	}
+
+	return s.repo.GetDailyStats(ctx, from, to, strings.TrimSpace(teamName))
+}
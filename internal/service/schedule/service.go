@@ -0,0 +1,153 @@
+package schedule
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"pr-service/internal/domain"
+)
+
+type scheduledChangeRepository interface {
+	CreateScheduledChange(ctx context.Context, change domain.ScheduledChange) (domain.ScheduledChange, error)
+	ClaimDueScheduledChanges(ctx context.Context, now time.Time, limit int) ([]domain.ScheduledChange, error)
+	CompleteScheduledChange(ctx context.Context, id int64, status domain.ScheduledChangeStatus, errMsg string) error
+}
+
+type userService interface {
+	GetUser(ctx context.Context, userID string) (domain.User, error)
+	SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error)
+	BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (domain.Team, []string, []domain.Reassignment, error)
+}
+
+// Service stages activity changes and applies them once they become due
+type Service struct {
+	repo  scheduledChangeRepository
+	users userService
+}
+
+// NewService creates a new schedule service
+func NewService(repo scheduledChangeRepository, users userService) *Service {
+	return &Service{
+		repo:  repo,
+		users: users,
+	}
+}
+
+// ScheduleSetIsActive stages an activity flag change for a single user
+func (s *Service) ScheduleSetIsActive(
+	ctx context.Context,
+	userID string,
+	isActive bool,
+	effectiveAt time.Time,
+) (domain.ScheduledChange, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" || effectiveAt.IsZero() {
+		return domain.ScheduledChange{}, domain.ErrInvalidArgument
+	}
+
+	user, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return domain.ScheduledChange{}, err
+	}
+	if err := domain.AuthorizeLead(ctx, s.users.GetUser, user.MemberTeams()...); err != nil {
+		return domain.ScheduledChange{}, err
+	}
+
+	change := domain.NewScheduledChange(domain.ScheduledChangeSetIsActive, "", []string{userID}, isActive, effectiveAt)
+	return s.repo.CreateScheduledChange(ctx, change)
+}
+
+// ScheduleBulkDeactivation stages deactivation of team members together with review handoff
+func (s *Service) ScheduleBulkDeactivation(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	effectiveAt time.Time,
+) (domain.ScheduledChange, error) {
+	teamName = strings.TrimSpace(teamName)
+	if teamName == "" || len(userIDs) == 0 || effectiveAt.IsZero() {
+		return domain.ScheduledChange{}, domain.ErrInvalidArgument
+	}
+	if err := domain.AuthorizeLead(ctx, s.users.GetUser, teamName); err != nil {
+		return domain.ScheduledChange{}, err
+	}
+
+	normalized := make([]string, 0, len(userIDs))
+	seen := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return domain.ScheduledChange{}, domain.ErrInvalidArgument
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		user, err := s.users.GetUser(ctx, id)
+		if err != nil {
+			return domain.ScheduledChange{}, err
+		}
+		if !user.IsMemberOf(teamName) {
+			return domain.ScheduledChange{}, domain.ErrNotFound
+		}
+		normalized = append(normalized, id)
+	}
+
+	change := domain.NewScheduledChange(domain.ScheduledChangeBulkDeactivate, teamName, normalized, false, effectiveAt)
+	return s.repo.CreateScheduledChange(ctx, change)
+}
+
+// ApplyDueChanges applies up to limit changes that are due at now and returns how many were processed.
+// A failing change is marked FAILED with its error and does not stop the batch.
+func (s *Service) ApplyDueChanges(ctx context.Context, now time.Time, limit int) (int, error) {
+	changes, err := s.repo.ClaimDueScheduledChanges(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, change := range changes {
+		status, errMsg := domain.ScheduledChangeApplied, ""
+		if err := s.apply(ctx, change); err != nil {
+			status, errMsg = domain.ScheduledChangeFailed, err.Error()
+		}
+
+		if err := s.repo.CompleteScheduledChange(ctx, change.ID, status, errMsg); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(changes), nil
+}
+
+func (s *Service) apply(ctx context.Context, change domain.ScheduledChange) error {
+	switch change.Kind {
+	case domain.ScheduledChangeBulkDeactivate:
+		_, _, _, err := s.users.BulkDeactivateTeamMembers(ctx, change.TeamName, change.UserIDs)
+		return err
+	case domain.ScheduledChangeSetIsActive:
+		if len(change.UserIDs) != 1 {
+			return domain.ErrInvalidArgument
+		}
+		userID := change.UserIDs[0]
+
+		// Staged offboarding should not leave reviews behind, so a deactivation
+		// goes through the bulk path which hands open reviews over to teammates.
+		if !change.IsActive {
+			user, err := s.users.GetUser(ctx, userID)
+			if err != nil {
+				return err
+			}
+			if user.TeamName != "" {
+				_, _, _, err := s.users.BulkDeactivateTeamMembers(ctx, user.TeamName, []string{userID})
+				return err
+			}
+		}
+
+		_, err := s.users.SetIsActive(ctx, userID, change.IsActive)
+		return err
+	default:
+		return domain.ErrInvalidArgument
+	}
+}
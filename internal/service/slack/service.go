@@ -0,0 +1,239 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+)
+
+type messenger interface {
+	PostMessage(ctx context.Context, channel, text string) error
+}
+
+type prRepository interface {
+	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
+	ClaimStaleReviews(ctx context.Context, assignedBefore, now time.Time, limit int) ([]domain.StaleReview, error)
+	GetPendingReviewsByReviewers(ctx context.Context, userIDs []string) ([]domain.ReviewAssignment, error)
+}
+
+const (
+	// DefaultStaleAfter is how long a review may wait for the reviewer's first action
+	// before the reviewer is reminded, when not configured
+	DefaultStaleAfter = 48 * time.Hour
+	// DefaultReviewSLA is the first-review SLA digests compute due dates with, when not configured
+	DefaultReviewSLA = 24 * time.Hour
+	// queueSize bounds the events waiting to be sent; further events are dropped
+	queueSize = 1024
+)
+
+// Service sends direct messages to reviewers in Slack. Users maps user IDs to
+// Slack member IDs; users without a mapping are not notified.
+type Service struct {
+	messenger  messenger
+	prRepo     prRepository
+	users      map[string]string
+	staleAfter time.Duration
+	reviewSLA  time.Duration
+	queue      chan domain.Event
+}
+
+// NewService creates a new Slack notification service; non-positive staleAfter and
+// reviewSLA fall back to DefaultStaleAfter and DefaultReviewSLA
+func NewService(messenger messenger, prRepo prRepository, users map[string]string, staleAfter, reviewSLA time.Duration) *Service {
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	if reviewSLA <= 0 {
+		reviewSLA = DefaultReviewSLA
+	}
+
+	return &Service{
+		messenger:  messenger,
+		prRepo:     prRepo,
+		users:      users,
+		staleAfter: staleAfter,
+		reviewSLA:  reviewSLA,
+		queue:      make(chan domain.Event, queueSize),
+	}
+}
+
+// Notify queues committed events for sending without blocking the caller.
+// Events are dropped when the queue is full.
+func (s *Service) Notify(_ context.Context, events ...domain.Event) {
+	for _, event := range events {
+		select {
+		case s.queue <- event:
+		default:
+			metrics.SlackNotifications.WithLabelValues("dropped").Inc()
+		}
+	}
+}
+
+// Pending returns the queue of events waiting to be sent
+func (s *Service) Pending() <-chan domain.Event {
+	return s.queue
+}
+
+// Send messages the reviewers affected by event: assigned reviewers are told about
+// their new review and replaced reviewers that they were taken off it
+func (s *Service) Send(ctx context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.EventReviewerAssigned, domain.EventReviewerReassigned:
+	default:
+		return nil
+	}
+
+	pr, err := s.pullRequest(ctx, event)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("*%s* (`%s`)", pr.PullRequestName, pr.PullRequestID)
+
+	var errs []error
+	if event.ReviewerID != "" {
+		text := fmt.Sprintf("You were assigned to review %s.", title)
+		if event.OldReviewerID != "" {
+			text = fmt.Sprintf("You were assigned to review %s, taking over from %s.", title, s.mention(event.OldReviewerID))
+		}
+		errs = append(errs, s.post(ctx, event.ReviewerID, text))
+	}
+	if event.OldReviewerID != "" {
+		errs = append(errs, s.post(ctx, event.OldReviewerID, fmt.Sprintf("You were taken off the review of %s.", title)))
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyStaleReviews reminds reviewers of up to limit open reviews they have not acted on
+// within the stale threshold and returns how many reviews were found. Each review is
+// reminded once; failed messages are reported but not retried.
+func (s *Service) NotifyStaleReviews(ctx context.Context, now time.Time, limit int) (int, error) {
+	reviews, err := s.prRepo.ClaimStaleReviews(ctx, now.Add(-s.staleAfter), now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, review := range reviews {
+		text := fmt.Sprintf("*%s* (`%s`) has been waiting for your review since %s.",
+			review.PullRequestName, review.PullRequestID, review.AssignedAt.UTC().Format("2006-01-02 15:04 UTC"))
+		errs = append(errs, s.post(ctx, review.UserID, text))
+	}
+	return len(reviews), errors.Join(errs...)
+}
+
+// SendDigests messages every mapped reviewer with pending reviews a summary of them,
+// as of now: reviews past their due date, reviews due before the next digest,
+// reviews assigned since the previous one and the rest. It returns how many
+// digests were sent; failed messages are reported but not retried.
+func (s *Service) SendDigests(ctx context.Context, since, now, next time.Time) (int, error) {
+	userIDs := make([]string, 0, len(s.users))
+	for userID := range s.users {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	reviews, err := s.prRepo.GetPendingReviewsByReviewers(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	byUser := make(map[string][]domain.ReviewAssignment)
+	for _, review := range reviews {
+		review.DueAt = review.AssignedAt.Add(s.reviewSLA)
+		byUser[review.UserID] = append(byUser[review.UserID], review)
+	}
+
+	var errs []error
+	sent := 0
+	for _, userID := range userIDs {
+		pending := byUser[userID]
+		if len(pending) == 0 {
+			continue
+		}
+		if err := s.post(ctx, userID, s.renderDigest(pending, since, now, next)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// renderDigest lists each pending review once, under the most urgent section it falls in
+func (s *Service) renderDigest(reviews []domain.ReviewAssignment, since, now, next time.Time) string {
+	sections := []struct {
+		title   string
+		reviews []domain.ReviewAssignment
+	}{
+		{title: "Overdue"},
+		{title: "Due before the next digest"},
+		{title: "Newly assigned"},
+		{title: "Still waiting"},
+	}
+	for _, review := range reviews {
+		switch {
+		case !review.DueAt.After(now):
+			sections[0].reviews = append(sections[0].reviews, review)
+		case !next.IsZero() && review.DueAt.Before(next):
+			sections[1].reviews = append(sections[1].reviews, review)
+		case review.AssignedAt.After(since):
+			sections[2].reviews = append(sections[2].reviews, review)
+		default:
+			sections[3].reviews = append(sections[3].reviews, review)
+		}
+	}
+
+	var b strings.Builder
+	noun := "reviews"
+	if len(reviews) == 1 {
+		noun = "review"
+	}
+	fmt.Fprintf(&b, "Your review digest: %d pending %s.", len(reviews), noun)
+	for _, section := range sections {
+		if len(section.reviews) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n*%s*", section.title)
+		for _, review := range section.reviews {
+			fmt.Fprintf(&b, "\n• *%s* (`%s`) by %s, due %s", review.PullRequestName, review.PullRequestID,
+				s.mention(review.AuthorID), review.DueAt.UTC().Format("2006-01-02 15:04 UTC"))
+		}
+	}
+	return b.String()
+}
+
+func (s *Service) pullRequest(ctx context.Context, event domain.Event) (domain.PullRequest, error) {
+	if event.PR != nil {
+		return *event.PR, nil
+	}
+	return s.prRepo.GetPR(ctx, event.PullRequestID)
+}
+
+// post messages userID if they have a Slack mapping
+func (s *Service) post(ctx context.Context, userID, text string) error {
+	slackID, ok := s.users[userID]
+	if !ok {
+		return nil
+	}
+
+	if err := s.messenger.PostMessage(ctx, slackID, text); err != nil {
+		metrics.SlackNotifications.WithLabelValues("failed").Inc()
+		return fmt.Errorf("failed to notify %s: %w", userID, err)
+	}
+	metrics.SlackNotifications.WithLabelValues("sent").Inc()
+	return nil
+}
+
+// mention renders a Slack mention of userID, or the plain ID when unmapped
+func (s *Service) mention(userID string) string {
+	if slackID, ok := s.users[userID]; ok {
+		return fmt.Sprintf("<@%s>", slackID)
+	}
+	return userID
+}
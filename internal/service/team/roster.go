@@ -0,0 +1,278 @@
+package team
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/tracing"
+)
+
+// MaxRosterRows caps the number of members accepted by a single import
+const MaxRosterRows = 1000
+
+// ImportTeam creates or updates a team from a CSV or NDJSON roster in one transaction.
+// Every row is validated first; if any row is invalid nothing is written and a
+// *domain.RosterError lists the problems. Members missing from the roster are kept.
+func (s *Service) ImportTeam(
+	ctx context.Context,
+	teamName string,
+	format domain.RosterFormat,
+	data io.Reader,
+) (domain.RosterImport, error) {
+	ctx, span := tracing.Start(ctx, "team.ImportTeam")
+	defer span.End()
+
+	teamName = strings.TrimSpace(teamName)
+	if teamName == "" || !format.IsValid() {
+		return domain.RosterImport{}, domain.ErrInvalidArgument
+	}
+
+	rows, rowErrs, err := parseRoster(format, data)
+	if err != nil {
+		return domain.RosterImport{}, err
+	}
+	rowErrs = append(rowErrs, validateRoster(rows)...)
+	if len(rowErrs) > 0 {
+		return domain.RosterImport{}, &domain.RosterError{Rows: rowErrs}
+	}
+	if len(rows) == 0 {
+		return domain.RosterImport{}, domain.ErrInvalidArgument
+	}
+
+	result := domain.RosterImport{
+		CreatedUserIDs: make([]string, 0),
+		UpdatedUserIDs: make([]string, 0),
+	}
+	var deactivations []domain.Event
+
+	err = s.transactor.Do(db.WithTxProfile(ctx, db.ProfileBulk), func(txCtx context.Context) error {
+		exists, err := s.teamRepo.TeamExists(txCtx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := s.teamRepo.CreateTeam(txCtx, domain.NewTeam(teamName, nil)); err != nil {
+				return err
+			}
+			result.TeamCreated = true
+		}
+
+		var events []domain.MembershipEvent
+		for _, row := range rows {
+			existing, err := s.userRepo.GetUser(txCtx, row.UserID)
+			user := existing
+			switch {
+			case errors.Is(err, domain.ErrNotFound):
+				existing = domain.User{}
+				user = domain.NewUser(row.UserID, row.Username, teamName, true)
+				result.CreatedUserIDs = append(result.CreatedUserIDs, row.UserID)
+			case err != nil:
+				return err
+			default:
+				user.Username = row.Username
+				user.UpdatedAt = time.Now()
+				result.UpdatedUserIDs = append(result.UpdatedUserIDs, row.UserID)
+			}
+
+			if row.IsActive != nil {
+				user.IsActive = *row.IsActive
+			}
+			if row.Role != "" {
+				user.Role = row.Role
+			}
+
+			if err := s.userRepo.CreateOrUpdateUser(txCtx, user); err != nil {
+				return err
+			}
+			if err := s.userRepo.AddTeamMember(txCtx, teamName, row.UserID); err != nil {
+				return err
+			}
+			events = append(events, memberEvents(teamName, existing, user)...)
+		}
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, events); err != nil {
+			return err
+		}
+		deactivations = domain.NewDeactivationEvents(events)
+		if err := s.publish(txCtx, deactivations...); err != nil {
+			return err
+		}
+
+		result.Team, err = s.teamRepo.GetTeam(txCtx, teamName)
+		return err
+	})
+
+	if err != nil {
+		return domain.RosterImport{}, err
+	}
+	s.notify(ctx, deactivations...)
+
+	return result, nil
+}
+
+// validateRoster checks required fields, roles and duplicate user IDs
+func validateRoster(rows []domain.RosterRow) []domain.RosterRowError {
+	var rowErrs []domain.RosterRowError
+	if len(rows) > MaxRosterRows {
+		return []domain.RosterRowError{{
+			Line:    rows[MaxRosterRows].Line,
+			Message: fmt.Sprintf("roster exceeds %d rows", MaxRosterRows),
+		}}
+	}
+
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		reject := func(message string) {
+			rowErrs = append(rowErrs, domain.RosterRowError{Line: row.Line, UserID: row.UserID, Message: message})
+		}
+
+		switch {
+		case row.UserID == "":
+			reject("user_id is required")
+		case row.Username == "":
+			reject("username is required")
+		case row.Role != "" && !row.Role.IsValid():
+			reject(fmt.Sprintf("unknown role %q", row.Role))
+		default:
+			if line, ok := seen[row.UserID]; ok {
+				reject(fmt.Sprintf("duplicate user_id, first seen on line %d", line))
+				continue
+			}
+			seen[row.UserID] = row.Line
+		}
+	}
+	return rowErrs
+}
+
+// parseRoster decodes rows; malformed rows are reported, unreadable input fails outright
+func parseRoster(format domain.RosterFormat, data io.Reader) ([]domain.RosterRow, []domain.RosterRowError, error) {
+	if format == domain.RosterFormatCSV {
+		return parseRosterCSV(data)
+	}
+	return parseRosterNDJSON(data)
+}
+
+// parseRosterCSV reads a CSV roster with a header row naming the columns
+// user_id, username and optionally is_active and role, in any order.
+func parseRosterCSV(data io.Reader) ([]domain.RosterRow, []domain.RosterRowError, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, domain.ErrInvalidArgument
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["user_id"]; !ok {
+		return nil, nil, domain.ErrInvalidArgument
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, nil, domain.ErrInvalidArgument
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var (
+		rows    []domain.RosterRow
+		rowErrs []domain.RosterRowError
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, domain.ErrInvalidArgument
+			}
+			rowErrs = append(rowErrs, domain.RosterRowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := domain.RosterRow{
+			Line:     line,
+			UserID:   field(record, "user_id"),
+			Username: field(record, "username"),
+			Role:     domain.UserRole(field(record, "role")),
+		}
+		if raw := field(record, "is_active"); raw != "" {
+			isActive, err := strconv.ParseBool(raw)
+			if err != nil {
+				rowErrs = append(rowErrs, domain.RosterRowError{
+					Line:    line,
+					UserID:  row.UserID,
+					Message: fmt.Sprintf("invalid is_active %q", raw),
+				})
+				continue
+			}
+			row.IsActive = &isActive
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrs, nil
+}
+
+type rosterRecord struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive *bool  `json:"is_active"`
+	Role     string `json:"role"`
+}
+
+// parseRosterNDJSON reads one JSON member object per line, skipping blank lines
+func parseRosterNDJSON(data io.Reader) ([]domain.RosterRow, []domain.RosterRowError, error) {
+	scanner := bufio.NewScanner(data)
+
+	var (
+		rows    []domain.RosterRow
+		rowErrs []domain.RosterRowError
+	)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record rosterRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			rowErrs = append(rowErrs, domain.RosterRowError{Line: line, Message: "malformed JSON"})
+			continue
+		}
+
+		rows = append(rows, domain.RosterRow{
+			Line:     line,
+			UserID:   strings.TrimSpace(record.UserID),
+			Username: strings.TrimSpace(record.Username),
+			IsActive: record.IsActive,
+			Role:     domain.UserRole(strings.TrimSpace(record.Role)),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, domain.ErrInvalidArgument
+	}
+
+	return rows, rowErrs, nil
+}
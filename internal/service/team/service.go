@@ -2,67 +2,176 @@ package team
 
 import (
 	"context"
+	"errors"
+	"net/url"
+	"slices"
 	"strings"
+	"time"
 
+	"pr-service/internal/cache"
 	"pr-service/internal/db"
 	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+	"pr-service/internal/pagination"
+	"pr-service/internal/service/assignment"
+	"pr-service/internal/tracing"
 )
 
 type teamRepository interface {
 	CreateTeam(ctx context.Context, team domain.Team) error
 	GetTeam(ctx context.Context, teamName string) (domain.Team, error)
 	TeamExists(ctx context.Context, teamName string) (bool, error)
+	DeleteTeam(ctx context.Context, teamName string) error
+	ListDeletedTeams(ctx context.Context, page pagination.Page) ([]domain.Team, int, error)
+	RestoreTeam(ctx context.Context, teamName string) error
+	ListTeams(ctx context.Context, limit, offset int) ([]domain.TeamSummary, int, error)
+	RenameTeam(ctx context.Context, oldName, newName string) error
+	SetParentTeam(ctx context.Context, teamName, parentTeamName string) error
+	GetAncestorTeamNames(ctx context.Context, teamName string) ([]string, error)
+	GetChildTeamNames(ctx context.Context, teamName string) ([]string, error)
+	GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error)
+	SetTeamSettings(ctx context.Context, settings domain.TeamSettings) error
 }
 
 type userRepository interface {
 	CreateOrUpdateUser(ctx context.Context, user domain.User) error
+	CreateOrUpdateUsers(ctx context.Context, users []domain.User) error
+	AddTeamMember(ctx context.Context, teamName, userID string) error
+	AddTeamMembers(ctx context.Context, teamName string, userIDs []string) error
+	RemoveTeamMember(ctx context.Context, teamName, userID string) error
+	GetUser(ctx context.Context, userID string) (domain.User, error)
+	GetTeamMembers(ctx context.Context, teamName string) ([]domain.User, error)
+	GetTeamTreeMembers(ctx context.Context, teamName string) ([]domain.User, error)
+	DeactivateUsers(ctx context.Context, teamName string, userIDs []string) error
+	MoveTeamMembers(ctx context.Context, fromTeam, toTeam string) error
 }
 
+type prRepository interface {
+	GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID string, userID string) error
+	AddReviewer(ctx context.Context, prID string, userID string) error
+	RecordReassignments(ctx context.Context, reassignments []domain.Reassignment) error
+	GetOpenPRIDsByTeam(ctx context.Context, teamName string) ([]string, error)
+	MovePRsToTeam(ctx context.Context, fromTeam, toTeam string) error
+}
+
+type auditRepository interface {
+	RecordMembershipEvents(ctx context.Context, events []domain.MembershipEvent) error
+	ListMembershipEvents(ctx context.Context, teamName string, limit, offset int) ([]domain.MembershipEvent, int, error)
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event) error
+}
+
+type eventListener interface {
+	Notify(ctx context.Context, events ...domain.Event)
+}
+
+const (
+	// DefaultListLimit is used when the caller does not specify a page size
+	DefaultListLimit = 50
+	// MaxListLimit caps the page size of team listings
+	MaxListLimit = 100
+)
+
 // Service handles team business logic
 type Service struct {
-	teamRepo   teamRepository
-	userRepo   userRepository
-	transactor db.Transactioner
+	teamRepo       teamRepository
+	userRepo       userRepository
+	prRepo         prRepository
+	auditRepo      auditRepository
+	transactor     db.Transactioner
+	assignStrategy *assignment.Strategy
+	statsCache     *cache.Cache
+	userCache      *cache.Cache
+	publishers     []eventPublisher
+	listeners      []eventListener
+}
+
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithStatsCache invalidates c whenever teams or memberships change
+func WithStatsCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.statsCache = c
+	}
+}
+
+// WithUserCache invalidates c whenever teams or memberships change
+func WithUserCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.userCache = c
+	}
+}
+
+// WithEventPublisher publishes reviewer reassignment and user deactivation events
+// with p inside the transaction of the change. It may be given more than once.
+func WithEventPublisher(p eventPublisher) Option {
+	return func(s *Service) {
+		s.publishers = append(s.publishers, p)
+	}
+}
+
+// WithEventListener hands events to l once the change that caused them has
+// committed. l is called on the request path and must not block.
+func WithEventListener(l eventListener) Option {
+	return func(s *Service) {
+		s.listeners = append(s.listeners, l)
+	}
 }
 
 // NewService creates a new team service
 func NewService(
 	teamRepo teamRepository,
 	userRepo userRepository,
+	prRepo prRepository,
+	auditRepo auditRepository,
 	transactor db.Transactioner,
+	assignStrategy *assignment.Strategy,
+	opts ...Option,
 ) *Service {
-	return &Service{
-		teamRepo:   teamRepo,
-		userRepo:   userRepo,
-		transactor: transactor,
+	s := &Service{
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		prRepo:         prRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+		assignStrategy: assignStrategy,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
+}
+
+// invalidateCaches drops the cached reads a write may have made stale
+func (s *Service) invalidateCaches() {
+	s.statsCache.Invalidate()
+	s.userCache.Invalidate()
 }
 
-// CreateTeam creates a team with members in a transaction
+// CreateTeam creates a team with members in a transaction.
+// parentTeamName is optional and must reference an existing team.
 func (s *Service) CreateTeam(
 	ctx context.Context,
 	teamName string,
+	parentTeamName string,
 	members []domain.User,
 ) (domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "team.CreateTeam")
+	defer span.End()
+	defer s.invalidateCaches()
+
 	teamName = strings.TrimSpace(teamName)
-	if teamName == "" || len(members) == 0 {
+	parentTeamName = strings.TrimSpace(parentTeamName)
+	if teamName == "" || len(members) == 0 || teamName == parentTeamName {
 		return domain.Team{}, domain.ErrInvalidArgument
 	}
 
-	for i := range members {
-		members[i].UserID = strings.TrimSpace(members[i].UserID)
-		members[i].Username = strings.TrimSpace(members[i].Username)
-		members[i].TeamName = strings.TrimSpace(members[i].TeamName)
-
-		if members[i].UserID == "" || members[i].Username == "" {
-			return domain.Team{}, domain.ErrInvalidArgument
-		}
-		if members[i].TeamName == "" {
-			members[i].TeamName = teamName
-		}
-		if members[i].TeamName != teamName {
-			return domain.Team{}, domain.ErrInvalidArgument
-		}
+	if err := normalizeMembers(teamName, members); err != nil {
+		return domain.Team{}, err
 	}
 
 	// Check if team already exists
@@ -74,7 +183,18 @@ func (s *Service) CreateTeam(
 		return domain.Team{}, domain.ErrTeamExists
 	}
 
+	if parentTeamName != "" {
+		parentExists, err := s.teamRepo.TeamExists(ctx, parentTeamName)
+		if err != nil {
+			return domain.Team{}, err
+		}
+		if !parentExists {
+			return domain.Team{}, domain.ErrNotFound
+		}
+	}
+
 	team := domain.NewTeam(teamName, members)
+	team.ParentTeamName = parentTeamName
 
 	// Create team and upsert users in transaction
 	err = s.transactor.Do(ctx, func(txCtx context.Context) error {
@@ -83,14 +203,425 @@ func (s *Service) CreateTeam(
 			return err
 		}
 
-		// Upsert all members
+		// Upsert all members; memberships in other teams are kept
+		if err := s.upsertMembers(txCtx, teamName, members); err != nil {
+			return err
+		}
+		events := make([]domain.MembershipEvent, 0, len(members))
 		for _, member := range members {
-			if err := s.userRepo.CreateOrUpdateUser(txCtx, member); err != nil {
+			events = append(events, domain.NewMembershipEvent(teamName, member.UserID, domain.MembershipAdded))
+		}
+
+		return s.auditRepo.RecordMembershipEvents(txCtx, events)
+	})
+
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	return team, nil
+}
+
+// UpsertTeam creates a team like CreateTeam when it does not exist; otherwise it
+// synchronizes the roster in one transaction: listed members are created or
+// updated and joined, members missing from the list leave the team (keeping their
+// other memberships and already assigned reviews). A non-empty parentTeamName is
+// applied to an existing team, an empty one keeps its current parent.
+// The returned flag reports whether the team was created.
+func (s *Service) UpsertTeam(
+	ctx context.Context,
+	teamName string,
+	parentTeamName string,
+	members []domain.User,
+) (domain.Team, bool, error) {
+	ctx, span := tracing.Start(ctx, "team.UpsertTeam")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	teamName = strings.TrimSpace(teamName)
+	parentTeamName = strings.TrimSpace(parentTeamName)
+	if teamName == "" || len(members) == 0 || teamName == parentTeamName {
+		return domain.Team{}, false, domain.ErrInvalidArgument
+	}
+	if err := normalizeMembers(teamName, members); err != nil {
+		return domain.Team{}, false, err
+	}
+
+	var (
+		team          domain.Team
+		created       bool
+		deactivations []domain.Event
+	)
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		exists, err := s.teamRepo.TeamExists(txCtx, teamName)
+		if err != nil {
+			return err
+		}
+
+		var current domain.Team
+		if exists {
+			current, err = s.teamRepo.GetTeam(txCtx, teamName)
+			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		if parentTeamName != "" && parentTeamName != current.ParentTeamName {
+			parentExists, err := s.teamRepo.TeamExists(txCtx, parentTeamName)
+			if err != nil {
+				return err
+			}
+			if !parentExists {
+				return domain.ErrNotFound
+			}
+			ancestors, err := s.teamRepo.GetAncestorTeamNames(txCtx, parentTeamName)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(ancestors, teamName) {
+				return domain.ErrInvalidArgument
+			}
+		}
+
+		if !exists {
+			newTeam := domain.NewTeam(teamName, members)
+			newTeam.ParentTeamName = parentTeamName
+			if err := s.teamRepo.CreateTeam(txCtx, newTeam); err != nil {
+				return err
+			}
+			created = true
+		} else if parentTeamName != "" && parentTeamName != current.ParentTeamName {
+			if err := s.teamRepo.SetParentTeam(txCtx, teamName, parentTeamName); err != nil {
+				return err
+			}
+		}
+
+		previous := make(map[string]domain.User, len(current.Members))
+		for _, member := range current.Members {
+			previous[member.UserID] = member
+		}
+
+		if err := s.upsertMembers(txCtx, teamName, members); err != nil {
+			return err
+		}
+		var events []domain.MembershipEvent
+		listed := make(map[string]struct{}, len(members))
+		for _, member := range members {
+			listed[member.UserID] = struct{}{}
+			events = append(events, memberEvents(teamName, previous[member.UserID], member)...)
+		}
+		for _, member := range current.Members {
+			if _, ok := listed[member.UserID]; ok {
+				continue
+			}
+			if err := s.userRepo.RemoveTeamMember(txCtx, teamName, member.UserID); err != nil {
+				return err
+			}
+			events = append(events, domain.NewMembershipEvent(teamName, member.UserID, domain.MembershipRemoved))
+		}
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, events); err != nil {
+			return err
+		}
+		deactivations = domain.NewDeactivationEvents(events)
+		if err := s.publish(txCtx, deactivations...); err != nil {
+			return err
+		}
+
+		team, err = s.teamRepo.GetTeam(txCtx, teamName)
+		return err
+	})
+
+	if err != nil {
+		return domain.Team{}, false, err
+	}
+	s.notify(ctx, deactivations...)
+
+	return team, created, nil
+}
+
+// AddMember creates a user in an existing team or adds an existing user to it,
+// keeping their other memberships. A nil isActive keeps the current flag (new
+// users start active); an empty role keeps the current role.
+// The returned flag reports whether the user was created.
+func (s *Service) AddMember(
+	ctx context.Context,
+	userID, username, teamName string,
+	isActive *bool,
+	role domain.UserRole,
+) (domain.User, bool, error) {
+	ctx, span := tracing.Start(ctx, "team.AddMember")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	userID = strings.TrimSpace(userID)
+	username = strings.TrimSpace(username)
+	teamName = strings.TrimSpace(teamName)
+	if userID == "" || username == "" || teamName == "" {
+		return domain.User{}, false, domain.ErrInvalidArgument
+	}
+	if role != "" && !role.IsValid() {
+		return domain.User{}, false, domain.ErrInvalidArgument
+	}
+
+	var (
+		user          domain.User
+		created       bool
+		deactivations []domain.Event
+	)
+
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		exists, err := s.teamRepo.TeamExists(txCtx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+
+		existing, err := s.userRepo.GetUser(txCtx, userID)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			created = true
+			existing = domain.User{}
+			user = domain.NewUser(userID, username, teamName, true)
+		case err != nil:
+			return err
+		default:
+			user = existing
+			user.Teams = slices.Clone(existing.Teams)
+			user.JoinTeam(teamName)
+			user.TeamName = teamName
+			user.Username = username
+			user.UpdatedAt = time.Now()
+		}
+
+		if isActive != nil {
+			user.IsActive = *isActive
+		}
+		if role != "" {
+			user.Role = role
+		}
+
+		if err := s.userRepo.CreateOrUpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		if err := s.userRepo.AddTeamMember(txCtx, teamName, userID); err != nil {
+			return err
+		}
+		events := memberEvents(teamName, existing, user)
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, events); err != nil {
+			return err
+		}
+		deactivations = domain.NewDeactivationEvents(events)
+		return s.publish(txCtx, deactivations...)
+	})
+
+	if err != nil {
+		return domain.User{}, false, err
+	}
+	s.notify(ctx, deactivations...)
+
+	return user, created, nil
+}
+
+// GetTeam retrieves a team with its members.
+// When flatten is set, members of all sub-teams are included as well.
+func (s *Service) GetTeam(ctx context.Context, teamName string, flatten bool) (domain.Team, error) {
+	team, err := s.teamRepo.GetTeam(ctx, teamName)
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	if flatten {
+		members, err := s.userRepo.GetTeamTreeMembers(ctx, teamName)
+		if err != nil {
+			return domain.Team{}, err
+		}
+		team.Members = members
+	}
+
+	return team, nil
+}
+
+// GetSettings returns the settings of a team
+func (s *Service) GetSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	teamName = strings.TrimSpace(teamName)
+	if teamName == "" {
+		return domain.TeamSettings{}, domain.ErrInvalidArgument
+	}
+	return s.teamRepo.GetTeamSettings(ctx, teamName)
+}
+
+// UpdateSettings replaces the settings of a team. A notification channel must
+// name a known channel type and an http(s) incoming webhook URL; nil removes it.
+func (s *Service) UpdateSettings(ctx context.Context, settings domain.TeamSettings) (domain.TeamSettings, error) {
+	ctx, span := tracing.Start(ctx, "team.UpdateSettings")
+	defer span.End()
+
+	settings.TeamName = strings.TrimSpace(settings.TeamName)
+	if settings.TeamName == "" {
+		return domain.TeamSettings{}, domain.ErrInvalidArgument
+	}
+	if err := domain.AuthorizeLead(ctx, s.userRepo.GetUser, settings.TeamName); err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if settings.NotificationChannel != nil {
+		ch := *settings.NotificationChannel
+		ch.WebhookURL = strings.TrimSpace(ch.WebhookURL)
+		u, err := url.Parse(ch.WebhookURL)
+		if !ch.Type.IsValid() || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return domain.TeamSettings{}, domain.ErrInvalidArgument
+		}
+		settings.NotificationChannel = &ch
+	}
+
+	if err := s.teamRepo.SetTeamSettings(ctx, settings); err != nil {
+		return domain.TeamSettings{}, err
+	}
+	return settings, nil
+}
+
+// SetParentTeam nests a team under a parent team; an empty parent detaches it
+func (s *Service) SetParentTeam(ctx context.Context, teamName, parentTeamName string) (domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "team.SetParentTeam")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	teamName = strings.TrimSpace(teamName)
+	parentTeamName = strings.TrimSpace(parentTeamName)
+	if teamName == "" || teamName == parentTeamName {
+		return domain.Team{}, domain.ErrInvalidArgument
+	}
+
+	var team domain.Team
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		if parentTeamName != "" {
+			exists, err := s.teamRepo.TeamExists(txCtx, parentTeamName)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return domain.ErrNotFound
+			}
+
+			// Reject cycles: the new parent must not be a descendant of the team
+			ancestors, err := s.teamRepo.GetAncestorTeamNames(txCtx, parentTeamName)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(ancestors, teamName) {
+				return domain.ErrInvalidArgument
+			}
+		}
+
+		if err := s.teamRepo.SetParentTeam(txCtx, teamName, parentTeamName); err != nil {
+			return err
+		}
+
+		var err error
+		team, err = s.teamRepo.GetTeam(txCtx, teamName)
+		return err
+	})
+
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	return team, nil
+}
+
+// ListTeams returns a page of teams with member counts and the total number of teams
+func (s *Service) ListTeams(ctx context.Context, limit, offset int) ([]domain.TeamSummary, int, error) {
+	if limit < 0 || offset < 0 || limit > MaxListLimit {
+		return nil, 0, domain.ErrInvalidArgument
+	}
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+
+	return s.teamRepo.ListTeams(ctx, limit, offset)
+}
+
+// ListDeletedTeams returns a page of deleted teams, most recently deleted
+// first unless the page asks otherwise, with the total number of deleted teams
+// and the next page's cursor
+func (s *Service) ListDeletedTeams(ctx context.Context, page pagination.Page) (pagination.Result[domain.Team], error) {
+	page, err := page.Normalize(pagination.OrderDesc)
+	if err != nil {
+		return pagination.Result[domain.Team]{}, err
+	}
+	if page.After != nil {
+		if _, err := page.After.Time(); err != nil {
+			return pagination.Result[domain.Team]{}, err
+		}
+	}
+
+	teams, total, err := s.teamRepo.ListDeletedTeams(ctx, page)
+	if err != nil {
+		return pagination.Result[domain.Team]{}, err
+	}
+	teams, next := pagination.Trim(teams, page, func(t domain.Team) pagination.Cursor {
+		return pagination.TimeCursor(*t.DeletedAt, t.TeamName)
+	})
+	return pagination.Result[domain.Team]{Items: teams, Total: total, NextCursor: next}, nil
+}
+
+// RestoreTeam undoes the deletion of a team. The team comes back with its
+// settings but without members, which were moved or detached on delete.
+func (s *Service) RestoreTeam(ctx context.Context, teamName string) (domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "team.RestoreTeam")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	teamName = strings.TrimSpace(teamName)
+	if teamName == "" {
+		return domain.Team{}, domain.ErrInvalidArgument
+	}
+
+	var team domain.Team
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		if err := s.teamRepo.RestoreTeam(txCtx, teamName); err != nil {
+			return err
+		}
+		var err error
+		team, err = s.teamRepo.GetTeam(txCtx, teamName)
+		return err
+	})
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	return team, nil
+}
+
+// RenameTeam renames a team and its members' references in one transaction
+func (s *Service) RenameTeam(ctx context.Context, oldName, newName string) (domain.Team, error) {
+	ctx, span := tracing.Start(ctx, "team.RenameTeam")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	oldName = strings.TrimSpace(oldName)
+	newName = strings.TrimSpace(newName)
+	if oldName == "" || newName == "" || oldName == newName {
+		return domain.Team{}, domain.ErrInvalidArgument
+	}
+
+	var team domain.Team
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		exists, err := s.teamRepo.TeamExists(txCtx, newName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrTeamExists
+		}
+
+		if err := s.teamRepo.RenameTeam(txCtx, oldName, newName); err != nil {
+			return err
+		}
+
+		team, err = s.teamRepo.GetTeam(txCtx, newName)
+		return err
 	})
 
 	if err != nil {
@@ -100,7 +631,420 @@ func (s *Service) CreateTeam(
 	return team, nil
 }
 
-// GetTeam retrieves a team with its members
-func (s *Service) GetTeam(ctx context.Context, teamName string) (domain.Team, error) {
-	return s.teamRepo.GetTeam(ctx, teamName)
+// DeleteTeam removes a team in a single transaction.
+// When targetTeam is set, members are moved there and keep their reviews.
+// Otherwise members without another team are deactivated and their open
+// reviews are handed to an active member of the PR's team, or closed if
+// nobody is available. Members of other teams just lose this membership.
+func (s *Service) DeleteTeam(
+	ctx context.Context,
+	teamName, targetTeam string,
+) (domain.Team, []domain.Reassignment, error) {
+	ctx, span := tracing.Start(ctx, "team.DeleteTeam")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	teamName = strings.TrimSpace(teamName)
+	targetTeam = strings.TrimSpace(targetTeam)
+	if teamName == "" || teamName == targetTeam {
+		return domain.Team{}, nil, domain.ErrInvalidArgument
+	}
+
+	var (
+		team          domain.Team
+		reassignments = make([]domain.Reassignment, 0)
+		deactivations []domain.Event
+	)
+
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		var err error
+		team, err = s.teamRepo.GetTeam(txCtx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if targetTeam != "" {
+			exists, err := s.teamRepo.TeamExists(txCtx, targetTeam)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return domain.ErrNotFound
+			}
+
+			if err := s.userRepo.MoveTeamMembers(txCtx, teamName, targetTeam); err != nil {
+				return err
+			}
+			if err := s.auditRepo.RecordMembershipEvents(txCtx, moveEvents(team.Members, teamName, targetTeam)); err != nil {
+				return err
+			}
+			for i := range team.Members {
+				team.Members[i].Teams = slices.DeleteFunc(team.Members[i].Teams, func(name string) bool {
+					return name == teamName
+				})
+				team.Members[i].JoinTeam(targetTeam)
+				team.Members[i].TeamName = targetTeam
+			}
+
+			return s.teamRepo.DeleteTeam(txCtx, teamName)
+		}
+
+		memberIDs := make([]string, 0, len(team.Members))
+		for _, m := range team.Members {
+			if !hasOtherTeam(m, teamName) {
+				memberIDs = append(memberIDs, m.UserID)
+			}
+		}
+
+		if err := s.userRepo.DeactivateUsers(txCtx, teamName, memberIDs); err != nil {
+			return err
+		}
+
+		events := make([]domain.MembershipEvent, 0, len(team.Members))
+		for _, m := range team.Members {
+			if m.IsActive && !hasOtherTeam(m, teamName) {
+				events = append(events, domain.NewMembershipEvent(teamName, m.UserID, domain.MembershipDeactivated))
+			}
+			events = append(events, domain.NewMembershipEvent(teamName, m.UserID, domain.MembershipRemoved))
+		}
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, events); err != nil {
+			return err
+		}
+		deactivations = domain.NewDeactivationEvents(events)
+		if err := s.publish(txCtx, deactivations...); err != nil {
+			return err
+		}
+
+		if err := s.teamRepo.DeleteTeam(txCtx, teamName); err != nil {
+			return err
+		}
+
+		for i := range team.Members {
+			member := &team.Members[i]
+			stayed := hasOtherTeam(*member, teamName)
+			member.Teams = slices.DeleteFunc(member.Teams, func(name string) bool {
+				return name == teamName
+			})
+			member.TeamName = ""
+			if stayed {
+				member.TeamName = member.Teams[0]
+				continue
+			}
+			member.IsActive = false
+		}
+
+		for _, userID := range memberIDs {
+			handed, err := s.handOffReviews(txCtx, userID, teamName)
+			if err != nil {
+				return err
+			}
+			reassignments = append(reassignments, handed...)
+		}
+
+		if err := s.prRepo.RecordReassignments(txCtx, reassignments); err != nil {
+			return err
+		}
+		return s.publish(txCtx, domain.NewReassignmentEvents(reassignments)...)
+	})
+
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	metrics.RecordReassignments(reassignments)
+	s.notify(ctx, append(deactivations, domain.NewReassignmentEvents(reassignments)...)...)
+
+	return team, reassignments, nil
+}
+
+// MergeTeams folds sourceTeam into targetTeam in a single transaction.
+// Members and PRs move to the target with reviewers untouched, sub-teams are
+// re-parented under the target, and the source team is removed. The target
+// keeps its own parent unless it was nested in the source, in which case it
+// takes the source's place. With dryRun nothing is written and the returned
+// report describes what would happen.
+func (s *Service) MergeTeams(
+	ctx context.Context,
+	sourceTeam, targetTeam string,
+	dryRun bool,
+) (domain.TeamMerge, error) {
+	ctx, span := tracing.Start(ctx, "team.MergeTeams")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	sourceTeam = strings.TrimSpace(sourceTeam)
+	targetTeam = strings.TrimSpace(targetTeam)
+	if sourceTeam == "" || targetTeam == "" || sourceTeam == targetTeam {
+		return domain.TeamMerge{}, domain.ErrInvalidArgument
+	}
+
+	merge := domain.TeamMerge{
+		SourceTeam:      sourceTeam,
+		TargetTeam:      targetTeam,
+		DryRun:          dryRun,
+		MovedUserIDs:    make([]string, 0),
+		SharedUserIDs:   make([]string, 0),
+		ReparentedTeams: make([]string, 0),
+	}
+
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		source, err := s.teamRepo.GetTeam(txCtx, sourceTeam)
+		if err != nil {
+			return err
+		}
+		target, err := s.teamRepo.GetTeam(txCtx, targetTeam)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range source.Members {
+			if target.HasMember(member.UserID) {
+				merge.SharedUserIDs = append(merge.SharedUserIDs, member.UserID)
+			} else {
+				merge.MovedUserIDs = append(merge.MovedUserIDs, member.UserID)
+			}
+		}
+
+		children, err := s.teamRepo.GetChildTeamNames(txCtx, sourceTeam)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if child != targetTeam {
+				merge.ReparentedTeams = append(merge.ReparentedTeams, child)
+			}
+		}
+
+		prIDs, err := s.prRepo.GetOpenPRIDsByTeam(txCtx, sourceTeam)
+		if err != nil {
+			return err
+		}
+		merge.MovedPRIDs = append(make([]string, 0, len(prIDs)), prIDs...)
+
+		targetParent := target.ParentTeamName
+		if targetParent == sourceTeam {
+			targetParent = source.ParentTeamName
+		}
+
+		if dryRun {
+			merge.Team = target
+			merge.Team.ParentTeamName = targetParent
+			return nil
+		}
+
+		if err := s.userRepo.MoveTeamMembers(txCtx, sourceTeam, targetTeam); err != nil {
+			return err
+		}
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, moveEvents(source.Members, sourceTeam, targetTeam)); err != nil {
+			return err
+		}
+		for _, child := range merge.ReparentedTeams {
+			if err := s.teamRepo.SetParentTeam(txCtx, child, targetTeam); err != nil {
+				return err
+			}
+		}
+		if targetParent != target.ParentTeamName {
+			if err := s.teamRepo.SetParentTeam(txCtx, targetTeam, targetParent); err != nil {
+				return err
+			}
+		}
+		if err := s.prRepo.MovePRsToTeam(txCtx, sourceTeam, targetTeam); err != nil {
+			return err
+		}
+		if err := s.teamRepo.DeleteTeam(txCtx, sourceTeam); err != nil {
+			return err
+		}
+
+		merge.Team, err = s.teamRepo.GetTeam(txCtx, targetTeam)
+		return err
+	})
+
+	if err != nil {
+		return domain.TeamMerge{}, err
+	}
+
+	return merge, nil
+}
+
+// GetAuditLog returns a page of a team's membership audit log, newest first,
+// and the total number of entries. History of deleted teams stays available.
+func (s *Service) GetAuditLog(ctx context.Context, teamName string, limit, offset int) ([]domain.MembershipEvent, int, error) {
+	teamName = strings.TrimSpace(teamName)
+	if teamName == "" || limit < 0 || offset < 0 || limit > MaxListLimit {
+		return nil, 0, domain.ErrInvalidArgument
+	}
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+
+	return s.auditRepo.ListMembershipEvents(ctx, teamName, limit, offset)
+}
+
+// memberEvents describes how a user's membership in teamName changed from
+// before (the zero User for unknown users) to after
+func memberEvents(teamName string, before, after domain.User) []domain.MembershipEvent {
+	var events []domain.MembershipEvent
+	if !before.IsMemberOf(teamName) {
+		events = append(events, domain.NewMembershipEvent(teamName, after.UserID, domain.MembershipAdded))
+	}
+	if before.UserID != "" && before.IsActive != after.IsActive {
+		before.Teams = slices.Clone(before.Teams)
+		before.JoinTeam(teamName)
+		events = append(events, domain.ActivityEvents(before, after.IsActive)...)
+	}
+	return events
+}
+
+// moveEvents records members of fromTeam moving to toTeam; members that
+// already belong to toTeam only leave fromTeam
+func moveEvents(members []domain.User, fromTeam, toTeam string) []domain.MembershipEvent {
+	events := make([]domain.MembershipEvent, 0, len(members))
+	for _, m := range members {
+		if m.IsMemberOf(toTeam) {
+			events = append(events, domain.NewMembershipEvent(fromTeam, m.UserID, domain.MembershipRemoved))
+			continue
+		}
+		events = append(events, domain.NewMembershipMove(fromTeam, toTeam, m.UserID))
+	}
+	return events
+}
+
+// upsertMembers creates or updates members and joins them to the team, with
+// one statement for each
+func (s *Service) upsertMembers(ctx context.Context, teamName string, members []domain.User) error {
+	if err := s.userRepo.CreateOrUpdateUsers(ctx, members); err != nil {
+		return err
+	}
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	return s.userRepo.AddTeamMembers(ctx, teamName, userIDs)
+}
+
+// normalizeMembers trims member fields, defaults their team and role and
+// rejects members that are incomplete or belong to another team
+func normalizeMembers(teamName string, members []domain.User) error {
+	for i := range members {
+		members[i].UserID = strings.TrimSpace(members[i].UserID)
+		members[i].Username = strings.TrimSpace(members[i].Username)
+		members[i].TeamName = strings.TrimSpace(members[i].TeamName)
+
+		if members[i].UserID == "" || members[i].Username == "" {
+			return domain.ErrInvalidArgument
+		}
+		if members[i].TeamName == "" {
+			members[i].TeamName = teamName
+		}
+		if members[i].TeamName != teamName {
+			return domain.ErrInvalidArgument
+		}
+		if members[i].Role == "" {
+			members[i].Role = domain.UserRoleMember
+		}
+		if !members[i].Role.IsValid() {
+			return domain.ErrInvalidArgument
+		}
+	}
+
+	return nil
+}
+
+// hasOtherTeam reports whether user belongs to any team besides teamName
+func hasOtherTeam(user domain.User, teamName string) bool {
+	for _, name := range user.Teams {
+		if name != teamName {
+			return true
+		}
+	}
+	return false
+}
+
+// handOffReviews replaces userID on every open PR it reviews with an active
+// member of the PR's team, removing the assignment when none is left. PRs of
+// deletedTeam, or without a team, fall back to the author's team.
+func (s *Service) handOffReviews(ctx context.Context, userID, deletedTeam string) ([]domain.Reassignment, error) {
+	prs, err := s.prRepo.GetOpenPRsByReviewers(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	reassignments := make([]domain.Reassignment, 0, len(prs))
+	for _, pr := range prs {
+		prID := pr.PullRequestID
+
+		poolTeam := pr.TeamName
+		if poolTeam == "" || poolTeam == deletedTeam {
+			// A deleted author has no team to pick a replacement from
+			author, err := s.userRepo.GetUser(ctx, pr.AuthorID)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return nil, err
+			}
+			poolTeam = author.TeamName
+		}
+
+		newUserID := ""
+		if poolTeam != "" {
+			members, err := s.userRepo.GetTeamMembers(ctx, poolTeam)
+			if err != nil {
+				return nil, err
+			}
+
+			exclude := slices.Clone(pr.AssignedReviewers)
+			exclude = append(exclude, pr.AuthorID)
+
+			candidate, err := s.assignStrategy.SelectReplacementReviewer(
+				ctx,
+				domain.Team{TeamName: poolTeam, Members: members},
+				exclude,
+			)
+			switch {
+			case err == nil:
+				newUserID = candidate
+			case !errors.Is(err, domain.ErrNoCandidate):
+				return nil, err
+			}
+		}
+
+		if err := s.prRepo.RemoveReviewer(ctx, prID, userID); err != nil {
+			return nil, err
+		}
+		if newUserID != "" {
+			if err := s.prRepo.AddReviewer(ctx, prID, newUserID); err != nil {
+				return nil, err
+			}
+		}
+
+		reassignments = append(reassignments, domain.Reassignment{
+			PullRequestID: prID,
+			OldUserID:     userID,
+			NewUserID:     newUserID,
+		})
+	}
+
+	return reassignments, nil
+}
+
+// publish hands events to the configured publishers
+func (s *Service) publish(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	domain.SetEventOrg(ctx, events)
+	for _, p := range s.publishers {
+		if err := p.Publish(ctx, events...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify hands committed events to the configured listeners
+func (s *Service) notify(ctx context.Context, events ...domain.Event) {
+	if len(events) == 0 {
+		return
+	}
+	domain.SetEventOrg(ctx, events)
+	for _, l := range s.listeners {
+		l.Notify(ctx, events...)
+	}
 }
@@ -0,0 +1,130 @@
+package teamchannel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+)
+
+// Channel posts a message to a team chat channel through its incoming webhook
+type Channel interface {
+	Post(ctx context.Context, webhookURL, text string) error
+}
+
+type teamRepository interface {
+	GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error)
+}
+
+type prRepository interface {
+	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
+}
+
+// queueSize bounds the events waiting to be posted; further events are dropped
+const queueSize = 1024
+
+// Service posts PR activity to the chat channel configured in the settings of
+// the PR's team. Channels maps each channel type to the tool posting to it;
+// teams with a channel of an unsupported type are skipped.
+type Service struct {
+	channels map[domain.NotificationChannelType]Channel
+	teamRepo teamRepository
+	prRepo   prRepository
+	queue    chan domain.Event
+}
+
+// NewService creates a new team channel notification service
+func NewService(channels map[domain.NotificationChannelType]Channel, teamRepo teamRepository, prRepo prRepository) *Service {
+	return &Service{
+		channels: channels,
+		teamRepo: teamRepo,
+		prRepo:   prRepo,
+		queue:    make(chan domain.Event, queueSize),
+	}
+}
+
+// Notify queues committed events for posting without blocking the caller.
+// Events are dropped when the queue is full.
+func (s *Service) Notify(_ context.Context, events ...domain.Event) {
+	for _, event := range events {
+		select {
+		case s.queue <- event:
+		default:
+			metrics.TeamChannelMessages.WithLabelValues("unknown", "dropped").Inc()
+		}
+	}
+}
+
+// Pending returns the queue of events waiting to be posted
+func (s *Service) Pending() <-chan domain.Event {
+	return s.queue
+}
+
+// Send posts the event to the channel of the PR's team, if it has one. Initial
+// assignments are covered by the pr.created message, which lists the reviewers.
+func (s *Service) Send(ctx context.Context, event domain.Event) error {
+	var pr domain.PullRequest
+	switch event.Type {
+	case domain.EventPRCreated, domain.EventPRMerged:
+		if event.PR == nil {
+			return nil
+		}
+		pr = *event.PR
+	case domain.EventReviewerReassigned:
+		var err error
+		if pr, err = s.prRepo.GetPR(ctx, event.PullRequestID); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if pr.TeamName == "" {
+		return nil
+	}
+
+	settings, err := s.teamRepo.GetTeamSettings(ctx, pr.TeamName)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ch := settings.NotificationChannel
+	if ch == nil {
+		return nil
+	}
+	channel, ok := s.channels[ch.Type]
+	if !ok {
+		return nil
+	}
+
+	if err := channel.Post(ctx, ch.WebhookURL, message(event, pr)); err != nil {
+		metrics.TeamChannelMessages.WithLabelValues(string(ch.Type), "failed").Inc()
+		return fmt.Errorf("failed to post to the %s channel of %s: %w", ch.Type, pr.TeamName, err)
+	}
+	metrics.TeamChannelMessages.WithLabelValues(string(ch.Type), "sent").Inc()
+	return nil
+}
+
+// message renders event as plain text, which every channel type displays as is
+func message(event domain.Event, pr domain.PullRequest) string {
+	title := fmt.Sprintf("Pull request %q (%s)", pr.PullRequestName, pr.PullRequestID)
+	switch event.Type {
+	case domain.EventPRCreated:
+		reviewers := "none available"
+		if len(pr.AssignedReviewers) > 0 {
+			reviewers = strings.Join(pr.AssignedReviewers, ", ")
+		}
+		return fmt.Sprintf("%s by %s is waiting for review. Reviewers: %s.", title, pr.AuthorID, reviewers)
+	case domain.EventReviewerReassigned:
+		if event.ReviewerID == "" {
+			return fmt.Sprintf("%s: reviewer %s was removed and no replacement was available.", title, event.OldReviewerID)
+		}
+		return fmt.Sprintf("%s: reviewer %s was replaced by %s.", title, event.OldReviewerID, event.ReviewerID)
+	default:
+		return fmt.Sprintf("%s by %s was merged.", title, pr.AuthorID)
+	}
+}
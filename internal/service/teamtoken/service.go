@@ -0,0 +1,164 @@
+package teamtoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
+)
+
+type teamTokenRepository interface {
+	CreateTeamToken(ctx context.Context, token domain.TeamToken, hash string) (domain.TeamToken, error)
+	GetTeamToken(ctx context.Context, id int64) (domain.TeamToken, error)
+	GetTeamTokenByHash(ctx context.Context, hash string) (domain.TeamToken, error)
+	ListTeamTokens(ctx context.Context, teamName string, page pagination.Page) ([]domain.TeamToken, int, error)
+	DeleteTeamToken(ctx context.Context, id int64) error
+}
+
+type teamRepository interface {
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+}
+
+type userRepository interface {
+	GetUser(ctx context.Context, userID string) (domain.User, error)
+}
+
+const (
+	// secretBytes is the size of the random part of generated tokens
+	secretBytes = 32
+	// maxNameLength matches the name column
+	maxNameLength = 100
+)
+
+// Service issues bearer tokens restricted to one team and authenticates them.
+// Leads manage the tokens of their teams and admins those of any team.
+type Service struct {
+	repo     teamTokenRepository
+	teamRepo teamRepository
+	userRepo userRepository
+	now      func() time.Time
+}
+
+// NewService creates a new team token service
+func NewService(repo teamTokenRepository, teamRepo teamRepository, userRepo userRepository) *Service {
+	return &Service{
+		repo:     repo,
+		teamRepo: teamRepo,
+		userRepo: userRepo,
+		now:      time.Now,
+	}
+}
+
+// Issue creates a token for teamName and returns it with its secret, which is
+// not stored and cannot be shown again. A positive ttl makes the token expire.
+func (s *Service) Issue(ctx context.Context, teamName, name string, ttl time.Duration) (domain.TeamToken, string, error) {
+	teamName = strings.TrimSpace(teamName)
+	name = strings.TrimSpace(name)
+	var v domain.Validator
+	v.Required(teamName, "team_name")
+	v.Required(name, "name")
+	v.Check(len(name) <= maxNameLength, "name", fmt.Sprintf("must be at most %d characters", maxNameLength))
+	v.Check(ttl >= 0, "expires_in", "must not be negative")
+	if err := v.Err(); err != nil {
+		return domain.TeamToken{}, "", err
+	}
+	if err := domain.AuthorizeLead(ctx, s.userRepo.GetUser, teamName); err != nil {
+		return domain.TeamToken{}, "", err
+	}
+
+	exists, err := s.teamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return domain.TeamToken{}, "", err
+	}
+	if !exists {
+		return domain.TeamToken{}, "", domain.ErrNotFound
+	}
+
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return domain.TeamToken{}, "", fmt.Errorf("failed to generate team token: %w", err)
+	}
+	secret := domain.TeamTokenPrefix + hex.EncodeToString(buf)
+
+	token := domain.TeamToken{TeamName: teamName, Name: name, CreatedAt: s.now()}
+	if ttl > 0 {
+		expiresAt := token.CreatedAt.Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	token, err = s.repo.CreateTeamToken(ctx, token, hashSecret(secret))
+	if err != nil {
+		return domain.TeamToken{}, "", err
+	}
+	return token, secret, nil
+}
+
+// List returns a page of the tokens of teamName, without their secrets,
+// oldest first unless the page asks otherwise
+func (s *Service) List(ctx context.Context, teamName string, page pagination.Page) (pagination.Result[domain.TeamToken], error) {
+	page, err := page.Normalize(pagination.OrderAsc)
+	if err != nil {
+		return pagination.Result[domain.TeamToken]{}, err
+	}
+	teamName = strings.TrimSpace(teamName)
+	if teamName == "" {
+		return pagination.Result[domain.TeamToken]{}, domain.NewValidationError("team_name", "must not be empty")
+	}
+	if err := domain.AuthorizeLead(ctx, s.userRepo.GetUser, teamName); err != nil {
+		return pagination.Result[domain.TeamToken]{}, err
+	}
+
+	tokens, total, err := s.repo.ListTeamTokens(ctx, teamName, page)
+	if err != nil {
+		return pagination.Result[domain.TeamToken]{}, err
+	}
+	tokens, next := pagination.Trim(tokens, page, func(t domain.TeamToken) pagination.Cursor {
+		id := strconv.FormatInt(t.ID, 10)
+		return pagination.Cursor{Key: id, ID: id}
+	})
+	return pagination.Result[domain.TeamToken]{Items: tokens, Total: total, NextCursor: next}, nil
+}
+
+// Revoke deletes a token, which stops authenticating at once
+func (s *Service) Revoke(ctx context.Context, id int64) (domain.TeamToken, error) {
+	token, err := s.repo.GetTeamToken(ctx, id)
+	if err != nil {
+		return domain.TeamToken{}, err
+	}
+	if err := domain.AuthorizeLead(ctx, s.userRepo.GetUser, token.TeamName); err != nil {
+		return domain.TeamToken{}, err
+	}
+	if err := s.repo.DeleteTeamToken(ctx, id); err != nil {
+		return domain.TeamToken{}, err
+	}
+	return token, nil
+}
+
+// Authenticate resolves a team token to its caller
+func (s *Service) Authenticate(ctx context.Context, secret string) (domain.Principal, error) {
+	token, err := s.repo.GetTeamTokenByHash(ctx, hashSecret(secret))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Principal{}, errors.New("unknown team token")
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+	if token.IsExpired(s.now()) {
+		return domain.Principal{}, errors.New("team token has expired")
+	}
+	return token.Principal(), nil
+}
+
+// hashSecret derives the stored lookup key of a token. Tokens carry 256 random
+// bits, so a fast unsalted hash is enough to keep them out of the database.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
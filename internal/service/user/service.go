@@ -2,12 +2,18 @@ package user
 
 import (
 	"context"
+	"errors"
 	"slices"
 	"strings"
+	"time"
 
+	"pr-service/internal/cache"
 	"pr-service/internal/db"
 	"pr-service/internal/domain"
+	"pr-service/internal/metrics"
+	"pr-service/internal/pagination"
 	"pr-service/internal/service/assignment"
+	"pr-service/internal/tracing"
 )
 
 type userRepository interface {
@@ -15,37 +21,133 @@ type userRepository interface {
 	UpdateUser(ctx context.Context, user domain.User) error
 	GetTeamMembers(ctx context.Context, teamName string) ([]domain.User, error)
 	DeactivateUsers(ctx context.Context, teamName string, userIDs []string) error
+	ActivateUsers(ctx context.Context, teamName string, userIDs []string) error
+	SoftDeleteUser(ctx context.Context, userID string) error
+	ListDeletedUsers(ctx context.Context, page pagination.Page) ([]domain.User, int, error)
+	RestoreUser(ctx context.Context, userID string) error
+	TouchLastSeen(ctx context.Context, userID string, at time.Time) error
+	ListDormantUsers(ctx context.Context, seenBefore time.Time, limit, offset int) ([]domain.User, int, error)
 }
 
 type prRepository interface {
 	GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
-	GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error)
-	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
+	GetPendingReviewsByReviewer(ctx context.Context, userID string) ([]domain.ReviewAssignment, error)
+	GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error)
 	RemoveReviewer(ctx context.Context, prID string, userID string) error
 	AddReviewer(ctx context.Context, prID string, userID string) error
+	ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error
+	RecordReassignments(ctx context.Context, reassignments []domain.Reassignment) error
 }
 
+type auditRepository interface {
+	RecordMembershipEvents(ctx context.Context, events []domain.MembershipEvent) error
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event) error
+}
+
+type eventListener interface {
+	Notify(ctx context.Context, events ...domain.Event)
+}
+
+const (
+	// DefaultListLimit is used when the caller does not specify a page size
+	DefaultListLimit = 50
+	// MaxListLimit caps the page size of user listings
+	MaxListLimit = 100
+	// DefaultDormantAfter is the dormancy threshold of the report when neither
+	// the caller nor the assignment strategy sets one
+	DefaultDormantAfter = 30 * 24 * time.Hour
+	// DefaultReviewSLA is the first-review SLA that sets review due dates when none is configured
+	DefaultReviewSLA = 24 * time.Hour
+)
+
 // Service handles user business logic
 type Service struct {
 	userRepo       userRepository
 	prRepo         prRepository
+	auditRepo      auditRepository
 	transactor     db.Transactioner
 	assignStrategy *assignment.Strategy
+	statsCache     *cache.Cache
+	userCache      *cache.Cache
+	reviewSLA      time.Duration
+	publishers     []eventPublisher
+	listeners      []eventListener
+}
+
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithStatsCache invalidates c whenever users change in a way visible in stats
+func WithStatsCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.statsCache = c
+	}
+}
+
+// WithUserCache invalidates c whenever users or their memberships change.
+// Heartbeats don't, so a cached last-seen time may be one TTL old.
+func WithUserCache(c *cache.Cache) Option {
+	return func(s *Service) {
+		s.userCache = c
+	}
+}
+
+// WithReviewSLA sets the first-review SLA that review due dates are computed
+// from; non-positive values keep DefaultReviewSLA
+func WithReviewSLA(sla time.Duration) Option {
+	return func(s *Service) {
+		if sla > 0 {
+			s.reviewSLA = sla
+		}
+	}
+}
+
+// WithEventPublisher publishes reviewer reassignment and user deactivation events
+// with p inside the transaction of the change. It may be given more than once.
+func WithEventPublisher(p eventPublisher) Option {
+	return func(s *Service) {
+		s.publishers = append(s.publishers, p)
+	}
+}
+
+// WithEventListener hands events to l once the change that caused them has
+// committed. l is called on the request path and must not block.
+func WithEventListener(l eventListener) Option {
+	return func(s *Service) {
+		s.listeners = append(s.listeners, l)
+	}
 }
 
 // NewService creates a new user service
 func NewService(
 	userRepo userRepository,
 	prRepo prRepository,
+	auditRepo auditRepository,
 	transactor db.Transactioner,
 	assignStrategy *assignment.Strategy,
+	opts ...Option,
 ) *Service {
-	return &Service{
+	s := &Service{
 		userRepo:       userRepo,
 		prRepo:         prRepo,
+		auditRepo:      auditRepo,
 		transactor:     transactor,
 		assignStrategy: assignStrategy,
+		reviewSLA:      DefaultReviewSLA,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
+}
+
+// invalidateCaches drops the cached reads a write may have made stale
+func (s *Service) invalidateCaches() {
+	s.statsCache.Invalidate()
+	s.userCache.Invalidate()
 }
 
 // SetIsActive updates user's active status
@@ -54,17 +156,76 @@ func (s *Service) SetIsActive(
 	userID string,
 	isActive bool,
 ) (domain.User, error) {
+	ctx, span := tracing.Start(ctx, "user.SetIsActive")
+	defer span.End()
+	defer s.invalidateCaches()
+
 	userID = strings.TrimSpace(userID)
 	if userID == "" {
 		return domain.User{}, domain.ErrInvalidArgument
 	}
 
+	var (
+		user   domain.User
+		events []domain.Event
+	)
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetUser(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if err := domain.AuthorizeLead(txCtx, s.userRepo.GetUser, user.MemberTeams()...); err != nil {
+			return err
+		}
+
+		changed := user.IsActive != isActive
+		user.SetIsActive(isActive)
+
+		if err := s.userRepo.UpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+
+		membership := domain.ActivityEvents(user, isActive)
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, membership); err != nil {
+			return err
+		}
+		events = domain.NewDeactivationEvents(membership)
+		return s.publish(txCtx, events...)
+	})
+
+	if err != nil {
+		return domain.User{}, err
+	}
+	s.notify(ctx, events...)
+
+	return user, nil
+}
+
+// SetRole updates user's role within their team
+func (s *Service) SetRole(
+	ctx context.Context,
+	userID string,
+	role domain.UserRole,
+) (domain.User, error) {
+	ctx, span := tracing.Start(ctx, "user.SetRole")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	userID = strings.TrimSpace(userID)
+	if userID == "" || !role.IsValid() {
+		return domain.User{}, domain.ErrInvalidArgument
+	}
+
 	user, err := s.userRepo.GetUser(ctx, userID)
 	if err != nil {
 		return domain.User{}, err
 	}
 
-	user.SetIsActive(isActive)
+	user.SetRole(role)
 
 	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
 		return domain.User{}, err
@@ -73,6 +234,214 @@ func (s *Service) SetIsActive(
 	return user, nil
 }
 
+// DeleteUser soft-deletes a user and hands their open reviews to active members
+// of each PR's team, falling back to the user's own team for PRs without one.
+// Reviews with no available replacement are closed, reported with an empty NewUserID.
+func (s *Service) DeleteUser(
+	ctx context.Context,
+	userID string,
+) (domain.User, []domain.Reassignment, error) {
+	ctx, span := tracing.Start(ctx, "user.DeleteUser")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return domain.User{}, nil, domain.ErrInvalidArgument
+	}
+
+	var (
+		user          domain.User
+		reassignments = make([]domain.Reassignment, 0)
+	)
+
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetUser(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		team := domain.Team{TeamName: user.TeamName}
+		if user.TeamName != "" {
+			team.Members, err = s.userRepo.GetTeamMembers(txCtx, user.TeamName)
+			if err != nil {
+				return err
+			}
+		}
+		pools := map[string]domain.Team{"": team, team.TeamName: team}
+
+		if err := s.userRepo.SoftDeleteUser(txCtx, userID); err != nil {
+			return err
+		}
+		user.MarkDeleted()
+
+		events := make([]domain.MembershipEvent, 0, len(user.Teams))
+		for _, teamName := range user.Teams {
+			events = append(events, domain.NewMembershipEvent(teamName, userID, domain.MembershipRemoved))
+		}
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, events); err != nil {
+			return err
+		}
+
+		prs, err := s.prRepo.GetOpenPRsByReviewers(txCtx, []string{userID})
+		if err != nil {
+			return err
+		}
+
+		for _, pr := range prs {
+			prID := pr.PullRequestID
+			exclude := slices.Clone(pr.AssignedReviewers)
+			exclude = append(exclude, pr.AuthorID)
+
+			pool, err := s.reviewPool(txCtx, pr, pools, nil)
+			if err != nil {
+				return err
+			}
+
+			newUserID, err := s.assignStrategy.SelectReplacementReviewer(txCtx, pool, exclude)
+			if err != nil && !errors.Is(err, domain.ErrNoCandidate) {
+				return err
+			}
+
+			if err := s.prRepo.RemoveReviewer(txCtx, prID, userID); err != nil {
+				return err
+			}
+
+			if newUserID != "" {
+				if err := s.prRepo.AddReviewer(txCtx, prID, newUserID); err != nil {
+					return err
+				}
+			}
+
+			reassignments = append(reassignments, domain.Reassignment{
+				PullRequestID: prID,
+				OldUserID:     userID,
+				NewUserID:     newUserID,
+			})
+		}
+
+		if err := s.prRepo.RecordReassignments(txCtx, reassignments); err != nil {
+			return err
+		}
+		return s.publish(txCtx, domain.NewReassignmentEvents(reassignments)...)
+	})
+
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	metrics.RecordReassignments(reassignments)
+	s.notify(ctx, domain.NewReassignmentEvents(reassignments)...)
+
+	return user, reassignments, nil
+}
+
+// ListDeletedUsers returns a page of deleted users, most recently deleted
+// first unless the page asks otherwise, with the total number of deleted users
+// and the next page's cursor
+func (s *Service) ListDeletedUsers(ctx context.Context, page pagination.Page) (pagination.Result[domain.User], error) {
+	page, err := page.Normalize(pagination.OrderDesc)
+	if err != nil {
+		return pagination.Result[domain.User]{}, err
+	}
+	if page.After != nil {
+		if _, err := page.After.Time(); err != nil {
+			return pagination.Result[domain.User]{}, err
+		}
+	}
+
+	users, total, err := s.userRepo.ListDeletedUsers(ctx, page)
+	if err != nil {
+		return pagination.Result[domain.User]{}, err
+	}
+	users, next := pagination.Trim(users, page, func(u domain.User) pagination.Cursor {
+		return pagination.TimeCursor(*u.DeletedAt, u.UserID)
+	})
+	return pagination.Result[domain.User]{Items: users, Total: total, NextCursor: next}, nil
+}
+
+// RestoreUser undoes the deletion of a user. The user comes back inactive in
+// the teams it belonged to, so it gets no reviews until it is activated.
+func (s *Service) RestoreUser(ctx context.Context, userID string) (domain.User, error) {
+	ctx, span := tracing.Start(ctx, "user.RestoreUser")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return domain.User{}, domain.ErrInvalidArgument
+	}
+
+	var user domain.User
+	err := s.transactor.Do(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.RestoreUser(txCtx, userID); err != nil {
+			return err
+		}
+
+		var err error
+		user, err = s.userRepo.GetUser(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		events := make([]domain.MembershipEvent, 0, len(user.Teams))
+		for _, teamName := range user.Teams {
+			events = append(events, domain.NewMembershipEvent(teamName, userID, domain.MembershipAdded))
+		}
+		return s.auditRepo.RecordMembershipEvents(txCtx, events)
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// Heartbeat records that a user is active now
+func (s *Service) Heartbeat(ctx context.Context, userID string) (domain.User, error) {
+	ctx, span := tracing.Start(ctx, "user.Heartbeat")
+	defer span.End()
+
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return domain.User{}, domain.ErrInvalidArgument
+	}
+
+	if err := s.userRepo.TouchLastSeen(ctx, userID, time.Now()); err != nil {
+		return domain.User{}, err
+	}
+
+	return s.userRepo.GetUser(ctx, userID)
+}
+
+// ListDormantUsers returns a page of active users not seen within dormantAfter,
+// the total number of such users and the threshold that was applied.
+// Zero dormantAfter falls back to the assignment strategy's threshold.
+func (s *Service) ListDormantUsers(
+	ctx context.Context,
+	dormantAfter time.Duration,
+	limit, offset int,
+) ([]domain.User, int, time.Duration, error) {
+	if dormantAfter < 0 || limit < 0 || offset < 0 || limit > MaxListLimit {
+		return nil, 0, 0, domain.ErrInvalidArgument
+	}
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+	if dormantAfter == 0 {
+		dormantAfter = s.assignStrategy.DormantAfter()
+	}
+	if dormantAfter == 0 {
+		dormantAfter = DefaultDormantAfter
+	}
+
+	users, total, err := s.userRepo.ListDormantUsers(ctx, time.Now().Add(-dormantAfter), limit, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return users, total, dormantAfter, nil
+}
+
 // GetUser retrieves a user by ID
 func (s *Service) GetUser(ctx context.Context, userID string) (domain.User, error) {
 	userID = strings.TrimSpace(userID)
@@ -96,29 +465,51 @@ func (s *Service) GetPRsByReviewer(
 	return s.prRepo.GetPRsByReviewer(ctx, userID)
 }
 
-// BulkDeactivateTeamMembers deactivates users of a team and reassigns their open reviews.
+// GetPendingReviews returns the open reviews of a user still waiting for their
+// first action, each due the review SLA after it was assigned
+func (s *Service) GetPendingReviews(ctx context.Context, userID string) ([]domain.ReviewAssignment, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, domain.ErrInvalidArgument
+	}
+	if _, err := s.userRepo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.prRepo.GetPendingReviewsByReviewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reviews {
+		reviews[i].DueAt = reviews[i].AssignedAt.Add(s.reviewSLA)
+	}
+	return reviews, nil
+}
+
+// BulkDeactivateTeamMembers deactivates users of a team and reassigns their open reviews
+// within each PR's team; PRs without a team draw from teamName. The affected PRs
+// are read and their reviewers swapped with a fixed number of queries, however
+// many PRs there are.
 func (s *Service) BulkDeactivateTeamMembers(
 	ctx context.Context,
 	teamName string,
 	userIDs []string,
 ) (domain.Team, []string, []domain.Reassignment, error) {
+	ctx, span := tracing.Start(ctx, "user.BulkDeactivateTeamMembers")
+	defer span.End()
+	defer s.invalidateCaches()
+
 	teamName = strings.TrimSpace(teamName)
 	if teamName == "" || len(userIDs) == 0 {
 		return domain.Team{}, nil, nil, domain.ErrInvalidArgument
 	}
+	if err := domain.AuthorizeLead(ctx, s.userRepo.GetUser, teamName); err != nil {
+		return domain.Team{}, nil, nil, err
+	}
 
-	normalized := make([]string, 0, len(userIDs))
-	seen := make(map[string]struct{}, len(userIDs))
-	for _, id := range userIDs {
-		id = strings.TrimSpace(id)
-		if id == "" {
-			return domain.Team{}, nil, nil, domain.ErrInvalidArgument
-		}
-		if _, ok := seen[id]; ok {
-			continue
-		}
-		seen[id] = struct{}{}
-		normalized = append(normalized, id)
+	normalized, seen, err := normalizeUserIDs(userIDs)
+	if err != nil {
+		return domain.Team{}, nil, nil, err
 	}
 
 	members, err := s.userRepo.GetTeamMembers(ctx, teamName)
@@ -173,42 +564,55 @@ func (s *Service) BulkDeactivateTeamMembers(
 		}
 	}
 
-	var reassignments []domain.Reassignment
+	var (
+		reassignments []domain.Reassignment
+		deactivations []domain.Event
+	)
+	pools := map[string]domain.Team{"": futureTeam, teamName: futureTeam}
 
-	err = s.transactor.Do(ctx, func(txCtx context.Context) error {
+	err = s.transactor.Do(db.WithTxProfile(ctx, db.ProfileBulk), func(txCtx context.Context) error {
 		if err := s.userRepo.DeactivateUsers(txCtx, teamName, targetIDs); err != nil {
 			return err
 		}
 
+		var events []domain.MembershipEvent
 		for _, target := range targets {
-			prIDs, err := s.prRepo.GetOpenPRIDsByReviewer(txCtx, target.UserID)
-			if err != nil {
-				return err
-			}
+			events = append(events, domain.ActivityEvents(target, false)...)
+		}
+		if err := s.auditRepo.RecordMembershipEvents(txCtx, events); err != nil {
+			return err
+		}
+		deactivations = domain.NewDeactivationEvents(events)
+		if err := s.publish(txCtx, deactivations...); err != nil {
+			return err
+		}
 
-			for _, prID := range prIDs {
-				pr, err := s.prRepo.GetPR(txCtx, prID)
-				if err != nil {
-					return err
-				}
+		// PRs are loaded once for all targets and kept up to date as
+		// reviewers are replaced, so a PR reviewed by several targets sees
+		// the earlier replacements
+		prs, err := s.prRepo.GetOpenPRsByReviewers(txCtx, targetIDs)
+		if err != nil {
+			return err
+		}
 
-				if pr.IsMerged() {
+		for _, target := range targets {
+			for i := range prs {
+				pr := &prs[i]
+				if !slices.Contains(pr.AssignedReviewers, target.UserID) {
 					continue
 				}
+				prID := pr.PullRequestID
 
 				exclude := slices.Clone(pr.AssignedReviewers)
 				exclude = append(exclude, pr.AuthorID)
 
-				newUserID, err := s.assignStrategy.SelectReplacementReviewer(txCtx, futureTeam, exclude)
+				pool, err := s.reviewPool(txCtx, *pr, pools, seen)
 				if err != nil {
 					return err
 				}
 
-				if err := s.prRepo.RemoveReviewer(txCtx, prID, target.UserID); err != nil {
-					return err
-				}
-
-				if err := s.prRepo.AddReviewer(txCtx, prID, newUserID); err != nil {
+				newUserID, err := s.assignStrategy.SelectReplacementReviewer(txCtx, pool, exclude)
+				if err != nil {
 					return err
 				}
 
@@ -224,12 +628,21 @@ func (s *Service) BulkDeactivateTeamMembers(
 			}
 		}
 
-		return nil
+		// Replacements are chosen in memory and written in one batch
+		if err := s.prRepo.ReplaceReviewers(txCtx, reassignments); err != nil {
+			return err
+		}
+		if err := s.prRepo.RecordReassignments(txCtx, reassignments); err != nil {
+			return err
+		}
+		return s.publish(txCtx, domain.NewReassignmentEvents(reassignments)...)
 	})
 
 	if err != nil {
 		return domain.Team{}, nil, nil, err
 	}
+	metrics.RecordReassignments(reassignments)
+	s.notify(ctx, append(deactivations, domain.NewReassignmentEvents(reassignments)...)...)
 
 	for i := range team.Members {
 		if _, ok := seen[team.Members[i].UserID]; ok {
@@ -239,3 +652,156 @@ func (s *Service) BulkDeactivateTeamMembers(
 
 	return team, deactivated, reassignments, nil
 }
+
+// BulkActivateTeamMembers re-enables users of a team in one call.
+// Members that are already active are left untouched and not reported.
+func (s *Service) BulkActivateTeamMembers(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+) (domain.Team, []string, error) {
+	ctx, span := tracing.Start(ctx, "user.BulkActivateTeamMembers")
+	defer span.End()
+	defer s.invalidateCaches()
+
+	teamName = strings.TrimSpace(teamName)
+	if teamName == "" || len(userIDs) == 0 {
+		return domain.Team{}, nil, domain.ErrInvalidArgument
+	}
+	if err := domain.AuthorizeLead(ctx, s.userRepo.GetUser, teamName); err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	normalized, _, err := normalizeUserIDs(userIDs)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	members, err := s.userRepo.GetTeamMembers(ctx, teamName)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	if len(members) == 0 {
+		return domain.Team{}, nil, domain.ErrNotFound
+	}
+
+	team := domain.Team{
+		TeamName: teamName,
+		Members:  members,
+	}
+
+	memberByID := make(map[string]*domain.User, len(team.Members))
+	for i := range team.Members {
+		member := &team.Members[i]
+		memberByID[member.UserID] = member
+	}
+
+	activated := make([]string, 0, len(normalized))
+	for _, id := range normalized {
+		member, ok := memberByID[id]
+		if !ok {
+			return domain.Team{}, nil, domain.ErrNotFound
+		}
+		if member.IsActive {
+			continue
+		}
+		activated = append(activated, id)
+	}
+
+	if len(activated) == 0 {
+		return team, activated, nil
+	}
+
+	err = s.transactor.Do(db.WithTxProfile(ctx, db.ProfileBulk), func(txCtx context.Context) error {
+		if err := s.userRepo.ActivateUsers(txCtx, teamName, activated); err != nil {
+			return err
+		}
+
+		var events []domain.MembershipEvent
+		for _, id := range activated {
+			events = append(events, domain.ActivityEvents(*memberByID[id], true)...)
+		}
+		return s.auditRepo.RecordMembershipEvents(txCtx, events)
+	})
+
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	for _, id := range activated {
+		memberByID[id].Activate()
+	}
+
+	return team, activated, nil
+}
+
+// reviewPool returns the replacement pool for pr, loading and caching the PR's
+// team on first use. PRs without a team use the pool cached under "" if any,
+// and users in inactive are treated as already deactivated.
+func (s *Service) reviewPool(
+	ctx context.Context,
+	pr domain.PullRequest,
+	pools map[string]domain.Team,
+	inactive map[string]struct{},
+) (domain.Team, error) {
+	if pool, ok := pools[pr.TeamName]; ok {
+		return pool, nil
+	}
+
+	members, err := s.userRepo.GetTeamMembers(ctx, pr.TeamName)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	for i := range members {
+		if _, ok := inactive[members[i].UserID]; ok {
+			members[i].IsActive = false
+		}
+	}
+
+	pool := domain.Team{TeamName: pr.TeamName, Members: members}
+	pools[pr.TeamName] = pool
+	return pool, nil
+}
+
+// normalizeUserIDs trims and de-duplicates IDs, preserving their order
+func normalizeUserIDs(userIDs []string) ([]string, map[string]struct{}, error) {
+	normalized := make([]string, 0, len(userIDs))
+	seen := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, nil, domain.ErrInvalidArgument
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		normalized = append(normalized, id)
+	}
+	return normalized, seen, nil
+}
+
+// publish hands events to the configured publishers
+func (s *Service) publish(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	domain.SetEventOrg(ctx, events)
+	for _, p := range s.publishers {
+		if err := p.Publish(ctx, events...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify hands committed events to the configured listeners
+func (s *Service) notify(ctx context.Context, events ...domain.Event) {
+	if len(events) == 0 {
+		return
+	}
+	domain.SetEventOrg(ctx, events)
+	for _, l := range s.listeners {
+		l.Notify(ctx, events...)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"slices"
+	"sort"
 	"testing"
 	"time"
 
 	"pr-service/internal/domain"
+	"pr-service/internal/pagination"
 	"pr-service/internal/service/assignment"
 )
 
@@ -63,8 +66,74 @@ func (r *fakeUserRepo) DeactivateUsers(ctx context.Context, teamName string, use
 	return nil
 }
 
+func (r *fakeUserRepo) SoftDeleteUser(ctx context.Context, userID string) error {
+	user, ok := r.users[userID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	user.MarkDeleted()
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) ListDeletedUsers(ctx context.Context, page pagination.Page) ([]domain.User, int, error) {
+	var result []domain.User
+	for _, u := range r.users {
+		if u.IsDeleted() {
+			result = append(result, u)
+		}
+	}
+	total := len(result)
+	offset := min(page.Offset, total)
+	return result[offset:min(offset+page.Fetch(), total)], total, nil
+}
+
+func (r *fakeUserRepo) RestoreUser(ctx context.Context, userID string) error {
+	user, ok := r.users[userID]
+	if !ok || !user.IsDeleted() {
+		return domain.ErrNotFound
+	}
+	user.DeletedAt = nil
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) ActivateUsers(ctx context.Context, teamName string, userIDs []string) error {
+	for _, id := range userIDs {
+		user, ok := r.users[id]
+		if !ok || user.TeamName != teamName {
+			return domain.ErrNotFound
+		}
+		user.IsActive = true
+		user.UpdatedAt = time.Now()
+		r.users[id] = user
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) TouchLastSeen(ctx context.Context, userID string, at time.Time) error {
+	user, ok := r.users[userID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	user.MarkSeen(at)
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) ListDormantUsers(ctx context.Context, seenBefore time.Time, limit, offset int) ([]domain.User, int, error) {
+	result := make([]domain.User, 0)
+	for _, user := range r.users {
+		if user.IsActive && (user.LastSeenAt == nil || user.LastSeenAt.Before(seenBefore)) {
+			result = append(result, user)
+		}
+	}
+	return result, len(result), nil
+}
+
 type fakePRRepo struct {
-	prs map[string]domain.PullRequest
+	prs           map[string]domain.PullRequest
+	reassignments []domain.Reassignment
 }
 
 func newFakePRRepo() *fakePRRepo {
@@ -86,20 +155,26 @@ func (r *fakePRRepo) GetPRsByReviewer(ctx context.Context, userID string) ([]dom
 	return result, nil
 }
 
-func (r *fakePRRepo) GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
-	var ids []string
-	for id, pr := range r.prs {
+func (r *fakePRRepo) GetPendingReviewsByReviewer(ctx context.Context, userID string) ([]domain.ReviewAssignment, error) {
+	return nil, nil
+}
+
+func (r *fakePRRepo) GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	for _, pr := range r.prs {
 		if pr.Status != domain.PRStatusOpen {
 			continue
 		}
 		for _, reviewer := range pr.AssignedReviewers {
-			if reviewer == userID {
-				ids = append(ids, id)
+			if slices.Contains(userIDs, reviewer) {
+				pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+				prs = append(prs, pr)
 				break
 			}
 		}
 	}
-	return ids, nil
+	sort.Slice(prs, func(i, j int) bool { return prs[i].PullRequestID < prs[j].PullRequestID })
+	return prs, nil
 }
 
 func (r *fakePRRepo) GetPR(ctx context.Context, prID string) (domain.PullRequest, error) {
@@ -151,6 +226,35 @@ func (r *fakePRRepo) AddReviewer(ctx context.Context, prID string, userID string
 	return nil
 }
 
+func (r *fakePRRepo) ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error {
+	for _, ra := range reassignments {
+		if err := r.RemoveReviewer(ctx, ra.PullRequestID, ra.OldUserID); err != nil {
+			return err
+		}
+		if ra.NewUserID == "" {
+			continue
+		}
+		if err := r.AddReviewer(ctx, ra.PullRequestID, ra.NewUserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakePRRepo) RecordReassignments(ctx context.Context, reassignments []domain.Reassignment) error {
+	r.reassignments = append(r.reassignments, reassignments...)
+	return nil
+}
+
+type fakeAuditRepo struct {
+	events []domain.MembershipEvent
+}
+
+func (r *fakeAuditRepo) RecordMembershipEvents(ctx context.Context, events []domain.MembershipEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
 type noopTransactor struct{}
 
 func (noopTransactor) Do(ctx context.Context, f func(ctx context.Context) error) error {
@@ -166,12 +270,12 @@ func TestBulkDeactivateTeamMembers(t *testing.T) {
 	userRepo.users["u3"] = domain.NewUser("u3", "Charlie", "backend", true)
 	userRepo.users["u4"] = domain.NewUser("u4", "David", "backend", true)
 
-	pr := domain.NewPullRequest("pr-1", "Add search", "u1")
+	pr := domain.NewPullRequest("pr-1", "Add search", "u1", "backend")
 	pr.AssignedReviewers = []string{"u2", "u3"}
 	prRepo.prs["pr-1"] = pr
 
 	strategy := assignment.NewStrategyWithSource(rand.NewSource(1))
-	service := NewService(userRepo, prRepo, noopTransactor{}, strategy)
+	service := NewService(userRepo, prRepo, &fakeAuditRepo{}, noopTransactor{}, strategy)
 
 	team, deactivated, reassignments, err := service.BulkDeactivateTeamMembers(context.Background(), "backend", []string{"u2"})
 	if err != nil {
@@ -201,6 +305,153 @@ func TestBulkDeactivateTeamMembers(t *testing.T) {
 	if reassignments[0].NewUserID == "u2" {
 		t.Fatalf("new reviewer must differ from old")
 	}
+
+	if len(prRepo.reassignments) != 1 || prRepo.reassignments[0] != reassignments[0] {
+		t.Fatalf("expected the reassignment to be logged, got %+v", prRepo.reassignments)
+	}
+}
+
+func TestBulkDeactivatePrefersRecentlySeenReviewers(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	prRepo := newFakePRRepo()
+
+	userRepo.users["u1"] = domain.NewUser("u1", "Alice", "backend", true)
+	userRepo.users["u2"] = domain.NewUser("u2", "Bob", "backend", true)
+	userRepo.users["u3"] = domain.NewUser("u3", "Charlie", "backend", true)
+	userRepo.users["u4"] = domain.NewUser("u4", "David", "backend", true)
+	userRepo.users["u5"] = domain.NewUser("u5", "Eve", "backend", true)
+
+	pr := domain.NewPullRequest("pr-1", "Add search", "u1", "backend")
+	pr.AssignedReviewers = []string{"u2", "u3"}
+	prRepo.prs["pr-1"] = pr
+
+	strategy := assignment.NewStrategyWithSource(rand.NewSource(1), assignment.WithDormantAfter(24*time.Hour))
+	service := NewService(userRepo, prRepo, &fakeAuditRepo{}, noopTransactor{}, strategy)
+
+	// u4 was last seen long ago and u5 has never been seen, so u5 is picked only after a heartbeat
+	if err := userRepo.TouchLastSeen(context.Background(), "u4", time.Now().Add(-72*time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.Heartbeat(context.Background(), "u5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _, reassignments, err := service.BulkDeactivateTeamMembers(context.Background(), "backend", []string{"u2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reassignments) != 1 || reassignments[0].NewUserID != "u5" {
+		t.Fatalf("expected recently seen u5 to take the review, got %+v", reassignments)
+	}
+
+	dormant, total, dormantAfter, err := service.ListDormantUsers(context.Background(), 0, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dormantAfter != 24*time.Hour || total != len(dormant) || total != 3 {
+		t.Fatalf("expected u1, u3 and u4 to be dormant after a day, got %d users after %s", total, dormantAfter)
+	}
+}
+
+func TestBulkActivateTeamMembers(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	prRepo := newFakePRRepo()
+
+	userRepo.users["u1"] = domain.NewUser("u1", "Alice", "backend", true)
+	userRepo.users["u2"] = domain.NewUser("u2", "Bob", "backend", false)
+	userRepo.users["u3"] = domain.NewUser("u3", "Charlie", "backend", false)
+
+	auditRepo := &fakeAuditRepo{}
+	service := NewService(userRepo, prRepo, auditRepo, noopTransactor{}, assignment.NewStrategyWithSource(rand.NewSource(1)))
+
+	team, activated, err := service.BulkActivateTeamMembers(context.Background(), "backend", []string{"u1", "u2", " u2 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(activated) != 1 || activated[0] != "u2" {
+		t.Fatalf("expected only u2 to be activated, got %v", activated)
+	}
+
+	for _, member := range team.Members {
+		if member.UserID != "u3" && !member.IsActive {
+			t.Fatalf("expected %s to be active", member.UserID)
+		}
+	}
+
+	if !userRepo.users["u2"].IsActive || userRepo.users["u3"].IsActive {
+		t.Fatalf("unexpected stored activity flags")
+	}
+
+	if len(auditRepo.events) != 1 || auditRepo.events[0].UserID != "u2" ||
+		auditRepo.events[0].TeamName != "backend" || auditRepo.events[0].Action != domain.MembershipActivated {
+		t.Fatalf("expected one activation of u2 in the audit log, got %+v", auditRepo.events)
+	}
+
+	if _, _, err := service.BulkActivateTeamMembers(context.Background(), "backend", []string{"ghost"}); err != domain.ErrNotFound {
+		t.Fatalf("expected not found for unknown member, got %v", err)
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	prRepo := newFakePRRepo()
+
+	userRepo.users["u1"] = domain.NewUser("u1", "Alice", "backend", true)
+	userRepo.users["u2"] = domain.NewUser("u2", "Bob", "backend", true)
+	userRepo.users["u3"] = domain.NewUser("u3", "Charlie", "backend", true)
+	userRepo.users["u4"] = domain.NewUser("u4", "David", "backend", true)
+
+	pr := domain.NewPullRequest("pr-1", "Add search", "u1", "backend")
+	pr.AssignedReviewers = []string{"u2", "u3"}
+	prRepo.prs["pr-1"] = pr
+
+	strategy := assignment.NewStrategyWithSource(rand.NewSource(1))
+	service := NewService(userRepo, prRepo, &fakeAuditRepo{}, noopTransactor{}, strategy)
+
+	deleted, reassignments, err := service.DeleteUser(context.Background(), "u2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !deleted.IsDeleted() || deleted.IsActive {
+		t.Fatalf("expected u2 to be deleted and inactive")
+	}
+
+	if len(reassignments) != 1 || reassignments[0].NewUserID != "u4" {
+		t.Fatalf("expected u2 review to move to u4, got %+v", reassignments)
+	}
+
+	if reviewers := prRepo.prs["pr-1"].AssignedReviewers; len(reviewers) != 2 || reviewers[1] != "u4" {
+		t.Fatalf("unexpected reviewers after delete: %v", reviewers)
+	}
+}
+
+func TestDeleteUserClosesReviewWithoutCandidate(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	prRepo := newFakePRRepo()
+
+	userRepo.users["u1"] = domain.NewUser("u1", "Alice", "backend", true)
+	userRepo.users["u2"] = domain.NewUser("u2", "Bob", "backend", true)
+
+	pr := domain.NewPullRequest("pr-1", "Add search", "u1", "backend")
+	pr.AssignedReviewers = []string{"u2"}
+	prRepo.prs["pr-1"] = pr
+
+	service := NewService(userRepo, prRepo, &fakeAuditRepo{}, noopTransactor{}, assignment.NewStrategyWithSource(rand.NewSource(1)))
+
+	_, reassignments, err := service.DeleteUser(context.Background(), "u2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reassignments) != 1 || !reassignments[0].IsClosed() {
+		t.Fatalf("expected closed review, got %+v", reassignments)
+	}
+
+	if reviewers := prRepo.prs["pr-1"].AssignedReviewers; len(reviewers) != 0 {
+		t.Fatalf("expected no reviewers left, got %v", reviewers)
+	}
 }
 
 func BenchmarkBulkDeactivateTeamMembers(b *testing.B) {
@@ -216,7 +467,7 @@ func BenchmarkBulkDeactivateTeamMembers(b *testing.B) {
 		// Create 50 PRs with two reviewers each.
 		for p := 0; p < 50; p++ {
 			prID := fmt.Sprintf("pr-%d", p)
-			pr := domain.NewPullRequest(prID, "Feature", "u0")
+			pr := domain.NewPullRequest(prID, "Feature", "u0", "backend")
 			pr.AssignedReviewers = []string{
 				fmt.Sprintf("u%d", (p%18)+1),
 				fmt.Sprintf("u%d", (p%18)+2),
@@ -225,10 +476,79 @@ func BenchmarkBulkDeactivateTeamMembers(b *testing.B) {
 		}
 
 		strategy := assignment.NewStrategyWithSource(rand.NewSource(42))
-		service := NewService(userRepo, prRepo, noopTransactor{}, strategy)
+		service := NewService(userRepo, prRepo, &fakeAuditRepo{}, noopTransactor{}, strategy)
 
 		if _, _, _, err := service.BulkDeactivateTeamMembers(context.Background(), "backend", []string{"u1", "u2", "u3"}); err != nil {
 			b.Fatalf("bulk deactivate failed: %v", err)
 		}
 	}
 }
+
+// countingPRRepo counts the PR repository calls a service makes, each of
+// which is a database round trip in production
+type countingPRRepo struct {
+	*fakePRRepo
+	calls int
+}
+
+func (r *countingPRRepo) GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	r.calls++
+	return r.fakePRRepo.GetOpenPRsByReviewers(ctx, userIDs)
+}
+
+func (r *countingPRRepo) RemoveReviewer(ctx context.Context, prID string, userID string) error {
+	r.calls++
+	return r.fakePRRepo.RemoveReviewer(ctx, prID, userID)
+}
+
+func (r *countingPRRepo) AddReviewer(ctx context.Context, prID string, userID string) error {
+	r.calls++
+	return r.fakePRRepo.AddReviewer(ctx, prID, userID)
+}
+
+func (r *countingPRRepo) ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error {
+	// One statement removes the old reviewers and one adds the new ones
+	r.calls += 2
+	return r.fakePRRepo.ReplaceReviewers(ctx, reassignments)
+}
+
+func (r *countingPRRepo) RecordReassignments(ctx context.Context, reassignments []domain.Reassignment) error {
+	r.calls++
+	return r.fakePRRepo.RecordReassignments(ctx, reassignments)
+}
+
+// BenchmarkBulkDeactivateTeamMembers1kPRs reports the PR queries made to
+// reassign 1000 open PRs, which no longer grow with the number of PRs
+func BenchmarkBulkDeactivateTeamMembers1kPRs(b *testing.B) {
+	var calls int
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		userRepo := newFakeUserRepo()
+		prRepo := &countingPRRepo{fakePRRepo: newFakePRRepo()}
+		for u := 0; u < 50; u++ {
+			id := fmt.Sprintf("u%d", u)
+			userRepo.users[id] = domain.NewUser(id, fmt.Sprintf("User %d", u), "backend", true)
+		}
+		for p := 0; p < 1000; p++ {
+			prID := fmt.Sprintf("pr-%04d", p)
+			pr := domain.NewPullRequest(prID, "Feature", "u0", "backend")
+			pr.AssignedReviewers = []string{fmt.Sprintf("u%d", p%10+1), fmt.Sprintf("u%d", p%10+11)}
+			prRepo.prs[prID] = pr
+		}
+		strategy := assignment.NewStrategyWithSource(rand.NewSource(42))
+		service := NewService(userRepo, prRepo, &fakeAuditRepo{}, noopTransactor{}, strategy)
+		b.StartTimer()
+
+		// Every PR has one of u1..u10 as reviewer
+		targets := []string{"u1", "u2", "u3", "u4", "u5", "u6", "u7", "u8", "u9", "u10"}
+		_, _, reassignments, err := service.BulkDeactivateTeamMembers(context.Background(), "backend", targets)
+		if err != nil {
+			b.Fatalf("bulk deactivate failed: %v", err)
+		}
+		if len(reassignments) != 1000 {
+			b.Fatalf("expected 1000 reassignments, got %d", len(reassignments))
+		}
+		calls += prRepo.calls
+	}
+	b.ReportMetric(float64(calls)/float64(b.N), "pr_queries/op")
+}
@@ -0,0 +1,234 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"pr-service/internal/db"
+	"pr-service/internal/domain"
+	"pr-service/internal/event"
+)
+
+type webhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	EnqueueWebhookDeliveries(ctx context.Context, eventType domain.EventType, payload []byte, now time.Time) (int, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status domain.WebhookDeliveryStatus, limit, offset int) ([]domain.WebhookDelivery, int, error)
+}
+
+type sender interface {
+	Send(ctx context.Context, url, secret, event string, deliveryID int64, body []byte) (int, error)
+}
+
+const (
+	// DefaultMaxAttempts is the number of attempts after which a delivery is marked FAILED
+	DefaultMaxAttempts = 8
+	// DefaultRetryBase is the delay before the first retry; each further retry doubles it
+	DefaultRetryBase = 30 * time.Second
+	// DefaultRetryMax caps the delay between retries
+	DefaultRetryMax = time.Hour
+	// DefaultListLimit is used when the caller does not specify a page size
+	DefaultListLimit = 50
+	// MaxListLimit caps the page size of delivery listings
+	MaxListLimit = 100
+
+	// deliveryLease is how long a claimed delivery stays invisible to other workers.
+	// It must outlast a single send, which is bounded by the sender timeout.
+	deliveryLease = 5 * time.Minute
+	// secretBytes is the size of generated subscription secrets
+	secretBytes = 32
+)
+
+// Service manages outbound webhook subscriptions and delivers events to them
+type Service struct {
+	repo        webhookRepository
+	sender      sender
+	maxAttempts int
+	retryBase   time.Duration
+	retryMax    time.Duration
+}
+
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithRetryPolicy sets how often and how long failed deliveries are retried.
+// Non-positive values keep the defaults.
+func WithRetryPolicy(maxAttempts int, base, maxDelay time.Duration) Option {
+	return func(s *Service) {
+		if maxAttempts > 0 {
+			s.maxAttempts = maxAttempts
+		}
+		if base > 0 {
+			s.retryBase = base
+		}
+		if maxDelay > 0 {
+			s.retryMax = maxDelay
+		}
+	}
+}
+
+// NewService creates a new webhook service
+func NewService(repo webhookRepository, sender sender, opts ...Option) *Service {
+	s := &Service{
+		repo:        repo,
+		sender:      sender,
+		maxAttempts: DefaultMaxAttempts,
+		retryBase:   DefaultRetryBase,
+		retryMax:    DefaultRetryMax,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Subscribe registers rawURL for eventTypes. An empty secret is replaced with a
+// generated one; the returned subscription is the only place it is disclosed.
+func (s *Service) Subscribe(
+	ctx context.Context,
+	rawURL, secret string,
+	eventTypes []domain.EventType,
+) (domain.WebhookSubscription, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.WebhookSubscription{}, domain.ErrInvalidArgument
+	}
+
+	if len(eventTypes) == 0 {
+		return domain.WebhookSubscription{}, domain.ErrInvalidArgument
+	}
+	normalized := make([]domain.EventType, 0, len(eventTypes))
+	seen := make(map[domain.EventType]struct{}, len(eventTypes))
+	for _, t := range eventTypes {
+		if !t.IsValid() {
+			return domain.WebhookSubscription{}, domain.ErrInvalidArgument
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		normalized = append(normalized, t)
+	}
+
+	if secret == "" {
+		buf := make([]byte, secretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return domain.WebhookSubscription{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	return s.repo.CreateWebhookSubscription(ctx, domain.WebhookSubscription{
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: normalized,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// ListSubscriptions returns all subscriptions
+func (s *Service) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.repo.ListWebhookSubscriptions(ctx)
+}
+
+// Unsubscribe removes a subscription and its delivery log
+func (s *Service) Unsubscribe(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrInvalidArgument
+	}
+	return s.repo.DeleteWebhookSubscription(ctx, id)
+}
+
+// ListDeliveries returns a page of a subscription's delivery log, optionally filtered by status
+func (s *Service) ListDeliveries(
+	ctx context.Context,
+	subscriptionID int64,
+	status domain.WebhookDeliveryStatus,
+	limit, offset int,
+) ([]domain.WebhookDelivery, int, error) {
+	if subscriptionID <= 0 || (status != "" && !status.IsValid()) ||
+		limit < 0 || offset < 0 || limit > MaxListLimit {
+		return nil, 0, domain.ErrInvalidArgument
+	}
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+
+	return s.repo.ListWebhookDeliveries(ctx, subscriptionID, status, limit, offset)
+}
+
+// Publish queues events for their subscribers. Callers publish inside the
+// transaction of the change, so deliveries exist only for committed changes.
+func (s *Service) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, e := range events {
+		payload, err := event.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := s.repo.EnqueueWebhookDeliveries(ctx, e.Type, payload, e.OccurredAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue attempts up to limit deliveries that are due at now and returns how many were attempted.
+// A failed attempt is retried with exponential backoff until maxAttempts is reached.
+// Deliveries of every organization are attempted.
+func (s *Service) DeliverDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	ctx = db.AllOrgs(ctx)
+	deliveries, err := s.repo.ClaimDueWebhookDeliveries(ctx, now, deliveryLease, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		status, sendErr := s.sender.Send(ctx, delivery.URL, delivery.Secret, string(delivery.EventType), delivery.ID, delivery.Payload)
+
+		delivery.ResponseStatus = nil
+		if status != 0 {
+			delivery.ResponseStatus = &status
+		}
+		switch {
+		case sendErr == nil:
+			delivered := time.Now()
+			delivery.Status = domain.WebhookDeliveryDelivered
+			delivery.LastError = ""
+			delivery.DeliveredAt = &delivered
+		case delivery.Attempts >= s.maxAttempts:
+			delivery.Status = domain.WebhookDeliveryFailed
+			delivery.LastError = sendErr.Error()
+		default:
+			delivery.Status = domain.WebhookDeliveryPending
+			delivery.LastError = sendErr.Error()
+			delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+		}
+
+		if err := s.repo.RecordWebhookAttempt(ctx, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// backoff returns the delay after the given number of failed attempts
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.retryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.retryMax {
+			return s.retryMax
+		}
+	}
+	return min(delay, s.retryMax)
+}
@@ -0,0 +1,87 @@
+// Package tracing sets up OpenTelemetry tracing exported over OTLP/HTTP and
+// holds the glue instrumenting this service with it. Spans are started on the
+// global tracer provider set by SetDefault; until one is set, they are not
+// recorded.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultTimeout bounds a single export when no timeout is configured
+	DefaultTimeout = 10 * time.Second
+	// tracesPath is appended to the collector endpoint, as OTLP exporters do
+	tracesPath = "/v1/traces"
+	// scopeName names the instrumentation in exported spans
+	scopeName = "pr-service"
+)
+
+// NewProvider creates a tracer provider exporting spans in batches to the
+// OTLP/HTTP collector at endpoint, such as http://otel-collector:4318, with
+// headers added to each request (for example an API key). Spans are attributed
+// to serviceName. New traces are recorded with probability sampleRatio (all of
+// them when it is not in (0, 1]), while spans with a parent follow its
+// decision. A non-positive timeout uses DefaultTimeout.
+func NewProvider(ctx context.Context, endpoint, serviceName string, headers map[string]string, sampleRatio float64, timeout time.Duration) (*sdktrace.TracerProvider, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(strings.TrimRight(endpoint, "/")+tracesPath),
+		otlptracehttp.WithHeaders(headers),
+		otlptracehttp.WithTimeout(timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sampler(sampleRatio)),
+	), nil
+}
+
+// sampler records ratio of new traces and follows the parent's decision for
+// the rest, including callers passing a W3C traceparent
+func sampler(ratio float64) sdktrace.Sampler {
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+}
+
+// SetDefault makes tp the global tracer provider and propagates the trace
+// context in W3C traceparent headers
+func SetDefault(tp trace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Start starts a span as a child of the span in ctx, or as the root of a new
+// trace, and returns a context carrying it. The span must be ended with End.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(scopeName).Start(ctx, name, opts...)
+}
+
+// RecordError records err on span and marks the span as failed; nil errors are
+// ignored
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	remoteTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	remoteSpanID  = "00f067aa0ba902b7"
+)
+
+// withRecorder installs a provider sampling new traces with ratio and returns
+// the recorder of its ended spans
+func withRecorder(t *testing.T, ratio float64) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	SetDefault(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSampler(sampler(ratio))))
+	t.Cleanup(func() { SetDefault(noop.NewTracerProvider()) })
+	return recorder
+}
+
+func remoteContext(flags string) context.Context {
+	header := http.Header{}
+	header.Set("traceparent", "00-"+remoteTraceID+"-"+remoteSpanID+"-"+flags)
+	return otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+}
+
+func TestStartFollowsCaller(t *testing.T) {
+	// New traces are almost never sampled, so recorded spans follow the caller
+	recorder := withRecorder(t, 1e-9)
+
+	_, span := Start(remoteContext("00"), "skipped")
+	if span.IsRecording() || span.SpanContext().TraceID().String() != remoteTraceID {
+		t.Fatalf("expected an unrecorded span of the caller's trace, got %+v", span.SpanContext())
+	}
+	span.End()
+
+	ctx, parent := Start(remoteContext("01"), "parent", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := Start(ctx, "child")
+	RecordError(child, errors.New("boom"))
+	RecordError(child, nil)
+	child.End()
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	if p.SpanContext().TraceID() != c.SpanContext().TraceID() || c.Parent().SpanID() != p.SpanContext().SpanID() ||
+		p.Parent().SpanID().String() != remoteSpanID {
+		t.Fatalf("unexpected span tree: parent %s, child %s", p.Name(), c.Name())
+	}
+	if p.SpanKind() != trace.SpanKindServer || c.Status().Code != codes.Error || c.Status().Description != "boom" || len(c.Events()) != 1 {
+		t.Fatalf("unexpected span fields: parent %v, child %+v", p.SpanKind(), c.Status())
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := withRecorder(t, 1)
+
+	var tracer QueryTracer
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "  select 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "db SELECT" || spans[0].SpanKind() != trace.SpanKindClient || spans[0].Status().Code != codes.Error {
+		t.Fatalf("unexpected query spans %+v", spans)
+	}
+}
+
+func TestNewProviderExports(t *testing.T) {
+	exports := make(chan *http.Request, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exports <- r
+	}))
+	defer collector.Close()
+
+	tp, err := NewProvider(context.Background(), collector.URL+"/", "pr-service", map[string]string{"X-Api-Key": "secret"}, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	_, span := tp.Tracer(scopeName).Start(context.Background(), "op")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	r := <-exports
+	if r.URL.Path != "/v1/traces" || r.Header.Get("X-Api-Key") != "secret" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+		t.Fatalf("unexpected export request %s %v", r.URL.Path, r.Header)
+	}
+}
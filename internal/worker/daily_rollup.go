@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultRollupInterval is used when no rollup interval is configured
+const DefaultRollupInterval = time.Hour
+
+type rollupService interface {
+	RollUpDueDays(ctx context.Context, now time.Time) (int, error)
+}
+
+// DailyRollupWorker periodically rolls completed days up into the daily stats tables.
+// Checking hourly makes a finished day available shortly after midnight UTC.
+type DailyRollupWorker struct {
+	service  rollupService
+	orgs     orgLister
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewDailyRollupWorker creates a new daily rollup worker
+func NewDailyRollupWorker(service rollupService, orgs orgLister, interval time.Duration, logger *zap.Logger) *DailyRollupWorker {
+	if interval <= 0 {
+		interval = DefaultRollupInterval
+	}
+
+	return &DailyRollupWorker{
+		service:  service,
+		orgs:     orgs,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run rolls up due days on start and then every interval until ctx is canceled
+func (w *DailyRollupWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Daily rollup worker started", zap.Duration("interval", w.interval))
+
+	forEachOrg(ctx, w.orgs, w.logger, w.tick)
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Daily rollup worker stopped")
+			return
+		case <-ticker.C:
+			forEachOrg(ctx, w.orgs, w.logger, w.tick)
+		}
+	}
+}
+
+func (w *DailyRollupWorker) tick(ctx context.Context) {
+	rolled, err := w.service.RollUpDueDays(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("Failed to roll up daily stats", zap.Error(err))
+		}
+		return
+	}
+	if rolled > 0 {
+		w.logger.Info("Rolled up daily stats", zap.Int("days", rolled))
+	}
+}
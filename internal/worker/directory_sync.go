@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// DefaultDirectorySyncInterval is used when no directory sync interval is configured
+const DefaultDirectorySyncInterval = time.Hour
+
+type directoryService interface {
+	Sync(ctx context.Context) (domain.DirectorySync, error)
+}
+
+// DirectorySyncWorker periodically syncs teams and users from a directory service
+type DirectorySyncWorker struct {
+	service  directoryService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewDirectorySyncWorker creates a new directory sync worker
+func NewDirectorySyncWorker(service directoryService, interval time.Duration, logger *zap.Logger) *DirectorySyncWorker {
+	if interval <= 0 {
+		interval = DefaultDirectorySyncInterval
+	}
+
+	return &DirectorySyncWorker{
+		service:  service,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run syncs on start and then every interval until ctx is canceled
+func (w *DirectorySyncWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Directory sync worker started", zap.Duration("interval", w.interval))
+
+	w.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Directory sync worker stopped")
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+func (w *DirectorySyncWorker) tick(ctx context.Context) {
+	result, err := w.service.Sync(ctx)
+	if err != nil && ctx.Err() == nil {
+		w.logger.Error("Failed to sync directory", zap.Error(err))
+	}
+	if result.TeamsSynced > 0 {
+		w.logger.Info("Synced directory",
+			zap.Int("teams", result.TeamsSynced),
+			zap.Strings("created_teams", result.TeamsCreated),
+			zap.Strings("deactivated_users", result.Deactivated),
+			zap.Int("reassignments", len(result.Reassignments)))
+	}
+}
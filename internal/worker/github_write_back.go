@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type githubSyncService interface {
+	Pending() <-chan domain.Event
+	Send(ctx context.Context, event domain.Event) error
+}
+
+// GitHubWriteBackWorker writes queued reviewer assignments back to GitHub as they arrive
+type GitHubWriteBackWorker struct {
+	service githubSyncService
+	logger  *zap.Logger
+}
+
+// NewGitHubWriteBackWorker creates a new GitHub write-back worker
+func NewGitHubWriteBackWorker(service githubSyncService, logger *zap.Logger) *GitHubWriteBackWorker {
+	return &GitHubWriteBackWorker{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Run writes back assignments until ctx is canceled
+func (w *GitHubWriteBackWorker) Run(ctx context.Context) {
+	w.logger.Info("GitHub write-back worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("GitHub write-back worker stopped")
+			return
+		case event := <-w.service.Pending():
+			if err := w.service.Send(domain.WithOrg(ctx, event.OrgID), event); err != nil && ctx.Err() == nil {
+				w.logger.Error("Failed to write reviewers back to GitHub",
+					zap.String("event", string(event.Type)),
+					zap.String("pull_request_id", event.PullRequestID),
+					zap.Error(err))
+			}
+		}
+	}
+}
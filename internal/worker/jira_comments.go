@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type jiraCommentService interface {
+	Pending() <-chan domain.Event
+	Send(ctx context.Context, event domain.Event) error
+}
+
+// JiraCommentsWorker posts queued comments on linked Jira issues as they arrive
+type JiraCommentsWorker struct {
+	service jiraCommentService
+	logger  *zap.Logger
+}
+
+// NewJiraCommentsWorker creates a new Jira comments worker
+func NewJiraCommentsWorker(service jiraCommentService, logger *zap.Logger) *JiraCommentsWorker {
+	return &JiraCommentsWorker{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Run posts comments until ctx is canceled
+func (w *JiraCommentsWorker) Run(ctx context.Context) {
+	w.logger.Info("Jira comments worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Jira comments worker stopped")
+			return
+		case event := <-w.service.Pending():
+			if err := w.service.Send(domain.WithOrg(ctx, event.OrgID), event); err != nil && ctx.Err() == nil {
+				w.logger.Error("Failed to comment on Jira issue",
+					zap.String("event", string(event.Type)),
+					zap.String("pull_request_id", event.PullRequestID),
+					zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// orgLister lists the organizations periodic work is done for
+type orgLister interface {
+	ListOrgs(ctx context.Context) ([]string, error)
+}
+
+// forEachOrg calls fn with a context scoped to each organization orgs lists,
+// one after another. With nil orgs, for storage holding only the default
+// organization, fn is called once with ctx.
+func forEachOrg(ctx context.Context, orgs orgLister, logger *zap.Logger, fn func(ctx context.Context)) {
+	if orgs == nil {
+		fn(ctx)
+		return
+	}
+
+	ids, err := orgs.ListOrgs(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to list organizations", zap.Error(err))
+		}
+		return
+	}
+	for _, org := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		fn(domain.WithOrg(ctx, org))
+	}
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultRelayPollInterval is used when no outbox relay poll interval is configured
+const DefaultRelayPollInterval = time.Second
+
+type outboxService interface {
+	Relay(ctx context.Context, limit int) (int, error)
+}
+
+// OutboxRelayWorker periodically relays recorded domain events to the message broker
+type OutboxRelayWorker struct {
+	service      outboxService
+	pollInterval time.Duration
+	batchSize    int
+	logger       *zap.Logger
+}
+
+// NewOutboxRelayWorker creates a new outbox relay worker
+func NewOutboxRelayWorker(
+	service outboxService,
+	pollInterval time.Duration,
+	batchSize int,
+	logger *zap.Logger,
+) *OutboxRelayWorker {
+	if pollInterval <= 0 {
+		pollInterval = DefaultRelayPollInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &OutboxRelayWorker{
+		service:      service,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		logger:       logger,
+	}
+}
+
+// Run polls the outbox until ctx is canceled
+func (w *OutboxRelayWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("Outbox relay worker started", zap.Duration("poll_interval", w.pollInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Outbox relay worker stopped")
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+func (w *OutboxRelayWorker) tick(ctx context.Context) {
+	for {
+		sent, err := w.service.Relay(ctx, w.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.Error("Failed to relay events", zap.Error(err))
+			}
+			return
+		}
+		if sent > 0 {
+			w.logger.Debug("Relayed events", zap.Int("count", sent))
+		}
+		if sent < w.batchSize {
+			return
+		}
+	}
+}
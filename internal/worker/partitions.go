@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultPartitionInterval is how often the partition worker checks for
+// missing partitions
+const DefaultPartitionInterval = 24 * time.Hour
+
+// PartitionMonthsAhead is how many months past the current one get their
+// pull_requests partition in advance
+const PartitionMonthsAhead = 3
+
+type partitionRepository interface {
+	CreatePRPartitions(ctx context.Context, from, through time.Time) (int, error)
+}
+
+// PartitionWorker periodically creates the monthly partitions of pull_requests
+// ahead of time, so new PRs never land in the default partition
+type PartitionWorker struct {
+	repo     partitionRepository
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewPartitionWorker creates a new partition worker
+func NewPartitionWorker(repo partitionRepository, logger *zap.Logger) *PartitionWorker {
+	return &PartitionWorker{
+		repo:     repo,
+		interval: DefaultPartitionInterval,
+		logger:   logger,
+	}
+}
+
+// Run creates missing partitions on start and then every interval until ctx is canceled
+func (w *PartitionWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Partition worker started", zap.Duration("interval", w.interval))
+
+	w.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Partition worker stopped")
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+func (w *PartitionWorker) tick(ctx context.Context) {
+	now := time.Now()
+	created, err := w.repo.CreatePRPartitions(ctx, now, now.AddDate(0, PartitionMonthsAhead, 0))
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("Failed to create pull request partitions", zap.Error(err))
+		}
+		return
+	}
+	if created > 0 {
+		w.logger.Info("Created pull request partitions", zap.Int("partitions", created))
+	}
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultRetentionInterval is used when no retention interval is configured
+const DefaultRetentionInterval = time.Hour
+
+type retentionService interface {
+	RemoveExpiredPRs(ctx context.Context, now time.Time) (int, error)
+}
+
+// RetentionWorker periodically moves merged PRs past the retention age out of
+// the hot tables
+type RetentionWorker struct {
+	service  retentionService
+	orgs     orgLister
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewRetentionWorker creates a new retention worker
+func NewRetentionWorker(service retentionService, orgs orgLister, interval time.Duration, logger *zap.Logger) *RetentionWorker {
+	if interval <= 0 {
+		interval = DefaultRetentionInterval
+	}
+
+	return &RetentionWorker{
+		service:  service,
+		orgs:     orgs,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run applies the retention on start and then every interval until ctx is canceled
+func (w *RetentionWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Retention worker started", zap.Duration("interval", w.interval))
+
+	forEachOrg(ctx, w.orgs, w.logger, w.tick)
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Retention worker stopped")
+			return
+		case <-ticker.C:
+			forEachOrg(ctx, w.orgs, w.logger, w.tick)
+		}
+	}
+}
+
+func (w *RetentionWorker) tick(ctx context.Context) {
+	removed, err := w.service.RemoveExpiredPRs(ctx, time.Now())
+	if removed > 0 {
+		w.logger.Info("Removed merged PRs past retention", zap.Int("prs", removed))
+	}
+	if err != nil && ctx.Err() == nil {
+		w.logger.Error("Failed to remove merged PRs past retention", zap.Error(err))
+	}
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/cron"
+
+	"go.uber.org/zap"
+)
+
+type digestService interface {
+	SendDigests(ctx context.Context, since, now, next time.Time) (int, error)
+}
+
+// ReviewDigestWorker sends reviewers a summary of their pending reviews on a cron schedule
+type ReviewDigestWorker struct {
+	service  digestService
+	orgs     orgLister
+	schedule *cron.Schedule
+	logger   *zap.Logger
+}
+
+// NewReviewDigestWorker creates a new review digest worker
+func NewReviewDigestWorker(service digestService, orgs orgLister, schedule *cron.Schedule, logger *zap.Logger) *ReviewDigestWorker {
+	return &ReviewDigestWorker{
+		service:  service,
+		orgs:     orgs,
+		schedule: schedule,
+		logger:   logger,
+	}
+}
+
+// Run sends digests at every scheduled time until ctx is canceled. Reviews
+// assigned since the previous run, or since the worker started for the first
+// run, are reported as new. Runs missed while the service was down are not caught up.
+func (w *ReviewDigestWorker) Run(ctx context.Context) {
+	w.logger.Info("Review digest worker started")
+
+	since := time.Now()
+	for {
+		next := w.schedule.Next(time.Now())
+		if next.IsZero() {
+			w.logger.Warn("Review digest schedule never fires, worker stopped")
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.logger.Info("Review digest worker stopped")
+			return
+		case <-timer.C:
+			forEachOrg(ctx, w.orgs, w.logger, func(ctx context.Context) {
+				w.send(ctx, since, next)
+			})
+			since = next
+		}
+	}
+}
+
+func (w *ReviewDigestWorker) send(ctx context.Context, since, now time.Time) {
+	sent, err := w.service.SendDigests(ctx, since, now, w.schedule.Next(now))
+	if err != nil && ctx.Err() == nil {
+		w.logger.Error("Failed to send review digests", zap.Error(err))
+	}
+	if sent > 0 {
+		w.logger.Info("Sent review digests", zap.Int("count", sent))
+	}
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultEscalationCheckInterval is used when no escalation check interval is configured
+const DefaultEscalationCheckInterval = 15 * time.Minute
+
+type escalationService interface {
+	EscalateOverdueReviews(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// ReviewEscalationsWorker periodically escalates reviews that breached their SLA
+type ReviewEscalationsWorker struct {
+	service       escalationService
+	orgs          orgLister
+	checkInterval time.Duration
+	batchSize     int
+	logger        *zap.Logger
+}
+
+// NewReviewEscalationsWorker creates a new review escalations worker
+func NewReviewEscalationsWorker(service escalationService, orgs orgLister, checkInterval time.Duration, logger *zap.Logger) *ReviewEscalationsWorker {
+	if checkInterval <= 0 {
+		checkInterval = DefaultEscalationCheckInterval
+	}
+
+	return &ReviewEscalationsWorker{
+		service:       service,
+		orgs:          orgs,
+		checkInterval: checkInterval,
+		batchSize:     DefaultBatchSize,
+		logger:        logger,
+	}
+}
+
+// Run escalates overdue reviews until ctx is canceled
+func (w *ReviewEscalationsWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("Review escalations worker started", zap.Duration("check_interval", w.checkInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Review escalations worker stopped")
+			return
+		case <-ticker.C:
+			forEachOrg(ctx, w.orgs, w.logger, w.escalate)
+		}
+	}
+}
+
+func (w *ReviewEscalationsWorker) escalate(ctx context.Context) {
+	for {
+		found, err := w.service.EscalateOverdueReviews(ctx, time.Now(), w.batchSize)
+		if err != nil && ctx.Err() == nil {
+			w.logger.Error("Failed to escalate overdue reviews", zap.Error(err))
+		}
+		if found > 0 {
+			w.logger.Info("Escalated overdue reviews", zap.Int("count", found))
+		}
+		if found < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultPollInterval is used when no poll interval is configured
+const DefaultPollInterval = 30 * time.Second
+
+// DefaultBatchSize is used when no batch size is configured
+const DefaultBatchSize = 50
+
+type scheduleService interface {
+	ApplyDueChanges(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// ScheduledChangesWorker periodically applies scheduled activity changes that became due
+type ScheduledChangesWorker struct {
+	service      scheduleService
+	orgs         orgLister
+	pollInterval time.Duration
+	batchSize    int
+	logger       *zap.Logger
+}
+
+// NewScheduledChangesWorker creates a new scheduled changes worker
+func NewScheduledChangesWorker(
+	service scheduleService,
+	orgs orgLister,
+	pollInterval time.Duration,
+	batchSize int,
+	logger *zap.Logger,
+) *ScheduledChangesWorker {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &ScheduledChangesWorker{
+		service:      service,
+		orgs:         orgs,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		logger:       logger,
+	}
+}
+
+// Run polls for due changes until ctx is canceled
+func (w *ScheduledChangesWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("Scheduled changes worker started", zap.Duration("poll_interval", w.pollInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Scheduled changes worker stopped")
+			return
+		case <-ticker.C:
+			forEachOrg(ctx, w.orgs, w.logger, w.tick)
+		}
+	}
+}
+
+func (w *ScheduledChangesWorker) tick(ctx context.Context) {
+	// Drain the backlog in batches so a burst of due changes is not spread over several intervals
+	for {
+		applied, err := w.service.ApplyDueChanges(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.Error("Failed to apply scheduled changes", zap.Error(err))
+			}
+			return
+		}
+		if applied > 0 {
+			w.logger.Info("Applied scheduled changes", zap.Int("count", applied))
+		}
+		if applied < w.batchSize {
+			return
+		}
+	}
+}
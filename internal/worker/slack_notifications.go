@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// DefaultStaleCheckInterval is used when no stale review check interval is configured
+const DefaultStaleCheckInterval = time.Hour
+
+type slackService interface {
+	Pending() <-chan domain.Event
+	Send(ctx context.Context, event domain.Event) error
+	NotifyStaleReviews(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// SlackNotificationsWorker sends queued Slack messages as events arrive and
+// periodically reminds reviewers of stale reviews
+type SlackNotificationsWorker struct {
+	service       slackService
+	orgs          orgLister
+	checkInterval time.Duration
+	batchSize     int
+	logger        *zap.Logger
+}
+
+// NewSlackNotificationsWorker creates a new Slack notifications worker
+func NewSlackNotificationsWorker(service slackService, orgs orgLister, checkInterval time.Duration, logger *zap.Logger) *SlackNotificationsWorker {
+	if checkInterval <= 0 {
+		checkInterval = DefaultStaleCheckInterval
+	}
+
+	return &SlackNotificationsWorker{
+		service:       service,
+		orgs:          orgs,
+		checkInterval: checkInterval,
+		batchSize:     DefaultBatchSize,
+		logger:        logger,
+	}
+}
+
+// Run sends notifications until ctx is canceled
+func (w *SlackNotificationsWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("Slack notifications worker started", zap.Duration("stale_check_interval", w.checkInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Slack notifications worker stopped")
+			return
+		case event := <-w.service.Pending():
+			if err := w.service.Send(domain.WithOrg(ctx, event.OrgID), event); err != nil && ctx.Err() == nil {
+				w.logger.Error("Failed to send Slack notification",
+					zap.String("event", string(event.Type)),
+					zap.String("pull_request_id", event.PullRequestID),
+					zap.Error(err))
+			}
+		case <-ticker.C:
+			forEachOrg(ctx, w.orgs, w.logger, w.checkStale)
+		}
+	}
+}
+
+func (w *SlackNotificationsWorker) checkStale(ctx context.Context) {
+	for {
+		found, err := w.service.NotifyStaleReviews(ctx, time.Now(), w.batchSize)
+		if err != nil && ctx.Err() == nil {
+			w.logger.Error("Failed to send stale review reminders", zap.Error(err))
+		}
+		if found > 0 {
+			w.logger.Info("Reminded reviewers of stale reviews", zap.Int("count", found))
+		}
+		if found < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+
+	"pr-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type teamChannelService interface {
+	Pending() <-chan domain.Event
+	Send(ctx context.Context, event domain.Event) error
+}
+
+// TeamChannelNotificationsWorker posts queued PR activity to team chat channels as it arrives
+type TeamChannelNotificationsWorker struct {
+	service teamChannelService
+	logger  *zap.Logger
+}
+
+// NewTeamChannelNotificationsWorker creates a new team channel notifications worker
+func NewTeamChannelNotificationsWorker(service teamChannelService, logger *zap.Logger) *TeamChannelNotificationsWorker {
+	return &TeamChannelNotificationsWorker{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Run posts notifications until ctx is canceled
+func (w *TeamChannelNotificationsWorker) Run(ctx context.Context) {
+	w.logger.Info("Team channel notifications worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Team channel notifications worker stopped")
+			return
+		case event := <-w.service.Pending():
+			if err := w.service.Send(domain.WithOrg(ctx, event.OrgID), event); err != nil && ctx.Err() == nil {
+				w.logger.Error("Failed to post team channel notification",
+					zap.String("event", string(event.Type)),
+					zap.String("pull_request_id", event.PullRequestID),
+					zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultDeliveryPollInterval is used when no webhook delivery poll interval is configured
+const DefaultDeliveryPollInterval = 5 * time.Second
+
+type webhookService interface {
+	DeliverDue(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// WebhookDeliveriesWorker periodically sends queued outbound webhook deliveries that are due
+type WebhookDeliveriesWorker struct {
+	service      webhookService
+	pollInterval time.Duration
+	batchSize    int
+	logger       *zap.Logger
+}
+
+// NewWebhookDeliveriesWorker creates a new webhook deliveries worker
+func NewWebhookDeliveriesWorker(
+	service webhookService,
+	pollInterval time.Duration,
+	batchSize int,
+	logger *zap.Logger,
+) *WebhookDeliveriesWorker {
+	if pollInterval <= 0 {
+		pollInterval = DefaultDeliveryPollInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &WebhookDeliveriesWorker{
+		service:      service,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		logger:       logger,
+	}
+}
+
+// Run polls for due deliveries until ctx is canceled
+func (w *WebhookDeliveriesWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("Webhook deliveries worker started", zap.Duration("poll_interval", w.pollInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Webhook deliveries worker stopped")
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+func (w *WebhookDeliveriesWorker) tick(ctx context.Context) {
+	for {
+		attempted, err := w.service.DeliverDue(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.Error("Failed to deliver webhooks", zap.Error(err))
+			}
+			return
+		}
+		if attempted > 0 {
+			w.logger.Info("Attempted webhook deliveries", zap.Int("count", attempted))
+		}
+		if attempted < w.batchSize {
+			return
+		}
+	}
+}
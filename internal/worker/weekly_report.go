@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"pr-service/internal/cron"
+
+	"go.uber.org/zap"
+)
+
+type reportService interface {
+	DeliverWeeklyReport(ctx context.Context, now time.Time) error
+}
+
+// WeeklyReportWorker delivers the weekly review summary on a cron schedule
+type WeeklyReportWorker struct {
+	service  reportService
+	schedule *cron.Schedule
+	logger   *zap.Logger
+}
+
+// NewWeeklyReportWorker creates a new weekly report worker
+func NewWeeklyReportWorker(service reportService, schedule *cron.Schedule, logger *zap.Logger) *WeeklyReportWorker {
+	return &WeeklyReportWorker{
+		service:  service,
+		schedule: schedule,
+		logger:   logger,
+	}
+}
+
+// Run delivers the report at every scheduled time until ctx is canceled.
+// Runs missed while the service was down are not caught up.
+func (w *WeeklyReportWorker) Run(ctx context.Context) {
+	w.logger.Info("Weekly report worker started")
+
+	for {
+		next := w.schedule.Next(time.Now())
+		if next.IsZero() {
+			w.logger.Warn("Weekly report schedule never fires, worker stopped")
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.logger.Info("Weekly report worker stopped")
+			return
+		case <-timer.C:
+			w.deliver(ctx, next)
+		}
+	}
+}
+
+func (w *WeeklyReportWorker) deliver(ctx context.Context, now time.Time) {
+	if err := w.service.DeliverWeeklyReport(ctx, now); err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("Failed to deliver weekly report", zap.Error(err))
+		}
+		return
+	}
+	w.logger.Info("Delivered weekly report")
+}
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Users detached by team deletion cannot get their team back, and deleting
+-- them would take their PRs and reviews along, so they have to be moved to a
+-- team by hand before rolling back
+DO $$
+DECLARE
+    detached BIGINT;
+BEGIN
+    SELECT count(*) INTO detached FROM users WHERE team_name IS NULL;
+    IF detached > 0 THEN
+        RAISE EXCEPTION 'cannot restore NOT NULL on users.team_name: % users have no team', detached
+            USING HINT = 'Move them to a team, e.g. with UPDATE users SET team_name = ..., before rolling back.';
+    END IF;
+END
+$$;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
+-- +goose StatementEnd
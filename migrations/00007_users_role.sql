@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('lead', 'member'));
+
+CREATE INDEX IF NOT EXISTS idx_users_team_role ON users(team_name, role);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_team_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS parent_team_name VARCHAR(100)
+    REFERENCES teams(team_name) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE teams
+    ADD CONSTRAINT teams_parent_not_self CHECK (parent_team_name <> team_name);
+
+CREATE INDEX IF NOT EXISTS idx_teams_parent_team_name ON teams(parent_team_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_teams_parent_team_name;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_parent_not_self;
+ALTER TABLE teams DROP COLUMN IF EXISTS parent_team_name;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS scheduled_status_changes (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(30) NOT NULL CHECK (kind IN ('SET_IS_ACTIVE', 'BULK_DEACTIVATE')),
+    team_name VARCHAR(100),
+    user_ids TEXT[] NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT false,
+    effective_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'PROCESSING', 'APPLIED', 'FAILED')),
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    applied_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_status_changes_due
+    ON scheduled_status_changes(effective_at)
+    WHERE status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduled_status_changes;
+-- +goose StatementEnd
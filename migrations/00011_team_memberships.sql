@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS team_members (
+    team_name VARCHAR(100) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    user_id VARCHAR(100) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON team_members(user_id);
+
+INSERT INTO team_members (team_name, user_id, joined_at)
+SELECT team_name, user_id, created_at
+FROM users
+WHERE team_name IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_team_name;
+DROP INDEX IF EXISTS idx_users_team_active;
+DROP INDEX IF EXISTS idx_users_team_role;
+ALTER TABLE users DROP COLUMN IF EXISTS team_name;
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS team_name VARCHAR(100)
+    REFERENCES teams(team_name) ON DELETE SET NULL ON UPDATE CASCADE;
+
+UPDATE pull_requests pr
+SET team_name = tm.team_name
+FROM team_members tm
+WHERE tm.user_id = pr.author_id;
+
+CREATE INDEX IF NOT EXISTS idx_pr_team_name ON pull_requests(team_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pr_team_name;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS team_name;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS team_name VARCHAR(100)
+    REFERENCES teams(team_name) ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- A user can only keep one team; the earliest membership wins
+UPDATE users u
+SET team_name = (
+    SELECT tm.team_name
+    FROM team_members tm
+    WHERE tm.user_id = u.user_id
+    ORDER BY tm.joined_at, tm.team_name
+    LIMIT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users(team_name);
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active);
+CREATE INDEX IF NOT EXISTS idx_users_team_role ON users(team_name, role);
+
+DROP TABLE IF EXISTS team_members;
+-- +goose StatementEnd
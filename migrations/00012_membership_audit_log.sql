@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- No foreign keys: history must outlive renamed or deleted teams and users
+CREATE TABLE IF NOT EXISTS membership_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    team_name VARCHAR(100) NOT NULL,
+    user_id VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL
+        CHECK (action IN ('ADDED', 'REMOVED', 'ACTIVATED', 'DEACTIVATED', 'MOVED')),
+    from_team_name VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_membership_audit_log_team
+    ON membership_audit_log(team_name, id DESC);
+CREATE INDEX IF NOT EXISTS idx_membership_audit_log_from_team
+    ON membership_audit_log(from_team_name, id DESC)
+    WHERE from_team_name IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS membership_audit_log;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS first_action_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_first_action_at
+    ON pr_reviewers(first_action_at)
+    WHERE first_action_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pr_reviewers_first_action_at;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS first_action_at;
+-- +goose StatementEnd
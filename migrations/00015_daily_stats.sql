@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reviewer_reassignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(100) NOT NULL,
+    old_user_id VARCHAR(100) NOT NULL,
+    new_user_id VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_reassignments_created_at
+    ON reviewer_reassignments(created_at);
+
+CREATE TABLE IF NOT EXISTS daily_team_stats (
+    day DATE NOT NULL,
+    team_name VARCHAR(100) NOT NULL,
+    assignments INTEGER NOT NULL DEFAULT 0,
+    merges INTEGER NOT NULL DEFAULT 0,
+    reassignments INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, team_name)
+);
+
+CREATE TABLE IF NOT EXISTS stats_rollups (
+    day DATE PRIMARY KEY,
+    rolled_up_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stats_rollups;
+DROP TABLE IF EXISTS daily_team_stats;
+DROP TABLE IF EXISTS reviewer_reassignments;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_repository
+    ON pull_requests(repository)
+    WHERE repository IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pull_requests_repository;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS stale_notified_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS stale_notified_at;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS ticket_key VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_ticket_key
+    ON pull_requests(ticket_key)
+    WHERE ticket_key IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pull_requests_ticket_key;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS ticket_key;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS notification_channel_type VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS notification_channel_url TEXT;
+
+ALTER TABLE teams
+    ADD CONSTRAINT teams_notification_channel_type_check
+    CHECK (notification_channel_type IN ('slack', 'msteams', 'mattermost'));
+
+ALTER TABLE teams
+    ADD CONSTRAINT teams_notification_channel_complete
+    CHECK ((notification_channel_type IS NULL) = (notification_channel_url IS NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_notification_channel_complete;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_notification_channel_type_check;
+ALTER TABLE teams DROP COLUMN IF EXISTS notification_channel_url;
+ALTER TABLE teams DROP COLUMN IF EXISTS notification_channel_type;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The recording transaction orders the event log for replay: a row only becomes
+-- visible to readers once every transaction older than its own has finished
+ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS txid xid8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_replay ON event_outbox(txid, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_event_outbox_replay;
+ALTER TABLE event_outbox DROP COLUMN IF EXISTS txid;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Bearer tokens restricted to one team, for the team's bots. Only a hash of
+-- each token is stored; the token itself is shown once when it is issued.
+CREATE TABLE IF NOT EXISTS team_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    team_name VARCHAR(100) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_tokens_team ON team_tokens(team_name, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Counts changes to a PR row; updates only apply to the version they read,
+-- so a stale write is refused instead of silently overwriting a newer one.
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleted teams keep their row, so their PRs keep the team for stats and the
+-- team can be restored
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_teams_deleted_at ON teams(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM teams WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_teams_deleted_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
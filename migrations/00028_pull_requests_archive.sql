@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Merged PRs past the retention age are moved here with their reviewer
+-- assignments and reassignments, keeping the tables stats read from small
+CREATE TABLE IF NOT EXISTS pull_requests_archive (
+    LIKE pull_requests INCLUDING DEFAULTS,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (pull_request_id)
+);
+
+CREATE TABLE IF NOT EXISTS pr_reviewers_archive (
+    LIKE pr_reviewers INCLUDING DEFAULTS,
+    PRIMARY KEY (pull_request_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS reviewer_reassignments_archive (
+    LIKE reviewer_reassignments INCLUDING DEFAULTS,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_merged_at
+    ON pull_requests(merged_at)
+    WHERE status = 'MERGED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pr_merged_at;
+DROP TABLE IF EXISTS reviewer_reassignments_archive;
+DROP TABLE IF EXISTS pr_reviewers_archive;
+DROP TABLE IF EXISTS pull_requests_archive;
+-- +goose StatementEnd
@@ -0,0 +1,160 @@
+-- +goose Up
+-- +goose StatementBegin
+-- pull_requests is partitioned by month of created_at, so queries over a
+-- period only scan its months. A partitioned table only enforces uniqueness
+-- together with the partition key, so pull_request_ids keeps PR IDs unique
+-- and is what PR rows and reviewer assignments reference. Deleting an ID
+-- deletes the PR and its reviewers.
+CREATE TABLE IF NOT EXISTS pull_request_ids (
+    pull_request_id VARCHAR(100) PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO pull_request_ids (pull_request_id, created_at)
+SELECT pull_request_id, created_at
+FROM pull_requests;
+
+ALTER TABLE pr_reviewers DROP CONSTRAINT IF EXISTS pr_reviewers_pull_request_id_fkey;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_pull_request_id_fkey
+    FOREIGN KEY (pull_request_id) REFERENCES pull_request_ids(pull_request_id) ON DELETE CASCADE;
+
+ALTER TABLE pull_requests RENAME TO pull_requests_unpartitioned;
+ALTER INDEX pull_requests_pkey RENAME TO pull_requests_unpartitioned_pkey;
+
+-- Columns keep their order, which the archive tables copy
+CREATE TABLE pull_requests (
+    pull_request_id VARCHAR(100) NOT NULL REFERENCES pull_request_ids(pull_request_id) ON DELETE CASCADE,
+    pull_request_name VARCHAR(500) NOT NULL,
+    author_id VARCHAR(100) NOT NULL REFERENCES users(user_id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    merged_at TIMESTAMP,
+    team_name VARCHAR(100) REFERENCES teams(team_name) ON DELETE SET NULL ON UPDATE CASCADE,
+    repository VARCHAR(255),
+    ticket_key VARCHAR(64),
+    version BIGINT NOT NULL DEFAULT 1,
+    PRIMARY KEY (pull_request_id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- Rows of months without a partition, e.g. imported history
+CREATE TABLE pull_requests_default PARTITION OF pull_requests DEFAULT;
+
+-- create_pull_request_partitions creates the missing monthly partitions from
+-- the month of from_month through the month of through and returns how many
+-- it created. Months that already have rows in the default partition are
+-- skipped, since attaching them would fail.
+CREATE OR REPLACE FUNCTION create_pull_request_partitions(from_month TIMESTAMP, through TIMESTAMP)
+RETURNS INTEGER AS $$
+DECLARE
+    month_start TIMESTAMP := date_trunc('month', from_month);
+    partition_name TEXT;
+    created INTEGER := 0;
+BEGIN
+    WHILE month_start <= through LOOP
+        partition_name := 'pull_requests_' || to_char(month_start, 'YYYY_MM');
+        IF to_regclass(partition_name) IS NULL AND NOT EXISTS (
+            SELECT 1 FROM pull_requests_default
+            WHERE created_at >= month_start AND created_at < month_start + INTERVAL '1 month'
+        ) THEN
+            EXECUTE format('CREATE TABLE %I PARTITION OF pull_requests FOR VALUES FROM (%L) TO (%L)',
+                partition_name, month_start, month_start + INTERVAL '1 month');
+            created := created + 1;
+        END IF;
+        month_start := month_start + INTERVAL '1 month';
+    END LOOP;
+    RETURN created;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT create_pull_request_partitions(
+    COALESCE((SELECT MIN(created_at) FROM pull_requests_unpartitioned), NOW()::timestamp),
+    NOW()::timestamp + INTERVAL '3 months'
+);
+
+INSERT INTO pull_requests
+SELECT * FROM pull_requests_unpartitioned;
+
+DROP TABLE pull_requests_unpartitioned;
+
+CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(author_id);
+CREATE INDEX IF NOT EXISTS idx_pr_created_at ON pull_requests(created_at);
+CREATE INDEX IF NOT EXISTS idx_pr_team_name ON pull_requests(team_name);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_repository
+    ON pull_requests(repository)
+    WHERE repository IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_ticket_key
+    ON pull_requests(ticket_key)
+    WHERE ticket_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pr_merged_at
+    ON pull_requests(merged_at)
+    WHERE status = 'MERGED';
+
+-- Open PRs are a small share of the table: the open PRs of a team in age
+-- order, and the open PRs reviewers are assigned to, are read from the
+-- index alone
+CREATE INDEX IF NOT EXISTS idx_pr_open_team
+    ON pull_requests(team_name, created_at)
+    INCLUDE (pull_request_id)
+    WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS idx_pr_open_id
+    ON pull_requests(pull_request_id)
+    WHERE status = 'OPEN';
+
+-- Reviewer lookups read the PRs and assignment times of a user from the
+-- index alone; pending reviews come in assignment order
+DROP INDEX IF EXISTS idx_pr_reviewers_user_id;
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user
+    ON pr_reviewers(user_id)
+    INCLUDE (pull_request_id, assigned_at);
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user_pending
+    ON pr_reviewers(user_id, assigned_at)
+    INCLUDE (pull_request_id)
+    WHERE first_action_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pr_reviewers_user_pending;
+DROP INDEX IF EXISTS idx_pr_reviewers_user;
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user_id ON pr_reviewers(user_id);
+
+CREATE TABLE pull_requests_unpartitioned (
+    pull_request_id VARCHAR(100) PRIMARY KEY,
+    pull_request_name VARCHAR(500) NOT NULL,
+    author_id VARCHAR(100) NOT NULL REFERENCES users(user_id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    merged_at TIMESTAMP,
+    team_name VARCHAR(100) REFERENCES teams(team_name) ON DELETE SET NULL ON UPDATE CASCADE,
+    repository VARCHAR(255),
+    ticket_key VARCHAR(64),
+    version BIGINT NOT NULL DEFAULT 1
+);
+
+INSERT INTO pull_requests_unpartitioned
+SELECT * FROM pull_requests;
+
+ALTER TABLE pr_reviewers DROP CONSTRAINT IF EXISTS pr_reviewers_pull_request_id_fkey;
+DROP TABLE pull_requests;
+DROP FUNCTION IF EXISTS create_pull_request_partitions(TIMESTAMP, TIMESTAMP);
+DROP TABLE IF EXISTS pull_request_ids;
+
+ALTER TABLE pull_requests_unpartitioned RENAME TO pull_requests;
+ALTER INDEX pull_requests_unpartitioned_pkey RENAME TO pull_requests_pkey;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_pull_request_id_fkey
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
+CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(author_id);
+CREATE INDEX IF NOT EXISTS idx_pr_created_at ON pull_requests(created_at);
+CREATE INDEX IF NOT EXISTS idx_pr_team_name ON pull_requests(team_name);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_repository
+    ON pull_requests(repository)
+    WHERE repository IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_ticket_key
+    ON pull_requests(ticket_key)
+    WHERE ticket_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pr_merged_at
+    ON pull_requests(merged_at)
+    WHERE status = 'MERGED';
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- reviewer_workload keeps the number of open PRs every user reviews, updated
+-- in the same statements that assign, remove and merge, so the workload
+-- stats need not count all of pr_reviewers. Stats over a from/to window count
+-- assignments made within it, which a running total cannot tell, and stay on
+-- pr_reviewers.
+CREATE TABLE IF NOT EXISTS reviewer_workload (
+    user_id VARCHAR(100) PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    open_reviews INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO reviewer_workload (user_id, open_reviews)
+SELECT rev.user_id, COUNT(*)
+FROM pr_reviewers rev
+INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id AND pr.status = 'OPEN'
+GROUP BY rev.user_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reviewer_workload;
+-- +goose StatementEnd
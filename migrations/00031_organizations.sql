@@ -0,0 +1,200 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every team, user and PR, and everything recorded about them, belongs to an
+-- organization. Each session names its organization in the pr_service.org_id
+-- setting, and row level security hides the rows of other organizations from
+-- it; "*" shows the rows of every organization. Sessions that set nothing
+-- work in the default organization, which owns all rows stored before.
+CREATE OR REPLACE FUNCTION current_org_id() RETURNS VARCHAR AS $$
+    SELECT COALESCE(NULLIF(current_setting('pr_service.org_id', true), ''), 'default')
+$$ LANGUAGE sql STABLE;
+
+-- organizations lists the organizations with data, so background work can
+-- be done for each
+CREATE TABLE IF NOT EXISTS organizations (
+    org_id VARCHAR(100) PRIMARY KEY CHECK (org_id <> '*'),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO organizations (org_id) VALUES ('default');
+
+DO $$
+DECLARE
+    tenant_table TEXT;
+BEGIN
+    FOREACH tenant_table IN ARRAY ARRAY[
+        'teams', 'users', 'team_members', 'pull_request_ids', 'pull_requests',
+        'pr_reviewers', 'reviewer_reassignments', 'reviewer_workload',
+        'daily_team_stats', 'stats_rollups', 'scheduled_status_changes',
+        'membership_audit_log', 'webhook_subscriptions', 'webhook_deliveries',
+        'event_outbox', 'team_tokens', 'audit_log', 'pull_requests_archive',
+        'pr_reviewers_archive', 'reviewer_reassignments_archive'
+    ] LOOP
+        -- Existing rows belong to the default organization, new ones to the
+        -- organization of the session inserting them
+        EXECUTE format('ALTER TABLE %I ADD COLUMN org_id VARCHAR(100) NOT NULL DEFAULT %L',
+            tenant_table, 'default');
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN org_id SET DEFAULT current_org_id()', tenant_table);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tenant_table);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', tenant_table);
+        EXECUTE format($policy$
+            CREATE POLICY org_isolation ON %I
+            USING (org_id = current_org_id() OR current_org_id() = '*')
+            WITH CHECK (org_id <> '*' AND (org_id = current_org_id() OR current_org_id() = '*'))
+        $policy$, tenant_table);
+    END LOOP;
+END $$;
+
+-- Names are unique within an organization, so every key, and every foreign
+-- key referencing one, includes org_id
+DO $$
+DECLARE
+    fk RECORD;
+BEGIN
+    FOR fk IN
+        SELECT conrelid::regclass AS tbl, conname
+        FROM pg_constraint
+        WHERE contype = 'f'
+          AND conparentid = 0
+          AND confrelid IN ('teams'::regclass, 'users'::regclass, 'pull_request_ids'::regclass)
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', fk.tbl, fk.conname);
+    END LOOP;
+END $$;
+
+ALTER TABLE teams DROP CONSTRAINT teams_pkey;
+ALTER TABLE teams ADD PRIMARY KEY (team_name, org_id);
+ALTER TABLE users DROP CONSTRAINT users_pkey;
+ALTER TABLE users ADD PRIMARY KEY (user_id, org_id);
+ALTER TABLE team_members DROP CONSTRAINT team_members_pkey;
+ALTER TABLE team_members ADD PRIMARY KEY (team_name, user_id, org_id);
+ALTER TABLE pull_request_ids DROP CONSTRAINT pull_request_ids_pkey;
+ALTER TABLE pull_request_ids ADD PRIMARY KEY (pull_request_id, org_id);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey;
+ALTER TABLE pull_requests ADD PRIMARY KEY (pull_request_id, created_at, org_id);
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_pkey;
+ALTER TABLE pr_reviewers ADD PRIMARY KEY (pull_request_id, user_id, org_id);
+ALTER TABLE reviewer_workload DROP CONSTRAINT reviewer_workload_pkey;
+ALTER TABLE reviewer_workload ADD PRIMARY KEY (user_id, org_id);
+ALTER TABLE daily_team_stats DROP CONSTRAINT daily_team_stats_pkey;
+ALTER TABLE daily_team_stats ADD PRIMARY KEY (day, team_name, org_id);
+ALTER TABLE stats_rollups DROP CONSTRAINT stats_rollups_pkey;
+ALTER TABLE stats_rollups ADD PRIMARY KEY (day, org_id);
+ALTER TABLE pull_requests_archive DROP CONSTRAINT pull_requests_archive_pkey;
+ALTER TABLE pull_requests_archive ADD PRIMARY KEY (pull_request_id, org_id);
+ALTER TABLE pr_reviewers_archive DROP CONSTRAINT pr_reviewers_archive_pkey;
+ALTER TABLE pr_reviewers_archive ADD PRIMARY KEY (pull_request_id, user_id, org_id);
+
+ALTER TABLE teams ADD CONSTRAINT teams_parent_team_name_fkey
+    FOREIGN KEY (parent_team_name, org_id) REFERENCES teams(team_name, org_id)
+    ON DELETE SET NULL (parent_team_name) ON UPDATE CASCADE;
+ALTER TABLE team_members ADD CONSTRAINT team_members_team_name_fkey
+    FOREIGN KEY (team_name, org_id) REFERENCES teams(team_name, org_id)
+    ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE team_members ADD CONSTRAINT team_members_user_id_fkey
+    FOREIGN KEY (user_id, org_id) REFERENCES users(user_id, org_id) ON DELETE CASCADE;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_pull_request_id_fkey
+    FOREIGN KEY (pull_request_id, org_id) REFERENCES pull_request_ids(pull_request_id, org_id)
+    ON DELETE CASCADE;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_author_id_fkey
+    FOREIGN KEY (author_id, org_id) REFERENCES users(user_id, org_id);
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_team_name_fkey
+    FOREIGN KEY (team_name, org_id) REFERENCES teams(team_name, org_id)
+    ON DELETE SET NULL (team_name) ON UPDATE CASCADE;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_pull_request_id_fkey
+    FOREIGN KEY (pull_request_id, org_id) REFERENCES pull_request_ids(pull_request_id, org_id)
+    ON DELETE CASCADE;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_user_id_fkey
+    FOREIGN KEY (user_id, org_id) REFERENCES users(user_id, org_id);
+ALTER TABLE reviewer_workload ADD CONSTRAINT reviewer_workload_user_id_fkey
+    FOREIGN KEY (user_id, org_id) REFERENCES users(user_id, org_id) ON DELETE CASCADE;
+ALTER TABLE team_tokens ADD CONSTRAINT team_tokens_team_name_fkey
+    FOREIGN KEY (team_name, org_id) REFERENCES teams(team_name, org_id)
+    ON DELETE CASCADE ON UPDATE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Only possible while the default organization is the only one, since the
+-- keys without org_id would no longer be unique
+DO $$
+DECLARE
+    fk RECORD;
+BEGIN
+    FOR fk IN
+        SELECT conrelid::regclass AS tbl, conname
+        FROM pg_constraint
+        WHERE contype = 'f'
+          AND conparentid = 0
+          AND confrelid IN ('teams'::regclass, 'users'::regclass, 'pull_request_ids'::regclass)
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', fk.tbl, fk.conname);
+    END LOOP;
+END $$;
+
+ALTER TABLE teams DROP CONSTRAINT teams_pkey;
+ALTER TABLE teams ADD PRIMARY KEY (team_name);
+ALTER TABLE users DROP CONSTRAINT users_pkey;
+ALTER TABLE users ADD PRIMARY KEY (user_id);
+ALTER TABLE team_members DROP CONSTRAINT team_members_pkey;
+ALTER TABLE team_members ADD PRIMARY KEY (team_name, user_id);
+ALTER TABLE pull_request_ids DROP CONSTRAINT pull_request_ids_pkey;
+ALTER TABLE pull_request_ids ADD PRIMARY KEY (pull_request_id);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey;
+ALTER TABLE pull_requests ADD PRIMARY KEY (pull_request_id, created_at);
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_pkey;
+ALTER TABLE pr_reviewers ADD PRIMARY KEY (pull_request_id, user_id);
+ALTER TABLE reviewer_workload DROP CONSTRAINT reviewer_workload_pkey;
+ALTER TABLE reviewer_workload ADD PRIMARY KEY (user_id);
+ALTER TABLE daily_team_stats DROP CONSTRAINT daily_team_stats_pkey;
+ALTER TABLE daily_team_stats ADD PRIMARY KEY (day, team_name);
+ALTER TABLE stats_rollups DROP CONSTRAINT stats_rollups_pkey;
+ALTER TABLE stats_rollups ADD PRIMARY KEY (day);
+ALTER TABLE pull_requests_archive DROP CONSTRAINT pull_requests_archive_pkey;
+ALTER TABLE pull_requests_archive ADD PRIMARY KEY (pull_request_id);
+ALTER TABLE pr_reviewers_archive DROP CONSTRAINT pr_reviewers_archive_pkey;
+ALTER TABLE pr_reviewers_archive ADD PRIMARY KEY (pull_request_id, user_id);
+
+ALTER TABLE teams ADD CONSTRAINT teams_parent_team_name_fkey
+    FOREIGN KEY (parent_team_name) REFERENCES teams(team_name) ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE team_members ADD CONSTRAINT team_members_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE team_members ADD CONSTRAINT team_members_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_pull_request_id_fkey
+    FOREIGN KEY (pull_request_id) REFERENCES pull_request_ids(pull_request_id) ON DELETE CASCADE;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_author_id_fkey
+    FOREIGN KEY (author_id) REFERENCES users(user_id);
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_pull_request_id_fkey
+    FOREIGN KEY (pull_request_id) REFERENCES pull_request_ids(pull_request_id) ON DELETE CASCADE;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(user_id);
+ALTER TABLE reviewer_workload ADD CONSTRAINT reviewer_workload_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE team_tokens ADD CONSTRAINT team_tokens_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+DO $$
+DECLARE
+    tenant_table TEXT;
+BEGIN
+    FOREACH tenant_table IN ARRAY ARRAY[
+        'teams', 'users', 'team_members', 'pull_request_ids', 'pull_requests',
+        'pr_reviewers', 'reviewer_reassignments', 'reviewer_workload',
+        'daily_team_stats', 'stats_rollups', 'scheduled_status_changes',
+        'membership_audit_log', 'webhook_subscriptions', 'webhook_deliveries',
+        'event_outbox', 'team_tokens', 'audit_log', 'pull_requests_archive',
+        'pr_reviewers_archive', 'reviewer_reassignments_archive'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS org_isolation ON %I', tenant_table);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', tenant_table);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', tenant_table);
+        EXECUTE format('ALTER TABLE %I DROP COLUMN org_id', tenant_table);
+    END LOOP;
+END $$;
+
+DROP TABLE IF EXISTS organizations;
+DROP FUNCTION IF EXISTS current_org_id();
+-- +goose StatementEnd
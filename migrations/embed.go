@@ -0,0 +1,10 @@
+// Package migrations embeds the goose SQL migrations of the database schema
+// into the binary
+package migrations
+
+import "embed"
+
+// FS holds the migration files, named <version>_<description>.sql
+//
+//go:embed *.sql
+var FS embed.FS